@@ -4,8 +4,10 @@ import (
 	"database/sql"
 	"log"
 	"os"
+	"time"
 
 	"learn-go-capstone/internal/api"
+	"learn-go-capstone/internal/attachments"
 	"learn-go-capstone/internal/auth"
 	"learn-go-capstone/internal/config"
 	"learn-go-capstone/internal/database"
@@ -70,29 +72,125 @@ func main() {
 		log.Println("⚠️  Database disabled, using in-memory storage")
 	}
 
+	// Cascade rules for subtasks of deleted or completed tasks
+	cascadeRules := task.CascadeRules{
+		OnDelete:   task.DeleteRule(cfg.Tasks.SubtaskDeleteRule),
+		OnComplete: task.CompleteRule(cfg.Tasks.SubtaskCompleteRule),
+	}
+	if err := cascadeRules.Validate(); err != nil {
+		log.Printf("⚠️  %v, using default subtask cascade rules", err)
+	}
+	hierarchyManager := task.NewHierarchyManager(repository, cascadeRules)
+
+	// Task lifecycle events; Close waits for queued notifications to be sent
+	eventBus := task.NewEventBus()
+	defer eventBus.Close()
+
 	// Create task manager
-	var taskManager task.TaskManagerInterface
+	var taskManager task.TaskManagerV2
 	if cfg.IsDatabaseEnabled() && repository != nil {
-		taskManager = task.NewHybridTaskManager(repository, task.DatabaseStorage)
+		hybridManager := task.NewHybridTaskManager(repository, task.DatabaseStorage)
+		hybridManager.SetHierarchyManager(hierarchyManager)
+		hybridManager.SetEventBus(eventBus)
+		taskManager = hybridManager
 		log.Println("✅ Hybrid task manager initialized (Database + Memory)")
 	} else {
-		taskManager = task.NewTaskManager()
+		memoryManager := task.NewTaskManager()
+		memoryManager.SetEventBus(eventBus)
+		taskManager = memoryManager
 		log.Println("✅ In-memory task manager initialized")
 	}
 
 	// Create managers
 	userManager := task.NewUserManager(repository)
+	userManager.SetHierarchyManager(hierarchyManager)
+	userManager.SetEventBus(eventBus)
 	categoryManager := task.NewCategoryManager(repository)
+	categoryManager.SetEventBus(eventBus)
 	dependencyManager := task.NewDependencyManager(repository)
+	dependencyManager.SetEventBus(eventBus)
+	commentManager := task.NewCommentManager(repository)
+	commentManager.SetEventBus(eventBus)
 	searchManager := task.NewSearchManager(repository)
 	exportManager := task.NewExportManager(repository)
 
+	// Attachment contents are stored on disk under their SHA-256 digest
+	attachmentStore, err := attachments.NewStore(cfg.Attachments.Dir)
+	if err != nil {
+		log.Fatalf("❌ Failed to open attachment storage: %v", err)
+	}
+	attachmentManager := task.NewAttachmentManager(repository, attachmentStore, attachments.Limits{
+		MaxSize:      cfg.Attachments.MaxSizeBytes(),
+		AllowedTypes: cfg.Attachments.AllowedTypes,
+	})
+	exportManager.SetAttachmentStore(attachmentStore)
+	projectManager := task.NewProjectManager(repository)
+	boardManager := task.NewBoardManager(repository)
+	boardManager.SetHierarchyManager(hierarchyManager)
+	boardManager.SetEventBus(eventBus)
+	sprintManager := task.NewSprintManager(repository)
+	customFieldManager := task.NewCustomFieldManager(repository)
+	templateManager := task.NewTemplateManager(repository)
+	templateManager.SetEventBus(eventBus)
+
 	// Create auth service
 	authService := auth.NewAuthService(repository)
 
 	// Create notification service
 	notificationService := notifications.NewNotificationService(repository, notifications.DefaultNotificationConfig())
 	notificationManager := task.NewNotificationManager(repository, notificationService)
+	if cfg.Features.NotificationsEnabled {
+		notificationManager.SubscribeToEvents(eventBus)
+	}
+	if cfg.Tasks.AuditLog {
+		eventBus.SubscribeAsync(task.LogEvents(log.New(os.Stdout, "audit: ", log.LstdFlags)))
+	}
+
+	// Archive old completed tasks in the background
+	if cfg.Tasks.AutoArchiveDays > 0 && repository != nil {
+		policy := task.ArchivePolicy{
+			CompletedFor: time.Duration(cfg.Tasks.AutoArchiveDays) * 24 * time.Hour,
+			Interval:     time.Duration(cfg.Tasks.AutoArchiveIntervalMinutes) * time.Minute,
+		}
+		if err := policy.Validate(); err != nil {
+			log.Printf("⚠️  Auto-archiving disabled: %v", err)
+		} else {
+			archiver := task.NewAutoArchiver(repository, policy)
+			archiver.SetEventBus(eventBus)
+			archiver.Start()
+			defer archiver.Stop()
+			log.Printf("✅ Auto-archiving tasks completed more than %d days ago", cfg.Tasks.AutoArchiveDays)
+		}
+	}
+
+	// Purge deleted tasks once their retention in the trash has expired
+	if repository != nil {
+		policy := task.TrashPolicy{
+			Retention: time.Duration(cfg.Tasks.TrashRetentionDays) * 24 * time.Hour,
+			Interval:  time.Duration(cfg.Tasks.TrashPurgeIntervalMinutes) * time.Minute,
+		}
+		if err := policy.Validate(); err != nil {
+			log.Printf("⚠️  Trash purging disabled: %v", err)
+		} else {
+			purger := task.NewTrashPurger(repository, policy)
+			purger.SetAttachmentManager(attachmentManager)
+			purger.Start()
+			defer purger.Stop()
+			log.Printf("✅ Purging deleted tasks after %d days in the trash", cfg.Tasks.TrashRetentionDays)
+		}
+	}
+
+	// Wake snoozed tasks when their time comes, so that their owners are notified
+	if repository != nil {
+		if cfg.Tasks.SnoozeWakeIntervalMinutes <= 0 {
+			log.Printf("⚠️  Waking snoozed tasks disabled: interval must be positive")
+		} else {
+			waker := task.NewSnoozeWaker(repository, time.Duration(cfg.Tasks.SnoozeWakeIntervalMinutes)*time.Minute)
+			waker.SetEventBus(eventBus)
+			waker.Start()
+			defer waker.Stop()
+		}
+	}
 
 	// Create API server
 	server := api.NewServer(
@@ -103,6 +201,13 @@ func main() {
 		searchManager,
 		exportManager,
 		notificationManager,
+		commentManager,
+		attachmentManager,
+		projectManager,
+		boardManager,
+		sprintManager,
+		customFieldManager,
+		templateManager,
 		authService,
 	)
 
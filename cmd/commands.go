@@ -1,17 +1,21 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
+	"learn-go-capstone/internal/quickadd"
+	"learn-go-capstone/internal/search"
 	"learn-go-capstone/internal/task"
 	"github.com/fatih/color"
 )
 
-// HandleCommand processes command line arguments
-func HandleCommand(args []string, tm task.TaskManagerInterface) {
+// HandleCommand processes command line arguments. Storage calls are bound to ctx.
+func HandleCommand(ctx context.Context, args []string, tm task.TaskManagerV2) {
 	if len(args) == 0 {
 		return
 	}
@@ -20,17 +24,47 @@ func HandleCommand(args []string, tm task.TaskManagerInterface) {
 	
 	switch command {
 	case "add":
-		handleAddCommand(args[1:], tm)
+		handleAddCommand(ctx, args[1:], tm)
 	case "list":
-		handleListCommand(args[1:], tm)
+		handleListCommand(ctx, args[1:], tm)
 	case "update":
-		handleUpdateCommand(args[1:], tm)
+		handleUpdateCommand(ctx, args[1:], tm)
 	case "delete":
-		handleDeleteCommand(args[1:], tm)
+		handleDeleteCommand(ctx, args[1:], tm)
+	case "archive":
+		handleArchiveCommand(ctx, args[1:], tm, true)
+	case "unarchive":
+		handleArchiveCommand(ctx, args[1:], tm, false)
+	case "snooze":
+		handleSnoozeCommand(ctx, args[1:], tm)
+	case "unsnooze":
+		handleUnsnoozeCommand(ctx, args[1:], tm)
+	case "trash":
+		handleTrashCommand(ctx, tm)
+	case "restore":
+		handleRestoreCommand(ctx, args[1:], tm)
+	case "undo":
+		handleUndoCommand(ctx, tm, true)
+	case "redo":
+		handleUndoCommand(ctx, tm, false)
+	case "history":
+		handleHistoryCommand(ctx, args[1:], tm)
+	case "start":
+		handleStartCommand(ctx, args[1:], tm)
+	case "stop":
+		handleStopCommand(ctx, tm)
+	case "log":
+		handleLogCommand(ctx, args[1:], tm)
+	case "sprint":
+		handleSprintCommand(ctx, args[1:], tm)
+	case "bulk":
+		handleBulkCommand(ctx, args[1:], tm)
+	case "next":
+		handleNextCommand(ctx, args[1:], tm)
 	case "stats":
-		handleStatsCommand(tm)
+		handleStatsCommand(ctx, tm)
 	case "demo":
-		handleDemoCommand(tm)
+		handleDemoCommand(ctx, tm)
 	case "help":
 		handleHelpCommand()
 	default:
@@ -39,42 +73,142 @@ func HandleCommand(args []string, tm task.TaskManagerInterface) {
 	}
 }
 
-func handleAddCommand(args []string, tm task.TaskManagerInterface) {
-	if len(args) < 2 {
-		color.Red("❌ Usage: go run main.go add <title> <description> [priority] [due_date]")
-		color.White("Priority: 1=Low, 2=Medium, 3=High, 4=Urgent")
-		color.White("Due date format: YYYY-MM-DD")
+const addUsage = "❌ Usage: go run main.go add <text> [--desc <description>]"
+
+func handleAddCommand(ctx context.Context, args []string, tm task.TaskManagerV2) {
+	if isPositionalAdd(args) {
+		handlePositionalAddCommand(ctx, args, tm)
 		return
 	}
 	
-	title := args[0]
-	description := args[1]
+	var words []string
+	description := ""
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--desc" {
+			if i+1 == len(args) {
+				color.Red(addUsage)
+				return
+			}
+			description = args[i+1]
+			i++
+			continue
+		}
+		words = append(words, args[i])
+	}
 	
-	priority := task.Medium // Default priority
-	if len(args) > 2 {
-		if p, err := strconv.Atoi(args[2]); err == nil && p >= 1 && p <= 4 {
-			priority = task.Priority(p - 1)
+	if len(words) == 0 {
+		color.Red(addUsage)
+		color.White(quickAddHelp)
+		return
+	}
+	
+	entry, err := quickadd.Parse(strings.Join(words, " "), time.Now())
+	if err != nil {
+		color.Red("❌ %v", err)
+		color.White(quickAddHelp)
+		return
+	}
+	
+	// A number given as an argument of its own that quick-add did not read
+	// as part of a date is most likely a priority, so it is rejected instead
+	// of ending up in the title
+	titleWords := strings.Fields(entry.Title)
+	for _, word := range words {
+		if _, err := strconv.Atoi(word); err != nil {
+			continue
+		}
+		for _, titleWord := range titleWords {
+			if titleWord == word {
+				color.Red("❌ Unexpected number %s: use !low..!urgent (or !1-!4) for the priority, or quote it together with the title", word)
+				color.White(quickAddHelp)
+				return
+			}
 		}
 	}
 	
+	newTask, err := tm.QuickAddContext(ctx, entry, description)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	printAddedTask(newTask)
+}
+
+// isPositionalAdd reports whether the arguments use the original
+// add <title> <description> <priority> [due_date] form, whose priority is a
+// bare number from 1 to 4 and whose due date is written as YYYY-MM-DD. The
+// title or the description must be quoted text with a space in it, so that
+// quick-add text such as "add Read chapter 3" is not mistaken for it.
+func isPositionalAdd(args []string) bool {
+	if len(args) != 3 && len(args) != 4 {
+		return false
+	}
+	if !strings.Contains(args[0], " ") && !strings.Contains(args[1], " ") {
+		return false
+	}
+	if p, err := strconv.Atoi(args[2]); err != nil || p < 1 || p > 4 {
+		return false
+	}
+	if len(args) == 4 {
+		if _, err := time.Parse("2006-01-02", args[3]); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// handlePositionalAddCommand adds a task given in the original positional form
+func handlePositionalAddCommand(ctx context.Context, args []string, tm task.TaskManagerV2) {
+	title := args[0]
+	description := args[1]
+	
+	p, _ := strconv.Atoi(args[2])
+	priority := task.Priority(p)
+	
 	var dueDate *time.Time
 	if len(args) > 3 {
-		if parsed, err := time.Parse("2006-01-02", args[3]); err == nil {
-			dueDate = &parsed
-		}
+		parsed, _ := time.Parse("2006-01-02", args[3])
+		dueDate = &parsed
 	}
 	
-	newTask := tm.AddTask(title, description, priority, dueDate)
+	newTask, err := tm.AddTaskContext(ctx, title, description, priority, dueDate)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	printAddedTask(newTask)
+}
+
+// printAddedTask shows a task that was just added
+func printAddedTask(newTask *task.Task) {
 	color.Green("✅ Task added successfully!")
 	color.White("ID: %d | Title: %s | Priority: %s", 
 		newTask.ID, newTask.Title, newTask.Priority.String())
+	if newTask.DueDate != nil {
+		color.White("Due: %s", newTask.DueDate.Format("2006-01-02 15:04"))
+	}
+	if newTask.Recurrence != nil {
+		color.White("Repeats: %s", newTask.Recurrence.String())
+	}
+	if newTask.Category != nil {
+		color.White("Category: %s", newTask.Category.Name)
+	}
+	if len(newTask.Tags) > 0 {
+		names := make([]string, len(newTask.Tags))
+		for i, tag := range newTask.Tags {
+			names[i] = "#" + tag.Name
+		}
+		color.White("Tags: %s", strings.Join(names, " "))
+	}
 }
 
-func handleListCommand(args []string, tm task.TaskManagerInterface) {
-	tasks := tm.GetAllTasks()
-	
-	if len(tasks) == 0 {
-		color.Yellow("📝 No tasks found.")
+// quickAddHelp explains the quick-add syntax shared by the add command and interactive mode
+const quickAddHelp = "Quick-add: !low..!urgent priority, #tag, @category, dates like tomorrow 5pm, fri, in 3 days, 2024-12-31, repeats like every month or every mon,fri"
+
+func handleListCommand(ctx context.Context, args []string, tm task.TaskManagerV2) {
+	tasks, err := tm.GetAllTasksContext(ctx)
+	if err != nil {
+		color.Red("❌ %v", err)
 		return
 	}
 	
@@ -83,22 +217,35 @@ func handleListCommand(args []string, tm task.TaskManagerInterface) {
 		filter := args[0]
 		switch filter {
 		case "pending":
-			tasks = tm.GetTasksByStatus(task.Pending)
+			tasks, err = tm.GetTasksByStatusContext(ctx, task.Pending)
 		case "in-progress":
-			tasks = tm.GetTasksByStatus(task.InProgress)
+			tasks, err = tm.GetTasksByStatusContext(ctx, task.InProgress)
 		case "completed":
-			tasks = tm.GetTasksByStatus(task.Completed)
+			tasks, err = tm.GetTasksByStatusContext(ctx, task.Completed)
 		case "cancelled":
-			tasks = tm.GetTasksByStatus(task.Cancelled)
+			tasks, err = tm.GetTasksByStatusContext(ctx, task.Cancelled)
 		case "overdue":
-			tasks = tm.GetOverdueTasks()
+			tasks, err = tm.GetOverdueTasksContext(ctx)
+		case "archived":
+			tasks, err = tm.GetArchivedTasksContext(ctx)
+		case "snoozed":
+			tasks, err = tm.GetSnoozedTasksContext(ctx)
 		case "priority":
 			if len(args) > 1 {
 				if p, err := strconv.Atoi(args[1]); err == nil && p >= 1 && p <= 4 {
-					tasks = tm.GetTasksByPriority(task.Priority(p - 1))
+					tasks, err = tm.GetTasksByPriorityContext(ctx, task.Priority(p - 1))
 				}
 			}
 		}
+		if err != nil {
+			color.Red("❌ %v", err)
+			return
+		}
+	}
+	
+	if len(tasks) == 0 {
+		color.Yellow("📝 No tasks found.")
+		return
 	}
 	
 	color.Cyan("📋 Task List:")
@@ -119,7 +266,7 @@ func handleListCommand(args []string, tm task.TaskManagerInterface) {
 	}
 }
 
-func handleUpdateCommand(args []string, tm task.TaskManagerInterface) {
+func handleUpdateCommand(ctx context.Context, args []string, tm task.TaskManagerV2) {
 	if len(args) < 2 {
 		color.Red("❌ Usage: go run main.go update <task_id> <status>")
 		color.White("Status: pending, in-progress, completed, cancelled")
@@ -132,35 +279,138 @@ func handleUpdateCommand(args []string, tm task.TaskManagerInterface) {
 		return
 	}
 	
-	statusStr := strings.ToLower(args[1])
-	var status task.Status
+	status, ok := parseStatusArg(args[1])
+	if !ok {
+		color.Red("❌ Invalid status. Use: pending, in-progress, completed, cancelled")
+		return
+	}
 	
-	switch statusStr {
+	err = tm.UpdateTaskStatusContext(ctx, id, status)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	
+	color.Green("✅ Task status updated successfully!")
+}
+
+// parseStatusArg parses a status given on the command line
+func parseStatusArg(arg string) (task.Status, bool) {
+	switch strings.ToLower(arg) {
 	case "pending":
-		status = task.Pending
+		return task.Pending, true
 	case "in-progress":
-		status = task.InProgress
+		return task.InProgress, true
 	case "completed":
-		status = task.Completed
+		return task.Completed, true
 	case "cancelled":
-		status = task.Cancelled
+		return task.Cancelled, true
 	default:
-		color.Red("❌ Invalid status. Use: pending, in-progress, completed, cancelled")
+		return 0, false
+	}
+}
+
+func handleDeleteCommand(ctx context.Context, args []string, tm task.TaskManagerV2) {
+	if len(args) < 1 {
+		color.Red("❌ Usage: go run main.go delete <task_id>")
+		return
+	}
+	
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		color.Red("❌ Invalid task ID")
 		return
 	}
 	
-	err = tm.UpdateTaskStatus(id, status)
+	err = tm.DeleteTaskContext(ctx, id)
 	if err != nil {
 		color.Red("❌ %v", err)
 		return
 	}
 	
-	color.Green("✅ Task status updated successfully!")
+	color.Green("✅ Task moved to the trash! Use 'restore %d' to bring it back.", id)
+}
+
+func handleTrashCommand(ctx context.Context, tm task.TaskManagerV2) {
+	tasks, err := tm.GetDeletedTasksContext(ctx)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	
+	if len(tasks) == 0 {
+		color.Yellow("🗑️  The trash is empty.")
+		return
+	}
+	
+	color.Cyan("🗑️  Deleted Tasks:")
+	for _, t := range tasks {
+		deletedAt := "N/A"
+		if t.DeletedAt != nil {
+			deletedAt = t.DeletedAt.Format("2006-01-02 15:04")
+		}
+		
+		fmt.Printf("ID: %d | %s | %s | Deleted: %s\n",
+			t.ID, t.Title,
+			getStatusColor(t.Status)(t.Status.String()),
+			deletedAt)
+	}
 }
 
-func handleDeleteCommand(args []string, tm task.TaskManagerInterface) {
+func handleRestoreCommand(ctx context.Context, args []string, tm task.TaskManagerV2) {
 	if len(args) < 1 {
-		color.Red("❌ Usage: go run main.go delete <task_id>")
+		color.Red("❌ Usage: go run main.go restore <task_id>")
+		return
+	}
+	
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		color.Red("❌ Invalid task ID")
+		return
+	}
+	
+	if err := tm.RestoreTaskContext(ctx, id); err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	
+	color.Green("✅ Task restored successfully!")
+}
+
+func handleArchiveCommand(ctx context.Context, args []string, tm task.TaskManagerV2, archive bool) {
+	command := "unarchive"
+	if archive {
+		command = "archive"
+	}
+	
+	if len(args) < 1 {
+		color.Red("❌ Usage: go run main.go %s <task_id>", command)
+		return
+	}
+	
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		color.Red("❌ Invalid task ID")
+		return
+	}
+	
+	if archive {
+		err = tm.ArchiveTaskContext(ctx, id)
+	} else {
+		err = tm.UnarchiveTaskContext(ctx, id)
+	}
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	
+	color.Green("✅ Task %sd successfully!", command)
+}
+
+func handleSnoozeCommand(ctx context.Context, args []string, tm task.TaskManagerV2) {
+	if len(args) < 2 {
+		color.Red("❌ Usage: go run main.go snooze <task_id> <when>")
+		color.White("When: 3 days, for 2 weeks, until monday, until tomorrow 9am")
 		return
 	}
 	
@@ -170,17 +420,465 @@ func handleDeleteCommand(args []string, tm task.TaskManagerInterface) {
 		return
 	}
 	
-	err = tm.DeleteTask(id)
+	until, err := quickadd.ParseSnooze(strings.Join(args[1:], " "), time.Now())
 	if err != nil {
 		color.Red("❌ %v", err)
 		return
 	}
 	
-	color.Green("✅ Task deleted successfully!")
+	if err := tm.SnoozeTaskContext(ctx, id, until); err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	
+	color.Green("✅ Task snoozed until %s", until.Format("2006-01-02 15:04"))
 }
 
-func handleStatsCommand(tm task.TaskManagerInterface) {
-	allTasks := tm.GetAllTasks()
+func handleUnsnoozeCommand(ctx context.Context, args []string, tm task.TaskManagerV2) {
+	if len(args) < 1 {
+		color.Red("❌ Usage: go run main.go unsnooze <task_id>")
+		return
+	}
+	
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		color.Red("❌ Invalid task ID")
+		return
+	}
+	
+	if err := tm.UnsnoozeTaskContext(ctx, id); err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	
+	color.Green("✅ Task unsnoozed successfully!")
+}
+
+func handleUndoCommand(ctx context.Context, tm task.TaskManagerV2, undo bool) {
+	var command *task.Command
+	var err error
+	if undo {
+		command, err = tm.UndoContext(ctx)
+	} else {
+		command, err = tm.RedoContext(ctx)
+	}
+	switch {
+	case errors.Is(err, task.ErrNothingToUndo):
+		color.Yellow("↩️  Nothing to undo.")
+		return
+	case errors.Is(err, task.ErrNothingToRedo):
+		color.Yellow("↪️  Nothing to redo.")
+		return
+	case err != nil:
+		color.Red("❌ %v", err)
+		return
+	}
+	
+	if undo {
+		color.Green("✅ Undone: %s. Use 'redo' to apply it again.", command)
+	} else {
+		color.Green("✅ Redone: %s", command)
+	}
+}
+
+func handleHistoryCommand(ctx context.Context, args []string, tm task.TaskManagerV2) {
+	if len(args) < 1 {
+		color.Red("❌ Usage: go run main.go history <task_id>")
+		return
+	}
+	
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		color.Red("❌ Invalid task ID")
+		return
+	}
+	
+	history, err := tm.GetTaskHistoryContext(ctx, id)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	
+	if len(history) == 0 {
+		color.Yellow("📝 No changes recorded for task %d.", id)
+		return
+	}
+	
+	color.Cyan("🕓 History of task %d:", id)
+	for _, entry := range history {
+		actor := "system"
+		if entry.UserID != nil {
+			actor = fmt.Sprintf("user %d", *entry.UserID)
+		}
+		
+		fmt.Printf("%s | %s | %s: %s -> %s\n",
+			entry.ChangedAt.Local().Format("2006-01-02 15:04:05"),
+			actor,
+			entry.Field,
+			historyValue(entry.OldValue),
+			historyValue(entry.NewValue))
+	}
+}
+
+// historyValue formats a recorded field value, showing unset values as a dash
+func historyValue(value *string) string {
+	if value == nil {
+		return "-"
+	}
+	return *value
+}
+
+func handleStartCommand(ctx context.Context, args []string, tm task.TaskManagerV2) {
+	if len(args) < 1 {
+		color.Red("❌ Usage: go run main.go start <task_id> [note]")
+		return
+	}
+	
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		color.Red("❌ Invalid task ID")
+		return
+	}
+	
+	entry, err := tm.StartTimerContext(ctx, id, strings.Join(args[1:], " "))
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	
+	color.Green("⏱️  Timer started on task %d at %s", entry.TaskID, entry.StartedAt.Local().Format("15:04"))
+}
+
+func handleStopCommand(ctx context.Context, tm task.TaskManagerV2) {
+	entry, err := tm.StopTimerContext(ctx)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	
+	color.Green("✅ Timer stopped: %s on task %d", formatTrackedTime(entry.Duration), entry.TaskID)
+	printTaskTime(ctx, tm, entry.TaskID)
+}
+
+func handleLogCommand(ctx context.Context, args []string, tm task.TaskManagerV2) {
+	if len(args) < 2 {
+		color.Red("❌ Usage: go run main.go log <task_id> <minutes> [note]")
+		return
+	}
+	
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		color.Red("❌ Invalid task ID")
+		return
+	}
+	
+	minutes, err := strconv.Atoi(args[1])
+	if err != nil || minutes < 1 {
+		color.Red("❌ Invalid number of minutes")
+		return
+	}
+	
+	duration := time.Duration(minutes) * time.Minute
+	entry, err := tm.LogTimeContext(ctx, id, time.Now().Add(-duration), duration, strings.Join(args[2:], " "))
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	
+	color.Green("✅ Logged %s on task %d", formatTrackedTime(entry.Duration), entry.TaskID)
+	printTaskTime(ctx, tm, entry.TaskID)
+}
+
+// printTaskTime prints the time tracked on a task against its estimate
+func printTaskTime(ctx context.Context, tm task.TaskManagerV2, id int) {
+	taskTime, err := tm.GetTaskTimeContext(ctx, id)
+	if err != nil {
+		return
+	}
+	
+	if taskTime.EstimateMinutes == nil {
+		color.White("Tracked on task %d: %s", id, formatTrackedTime(taskTime.Tracked))
+		return
+	}
+	
+	estimate := time.Duration(*taskTime.EstimateMinutes) * time.Minute
+	color.White("Tracked on task %d: %s of %s estimated", id, formatTrackedTime(taskTime.Tracked), formatTrackedTime(estimate))
+}
+
+// formatTrackedTime formats a duration as hours and minutes, e.g. 1h05m
+func formatTrackedTime(d time.Duration) string {
+	minutes := int(d / time.Minute)
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
+
+func handleSprintCommand(ctx context.Context, args []string, tm task.TaskManagerV2) {
+	if len(args) < 2 || args[0] != "report" {
+		color.Red("❌ Usage: go run main.go sprint report <sprint_id> [tasks|estimate]")
+		return
+	}
+	
+	id, err := strconv.Atoi(args[1])
+	if err != nil {
+		color.Red("❌ Invalid sprint ID")
+		return
+	}
+	
+	unit := task.BurndownTasks
+	if len(args) > 2 {
+		unit, err = task.ParseBurndownUnit(args[2])
+		if err != nil {
+			color.Red("❌ %v", err)
+			return
+		}
+	}
+	
+	report, err := tm.GetSprintReportContext(ctx, id, unit)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	
+	sprint := report.Sprint
+	state := "open"
+	if sprint.ClosedAt != nil {
+		state = "closed " + sprint.ClosedAt.Local().Format("2006-01-02")
+	}
+	color.Cyan("🏃 Sprint %d: %s (%s to %s, %s)", sprint.ID, sprint.Name,
+		sprint.StartDate.Format("2006-01-02"), sprint.EndDate.Format("2006-01-02"), state)
+	if sprint.Goal != "" {
+		color.White("Goal: %s", sprint.Goal)
+	}
+	fmt.Printf("Committed: %s | Completed: %s | Remaining: %s\n",
+		sprintValue(unit, report.Committed), sprintValue(unit, report.Completed), sprintValue(unit, report.Remaining))
+	
+	fmt.Println()
+	color.Yellow("Burndown (%s):", unit)
+	if len(report.Burndown) == 0 {
+		fmt.Println("The sprint has not started yet.")
+	}
+	for _, point := range report.Burndown {
+		fmt.Printf("%s | scope %s | done %s | remaining %s | ideal %s\n",
+			point.Date.Format("2006-01-02"),
+			sprintValue(unit, point.Scope),
+			sprintValue(unit, point.Completed),
+			sprintValue(unit, point.Remaining),
+			sprintValue(unit, int(point.Ideal+0.5)))
+	}
+	
+	fmt.Println()
+	color.Yellow("Velocity (%s):", unit)
+	if len(report.Velocity.Sprints) == 0 {
+		fmt.Println("No closed sprints yet.")
+		return
+	}
+	for _, past := range report.Velocity.Sprints {
+		fmt.Printf("%s | committed %s | completed %s\n",
+			past.Name, sprintValue(unit, past.Committed), sprintValue(unit, past.Completed))
+	}
+	fmt.Printf("Average: %s\n", sprintValue(unit, int(report.Velocity.Average+0.5)))
+}
+
+// sprintValue formats a sprint report value, which is a task count or a
+// number of estimated minutes
+func sprintValue(unit task.BurndownUnit, value int) string {
+	if unit == task.BurndownEstimate {
+		return formatTrackedTime(time.Duration(value) * time.Minute)
+	}
+	return strconv.Itoa(value)
+}
+
+const bulkUsage = "❌ Usage: go run main.go bulk <action> [value] (--ids <id,...> | --query <text> | --status <status> | --priority <1-4>) [--dry-run] [--partial]"
+
+func handleBulkCommand(ctx context.Context, args []string, tm task.TaskManagerV2) {
+	if len(args) < 1 {
+		color.Red(bulkUsage)
+		color.White("Actions: status <status>, priority <1-4>, category <id|none>, tags <+id|-id,...>, archive, delete")
+		return
+	}
+	
+	request := task.BulkRequest{Action: task.BulkAction(args[0])}
+	rest := args[1:]
+	value := func() (string, bool) {
+		if len(rest) == 0 || strings.HasPrefix(rest[0], "--") {
+			return "", false
+		}
+		v := rest[0]
+		rest = rest[1:]
+		return v, true
+	}
+	
+	switch request.Action {
+	case task.BulkSetStatus:
+		arg, ok := value()
+		status, valid := parseStatusArg(arg)
+		if !ok || !valid {
+			color.Red("❌ Invalid status. Use: pending, in-progress, completed, cancelled")
+			return
+		}
+		request.Status = &status
+	case task.BulkSetPriority:
+		arg, _ := value()
+		p, err := strconv.Atoi(arg)
+		if err != nil {
+			color.Red("❌ Invalid priority. Use 1-4")
+			return
+		}
+		priority := task.Priority(p)
+		request.Priority = &priority
+	case task.BulkSetCategory:
+		arg, ok := value()
+		if !ok {
+			color.Red("❌ Give a category ID, or none to clear the category")
+			return
+		}
+		if arg != "none" {
+			id, err := strconv.Atoi(arg)
+			if err != nil {
+				color.Red("❌ Invalid category ID")
+				return
+			}
+			request.CategoryID = &id
+		}
+	case task.BulkSetTags:
+		arg, _ := value()
+		for _, item := range strings.Split(arg, ",") {
+			id, err := strconv.Atoi(strings.TrimLeft(item, "+-"))
+			if err != nil || (!strings.HasPrefix(item, "+") && !strings.HasPrefix(item, "-")) {
+				color.Red("❌ Invalid tags. Use +<id> to add and -<id> to remove, separated by commas")
+				return
+			}
+			if strings.HasPrefix(item, "+") {
+				request.AddTagIDs = append(request.AddTagIDs, id)
+			} else {
+				request.RemoveTagIDs = append(request.RemoveTagIDs, id)
+			}
+		}
+	}
+	
+	for len(rest) > 0 {
+		option := rest[0]
+		rest = rest[1:]
+		switch option {
+		case "--dry-run":
+			request.DryRun = true
+			continue
+		case "--partial":
+			request.Partial = true
+			continue
+		}
+		
+		arg, ok := value()
+		if !ok {
+			color.Red(bulkUsage)
+			return
+		}
+		if option != "--ids" && request.Filter == nil {
+			request.Filter = &search.SearchQuery{}
+		}
+		switch option {
+		case "--ids":
+			for _, item := range strings.Split(arg, ",") {
+				id, err := strconv.Atoi(item)
+				if err != nil {
+					color.Red("❌ Invalid task ID: %s", item)
+					return
+				}
+				request.IDs = append(request.IDs, id)
+			}
+		case "--query":
+			request.Filter.Query = arg
+		case "--status":
+			status, valid := parseStatusArg(arg)
+			if !valid {
+				color.Red("❌ Invalid status. Use: pending, in-progress, completed, cancelled")
+				return
+			}
+			filterStatus := int(status)
+			request.Filter.Status = &filterStatus
+		case "--priority":
+			priority, err := strconv.Atoi(arg)
+			if err != nil {
+				color.Red("❌ Invalid priority. Use 1-4")
+				return
+			}
+			request.Filter.Priority = &priority
+		default:
+			color.Red(bulkUsage)
+			return
+		}
+	}
+	
+	result, err := tm.BulkContext(ctx, request)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	
+	for _, item := range result.Items {
+		if item.Success {
+			fmt.Printf("ID: %d | %s\n", item.TaskID, color.GreenString("ok"))
+		} else {
+			fmt.Printf("ID: %d | %s\n", item.TaskID, color.RedString(item.Error))
+		}
+	}
+	
+	if result.DryRun {
+		color.Cyan("🔍 Dry run: %d of %d tasks would be changed, %d would fail. Nothing was changed.",
+			result.Succeeded, result.Matched, result.Failed)
+		return
+	}
+	color.Green("✅ %d of %d tasks changed (%s)", result.Succeeded, result.Matched, result.Action)
+	if result.Failed > 0 {
+		color.Yellow("⚠️  %d tasks could not be changed", result.Failed)
+	}
+}
+
+func handleNextCommand(ctx context.Context, args []string, tm task.TaskManagerV2) {
+	limit := 5
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			color.Red("❌ Usage: go run main.go next [limit]")
+			return
+		}
+		limit = n
+	}
+	
+	ranked, err := tm.NextTasksContext(ctx, limit)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	
+	if len(ranked) == 0 {
+		color.Yellow("🎉 Nothing left to do.")
+		return
+	}
+	
+	color.Cyan("🎯 Next up:")
+	for i, scored := range ranked {
+		t := scored.Task
+		dueDate := "N/A"
+		if t.DueDate != nil {
+			dueDate = t.DueDate.Format("2006-01-02")
+		}
+	
+		priorityColor := getPriorityColor(t.Priority)
+	
+		fmt.Printf("%d. ID: %d | %s | %s | Due: %s | Score: %.0f\n",
+			i+1, t.ID, t.Title,
+			priorityColor(t.Priority.String()),
+			dueDate,
+			scored.Score*100)
+	}
+}
+
+func handleStatsCommand(ctx context.Context, tm task.TaskManagerV2) {
+	allTasks, err := tm.GetAllTasksContext(ctx)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
 	
 	if len(allTasks) == 0 {
 		color.Yellow("📊 No tasks to show statistics for.")
@@ -194,10 +892,16 @@ func handleStatsCommand(tm task.TaskManagerInterface) {
 	color.White("Total Tasks: %d", len(allTasks))
 	
 	// Status breakdown
-	pending := len(tm.GetTasksByStatus(task.Pending))
-	inProgress := len(tm.GetTasksByStatus(task.InProgress))
-	completed := len(tm.GetTasksByStatus(task.Completed))
-	cancelled := len(tm.GetTasksByStatus(task.Cancelled))
+	statusCounts := make(map[task.Status]int)
+	priorityCounts := make(map[task.Priority]int)
+	for _, t := range allTasks {
+		statusCounts[t.Status]++
+		priorityCounts[t.Priority]++
+	}
+	pending := statusCounts[task.Pending]
+	inProgress := statusCounts[task.InProgress]
+	completed := statusCounts[task.Completed]
+	cancelled := statusCounts[task.Cancelled]
 	
 	fmt.Printf("Pending: %d\n", pending)
 	fmt.Printf("In Progress: %d\n", inProgress)
@@ -205,10 +909,10 @@ func handleStatsCommand(tm task.TaskManagerInterface) {
 	fmt.Printf("Cancelled: %d\n", cancelled)
 	
 	// Priority breakdown
-	low := len(tm.GetTasksByPriority(task.Low))
-	medium := len(tm.GetTasksByPriority(task.Medium))
-	high := len(tm.GetTasksByPriority(task.High))
-	urgent := len(tm.GetTasksByPriority(task.Urgent))
+	low := priorityCounts[task.Low]
+	medium := priorityCounts[task.Medium]
+	high := priorityCounts[task.High]
+	urgent := priorityCounts[task.Urgent]
 	
 	fmt.Printf("\nPriority Breakdown:\n")
 	fmt.Printf("Low: %d\n", low)
@@ -217,11 +921,16 @@ func handleStatsCommand(tm task.TaskManagerInterface) {
 	fmt.Printf("Urgent: %d\n", urgent)
 	
 	// Overdue tasks
-	overdue := len(tm.GetOverdueTasks())
+	overdueTasks, err := tm.GetOverdueTasksContext(ctx)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	overdue := len(overdueTasks)
 	fmt.Printf("\nOverdue Tasks: %d\n", overdue)
 }
 
-func handleDemoCommand(tm task.TaskManagerInterface) {
+func handleDemoCommand(ctx context.Context, tm task.TaskManagerV2) {
 	color.Cyan("🔄 Go Concurrency Demo")
 	fmt.Println("Adding demo tasks using goroutines and channels...")
 	
@@ -238,20 +947,28 @@ func handleDemoCommand(tm task.TaskManagerInterface) {
 	}
 	
 	// Use channels to add tasks concurrently
-	taskChan := make(chan *task.Task, len(demoTasks))
+	type result struct {
+		task *task.Task
+		err  error
+	}
+	taskChan := make(chan result, len(demoTasks))
 	
 	// Start goroutines to add tasks
 	for _, demoTask := range demoTasks {
 		go func(title, desc string, priority task.Priority) {
-			newTask := tm.AddTask(title, desc, priority, nil)
-			taskChan <- newTask
+			newTask, err := tm.AddTaskContext(ctx, title, desc, priority, nil)
+			taskChan <- result{task: newTask, err: err}
 		}(demoTask.title, demoTask.description, demoTask.priority)
 	}
 	
 	// Collect results
 	for i := 0; i < len(demoTasks); i++ {
-		addedTask := <-taskChan
-		color.Green("✅ Added task: %s (ID: %d)", addedTask.Title, addedTask.ID)
+		added := <-taskChan
+		if added.err != nil {
+			color.Red("❌ %v", added.err)
+			continue
+		}
+		color.Green("✅ Added task: %s (ID: %d)", added.task.Title, added.task.ID)
 		time.Sleep(200 * time.Millisecond) // Simulate processing time
 	}
 	
@@ -269,24 +986,63 @@ func handleHelpCommand() {
 	fmt.Println()
 	
 	color.Yellow("Command Line Mode:")
-	color.White("  go run main.go add <title> <description> [priority] [due_date]")
+	color.White("  go run main.go add <text> [--desc <description>]")
+	color.White("  go run main.go add <title> <description> <priority> [due_date]")
 	color.White("  go run main.go list [filter]")
 	color.White("  go run main.go update <id> <status>")
 	color.White("  go run main.go delete <id>")
+	color.White("  go run main.go archive <id>")
+	color.White("  go run main.go unarchive <id>")
+	color.White("  go run main.go snooze <id> <when>")
+	color.White("  go run main.go unsnooze <id>")
+	color.White("  go run main.go trash")
+	color.White("  go run main.go restore <id>")
+	color.White("  go run main.go undo")
+	color.White("  go run main.go redo")
+	color.White("  go run main.go history <id>")
+	color.White("  go run main.go start <id> [note]")
+	color.White("  go run main.go stop")
+	color.White("  go run main.go log <id> <minutes> [note]")
+	color.White("  go run main.go sprint report <sprint_id> [tasks|estimate]")
+	color.White("  go run main.go bulk <action> [value] <targets> [--dry-run] [--partial]")
+	color.White("  go run main.go next [limit]")
 	color.White("  go run main.go stats")
 	color.White("  go run main.go demo")
 	color.White("  go run main.go help")
 	fmt.Println()
 	
 	color.Yellow("Examples:")
-	color.White("  go run main.go add \"Learn Go\" \"Study Go programming\" 3 2024-12-31")
+	color.White("  go run main.go add Learn Go by dec 31 !high #learning --desc \"Study Go programming\"")
+	color.White("  go run main.go add Pay rent tomorrow 5pm !urgent #finance every month")
+	color.White("  go run main.go add \"Build Project\" \"Create a real application\" 4 2024-12-31")
 	color.White("  go run main.go list pending")
 	color.White("  go run main.go update 1 completed")
 	color.White("  go run main.go delete 1")
+	color.White("  go run main.go restore 1")
+	color.White("  go run main.go undo")
+	color.White("  go run main.go snooze 1 until monday 9am")
+	color.White("  go run main.go log 1 45 \"Code review\"")
+	color.White("  go run main.go sprint report 2 estimate")
+	color.White("  go run main.go next 5")
+	color.White("  go run main.go bulk status completed --ids 1,2,3")
+	color.White("  go run main.go bulk tags +4,-2 --query release --dry-run")
+	fmt.Println()
+	
+	color.Yellow("Quick-add syntax for add:")
+	color.White("  !low, !medium, !high, !urgent (or !1-!4), #tag, @category")
+	color.White("  today, tomorrow, fri, next monday, in 3 days, next week, 2024-12-31, dec 31, at 5pm, 17:30, noon")
+	color.White("  daily, weekly, every month, every 2 weeks, every other day, every weekday, every mon,fri")
+	color.White("  Text in double quotes is kept in the title as written")
 	fmt.Println()
 	
 	color.Yellow("Filters for list command:")
-	color.White("  pending, in-progress, completed, cancelled, overdue, priority <1-4>")
+	color.White("  pending, in-progress, completed, cancelled, overdue, archived, snoozed, priority <1-4>")
+	fmt.Println()
+	
+	color.Yellow("Bulk actions and targets:")
+	color.White("  status <status>, priority <1-4>, category <id|none>, tags <+id|-id,...>, archive, delete")
+	color.White("  --ids <id,...> or filters --query <text>, --status <status>, --priority <1-4>")
+	color.White("  Bulk operations need database storage; without --partial nothing changes if any task fails")
 }
 
 func getPriorityColor(p task.Priority) func(string) string {
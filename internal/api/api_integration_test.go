@@ -5,10 +5,14 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"learn-go-capstone/internal/attachments"
 	"learn-go-capstone/internal/auth"
 	"learn-go-capstone/internal/database"
 	"learn-go-capstone/internal/notifications"
@@ -60,6 +64,21 @@ func setupTestAPI(t *testing.T) (*httptest.Server, *Server, func()) {
 
 	// Create notification manager
 	notificationManager := task.NewNotificationManager(repository, notifService)
+	commentManager := task.NewCommentManager(repository)
+
+	attachmentStore, err := attachments.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Should create attachment store: %v", err)
+	}
+	attachmentManager := task.NewAttachmentManager(repository, attachmentStore, attachments.Limits{
+		MaxSize:      1 << 20,
+		AllowedTypes: []string{"image/*", "text/*", "application/pdf"},
+	})
+	projectManager := task.NewProjectManager(repository)
+	boardManager := task.NewBoardManager(repository)
+	sprintManager := task.NewSprintManager(repository)
+	customFieldManager := task.NewCustomFieldManager(repository)
+	templateManager := task.NewTemplateManager(repository)
 
 	// Create server
 	server := NewServer(
@@ -70,6 +89,13 @@ func setupTestAPI(t *testing.T) (*httptest.Server, *Server, func()) {
 		searchManager,
 		exportManager,
 		notificationManager,
+		commentManager,
+		attachmentManager,
+		projectManager,
+		boardManager,
+		sprintManager,
+		customFieldManager,
+		templateManager,
 		authService,
 	)
 
@@ -164,3 +190,1870 @@ func TestUnauthorizedAccess(t *testing.T) {
 		t.Fatal("Response should indicate failure")
 	}
 }
+
+// registerAndLogin creates a user through the API and returns a bearer token
+func registerAndLogin(t *testing.T, serverURL, username string) string {
+	userData, _ := json.Marshal(map[string]string{
+		"username": username,
+		"email":    username + "@example.com",
+		"password": "password123",
+	})
+	resp, err := http.Post(serverURL+"/api/v1/auth/register", "application/json", bytes.NewBuffer(userData))
+	if err != nil {
+		t.Fatalf("Should make registration request: %v", err)
+	}
+	resp.Body.Close()
+
+	loginData, _ := json.Marshal(map[string]string{
+		"username": username,
+		"password": "password123",
+	})
+	resp, err = http.Post(serverURL+"/api/v1/auth/login", "application/json", bytes.NewBuffer(loginData))
+	if err != nil {
+		t.Fatalf("Should make login request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Data LoginResponse `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("Should decode login response: %v", err)
+	}
+	if response.Data.Token == "" {
+		t.Fatal("Login should return a token")
+	}
+
+	return response.Data.Token
+}
+
+// doJSON sends an authenticated JSON request and decodes the response body
+func doJSON(t *testing.T, method, url, token string, body interface{}, out interface{}) int {
+	var payload []byte
+	switch b := body.(type) {
+	case nil:
+	case string:
+		payload = []byte(b)
+	default:
+		payload, _ = json.Marshal(b)
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewBuffer(payload))
+	if err != nil {
+		t.Fatalf("Should create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Should make %s request: %v", method, err)
+	}
+	defer resp.Body.Close()
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("Should decode response: %v", err)
+		}
+	}
+
+	return resp.StatusCode
+}
+
+func TestPatchTask(t *testing.T) {
+	server, _, cleanup := setupTestAPI(t)
+	defer cleanup()
+
+	token := registerAndLogin(t, server.URL, "patcher")
+
+	var created struct {
+		Data TaskResponse `json:"data"`
+	}
+	status := doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks", token, map[string]interface{}{
+		"title":       "Original title",
+		"description": "Original description",
+		"priority":    2,
+		"due_date":    "2030-01-01T10:00:00Z",
+	}, &created)
+	if status != http.StatusCreated {
+		t.Fatalf("Task creation should return 201, got %d", status)
+	}
+
+	taskURL := fmt.Sprintf("%s/api/v1/tasks/%d", server.URL, created.Data.ID)
+
+	var patched struct {
+		Data TaskResponse `json:"data"`
+	}
+	status = doJSON(t, http.MethodPatch, taskURL, token, `{"title": "Patched title", "due_date": null}`, &patched)
+	if status != http.StatusOK {
+		t.Fatalf("Patch should return 200, got %d", status)
+	}
+
+	if patched.Data.ID != created.Data.ID {
+		t.Errorf("Patch should keep the task ID %d, got %d", created.Data.ID, patched.Data.ID)
+	}
+	if patched.Data.Title != "Patched title" {
+		t.Errorf("Expected title 'Patched title', got '%s'", patched.Data.Title)
+	}
+	if patched.Data.Description != "Original description" {
+		t.Errorf("Omitted description should be unchanged, got '%s'", patched.Data.Description)
+	}
+	if patched.Data.Priority != 2 {
+		t.Errorf("Omitted priority should be unchanged, got %d", patched.Data.Priority)
+	}
+	if patched.Data.DueDate != nil {
+		t.Error("A null due_date should clear the due date")
+	}
+
+	// An empty patch returns the task without touching it
+	var history struct {
+		Data []TaskHistoryResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodGet, taskURL+"/history", token, nil, &history); status != http.StatusOK {
+		t.Fatalf("Getting history should return 200, got %d", status)
+	}
+	entries := len(history.Data)
+	var unchanged struct {
+		Data TaskResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodPatch, taskURL, token, `{}`, &unchanged); status != http.StatusOK {
+		t.Fatalf("Empty patch should return 200, got %d", status)
+	}
+	if unchanged.Data.Title != "Patched title" || !unchanged.Data.UpdatedAt.Equal(patched.Data.UpdatedAt) {
+		t.Errorf("Expected the task unchanged, got %+v", unchanged.Data)
+	}
+	if status := doJSON(t, http.MethodGet, taskURL+"/history", token, nil, &history); status != http.StatusOK {
+		t.Fatalf("Getting history should return 200, got %d", status)
+	}
+	if len(history.Data) != entries {
+		t.Errorf("Expected no history entry for an empty patch, got %+v", history.Data)
+	}
+
+	if status := doJSON(t, http.MethodPatch, taskURL, token, `{"priority": 7}`, nil); status != http.StatusBadRequest {
+		t.Errorf("Invalid priority should return 400, got %d", status)
+	}
+	if status := doJSON(t, http.MethodPatch, taskURL, token, `{"status": 2}`, nil); status != http.StatusBadRequest {
+		t.Errorf("Read-only field should return 400, got %d", status)
+	}
+
+	otherToken := registerAndLogin(t, server.URL, "intruder")
+	if status := doJSON(t, http.MethodPatch, taskURL, otherToken, `{"title": "Hijacked"}`, nil); status != http.StatusNotFound {
+		t.Errorf("Patching another user's task should return 404, got %d", status)
+	}
+}
+
+func TestTaskHierarchy(t *testing.T) {
+	server, _, cleanup := setupTestAPI(t)
+	defer cleanup()
+
+	token := registerAndLogin(t, server.URL, "planner")
+
+	var parent struct {
+		Data TaskResponse `json:"data"`
+	}
+	status := doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks", token, map[string]interface{}{
+		"title":    "Plan trip",
+		"priority": 2,
+	}, &parent)
+	if status != http.StatusCreated {
+		t.Fatalf("Task creation should return 201, got %d", status)
+	}
+
+	var subtaskIDs []int
+	for _, title := range []string{"Book flights", "Book hotel"} {
+		var child struct {
+			Data TaskResponse `json:"data"`
+		}
+		status := doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks", token, map[string]interface{}{
+			"title":     title,
+			"priority":  2,
+			"parent_id": parent.Data.ID,
+		}, &child)
+		if status != http.StatusCreated {
+			t.Fatalf("Subtask creation should return 201, got %d", status)
+		}
+		if child.Data.ParentID == nil || *child.Data.ParentID != parent.Data.ID {
+			t.Errorf("Subtask should point at parent %d, got %v", parent.Data.ID, child.Data.ParentID)
+		}
+		subtaskIDs = append(subtaskIDs, child.Data.ID)
+	}
+
+	// A task with an invalid parent is not created at all
+	status = doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks", token, map[string]interface{}{
+		"title":     "Rent a car",
+		"priority":  2,
+		"parent_id": 9999,
+	}, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("Creating a task with a missing parent should return 400, got %d", status)
+	}
+	var listed struct {
+		Data []TaskResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodGet, server.URL+"/api/v1/tasks?page_size=50", token, nil, &listed); status != http.StatusOK || len(listed.Data) != 3 {
+		t.Errorf("Expected the rejected task not to be stored, got %d tasks (%d)", len(listed.Data), status)
+	}
+
+	statusURL := fmt.Sprintf("%s/api/v1/tasks/%d/status", server.URL, subtaskIDs[0])
+	if status := doJSON(t, http.MethodPut, statusURL, token, map[string]int{"status": 2}, nil); status != http.StatusOK {
+		t.Fatalf("Status update should return 200, got %d", status)
+	}
+
+	var subtree struct {
+		Data TaskResponse `json:"data"`
+	}
+	status = doJSON(t, http.MethodGet, fmt.Sprintf("%s/api/v1/tasks/%d?include=subtree", server.URL, parent.Data.ID), token, nil, &subtree)
+	if status != http.StatusOK {
+		t.Fatalf("Get task should return 200, got %d", status)
+	}
+	if len(subtree.Data.Subtasks) != 2 {
+		t.Errorf("Expected 2 subtasks in the subtree, got %d", len(subtree.Data.Subtasks))
+	}
+	if subtree.Data.Progress == nil || *subtree.Data.Progress != 50 {
+		t.Errorf("Expected progress 50, got %v", subtree.Data.Progress)
+	}
+
+	var ancestors struct {
+		Data []TaskResponse `json:"data"`
+	}
+	status = doJSON(t, http.MethodGet, fmt.Sprintf("%s/api/v1/tasks/%d/ancestors", server.URL, subtaskIDs[1]), token, nil, &ancestors)
+	if status != http.StatusOK || len(ancestors.Data) != 1 || ancestors.Data[0].ID != parent.Data.ID {
+		t.Errorf("Expected parent %d as the only ancestor, got %d %v", parent.Data.ID, status, ancestors.Data)
+	}
+
+	// Moving the parent below its own subtask would create a cycle
+	parentURL := fmt.Sprintf("%s/api/v1/tasks/%d/parent", server.URL, parent.Data.ID)
+	if status := doJSON(t, http.MethodPut, parentURL, token, map[string]int{"parent_id": subtaskIDs[1]}, nil); status != http.StatusBadRequest {
+		t.Errorf("Creating a cycle should return 400, got %d", status)
+	}
+
+	childParentURL := fmt.Sprintf("%s/api/v1/tasks/%d/parent", server.URL, subtaskIDs[1])
+	if status := doJSON(t, http.MethodPut, childParentURL, token, `{"parent_id": null}`, nil); status != http.StatusOK {
+		t.Errorf("Detaching a subtask should return 200, got %d", status)
+	}
+
+	var subtasks struct {
+		Data []TaskResponse `json:"data"`
+	}
+	status = doJSON(t, http.MethodGet, fmt.Sprintf("%s/api/v1/tasks/%d/subtasks", server.URL, parent.Data.ID), token, nil, &subtasks)
+	if status != http.StatusOK || len(subtasks.Data) != 1 || subtasks.Data[0].ID != subtaskIDs[0] {
+		t.Errorf("Expected only subtask %d after detaching, got %d %v", subtaskIDs[0], status, subtasks.Data)
+	}
+}
+
+func TestTaskStatusWorkflow(t *testing.T) {
+	server, _, cleanup := setupTestAPI(t)
+	defer cleanup()
+
+	token := registerAndLogin(t, server.URL, "reviewer")
+
+	var created struct {
+		Data TaskResponse `json:"data"`
+	}
+	status := doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks", token, map[string]interface{}{
+		"title":    "Ship feature",
+		"priority": 3,
+	}, &created)
+	if status != http.StatusCreated {
+		t.Fatalf("Task creation should return 201, got %d", status)
+	}
+
+	statusURL := fmt.Sprintf("%s/api/v1/tasks/%d/status", server.URL, created.Data.ID)
+	if status := doJSON(t, http.MethodPut, statusURL, token, map[string]int{"status": 3}, nil); status != http.StatusOK {
+		t.Fatalf("Cancelling should return 200, got %d", status)
+	}
+	if status := doJSON(t, http.MethodPut, statusURL, token, map[string]int{"status": 2}, nil); status != http.StatusConflict {
+		t.Errorf("Completing a cancelled task should return 409, got %d", status)
+	}
+	if status := doJSON(t, http.MethodPut, statusURL, token, map[string]int{"status": 0}, nil); status != http.StatusOK {
+		t.Errorf("Reopening as Pending should return 200, got %d", status)
+	}
+	if status := doJSON(t, http.MethodPut, statusURL, token, map[string]int{"status": 9}, nil); status != http.StatusBadRequest {
+		t.Errorf("Unknown status should return 400, got %d", status)
+	}
+
+	var workflow struct {
+		Data []WorkflowStatusResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodGet, server.URL+"/api/v1/workflow", token, nil, &workflow); status != http.StatusOK {
+		t.Fatalf("Workflow should return 200, got %d", status)
+	}
+	if len(workflow.Data) != 4 || workflow.Data[3].Name != "Cancelled" {
+		t.Errorf("Expected the 4 built-in statuses, got %+v", workflow.Data)
+	}
+}
+
+func TestArchiveTasks(t *testing.T) {
+	server, _, cleanup := setupTestAPI(t)
+	defer cleanup()
+
+	token := registerAndLogin(t, server.URL, "archivist")
+
+	var created struct {
+		Data TaskResponse `json:"data"`
+	}
+	status := doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks", token, map[string]interface{}{
+		"title":    "Old report",
+		"priority": 1,
+	}, &created)
+	if status != http.StatusCreated {
+		t.Fatalf("Task creation should return 201, got %d", status)
+	}
+
+	archiveURL := fmt.Sprintf("%s/api/v1/tasks/%d/archive", server.URL, created.Data.ID)
+	var archived struct {
+		Data TaskResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodPost, archiveURL, token, nil, &archived); status != http.StatusOK {
+		t.Fatalf("Archiving should return 200, got %d", status)
+	}
+	if !archived.Data.IsArchived {
+		t.Error("Expected the archived task to be marked as archived")
+	}
+
+	var list struct {
+		Data []TaskResponse `json:"data"`
+	}
+	doJSON(t, http.MethodGet, server.URL+"/api/v1/tasks", token, nil, &list)
+	if len(list.Data) != 0 {
+		t.Errorf("Expected archived tasks to be hidden, got %d tasks", len(list.Data))
+	}
+	doJSON(t, http.MethodGet, server.URL+"/api/v1/tasks?include_archived=true", token, nil, &list)
+	if len(list.Data) != 1 || !list.Data[0].IsArchived {
+		t.Errorf("Expected the archived task with include_archived, got %+v", list.Data)
+	}
+
+	search := map[string]interface{}{"query": "report", "page": 1, "page_size": 10}
+	doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks/search", token, search, &list)
+	if len(list.Data) != 0 {
+		t.Errorf("Expected search to skip archived tasks, got %d", len(list.Data))
+	}
+	search["include_archived"] = true
+	doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks/search", token, search, &list)
+	if len(list.Data) != 1 {
+		t.Errorf("Expected search with include_archived to find the task, got %d", len(list.Data))
+	}
+
+	if status := doJSON(t, http.MethodDelete, archiveURL, token, nil, nil); status != http.StatusOK {
+		t.Fatalf("Unarchiving should return 200, got %d", status)
+	}
+	doJSON(t, http.MethodGet, server.URL+"/api/v1/tasks", token, nil, &list)
+	if len(list.Data) != 1 {
+		t.Errorf("Expected the unarchived task to be listed, got %d tasks", len(list.Data))
+	}
+
+	other := registerAndLogin(t, server.URL, "stranger")
+	if status := doJSON(t, http.MethodPost, archiveURL, other, nil, nil); status != http.StatusNotFound {
+		t.Errorf("Archiving another user's task should return 404, got %d", status)
+	}
+}
+
+func TestTrashAndRestoreTasks(t *testing.T) {
+	server, _, cleanup := setupTestAPI(t)
+	defer cleanup()
+
+	token := registerAndLogin(t, server.URL, "tidier")
+
+	var created struct {
+		Data TaskResponse `json:"data"`
+	}
+	status := doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks", token, map[string]interface{}{
+		"title":    "Mistake",
+		"priority": 2,
+	}, &created)
+	if status != http.StatusCreated {
+		t.Fatalf("Task creation should return 201, got %d", status)
+	}
+
+	taskURL := fmt.Sprintf("%s/api/v1/tasks/%d", server.URL, created.Data.ID)
+	if status := doJSON(t, http.MethodDelete, taskURL, token, nil, nil); status != http.StatusOK {
+		t.Fatalf("Deleting should return 200, got %d", status)
+	}
+	if status := doJSON(t, http.MethodGet, taskURL, token, nil, nil); status != http.StatusNotFound {
+		t.Errorf("A deleted task should return 404, got %d", status)
+	}
+
+	var list struct {
+		Data []TaskResponse `json:"data"`
+	}
+	doJSON(t, http.MethodGet, server.URL+"/api/v1/trash", token, nil, &list)
+	if len(list.Data) != 1 || list.Data[0].ID != created.Data.ID || list.Data[0].DeletedAt == nil {
+		t.Fatalf("Expected the deleted task in the trash, got %+v", list.Data)
+	}
+
+	restoreURL := fmt.Sprintf("%s/api/v1/trash/%d/restore", server.URL, created.Data.ID)
+	other := registerAndLogin(t, server.URL, "snoop")
+	if status := doJSON(t, http.MethodPost, restoreURL, other, nil, nil); status != http.StatusNotFound {
+		t.Errorf("Restoring another user's task should return 404, got %d", status)
+	}
+
+	var restored struct {
+		Data TaskResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodPost, restoreURL, token, nil, &restored); status != http.StatusOK {
+		t.Fatalf("Restoring should return 200, got %d", status)
+	}
+	if restored.Data.DeletedAt != nil {
+		t.Error("Expected the restored task to have no deletion time")
+	}
+	if status := doJSON(t, http.MethodGet, taskURL, token, nil, nil); status != http.StatusOK {
+		t.Errorf("A restored task should be found again, got %d", status)
+	}
+	if status := doJSON(t, http.MethodPost, restoreURL, token, nil, nil); status != http.StatusNotFound {
+		t.Errorf("Restoring a task that is not in the trash should return 404, got %d", status)
+	}
+}
+
+func TestTaskHistory(t *testing.T) {
+	server, _, cleanup := setupTestAPI(t)
+	defer cleanup()
+
+	token := registerAndLogin(t, server.URL, "auditor")
+
+	var created struct {
+		Data TaskResponse `json:"data"`
+	}
+	status := doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks", token, map[string]interface{}{
+		"title":    "Budget",
+		"priority": 1,
+	}, &created)
+	if status != http.StatusCreated {
+		t.Fatalf("Task creation should return 201, got %d", status)
+	}
+
+	taskURL := fmt.Sprintf("%s/api/v1/tasks/%d", server.URL, created.Data.ID)
+	if status := doJSON(t, http.MethodPatch, taskURL, token, map[string]interface{}{"priority": 4}, nil); status != http.StatusOK {
+		t.Fatalf("Patching should return 200, got %d", status)
+	}
+
+	var history struct {
+		Data []TaskHistoryResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodGet, taskURL+"/history", token, nil, &history); status != http.StatusOK {
+		t.Fatalf("Getting history should return 200, got %d", status)
+	}
+	if len(history.Data) != 2 || history.Data[0].Field != "created" {
+		t.Fatalf("Expected the creation and one change, got %+v", history.Data)
+	}
+	change := history.Data[1]
+	if change.Field != "priority" || change.OldValue == nil || *change.OldValue != "1" || change.NewValue == nil || *change.NewValue != "4" {
+		t.Errorf("Unexpected priority change %+v", change)
+	}
+	creator := history.Data[0].UserID
+	if creator == nil || change.UserID == nil || *change.UserID != *creator {
+		t.Errorf("Expected both changes to be attributed to the task owner, got %v and %v", creator, change.UserID)
+	}
+
+	other := registerAndLogin(t, server.URL, "outsider")
+	if status := doJSON(t, http.MethodGet, taskURL+"/history", other, nil, nil); status != http.StatusNotFound {
+		t.Errorf("Getting another user's task history should return 404, got %d", status)
+	}
+}
+
+func TestTimeTracking(t *testing.T) {
+	server, _, cleanup := setupTestAPI(t)
+	defer cleanup()
+
+	token := registerAndLogin(t, server.URL, "consultant")
+
+	var created struct {
+		Data TaskResponse `json:"data"`
+	}
+	status := doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks", token, map[string]interface{}{
+		"title":            "Client audit",
+		"priority":         2,
+		"estimate_minutes": 60,
+	}, &created)
+	if status != http.StatusCreated {
+		t.Fatalf("Task creation should return 201, got %d", status)
+	}
+	if created.Data.EstimateMinutes == nil || *created.Data.EstimateMinutes != 60 {
+		t.Fatalf("Expected the estimate to be set on creation, got %v", created.Data.EstimateMinutes)
+	}
+
+	// The estimate is part of the creation rather than a change after it
+	var history struct {
+		Data []TaskHistoryResponse `json:"data"`
+	}
+	historyURL := fmt.Sprintf("%s/api/v1/tasks/%d/history", server.URL, created.Data.ID)
+	if status := doJSON(t, http.MethodGet, historyURL, token, nil, &history); status != http.StatusOK {
+		t.Fatalf("Getting history should return 200, got %d", status)
+	}
+	if len(history.Data) != 1 || history.Data[0].Field != "created" {
+		t.Errorf("Expected only the creation in the history, got %+v", history.Data)
+	}
+
+	timeURL := fmt.Sprintf("%s/api/v1/tasks/%d/time", server.URL, created.Data.ID)
+	if status := doJSON(t, http.MethodPost, timeURL+"/stop", token, nil, nil); status != http.StatusConflict {
+		t.Errorf("Stopping without a running timer should return 409, got %d", status)
+	}
+	if status := doJSON(t, http.MethodPost, timeURL+"/start", token, map[string]string{"note": "kickoff"}, nil); status != http.StatusCreated {
+		t.Fatalf("Starting a timer should return 201, got %d", status)
+	}
+	if status := doJSON(t, http.MethodPost, timeURL+"/start", token, nil, nil); status != http.StatusConflict {
+		t.Errorf("Starting a second timer should return 409, got %d", status)
+	}
+	var stopped struct {
+		Data TimeEntryResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodPost, timeURL+"/stop", token, nil, &stopped); status != http.StatusOK {
+		t.Fatalf("Stopping the timer should return 200, got %d", status)
+	}
+	if stopped.Data.EndedAt == nil || stopped.Data.Note != "kickoff" {
+		t.Errorf("Expected a completed entry with its note, got %+v", stopped.Data)
+	}
+
+	if status := doJSON(t, http.MethodPost, timeURL, token, map[string]interface{}{"minutes": 0}, nil); status != http.StatusBadRequest {
+		t.Errorf("Logging no time should return 400, got %d", status)
+	}
+	if status := doJSON(t, http.MethodPost, timeURL, token, map[string]interface{}{"minutes": 30, "note": "report"}, nil); status != http.StatusCreated {
+		t.Fatalf("Logging time should return 201, got %d", status)
+	}
+
+	var taskTime struct {
+		Data TaskTimeResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodGet, timeURL, token, nil, &taskTime); status != http.StatusOK {
+		t.Fatalf("Getting task time should return 200, got %d", status)
+	}
+	if len(taskTime.Data.Entries) != 2 || taskTime.Data.TrackedSeconds < 1800 {
+		t.Errorf("Expected two entries and at least 30 minutes tracked, got %+v", taskTime.Data)
+	}
+	if taskTime.Data.EstimateMinutes == nil || *taskTime.Data.EstimateMinutes != 60 {
+		t.Errorf("Expected the estimate of 60 minutes, got %v", taskTime.Data.EstimateMinutes)
+	}
+
+	var report struct {
+		Data []TimeReportRowResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodGet, server.URL+"/api/v1/time/report?group=task", token, nil, &report); status != http.StatusOK {
+		t.Fatalf("Getting the report should return 200, got %d", status)
+	}
+	if len(report.Data) != 1 || report.Data[0].Name != "Client audit" || report.Data[0].Entries != 2 || report.Data[0].EstimateMinutes != 60 {
+		t.Errorf("Unexpected report %+v", report.Data)
+	}
+	if status := doJSON(t, http.MethodGet, server.URL+"/api/v1/time/report?group=project", token, nil, nil); status != http.StatusBadRequest {
+		t.Errorf("An unknown grouping should return 400, got %d", status)
+	}
+	if status := doJSON(t, http.MethodGet, server.URL+"/api/v1/time/report?from=yesterday", token, nil, nil); status != http.StatusBadRequest {
+		t.Errorf("An invalid range should return 400, got %d", status)
+	}
+
+	other := registerAndLogin(t, server.URL, "intruder")
+	if status := doJSON(t, http.MethodPost, timeURL+"/start", other, nil, nil); status != http.StatusNotFound {
+		t.Errorf("Starting a timer on another user's task should return 404, got %d", status)
+	}
+	if status := doJSON(t, http.MethodGet, server.URL+"/api/v1/time/report", other, nil, &report); status != http.StatusOK || len(report.Data) != 0 {
+		t.Errorf("Expected an empty report for another user, got %d %+v", status, report.Data)
+	}
+}
+
+func TestTaskComments(t *testing.T) {
+	server, _, cleanup := setupTestAPI(t)
+	defer cleanup()
+
+	token := registerAndLogin(t, server.URL, "writer")
+	registerAndLogin(t, server.URL, "editor")
+
+	var created struct {
+		Data TaskResponse `json:"data"`
+	}
+	status := doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks", token, map[string]interface{}{
+		"title":    "Blog post",
+		"priority": 2,
+	}, &created)
+	if status != http.StatusCreated {
+		t.Fatalf("Task creation should return 201, got %d", status)
+	}
+
+	taskURL := fmt.Sprintf("%s/api/v1/tasks/%d", server.URL, created.Data.ID)
+	var comment struct {
+		Data CommentResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodPost, taskURL+"/comments", token, map[string]interface{}{"body": "First draft, **@editor** please review"}, &comment); status != http.StatusCreated {
+		t.Fatalf("Commenting should return 201, got %d", status)
+	}
+	if comment.Data.Author != "writer" {
+		t.Errorf("Expected the comment to be written by writer, got %+v", comment.Data)
+	}
+	if status := doJSON(t, http.MethodPost, taskURL+"/comments", token, map[string]interface{}{"body": "Second pass done", "parent_id": comment.Data.ID}, nil); status != http.StatusCreated {
+		t.Fatalf("Replying should return 201, got %d", status)
+	}
+	if status := doJSON(t, http.MethodPost, taskURL+"/comments", token, map[string]interface{}{"body": ""}, nil); status != http.StatusBadRequest {
+		t.Errorf("An empty comment should return 400, got %d", status)
+	}
+
+	commentURL := fmt.Sprintf("%s/comments/%d", taskURL, comment.Data.ID)
+	if status := doJSON(t, http.MethodPatch, commentURL, token, map[string]interface{}{"body": "Final draft"}, &comment); status != http.StatusOK {
+		t.Fatalf("Editing should return 200, got %d", status)
+	}
+	if comment.Data.Body != "Final draft" {
+		t.Errorf("Expected the edited body, got %q", comment.Data.Body)
+	}
+
+	var threads struct {
+		Data []CommentResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodGet, taskURL+"/comments", token, nil, &threads); status != http.StatusOK {
+		t.Fatalf("Listing comments should return 200, got %d", status)
+	}
+	if len(threads.Data) != 1 || len(threads.Data[0].Replies) != 1 {
+		t.Fatalf("Expected one thread with one reply, got %+v", threads.Data)
+	}
+
+	var fetched struct {
+		Data TaskResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodGet, taskURL, token, nil, &fetched); status != http.StatusOK || fetched.Data.CommentCount != 2 {
+		t.Errorf("Expected the task to count two comments, got %d %+v", status, fetched.Data)
+	}
+
+	if status := doJSON(t, http.MethodDelete, commentURL, token, nil, nil); status != http.StatusOK {
+		t.Fatalf("Deleting should return 200, got %d", status)
+	}
+	if status := doJSON(t, http.MethodDelete, commentURL, token, nil, nil); status != http.StatusNotFound {
+		t.Errorf("Deleting twice should return 404, got %d", status)
+	}
+
+	other := registerAndLogin(t, server.URL, "stranger")
+	if status := doJSON(t, http.MethodGet, taskURL+"/comments", other, nil, nil); status != http.StatusNotFound {
+		t.Errorf("Reading comments of another user's task should return 404, got %d", status)
+	}
+}
+
+// uploadFile posts content as the "file" field of a multipart form
+func uploadFile(t *testing.T, url, token, fileName string, content []byte, out interface{}) int {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", fileName)
+	if err != nil {
+		t.Fatalf("Should create form file: %v", err)
+	}
+	part.Write(content)
+	writer.Close()
+
+	req, err := http.NewRequest(http.MethodPost, url, &body)
+	if err != nil {
+		t.Fatalf("Should create request: %v", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Should make upload request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("Should decode response: %v", err)
+		}
+	}
+
+	return resp.StatusCode
+}
+
+func TestTaskAttachments(t *testing.T) {
+	server, _, cleanup := setupTestAPI(t)
+	defer cleanup()
+
+	token := registerAndLogin(t, server.URL, "uploader")
+	otherToken := registerAndLogin(t, server.URL, "outsider")
+
+	var created struct {
+		Data TaskResponse `json:"data"`
+	}
+	status := doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks", token, map[string]interface{}{
+		"title":    "Crash report",
+		"priority": 3,
+	}, &created)
+	if status != http.StatusCreated {
+		t.Fatalf("Task creation should return 201, got %d", status)
+	}
+
+	attachmentsURL := fmt.Sprintf("%s/api/v1/tasks/%d/attachments", server.URL, created.Data.ID)
+	logContent := []byte("panic: runtime error: index out of range\n")
+
+	var uploaded struct {
+		Data AttachmentResponse `json:"data"`
+	}
+	if status := uploadFile(t, attachmentsURL, token, "logs/crash.log", logContent, &uploaded); status != http.StatusCreated {
+		t.Fatalf("Upload should return 201, got %d", status)
+	}
+	if uploaded.Data.FileName != "crash.log" || uploaded.Data.ContentType != "text/plain; charset=utf-8" || uploaded.Data.Size != int64(len(logContent)) || uploaded.Data.Uploader != "uploader" {
+		t.Errorf("Unexpected attachment %+v", uploaded.Data)
+	}
+
+	if status := uploadFile(t, attachmentsURL, token, "tool.exe", []byte("MZ\x90\x00\x03\x00\x00\x00"), nil); status != http.StatusUnsupportedMediaType {
+		t.Errorf("A disallowed type should return 415, got %d", status)
+	}
+	if status := uploadFile(t, attachmentsURL, token, "huge.txt", bytes.Repeat([]byte("a"), 1<<20+1), nil); status != http.StatusRequestEntityTooLarge {
+		t.Errorf("An oversized file should return 413, got %d", status)
+	}
+	if status := uploadFile(t, attachmentsURL, otherToken, "crash.log", logContent, nil); status != http.StatusNotFound {
+		t.Errorf("Uploading to another user's task should return 404, got %d", status)
+	}
+
+	var listed struct {
+		Data []AttachmentResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodGet, attachmentsURL, token, nil, &listed); status != http.StatusOK || len(listed.Data) != 1 {
+		t.Fatalf("Expected one attachment, got %d %+v", status, listed.Data)
+	}
+
+	attachmentURL := fmt.Sprintf("%s/%d", attachmentsURL, uploaded.Data.ID)
+	req, _ := http.NewRequest(http.MethodGet, attachmentURL, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Should make download request: %v", err)
+	}
+	downloaded, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !bytes.Equal(downloaded, logContent) {
+		t.Errorf("Expected the uploaded content, got %d %q", resp.StatusCode, downloaded)
+	}
+	if disposition := resp.Header.Get("Content-Disposition"); disposition != `attachment; filename=crash.log` {
+		t.Errorf("Expected the file to be downloaded as an attachment, got %q", disposition)
+	}
+
+	if status := doJSON(t, http.MethodDelete, attachmentURL, otherToken, nil, nil); status != http.StatusNotFound {
+		t.Errorf("Deleting another user's attachment should return 404, got %d", status)
+	}
+	if status := doJSON(t, http.MethodDelete, attachmentURL, token, nil, nil); status != http.StatusOK {
+		t.Fatalf("Deleting should return 200, got %d", status)
+	}
+	if status := doJSON(t, http.MethodGet, attachmentURL, token, nil, nil); status != http.StatusNotFound {
+		t.Errorf("A deleted attachment should return 404, got %d", status)
+	}
+}
+
+func TestTaskAssignees(t *testing.T) {
+	server, _, cleanup := setupTestAPI(t)
+	defer cleanup()
+
+	token := registerAndLogin(t, server.URL, "lead")
+	devToken := registerAndLogin(t, server.URL, "developer")
+	watcherToken := registerAndLogin(t, server.URL, "stakeholder")
+
+	var created struct {
+		Data TaskResponse `json:"data"`
+	}
+	status := doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks", token, map[string]interface{}{
+		"title":    "Migrate billing",
+		"priority": 4,
+		"assignees": []map[string]string{
+			{"username": "developer", "role": "assignee"},
+			{"username": "stakeholder", "role": "watcher"},
+		},
+	}, &created)
+	if status != http.StatusCreated {
+		t.Fatalf("Task creation should return 201, got %d", status)
+	}
+	if len(created.Data.Assignees) != 2 || created.Data.Assignees[0].Username != "developer" || created.Data.Assignees[0].Role != "assignee" {
+		t.Errorf("Expected the assignees in the response, got %+v", created.Data.Assignees)
+	}
+
+	if status := doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks", token, map[string]interface{}{
+		"title":     "Bad role",
+		"priority":  1,
+		"assignees": []map[string]string{{"username": "developer", "role": "boss"}},
+	}, nil); status != http.StatusBadRequest {
+		t.Errorf("An unknown role should return 400, got %d", status)
+	}
+
+	taskURL := fmt.Sprintf("%s/api/v1/tasks/%d", server.URL, created.Data.ID)
+
+	var assigned struct {
+		Data []TaskResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodGet, server.URL+"/api/v1/tasks/assigned?role=assignee", devToken, nil, &assigned); status != http.StatusOK || len(assigned.Data) != 1 {
+		t.Fatalf("Expected one assigned task, got %d %+v", status, assigned.Data)
+	}
+	if status := doJSON(t, http.MethodGet, server.URL+"/api/v1/tasks/assigned?role=boss", devToken, nil, nil); status != http.StatusBadRequest {
+		t.Errorf("An unknown role filter should return 400, got %d", status)
+	}
+
+	if status := doJSON(t, http.MethodGet, taskURL, watcherToken, nil, nil); status != http.StatusOK {
+		t.Errorf("A watcher should see the task, got %d", status)
+	}
+	if status := doJSON(t, http.MethodPut, taskURL+"/status", devToken, map[string]int{"status": 1}, nil); status != http.StatusOK {
+		t.Errorf("The assignee should change the status, got %d", status)
+	}
+	if status := doJSON(t, http.MethodPut, taskURL+"/status", watcherToken, map[string]int{"status": 2}, nil); status != http.StatusForbidden {
+		t.Errorf("A watcher changing the status should return 403, got %d", status)
+	}
+	if status := doJSON(t, http.MethodPatch, taskURL, devToken, map[string]interface{}{"assignees": nil}, nil); status != http.StatusNotFound {
+		t.Errorf("Only the owner should change the assignees, got %d", status)
+	}
+
+	var patched struct {
+		Data TaskResponse `json:"data"`
+	}
+	status = doJSON(t, http.MethodPatch, taskURL, token, map[string]interface{}{
+		"title":     "Migrate billing to v2",
+		"assignees": []map[string]string{{"username": "developer", "role": "reviewer"}},
+	}, &patched)
+	if status != http.StatusOK {
+		t.Fatalf("Patching should return 200, got %d", status)
+	}
+	if patched.Data.Title != "Migrate billing to v2" || len(patched.Data.Assignees) != 1 || patched.Data.Assignees[0].Role != "reviewer" {
+		t.Errorf("Expected the new title and a single reviewer, got %+v", patched.Data)
+	}
+	if status := doJSON(t, http.MethodGet, taskURL, watcherToken, nil, nil); status != http.StatusNotFound {
+		t.Errorf("A removed watcher should no longer see the task, got %d", status)
+	}
+}
+
+func TestProjects(t *testing.T) {
+	server, _, cleanup := setupTestAPI(t)
+	defer cleanup()
+
+	token := registerAndLogin(t, server.URL, "maintainer")
+	memberToken := registerAndLogin(t, server.URL, "contributor")
+	otherToken := registerAndLogin(t, server.URL, "stranger")
+
+	var project struct {
+		Data ProjectResponse `json:"data"`
+	}
+	status := doJSON(t, http.MethodPost, server.URL+"/api/v1/projects", token, map[string]interface{}{
+		"name":     "Docs",
+		"settings": map[string]interface{}{"default_priority": 3},
+	}, &project)
+	if status != http.StatusCreated {
+		t.Fatalf("Project creation should return 201, got %d", status)
+	}
+	if project.Data.Settings.DefaultPriority != 3 || len(project.Data.Members) != 1 {
+		t.Errorf("Expected the settings and the owner in the response, got %+v", project.Data)
+	}
+
+	projectURL := fmt.Sprintf("%s/api/v1/projects/%d", server.URL, project.Data.ID)
+	if status := doJSON(t, http.MethodGet, projectURL, otherToken, nil, nil); status != http.StatusNotFound {
+		t.Errorf("Another user's project should return 404, got %d", status)
+	}
+	if status := doJSON(t, http.MethodPut, projectURL+"/members", token, map[string]string{"username": "contributor", "role": "member"}, nil); status != http.StatusOK {
+		t.Fatalf("Adding a member should return 200, got %d", status)
+	}
+	if status := doJSON(t, http.MethodPut, projectURL, memberToken, map[string]string{"name": "Handbook"}, nil); status != http.StatusForbidden {
+		t.Errorf("A member updating the project should return 403, got %d", status)
+	}
+
+	// Categories with the same name live side by side in different scopes
+	scoped := map[string]interface{}{"name": "Guides", "project_id": project.Data.ID}
+	if status := doJSON(t, http.MethodPost, server.URL+"/api/v1/categories", token, map[string]string{"name": "Guides"}, nil); status != http.StatusCreated {
+		t.Fatalf("Global category creation should return 201, got %d", status)
+	}
+	if status := doJSON(t, http.MethodPost, server.URL+"/api/v1/categories", memberToken, scoped, nil); status != http.StatusCreated {
+		t.Fatalf("Project category creation should return 201, got %d", status)
+	}
+	if status := doJSON(t, http.MethodPost, server.URL+"/api/v1/categories", otherToken, scoped, nil); status != http.StatusNotFound {
+		t.Errorf("Creating a category in another user's project should return 404, got %d", status)
+	}
+	var categories struct {
+		Data []CategoryResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodGet, fmt.Sprintf("%s/api/v1/categories?project_id=%d", server.URL, project.Data.ID), token, nil, &categories); status != http.StatusOK || len(categories.Data) != 1 {
+		t.Errorf("Expected one project category, got %d %+v", status, categories.Data)
+	}
+
+	// Tasks created in the project take its default priority
+	var created struct {
+		Data TaskResponse `json:"data"`
+	}
+	status = doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks", token, map[string]interface{}{
+		"title":      "Write the quick start",
+		"project_id": project.Data.ID,
+	}, &created)
+	if status != http.StatusCreated {
+		t.Fatalf("Task creation should return 201, got %d", status)
+	}
+	if created.Data.Priority != 3 || created.Data.ProjectID == nil || *created.Data.ProjectID != project.Data.ID {
+		t.Errorf("Expected a priority 3 task in the project, got %+v", created.Data)
+	}
+	if status := doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks", token, map[string]string{"title": "No priority"}, nil); status != http.StatusBadRequest {
+		t.Errorf("A task outside a project without priority should return 400, got %d", status)
+	}
+
+	var tasks struct {
+		Data []TaskResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodGet, fmt.Sprintf("%s/api/v1/tasks?project_id=%d", server.URL, project.Data.ID), memberToken, nil, &tasks); status != http.StatusOK || len(tasks.Data) != 1 {
+		t.Errorf("Expected the member to list the project task, got %d %+v", status, tasks.Data)
+	}
+	if status := doJSON(t, http.MethodGet, fmt.Sprintf("%s/api/v1/tasks?project_id=%d", server.URL, project.Data.ID), otherToken, nil, nil); status != http.StatusNotFound {
+		t.Errorf("Listing another user's project should return 404, got %d", status)
+	}
+
+	var found struct {
+		Data []TaskResponse `json:"data"`
+	}
+	status = doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks/search", memberToken, map[string]interface{}{
+		"query":      "quick",
+		"project_id": project.Data.ID,
+		"page":       1,
+		"page_size":  10,
+	}, &found)
+	if status != http.StatusOK || len(found.Data) != 1 {
+		t.Errorf("Expected the member to find the project task, got %d %+v", status, found.Data)
+	}
+
+	var stats struct {
+		Data StatisticsResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodGet, fmt.Sprintf("%s/api/v1/statistics?project_id=%d", server.URL, project.Data.ID), memberToken, nil, &stats); status != http.StatusOK {
+		t.Fatalf("Project statistics should return 200, got %d", status)
+	}
+	if stats.Data.TotalTasks != 1 || stats.Data.PendingTasks != 1 || stats.Data.TotalCategories != 1 {
+		t.Errorf("Expected one pending task and one category, got %+v", stats.Data)
+	}
+
+	taskURL := fmt.Sprintf("%s/api/v1/tasks/%d", server.URL, created.Data.ID)
+	if status := doJSON(t, http.MethodPut, taskURL+"/status", memberToken, map[string]int{"status": 1}, nil); status != http.StatusOK {
+		t.Errorf("A member should change the status, got %d", status)
+	}
+	if status := doJSON(t, http.MethodDelete, projectURL, token, nil, nil); status != http.StatusConflict {
+		t.Errorf("Deleting a project with tasks should return 409, got %d", status)
+	}
+}
+
+func TestBoards(t *testing.T) {
+	server, _, cleanup := setupTestAPI(t)
+	defer cleanup()
+
+	token := registerAndLogin(t, server.URL, "lead")
+	memberToken := registerAndLogin(t, server.URL, "teammate")
+	otherToken := registerAndLogin(t, server.URL, "visitor")
+
+	var project struct {
+		Data ProjectResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodPost, server.URL+"/api/v1/projects", token, map[string]string{"name": "Release"}, &project); status != http.StatusCreated {
+		t.Fatalf("Project creation should return 201, got %d", status)
+	}
+	projectURL := fmt.Sprintf("%s/api/v1/projects/%d", server.URL, project.Data.ID)
+	if status := doJSON(t, http.MethodPut, projectURL+"/members", token, map[string]string{"username": "teammate", "role": "member"}, nil); status != http.StatusOK {
+		t.Fatalf("Adding a member should return 200, got %d", status)
+	}
+
+	var taskIDs []int
+	for _, title := range []string{"Changelog", "Tag release"} {
+		var created struct {
+			Data TaskResponse `json:"data"`
+		}
+		status := doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks", token, map[string]interface{}{
+			"title":      title,
+			"project_id": project.Data.ID,
+		}, &created)
+		if status != http.StatusCreated {
+			t.Fatalf("Task creation should return 201, got %d", status)
+		}
+		taskIDs = append(taskIDs, created.Data.ID)
+	}
+
+	var board struct {
+		Data BoardResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodPost, server.URL+"/api/v1/boards", token, map[string]interface{}{
+		"name":    "Broken",
+		"columns": []map[string]interface{}{{"name": "Nowhere"}},
+	}, nil); status != http.StatusBadRequest {
+		t.Errorf("A column without status or tag should return 400, got %d", status)
+	}
+	status := doJSON(t, http.MethodPost, server.URL+"/api/v1/boards", token, map[string]interface{}{
+		"name":       "Release board",
+		"project_id": project.Data.ID,
+		"columns": []map[string]interface{}{
+			{"name": "Todo", "status": 0},
+			{"name": "Doing", "status": 1, "wip_limit": 1},
+			{"name": "Done", "status": 2},
+		},
+	}, &board)
+	if status != http.StatusCreated {
+		t.Fatalf("Board creation should return 201, got %d", status)
+	}
+	if len(board.Data.Columns) != 3 || len(board.Data.Columns[0].Cards) != 2 {
+		t.Fatalf("Expected the project tasks in the first column, got %+v", board.Data)
+	}
+	doing := board.Data.Columns[1].ID
+
+	// Members fetch the whole board and move its cards
+	boardURL := fmt.Sprintf("%s/api/v1/boards/%d", server.URL, board.Data.ID)
+	if status := doJSON(t, http.MethodGet, boardURL, memberToken, nil, &board); status != http.StatusOK {
+		t.Fatalf("A member reading the board should return 200, got %d", status)
+	}
+	moveURL := fmt.Sprintf("%s/cards/%d/move", boardURL, taskIDs[1])
+	if status := doJSON(t, http.MethodPost, moveURL, memberToken, map[string]int{"column_id": doing, "position": 0}, &board); status != http.StatusOK {
+		t.Fatalf("Moving a card should return 200, got %d", status)
+	}
+	if cards := board.Data.Columns[1].Cards; len(cards) != 1 || cards[0].ID != taskIDs[1] || cards[0].Status != 1 {
+		t.Errorf("Expected the task in progress in the second column, got %+v", cards)
+	}
+	moveURL = fmt.Sprintf("%s/cards/%d/move", boardURL, taskIDs[0])
+	if status := doJSON(t, http.MethodPost, moveURL, memberToken, map[string]int{"column_id": doing, "position": 0}, nil); status != http.StatusConflict {
+		t.Errorf("Moving past the work-in-progress limit should return 409, got %d", status)
+	}
+
+	if status := doJSON(t, http.MethodGet, boardURL, otherToken, nil, nil); status != http.StatusNotFound {
+		t.Errorf("Another user's board should return 404, got %d", status)
+	}
+	if status := doJSON(t, http.MethodDelete, boardURL, memberToken, nil, nil); status != http.StatusForbidden {
+		t.Errorf("A member deleting the board should return 403, got %d", status)
+	}
+
+	var boards struct {
+		Data []BoardResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodGet, server.URL+"/api/v1/boards", memberToken, nil, &boards); status != http.StatusOK || len(boards.Data) != 1 {
+		t.Errorf("Expected the project board in the member's boards, got %d %+v", status, boards.Data)
+	}
+	if status := doJSON(t, http.MethodDelete, boardURL, token, nil, nil); status != http.StatusOK {
+		t.Errorf("Deleting the board should return 200, got %d", status)
+	}
+}
+
+func TestSprints(t *testing.T) {
+	server, _, cleanup := setupTestAPI(t)
+	defer cleanup()
+
+	token := registerAndLogin(t, server.URL, "lead")
+	memberToken := registerAndLogin(t, server.URL, "teammate")
+	otherToken := registerAndLogin(t, server.URL, "visitor")
+
+	var project struct {
+		Data ProjectResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodPost, server.URL+"/api/v1/projects", token, map[string]string{"name": "Release"}, &project); status != http.StatusCreated {
+		t.Fatalf("Project creation should return 201, got %d", status)
+	}
+	projectURL := fmt.Sprintf("%s/api/v1/projects/%d", server.URL, project.Data.ID)
+	if status := doJSON(t, http.MethodPut, projectURL+"/members", token, map[string]string{"username": "teammate", "role": "member"}, nil); status != http.StatusOK {
+		t.Fatalf("Adding a member should return 200, got %d", status)
+	}
+
+	var created struct {
+		Data TaskResponse `json:"data"`
+	}
+	status := doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks", token, map[string]interface{}{
+		"title":      "Changelog",
+		"project_id": project.Data.ID,
+	}, &created)
+	if status != http.StatusCreated {
+		t.Fatalf("Task creation should return 201, got %d", status)
+	}
+
+	today := time.Now().UTC()
+	if status := doJSON(t, http.MethodPost, server.URL+"/api/v1/sprints", token, map[string]interface{}{
+		"name":       "Backwards",
+		"start_date": today.Format("2006-01-02"),
+		"end_date":   "yesterday",
+	}, nil); status != http.StatusBadRequest {
+		t.Errorf("Invalid sprint dates should return 400, got %d", status)
+	}
+
+	var sprint struct {
+		Data SprintResponse `json:"data"`
+	}
+	status = doJSON(t, http.MethodPost, server.URL+"/api/v1/sprints", token, map[string]interface{}{
+		"name":       "Sprint 1",
+		"goal":       "Ship it",
+		"start_date": today.AddDate(0, 0, -1).Format("2006-01-02"),
+		"end_date":   today.AddDate(0, 0, 12).Format("2006-01-02"),
+		"project_id": project.Data.ID,
+	}, &sprint)
+	if status != http.StatusCreated {
+		t.Fatalf("Sprint creation should return 201, got %d", status)
+	}
+
+	// Members plan project tasks into the sprint
+	sprintURL := fmt.Sprintf("%s/api/v1/sprints/%d", server.URL, sprint.Data.ID)
+	if status := doJSON(t, http.MethodPost, sprintURL+"/tasks", memberToken, map[string]int{"task_id": created.Data.ID}, &sprint); status != http.StatusOK {
+		t.Fatalf("Planning a task should return 200, got %d", status)
+	}
+	if len(sprint.Data.Tasks) != 1 || sprint.Data.Tasks[0].SprintID == nil || *sprint.Data.Tasks[0].SprintID != sprint.Data.ID {
+		t.Errorf("Expected the task in the sprint, got %+v", sprint.Data.Tasks)
+	}
+
+	var report struct {
+		Data SprintReportResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodGet, sprintURL+"/report?unit=tasks", memberToken, nil, &report); status != http.StatusOK {
+		t.Fatalf("Reading the report should return 200, got %d", status)
+	}
+	if n := len(report.Data.Burndown); n != 2 || report.Data.Burndown[n-1].Remaining != 1 {
+		t.Errorf("Expected one task remaining today, got %+v", report.Data.Burndown)
+	}
+	if status := doJSON(t, http.MethodGet, sprintURL+"/report?unit=points", token, nil, nil); status != http.StatusBadRequest {
+		t.Errorf("An unknown unit should return 400, got %d", status)
+	}
+	if status := doJSON(t, http.MethodGet, sprintURL, otherToken, nil, nil); status != http.StatusNotFound {
+		t.Errorf("Another project's sprint should return 404, got %d", status)
+	}
+
+	// Only the project owner closes the sprint, once
+	if status := doJSON(t, http.MethodPost, sprintURL+"/close", memberToken, nil, nil); status != http.StatusForbidden {
+		t.Errorf("A member closing the sprint should return 403, got %d", status)
+	}
+	var closed struct {
+		Data SprintResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodPost, sprintURL+"/close", token, nil, &closed); status != http.StatusOK {
+		t.Fatalf("Closing the sprint should return 200, got %d", status)
+	}
+	if closed.Data.ClosedAt == nil || len(closed.Data.Tasks) != 0 {
+		t.Errorf("Expected a closed sprint with its unfinished task back in the backlog, got %+v", closed.Data)
+	}
+	if status := doJSON(t, http.MethodPost, sprintURL+"/close", token, nil, nil); status != http.StatusConflict {
+		t.Errorf("Closing a closed sprint should return 409, got %d", status)
+	}
+
+	var velocity struct {
+		Data VelocityResponse `json:"data"`
+	}
+	velocityURL := fmt.Sprintf("%s/api/v1/sprints/velocity?project_id=%d", server.URL, project.Data.ID)
+	if status := doJSON(t, http.MethodGet, velocityURL, memberToken, nil, &velocity); status != http.StatusOK {
+		t.Fatalf("Reading the velocity should return 200, got %d", status)
+	}
+	if len(velocity.Data.Sprints) != 1 || velocity.Data.Sprints[0].SprintID != sprint.Data.ID || velocity.Data.Sprints[0].Completed != 0 {
+		t.Errorf("Expected the closed sprint with nothing completed, got %+v", velocity.Data)
+	}
+}
+
+func TestCustomFields(t *testing.T) {
+	server, _, cleanup := setupTestAPI(t)
+	defer cleanup()
+
+	token := registerAndLogin(t, server.URL, "lead")
+	memberToken := registerAndLogin(t, server.URL, "teammate")
+	otherToken := registerAndLogin(t, server.URL, "visitor")
+
+	var project struct {
+		Data ProjectResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodPost, server.URL+"/api/v1/projects", token, map[string]string{"name": "Release"}, &project); status != http.StatusCreated {
+		t.Fatalf("Project creation should return 201, got %d", status)
+	}
+	projectURL := fmt.Sprintf("%s/api/v1/projects/%d", server.URL, project.Data.ID)
+	if status := doJSON(t, http.MethodPut, projectURL+"/members", token, map[string]string{"username": "teammate", "role": "member"}, nil); status != http.StatusOK {
+		t.Fatalf("Adding a member should return 200, got %d", status)
+	}
+
+	fieldsURL := server.URL + "/api/v1/custom-fields"
+	severityField := map[string]interface{}{
+		"name":       "Severity",
+		"type":       "enum",
+		"options":    []string{"low", "medium", "high"},
+		"project_id": project.Data.ID,
+	}
+	if status := doJSON(t, http.MethodPost, fieldsURL, token, map[string]interface{}{"name": "Severity", "type": "enum", "project_id": project.Data.ID}, nil); status != http.StatusBadRequest {
+		t.Errorf("An enum field without options should return 400, got %d", status)
+	}
+	if status := doJSON(t, http.MethodPost, fieldsURL, memberToken, severityField, nil); status != http.StatusForbidden {
+		t.Errorf("Members defining project fields should return 403, got %d", status)
+	}
+	if status := doJSON(t, http.MethodPost, fieldsURL, otherToken, severityField, nil); status != http.StatusNotFound {
+		t.Errorf("Outsiders defining project fields should return 404, got %d", status)
+	}
+
+	var severity struct {
+		Data CustomFieldResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodPost, fieldsURL, token, severityField, &severity); status != http.StatusCreated {
+		t.Fatalf("Field creation should return 201, got %d", status)
+	}
+	var due struct {
+		Data CustomFieldResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodPost, fieldsURL, token, map[string]interface{}{"name": "Launch date", "type": "date", "project_id": project.Data.ID}, &due); status != http.StatusCreated {
+		t.Fatalf("Field creation should return 201, got %d", status)
+	}
+
+	var fields struct {
+		Data []CustomFieldResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodGet, fmt.Sprintf("%s?project_id=%d", fieldsURL, project.Data.ID), memberToken, nil, &fields); status != http.StatusOK {
+		t.Fatalf("Listing fields should return 200, got %d", status)
+	}
+	if len(fields.Data) != 2 || fields.Data[0].Name != "Launch date" || fields.Data[1].Name != "Severity" {
+		t.Errorf("Expected both fields by name, got %+v", fields.Data)
+	}
+	if status := doJSON(t, http.MethodGet, fmt.Sprintf("%s/%d", fieldsURL, severity.Data.ID), otherToken, nil, nil); status != http.StatusNotFound {
+		t.Errorf("Outsiders reading project fields should return 404, got %d", status)
+	}
+
+	taskIDs := make(map[string]int)
+	for title, values := range map[string][]map[string]interface{}{
+		"Minor": {{"field_id": severity.Data.ID, "value": "low"}, {"field_id": due.Data.ID, "value": "2024-05-01"}},
+		"Major": {{"field_id": severity.Data.ID, "value": "High"}, {"field_id": due.Data.ID, "value": "2024-03-01"}},
+		"Blank": nil,
+	} {
+		var created struct {
+			Data TaskResponse `json:"data"`
+		}
+		if status := doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks", token, map[string]interface{}{"title": title, "project_id": project.Data.ID}, &created); status != http.StatusCreated {
+			t.Fatalf("Task creation should return 201, got %d", status)
+		}
+		taskIDs[title] = created.Data.ID
+		if values == nil {
+			continue
+		}
+		if status := doJSON(t, http.MethodPut, fmt.Sprintf("%s/api/v1/tasks/%d/custom-fields", server.URL, created.Data.ID), memberToken, map[string]interface{}{"values": values}, nil); status != http.StatusOK {
+			t.Fatalf("Setting values should return 200, got %d", status)
+		}
+	}
+
+	majorURL := fmt.Sprintf("%s/api/v1/tasks/%d", server.URL, taskIDs["Major"])
+	if status := doJSON(t, http.MethodPut, majorURL+"/custom-fields", token, map[string]interface{}{"values": []map[string]interface{}{{"field_id": severity.Data.ID, "value": "urgent"}}}, nil); status != http.StatusBadRequest {
+		t.Errorf("An invalid enum value should return 400, got %d", status)
+	}
+	if status := doJSON(t, http.MethodPut, majorURL+"/custom-fields", otherToken, map[string]interface{}{"values": []map[string]interface{}{{"field_id": severity.Data.ID, "value": "low"}}}, nil); status != http.StatusNotFound {
+		t.Errorf("Outsiders setting values should return 404, got %d", status)
+	}
+
+	var major struct {
+		Data TaskResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodGet, majorURL, memberToken, nil, &major); status != http.StatusOK {
+		t.Fatalf("Getting the task should return 200, got %d", status)
+	}
+	if len(major.Data.CustomFields) != 2 || major.Data.CustomFields[1].Name != "Severity" || major.Data.CustomFields[1].Value != "high" {
+		t.Errorf("Expected the task with its normalized values, got %+v", major.Data.CustomFields)
+	}
+
+	search := func(body map[string]interface{}) []string {
+		t.Helper()
+		body["project_id"] = project.Data.ID
+		body["page"] = 1
+		body["page_size"] = 10
+		var result struct {
+			Data []TaskResponse `json:"data"`
+		}
+		if status := doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks/search", memberToken, body, &result); status != http.StatusOK {
+			t.Fatalf("Search should return 200, got %d", status)
+		}
+		titles := make([]string, len(result.Data))
+		for i, task := range result.Data {
+			titles[i] = task.Title
+		}
+		return titles
+	}
+
+	if titles := search(map[string]interface{}{"custom_fields": []map[string]interface{}{{"field_id": severity.Data.ID, "value": "HIGH"}}}); len(titles) != 1 || titles[0] != "Major" {
+		t.Errorf("Expected the enum filter to match Major, got %v", titles)
+	}
+	if titles := search(map[string]interface{}{"custom_fields": []map[string]interface{}{{"field_id": due.Data.ID, "min": "2024-04-01"}}}); len(titles) != 1 || titles[0] != "Minor" {
+		t.Errorf("Expected the date range to match Minor, got %v", titles)
+	}
+	sortKey := fmt.Sprintf("custom:%d", severity.Data.ID)
+	if titles := search(map[string]interface{}{"sort_by": sortKey, "sort_order": "asc"}); len(titles) != 3 || titles[0] != "Minor" || titles[1] != "Major" || titles[2] != "Blank" {
+		t.Errorf("Expected tasks by severity with the blank one last, got %v", titles)
+	}
+	if status := doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks/search", memberToken, map[string]interface{}{
+		"project_id":    project.Data.ID,
+		"page":          1,
+		"page_size":     10,
+		"custom_fields": []map[string]interface{}{{"field_id": due.Data.ID, "value": "soon"}},
+	}, nil); status != http.StatusBadRequest {
+		t.Errorf("An invalid filter value should return 400, got %d", status)
+	}
+
+	if status := doJSON(t, http.MethodDelete, fmt.Sprintf("%s/%d", fieldsURL, severity.Data.ID), memberToken, nil, nil); status != http.StatusForbidden {
+		t.Errorf("Members deleting project fields should return 403, got %d", status)
+	}
+	if status := doJSON(t, http.MethodDelete, fmt.Sprintf("%s/%d", fieldsURL, severity.Data.ID), token, nil, nil); status != http.StatusOK {
+		t.Errorf("Field deletion should return 200, got %d", status)
+	}
+	var after struct {
+		Data TaskResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodGet, majorURL, token, nil, &after); status != http.StatusOK || len(after.Data.CustomFields) != 1 {
+		t.Errorf("Expected the deleted field's value to be gone, got %d %+v", status, after.Data.CustomFields)
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	server, _, cleanup := setupTestAPI(t)
+	defer cleanup()
+
+	token := registerAndLogin(t, server.URL, "planner")
+	otherToken := registerAndLogin(t, server.URL, "visitor")
+
+	templatesURL := server.URL + "/api/v1/templates"
+	release := map[string]interface{}{
+		"name": "Release",
+		"tasks": []map[string]interface{}{
+			{"title": "Release", "priority": 3, "due_offset_days": 7},
+			{"title": "Write notes", "priority": 2, "parent": 0, "checklist": []string{"Draft", "Review"}},
+			{"title": "Publish", "priority": 4, "due_offset_days": 7, "depends_on": []int{1}},
+		},
+	}
+	if status := doJSON(t, http.MethodPost, templatesURL, token, map[string]interface{}{"name": "Empty", "tasks": []interface{}{}}, nil); status != http.StatusBadRequest {
+		t.Errorf("A template without tasks should return 400, got %d", status)
+	}
+	if status := doJSON(t, http.MethodPost, templatesURL, token, map[string]interface{}{
+		"name":  "Backwards",
+		"tasks": []map[string]interface{}{{"title": "First", "priority": 1, "depends_on": []int{1}}, {"title": "Second", "priority": 1}},
+	}, nil); status != http.StatusBadRequest {
+		t.Errorf("A dependency on a later task should return 400, got %d", status)
+	}
+
+	var template struct {
+		Data TemplateResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodPost, templatesURL, token, release, &template); status != http.StatusCreated {
+		t.Fatalf("Template creation should return 201, got %d", status)
+	}
+	if len(template.Data.Tasks) != 3 || template.Data.Tasks[1].Parent == nil || len(template.Data.Tasks[2].DependsOn) != 1 {
+		t.Errorf("Expected the template with its structure, got %+v", template.Data)
+	}
+
+	templateURL := fmt.Sprintf("%s/%d", templatesURL, template.Data.ID)
+	if status := doJSON(t, http.MethodGet, templateURL, otherToken, nil, nil); status != http.StatusNotFound {
+		t.Errorf("Reading another user's template should return 404, got %d", status)
+	}
+	var templates struct {
+		Data []TemplateResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodGet, templatesURL, token, nil, &templates); status != http.StatusOK || len(templates.Data) != 1 {
+		t.Errorf("Listing templates should return the template, got %d %+v", status, templates.Data)
+	}
+
+	if status := doJSON(t, http.MethodPost, templateURL+"/instantiate", token, map[string]string{"start_date": "03/01/2024"}, nil); status != http.StatusBadRequest {
+		t.Errorf("An invalid start date should return 400, got %d", status)
+	}
+	var created struct {
+		Data []TaskResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodPost, templateURL+"/instantiate", token, map[string]string{"start_date": "2024-03-01"}, &created); status != http.StatusCreated {
+		t.Fatalf("Instantiation should return 201, got %d", status)
+	}
+	if len(created.Data) != 3 || created.Data[0].DueDate == nil || created.Data[0].DueDate.Format("2006-01-02") != "2024-03-08" {
+		t.Fatalf("Expected three tasks with due dates a week after the start, got %+v", created.Data)
+	}
+	if created.Data[1].ParentID == nil || *created.Data[1].ParentID != created.Data[0].ID {
+		t.Errorf("Expected the notes to be a subtask, got %+v", created.Data[1])
+	}
+	if status := doJSON(t, http.MethodPost, templateURL+"/instantiate", token, nil, nil); status != http.StatusCreated {
+		t.Errorf("Instantiation without a body should return 201, got %d", status)
+	}
+
+	// The checklist of the notes came from the template
+	taskURL := fmt.Sprintf("%s/api/v1/tasks/%d", server.URL, created.Data[1].ID)
+	var notes struct {
+		Data TaskResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodGet, taskURL, token, nil, &notes); status != http.StatusOK {
+		t.Fatalf("Getting the task should return 200, got %d", status)
+	}
+	if len(notes.Data.Checklist) != 2 || notes.Data.Checklist[0].Text != "Draft" || notes.Data.Checklist[0].Done {
+		t.Fatalf("Expected the checklist of the template, got %+v", notes.Data.Checklist)
+	}
+
+	checklistURL := taskURL + "/checklist"
+	if status := doJSON(t, http.MethodPost, checklistURL, token, map[string]string{"text": "Publish"}, nil); status != http.StatusCreated {
+		t.Errorf("Adding a checklist item should return 201, got %d", status)
+	}
+	if status := doJSON(t, http.MethodPost, checklistURL, otherToken, map[string]string{"text": "Mine"}, nil); status != http.StatusNotFound {
+		t.Errorf("Changing another user's checklist should return 404, got %d", status)
+	}
+
+	itemURL := fmt.Sprintf("%s/%d", checklistURL, notes.Data.Checklist[0].ID)
+	var item struct {
+		Data ChecklistItemResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodPatch, itemURL, token, map[string]interface{}{"done": true, "position": 2}, &item); status != http.StatusOK {
+		t.Fatalf("Updating a checklist item should return 200, got %d", status)
+	}
+	if !item.Data.Done || item.Data.CompletedAt == nil || item.Data.Position != 2 {
+		t.Errorf("Expected a completed item at the end, got %+v", item.Data)
+	}
+	if status := doJSON(t, http.MethodPatch, itemURL, token, map[string]string{"text": " "}, nil); status != http.StatusBadRequest {
+		t.Errorf("An empty item should return 400, got %d", status)
+	}
+
+	var checklist struct {
+		Data []ChecklistItemResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodGet, checklistURL, token, nil, &checklist); status != http.StatusOK {
+		t.Fatalf("Getting the checklist should return 200, got %d", status)
+	}
+	if len(checklist.Data) != 3 || checklist.Data[0].Text != "Review" || checklist.Data[2].Text != "Draft" {
+		t.Errorf("Expected the moved item last, got %+v", checklist.Data)
+	}
+
+	if status := doJSON(t, http.MethodDelete, itemURL, token, nil, nil); status != http.StatusOK {
+		t.Errorf("Deleting a checklist item should return 200, got %d", status)
+	}
+	if status := doJSON(t, http.MethodDelete, itemURL, token, nil, nil); status != http.StatusNotFound {
+		t.Errorf("Deleting a deleted checklist item should return 404, got %d", status)
+	}
+
+	if status := doJSON(t, http.MethodDelete, templateURL, otherToken, nil, nil); status != http.StatusNotFound {
+		t.Errorf("Deleting another user's template should return 404, got %d", status)
+	}
+	if status := doJSON(t, http.MethodDelete, templateURL, token, nil, nil); status != http.StatusOK {
+		t.Errorf("Deleting the template should return 200, got %d", status)
+	}
+}
+
+func TestBulk(t *testing.T) {
+	server, _, cleanup := setupTestAPI(t)
+	defer cleanup()
+
+	token := registerAndLogin(t, server.URL, "closer")
+	otherToken := registerAndLogin(t, server.URL, "bystander")
+
+	tasksURL := server.URL + "/api/v1/tasks"
+	var ids []int
+	for _, title := range []string{"Sprint task one", "Sprint task two", "Backlog idea"} {
+		var created struct {
+			Data TaskResponse `json:"data"`
+		}
+		if status := doJSON(t, http.MethodPost, tasksURL, token, map[string]interface{}{"title": title, "priority": 2}, &created); status != http.StatusCreated {
+			t.Fatalf("Task creation should return 201, got %d", status)
+		}
+		ids = append(ids, created.Data.ID)
+	}
+	var foreign struct {
+		Data TaskResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodPost, tasksURL, otherToken, map[string]interface{}{"title": "Sprint task three", "priority": 2}, &foreign); status != http.StatusCreated {
+		t.Fatalf("Task creation should return 201, got %d", status)
+	}
+
+	bulkURL := tasksURL + "/bulk"
+	if status := doJSON(t, http.MethodPost, bulkURL, token, map[string]interface{}{"action": "rename", "ids": ids}, nil); status != http.StatusBadRequest {
+		t.Errorf("An unknown action should return 400, got %d", status)
+	}
+	if status := doJSON(t, http.MethodPost, bulkURL, token, map[string]interface{}{"action": "status", "ids": ids}, nil); status != http.StatusBadRequest {
+		t.Errorf("A status request without a status should return 400, got %d", status)
+	}
+
+	// Another user's task fails the whole request
+	if status := doJSON(t, http.MethodPost, bulkURL, token, map[string]interface{}{
+		"action": "status", "status": 2, "ids": []int{ids[0], foreign.Data.ID},
+	}, nil); status != http.StatusConflict {
+		t.Errorf("A failing task should return 409, got %d", status)
+	}
+
+	// The filter only matches the user's own tasks
+	var preview struct {
+		Data BulkResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodPost, bulkURL, token, map[string]interface{}{
+		"action": "status", "status": 2, "filter": map[string]string{"query": "Sprint"}, "dry_run": true,
+	}, &preview); status != http.StatusOK {
+		t.Fatalf("A dry run should return 200, got %d", status)
+	}
+	if !preview.Data.DryRun || preview.Data.Matched != 2 || len(preview.Data.Items) != 2 || !preview.Data.Items[0].Success {
+		t.Errorf("Expected a preview of the two sprint tasks, got %+v", preview.Data)
+	}
+
+	var loaded struct {
+		Data TaskResponse `json:"data"`
+	}
+	taskURL := fmt.Sprintf("%s/%d", tasksURL, ids[0])
+	if status := doJSON(t, http.MethodGet, taskURL, token, nil, &loaded); status != http.StatusOK || loaded.Data.Status != 0 {
+		t.Errorf("Expected the dry run to change nothing, got %d %+v", status, loaded.Data)
+	}
+
+	var partial struct {
+		Data BulkResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodPost, bulkURL, token, map[string]interface{}{
+		"action": "priority", "priority": 4, "ids": []int{ids[0], ids[1], foreign.Data.ID}, "partial": true,
+	}, &partial); status != http.StatusOK {
+		t.Fatalf("A partial request should return 200, got %d", status)
+	}
+	if partial.Data.Succeeded != 2 || partial.Data.Failed != 1 || len(partial.Data.Items) != 3 || partial.Data.Items[2].Error == "" {
+		t.Errorf("Expected two successes and one failure, got %+v", partial.Data)
+	}
+	if status := doJSON(t, http.MethodGet, taskURL, token, nil, &loaded); status != http.StatusOK || loaded.Data.Priority != 4 {
+		t.Errorf("Expected the task to be reprioritized, got %d %+v", status, loaded.Data)
+	}
+
+	var archived struct {
+		Data BulkResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodPost, bulkURL, token, map[string]interface{}{"action": "archive", "ids": ids[:2]}, &archived); status != http.StatusOK {
+		t.Fatalf("Archiving should return 200, got %d", status)
+	}
+	if archived.Data.Succeeded != 2 || archived.Data.Items != nil {
+		t.Errorf("Expected two archived tasks without items, got %+v", archived.Data)
+	}
+
+	if status := doJSON(t, http.MethodPost, bulkURL, token, map[string]interface{}{
+		"action": "delete", "filter": map[string]interface{}{"project_id": 999},
+	}, nil); status != http.StatusNotFound {
+		t.Errorf("Filtering on an unknown project should return 404, got %d", status)
+	}
+}
+
+func TestQuickAdd(t *testing.T) {
+	server, _, cleanup := setupTestAPI(t)
+	defer cleanup()
+
+	token := registerAndLogin(t, server.URL, "quickadder")
+	quickURL := server.URL + "/api/v1/tasks/quick"
+
+	var created struct {
+		Data TaskResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodPost, quickURL, token, map[string]interface{}{
+		"text": "Pay rent tomorrow 5pm !urgent #finance every month", "timezone": "Europe/Berlin",
+	}, &created); status != http.StatusCreated {
+		t.Fatalf("Quick add should return 201, got %d", status)
+	}
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	if created.Data.Title != "Pay rent" || created.Data.Priority != 4 || created.Data.Recurrence != "FREQ=MONTHLY" ||
+		len(created.Data.Tags) != 1 || created.Data.Tags[0].Name != "finance" ||
+		created.Data.DueDate == nil || created.Data.DueDate.In(berlin).Hour() != 17 {
+		t.Errorf("Expected the line to be parsed, got %+v", created.Data)
+	}
+
+	invalid := []map[string]interface{}{
+		{"text": ""},
+		{"text": "Pay !5"},
+		{"text": "Pay rent", "timezone": "Mars/Olympus"},
+		{"text": "Pay rent @nowhere"},
+	}
+	for _, body := range invalid {
+		if status := doJSON(t, http.MethodPost, quickURL, token, body, nil); status != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %v, got %d", body, status)
+		}
+	}
+	if status := doJSON(t, http.MethodPost, quickURL, token, map[string]interface{}{"text": "Pay rent", "project_id": 999}, nil); status != http.StatusNotFound {
+		t.Errorf("An unknown project should return 404, got %d", status)
+	}
+}
+
+func TestSnoozeTasks(t *testing.T) {
+	server, _, cleanup := setupTestAPI(t)
+	defer cleanup()
+
+	token := registerAndLogin(t, server.URL, "snoozer")
+
+	var created struct {
+		Data TaskResponse `json:"data"`
+	}
+	status := doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks", token, map[string]interface{}{
+		"title":    "Renew passport",
+		"priority": 2,
+	}, &created)
+	if status != http.StatusCreated {
+		t.Fatalf("Task creation should return 201, got %d", status)
+	}
+
+	snoozeURL := fmt.Sprintf("%s/api/v1/tasks/%d/snooze", server.URL, created.Data.ID)
+	var snoozed struct {
+		Data TaskResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodPost, snoozeURL, token, map[string]interface{}{"for": "3 days"}, &snoozed); status != http.StatusOK {
+		t.Fatalf("Snoozing should return 200, got %d", status)
+	}
+	if snoozed.Data.HiddenUntil == nil || snoozed.Data.HiddenUntil.Before(time.Now().Add(48*time.Hour)) {
+		t.Errorf("Expected the task to be hidden for about 3 days, got %v", snoozed.Data.HiddenUntil)
+	}
+
+	var list struct {
+		Data []TaskResponse `json:"data"`
+	}
+	doJSON(t, http.MethodGet, server.URL+"/api/v1/tasks", token, nil, &list)
+	if len(list.Data) != 0 {
+		t.Errorf("Expected snoozed tasks to be hidden, got %d tasks", len(list.Data))
+	}
+	doJSON(t, http.MethodGet, server.URL+"/api/v1/tasks?include_snoozed=true", token, nil, &list)
+	if len(list.Data) != 1 || list.Data[0].HiddenUntil == nil {
+		t.Errorf("Expected the snoozed task with include_snoozed, got %+v", list.Data)
+	}
+
+	invalid := []map[string]interface{}{
+		{},
+		{"for": "a while"},
+		{"until": time.Now().Add(-time.Hour).Format(time.RFC3339)},
+		{"until": time.Now().Add(time.Hour).Format(time.RFC3339), "for": "3 days"},
+		{"for": "3 days", "timezone": "Mars/Olympus"},
+	}
+	for _, body := range invalid {
+		if status := doJSON(t, http.MethodPost, snoozeURL, token, body, nil); status != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %v, got %d", body, status)
+		}
+	}
+
+	if status := doJSON(t, http.MethodDelete, snoozeURL, token, nil, nil); status != http.StatusOK {
+		t.Fatalf("Unsnoozing should return 200, got %d", status)
+	}
+	var awake struct {
+		Data []TaskResponse `json:"data"`
+	}
+	doJSON(t, http.MethodGet, server.URL+"/api/v1/tasks", token, nil, &awake)
+	if len(awake.Data) != 1 || awake.Data[0].HiddenUntil != nil {
+		t.Errorf("Expected the unsnoozed task to be listed, got %+v", awake.Data)
+	}
+
+	other := registerAndLogin(t, server.URL, "nosy")
+	if status := doJSON(t, http.MethodPost, snoozeURL, other, map[string]interface{}{"for": "1 day"}, nil); status != http.StatusNotFound {
+		t.Errorf("Snoozing another user's task should return 404, got %d", status)
+	}
+}
+
+func TestNextTasks(t *testing.T) {
+	server, _, cleanup := setupTestAPI(t)
+	defer cleanup()
+
+	token := registerAndLogin(t, server.URL, "planner")
+
+	for _, body := range []map[string]interface{}{
+		{"title": "Someday", "priority": 1},
+		{"title": "Fire", "priority": 4, "due_date": time.Now().Add(time.Hour).Format(time.RFC3339)},
+	} {
+		if status := doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks", token, body, nil); status != http.StatusCreated {
+			t.Fatalf("Task creation should return 201, got %d", status)
+		}
+	}
+
+	var next struct {
+		Data []NextTaskResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodGet, server.URL+"/api/v1/tasks/next?limit=1", token, nil, &next); status != http.StatusOK {
+		t.Fatalf("Next tasks should return 200, got %d", status)
+	}
+	if len(next.Data) != 1 || next.Data[0].Task.Title != "Fire" || next.Data[0].Score <= 0 {
+		t.Errorf("Expected the urgent task first, got %+v", next.Data)
+	}
+	if status := doJSON(t, http.MethodGet, server.URL+"/api/v1/tasks/next?limit=0", token, nil, nil); status != http.StatusBadRequest {
+		t.Errorf("A zero limit should return 400, got %d", status)
+	}
+
+	weightsURL := server.URL + "/api/v1/scoring-weights"
+	var weights struct {
+		Data map[string]float64 `json:"data"`
+	}
+	if status := doJSON(t, http.MethodPut, weightsURL, token, map[string]interface{}{
+		"weights": map[string]float64{"priority": 0, "due_date": 0, "staleness": 1},
+	}, &weights); status != http.StatusOK {
+		t.Fatalf("Setting weights should return 200, got %d", status)
+	}
+	if weights.Data["staleness"] != 1 || weights.Data["blocking"] != 2 {
+		t.Errorf("Expected the set weights over the defaults, got %v", weights.Data)
+	}
+
+	for _, body := range []map[string]interface{}{
+		{},
+		{"weights": map[string]float64{"luck": 1}},
+		{"weights": map[string]float64{"priority": -1}},
+	} {
+		if status := doJSON(t, http.MethodPut, weightsURL, token, body, nil); status != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %v, got %d", body, status)
+		}
+	}
+
+	var reset struct {
+		Data map[string]float64 `json:"data"`
+	}
+	if status := doJSON(t, http.MethodDelete, weightsURL, token, nil, nil); status != http.StatusOK {
+		t.Fatalf("Resetting weights should return 200, got %d", status)
+	}
+	doJSON(t, http.MethodGet, weightsURL, token, nil, &reset)
+	if reset.Data["priority"] != 3 {
+		t.Errorf("Expected the default weights after a reset, got %v", reset.Data)
+	}
+}
+
+func TestMoveTasks(t *testing.T) {
+	server, _, cleanup := setupTestAPI(t)
+	defer cleanup()
+
+	token := registerAndLogin(t, server.URL, "mover")
+
+	var ids []int
+	for _, title := range []string{"Write", "Review", "Publish"} {
+		var created struct {
+			Data TaskResponse `json:"data"`
+		}
+		status := doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks", token, map[string]interface{}{
+			"title":    title,
+			"priority": 2,
+		}, &created)
+		if status != http.StatusCreated {
+			t.Fatalf("Task creation should return 201, got %d", status)
+		}
+		ids = append(ids, created.Data.ID)
+	}
+
+	positionURL := func(id int) string {
+		return fmt.Sprintf("%s/api/v1/tasks/%d/position", server.URL, id)
+	}
+	var moved struct {
+		Data TaskPositionResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodPut, positionURL(ids[2]), token, map[string]interface{}{}, &moved); status != http.StatusOK {
+		t.Fatalf("Moving a task should return 200, got %d", status)
+	}
+	if moved.Data.TaskID != ids[2] || moved.Data.Key == "" {
+		t.Errorf("Expected the position of the moved task, got %+v", moved.Data)
+	}
+	for _, id := range []int{ids[0], ids[1]} {
+		if status := doJSON(t, http.MethodPut, positionURL(id), token, map[string]interface{}{"after_task_id": ids[2]}, nil); status != http.StatusOK {
+			t.Fatalf("Moving a task should return 200, got %d", status)
+		}
+	}
+
+	var list struct {
+		Data []TaskResponse `json:"data"`
+	}
+	search := map[string]interface{}{"sort_by": "position", "page": 1, "page_size": 10}
+	doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks/search", token, search, &list)
+	want := []int{ids[2], ids[1], ids[0]}
+	if len(list.Data) != len(want) {
+		t.Fatalf("Expected %d tasks, got %d", len(want), len(list.Data))
+	}
+	for i, task := range list.Data {
+		if task.ID != want[i] {
+			t.Errorf("Expected task %d at position %d, got %d", want[i], i, task.ID)
+		}
+	}
+
+	if status := doJSON(t, http.MethodPut, positionURL(ids[0]), token, map[string]interface{}{"after_task_id": ids[0]}, nil); status != http.StatusBadRequest {
+		t.Errorf("Placing a task after itself should return 400, got %d", status)
+	}
+	if status := doJSON(t, http.MethodPut, positionURL(9999), token, map[string]interface{}{}, nil); status != http.StatusNotFound {
+		t.Errorf("Moving a missing task should return 404, got %d", status)
+	}
+	other := registerAndLogin(t, server.URL, "bystander")
+	if status := doJSON(t, http.MethodPut, positionURL(ids[0]), other, map[string]interface{}{}, nil); status != http.StatusNotFound {
+		t.Errorf("Moving another user's task should return 404, got %d", status)
+	}
+}
+
+func TestUndoRedo(t *testing.T) {
+	server, _, cleanup := setupTestAPI(t)
+	defer cleanup()
+
+	token := registerAndLogin(t, server.URL, "undoer")
+	otherToken := registerAndLogin(t, server.URL, "bystander")
+
+	var created struct {
+		Data TaskResponse `json:"data"`
+	}
+	status := doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks", token, map[string]interface{}{
+		"title":    "Mistyped",
+		"priority": 2,
+	}, &created)
+	if status != http.StatusCreated {
+		t.Fatalf("Task creation should return 201, got %d", status)
+	}
+	taskURL := fmt.Sprintf("%s/api/v1/tasks/%d", server.URL, created.Data.ID)
+
+	if status := doJSON(t, http.MethodDelete, taskURL, token, nil, nil); status != http.StatusOK {
+		t.Fatalf("Deleting a task should return 200, got %d", status)
+	}
+
+	if status := doJSON(t, http.MethodPost, server.URL+"/api/v1/undo", otherToken, nil, nil); status != http.StatusConflict {
+		t.Errorf("Undo without changes should return 409, got %d", status)
+	}
+
+	var undone struct {
+		Data CommandResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodPost, server.URL+"/api/v1/undo", token, nil, &undone); status != http.StatusOK {
+		t.Fatalf("Undo should return 200, got %d", status)
+	}
+	if undone.Data.Kind != "delete" || undone.Data.TaskID != created.Data.ID || !undone.Data.Undone {
+		t.Errorf("Expected the delete to be undone, got %+v", undone.Data)
+	}
+	if status := doJSON(t, http.MethodGet, taskURL, token, nil, nil); status != http.StatusOK {
+		t.Errorf("Expected the task to be back, got %d", status)
+	}
+
+	var redone struct {
+		Data CommandResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodPost, server.URL+"/api/v1/redo", token, nil, &redone); status != http.StatusOK {
+		t.Fatalf("Redo should return 200, got %d", status)
+	}
+	if redone.Data.Kind != "delete" || redone.Data.Undone {
+		t.Errorf("Expected the delete to be redone, got %+v", redone.Data)
+	}
+	if status := doJSON(t, http.MethodGet, taskURL, token, nil, nil); status != http.StatusNotFound {
+		t.Errorf("Expected the task to be deleted again, got %d", status)
+	}
+	if status := doJSON(t, http.MethodPost, server.URL+"/api/v1/redo", token, nil, nil); status != http.StatusConflict {
+		t.Errorf("Redo without undone changes should return 409, got %d", status)
+	}
+}
+
+func TestTaskLinks(t *testing.T) {
+	server, _, cleanup := setupTestAPI(t)
+	defer cleanup()
+
+	token := registerAndLogin(t, server.URL, "linker")
+	otherToken := registerAndLogin(t, server.URL, "outsider")
+
+	var ids []int
+	for _, title := range []string{"Design", "Build"} {
+		var created struct {
+			Data TaskResponse `json:"data"`
+		}
+		status := doJSON(t, http.MethodPost, server.URL+"/api/v1/tasks", token, map[string]interface{}{
+			"title":    title,
+			"priority": 2,
+		}, &created)
+		if status != http.StatusCreated {
+			t.Fatalf("Task creation should return 201, got %d", status)
+		}
+		ids = append(ids, created.Data.ID)
+	}
+	linksURL := fmt.Sprintf("%s/api/v1/tasks/%d/links", server.URL, ids[1])
+
+	var created struct {
+		Data TaskLinkResponse `json:"data"`
+	}
+	status := doJSON(t, http.MethodPost, linksURL, token, map[string]interface{}{
+		"type":    "blocked_by",
+		"task_id": ids[0],
+	}, &created)
+	if status != http.StatusCreated {
+		t.Fatalf("Linking tasks should return 201, got %d", status)
+	}
+	if created.Data.Type != "blocks" || created.Data.Name != "blocked_by" || created.Data.LinkedTask == nil || created.Data.LinkedTask.ID != ids[0] {
+		t.Errorf("Expected Build to be blocked by Design, got %+v", created.Data)
+	}
+
+	// The other end sees the link under its inverse name
+	var links struct {
+		Data []TaskLinkResponse `json:"data"`
+	}
+	if status := doJSON(t, http.MethodGet, fmt.Sprintf("%s/api/v1/tasks/%d/links", server.URL, ids[0]), token, nil, &links); status != http.StatusOK {
+		t.Fatalf("Getting links should return 200, got %d", status)
+	}
+	if len(links.Data) != 1 || links.Data[0].Name != "blocks" || links.Data[0].LinkedTaskID != ids[1] {
+		t.Errorf("Expected Design to block Build, got %+v", links.Data)
+	}
+
+	status = doJSON(t, http.MethodPost, fmt.Sprintf("%s/api/v1/tasks/%d/links", server.URL, ids[0]), token, map[string]interface{}{
+		"type":    "blocked_by",
+		"task_id": ids[1],
+	}, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("A circular block should return 400, got %d", status)
+	}
+	status = doJSON(t, http.MethodPost, linksURL, token, map[string]interface{}{
+		"type":    "depends_on",
+		"task_id": ids[0],
+	}, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("An unknown link type should return 400, got %d", status)
+	}
+	if status := doJSON(t, http.MethodGet, linksURL, otherToken, nil, nil); status != http.StatusNotFound {
+		t.Errorf("Another user's task links should return 404, got %d", status)
+	}
+
+	deleteURL := fmt.Sprintf("%s/blocked_by/%d", linksURL, ids[0])
+	if status := doJSON(t, http.MethodDelete, deleteURL, token, nil, nil); status != http.StatusOK {
+		t.Fatalf("Unlinking tasks should return 200, got %d", status)
+	}
+	if status := doJSON(t, http.MethodDelete, deleteURL, token, nil, nil); status != http.StatusNotFound {
+		t.Errorf("Removing a missing link should return 404, got %d", status)
+	}
+}
@@ -1,36 +1,54 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"learn-go-capstone/internal/auth"
+	"learn-go-capstone/internal/search"
 	"learn-go-capstone/internal/task"
 )
 
 // Handler contains all the handlers for the API
 type Handler struct {
-	taskManager        task.TaskManagerInterface
+	taskManager        task.TaskManagerV2
 	userManager        *task.UserManager
 	categoryManager    *task.CategoryManager
 	dependencyManager  *task.DependencyManager
 	searchManager      *task.SearchManager
 	exportManager      *task.ExportManager
 	notificationManager *task.NotificationManager
+	commentManager     *task.CommentManager
+	attachmentManager  *task.AttachmentManager
+	projectManager     *task.ProjectManager
+	boardManager       *task.BoardManager
+	sprintManager      *task.SprintManager
+	customFieldManager *task.CustomFieldManager
+	templateManager    *task.TemplateManager
 	authService        *auth.AuthService
 }
 
 // NewHandler creates a new API handler
 func NewHandler(
-	taskManager task.TaskManagerInterface,
+	taskManager task.TaskManagerV2,
 	userManager *task.UserManager,
 	categoryManager *task.CategoryManager,
 	dependencyManager *task.DependencyManager,
 	searchManager *task.SearchManager,
 	exportManager *task.ExportManager,
 	notificationManager *task.NotificationManager,
+	commentManager *task.CommentManager,
+	attachmentManager *task.AttachmentManager,
+	projectManager *task.ProjectManager,
+	boardManager *task.BoardManager,
+	sprintManager *task.SprintManager,
+	customFieldManager *task.CustomFieldManager,
+	templateManager *task.TemplateManager,
 	authService *auth.AuthService,
 ) *Handler {
 	return &Handler{
@@ -41,10 +59,33 @@ func NewHandler(
 		searchManager:      searchManager,
 		exportManager:      exportManager,
 		notificationManager: notificationManager,
+		commentManager:     commentManager,
+		attachmentManager:  attachmentManager,
+		projectManager:     projectManager,
+		boardManager:       boardManager,
+		sprintManager:      sprintManager,
+		customFieldManager: customFieldManager,
+		templateManager:    templateManager,
 		authService:        authService,
 	}
 }
 
+// users returns the user manager bound to the request context, so that
+// queries are cancelled when the client goes away
+func (h *Handler) users(c *gin.Context) *task.UserManager {
+	return h.userManager.WithContext(c.Request.Context())
+}
+
+// comments returns the comment manager bound to the request context
+func (h *Handler) comments(c *gin.Context) *task.CommentManager {
+	return h.commentManager.WithContext(c.Request.Context())
+}
+
+// attachments returns the attachment manager bound to the request context
+func (h *Handler) attachments(c *gin.Context) *task.AttachmentManager {
+	return h.attachmentManager.WithContext(c.Request.Context())
+}
+
 // HealthCheck handles health check requests
 // @Summary Health check
 // @Description Check the health status of the API
@@ -95,7 +136,7 @@ func (h *Handler) Register(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userManager.RegisterUser(req.Username, req.Email, req.Password)
+	user, err := h.users(c).RegisterUser(req.Username, req.Email, req.Password)
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "username already exists" || err.Error() == "email already exists" {
@@ -149,7 +190,7 @@ func (h *Handler) Login(c *gin.Context) {
 		return
 	}
 
-	token, user, err := h.userManager.LoginUser(req.Username, req.Password)
+	token, user, err := h.users(c).LoginUser(req.Username, req.Password)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, ErrorResponse{
 			Success: false,
@@ -183,7 +224,7 @@ func (h *Handler) Login(c *gin.Context) {
 
 // CreateTask handles task creation
 // @Summary Create a new task
-// @Description Create a new task for the authenticated user
+// @Description Create a new task for the authenticated user, optionally in one of their projects. Tasks in a project may leave out the priority to get the default priority of the project.
 // @Tags tasks
 // @Accept json
 // @Produce json
@@ -192,6 +233,7 @@ func (h *Handler) Login(c *gin.Context) {
 // @Success 201 {object} APIResponse{data=TaskResponse}
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Router /tasks [post]
 func (h *Handler) CreateTask(c *gin.Context) {
 	userID, exists := c.Get("user_id")
@@ -215,20 +257,93 @@ func (h *Handler) CreateTask(c *gin.Context) {
 		return
 	}
 
+	// Tasks created in a project take its default priority when none is given
+	if req.ProjectID != nil {
+		project, err := h.projects(c).GetProject(userID.(int), *req.ProjectID)
+		if err != nil {
+			status := projectStatus(err)
+			c.JSON(status, ErrorResponse{
+				Success: false,
+				Message: "Invalid project",
+				Error:   err.Error(),
+				Code:    status,
+			})
+			return
+		}
+		if req.Priority == 0 {
+			req.Priority = int(project.Settings.DefaultPriority)
+		}
+	}
+	if req.Priority == 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   "priority is required for tasks outside a project",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	// Recurrence and the estimate are stored with the task itself
+	var createPatch task.TaskPatch
+	if req.Recurrence != "" {
+		rule, err := task.ParseRecurrenceRule(req.Recurrence)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Success: false,
+				Message: "Invalid recurrence rule",
+				Error:   err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		createPatch.Recurrence = rule
+	}
+	createPatch.EstimateMinutes = req.EstimateMinutes
+	if err := createPatch.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	// The task is stored together with its project, parent and assignees,
+	// so that an invalid one leaves no task behind
+	relations := task.TaskRelations{ProjectID: req.ProjectID, ParentID: req.ParentID}
+	if req.Assignees != nil {
+		assignees, err := ConvertToAssignees(req.Assignees)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Success: false,
+				Message: "Invalid request data",
+				Error:   err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		relations.Assignees = assignees
+	}
+
 	task := ConvertToTaskRequest(req)
-	createdTask, err := h.userManager.CreateUserTask(
+	createdTask, err := h.users(c).CreateUserTaskWithRelations(
 		userID.(int),
 		task.Title,
 		task.Description,
 		task.Priority,
 		task.DueDate,
+		createPatch,
+		relations,
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+		status := createTaskStatus(err)
+		c.JSON(status, ErrorResponse{
 			Success: false,
 			Message: "Failed to create task",
 			Error:   err.Error(),
-			Code:    http.StatusInternalServerError,
+			Code:    status,
 		})
 		return
 	}
@@ -252,8 +367,12 @@ func (h *Handler) CreateTask(c *gin.Context) {
 // @Param page_size query int false "Page size" default(10)
 // @Param status query int false "Filter by status"
 // @Param priority query int false "Filter by priority"
+// @Param include_archived query bool false "Include archived tasks"
+// @Param include_snoozed query bool false "Include snoozed tasks"
+// @Param project_id query int false "List the tasks of this project instead of the user's own tasks"
 // @Success 200 {object} PaginatedResponse{data=[]TaskResponse}
 // @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Router /tasks [get]
 func (h *Handler) GetTasks(c *gin.Context) {
 	userID, exists := c.Get("user_id")
@@ -270,18 +389,72 @@ func (h *Handler) GetTasks(c *gin.Context) {
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
 	statusStr := c.Query("status")
 	priorityStr := c.Query("priority")
+	includeArchived, _ := strconv.ParseBool(c.DefaultQuery("include_archived", "false"))
+	includeSnoozed, _ := strconv.ParseBool(c.DefaultQuery("include_snoozed", "false"))
+
+	projectID, ok := h.projectScope(c, "Failed to get tasks")
+	if !ok {
+		return
+	}
 
 	var tasks []task.Task
 	var err error
 
-	if statusStr != "" {
-		status, _ := strconv.Atoi(statusStr)
-		tasks, err = h.userManager.GetUserTasksByStatus(userID.(int), task.Status(status))
+	status, _ := strconv.Atoi(statusStr)
+	priority, _ := strconv.Atoi(priorityStr)
+	now := time.Now()
+	if projectID != nil {
+		var projectTasks []task.Task
+		projectTasks, err = h.projects(c).GetProjectTasks(userID.(int), *projectID, includeArchived)
+		for _, t := range projectTasks {
+			if !includeSnoozed && t.IsSnoozed(now) {
+				continue
+			}
+			if statusStr != "" && t.Status != task.Status(status) {
+				continue
+			}
+			if statusStr == "" && priorityStr != "" && t.Priority != task.Priority(priority) {
+				continue
+			}
+			tasks = append(tasks, t)
+		}
+	} else if statusStr != "" {
+		tasks, err = h.users(c).GetUserTasksByStatus(userID.(int), task.Status(status))
 	} else if priorityStr != "" {
-		priority, _ := strconv.Atoi(priorityStr)
-		tasks, err = h.userManager.GetUserTasksByPriority(userID.(int), task.Priority(priority))
+		tasks, err = h.users(c).GetUserTasksByPriority(userID.(int), task.Priority(priority))
 	} else {
-		tasks, err = h.userManager.GetUserTasks(userID.(int))
+		tasks, err = h.users(c).GetUserTasks(userID.(int))
+	}
+
+	if err == nil && includeSnoozed && projectID == nil {
+		var snoozed []task.Task
+		snoozed, err = h.users(c).GetUserSnoozedTasks(userID.(int))
+		for _, t := range snoozed {
+			if statusStr != "" && t.Status != task.Status(status) {
+				continue
+			}
+			if statusStr == "" && priorityStr != "" && t.Priority != task.Priority(priority) {
+				continue
+			}
+			tasks = append(tasks, t)
+		}
+	}
+
+	if err == nil && includeArchived && projectID == nil {
+		var archived []task.Task
+		archived, err = h.users(c).GetUserArchivedTasks(userID.(int))
+		for _, t := range archived {
+			if !includeSnoozed && t.IsSnoozed(now) {
+				continue
+			}
+			if statusStr != "" && t.Status != task.Status(status) {
+				continue
+			}
+			if statusStr == "" && priorityStr != "" && t.Priority != task.Priority(priority) {
+				continue
+			}
+			tasks = append(tasks, t)
+		}
 	}
 
 	if err != nil {
@@ -331,12 +504,13 @@ func (h *Handler) GetTasks(c *gin.Context) {
 
 // GetTask handles getting a specific task
 // @Summary Get a specific task
-// @Description Get a task by ID for the authenticated user
+// @Description Get a task by ID that the authenticated user owns or takes part in, with its assignees. The subtree is only available to the owner.
 // @Tags tasks
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Task ID"
+// @Param include query string false "Set to subtree to include all subtasks and their progress"
 // @Success 200 {object} APIResponse{data=TaskResponse}
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
@@ -364,10 +538,15 @@ func (h *Handler) GetTask(c *gin.Context) {
 		return
 	}
 
-	task, err := h.userManager.GetUserTask(userID.(int), taskID)
+	var task *task.Task
+	if c.Query("include") == "subtree" {
+		task, err = h.users(c).GetUserTaskSubtree(userID.(int), taskID)
+	} else {
+		task, err = h.users(c).GetVisibleTask(userID.(int), taskID)
+	}
 	if err != nil {
 		status := http.StatusInternalServerError
-		if err.Error() == "access denied: task does not belong to user or user ID is missing" {
+		if err.Error() == "access denied: task does not belong to user or user ID is missing" || strings.HasSuffix(err.Error(), "not found") {
 			status = http.StatusNotFound
 		}
 		c.JSON(status, ErrorResponse{
@@ -389,7 +568,7 @@ func (h *Handler) GetTask(c *gin.Context) {
 
 // UpdateTaskStatus handles updating task status
 // @Summary Update task status
-// @Description Update the status of a specific task
+// @Description Update the status of a task owned by the authenticated user, or of a task they are an assignee or reviewer of
 // @Tags tasks
 // @Accept json
 // @Produce json
@@ -399,7 +578,9 @@ func (h *Handler) GetTask(c *gin.Context) {
 // @Success 200 {object} APIResponse{data=TaskResponse}
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "Watchers cannot change the status"
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Transition rejected by the workflow"
 // @Router /tasks/{id}/status [put]
 func (h *Handler) UpdateTaskStatus(c *gin.Context) {
 	userID, exists := c.Get("user_id")
@@ -423,8 +604,9 @@ func (h *Handler) UpdateTaskStatus(c *gin.Context) {
 		return
 	}
 
+	// A pointer so that Pending (0) passes the required check
 	var statusUpdate struct {
-		Status int `json:"status" binding:"required,min=0,max=3"`
+		Status *int `json:"status" binding:"required,min=0"`
 	}
 	if err := c.ShouldBindJSON(&statusUpdate); err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
@@ -436,11 +618,27 @@ func (h *Handler) UpdateTaskStatus(c *gin.Context) {
 		return
 	}
 
-	err = h.userManager.UpdateUserTaskStatus(userID.(int), taskID, task.Status(statusUpdate.Status))
+	newStatus := task.Status(*statusUpdate.Status)
+	if !h.users(c).GetWorkflow().IsKnownStatus(newStatus) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   fmt.Sprintf("unknown status %d", newStatus),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	err = h.users(c).UpdateUserTaskStatus(userID.(int), taskID, newStatus)
 	if err != nil {
 		status := http.StatusInternalServerError
+		var transitionErr *task.TransitionError
 		if err.Error() == "access denied: task does not belong to user or user ID is missing" {
 			status = http.StatusNotFound
+		} else if strings.HasPrefix(err.Error(), "access denied: a ") {
+			status = http.StatusForbidden
+		} else if errors.As(err, &transitionErr) {
+			status = http.StatusConflict
 		}
 		c.JSON(status, ErrorResponse{
 			Success: false,
@@ -452,7 +650,7 @@ func (h *Handler) UpdateTaskStatus(c *gin.Context) {
 	}
 
 	// Get updated task
-	updatedTask, err := h.userManager.GetUserTask(userID.(int), taskID)
+	updatedTask, err := h.users(c).GetVisibleTask(userID.(int), taskID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Success: false,
@@ -471,9 +669,461 @@ func (h *Handler) UpdateTaskStatus(c *gin.Context) {
 	})
 }
 
+// PatchTask handles partial task updates
+// @Summary Partially update a task
+// @Description Update the title, description, priority, due date, recurrence, estimate or assignees of a task using JSON Merge Patch semantics. Omitted fields are left unchanged and a null due_date, recurrence or estimate_minutes clears it. assignees replaces the whole list.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param task body TaskPatchRequest true "Fields to update"
+// @Success 200 {object} APIResponse{data=TaskResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id} [patch]
+func (h *Handler) PatchTask(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid task ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	patch, err := ParseTaskPatch(body)
+	if err == nil {
+		err = patch.Validate()
+	}
+	var assignees *[]task.Assignee
+	if err == nil {
+		assignees, err = ParseAssigneePatch(body)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	// Assignees go first: they are the only part that can still be rejected
+	var updatedTask *task.Task
+	if assignees != nil {
+		updatedTask, err = h.users(c).SetUserTaskAssignees(userID.(int), taskID, *assignees)
+		if err != nil {
+			status := assigneeStatus(err)
+			c.JSON(status, ErrorResponse{
+				Success: false,
+				Message: "Failed to update task",
+				Error:   err.Error(),
+				Code:    status,
+			})
+			return
+		}
+	}
+
+	if assignees == nil || !patch.IsEmpty() {
+		updatedTask, err = h.users(c).PatchUserTask(userID.(int), taskID, patch)
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "access denied: task does not belong to user or user ID is missing" ||
+			strings.HasSuffix(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to update task",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	taskResponse := ConvertToTaskResponse(*updatedTask)
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Task updated successfully",
+		Data:    taskResponse,
+	})
+}
+
+// SetTaskParent handles moving a task within the task hierarchy
+// @Summary Set the parent of a task
+// @Description Move a task under another task, or make it a top-level task with a null parent_id
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param parent body TaskParentRequest true "New parent"
+// @Success 200 {object} APIResponse{data=TaskResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/parent [put]
+func (h *Handler) SetTaskParent(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid task ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var req TaskParentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	// Make sure the task itself is visible before validating the new parent
+	if _, err := h.users(c).GetUserTask(userID.(int), taskID); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Success: false,
+			Message: "Failed to update task parent",
+			Error:   err.Error(),
+			Code:    http.StatusNotFound,
+		})
+		return
+	}
+
+	updatedTask, err := h.users(c).SetUserTaskParent(userID.(int), taskID, req.ParentID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Failed to update task parent",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	taskResponse := ConvertToTaskResponse(*updatedTask)
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Task parent updated successfully",
+		Data:    taskResponse,
+	})
+}
+
+// GetSubtasks handles getting the direct subtasks of a task
+// @Summary Get subtasks
+// @Description Get the direct subtasks of a specific task
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} APIResponse{data=[]TaskResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/subtasks [get]
+func (h *Handler) GetSubtasks(c *gin.Context) {
+	h.getRelatedTasks(c, "Subtasks retrieved successfully", h.users(c).GetUserSubtasks)
+}
+
+// GetTaskAncestors handles getting the ancestors of a task
+// @Summary Get task ancestors
+// @Description Get the chain of parent tasks of a specific task, nearest parent first
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} APIResponse{data=[]TaskResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/ancestors [get]
+func (h *Handler) GetTaskAncestors(c *gin.Context) {
+	h.getRelatedTasks(c, "Ancestors retrieved successfully", h.users(c).GetUserTaskAncestors)
+}
+
+// getRelatedTasks responds with the tasks returned by lookup for the task in the path
+func (h *Handler) getRelatedTasks(c *gin.Context, message string, lookup func(userID, taskID int) ([]task.Task, error)) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid task ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	tasks, err := lookup(userID.(int), taskID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "access denied: task does not belong to user or user ID is missing" || strings.HasSuffix(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to get tasks",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	taskResponses := make([]TaskResponse, len(tasks))
+	for i, t := range tasks {
+		taskResponses[i] = ConvertToTaskResponse(t)
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: message,
+		Data:    taskResponses,
+	})
+}
+
+// GetTaskHistory handles getting the change history of a task
+// @Summary Get task history
+// @Description Get every recorded field change of a specific task with the user who made it, oldest first
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} APIResponse{data=[]TaskHistoryResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/history [get]
+func (h *Handler) GetTaskHistory(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid task ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	history, err := h.users(c).GetUserTaskHistory(userID.(int), taskID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "access denied: task does not belong to user or user ID is missing" || strings.HasSuffix(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to get task history",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	response := make([]TaskHistoryResponse, len(history))
+	for i, entry := range history {
+		response[i] = TaskHistoryResponse{
+			ID:        entry.ID,
+			TaskID:    entry.TaskID,
+			UserID:    entry.UserID,
+			Field:     entry.Field,
+			OldValue:  entry.OldValue,
+			NewValue:  entry.NewValue,
+			ChangedAt: entry.ChangedAt,
+		}
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Task history retrieved successfully",
+		Data:    response,
+	})
+}
+
+// ArchiveTask handles archiving a task
+// @Summary Archive task
+// @Description Hide a task from listings and search without deleting it
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} APIResponse{data=TaskResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/archive [post]
+func (h *Handler) ArchiveTask(c *gin.Context) {
+	h.updateTaskState(c, "Task archived successfully", h.users(c).ArchiveUserTask)
+}
+
+// UnarchiveTask handles unarchiving a task
+// @Summary Unarchive task
+// @Description Bring an archived task back into listings and search
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} APIResponse{data=TaskResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/archive [delete]
+func (h *Handler) UnarchiveTask(c *gin.Context) {
+	h.updateTaskState(c, "Task unarchived successfully", h.users(c).UnarchiveUserTask)
+}
+
+// updateTaskState runs update on the task in the path and writes the updated task
+func (h *Handler) updateTaskState(c *gin.Context, message string, update func(userID, taskID int) (*task.Task, error)) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid task ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	updatedTask, err := update(userID.(int), taskID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "access denied: task does not belong to user or user ID is missing" || strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		} else if strings.HasPrefix(err.Error(), "invalid") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to update task",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: message,
+		Data:    ConvertToTaskResponse(*updatedTask),
+	})
+}
+
+// GetWorkflow handles getting the task status workflow
+// @Summary Get the status workflow
+// @Description Get all task statuses and the transitions allowed from each of them
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} APIResponse{data=[]WorkflowStatusResponse}
+// @Failure 401 {object} ErrorResponse
+// @Router /workflow [get]
+func (h *Handler) GetWorkflow(c *gin.Context) {
+	workflow := h.users(c).GetWorkflow()
+
+	statuses := workflow.Statuses()
+	response := make([]WorkflowStatusResponse, len(statuses))
+	for i, status := range statuses {
+		response[i] = WorkflowStatusResponse{
+			Status:      int(status),
+			Name:        workflow.StatusName(status),
+			Transitions: []int{},
+		}
+		for _, to := range workflow.AllowedTransitions(status) {
+			response[i].Transitions = append(response[i].Transitions, int(to))
+		}
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Workflow retrieved successfully",
+		Data:    response,
+	})
+}
+
 // DeleteTask handles task deletion
 // @Summary Delete a task
-// @Description Delete a specific task
+// @Description Move a specific task to the trash, from where it can be restored until it is purged
 // @Tags tasks
 // @Accept json
 // @Produce json
@@ -506,11 +1156,13 @@ func (h *Handler) DeleteTask(c *gin.Context) {
 		return
 	}
 
-	err = h.userManager.DeleteUserTask(userID.(int), taskID)
+	err = h.users(c).DeleteUserTask(userID.(int), taskID)
 	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "access denied: task does not belong to user or user ID is missing" {
 			status = http.StatusNotFound
+		} else if strings.Contains(err.Error(), "cannot be deleted") {
+			status = http.StatusConflict
 		}
 		c.JSON(status, ErrorResponse{
 			Success: false,
@@ -527,6 +1179,68 @@ func (h *Handler) DeleteTask(c *gin.Context) {
 	})
 }
 
+// GetTrash handles listing the deleted tasks of the user
+// @Summary List deleted tasks
+// @Description Get the tasks in the trash of the authenticated user, most recently deleted first
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} APIResponse{data=[]TaskResponse}
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /trash [get]
+func (h *Handler) GetTrash(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	tasks, err := h.users(c).GetUserDeletedTasks(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Success: false,
+			Message: "Failed to get deleted tasks",
+			Error:   err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	taskResponses := make([]TaskResponse, len(tasks))
+	for i, t := range tasks {
+		taskResponses[i] = ConvertToTaskResponse(t)
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Deleted tasks retrieved successfully",
+		Data:    taskResponses,
+	})
+}
+
+// RestoreTask handles restoring a deleted task
+// @Summary Restore a deleted task
+// @Description Take a task out of the trash with its tags and dependencies; deleted parent tasks are restored as well
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} APIResponse{data=TaskResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /trash/{id}/restore [post]
+func (h *Handler) RestoreTask(c *gin.Context) {
+	h.updateTaskState(c, "Task restored successfully", h.users(c).RestoreUserTask)
+}
+
 // SearchTasks handles task search
 // @Summary Search tasks
 // @Description Search tasks with various filters
@@ -573,28 +1287,55 @@ func (h *Handler) SearchTasks(c *gin.Context) {
 		priority = &p
 	}
 
+	// Members search all tasks of a project, not only their own
 	userIDInt := userID.(int)
-	searchResults, err := h.searchManager.SearchTasksWithFilters(
-		req.Query,
-		&userIDInt,
-		status,
-		priority,
-		req.CategoryID,
-		req.TagNames,
-		req.PageSize,
-		(req.Page-1)*req.PageSize,
-	)
+	ownerID := &userIDInt
+	if req.ProjectID != nil {
+		if !h.checkProjectAccess(c, req.ProjectID, "Failed to search tasks") {
+			return
+		}
+		ownerID = nil
+	}
+
+	customFilters := make([]search.CustomFieldFilter, len(req.CustomFields))
+	for i, filter := range req.CustomFields {
+		customFilters[i] = search.CustomFieldFilter{
+			FieldID: filter.FieldID,
+			Value:   filter.Value,
+			Min:     filter.Min,
+			Max:     filter.Max,
+		}
+	}
+
+	result, err := h.searchManager.SearchTasks(search.SearchQuery{
+		Query:           req.Query,
+		UserID:          ownerID,
+		ProjectID:       req.ProjectID,
+		Status:          (*int)(status),
+		Priority:        (*int)(priority),
+		CategoryID:      req.CategoryID,
+		TagNames:        req.TagNames,
+		IncludeArchived: req.IncludeArchived,
+		CustomFields:    customFilters,
+		Limit:           req.PageSize,
+		Offset:          (req.Page-1)*req.PageSize,
+		SortBy:          req.SortBy,
+		SortOrder:       req.SortOrder,
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
+		// Custom field filters fail on unknown fields and invalid values
+		status := customFieldStatus(err)
+		c.JSON(status, ErrorResponse{
 			Success: false,
 			Message: "Failed to search tasks",
 			Error:   err.Error(),
-			Code:    http.StatusInternalServerError,
+			Code:    status,
 		})
 		return
 	}
 
 	// Convert tasks to responses
+	searchResults := result.Tasks
 	taskResponses := make([]TaskResponse, len(searchResults))
 	for i, result := range searchResults {
 		// Convert TaskResult to Task
@@ -607,6 +1348,8 @@ func (h *Handler) SearchTasks(c *gin.Context) {
 			CreatedAt:   result.CreatedAt,
 			UpdatedAt:   result.UpdatedAt,
 			DueDate:     result.DueDate,
+			ProjectID:   result.ProjectID,
+			IsArchived:  result.IsArchived,
 		}
 		taskResponses[i] = ConvertToTaskResponse(task)
 	}
@@ -633,13 +1376,15 @@ func (h *Handler) SearchTasks(c *gin.Context) {
 
 // GetStatistics handles getting application statistics
 // @Summary Get statistics
-// @Description Get application statistics for the authenticated user
+// @Description Get application statistics for the authenticated user, or for the tasks, categories and tags of one of their projects
 // @Tags statistics
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param project_id query int false "Project to get statistics for"
 // @Success 200 {object} APIResponse{data=StatisticsResponse}
 // @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Router /statistics [get]
 func (h *Handler) GetStatistics(c *gin.Context) {
 	userID, exists := c.Get("user_id")
@@ -652,8 +1397,32 @@ func (h *Handler) GetStatistics(c *gin.Context) {
 		return
 	}
 
+	projectID, ok := h.projectScope(c, "Failed to get statistics")
+	if !ok {
+		return
+	}
+	if projectID != nil {
+		stats, err := h.projectStatistics(c, userID.(int), *projectID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Success: false,
+				Message: "Failed to get statistics",
+				Error:   err.Error(),
+				Code:    http.StatusInternalServerError,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, APIResponse{
+			Success: true,
+			Message: "Statistics retrieved successfully",
+			Data:    stats,
+		})
+		return
+	}
+
 	// Get user tasks
-	tasks, err := h.userManager.GetUserTasks(userID.(int))
+	tasks, err := h.users(c).GetUserTasks(userID.(int))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Success: false,
@@ -679,7 +1448,7 @@ func (h *Handler) GetStatistics(c *gin.Context) {
 	}
 
 	// Count overdue tasks
-	overdueTasks, err := h.userManager.GetUserOverdueTasks(userID.(int))
+	overdueTasks, err := h.users(c).GetUserOverdueTasks(userID.(int))
 	if err == nil {
 		stats.OverdueTasks = len(overdueTasks)
 	}
@@ -690,3 +1459,54 @@ func (h *Handler) GetStatistics(c *gin.Context) {
 		Data:    stats,
 	})
 }
+
+// projectStatistics counts the unarchived tasks of a project, and its
+// categories and tags
+func (h *Handler) projectStatistics(c *gin.Context, userID, projectID int) (*StatisticsResponse, error) {
+	tasks, err := h.projects(c).GetProjectTasks(userID, projectID, false)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := h.categoryManager.GetCategoriesByProject(&projectID)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := h.categoryManager.GetTagsByProject(&projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &StatisticsResponse{
+		TotalTasks:      len(tasks),
+		TotalCategories: len(categories),
+		TotalTags:       len(tags),
+	}
+
+	now := time.Now()
+	for _, t := range tasks {
+		switch t.Status {
+		case task.Completed:
+			stats.CompletedTasks++
+		case task.Pending:
+			stats.PendingTasks++
+		}
+		if t.DueDate != nil && t.DueDate.Before(now) && t.Status != task.Completed {
+			stats.OverdueTasks++
+		}
+	}
+
+	return stats, nil
+}
+
+// createTaskStatus maps an error from creating a task with its relations to an HTTP status
+func createTaskStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.HasPrefix(message, "invalid"):
+		return http.StatusBadRequest
+	case message == "access denied: task does not belong to user or user ID is missing" || strings.HasSuffix(message, "not found"):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -6,11 +6,12 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"learn-go-capstone/internal/database"
+	"learn-go-capstone/internal/export"
 )
 
 // CreateCategory handles category creation
 // @Summary Create a new category
-// @Description Create a new task category
+// @Description Create a new task category, either global or in a project of the authenticated user. Names are unique within their project.
 // @Tags categories
 // @Accept json
 // @Produce json
@@ -42,7 +43,17 @@ func (h *Handler) CreateCategory(c *gin.Context) {
 		return
 	}
 
-	createdCategory, err := h.categoryManager.CreateCategory(req.Name, req.Description, req.Color)
+	if !h.checkProjectAccess(c, req.ProjectID, "Failed to create category") {
+		return
+	}
+
+	var createdCategory *database.Category
+	var err error
+	if req.ProjectID != nil {
+		createdCategory, err = h.categoryManager.CreateProjectCategory(*req.ProjectID, req.Name, req.Description, req.Color)
+	} else {
+		createdCategory, err = h.categoryManager.CreateCategory(req.Name, req.Description, req.Color)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Success: false,
@@ -58,6 +69,7 @@ func (h *Handler) CreateCategory(c *gin.Context) {
 		Name:        createdCategory.Name,
 		Description: createdCategory.Description,
 		Color:       createdCategory.Color,
+		ProjectID:   createdCategory.ProjectID,
 		CreatedAt:   createdCategory.CreatedAt,
 		UpdatedAt:   createdCategory.UpdatedAt,
 	}
@@ -71,16 +83,23 @@ func (h *Handler) CreateCategory(c *gin.Context) {
 
 // GetCategories handles getting all categories
 // @Summary Get all categories
-// @Description Get all task categories
+// @Description Get the global task categories, or those of a project of the authenticated user
 // @Tags categories
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param project_id query int false "Project to get categories for"
 // @Success 200 {object} APIResponse{data=[]CategoryResponse}
 // @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Router /categories [get]
 func (h *Handler) GetCategories(c *gin.Context) {
-	categories, err := h.categoryManager.GetAllCategories()
+	projectID, ok := h.projectScope(c, "Failed to get categories")
+	if !ok {
+		return
+	}
+
+	categories, err := h.categoryManager.GetCategoriesByProject(projectID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Success: false,
@@ -98,6 +117,7 @@ func (h *Handler) GetCategories(c *gin.Context) {
 			Name:        category.Name,
 			Description: category.Description,
 			Color:       category.Color,
+			ProjectID:   category.ProjectID,
 			CreatedAt:   category.CreatedAt,
 			UpdatedAt:   category.UpdatedAt,
 		}
@@ -146,11 +166,16 @@ func (h *Handler) GetCategory(c *gin.Context) {
 		return
 	}
 
+	if !h.checkProjectAccess(c, category.ProjectID, "Category not found") {
+		return
+	}
+
 	categoryResponse := CategoryResponse{
 		ID:          category.ID,
 		Name:        category.Name,
 		Description: category.Description,
 		Color:       category.Color,
+		ProjectID:   category.ProjectID,
 		CreatedAt:   category.CreatedAt,
 		UpdatedAt:   category.UpdatedAt,
 	}
@@ -199,6 +224,10 @@ func (h *Handler) UpdateCategory(c *gin.Context) {
 		return
 	}
 
+	if !h.checkCategoryAccess(c, categoryID) {
+		return
+	}
+
 	category := &database.Category{
 		ID:          categoryID,
 		Name:        req.Name,
@@ -234,6 +263,7 @@ func (h *Handler) UpdateCategory(c *gin.Context) {
 		Name:        updatedCategory.Name,
 		Description: updatedCategory.Description,
 		Color:       updatedCategory.Color,
+		ProjectID:   updatedCategory.ProjectID,
 		CreatedAt:   updatedCategory.CreatedAt,
 		UpdatedAt:   updatedCategory.UpdatedAt,
 	}
@@ -270,6 +300,10 @@ func (h *Handler) DeleteCategory(c *gin.Context) {
 		return
 	}
 
+	if !h.checkCategoryAccess(c, categoryID) {
+		return
+	}
+
 	err = h.categoryManager.DeleteCategory(categoryID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
@@ -289,7 +323,7 @@ func (h *Handler) DeleteCategory(c *gin.Context) {
 
 // CreateTag handles tag creation
 // @Summary Create a new tag
-// @Description Create a new task tag
+// @Description Create a new task tag, either global or in a project of the authenticated user. Names are unique within their project.
 // @Tags tags
 // @Accept json
 // @Produce json
@@ -311,7 +345,17 @@ func (h *Handler) CreateTag(c *gin.Context) {
 		return
 	}
 
-	createdTag, err := h.categoryManager.CreateTag(req.Name, req.Color)
+	if !h.checkProjectAccess(c, req.ProjectID, "Failed to create tag") {
+		return
+	}
+
+	var createdTag *database.Tag
+	var err error
+	if req.ProjectID != nil {
+		createdTag, err = h.categoryManager.CreateProjectTag(*req.ProjectID, req.Name, req.Color)
+	} else {
+		createdTag, err = h.categoryManager.CreateTag(req.Name, req.Color)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Success: false,
@@ -326,6 +370,7 @@ func (h *Handler) CreateTag(c *gin.Context) {
 		ID:        createdTag.ID,
 		Name:      createdTag.Name,
 		Color:     createdTag.Color,
+		ProjectID: createdTag.ProjectID,
 		CreatedAt: createdTag.CreatedAt,
 	}
 
@@ -338,16 +383,23 @@ func (h *Handler) CreateTag(c *gin.Context) {
 
 // GetTags handles getting all tags
 // @Summary Get all tags
-// @Description Get all task tags
+// @Description Get the global task tags, or those of a project of the authenticated user
 // @Tags tags
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param project_id query int false "Project to get tags for"
 // @Success 200 {object} APIResponse{data=[]TagResponse}
 // @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Router /tags [get]
 func (h *Handler) GetTags(c *gin.Context) {
-	tags, err := h.categoryManager.GetAllTags()
+	projectID, ok := h.projectScope(c, "Failed to get tags")
+	if !ok {
+		return
+	}
+
+	tags, err := h.categoryManager.GetTagsByProject(projectID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Success: false,
@@ -364,6 +416,7 @@ func (h *Handler) GetTags(c *gin.Context) {
 			ID:        tag.ID,
 			Name:      tag.Name,
 			Color:     tag.Color,
+			ProjectID: tag.ProjectID,
 			CreatedAt: tag.CreatedAt,
 		}
 	}
@@ -411,10 +464,15 @@ func (h *Handler) GetTag(c *gin.Context) {
 		return
 	}
 
+	if !h.checkProjectAccess(c, tag.ProjectID, "Tag not found") {
+		return
+	}
+
 	tagResponse := TagResponse{
 		ID:        tag.ID,
 		Name:      tag.Name,
 		Color:     tag.Color,
+		ProjectID: tag.ProjectID,
 		CreatedAt: tag.CreatedAt,
 	}
 
@@ -462,6 +520,10 @@ func (h *Handler) UpdateTag(c *gin.Context) {
 		return
 	}
 
+	if !h.checkTagAccess(c, tagID) {
+		return
+	}
+
 	tag := &database.Tag{
 		ID:    tagID,
 		Name:  req.Name,
@@ -495,6 +557,7 @@ func (h *Handler) UpdateTag(c *gin.Context) {
 		ID:        updatedTag.ID,
 		Name:      updatedTag.Name,
 		Color:     updatedTag.Color,
+		ProjectID: updatedTag.ProjectID,
 		CreatedAt: updatedTag.CreatedAt,
 	}
 
@@ -530,6 +593,10 @@ func (h *Handler) DeleteTag(c *gin.Context) {
 		return
 	}
 
+	if !h.checkTagAccess(c, tagID) {
+		return
+	}
+
 	err = h.categoryManager.DeleteTag(tagID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
@@ -571,7 +638,7 @@ func (h *Handler) CreateDependency(c *gin.Context) {
 		return
 	}
 
-	err := h.dependencyManager.AddDependency(req.TaskID, req.DependsOnTaskID)
+	err := h.dependencyManager.WithContext(c.Request.Context()).AddDependency(req.TaskID, req.DependsOnTaskID)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Success: false,
@@ -658,7 +725,7 @@ func (h *Handler) DeleteDependency(c *gin.Context) {
 
 // ExportTasks handles task export
 // @Summary Export tasks
-// @Description Export tasks in JSON or CSV format
+// @Description Export the tasks of the authenticated user, or of one of their projects, in JSON or CSV format
 // @Tags export
 // @Accept json
 // @Produce json
@@ -690,13 +757,41 @@ func (h *Handler) ExportTasks(c *gin.Context) {
 		return
 	}
 
-	// Note: This would need to be implemented in the export manager
-	_ = userID
-	_ = req
+	// Members export all tasks of a project, not only their own
+	options := export.ExportOptions{
+		Format:            export.ExportFormat(req.Format),
+		IncludeTags:       req.IncludeTags,
+		IncludeCategories: req.IncludeCategories,
+		IncludeUsers:      req.IncludeUsers,
+		DateFrom:          req.CreatedAfter,
+		ProjectID:         req.ProjectID,
+		Status:            req.Status,
+		Priority:          req.Priority,
+	}
+	if req.ProjectID != nil {
+		if !h.checkProjectAccess(c, req.ProjectID, "Failed to export tasks") {
+			return
+		}
+	} else {
+		ownerID := userID.(int)
+		options.UserID = &ownerID
+	}
+
+	result, err := h.exportManager.ExportTasks(options)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Success: false,
+			Message: "Failed to export tasks",
+			Error:   err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
 
 	c.JSON(http.StatusOK, APIResponse{
 		Success: true,
 		Message: "Export completed successfully",
+		Data:    result,
 	})
 }
 
@@ -927,3 +1022,39 @@ func (h *Handler) GetNotificationStats(c *gin.Context) {
 		Data:    stats,
 	})
 }
+
+// checkCategoryAccess checks that a category exists and, when it belongs to a
+// project, that the authenticated user is a member, writing an error response
+// when either fails
+func (h *Handler) checkCategoryAccess(c *gin.Context, categoryID int) bool {
+	category, err := h.categoryManager.GetCategory(categoryID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Success: false,
+			Message: "Category not found",
+			Error:   err.Error(),
+			Code:    http.StatusNotFound,
+		})
+		return false
+	}
+
+	return h.checkProjectAccess(c, category.ProjectID, "Category not found")
+}
+
+// checkTagAccess checks that a tag exists and, when it belongs to a project,
+// that the authenticated user is a member, writing an error response when
+// either fails
+func (h *Handler) checkTagAccess(c *gin.Context, tagID int) bool {
+	tag, err := h.categoryManager.GetTag(tagID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Success: false,
+			Message: "Tag not found",
+			Error:   err.Error(),
+			Code:    http.StatusNotFound,
+		})
+		return false
+	}
+
+	return h.checkProjectAccess(c, tag.ProjectID, "Tag not found")
+}
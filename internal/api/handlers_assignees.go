@@ -0,0 +1,69 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"learn-go-capstone/internal/task"
+)
+
+// GetAssignedTasks handles listing the tasks the user takes part in
+// @Summary Get assigned tasks
+// @Description Get the tasks the authenticated user is an assignee, reviewer or watcher of, newest first
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param role query string false "Only tasks with this role: assignee, reviewer or watcher"
+// @Success 200 {object} APIResponse{data=[]TaskResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /tasks/assigned [get]
+func (h *Handler) GetAssignedTasks(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	tasks, err := h.users(c).GetAssignedTasks(userID.(int), task.AssigneeRole(c.Query("role")))
+	if err != nil {
+		status := assigneeStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to get assigned tasks",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	response := make([]TaskResponse, len(tasks))
+	for i, t := range tasks {
+		response[i] = ConvertToTaskResponse(t)
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Assigned tasks retrieved successfully",
+		Data:    response,
+	})
+}
+
+// assigneeStatus maps an assignee error to an HTTP status
+func assigneeStatus(err error) int {
+	message := err.Error()
+	switch {
+	case message == "access denied: task does not belong to user or user ID is missing" || strings.HasSuffix(message, "not found"):
+		return http.StatusNotFound
+	case strings.HasPrefix(message, "invalid assignee"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
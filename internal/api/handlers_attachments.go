@@ -0,0 +1,297 @@
+package api
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead is allowed on top of the attachment size limit for the
+// multipart framing of an upload
+const multipartOverhead = 1 << 20
+
+// GetAttachments handles listing the attachments of a task
+// @Summary Get task attachments
+// @Description List the files attached to a task, oldest first
+// @Tags attachments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} APIResponse{data=[]AttachmentResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/attachments [get]
+func (h *Handler) GetAttachments(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid task ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	attachments, err := h.attachments(c).GetAttachments(userID.(int), taskID)
+	if err != nil {
+		status := attachmentStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to get attachments",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	response := make([]AttachmentResponse, len(attachments))
+	for i, attachment := range attachments {
+		response[i] = ConvertToAttachmentResponse(attachment)
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Attachments retrieved successfully",
+		Data:    response,
+	})
+}
+
+// UploadAttachment handles attaching a file to a task
+// @Summary Upload an attachment
+// @Description Attach a file to a task. The type is detected from the content and, like the size, must be within the configured limits. Identical files are stored once.
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param file formData file true "File to attach"
+// @Success 201 {object} APIResponse{data=AttachmentResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Router /tasks/{id}/attachments [post]
+func (h *Handler) UploadAttachment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid task ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	manager := h.attachments(c)
+	if maxSize := manager.Limits().MaxSize; maxSize > 0 {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+multipartOverhead)
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		status := http.StatusBadRequest
+		if strings.Contains(err.Error(), "request body too large") {
+			status = http.StatusRequestEntityTooLarge
+		}
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Invalid upload",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Success: false,
+			Message: "Failed to read upload",
+			Error:   err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+	defer file.Close()
+
+	attachment, err := manager.AddAttachment(userID.(int), taskID, header.Filename, file)
+	if err != nil {
+		status := attachmentStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to upload attachment",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Success: true,
+		Message: "Attachment uploaded successfully",
+		Data:    ConvertToAttachmentResponse(*attachment),
+	})
+}
+
+// DownloadAttachment handles downloading an attachment
+// @Summary Download an attachment
+// @Description Download the content of a file attached to a task
+// @Tags attachments
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param attachment_id path int true "Attachment ID"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/attachments/{attachment_id} [get]
+func (h *Handler) DownloadAttachment(c *gin.Context) {
+	userID, taskID, attachmentID, ok := attachmentParams(c)
+	if !ok {
+		return
+	}
+
+	attachment, content, err := h.attachments(c).OpenAttachment(userID, taskID, attachmentID)
+	if err != nil {
+		status := attachmentStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to download attachment",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+	defer content.Close()
+
+	// Always download rather than render, so uploaded HTML or SVG never runs
+	// in the API's origin
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// DeleteAttachment handles removing an attachment
+// @Summary Delete an attachment
+// @Description Remove a file from a task, as its uploader or the owner of the task. The stored content is removed once no attachment refers to it.
+// @Tags attachments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param attachment_id path int true "Attachment ID"
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/attachments/{attachment_id} [delete]
+func (h *Handler) DeleteAttachment(c *gin.Context) {
+	userID, taskID, attachmentID, ok := attachmentParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.attachments(c).DeleteAttachment(userID, taskID, attachmentID); err != nil {
+		status := attachmentStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to delete attachment",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Attachment deleted successfully",
+	})
+}
+
+// attachmentParams reads the authenticated user and the task and attachment
+// IDs in the path, writing an error response when one of them is missing or invalid
+func attachmentParams(c *gin.Context) (userID, taskID, attachmentID int, ok bool) {
+	user, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return 0, 0, 0, false
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid task ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return 0, 0, 0, false
+	}
+
+	attachmentID, err = strconv.Atoi(c.Param("attachment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid attachment ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return 0, 0, 0, false
+	}
+
+	return user.(int), taskID, attachmentID, true
+}
+
+// attachmentStatus maps an attachment error to an HTTP status
+func attachmentStatus(err error) int {
+	message := err.Error()
+	switch {
+	case message == "access denied: task does not belong to user or user ID is missing" || strings.HasSuffix(message, "not found"):
+		return http.StatusNotFound
+	case message == "access denied: attachment was uploaded by another user":
+		return http.StatusForbidden
+	case strings.HasPrefix(message, "attachment is larger than"):
+		return http.StatusRequestEntityTooLarge
+	case strings.HasPrefix(message, "attachment type"):
+		return http.StatusUnsupportedMediaType
+	case strings.HasPrefix(message, "attachment file name"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
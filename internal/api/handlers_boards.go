@@ -0,0 +1,348 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"learn-go-capstone/internal/task"
+)
+
+// boards returns the board manager bound to the request context
+func (h *Handler) boards(c *gin.Context) *task.BoardManager {
+	return h.boardManager.WithContext(c.Request.Context())
+}
+
+// CreateBoard handles board creation
+// @Summary Create a board
+// @Description Create a kanban board over the authenticated user's tasks or, with project_id, over the tasks of one of their projects. Each column shows the tasks with a status or with a tag, and can limit how many cards it holds.
+// @Tags boards
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param board body BoardRequest true "Board data"
+// @Success 201 {object} APIResponse{data=BoardResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /boards [post]
+func (h *Handler) CreateBoard(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	var req BoardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	board, err := h.boards(c).CreateBoard(userID.(int), req.Name, req.ProjectID, ConvertToBoardColumns(req.Columns))
+	if err != nil {
+		status := boardStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to create board",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Success: true,
+		Message: "Board created successfully",
+		Data:    ConvertToBoardResponse(*board),
+	})
+}
+
+// GetBoards handles listing the boards of the user
+// @Summary Get boards
+// @Description Get the personal boards of the authenticated user and the boards of their projects, by name and without cards
+// @Tags boards
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} APIResponse{data=[]BoardResponse}
+// @Failure 401 {object} ErrorResponse
+// @Router /boards [get]
+func (h *Handler) GetBoards(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	boards, err := h.boards(c).GetUserBoards(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Success: false,
+			Message: "Failed to get boards",
+			Error:   err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	response := make([]BoardResponse, len(boards))
+	for i, board := range boards {
+		response[i] = ConvertToBoardResponse(board)
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Boards retrieved successfully",
+		Data:    response,
+	})
+}
+
+// GetBoard handles getting a whole board
+// @Summary Get a board
+// @Description Get a board with its columns and the cards of each column in order
+// @Tags boards
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Board ID"
+// @Success 200 {object} APIResponse{data=BoardResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /boards/{id} [get]
+func (h *Handler) GetBoard(c *gin.Context) {
+	userID, boardID, ok := boardParams(c)
+	if !ok {
+		return
+	}
+
+	board, err := h.boards(c).GetBoard(userID, boardID)
+	if err != nil {
+		status := boardStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to get board",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Board retrieved successfully",
+		Data:    ConvertToBoardResponse(*board),
+	})
+}
+
+// UpdateBoard handles board updates
+// @Summary Update a board
+// @Description Rename a board and replace its columns. Columns passed with their ID keep their cards; columns left out are removed. Only the board owner and project owners can update a board.
+// @Tags boards
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Board ID"
+// @Param board body BoardRequest true "Updated board data"
+// @Success 200 {object} APIResponse{data=BoardResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /boards/{id} [put]
+func (h *Handler) UpdateBoard(c *gin.Context) {
+	userID, boardID, ok := boardParams(c)
+	if !ok {
+		return
+	}
+
+	var req BoardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	board, err := h.boards(c).UpdateBoard(userID, boardID, req.Name, ConvertToBoardColumns(req.Columns))
+	if err != nil {
+		status := boardStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to update board",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Board updated successfully",
+		Data:    ConvertToBoardResponse(*board),
+	})
+}
+
+// DeleteBoard handles board deletion
+// @Summary Delete a board
+// @Description Delete a board. Its tasks are kept. Only the board owner and project owners can delete a board.
+// @Tags boards
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Board ID"
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /boards/{id} [delete]
+func (h *Handler) DeleteBoard(c *gin.Context) {
+	userID, boardID, ok := boardParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.boards(c).DeleteBoard(userID, boardID); err != nil {
+		status := boardStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to delete board",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Board deleted successfully",
+	})
+}
+
+// MoveBoardCard handles moving a card on a board
+// @Summary Move a card
+// @Description Move a task to a position in a column of a board. The task takes the status or the tag of the column in the same transaction as the new order. Moves into a column at its work-in-progress limit and status changes the workflow does not allow are refused.
+// @Tags boards
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Board ID"
+// @Param task_id path int true "Task ID"
+// @Param move body BoardCardMoveRequest true "Target column and position"
+// @Success 200 {object} APIResponse{data=BoardResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /boards/{id}/cards/{task_id}/move [post]
+func (h *Handler) MoveBoardCard(c *gin.Context) {
+	userID, boardID, ok := boardParams(c)
+	if !ok {
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("task_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid task ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var req BoardCardMoveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	board, err := h.boards(c).MoveCard(userID, boardID, taskID, req.ColumnID, req.Position)
+	if err != nil {
+		status := boardStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to move card",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Card moved successfully",
+		Data:    ConvertToBoardResponse(*board),
+	})
+}
+
+// boardParams reads the authenticated user and the board ID in the path,
+// writing an error response when one of them is missing or invalid
+func boardParams(c *gin.Context) (userID, boardID int, ok bool) {
+	user, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return 0, 0, false
+	}
+
+	boardID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid board ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return 0, 0, false
+	}
+
+	return user.(int), boardID, true
+}
+
+// boardStatus maps a board manager error to an HTTP status code
+func boardStatus(err error) int {
+	var transitionErr *task.TransitionError
+	message := err.Error()
+	switch {
+	case strings.HasSuffix(message, "not found"):
+		return http.StatusNotFound
+	case strings.HasPrefix(message, "access denied: only"):
+		return http.StatusForbidden
+	case strings.HasPrefix(message, "invalid"):
+		return http.StatusBadRequest
+	case strings.HasPrefix(message, "work-in-progress limit reached") || errors.As(err, &transitionErr):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"learn-go-capstone/internal/search"
+	"learn-go-capstone/internal/task"
+)
+
+// BulkUpdateTasks handles changing many tasks at once
+// @Summary Change tasks in bulk
+// @Description Change the status, priority, category or tags of many tasks, or archive or delete them, in one transaction. Tasks are given by ID or by a search filter. Unless partial is set, nothing changes when any task fails; dry_run reports the outcome for every task without changing anything.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body BulkRequest true "Bulk request"
+// @Success 200 {object} APIResponse{data=BulkResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /tasks/bulk [post]
+func (h *Handler) BulkUpdateTasks(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	var req BulkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	request := task.BulkRequest{
+		Action:       task.BulkAction(req.Action),
+		IDs:          req.IDs,
+		CategoryID:   req.CategoryID,
+		AddTagIDs:    req.AddTagIDs,
+		RemoveTagIDs: req.RemoveTagIDs,
+		DryRun:       req.DryRun,
+		Partial:      req.Partial,
+	}
+	if req.Status != nil {
+		status := task.Status(*req.Status)
+		request.Status = &status
+	}
+	if req.Priority != nil {
+		priority := task.Priority(*req.Priority)
+		request.Priority = &priority
+	}
+	if req.Filter != nil {
+		request.Filter = bulkFilter(*req.Filter)
+	}
+
+	result, err := h.users(c).BulkUpdateTasks(userID.(int), request)
+	if err != nil {
+		status := bulkStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to update tasks",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	message := "Tasks updated successfully"
+	if result.DryRun {
+		message = "Dry run completed, no tasks were changed"
+	}
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: message,
+		Data:    ConvertToBulkResponse(result),
+	})
+}
+
+// bulkFilter converts the filter of a bulk request to a search query
+func bulkFilter(req BulkFilterRequest) *search.SearchQuery {
+	customFilters := make([]search.CustomFieldFilter, len(req.CustomFields))
+	for i, filter := range req.CustomFields {
+		customFilters[i] = search.CustomFieldFilter{
+			FieldID: filter.FieldID,
+			Value:   filter.Value,
+			Min:     filter.Min,
+			Max:     filter.Max,
+		}
+	}
+
+	return &search.SearchQuery{
+		Query:           req.Query,
+		ProjectID:       req.ProjectID,
+		Status:          req.Status,
+		Priority:        req.Priority,
+		CategoryID:      req.CategoryID,
+		TagNames:        req.TagNames,
+		IncludeArchived: req.IncludeArchived,
+		CustomFields:    customFilters,
+	}
+}
+
+// bulkStatus maps a bulk request error to an HTTP status code. A task that
+// fails a request which is not partial is a conflict.
+func bulkStatus(err error) int {
+	var itemErr *task.BulkItemError
+	message := err.Error()
+	switch {
+	case errors.As(err, &itemErr):
+		return http.StatusConflict
+	case strings.HasPrefix(message, "invalid"):
+		return http.StatusBadRequest
+	case strings.HasSuffix(message, "not found"):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
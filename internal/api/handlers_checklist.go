@@ -0,0 +1,278 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"learn-go-capstone/internal/task"
+)
+
+// GetChecklist handles getting the checklist of a task
+// @Summary Get a task checklist
+// @Description Get the checklist items of a task by position
+// @Tags checklist
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} APIResponse{data=[]ChecklistItemResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/checklist [get]
+func (h *Handler) GetChecklist(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid task ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	items, err := h.users(c).GetChecklist(userID.(int), taskID)
+	if err != nil {
+		status := checklistStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to get checklist",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	response := make([]ChecklistItemResponse, len(items))
+	for i, item := range items {
+		response[i] = ConvertToChecklistItemResponse(item)
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Checklist retrieved successfully",
+		Data:    response,
+	})
+}
+
+// AddChecklistItem handles adding an item to the checklist of a task
+// @Summary Add a checklist item
+// @Description Append an item to the checklist of a task
+// @Tags checklist
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param item body ChecklistItemRequest true "Checklist item data"
+// @Success 201 {object} APIResponse{data=ChecklistItemResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/checklist [post]
+func (h *Handler) AddChecklistItem(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid task ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var req ChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	item, err := h.users(c).AddChecklistItem(userID.(int), taskID, req.Text)
+	if err != nil {
+		status := checklistStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to add checklist item",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Success: true,
+		Message: "Checklist item added successfully",
+		Data:    ConvertToChecklistItemResponse(*item),
+	})
+}
+
+// UpdateChecklistItem handles changing a checklist item
+// @Summary Update a checklist item
+// @Description Change the text of a checklist item, tick it off or move it to another position; the other items shift to make room
+// @Tags checklist
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param item_id path int true "Checklist item ID"
+// @Param item body ChecklistItemPatchRequest true "Checklist item changes"
+// @Success 200 {object} APIResponse{data=ChecklistItemResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/checklist/{item_id} [patch]
+func (h *Handler) UpdateChecklistItem(c *gin.Context) {
+	userID, taskID, itemID, ok := checklistParams(c)
+	if !ok {
+		return
+	}
+
+	var req ChecklistItemPatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	item, err := h.users(c).UpdateChecklistItem(userID, taskID, itemID, task.ChecklistItemPatch{
+		Text:     req.Text,
+		Done:     req.Done,
+		Position: req.Position,
+	})
+	if err != nil {
+		status := checklistStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to update checklist item",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Checklist item updated successfully",
+		Data:    ConvertToChecklistItemResponse(*item),
+	})
+}
+
+// DeleteChecklistItem handles removing a checklist item
+// @Summary Delete a checklist item
+// @Description Remove an item from the checklist of a task
+// @Tags checklist
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param item_id path int true "Checklist item ID"
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/checklist/{item_id} [delete]
+func (h *Handler) DeleteChecklistItem(c *gin.Context) {
+	userID, taskID, itemID, ok := checklistParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.users(c).DeleteChecklistItem(userID, taskID, itemID); err != nil {
+		status := checklistStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to delete checklist item",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Checklist item deleted successfully",
+	})
+}
+
+// checklistParams reads the authenticated user, the task ID and the
+// checklist item ID of a request, writing an error response when any is missing
+func checklistParams(c *gin.Context) (userID, taskID, itemID int, ok bool) {
+	user, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return 0, 0, 0, false
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid task ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return 0, 0, 0, false
+	}
+
+	itemID, err = strconv.Atoi(c.Param("item_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid checklist item ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return 0, 0, 0, false
+	}
+
+	return user.(int), taskID, itemID, true
+}
+
+// checklistStatus maps a checklist error to an HTTP status
+func checklistStatus(err error) int {
+	message := err.Error()
+	switch {
+	case message == "access denied: task does not belong to user or user ID is missing" || strings.HasSuffix(message, "not found"):
+		return http.StatusNotFound
+	case strings.HasPrefix(message, "invalid"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
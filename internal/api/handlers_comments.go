@@ -0,0 +1,277 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetComments handles getting the comments of a task
+// @Summary Get task comments
+// @Description Get the comment threads of a task, oldest first, with replies nested below their parent
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} APIResponse{data=[]CommentResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/comments [get]
+func (h *Handler) GetComments(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid task ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	comments, err := h.comments(c).GetComments(userID.(int), taskID)
+	if err != nil {
+		status := commentStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to get comments",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	response := make([]CommentResponse, len(comments))
+	for i, comment := range comments {
+		response[i] = ConvertToCommentResponse(comment)
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Comments retrieved successfully",
+		Data:    response,
+	})
+}
+
+// CreateComment handles commenting on a task
+// @Summary Comment on a task
+// @Description Post a markdown comment on a task, or a reply to another comment. Users mentioned as @username are notified.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param comment body CommentRequest true "Comment data"
+// @Success 201 {object} APIResponse{data=CommentResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/comments [post]
+func (h *Handler) CreateComment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid task ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var req CommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	comment, err := h.comments(c).AddComment(userID.(int), taskID, req.ParentID, req.Body)
+	if err != nil {
+		status := commentStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to create comment",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Success: true,
+		Message: "Comment created successfully",
+		Data:    ConvertToCommentResponse(*comment),
+	})
+}
+
+// UpdateComment handles editing a comment
+// @Summary Edit a comment
+// @Description Replace the body of a comment. Only the author can edit a comment; users newly mentioned are notified.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param comment_id path int true "Comment ID"
+// @Param comment body CommentUpdateRequest true "New body"
+// @Success 200 {object} APIResponse{data=CommentResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/comments/{comment_id} [patch]
+func (h *Handler) UpdateComment(c *gin.Context) {
+	userID, taskID, commentID, ok := commentParams(c)
+	if !ok {
+		return
+	}
+
+	var req CommentUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	comment, err := h.comments(c).EditComment(userID, taskID, commentID, req.Body)
+	if err != nil {
+		status := commentStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to update comment",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Comment updated successfully",
+		Data:    ConvertToCommentResponse(*comment),
+	})
+}
+
+// DeleteComment handles deleting a comment
+// @Summary Delete a comment
+// @Description Delete a comment. Only the author can delete a comment; its replies are kept.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param comment_id path int true "Comment ID"
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/comments/{comment_id} [delete]
+func (h *Handler) DeleteComment(c *gin.Context) {
+	userID, taskID, commentID, ok := commentParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.comments(c).DeleteComment(userID, taskID, commentID); err != nil {
+		status := commentStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to delete comment",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Comment deleted successfully",
+	})
+}
+
+// commentParams reads the authenticated user and the task and comment IDs in
+// the path, writing an error response when one of them is missing or invalid
+func commentParams(c *gin.Context) (userID, taskID, commentID int, ok bool) {
+	user, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return 0, 0, 0, false
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid task ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return 0, 0, 0, false
+	}
+
+	commentID, err = strconv.Atoi(c.Param("comment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid comment ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return 0, 0, 0, false
+	}
+
+	return user.(int), taskID, commentID, true
+}
+
+// commentStatus maps a comment error to an HTTP status
+func commentStatus(err error) int {
+	message := err.Error()
+	switch {
+	case message == "access denied: task does not belong to user or user ID is missing" || strings.HasSuffix(message, "not found"):
+		return http.StatusNotFound
+	case message == "access denied: comment was written by another user":
+		return http.StatusForbidden
+	case strings.HasPrefix(message, "comment body") || message == "cannot reply to a deleted comment":
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
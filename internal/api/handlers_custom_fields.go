@@ -0,0 +1,382 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"learn-go-capstone/internal/task"
+)
+
+// customFields returns the custom field manager bound to the request context
+func (h *Handler) customFields(c *gin.Context) *task.CustomFieldManager {
+	return h.customFieldManager.WithContext(c.Request.Context())
+}
+
+// CreateCustomField handles custom field creation
+// @Summary Create a custom field
+// @Description Define a typed custom field for the tasks of a category or of a project. Only project owners can define the fields of a project and of its categories.
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param field body CustomFieldRequest true "Custom field data"
+// @Success 201 {object} APIResponse{data=CustomFieldResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /custom-fields [post]
+func (h *Handler) CreateCustomField(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	var req CustomFieldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	field, err := h.customFields(c).CreateField(userID.(int), req.Name, task.CustomFieldType(req.Type), req.Options, req.CategoryID, req.ProjectID)
+	if err != nil {
+		status := customFieldStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to create custom field",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Success: true,
+		Message: "Custom field created successfully",
+		Data:    ConvertToCustomFieldResponse(*field),
+	})
+}
+
+// GetCustomFields handles listing custom fields
+// @Summary Get custom fields
+// @Description Get the custom fields of a category or of a project, by name. Exactly one of category_id and project_id is required.
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param category_id query int false "List the fields of this category"
+// @Param project_id query int false "List the fields of this project"
+// @Success 200 {object} APIResponse{data=[]CustomFieldResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /custom-fields [get]
+func (h *Handler) GetCustomFields(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	var scope [2]*int
+	for i, name := range []string{"category_id", "project_id"} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Success: false,
+				Message: "Invalid " + strings.Replace(name, "_id", " ID", 1),
+				Error:   err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		scope[i] = &id
+	}
+
+	fields, err := h.customFields(c).GetFields(userID.(int), scope[0], scope[1])
+	if err != nil {
+		status := customFieldStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to get custom fields",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	response := make([]CustomFieldResponse, len(fields))
+	for i, field := range fields {
+		response[i] = ConvertToCustomFieldResponse(field)
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Custom fields retrieved successfully",
+		Data:    response,
+	})
+}
+
+// GetCustomField handles getting a custom field
+// @Summary Get a custom field
+// @Description Get a custom field by ID
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Custom field ID"
+// @Success 200 {object} APIResponse{data=CustomFieldResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /custom-fields/{id} [get]
+func (h *Handler) GetCustomField(c *gin.Context) {
+	userID, fieldID, ok := customFieldParams(c)
+	if !ok {
+		return
+	}
+
+	field, err := h.customFields(c).GetField(userID, fieldID)
+	if err != nil {
+		status := customFieldStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to get custom field",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Custom field retrieved successfully",
+		Data:    ConvertToCustomFieldResponse(*field),
+	})
+}
+
+// UpdateCustomField handles custom field updates
+// @Summary Update a custom field
+// @Description Rename a custom field and replace the options of an enum field. The type cannot change; tasks lose enum values that are no longer among the options.
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Custom field ID"
+// @Param field body CustomFieldRequest true "Updated custom field data"
+// @Success 200 {object} APIResponse{data=CustomFieldResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /custom-fields/{id} [put]
+func (h *Handler) UpdateCustomField(c *gin.Context) {
+	userID, fieldID, ok := customFieldParams(c)
+	if !ok {
+		return
+	}
+
+	var req CustomFieldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	field, err := h.customFields(c).UpdateField(userID, fieldID, req.Name, req.Options)
+	if err != nil {
+		status := customFieldStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to update custom field",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Custom field updated successfully",
+		Data:    ConvertToCustomFieldResponse(*field),
+	})
+}
+
+// DeleteCustomField handles custom field deletion
+// @Summary Delete a custom field
+// @Description Delete a custom field with its values on every task
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Custom field ID"
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /custom-fields/{id} [delete]
+func (h *Handler) DeleteCustomField(c *gin.Context) {
+	userID, fieldID, ok := customFieldParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.customFields(c).DeleteField(userID, fieldID); err != nil {
+		status := customFieldStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to delete custom field",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Custom field deleted successfully",
+	})
+}
+
+// SetTaskCustomFields handles setting custom field values on a task
+// @Summary Set custom field values of a task
+// @Description Set values of the custom fields of the task's category or project; an empty value clears a field. Nothing changes when a value is invalid. Returns every value of the task.
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param values body TaskCustomFieldsRequest true "Custom field values"
+// @Success 200 {object} APIResponse{data=[]CustomFieldValueResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/custom-fields [put]
+func (h *Handler) SetTaskCustomFields(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid task ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var req TaskCustomFieldsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	values := make([]task.CustomFieldValue, len(req.Values))
+	for i, value := range req.Values {
+		values[i] = task.CustomFieldValue{FieldID: value.FieldID, Value: value.Value}
+	}
+
+	result, err := h.customFields(c).SetTaskValues(userID.(int), taskID, values)
+	if err != nil {
+		status := customFieldStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to set custom field values",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	response := ConvertToCustomFieldValueResponses(result)
+	if response == nil {
+		response = []CustomFieldValueResponse{}
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Custom field values updated successfully",
+		Data:    response,
+	})
+}
+
+// customFieldParams reads the authenticated user and the custom field ID of
+// a request, writing an error response when either is missing
+func customFieldParams(c *gin.Context) (userID, fieldID int, ok bool) {
+	user, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return 0, 0, false
+	}
+
+	fieldID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid custom field ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return 0, 0, false
+	}
+
+	return user.(int), fieldID, true
+}
+
+// customFieldStatus maps a custom field error to an HTTP status
+func customFieldStatus(err error) int {
+	message := err.Error()
+	switch {
+	case message == "access denied: task does not belong to user or user ID is missing" || strings.HasSuffix(message, "not found"):
+		return http.StatusNotFound
+	case strings.HasPrefix(message, "access denied: only"):
+		return http.StatusForbidden
+	case strings.HasPrefix(message, "invalid"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
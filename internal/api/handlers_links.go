@@ -0,0 +1,212 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTaskLinks handles getting the links of a task
+// @Summary Get task links
+// @Description Get the links of a task in both directions, oldest first. Each link is named as seen from the task, such as blocked_by for a task that another task blocks.
+// @Tags links
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} APIResponse{data=[]TaskLinkResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/links [get]
+func (h *Handler) GetTaskLinks(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid task ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	links, err := h.users(c).GetUserTaskLinks(userID.(int), taskID)
+	if err != nil {
+		status := linkStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to get task links",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	response := make([]TaskLinkResponse, len(links))
+	for i, link := range links {
+		response[i] = ConvertToTaskLinkResponse(link)
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Task links retrieved successfully",
+		Data:    response,
+	})
+}
+
+// CreateTaskLink handles linking a task to another task
+// @Summary Link two tasks
+// @Description Link a task to another task as blocks, blocked_by, relates_to, duplicates, duplicated_by, clones or cloned_by. Blocking links are task dependencies and may not form a cycle. With close_duplicate, a duplicates link cancels the duplicate.
+// @Tags links
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param link body TaskLinkRequest true "Link data"
+// @Success 201 {object} APIResponse{data=TaskLinkResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/links [post]
+func (h *Handler) CreateTaskLink(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid task ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var req TaskLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	link, err := h.users(c).AddUserTaskLink(userID.(int), taskID, req.Type, req.TaskID, req.CloseDuplicate)
+	if err != nil {
+		status := linkStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to link tasks",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Success: true,
+		Message: "Tasks linked successfully",
+		Data:    ConvertToTaskLinkResponse(*link),
+	})
+}
+
+// DeleteTaskLink handles removing a link between two tasks
+// @Summary Unlink two tasks
+// @Description Remove a link from a task, named as seen from the task
+// @Tags links
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param type path string true "Link type as seen from the task, such as blocked_by"
+// @Param linked_id path int true "Linked task ID"
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/links/{type}/{linked_id} [delete]
+func (h *Handler) DeleteTaskLink(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid task ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	linkedTaskID, err := strconv.Atoi(c.Param("linked_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid linked task ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	if err := h.users(c).RemoveUserTaskLink(userID.(int), taskID, c.Param("type"), linkedTaskID); err != nil {
+		status := linkStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to unlink tasks",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Tasks unlinked successfully",
+	})
+}
+
+// linkStatus maps a task link error to an HTTP status
+func linkStatus(err error) int {
+	message := err.Error()
+	switch {
+	case message == "access denied: task does not belong to user or user ID is missing" || strings.HasSuffix(message, "not found"):
+		return http.StatusNotFound
+	case strings.HasPrefix(message, "invalid"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
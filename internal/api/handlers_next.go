@@ -0,0 +1,160 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"learn-go-capstone/internal/task"
+)
+
+// GetNextTasks handles the "what should I do next" ranking
+// @Summary Get next tasks
+// @Description Rank the open tasks of the authenticated user that are not blocked by unfinished dependencies, best first. The score combines priority, due-date urgency, the number of tasks blocked downstream, the estimate and staleness with the user's scoring weights. Archived and snoozed tasks are left out.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of tasks to return" default(10)
+// @Success 200 {object} APIResponse{data=[]NextTaskResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /tasks/next [get]
+func (h *Handler) GetNextTasks(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid limit",
+			Error:   "limit must be a positive number",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	ranked, err := h.users(c).GetUserNextTasks(userID.(int), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Success: false,
+			Message: "Failed to get next tasks",
+			Error:   err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Next tasks retrieved successfully",
+		Data:    ConvertToNextTaskResponses(ranked),
+	})
+}
+
+// GetScoringWeights handles getting the scoring weights
+// @Summary Get scoring weights
+// @Description Get the weights the authenticated user's next-up ranking uses, by factor
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} APIResponse{data=map[string]number}
+// @Failure 401 {object} ErrorResponse
+// @Router /scoring-weights [get]
+func (h *Handler) GetScoringWeights(c *gin.Context) {
+	h.writeScoringWeights(c, "Scoring weights retrieved successfully", "Failed to get scoring weights", func(userID int) (task.ScoringWeights, error) {
+		return h.users(c).GetUserScoringWeights(userID)
+	})
+}
+
+// SetScoringWeights handles setting the scoring weights
+// @Summary Set scoring weights
+// @Description Set the weights of the authenticated user's next-up ranking. Weights must not be negative and at least one must be positive; factors left out keep their default weight.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ScoringWeightsRequest true "Weights by factor"
+// @Success 200 {object} APIResponse{data=map[string]number}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /scoring-weights [put]
+func (h *Handler) SetScoringWeights(c *gin.Context) {
+	var req ScoringWeightsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	h.writeScoringWeights(c, "Scoring weights updated successfully", "Failed to update scoring weights", func(userID int) (task.ScoringWeights, error) {
+		return h.users(c).SetUserScoringWeights(userID, task.ScoringWeights(req.Weights))
+	})
+}
+
+// ResetScoringWeights handles resetting the scoring weights
+// @Summary Reset scoring weights
+// @Description Bring the authenticated user's next-up ranking back to the default weights
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} APIResponse{data=map[string]number}
+// @Failure 401 {object} ErrorResponse
+// @Router /scoring-weights [delete]
+func (h *Handler) ResetScoringWeights(c *gin.Context) {
+	h.writeScoringWeights(c, "Scoring weights reset successfully", "Failed to reset scoring weights", func(userID int) (task.ScoringWeights, error) {
+		if err := h.users(c).ResetUserScoringWeights(userID); err != nil {
+			return nil, err
+		}
+		return task.DefaultScoringWeights(), nil
+	})
+}
+
+// writeScoringWeights runs load for the authenticated user and writes the weights
+func (h *Handler) writeScoringWeights(c *gin.Context, message, failure string, load func(userID int) (task.ScoringWeights, error)) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	weights, err := load(userID.(int))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "invalid") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: failure,
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: message,
+		Data:    weights,
+	})
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MoveTask handles placing a task in a manually ordered list
+// @Summary Move task
+// @Description Place a task right after another one in a manually ordered list, or at the top when after_task_id is left out. Without project_id the list is the user's own order of their tasks; with it, the order of the project's tasks that all members share. The task after which it goes must already have a position in the list. Search with sort_by=position to list tasks in this order.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param request body TaskPositionRequest true "Where the task goes"
+// @Success 200 {object} APIResponse{data=TaskPositionResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/position [put]
+func (h *Handler) MoveTask(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid task ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var req TaskPositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	position, err := h.users(c).MoveUserTask(userID.(int), taskID, req.ProjectID, req.AfterTaskID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "access denied: task does not belong to user or user ID is missing" || strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		} else if strings.HasPrefix(err.Error(), "invalid") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to move task",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Task moved successfully",
+		Data: TaskPositionResponse{
+			TaskID:    position.TaskID,
+			ProjectID: position.ProjectID,
+			Key:       position.Key,
+		},
+	})
+}
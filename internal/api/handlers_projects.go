@@ -0,0 +1,478 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"learn-go-capstone/internal/task"
+)
+
+// projects returns the project manager bound to the request context
+func (h *Handler) projects(c *gin.Context) *task.ProjectManager {
+	return h.projectManager.WithContext(c.Request.Context())
+}
+
+// CreateProject handles project creation
+// @Summary Create a project
+// @Description Create a project owned by the authenticated user. Its settings give a default priority to new tasks and can narrow the workflow for its tasks.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param project body ProjectRequest true "Project data"
+// @Success 201 {object} APIResponse{data=ProjectResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /projects [post]
+func (h *Handler) CreateProject(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	var req ProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	project, err := h.projects(c).CreateProject(userID.(int), req.Name, req.Description, ConvertToProjectSettings(req.Settings))
+	if err != nil {
+		status := projectStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to create project",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Success: true,
+		Message: "Project created successfully",
+		Data:    ConvertToProjectResponse(*project),
+	})
+}
+
+// GetProjects handles listing the projects of the user
+// @Summary Get projects
+// @Description Get the projects the authenticated user is a member of, by name
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} APIResponse{data=[]ProjectResponse}
+// @Failure 401 {object} ErrorResponse
+// @Router /projects [get]
+func (h *Handler) GetProjects(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	projects, err := h.projects(c).GetUserProjects(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Success: false,
+			Message: "Failed to get projects",
+			Error:   err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	response := make([]ProjectResponse, len(projects))
+	for i, project := range projects {
+		response[i] = ConvertToProjectResponse(project)
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Projects retrieved successfully",
+		Data:    response,
+	})
+}
+
+// GetProject handles getting a project
+// @Summary Get a project
+// @Description Get a project of the authenticated user with its members
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Success 200 {object} APIResponse{data=ProjectResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /projects/{id} [get]
+func (h *Handler) GetProject(c *gin.Context) {
+	userID, projectID, ok := projectParams(c)
+	if !ok {
+		return
+	}
+
+	project, err := h.projects(c).GetProject(userID, projectID)
+	if err != nil {
+		status := projectStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to get project",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Project retrieved successfully",
+		Data:    ConvertToProjectResponse(*project),
+	})
+}
+
+// UpdateProject handles project updates
+// @Summary Update a project
+// @Description Replace the name, description and settings of a project. Only owners can update a project.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Param project body ProjectRequest true "Updated project data"
+// @Success 200 {object} APIResponse{data=ProjectResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /projects/{id} [put]
+func (h *Handler) UpdateProject(c *gin.Context) {
+	userID, projectID, ok := projectParams(c)
+	if !ok {
+		return
+	}
+
+	var req ProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	project, err := h.projects(c).UpdateProject(userID, projectID, req.Name, req.Description, ConvertToProjectSettings(req.Settings))
+	if err != nil {
+		status := projectStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to update project",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Project updated successfully",
+		Data:    ConvertToProjectResponse(*project),
+	})
+}
+
+// DeleteProject handles project deletion
+// @Summary Delete a project
+// @Description Delete a project with its categories and tags. Only owners can delete a project, and only once its tasks are deleted or moved out.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /projects/{id} [delete]
+func (h *Handler) DeleteProject(c *gin.Context) {
+	userID, projectID, ok := projectParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.projects(c).DeleteProject(userID, projectID); err != nil {
+		status := projectStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to delete project",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Project deleted successfully",
+	})
+}
+
+// GetProjectMembers handles listing the members of a project
+// @Summary Get project members
+// @Description Get the members of a project of the authenticated user
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Success 200 {object} APIResponse{data=[]ProjectMemberResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /projects/{id}/members [get]
+func (h *Handler) GetProjectMembers(c *gin.Context) {
+	userID, projectID, ok := projectParams(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.projects(c).GetMembers(userID, projectID)
+	if err != nil {
+		status := projectStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to get project members",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Project members retrieved successfully",
+		Data:    ConvertToProjectMemberResponses(members),
+	})
+}
+
+// SetProjectMember handles adding a member to a project
+// @Summary Set a project member
+// @Description Add an active user to a project, or change the role of a member. Only owners can manage members, and a project always keeps one owner.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Param member body ProjectMemberRequest true "Member"
+// @Success 200 {object} APIResponse{data=[]ProjectMemberResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /projects/{id}/members [put]
+func (h *Handler) SetProjectMember(c *gin.Context) {
+	userID, projectID, ok := projectParams(c)
+	if !ok {
+		return
+	}
+
+	var req ProjectMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	members, err := h.projects(c).SetMember(userID, projectID, req.Username, task.ProjectRole(req.Role))
+	if err != nil {
+		status := projectStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to set project member",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Project member set successfully",
+		Data:    ConvertToProjectMemberResponses(members),
+	})
+}
+
+// RemoveProjectMember handles removing a member from a project
+// @Summary Remove a project member
+// @Description Remove a user from a project. Owners can remove any member and every member can leave, as long as the project keeps an owner.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Param user_id path int true "User ID of the member"
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /projects/{id}/members/{user_id} [delete]
+func (h *Handler) RemoveProjectMember(c *gin.Context) {
+	userID, projectID, ok := projectParams(c)
+	if !ok {
+		return
+	}
+
+	memberID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid user ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	if err := h.projects(c).RemoveMember(userID, projectID, memberID); err != nil {
+		status := projectStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to remove project member",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Project member removed successfully",
+	})
+}
+
+// projectParams reads the authenticated user and the project ID in the path,
+// writing an error response when one of them is missing or invalid
+func projectParams(c *gin.Context) (userID, projectID int, ok bool) {
+	user, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return 0, 0, false
+	}
+
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid project ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return 0, 0, false
+	}
+
+	return user.(int), projectID, true
+}
+
+// projectScope reads the optional project_id query parameter that scopes a
+// listing to a project. It writes an error response and returns false when the
+// parameter is invalid or the user is not a member of the project.
+func (h *Handler) projectScope(c *gin.Context, message string) (*int, bool) {
+	value := c.Query("project_id")
+	if value == "" {
+		return nil, true
+	}
+
+	projectID, err := strconv.Atoi(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid project ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return nil, false
+	}
+
+	if !h.checkProjectAccess(c, &projectID, message) {
+		return nil, false
+	}
+	return &projectID, true
+}
+
+// checkProjectAccess checks that the authenticated user is a member of the
+// project, if any, writing an error response with message when they are not
+func (h *Handler) checkProjectAccess(c *gin.Context, projectID *int, message string) bool {
+	if projectID == nil {
+		return true
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return false
+	}
+
+	if err := h.projects(c).CheckMember(userID.(int), *projectID); err != nil {
+		status := projectStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: message,
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return false
+	}
+
+	return true
+}
+
+// projectStatus maps a project error to an HTTP status
+func projectStatus(err error) int {
+	message := err.Error()
+	switch {
+	case message == "access denied: task does not belong to user or user ID is missing" || strings.HasSuffix(message, "not found"):
+		return http.StatusNotFound
+	case strings.HasPrefix(message, "access denied: only"):
+		return http.StatusForbidden
+	case strings.HasPrefix(message, "invalid project"):
+		return http.StatusBadRequest
+	case strings.Contains(message, "still has"):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
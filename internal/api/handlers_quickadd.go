@@ -0,0 +1,103 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"learn-go-capstone/internal/quickadd"
+)
+
+// QuickAddTask handles creating a task from a quick-add line
+// @Summary Quick-add a task
+// @Description Create a task from a single line. !low to !urgent (or !1 to !4) sets the priority, #name adds a tag, @name files the task under an existing category, and phrases such as "tomorrow 5pm", "next friday", "in 3 days" or "every month" set the due date and recurrence. The rest of the line is the title. Missing tags are created.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body QuickTaskRequest true "Quick-add line"
+// @Success 201 {object} APIResponse{data=TaskResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/quick [post]
+func (h *Handler) QuickAddTask(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	var req QuickTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	location := time.UTC
+	if req.Timezone != "" {
+		loaded, err := time.LoadLocation(req.Timezone)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Success: false,
+				Message: "Invalid time zone",
+				Error:   err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		location = loaded
+	}
+
+	entry, err := quickadd.Parse(req.Text, time.Now().In(location))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid quick-add text",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	createdTask, err := h.users(c).QuickAddUserTask(userID.(int), entry, req.Description, req.ProjectID)
+	if err != nil {
+		status := quickAddStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to create task",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Success: true,
+		Message: "Task created successfully",
+		Data:    ConvertToTaskResponse(*createdTask),
+	})
+}
+
+// quickAddStatus maps a quick-add error to an HTTP status code
+func quickAddStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.HasPrefix(message, "invalid"):
+		return http.StatusBadRequest
+	case strings.HasSuffix(message, "not found"):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
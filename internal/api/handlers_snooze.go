@@ -0,0 +1,93 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"learn-go-capstone/internal/quickadd"
+	"learn-go-capstone/internal/task"
+)
+
+// SnoozeTask handles snoozing a task
+// @Summary Snooze task
+// @Description Hide a task from the default listings, ready tasks and overdue reminders until a given time. Give either until or a phrase in for, such as "3 days", "for 2 weeks" or "until monday 9am". The owner is notified when the task wakes up.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param request body SnoozeRequest true "When the task wakes up"
+// @Success 200 {object} APIResponse{data=TaskResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/snooze [post]
+func (h *Handler) SnoozeTask(c *gin.Context) {
+	var req SnoozeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	until, err := snoozeUntil(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid snooze",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	h.updateTaskState(c, "Task snoozed successfully", func(userID, taskID int) (*task.Task, error) {
+		return h.users(c).SnoozeUserTask(userID, taskID, until)
+	})
+}
+
+// UnsnoozeTask handles waking up a snoozed task
+// @Summary Unsnooze task
+// @Description Bring a snoozed task back into the listings right away
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} APIResponse{data=TaskResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/snooze [delete]
+func (h *Handler) UnsnoozeTask(c *gin.Context) {
+	h.updateTaskState(c, "Task unsnoozed successfully", h.users(c).UnsnoozeUserTask)
+}
+
+// snoozeUntil works out when a snooze request ends
+func snoozeUntil(req SnoozeRequest) (time.Time, error) {
+	switch {
+	case req.Until != nil && req.For != "":
+		return time.Time{}, errors.New("give either until or for, not both")
+	case req.Until != nil:
+		return *req.Until, nil
+	case req.For == "":
+		return time.Time{}, errors.New("until or for is required")
+	}
+
+	location := time.UTC
+	if req.Timezone != "" {
+		loaded, err := time.LoadLocation(req.Timezone)
+		if err != nil {
+			return time.Time{}, err
+		}
+		location = loaded
+	}
+
+	return quickadd.ParseSnooze(req.For, time.Now().In(location))
+}
@@ -0,0 +1,590 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"learn-go-capstone/internal/task"
+)
+
+// sprints returns the sprint manager bound to the request context
+func (h *Handler) sprints(c *gin.Context) *task.SprintManager {
+	return h.sprintManager.WithContext(c.Request.Context())
+}
+
+// CreateSprint handles sprint creation
+// @Summary Create a sprint
+// @Description Create a sprint over the authenticated user's tasks or, with project_id, over the tasks of one of their projects. Dates are calendar dates, both included.
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param sprint body SprintRequest true "Sprint data"
+// @Success 201 {object} APIResponse{data=SprintResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /sprints [post]
+func (h *Handler) CreateSprint(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	var req SprintRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	startDate, endDate, ok := sprintDates(c, req)
+	if !ok {
+		return
+	}
+
+	sprint, err := h.sprints(c).CreateSprint(userID.(int), req.Name, req.Goal, startDate, endDate, req.ProjectID)
+	if err != nil {
+		status := sprintStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to create sprint",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Success: true,
+		Message: "Sprint created successfully",
+		Data:    ConvertToSprintResponse(*sprint),
+	})
+}
+
+// GetSprints handles listing sprints
+// @Summary Get sprints
+// @Description Get the personal sprints of the authenticated user or, with project_id, the sprints of one of their projects, by start date and without their tasks
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param project_id query int false "List the sprints of this project"
+// @Success 200 {object} APIResponse{data=[]SprintResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /sprints [get]
+func (h *Handler) GetSprints(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	projectID, ok := h.projectScope(c, "Failed to get sprints")
+	if !ok {
+		return
+	}
+
+	sprints, err := h.sprints(c).GetSprints(userID.(int), projectID)
+	if err != nil {
+		status := sprintStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to get sprints",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	response := make([]SprintResponse, len(sprints))
+	for i, sprint := range sprints {
+		response[i] = ConvertToSprintResponse(sprint)
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Sprints retrieved successfully",
+		Data:    response,
+	})
+}
+
+// GetSprint handles getting a sprint
+// @Summary Get a sprint
+// @Description Get a sprint with the tasks currently planned into it
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Sprint ID"
+// @Success 200 {object} APIResponse{data=SprintResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /sprints/{id} [get]
+func (h *Handler) GetSprint(c *gin.Context) {
+	userID, sprintID, ok := sprintParams(c)
+	if !ok {
+		return
+	}
+
+	sprint, err := h.sprints(c).GetSprint(userID, sprintID)
+	if err != nil {
+		status := sprintStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to get sprint",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Sprint retrieved successfully",
+		Data:    ConvertToSprintResponse(*sprint),
+	})
+}
+
+// UpdateSprint handles sprint updates
+// @Summary Update a sprint
+// @Description Replace the name, goal and dates of an open sprint. Only the sprint owner and project owners can update a sprint.
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Sprint ID"
+// @Param sprint body SprintRequest true "Updated sprint data"
+// @Success 200 {object} APIResponse{data=SprintResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /sprints/{id} [put]
+func (h *Handler) UpdateSprint(c *gin.Context) {
+	userID, sprintID, ok := sprintParams(c)
+	if !ok {
+		return
+	}
+
+	var req SprintRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	startDate, endDate, ok := sprintDates(c, req)
+	if !ok {
+		return
+	}
+
+	sprint, err := h.sprints(c).UpdateSprint(userID, sprintID, req.Name, req.Goal, startDate, endDate)
+	if err != nil {
+		status := sprintStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to update sprint",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Sprint updated successfully",
+		Data:    ConvertToSprintResponse(*sprint),
+	})
+}
+
+// DeleteSprint handles sprint deletion
+// @Summary Delete a sprint
+// @Description Delete a sprint. Its tasks go back to the backlog. Only the sprint owner and project owners can delete a sprint.
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Sprint ID"
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /sprints/{id} [delete]
+func (h *Handler) DeleteSprint(c *gin.Context) {
+	userID, sprintID, ok := sprintParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.sprints(c).DeleteSprint(userID, sprintID); err != nil {
+		status := sprintStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to delete sprint",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Sprint deleted successfully",
+	})
+}
+
+// AddSprintTask handles planning a task into a sprint
+// @Summary Add a task to a sprint
+// @Description Plan a task into an open sprint, taking it out of any other sprint. The task must belong to the project of the sprint, or be a personal task for a personal sprint.
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Sprint ID"
+// @Param task body SprintTaskRequest true "Task to plan"
+// @Success 200 {object} APIResponse{data=SprintResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /sprints/{id}/tasks [post]
+func (h *Handler) AddSprintTask(c *gin.Context) {
+	userID, sprintID, ok := sprintParams(c)
+	if !ok {
+		return
+	}
+
+	var req SprintTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	sprint, err := h.sprints(c).AddTask(userID, sprintID, req.TaskID)
+	if err != nil {
+		status := sprintStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to add task to sprint",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Task added to sprint successfully",
+		Data:    ConvertToSprintResponse(*sprint),
+	})
+}
+
+// RemoveSprintTask handles taking a task out of a sprint
+// @Summary Remove a task from a sprint
+// @Description Take a task out of an open sprint, back to the backlog
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Sprint ID"
+// @Param task_id path int true "Task ID"
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /sprints/{id}/tasks/{task_id} [delete]
+func (h *Handler) RemoveSprintTask(c *gin.Context) {
+	userID, sprintID, ok := sprintParams(c)
+	if !ok {
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("task_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid task ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	if err := h.sprints(c).RemoveTask(userID, sprintID, taskID); err != nil {
+		status := sprintStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to remove task from sprint",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Task removed from sprint successfully",
+	})
+}
+
+// CloseSprint handles closing a sprint
+// @Summary Close a sprint
+// @Description Close a sprint. Tasks that are neither completed nor cancelled carry over into the open sprint carry_over_to, or go back to the backlog. Only the sprint owner and project owners can close a sprint.
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Sprint ID"
+// @Param close body SprintCloseRequest false "Carry-over sprint"
+// @Success 200 {object} APIResponse{data=SprintResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /sprints/{id}/close [post]
+func (h *Handler) CloseSprint(c *gin.Context) {
+	userID, sprintID, ok := sprintParams(c)
+	if !ok {
+		return
+	}
+
+	// The body is optional: without one, unfinished tasks go back to the backlog
+	var req SprintCloseRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Success: false,
+				Message: "Invalid request data",
+				Error:   err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+	}
+
+	sprint, err := h.sprints(c).CloseSprint(userID, sprintID, req.CarryOverTo)
+	if err != nil {
+		status := sprintStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to close sprint",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Sprint closed successfully",
+		Data:    ConvertToSprintResponse(*sprint),
+	})
+}
+
+// GetSprintReport handles getting the report of a sprint
+// @Summary Get a sprint report
+// @Description Get the daily burndown of a sprint, rebuilt from the task history up to today or to its closing, with the velocity of the sprints of its project or owner that closed by its end
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Sprint ID"
+// @Param unit query string false "Count tasks or sum estimated minutes" Enums(tasks, estimate)
+// @Success 200 {object} APIResponse{data=SprintReportResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /sprints/{id}/report [get]
+func (h *Handler) GetSprintReport(c *gin.Context) {
+	userID, sprintID, ok := sprintParams(c)
+	if !ok {
+		return
+	}
+
+	unit, err := task.ParseBurndownUnit(c.Query("unit"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid report unit",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	report, err := h.sprints(c).GetReport(userID, sprintID, unit)
+	if err != nil {
+		status := sprintStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to get sprint report",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Sprint report retrieved successfully",
+		Data:    ConvertToSprintReportResponse(*report),
+	})
+}
+
+// GetSprintVelocity handles getting the velocity of past sprints
+// @Summary Get sprint velocity
+// @Description Get what each closed sprint committed to and completed, oldest first, for the personal sprints of the authenticated user or, with project_id, for the sprints of one of their projects
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param project_id query int false "Report on the sprints of this project"
+// @Param unit query string false "Count tasks or sum estimated minutes" Enums(tasks, estimate)
+// @Success 200 {object} APIResponse{data=VelocityResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /sprints/velocity [get]
+func (h *Handler) GetSprintVelocity(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	unit, err := task.ParseBurndownUnit(c.Query("unit"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid report unit",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	projectID, ok := h.projectScope(c, "Failed to get sprint velocity")
+	if !ok {
+		return
+	}
+
+	report, err := h.sprints(c).GetVelocity(userID.(int), projectID, unit)
+	if err != nil {
+		status := sprintStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to get sprint velocity",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Sprint velocity retrieved successfully",
+		Data:    ConvertToVelocityResponse(*report),
+	})
+}
+
+// sprintParams reads the authenticated user and the sprint ID in the path,
+// writing an error response when one of them is missing or invalid
+func sprintParams(c *gin.Context) (userID, sprintID int, ok bool) {
+	user, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return 0, 0, false
+	}
+
+	sprintID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid sprint ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return 0, 0, false
+	}
+
+	return user.(int), sprintID, true
+}
+
+// sprintDates parses the dates of a sprint request, writing an error
+// response when one of them is not a date
+func sprintDates(c *gin.Context, req SprintRequest) (startDate, endDate time.Time, ok bool) {
+	startDate, err := time.Parse(reportDateLayout, req.StartDate)
+	if err == nil {
+		endDate, err = time.Parse(reportDateLayout, req.EndDate)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid sprint dates",
+			Error:   fmt.Sprintf("dates must be formatted as %s: %v", reportDateLayout, err),
+			Code:    http.StatusBadRequest,
+		})
+		return time.Time{}, time.Time{}, false
+	}
+
+	return startDate, endDate, true
+}
+
+// sprintStatus maps a sprint manager error to an HTTP status code
+func sprintStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.HasSuffix(message, "not found"):
+		return http.StatusNotFound
+	case strings.HasPrefix(message, "access denied: only"):
+		return http.StatusForbidden
+	case strings.HasPrefix(message, "invalid"):
+		return http.StatusBadRequest
+	case strings.HasSuffix(message, "already closed"):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,384 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"learn-go-capstone/internal/task"
+)
+
+// templates returns the template manager bound to the request context
+func (h *Handler) templates(c *gin.Context) *task.TemplateManager {
+	return h.templateManager.WithContext(c.Request.Context())
+}
+
+// CreateTemplate handles template creation
+// @Summary Create a template
+// @Description Create a task template for the authenticated user or, with project_id, for one of their projects. Categories and tags must belong to the project of the template.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param template body TemplateRequest true "Template data"
+// @Success 201 {object} APIResponse{data=TemplateResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /templates [post]
+func (h *Handler) CreateTemplate(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	var req TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	template, err := h.templates(c).CreateTemplate(userID.(int), req.Name, req.Description, templateTasks(req.Tasks), req.ProjectID)
+	if err != nil {
+		status := templateStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to create template",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Success: true,
+		Message: "Template created successfully",
+		Data:    ConvertToTemplateResponse(*template),
+	})
+}
+
+// GetTemplates handles listing templates
+// @Summary Get templates
+// @Description Get the personal templates of the authenticated user or, with project_id, the templates of one of their projects, by name
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param project_id query int false "List the templates of this project"
+// @Success 200 {object} APIResponse{data=[]TemplateResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /templates [get]
+func (h *Handler) GetTemplates(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	projectID, ok := h.projectScope(c, "Failed to get templates")
+	if !ok {
+		return
+	}
+
+	templates, err := h.templates(c).GetTemplates(userID.(int), projectID)
+	if err != nil {
+		status := templateStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to get templates",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	response := make([]TemplateResponse, len(templates))
+	for i, template := range templates {
+		response[i] = ConvertToTemplateResponse(template)
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Templates retrieved successfully",
+		Data:    response,
+	})
+}
+
+// GetTemplate handles getting a template
+// @Summary Get a template
+// @Description Get a template by ID
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Template ID"
+// @Success 200 {object} APIResponse{data=TemplateResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /templates/{id} [get]
+func (h *Handler) GetTemplate(c *gin.Context) {
+	userID, templateID, ok := templateParams(c)
+	if !ok {
+		return
+	}
+
+	template, err := h.templates(c).GetTemplate(userID, templateID)
+	if err != nil {
+		status := templateStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to get template",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Template retrieved successfully",
+		Data:    ConvertToTemplateResponse(*template),
+	})
+}
+
+// UpdateTemplate handles template updates
+// @Summary Update a template
+// @Description Replace the name, description and tasks of a template. Only the template owner and project owners can update a template; tasks created from it are not changed.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Template ID"
+// @Param template body TemplateRequest true "Updated template data"
+// @Success 200 {object} APIResponse{data=TemplateResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /templates/{id} [put]
+func (h *Handler) UpdateTemplate(c *gin.Context) {
+	userID, templateID, ok := templateParams(c)
+	if !ok {
+		return
+	}
+
+	var req TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	template, err := h.templates(c).UpdateTemplate(userID, templateID, req.Name, req.Description, templateTasks(req.Tasks))
+	if err != nil {
+		status := templateStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to update template",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Template updated successfully",
+		Data:    ConvertToTemplateResponse(*template),
+	})
+}
+
+// DeleteTemplate handles template deletion
+// @Summary Delete a template
+// @Description Delete a template. Tasks created from it are kept. Only the template owner and project owners can delete a template.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Template ID"
+// @Success 200 {object} APIResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /templates/{id} [delete]
+func (h *Handler) DeleteTemplate(c *gin.Context) {
+	userID, templateID, ok := templateParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.templates(c).DeleteTemplate(userID, templateID); err != nil {
+		status := templateStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to delete template",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Template deleted successfully",
+	})
+}
+
+// InstantiateTemplate handles creating the tasks of a template
+// @Summary Instantiate a template
+// @Description Create the tasks of a template with their tags, checklists, subtasks and dependencies, all or none. Due dates are placed relative to start_date, which defaults to today.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Template ID"
+// @Param instantiation body InstantiateTemplateRequest false "Start date"
+// @Success 201 {object} APIResponse{data=[]TaskResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /templates/{id}/instantiate [post]
+func (h *Handler) InstantiateTemplate(c *gin.Context) {
+	userID, templateID, ok := templateParams(c)
+	if !ok {
+		return
+	}
+
+	// The body is optional
+	var req InstantiateTemplateRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Success: false,
+				Message: "Invalid request data",
+				Error:   err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+	}
+
+	start := time.Now()
+	if req.StartDate != "" {
+		date, err := time.Parse(reportDateLayout, req.StartDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Success: false,
+				Message: "Invalid start date",
+				Error:   fmt.Sprintf("dates must be formatted as %s: %v", reportDateLayout, err),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		start = date
+	}
+
+	tasks, err := h.templates(c).Instantiate(userID, templateID, start)
+	if err != nil {
+		status := templateStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to instantiate template",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	response := make([]TaskResponse, len(tasks))
+	for i, t := range tasks {
+		response[i] = ConvertToTaskResponse(t)
+	}
+
+	c.JSON(http.StatusCreated, APIResponse{
+		Success: true,
+		Message: "Template instantiated successfully",
+		Data:    response,
+	})
+}
+
+// templateTasks converts the tasks of a template request
+func templateTasks(requests []TemplateTaskRequest) []task.TemplateTask {
+	tasks := make([]task.TemplateTask, len(requests))
+	for i, req := range requests {
+		tasks[i] = task.TemplateTask{
+			Title:         req.Title,
+			Description:   req.Description,
+			Priority:      task.Priority(req.Priority),
+			CategoryID:    req.CategoryID,
+			TagIDs:        req.TagIDs,
+			DueOffsetDays: req.DueOffsetDays,
+			Checklist:     req.Checklist,
+			Parent:        req.Parent,
+			DependsOn:     req.DependsOn,
+		}
+	}
+	return tasks
+}
+
+// templateParams reads the authenticated user and the template ID in the
+// path, writing an error response when one of them is missing or invalid
+func templateParams(c *gin.Context) (userID, templateID int, ok bool) {
+	user, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return 0, 0, false
+	}
+
+	templateID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid template ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return 0, 0, false
+	}
+
+	return user.(int), templateID, true
+}
+
+// templateStatus maps a template manager error to an HTTP status code
+func templateStatus(err error) int {
+	message := err.Error()
+	switch {
+	case strings.HasSuffix(message, "not found"):
+		return http.StatusNotFound
+	case strings.HasPrefix(message, "access denied: only"):
+		return http.StatusForbidden
+	case strings.HasPrefix(message, "invalid"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,335 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"learn-go-capstone/internal/task"
+)
+
+// reportDateLayout is accepted for the report range besides RFC 3339
+const reportDateLayout = "2006-01-02"
+
+// GetTaskTime handles getting the time tracked on a task
+// @Summary Get the time tracked on a task
+// @Description Get the time entries of a task, the total tracked time and the estimate. A running timer is listed but not counted.
+// @Tags time
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} APIResponse{data=TaskTimeResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/time [get]
+func (h *Handler) GetTaskTime(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid task ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	taskTime, err := h.users(c).GetUserTaskTime(userID.(int), taskID)
+	if err != nil {
+		status := timeTrackingStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to get task time",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	response := TaskTimeResponse{
+		TaskID:          taskTime.TaskID,
+		EstimateMinutes: taskTime.EstimateMinutes,
+		TrackedSeconds:  int(taskTime.Tracked / time.Second),
+		Entries:         make([]TimeEntryResponse, len(taskTime.Entries)),
+	}
+	for i, entry := range taskTime.Entries {
+		response.Entries[i] = ConvertToTimeEntryResponse(entry)
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Task time retrieved successfully",
+		Data:    response,
+	})
+}
+
+// StartTimer handles starting a timer on a task
+// @Summary Start a timer on a task
+// @Description Start tracking time on a task. Each user can only run one timer at a time.
+// @Tags time
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param timer body TimerRequest false "Timer note"
+// @Success 201 {object} APIResponse{data=TimeEntryResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /tasks/{id}/time/start [post]
+func (h *Handler) StartTimer(c *gin.Context) {
+	var req TimerRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Success: false,
+				Message: "Invalid request data",
+				Error:   err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+	}
+
+	h.trackTime(c, http.StatusCreated, "Timer started successfully", func(userID, taskID int) (*task.TimeEntry, error) {
+		return h.users(c).StartUserTimer(userID, taskID, req.Note)
+	})
+}
+
+// StopTimer handles stopping the running timer on a task
+// @Summary Stop the timer on a task
+// @Description Stop the running timer of the authenticated user on a task and record the time entry
+// @Tags time
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} APIResponse{data=TimeEntryResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /tasks/{id}/time/stop [post]
+func (h *Handler) StopTimer(c *gin.Context) {
+	h.trackTime(c, http.StatusOK, "Timer stopped successfully", func(userID, taskID int) (*task.TimeEntry, error) {
+		return h.users(c).StopUserTimer(userID, taskID)
+	})
+}
+
+// LogTime handles logging time on a task by hand
+// @Summary Log time on a task
+// @Description Record time spent on a task without running a timer
+// @Tags time
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param entry body TimeEntryRequest true "Time spent"
+// @Success 201 {object} APIResponse{data=TimeEntryResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/time [post]
+func (h *Handler) LogTime(c *gin.Context) {
+	var req TimeEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid request data",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	duration := time.Duration(req.Minutes) * time.Minute
+	startedAt := time.Now().Add(-duration)
+	if req.StartedAt != nil {
+		startedAt = *req.StartedAt
+	}
+
+	h.trackTime(c, http.StatusCreated, "Time logged successfully", func(userID, taskID int) (*task.TimeEntry, error) {
+		return h.users(c).LogUserTime(userID, taskID, startedAt, duration, req.Note)
+	})
+}
+
+// trackTime runs track on the task in the path and writes the resulting time entry
+func (h *Handler) trackTime(c *gin.Context, successStatus int, message string, track func(userID, taskID int) (*task.TimeEntry, error)) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid task ID",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	entry, err := track(userID.(int), taskID)
+	if err != nil {
+		status := timeTrackingStatus(err)
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to track time",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(successStatus, APIResponse{
+		Success: true,
+		Message: message,
+		Data:    ConvertToTimeEntryResponse(*entry),
+	})
+}
+
+// timeTrackingStatus maps a time tracking error to an HTTP status
+func timeTrackingStatus(err error) int {
+	message := err.Error()
+	switch {
+	case message == "access denied: task does not belong to user or user ID is missing" || strings.HasSuffix(message, "not found"):
+		return http.StatusNotFound
+	case strings.Contains(message, "already running") || message == "no timer is running" || strings.HasPrefix(message, "the running timer is on task"):
+		return http.StatusConflict
+	case strings.HasPrefix(message, "invalid ") || strings.Contains(message, "cannot start in the future"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GetTimeReport handles aggregating the time tracked on the user's tasks
+// @Summary Get a time report
+// @Description Aggregate the time tracked on the authenticated user's tasks per task, category or user. from and to accept RFC 3339 timestamps or dates; a date in to includes that whole day. The range defaults to the last 30 days.
+// @Tags time
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param group query string false "Grouping: task, category or user" default(task)
+// @Param from query string false "Start of the range" example(2024-01-01)
+// @Param to query string false "End of the range" example(2024-01-31)
+// @Success 200 {object} APIResponse{data=[]TimeReportRowResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /time/report [get]
+func (h *Handler) GetTimeReport(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	grouping, err := task.ParseReportGrouping(c.DefaultQuery("group", string(task.GroupByTask)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Success: false,
+			Message: "Invalid report grouping",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	to := time.Now()
+	if value := c.Query("to"); value != "" {
+		if to, err = parseReportTime(value, true); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Success: false,
+				Message: "Invalid report range",
+				Error:   err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+	}
+
+	from := to.AddDate(0, 0, -30)
+	if value := c.Query("from"); value != "" {
+		if from, err = parseReportTime(value, false); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Success: false,
+				Message: "Invalid report range",
+				Error:   err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+	}
+
+	report, err := h.users(c).GetUserTimeReport(userID.(int), grouping, from, to)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "report range") {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to get time report",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	response := make([]TimeReportRowResponse, len(report))
+	for i, row := range report {
+		response[i] = TimeReportRowResponse{
+			GroupID:         row.GroupID,
+			Name:            row.Name,
+			TrackedSeconds:  int(row.Tracked / time.Second),
+			EstimateMinutes: row.EstimateMinutes,
+			Tasks:           row.Tasks,
+			Entries:         row.Entries,
+		}
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: "Time report retrieved successfully",
+		Data:    response,
+	})
+}
+
+// parseReportTime parses an RFC 3339 timestamp or a date. A date that ends
+// the range is moved to the following midnight so that the day is included.
+func parseReportTime(value string, end bool) (time.Time, error) {
+	if date, err := time.Parse(reportDateLayout, value); err == nil {
+		if end {
+			return date.AddDate(0, 0, 1), nil
+		}
+		return date, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
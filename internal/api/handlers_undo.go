@@ -0,0 +1,86 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"learn-go-capstone/internal/task"
+)
+
+// UndoCommand handles undoing the user's latest change to their tasks
+// @Summary Undo the latest change
+// @Description Reverse the latest create, update, status change or delete the authenticated user made to their tasks that has not been undone. Undoing a delete brings back the task's tags, category and dependencies. Only the fields the change made are put back; if one of them has been changed again since, or the workflow does not allow the status to be put back, nothing is undone and 409 is returned. Undo repeatedly to step further back.
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} APIResponse{data=CommandResponse}
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /undo [post]
+func (h *Handler) UndoCommand(c *gin.Context) {
+	h.replayCommand(c, true)
+}
+
+// RedoCommand handles applying the user's latest undone change again
+// @Summary Redo an undone change
+// @Description Apply the change the authenticated user undid most recently again. Any new change to the tasks clears what can be redone.
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} APIResponse{data=CommandResponse}
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /redo [post]
+func (h *Handler) RedoCommand(c *gin.Context) {
+	h.replayCommand(c, false)
+}
+
+// replayCommand undoes or redoes a command of the authenticated user
+func (h *Handler) replayCommand(c *gin.Context, undo bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Success: false,
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	var command *task.Command
+	var err error
+	message := "Change undone successfully"
+	if undo {
+		command, err = h.users(c).UndoUserCommand(userID.(int))
+	} else {
+		command, err = h.users(c).RedoUserCommand(userID.(int))
+		message = "Change redone successfully"
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		var transitionErr *task.TransitionError
+		if errors.Is(err, task.ErrNothingToUndo) || errors.Is(err, task.ErrNothingToRedo) ||
+			errors.Is(err, task.ErrReplayConflict) || errors.As(err, &transitionErr) {
+			status = http.StatusConflict
+		} else if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, ErrorResponse{
+			Success: false,
+			Message: "Failed to replay change",
+			Error:   err.Error(),
+			Code:    status,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, APIResponse{
+		Success: true,
+		Message: message,
+		Data:    ConvertToCommandResponse(*command),
+	})
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"learn-go-capstone/internal/auth"
+	"learn-go-capstone/internal/database"
 )
 
 // AuthMiddleware handles JWT authentication
@@ -65,6 +66,9 @@ func AuthMiddleware(authService *auth.AuthService) gin.HandlerFunc {
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 
+		// Attribute the task changes made by this request to the user
+		c.Request = c.Request.WithContext(database.WithActor(c.Request.Context(), claims.UserID))
+
 		// Continue to the next handler
 		c.Next()
 	}
@@ -74,7 +78,7 @@ func AuthMiddleware(authService *auth.AuthService) gin.HandlerFunc {
 func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
 		c.Header("Access-Control-Allow-Credentials", "true")
 
@@ -1,6 +1,9 @@
 package api
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"learn-go-capstone/internal/task"
@@ -37,11 +40,94 @@ type Pagination struct {
 type TaskRequest struct {
 	Title       string     `json:"title" binding:"required" example:"Learn Go Programming"`
 	Description string     `json:"description" example:"Study Go language fundamentals and best practices"`
-	Priority    int        `json:"priority" binding:"required,min=1,max=5" example:"3"`
-	Status      int        `json:"status" binding:"min=0,max=3" example:"0"`
+	// Priority may be left out for tasks created in a project, which then get
+	// the default priority of the project
+	Priority    int        `json:"priority" binding:"omitempty,min=1,max=5" example:"3"`
+	Status      int        `json:"status" binding:"min=0" example:"0"`
 	DueDate     *time.Time `json:"due_date,omitempty" example:"2024-12-31T23:59:59Z"`
 	CategoryID  *int       `json:"category_id,omitempty" example:"1"`
 	TagNames    []string   `json:"tag_names,omitempty" example:"[\"learning\", \"programming\"]"`
+	Recurrence  string     `json:"recurrence,omitempty" example:"FREQ=WEEKLY;BYDAY=MO"`
+	ParentID    *int       `json:"parent_id,omitempty" example:"1"`
+	EstimateMinutes *int   `json:"estimate_minutes,omitempty" binding:"omitempty,min=0" example:"90"`
+	Assignees   []AssigneeRequest `json:"assignees,omitempty" binding:"omitempty,dive"`
+	ProjectID   *int       `json:"project_id,omitempty" example:"1"`
+}
+
+// AssigneeRequest puts a user on a task with a role
+type AssigneeRequest struct {
+	Username string `json:"username" binding:"required" example:"janedoe"`
+	Role     string `json:"role" binding:"required,oneof=assignee reviewer watcher" example:"reviewer"`
+}
+
+// AssigneeResponse describes a user taking part in a task
+type AssigneeResponse struct {
+	UserID   int    `json:"user_id" example:"2"`
+	Username string `json:"username" example:"janedoe"`
+	Role     string `json:"role" example:"reviewer"`
+}
+
+// TaskParentRequest moves a task within the hierarchy; a null parent_id makes it a top-level task
+type TaskParentRequest struct {
+	ParentID *int `json:"parent_id" example:"1"`
+}
+
+// TaskPatchRequest represents a partial task update (JSON Merge Patch).
+// Omitted fields are left unchanged; a null due_date clears the due date.
+type TaskPatchRequest struct {
+	Title       *string    `json:"title,omitempty" example:"Learn Go Generics"`
+	Description *string    `json:"description,omitempty" example:"Read the type parameters proposal"`
+	Priority    *int       `json:"priority,omitempty" example:"4"`
+	DueDate     *time.Time `json:"due_date,omitempty" example:"2024-12-31T23:59:59Z"`
+	Recurrence  *string    `json:"recurrence,omitempty" example:"FREQ=MONTHLY;COUNT=12"`
+	EstimateMinutes *int   `json:"estimate_minutes,omitempty" example:"90"`
+	// Assignees replaces every assignee of the task; null or an empty list removes them all
+	Assignees   []AssigneeRequest `json:"assignees,omitempty"`
+}
+
+// QuickTaskRequest creates a task from a single quick-add line such as
+// "Pay rent tomorrow 5pm !urgent #finance @home every month"
+type QuickTaskRequest struct {
+	Text        string `json:"text" binding:"required" example:"Pay rent tomorrow 5pm !urgent #finance @home every month"`
+	Description string `json:"description,omitempty" example:"Transfer to the landlord"`
+	ProjectID   *int   `json:"project_id,omitempty" example:"1"`
+	// Timezone is the IANA time zone relative dates and times are read in; UTC by default
+	Timezone string `json:"timezone,omitempty" example:"Europe/Berlin"`
+}
+
+// SnoozeRequest hides a task from the default listings until a given time,
+// given either as until or as a phrase such as "3 days" or "until monday"
+type SnoozeRequest struct {
+	Until *time.Time `json:"until,omitempty" example:"2024-07-01T09:00:00Z"`
+	For   string     `json:"for,omitempty" example:"3 days"`
+	// Timezone is the IANA time zone the phrase in for is read in; UTC by default
+	Timezone string `json:"timezone,omitempty" example:"Europe/Berlin"`
+}
+
+// TaskPositionRequest places a task right after another one in a manually
+// ordered list, or at the top when after_task_id is left out. The list is the
+// user's own order, or the project's shared order when project_id is set.
+type TaskPositionRequest struct {
+	ProjectID   *int `json:"project_id,omitempty" example:"1"`
+	AfterTaskID *int `json:"after_task_id,omitempty" example:"2"`
+}
+
+// TaskPositionResponse is where a task sits in a manually ordered list
+type TaskPositionResponse struct {
+	TaskID    int    `json:"task_id" example:"1"`
+	ProjectID *int   `json:"project_id,omitempty" example:"1"`
+	Key       string `json:"key" example:"V"`
+}
+
+// CommandResponse is a change to the tasks that was undone or redone
+type CommandResponse struct {
+	ID          int       `json:"id" example:"1"`
+	Kind        string    `json:"kind" example:"delete"`
+	TaskID      int       `json:"task_id" example:"12"`
+	TaskIDs     []int     `json:"task_ids" example:"12,13"`
+	Description string    `json:"description" example:"delete task 12 \"Report\""`
+	Undone      bool      `json:"undone" example:"true"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 // TaskResponse represents a task response
@@ -57,7 +143,119 @@ type TaskResponse struct {
 	Category    *CategoryResponse  `json:"category,omitempty"`
 	Tags        []TagResponse      `json:"tags,omitempty"`
 	UserID      *int               `json:"user_id,omitempty" example:"1"`
+	ProjectID   *int               `json:"project_id,omitempty" example:"1"`
+	SprintID    *int               `json:"sprint_id,omitempty" example:"1"`
 	IsArchived  bool               `json:"is_archived" example:"false"`
+	HiddenUntil *time.Time         `json:"hidden_until,omitempty" example:"2024-07-01T00:00:00Z"`
+	DeletedAt   *time.Time         `json:"deleted_at,omitempty" example:"2024-06-01T00:00:00Z"`
+	Recurrence  string             `json:"recurrence,omitempty" example:"FREQ=WEEKLY;BYDAY=MO"`
+	ParentID    *int               `json:"parent_id,omitempty" example:"1"`
+	EstimateMinutes *int           `json:"estimate_minutes,omitempty" example:"90"`
+	CommentCount int               `json:"comment_count" example:"2"`
+	Assignees   []AssigneeResponse `json:"assignees,omitempty"`
+	CustomFields []CustomFieldValueResponse `json:"custom_fields,omitempty"`
+	Checklist   []ChecklistItemResponse `json:"checklist,omitempty"`
+	Progress    *float64           `json:"progress,omitempty" example:"50"`
+	Subtasks    []TaskResponse     `json:"subtasks,omitempty"`
+}
+
+// TaskHistoryResponse represents one recorded change to a task field
+type TaskHistoryResponse struct {
+	ID        int       `json:"id" example:"1"`
+	TaskID    int       `json:"task_id" example:"1"`
+	UserID    *int      `json:"user_id,omitempty" example:"1"`
+	Field     string    `json:"field" example:"priority"`
+	OldValue  *string   `json:"old_value,omitempty" example:"1"`
+	NewValue  *string   `json:"new_value,omitempty" example:"3"`
+	ChangedAt time.Time `json:"changed_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// TimerRequest starts a timer on a task; the body is optional
+type TimerRequest struct {
+	Note string `json:"note" example:"Pairing on the parser"`
+}
+
+// TimeEntryRequest logs time spent on a task without a timer.
+// started_at defaults to the given number of minutes before now.
+type TimeEntryRequest struct {
+	StartedAt *time.Time `json:"started_at,omitempty" example:"2024-01-01T09:00:00Z"`
+	Minutes   int        `json:"minutes" binding:"required,min=1" example:"45"`
+	Note      string     `json:"note" example:"Code review"`
+}
+
+// TimeEntryResponse represents time spent on a task; ended_at is omitted while the timer runs
+type TimeEntryResponse struct {
+	ID              int        `json:"id" example:"1"`
+	TaskID          int        `json:"task_id" example:"1"`
+	UserID          *int       `json:"user_id,omitempty" example:"1"`
+	StartedAt       time.Time  `json:"started_at" example:"2024-01-01T09:00:00Z"`
+	EndedAt         *time.Time `json:"ended_at,omitempty" example:"2024-01-01T09:45:00Z"`
+	DurationSeconds int        `json:"duration_seconds" example:"2700"`
+	Note            string     `json:"note,omitempty" example:"Code review"`
+}
+
+// TaskTimeResponse compares the time tracked on a task with its estimate
+type TaskTimeResponse struct {
+	TaskID          int                 `json:"task_id" example:"1"`
+	EstimateMinutes *int                `json:"estimate_minutes,omitempty" example:"90"`
+	TrackedSeconds  int                 `json:"tracked_seconds" example:"2700"`
+	Entries         []TimeEntryResponse `json:"entries"`
+}
+
+// TimeReportRowResponse is the time tracked for one task, category or user
+type TimeReportRowResponse struct {
+	GroupID         *int   `json:"group_id,omitempty" example:"1"`
+	Name            string `json:"name" example:"Work"`
+	TrackedSeconds  int    `json:"tracked_seconds" example:"2700"`
+	EstimateMinutes int    `json:"estimate_minutes" example:"90"`
+	Tasks           int    `json:"tasks" example:"2"`
+	Entries         int    `json:"entries" example:"3"`
+}
+
+// CommentRequest posts a comment, or a reply when parent_id is set
+type CommentRequest struct {
+	Body     string `json:"body" binding:"required" example:"Looks good, @johndoe can you review?"`
+	ParentID *int   `json:"parent_id,omitempty" example:"1"`
+}
+
+// CommentUpdateRequest replaces the body of a comment
+type CommentUpdateRequest struct {
+	Body string `json:"body" binding:"required" example:"Updated **markdown** body"`
+}
+
+// CommentResponse represents a comment with its replies. Deleted comments are
+// only listed while they have replies, and have an empty body.
+type CommentResponse struct {
+	ID        int               `json:"id" example:"1"`
+	TaskID    int               `json:"task_id" example:"1"`
+	ParentID  *int              `json:"parent_id,omitempty" example:"1"`
+	AuthorID  int               `json:"author_id" example:"1"`
+	Author    string            `json:"author" example:"johndoe"`
+	Body      string            `json:"body" example:"Looks good, @janedoe can you review?"`
+	Deleted   bool              `json:"deleted" example:"false"`
+	CreatedAt time.Time         `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt time.Time         `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+	Replies   []CommentResponse `json:"replies,omitempty"`
+}
+
+// AttachmentResponse describes a file attached to a task
+type AttachmentResponse struct {
+	ID          int       `json:"id" example:"1"`
+	TaskID      int       `json:"task_id" example:"1"`
+	UploaderID  *int      `json:"uploader_id,omitempty" example:"1"`
+	Uploader    string    `json:"uploader,omitempty" example:"johndoe"`
+	FileName    string    `json:"file_name" example:"screenshot.png"`
+	ContentType string    `json:"content_type" example:"image/png"`
+	Size        int64     `json:"size" example:"48213"`
+	SHA256      string    `json:"sha256" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	CreatedAt   time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// WorkflowStatusResponse describes a status and the statuses it can move to
+type WorkflowStatusResponse struct {
+	Status      int    `json:"status" example:"1"`
+	Name        string `json:"name" example:"In Progress"`
+	Transitions []int  `json:"transitions" example:"0,2,3"`
 }
 
 // CategoryRequest represents a category creation/update request
@@ -65,6 +263,8 @@ type CategoryRequest struct {
 	Name        string `json:"name" binding:"required" example:"Work"`
 	Description string `json:"description" example:"Work-related tasks"`
 	Color       string `json:"color" example:"#ff0000"`
+	// ProjectID creates the category in a project instead of globally
+	ProjectID   *int   `json:"project_id,omitempty" example:"1"`
 }
 
 // CategoryResponse represents a category response
@@ -73,6 +273,7 @@ type CategoryResponse struct {
 	Name        string    `json:"name" example:"Work"`
 	Description string    `json:"description" example:"Work-related tasks"`
 	Color       string    `json:"color" example:"#ff0000"`
+	ProjectID   *int      `json:"project_id,omitempty" example:"1"`
 	CreatedAt   time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
 	UpdatedAt   time.Time `json:"updated_at" example:"2024-01-01T00:00:00Z"`
 }
@@ -81,6 +282,8 @@ type CategoryResponse struct {
 type TagRequest struct {
 	Name  string `json:"name" binding:"required" example:"urgent"`
 	Color string `json:"color" example:"#ff0000"`
+	// ProjectID creates the tag in a project instead of globally
+	ProjectID *int `json:"project_id,omitempty" example:"1"`
 }
 
 // TagResponse represents a tag response
@@ -88,6 +291,7 @@ type TagResponse struct {
 	ID        int       `json:"id" example:"1"`
 	Name      string    `json:"name" example:"urgent"`
 	Color     string    `json:"color" example:"#ff0000"`
+	ProjectID *int      `json:"project_id,omitempty" example:"1"`
 	CreatedAt time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
 }
 
@@ -123,12 +327,19 @@ type LoginResponse struct {
 // SearchRequest represents a search request
 type SearchRequest struct {
 	Query       string    `json:"query" example:"learn go"`
+	// ProjectID searches the tasks of a project instead of the user's own tasks
+	ProjectID   *int      `json:"project_id,omitempty" example:"1"`
 	Status      *int      `json:"status,omitempty" example:"0"`
 	Priority    *int      `json:"priority,omitempty" example:"3"`
 	CategoryID  *int      `json:"category_id,omitempty" example:"1"`
 	TagNames    []string  `json:"tag_names,omitempty" example:"[\"learning\", \"programming\"]"`
+	IncludeArchived bool  `json:"include_archived" example:"false"`
 	CreatedAfter *time.Time `json:"created_after,omitempty" example:"2024-01-01T00:00:00Z"`
 	DueBefore   *time.Time `json:"due_before,omitempty" example:"2024-12-31T23:59:59Z"`
+	CustomFields []CustomFieldFilterRequest `json:"custom_fields,omitempty"`
+	// SortBy is title, created_at, updated_at, due_date, priority, status,
+	// position or custom:<field_id>. position follows the manual order of the
+	// project, or the user's own order, and sorts ascending by default.
 	SortBy      string    `json:"sort_by" example:"created_at"`
 	SortOrder   string    `json:"sort_order" example:"desc"`
 	Page        int       `json:"page" example:"1"`
@@ -138,6 +349,8 @@ type SearchRequest struct {
 // ExportRequest represents an export request
 type ExportRequest struct {
 	Format      string    `json:"format" binding:"required,oneof=json csv" example:"json"`
+	// ProjectID exports the tasks of a project instead of the user's own tasks
+	ProjectID   *int      `json:"project_id,omitempty" example:"1"`
 	Status      *int      `json:"status,omitempty" example:"0"`
 	Priority    *int      `json:"priority,omitempty" example:"3"`
 	CategoryID  *int      `json:"category_id,omitempty" example:"1"`
@@ -205,6 +418,381 @@ type DependencyResponse struct {
 	CreatedAt        time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
 }
 
+// ProjectSettingsRequest holds the settings of a project
+type ProjectSettingsRequest struct {
+	DefaultPriority int                      `json:"default_priority" binding:"omitempty,min=1,max=4" example:"2"`
+	Transitions     []StatusTransitionRequest `json:"transitions,omitempty"`
+}
+
+// StatusTransitionRequest allows tasks of a project to move from one status to another
+type StatusTransitionRequest struct {
+	From int `json:"from" binding:"min=0" example:"0"`
+	To   int `json:"to" binding:"min=0" example:"1"`
+}
+
+// ProjectRequest represents a project creation/update request
+type ProjectRequest struct {
+	Name        string                 `json:"name" binding:"required" example:"Website relaunch"`
+	Description string                 `json:"description" example:"Tasks of the web team"`
+	Settings    ProjectSettingsRequest `json:"settings"`
+}
+
+// ProjectMemberRequest adds a user to a project or changes their role
+type ProjectMemberRequest struct {
+	Username string `json:"username" binding:"required" example:"janedoe"`
+	Role     string `json:"role" binding:"required,oneof=owner member" example:"member"`
+}
+
+// ProjectMemberResponse describes a member of a project
+type ProjectMemberResponse struct {
+	UserID   int       `json:"user_id" example:"2"`
+	Username string    `json:"username" example:"janedoe"`
+	Role     string    `json:"role" example:"member"`
+	JoinedAt time.Time `json:"joined_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// ProjectResponse represents a project response
+type ProjectResponse struct {
+	ID          int                     `json:"id" example:"1"`
+	Name        string                  `json:"name" example:"Website relaunch"`
+	Description string                  `json:"description" example:"Tasks of the web team"`
+	OwnerID     *int                    `json:"owner_id,omitempty" example:"1"`
+	Settings    ProjectSettingsRequest  `json:"settings"`
+	Members     []ProjectMemberResponse `json:"members,omitempty"`
+	CreatedAt   time.Time               `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt   time.Time               `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// BoardColumnRequest describes a column of a board. A column shows the tasks
+// with its tag or, when it has no tag, the tasks with its status.
+type BoardColumnRequest struct {
+	// ID keeps an existing column and its cards when a board is updated
+	ID       int    `json:"id,omitempty" example:"1"`
+	Name     string `json:"name" binding:"required" example:"In progress"`
+	Status   *int   `json:"status,omitempty" example:"1"`
+	TagID    *int   `json:"tag_id,omitempty" example:"3"`
+	WIPLimit int    `json:"wip_limit" binding:"min=0" example:"3"`
+}
+
+// BoardRequest represents a board creation/update request
+type BoardRequest struct {
+	Name string `json:"name" binding:"required" example:"Sprint board"`
+	// ProjectID makes a board over the tasks of a project; it is ignored on update
+	ProjectID *int                 `json:"project_id,omitempty" example:"1"`
+	Columns   []BoardColumnRequest `json:"columns" binding:"required,min=1,dive"`
+}
+
+// BoardCardMoveRequest moves a card to a position in a column
+type BoardCardMoveRequest struct {
+	ColumnID int `json:"column_id" binding:"required" example:"2"`
+	Position int `json:"position" binding:"min=0" example:"0"`
+}
+
+// BoardColumnResponse represents a column of a board with its cards
+type BoardColumnResponse struct {
+	ID       int            `json:"id" example:"1"`
+	Name     string         `json:"name" example:"In progress"`
+	Status   *int           `json:"status,omitempty" example:"1"`
+	TagID    *int           `json:"tag_id,omitempty" example:"3"`
+	TagName  string         `json:"tag_name,omitempty" example:"blocked"`
+	WIPLimit int            `json:"wip_limit" example:"3"`
+	Cards    []TaskResponse `json:"cards"`
+}
+
+// BoardResponse represents a board response
+type BoardResponse struct {
+	ID        int                   `json:"id" example:"1"`
+	Name      string                `json:"name" example:"Sprint board"`
+	OwnerID   *int                  `json:"owner_id,omitempty" example:"1"`
+	ProjectID *int                  `json:"project_id,omitempty" example:"1"`
+	Columns   []BoardColumnResponse `json:"columns"`
+	CreatedAt time.Time             `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt time.Time             `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// SprintRequest represents a sprint creation/update request. Dates are
+// calendar dates, both included in the sprint.
+type SprintRequest struct {
+	Name      string `json:"name" binding:"required" example:"Sprint 12"`
+	Goal      string `json:"goal" example:"Ship the new onboarding"`
+	StartDate string `json:"start_date" binding:"required" example:"2024-01-01"`
+	EndDate   string `json:"end_date" binding:"required" example:"2024-01-14"`
+	// ProjectID plans the sprint for a project; it is ignored on update
+	ProjectID *int `json:"project_id,omitempty" example:"1"`
+}
+
+// SprintTaskRequest plans a task into a sprint
+type SprintTaskRequest struct {
+	TaskID int `json:"task_id" binding:"required" example:"42"`
+}
+
+// SprintCloseRequest closes a sprint. Unfinished tasks carry over into the
+// CarryOverTo sprint, or go back to the backlog when it is left out.
+type SprintCloseRequest struct {
+	CarryOverTo *int `json:"carry_over_to,omitempty" example:"13"`
+}
+
+// SprintResponse represents a sprint response
+type SprintResponse struct {
+	ID        int            `json:"id" example:"12"`
+	Name      string         `json:"name" example:"Sprint 12"`
+	Goal      string         `json:"goal,omitempty" example:"Ship the new onboarding"`
+	OwnerID   *int           `json:"owner_id,omitempty" example:"1"`
+	ProjectID *int           `json:"project_id,omitempty" example:"1"`
+	StartDate string         `json:"start_date" example:"2024-01-01"`
+	EndDate   string         `json:"end_date" example:"2024-01-14"`
+	ClosedAt  *time.Time     `json:"closed_at,omitempty" example:"2024-01-15T09:00:00Z"`
+	Tasks     []TaskResponse `json:"tasks,omitempty"`
+	CreatedAt time.Time      `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt time.Time      `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// BurndownPointResponse is the state of a sprint at the end of a day
+type BurndownPointResponse struct {
+	Date      string  `json:"date" example:"2024-01-02"`
+	Scope     int     `json:"scope" example:"12"`
+	Completed int     `json:"completed" example:"3"`
+	Remaining int     `json:"remaining" example:"9"`
+	Ideal     float64 `json:"ideal" example:"11.1"`
+}
+
+// SprintVelocityResponse is what a closed sprint committed to and completed
+type SprintVelocityResponse struct {
+	SprintID  int    `json:"sprint_id" example:"11"`
+	Name      string `json:"name" example:"Sprint 11"`
+	StartDate string `json:"start_date" example:"2023-12-18"`
+	EndDate   string `json:"end_date" example:"2023-12-31"`
+	Committed int    `json:"committed" example:"10"`
+	Completed int    `json:"completed" example:"8"`
+}
+
+// VelocityResponse lists the velocity of closed sprints, oldest first
+type VelocityResponse struct {
+	Unit    string                   `json:"unit" example:"tasks"`
+	Sprints []SprintVelocityResponse `json:"sprints"`
+	Average float64                  `json:"average" example:"8.5"`
+}
+
+// SprintReportResponse is the burndown of a sprint with the velocity of the
+// sprints that closed by its end
+type SprintReportResponse struct {
+	Sprint    SprintResponse          `json:"sprint"`
+	Unit      string                  `json:"unit" example:"tasks"`
+	Committed int                     `json:"committed" example:"12"`
+	Completed int                     `json:"completed" example:"3"`
+	Remaining int                     `json:"remaining" example:"9"`
+	Burndown  []BurndownPointResponse `json:"burndown"`
+	Velocity  VelocityResponse        `json:"velocity"`
+}
+
+// CustomFieldRequest represents a custom field creation/update request.
+// Options are required for enum fields and not allowed for other types.
+type CustomFieldRequest struct {
+	Name    string   `json:"name" binding:"required" example:"Severity"`
+	// Type is text, number, date, enum, user or url; it is ignored on update
+	Type    string   `json:"type" example:"enum"`
+	Options []string `json:"options,omitempty" example:"[\"low\", \"high\"]"`
+	// Exactly one of CategoryID and ProjectID is required on creation; both
+	// are ignored on update
+	CategoryID *int `json:"category_id,omitempty" example:"1"`
+	ProjectID  *int `json:"project_id,omitempty" example:"1"`
+}
+
+// CustomFieldResponse represents a custom field response
+type CustomFieldResponse struct {
+	ID         int       `json:"id" example:"1"`
+	Name       string    `json:"name" example:"Severity"`
+	Type       string    `json:"type" example:"enum"`
+	Options    []string  `json:"options,omitempty" example:"[\"low\", \"high\"]"`
+	CategoryID *int      `json:"category_id,omitempty" example:"1"`
+	ProjectID  *int      `json:"project_id,omitempty" example:"1"`
+	CreatedAt  time.Time `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt  time.Time `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// CustomFieldValueRequest sets the value of a custom field on a task; an
+// empty value clears it
+type CustomFieldValueRequest struct {
+	FieldID int    `json:"field_id" binding:"required" example:"1"`
+	Value   string `json:"value" example:"high"`
+}
+
+// TaskCustomFieldsRequest sets custom field values of a task. Fields left
+// out keep their value.
+type TaskCustomFieldsRequest struct {
+	Values []CustomFieldValueRequest `json:"values" binding:"required,dive"`
+}
+
+// CustomFieldValueResponse represents the value of a custom field on a task
+type CustomFieldValueResponse struct {
+	FieldID int    `json:"field_id" example:"1"`
+	Name    string `json:"name" example:"Severity"`
+	Type    string `json:"type" example:"enum"`
+	Value   string `json:"value" example:"high"`
+}
+
+// CustomFieldFilterRequest filters searched tasks by a custom field. Value
+// matches text fields by substring and other fields exactly; Min and Max
+// bound the value.
+type CustomFieldFilterRequest struct {
+	FieldID int    `json:"field_id" binding:"required" example:"1"`
+	Value   string `json:"value,omitempty" example:"high"`
+	Min     string `json:"min,omitempty" example:"2024-01-01"`
+	Max     string `json:"max,omitempty" example:"2024-03-31"`
+}
+
+// ChecklistItemRequest adds an item to the checklist of a task
+type ChecklistItemRequest struct {
+	Text string `json:"text" binding:"required" example:"Update the changelog"`
+}
+
+// ChecklistItemPatchRequest changes a checklist item; fields left out keep
+// their value
+type ChecklistItemPatchRequest struct {
+	Text     *string `json:"text,omitempty" example:"Update the changelog"`
+	Done     *bool   `json:"done,omitempty" example:"true"`
+	Position *int    `json:"position,omitempty" example:"0"`
+}
+
+// ChecklistItemResponse represents an item of the checklist of a task
+type ChecklistItemResponse struct {
+	ID          int        `json:"id" example:"1"`
+	Text        string     `json:"text" example:"Update the changelog"`
+	Done        bool       `json:"done" example:"false"`
+	Position    int        `json:"position" example:"0"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" example:"2024-01-02T00:00:00Z"`
+	CreatedAt   time.Time  `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt   time.Time  `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// TaskLinkRequest links a task to another task. Type is the link as seen
+// from the task, so blocked_by makes the other task block it. CloseDuplicate
+// cancels the duplicate of a duplicates link.
+type TaskLinkRequest struct {
+	Type           string `json:"type" binding:"required" example:"blocked_by"`
+	TaskID         int    `json:"task_id" binding:"required" example:"2"`
+	CloseDuplicate bool   `json:"close_duplicate,omitempty" example:"false"`
+}
+
+// TaskLinkResponse represents a link between two tasks as seen from one of them
+type TaskLinkResponse struct {
+	Type         string        `json:"type" example:"blocks"`
+	Name         string        `json:"name" example:"blocked_by"`
+	TaskID       int           `json:"task_id" example:"1"`
+	LinkedTaskID int           `json:"linked_task_id" example:"2"`
+	LinkedTask   *TaskResponse `json:"linked_task,omitempty"`
+	CreatedAt    time.Time     `json:"created_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// TemplateTaskRequest is a task of a template. DueOffsetDays places the due
+// date that many days after the start date of an instantiation. Parent and
+// DependsOn refer to earlier tasks of the template by their index, from 0.
+type TemplateTaskRequest struct {
+	Title         string   `json:"title" binding:"required" example:"Write release notes"`
+	Description   string   `json:"description" example:"Summarize the changes since the last release"`
+	Priority      int      `json:"priority" binding:"required,min=1,max=4" example:"2"`
+	CategoryID    *int     `json:"category_id,omitempty" example:"1"`
+	TagIDs        []int    `json:"tag_ids,omitempty" example:"1,2"`
+	DueOffsetDays *int     `json:"due_offset_days,omitempty" example:"3"`
+	Checklist     []string `json:"checklist,omitempty" example:"[\"Draft\", \"Review\"]"`
+	Parent        *int     `json:"parent,omitempty" example:"0"`
+	DependsOn     []int    `json:"depends_on,omitempty" example:"0"`
+}
+
+// TemplateRequest represents a template creation/update request
+type TemplateRequest struct {
+	Name        string                `json:"name" binding:"required" example:"Release"`
+	Description string                `json:"description" example:"Everything that goes into a release"`
+	Tasks       []TemplateTaskRequest `json:"tasks" binding:"required,dive"`
+	// ProjectID makes a template for a project; it is ignored on update
+	ProjectID *int `json:"project_id,omitempty" example:"1"`
+}
+
+// TemplateResponse represents a template response
+type TemplateResponse struct {
+	ID          int                   `json:"id" example:"1"`
+	Name        string                `json:"name" example:"Release"`
+	Description string                `json:"description,omitempty" example:"Everything that goes into a release"`
+	OwnerID     *int                  `json:"owner_id,omitempty" example:"1"`
+	ProjectID   *int                  `json:"project_id,omitempty" example:"1"`
+	Tasks       []TemplateTaskRequest `json:"tasks"`
+	CreatedAt   time.Time             `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt   time.Time             `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// InstantiateTemplateRequest creates the tasks of a template. Due offsets
+// count from StartDate, a calendar date that defaults to today; the body is
+// optional.
+type InstantiateTemplateRequest struct {
+	StartDate string `json:"start_date,omitempty" example:"2024-01-01"`
+}
+
+// BulkFilterRequest selects the tasks of a bulk request like a search does,
+// without paging. It covers the user's own tasks unless ProjectID is given.
+type BulkFilterRequest struct {
+	Query           string                     `json:"query" example:"release"`
+	ProjectID       *int                       `json:"project_id,omitempty" example:"1"`
+	Status          *int                       `json:"status,omitempty" example:"0"`
+	Priority        *int                       `json:"priority,omitempty" example:"3"`
+	CategoryID      *int                       `json:"category_id,omitempty" example:"1"`
+	TagNames        []string                   `json:"tag_names,omitempty" example:"[\"sprint-12\"]"`
+	IncludeArchived bool                       `json:"include_archived" example:"false"`
+	CustomFields    []CustomFieldFilterRequest `json:"custom_fields,omitempty"`
+}
+
+// BulkRequest applies one action to the tasks given by IDs or by a filter.
+// Action is status, priority, category, tags, archive or delete; the fields
+// of the action are set alongside it.
+type BulkRequest struct {
+	Action   string             `json:"action" binding:"required" example:"status"`
+	IDs      []int              `json:"ids,omitempty" example:"1,2,3"`
+	Filter   *BulkFilterRequest `json:"filter,omitempty"`
+	Status   *int               `json:"status,omitempty" example:"2"`
+	Priority *int               `json:"priority,omitempty" example:"3"`
+	// CategoryID is the new category; leave it out to clear the category
+	CategoryID   *int  `json:"category_id,omitempty" example:"1"`
+	AddTagIDs    []int `json:"add_tag_ids,omitempty" example:"1"`
+	RemoveTagIDs []int `json:"remove_tag_ids,omitempty" example:"2"`
+	// DryRun reports what would happen without changing anything
+	DryRun bool `json:"dry_run" example:"false"`
+	// Partial changes the tasks that can be changed and reports the others,
+	// instead of changing nothing when any task fails
+	Partial bool `json:"partial" example:"false"`
+}
+
+// BulkItemResponse is the outcome of a bulk request for a single task
+type BulkItemResponse struct {
+	TaskID  int    `json:"task_id" example:"1"`
+	Success bool   `json:"success" example:"true"`
+	Error   string `json:"error,omitempty" example:"task with ID 1 not found"`
+}
+
+// BulkResponse summarizes a bulk request. Items are listed for dry runs
+// and partial requests.
+type BulkResponse struct {
+	Action    string             `json:"action" example:"status"`
+	DryRun    bool               `json:"dry_run" example:"false"`
+	Matched   int                `json:"matched" example:"3"`
+	Succeeded int                `json:"succeeded" example:"3"`
+	Failed    int                `json:"failed" example:"0"`
+	Items     []BulkItemResponse `json:"items,omitempty"`
+}
+
+// NextTaskResponse is a task of the next-up ranking. Factors holds the
+// weighted share of each factor in Score.
+type NextTaskResponse struct {
+	Task    TaskResponse       `json:"task"`
+	Score   float64            `json:"score" example:"0.62"`
+	Factors map[string]float64 `json:"factors"`
+}
+
+// ScoringWeightsRequest sets the weights of the next-up ranking by factor:
+// priority, due_date, blocking, estimate and staleness. Factors left out keep
+// their default weight.
+type ScoringWeightsRequest struct {
+	Weights map[string]float64 `json:"weights" binding:"required"`
+}
+
 // StatisticsResponse represents application statistics
 type StatisticsResponse struct {
 	TotalTasks      int `json:"total_tasks" example:"100"`
@@ -244,7 +832,50 @@ func ConvertToTaskResponse(t task.Task) TaskResponse {
 		CreatedAt:   t.CreatedAt,
 		UpdatedAt:   t.UpdatedAt,
 		DueDate:     t.DueDate,
-		IsArchived:  false, // Default value
+		ProjectID:   t.ProjectID,
+		SprintID:    t.SprintID,
+		IsArchived:  t.IsArchived,
+		HiddenUntil: t.HiddenUntil,
+		DeletedAt:   t.DeletedAt,
+		ParentID:    t.ParentID,
+		EstimateMinutes: t.EstimateMinutes,
+		CommentCount: t.CommentCount,
+		Progress:    t.Progress,
+	}
+
+	// Assignees are only loaded for a single task
+	if len(t.Assignees) > 0 {
+		response.Assignees = make([]AssigneeResponse, len(t.Assignees))
+		for i, assignee := range t.Assignees {
+			response.Assignees[i] = AssigneeResponse{
+				UserID:   assignee.UserID,
+				Username: assignee.Username,
+				Role:     string(assignee.Role),
+			}
+		}
+	}
+
+	// Custom field values are only loaded for a single task
+	response.CustomFields = ConvertToCustomFieldValueResponses(t.CustomFields)
+
+	// Checklist items are only loaded for a single task
+	if len(t.Checklist) > 0 {
+		response.Checklist = make([]ChecklistItemResponse, len(t.Checklist))
+		for i, item := range t.Checklist {
+			response.Checklist[i] = ConvertToChecklistItemResponse(item)
+		}
+	}
+
+	// Subtasks are only present when the task was loaded with its subtree
+	if len(t.Subtasks) > 0 {
+		response.Subtasks = make([]TaskResponse, len(t.Subtasks))
+		for i, subtask := range t.Subtasks {
+			response.Subtasks[i] = ConvertToTaskResponse(subtask)
+		}
+	}
+
+	if t.Recurrence != nil {
+		response.Recurrence = t.Recurrence.String()
 	}
 
 	if t.Category != nil {
@@ -273,6 +904,75 @@ func ConvertToTaskResponse(t task.Task) TaskResponse {
 	return response
 }
 
+// ConvertToCommentResponse converts a task.Comment and its replies to CommentResponse
+func ConvertToCommentResponse(comment task.Comment) CommentResponse {
+	response := CommentResponse{
+		ID:        comment.ID,
+		TaskID:    comment.TaskID,
+		ParentID:  comment.ParentID,
+		AuthorID:  comment.AuthorID,
+		Author:    comment.Author,
+		Body:      comment.Body,
+		Deleted:   comment.Deleted,
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+	}
+
+	if len(comment.Replies) > 0 {
+		response.Replies = make([]CommentResponse, len(comment.Replies))
+		for i, reply := range comment.Replies {
+			response.Replies[i] = ConvertToCommentResponse(reply)
+		}
+	}
+
+	return response
+}
+
+// ConvertToAttachmentResponse converts a task.Attachment to AttachmentResponse
+func ConvertToAttachmentResponse(attachment task.Attachment) AttachmentResponse {
+	return AttachmentResponse{
+		ID:          attachment.ID,
+		TaskID:      attachment.TaskID,
+		UploaderID:  attachment.UploaderID,
+		Uploader:    attachment.Uploader,
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		SHA256:      attachment.SHA256,
+		CreatedAt:   attachment.CreatedAt,
+	}
+}
+
+// ConvertToTimeEntryResponse converts a task.TimeEntry to TimeEntryResponse
+func ConvertToTimeEntryResponse(e task.TimeEntry) TimeEntryResponse {
+	return TimeEntryResponse{
+		ID:              e.ID,
+		TaskID:          e.TaskID,
+		UserID:          e.UserID,
+		StartedAt:       e.StartedAt,
+		EndedAt:         e.EndedAt,
+		DurationSeconds: int(e.Duration / time.Second),
+		Note:            e.Note,
+	}
+}
+
+// ConvertToCommandResponse converts a task.Command to CommandResponse
+func ConvertToCommandResponse(command task.Command) CommandResponse {
+	taskIDs := make([]int, 0, len(command.Changes))
+	for _, change := range command.Changes {
+		taskIDs = append(taskIDs, change.TaskID)
+	}
+	return CommandResponse{
+		ID:          command.ID,
+		Kind:        string(command.Kind),
+		TaskID:      command.TaskID,
+		TaskIDs:     taskIDs,
+		Description: command.String(),
+		Undone:      command.Undone,
+		CreatedAt:   command.CreatedAt,
+	}
+}
+
 // ConvertToTaskRequest converts a TaskRequest to task.Task
 func ConvertToTaskRequest(req TaskRequest) task.Task {
 	t := task.Task{
@@ -300,3 +1000,447 @@ func ConvertToTaskRequest(req TaskRequest) task.Task {
 
 	return t
 }
+
+// ParseTaskPatch decodes a JSON Merge Patch document into a task.TaskPatch.
+// Unlike a plain struct decode it distinguishes an omitted field from an
+// explicit null, and it rejects fields that cannot be patched.
+func ParseTaskPatch(body []byte) (task.TaskPatch, error) {
+	var patch task.TaskPatch
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return patch, fmt.Errorf("patch must be a JSON object: %w", err)
+	}
+
+	for name, raw := range fields {
+		isNull := string(raw) == "null"
+
+		switch name {
+		case "title":
+			if isNull {
+				return patch, fmt.Errorf("title cannot be null")
+			}
+			var title string
+			if err := json.Unmarshal(raw, &title); err != nil {
+				return patch, fmt.Errorf("invalid title: %w", err)
+			}
+			patch.Title = &title
+
+		case "description":
+			// A null description clears it
+			var description string
+			if !isNull {
+				if err := json.Unmarshal(raw, &description); err != nil {
+					return patch, fmt.Errorf("invalid description: %w", err)
+				}
+			}
+			patch.Description = &description
+
+		case "priority":
+			if isNull {
+				return patch, fmt.Errorf("priority cannot be null")
+			}
+			var priority int
+			if err := json.Unmarshal(raw, &priority); err != nil {
+				return patch, fmt.Errorf("invalid priority: %w", err)
+			}
+			p := task.Priority(priority)
+			patch.Priority = &p
+
+		case "due_date":
+			if isNull {
+				patch.ClearDueDate = true
+				continue
+			}
+			var dueDate time.Time
+			if err := json.Unmarshal(raw, &dueDate); err != nil {
+				return patch, fmt.Errorf("invalid due_date: %w", err)
+			}
+			patch.DueDate = &dueDate
+
+		case "recurrence":
+			if isNull {
+				patch.ClearRecurrence = true
+				continue
+			}
+			var value string
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return patch, fmt.Errorf("invalid recurrence: %w", err)
+			}
+			rule, err := task.ParseRecurrenceRule(value)
+			if err != nil {
+				return patch, fmt.Errorf("invalid recurrence: %w", err)
+			}
+			patch.Recurrence = rule
+
+		case "estimate_minutes":
+			if isNull {
+				patch.ClearEstimate = true
+				continue
+			}
+			var estimate int
+			if err := json.Unmarshal(raw, &estimate); err != nil {
+				return patch, fmt.Errorf("invalid estimate_minutes: %w", err)
+			}
+			patch.EstimateMinutes = &estimate
+
+		case "assignees":
+			// Assignees are not part of the task itself; see ParseAssigneePatch
+
+		default:
+			return patch, fmt.Errorf("field %q cannot be patched", name)
+		}
+	}
+
+	return patch, nil
+}
+
+// ParseAssigneePatch reads the assignees of a merge patch body. It returns
+// nil when the body leaves them unchanged and an empty list when they are
+// removed with null or [].
+func ParseAssigneePatch(body []byte) (*[]task.Assignee, error) {
+	var fields struct {
+		Assignees *json.RawMessage `json:"assignees"`
+	}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, fmt.Errorf("patch must be a JSON object: %w", err)
+	}
+	if fields.Assignees == nil {
+		return nil, nil
+	}
+
+	var requests []AssigneeRequest
+	if string(*fields.Assignees) != "null" {
+		if err := json.Unmarshal(*fields.Assignees, &requests); err != nil {
+			return nil, fmt.Errorf("invalid assignees: %w", err)
+		}
+	}
+
+	assignees, err := ConvertToAssignees(requests)
+	if err != nil {
+		return nil, err
+	}
+	return &assignees, nil
+}
+
+// ConvertToAssignees converts assignee requests to task.Assignee
+func ConvertToAssignees(requests []AssigneeRequest) ([]task.Assignee, error) {
+	assignees := make([]task.Assignee, len(requests))
+	for i, req := range requests {
+		if strings.TrimSpace(req.Username) == "" {
+			return nil, fmt.Errorf("invalid assignee: username is required")
+		}
+		role, err := task.ParseAssigneeRole(req.Role)
+		if err != nil {
+			return nil, err
+		}
+		assignees[i] = task.Assignee{Username: req.Username, Role: role}
+	}
+	return assignees, nil
+}
+
+// ConvertToProjectSettings converts a ProjectSettingsRequest to task.ProjectSettings
+func ConvertToProjectSettings(req ProjectSettingsRequest) task.ProjectSettings {
+	settings := task.ProjectSettings{DefaultPriority: task.Priority(req.DefaultPriority)}
+	for _, transition := range req.Transitions {
+		settings.Transitions = append(settings.Transitions, task.StatusTransition{
+			From: task.Status(transition.From),
+			To:   task.Status(transition.To),
+		})
+	}
+	return settings
+}
+
+// ConvertToProjectMemberResponses converts project members to responses
+func ConvertToProjectMemberResponses(members []task.ProjectMemberInfo) []ProjectMemberResponse {
+	response := make([]ProjectMemberResponse, len(members))
+	for i, member := range members {
+		response[i] = ProjectMemberResponse{
+			UserID:   member.UserID,
+			Username: member.Username,
+			Role:     string(member.Role),
+			JoinedAt: member.JoinedAt,
+		}
+	}
+	return response
+}
+
+// ConvertToProjectResponse converts a task.Project to ProjectResponse
+func ConvertToProjectResponse(project task.Project) ProjectResponse {
+	response := ProjectResponse{
+		ID:          project.ID,
+		Name:        project.Name,
+		Description: project.Description,
+		OwnerID:     project.OwnerID,
+		Settings:    ProjectSettingsRequest{DefaultPriority: int(project.Settings.DefaultPriority)},
+		CreatedAt:   project.CreatedAt,
+		UpdatedAt:   project.UpdatedAt,
+	}
+
+	for _, transition := range project.Settings.Transitions {
+		response.Settings.Transitions = append(response.Settings.Transitions, StatusTransitionRequest{
+			From: int(transition.From),
+			To:   int(transition.To),
+		})
+	}
+
+	// Members are only loaded for a single project
+	if len(project.Members) > 0 {
+		response.Members = ConvertToProjectMemberResponses(project.Members)
+	}
+
+	return response
+}
+
+// ConvertToBoardColumns converts column requests to task.BoardColumn
+func ConvertToBoardColumns(requests []BoardColumnRequest) []task.BoardColumn {
+	columns := make([]task.BoardColumn, len(requests))
+	for i, req := range requests {
+		columns[i] = task.BoardColumn{
+			ID:       req.ID,
+			Name:     req.Name,
+			TagID:    req.TagID,
+			WIPLimit: req.WIPLimit,
+		}
+		if req.Status != nil {
+			status := task.Status(*req.Status)
+			columns[i].Status = &status
+		}
+	}
+	return columns
+}
+
+// ConvertToBoardResponse converts a task.Board to BoardResponse
+func ConvertToBoardResponse(board task.Board) BoardResponse {
+	response := BoardResponse{
+		ID:        board.ID,
+		Name:      board.Name,
+		OwnerID:   board.OwnerID,
+		ProjectID: board.ProjectID,
+		Columns:   make([]BoardColumnResponse, len(board.Columns)),
+		CreatedAt: board.CreatedAt,
+		UpdatedAt: board.UpdatedAt,
+	}
+
+	for i, column := range board.Columns {
+		response.Columns[i] = BoardColumnResponse{
+			ID:       column.ID,
+			Name:     column.Name,
+			TagID:    column.TagID,
+			TagName:  column.TagName,
+			WIPLimit: column.WIPLimit,
+			Cards:    make([]TaskResponse, len(column.Cards)),
+		}
+		if column.Status != nil {
+			status := int(*column.Status)
+			response.Columns[i].Status = &status
+		}
+		for j, card := range column.Cards {
+			response.Columns[i].Cards[j] = ConvertToTaskResponse(card)
+		}
+	}
+
+	return response
+}
+
+// ConvertToSprintResponse converts a task.Sprint to SprintResponse
+func ConvertToSprintResponse(sprint task.Sprint) SprintResponse {
+	response := SprintResponse{
+		ID:        sprint.ID,
+		Name:      sprint.Name,
+		Goal:      sprint.Goal,
+		OwnerID:   sprint.OwnerID,
+		ProjectID: sprint.ProjectID,
+		StartDate: sprint.StartDate.Format(reportDateLayout),
+		EndDate:   sprint.EndDate.Format(reportDateLayout),
+		ClosedAt:  sprint.ClosedAt,
+		CreatedAt: sprint.CreatedAt,
+		UpdatedAt: sprint.UpdatedAt,
+	}
+
+	// Tasks are only loaded for a single sprint
+	if sprint.Tasks != nil {
+		response.Tasks = make([]TaskResponse, len(sprint.Tasks))
+		for i, t := range sprint.Tasks {
+			response.Tasks[i] = ConvertToTaskResponse(t)
+		}
+	}
+
+	return response
+}
+
+// ConvertToVelocityResponse converts a task.VelocityReport to VelocityResponse
+func ConvertToVelocityResponse(report task.VelocityReport) VelocityResponse {
+	response := VelocityResponse{
+		Unit:    string(report.Unit),
+		Sprints: make([]SprintVelocityResponse, len(report.Sprints)),
+		Average: report.Average,
+	}
+
+	for i, sprint := range report.Sprints {
+		response.Sprints[i] = SprintVelocityResponse{
+			SprintID:  sprint.SprintID,
+			Name:      sprint.Name,
+			StartDate: sprint.StartDate.Format(reportDateLayout),
+			EndDate:   sprint.EndDate.Format(reportDateLayout),
+			Committed: sprint.Committed,
+			Completed: sprint.Completed,
+		}
+	}
+
+	return response
+}
+
+// ConvertToSprintReportResponse converts a task.SprintReport to SprintReportResponse
+func ConvertToSprintReportResponse(report task.SprintReport) SprintReportResponse {
+	response := SprintReportResponse{
+		Sprint:    ConvertToSprintResponse(report.Sprint),
+		Unit:      string(report.Unit),
+		Committed: report.Committed,
+		Completed: report.Completed,
+		Remaining: report.Remaining,
+		Burndown:  make([]BurndownPointResponse, len(report.Burndown)),
+		Velocity:  ConvertToVelocityResponse(report.Velocity),
+	}
+
+	for i, point := range report.Burndown {
+		response.Burndown[i] = BurndownPointResponse{
+			Date:      point.Date.Format(reportDateLayout),
+			Scope:     point.Scope,
+			Completed: point.Completed,
+			Remaining: point.Remaining,
+			Ideal:     point.Ideal,
+		}
+	}
+
+	return response
+}
+
+// ConvertToCustomFieldResponse converts a task.CustomField to CustomFieldResponse
+func ConvertToCustomFieldResponse(field task.CustomField) CustomFieldResponse {
+	return CustomFieldResponse{
+		ID:         field.ID,
+		Name:       field.Name,
+		Type:       string(field.Type),
+		Options:    field.Options,
+		CategoryID: field.CategoryID,
+		ProjectID:  field.ProjectID,
+		CreatedAt:  field.CreatedAt,
+		UpdatedAt:  field.UpdatedAt,
+	}
+}
+
+// ConvertToCustomFieldValueResponses converts custom field values, returning
+// nil when there are none
+func ConvertToCustomFieldValueResponses(values []task.CustomFieldValue) []CustomFieldValueResponse {
+	if len(values) == 0 {
+		return nil
+	}
+
+	response := make([]CustomFieldValueResponse, len(values))
+	for i, value := range values {
+		response[i] = CustomFieldValueResponse{
+			FieldID: value.FieldID,
+			Name:    value.Name,
+			Type:    string(value.Type),
+			Value:   value.Value,
+		}
+	}
+	return response
+}
+
+// ConvertToChecklistItemResponse converts a task.ChecklistItem to ChecklistItemResponse
+func ConvertToChecklistItemResponse(item task.ChecklistItem) ChecklistItemResponse {
+	return ChecklistItemResponse{
+		ID:          item.ID,
+		Text:        item.Text,
+		Done:        item.Done,
+		Position:    item.Position,
+		CompletedAt: item.CompletedAt,
+		CreatedAt:   item.CreatedAt,
+		UpdatedAt:   item.UpdatedAt,
+	}
+}
+
+// ConvertToTaskLinkResponse converts a task.TaskLink to TaskLinkResponse
+func ConvertToTaskLinkResponse(link task.TaskLink) TaskLinkResponse {
+	response := TaskLinkResponse{
+		Type:         string(link.Type),
+		Name:         link.Name,
+		TaskID:       link.TaskID,
+		LinkedTaskID: link.LinkedTaskID,
+		CreatedAt:    link.CreatedAt,
+	}
+	if link.LinkedTask != nil {
+		linkedTask := ConvertToTaskResponse(*link.LinkedTask)
+		response.LinkedTask = &linkedTask
+	}
+	return response
+}
+
+// ConvertToTemplateResponse converts a task.Template to TemplateResponse
+func ConvertToTemplateResponse(template task.Template) TemplateResponse {
+	response := TemplateResponse{
+		ID:          template.ID,
+		Name:        template.Name,
+		Description: template.Description,
+		OwnerID:     template.OwnerID,
+		ProjectID:   template.ProjectID,
+		Tasks:       make([]TemplateTaskRequest, len(template.Tasks)),
+		CreatedAt:   template.CreatedAt,
+		UpdatedAt:   template.UpdatedAt,
+	}
+
+	for i, templateTask := range template.Tasks {
+		response.Tasks[i] = TemplateTaskRequest{
+			Title:         templateTask.Title,
+			Description:   templateTask.Description,
+			Priority:      int(templateTask.Priority),
+			CategoryID:    templateTask.CategoryID,
+			TagIDs:        templateTask.TagIDs,
+			DueOffsetDays: templateTask.DueOffsetDays,
+			Checklist:     templateTask.Checklist,
+			Parent:        templateTask.Parent,
+			DependsOn:     templateTask.DependsOn,
+		}
+	}
+
+	return response
+}
+
+// ConvertToBulkResponse converts a task.BulkResult to BulkResponse
+func ConvertToBulkResponse(result *task.BulkResult) BulkResponse {
+	response := BulkResponse{
+		Action:    string(result.Action),
+		DryRun:    result.DryRun,
+		Matched:   result.Matched,
+		Succeeded: result.Succeeded,
+		Failed:    result.Failed,
+	}
+
+	if len(result.Items) > 0 {
+		response.Items = make([]BulkItemResponse, len(result.Items))
+		for i, item := range result.Items {
+			response.Items[i] = BulkItemResponse{
+				TaskID:  item.TaskID,
+				Success: item.Success,
+				Error:   item.Error,
+			}
+		}
+	}
+
+	return response
+}
+
+// ConvertToNextTaskResponses converts ranked tasks to NextTaskResponses
+func ConvertToNextTaskResponses(ranked []task.ScoredTask) []NextTaskResponse {
+	response := make([]NextTaskResponse, len(ranked))
+	for i, scored := range ranked {
+		response[i] = NextTaskResponse{
+			Task:    ConvertToTaskResponse(scored.Task),
+			Score:   scored.Score,
+			Factors: scored.Factors,
+		}
+	}
+	return response
+}
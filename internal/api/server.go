@@ -19,13 +19,20 @@ type Server struct {
 
 // NewServer creates a new API server
 func NewServer(
-	taskManager task.TaskManagerInterface,
+	taskManager task.TaskManagerV2,
 	userManager *task.UserManager,
 	categoryManager *task.CategoryManager,
 	dependencyManager *task.DependencyManager,
 	searchManager *task.SearchManager,
 	exportManager *task.ExportManager,
 	notificationManager *task.NotificationManager,
+	commentManager *task.CommentManager,
+	attachmentManager *task.AttachmentManager,
+	projectManager *task.ProjectManager,
+	boardManager *task.BoardManager,
+	sprintManager *task.SprintManager,
+	customFieldManager *task.CustomFieldManager,
+	templateManager *task.TemplateManager,
 	authService *auth.AuthService,
 ) *Server {
 	// Set Gin mode
@@ -51,6 +58,13 @@ func NewServer(
 		searchManager,
 		exportManager,
 		notificationManager,
+		commentManager,
+		attachmentManager,
+		projectManager,
+		boardManager,
+		sprintManager,
+		customFieldManager,
+		templateManager,
 		authService,
 	)
 
@@ -92,12 +106,134 @@ func (s *Server) SetupRoutes() {
 			{
 				tasks.POST("", s.handler.CreateTask)
 				tasks.GET("", s.handler.GetTasks)
+				tasks.GET("/assigned", s.handler.GetAssignedTasks)
+				tasks.POST("/bulk", s.handler.BulkUpdateTasks)
+				tasks.POST("/quick", s.handler.QuickAddTask)
+				tasks.GET("/next", s.handler.GetNextTasks)
 				tasks.GET("/:id", s.handler.GetTask)
+				tasks.PATCH("/:id", s.handler.PatchTask)
 				tasks.PUT("/:id/status", s.handler.UpdateTaskStatus)
+				tasks.PUT("/:id/parent", s.handler.SetTaskParent)
+				tasks.GET("/:id/subtasks", s.handler.GetSubtasks)
+				tasks.GET("/:id/ancestors", s.handler.GetTaskAncestors)
+				tasks.GET("/:id/history", s.handler.GetTaskHistory)
+				tasks.GET("/:id/time", s.handler.GetTaskTime)
+				tasks.POST("/:id/time", s.handler.LogTime)
+				tasks.POST("/:id/time/start", s.handler.StartTimer)
+				tasks.POST("/:id/time/stop", s.handler.StopTimer)
+				tasks.GET("/:id/comments", s.handler.GetComments)
+				tasks.POST("/:id/comments", s.handler.CreateComment)
+				tasks.PATCH("/:id/comments/:comment_id", s.handler.UpdateComment)
+				tasks.DELETE("/:id/comments/:comment_id", s.handler.DeleteComment)
+				tasks.GET("/:id/attachments", s.handler.GetAttachments)
+				tasks.POST("/:id/attachments", s.handler.UploadAttachment)
+				tasks.GET("/:id/attachments/:attachment_id", s.handler.DownloadAttachment)
+				tasks.DELETE("/:id/attachments/:attachment_id", s.handler.DeleteAttachment)
+				tasks.PUT("/:id/custom-fields", s.handler.SetTaskCustomFields)
+				tasks.GET("/:id/checklist", s.handler.GetChecklist)
+				tasks.POST("/:id/checklist", s.handler.AddChecklistItem)
+				tasks.PATCH("/:id/checklist/:item_id", s.handler.UpdateChecklistItem)
+				tasks.DELETE("/:id/checklist/:item_id", s.handler.DeleteChecklistItem)
+				tasks.GET("/:id/links", s.handler.GetTaskLinks)
+				tasks.POST("/:id/links", s.handler.CreateTaskLink)
+				tasks.DELETE("/:id/links/:type/:linked_id", s.handler.DeleteTaskLink)
+				tasks.POST("/:id/archive", s.handler.ArchiveTask)
+				tasks.DELETE("/:id/archive", s.handler.UnarchiveTask)
+				tasks.POST("/:id/snooze", s.handler.SnoozeTask)
+				tasks.DELETE("/:id/snooze", s.handler.UnsnoozeTask)
+				tasks.PUT("/:id/position", s.handler.MoveTask)
 				tasks.DELETE("/:id", s.handler.DeleteTask)
 				tasks.POST("/search", s.handler.SearchTasks)
 			}
 
+			// Trash routes
+			trash := protected.Group("/trash")
+			{
+				trash.GET("", s.handler.GetTrash)
+				trash.POST("/:id/restore", s.handler.RestoreTask)
+			}
+
+			// Time tracking routes; timers and entries live under /tasks/:id/time
+			timeTracking := protected.Group("/time")
+			{
+				timeTracking.GET("/report", s.handler.GetTimeReport)
+			}
+
+			// Project routes
+			projects := protected.Group("/projects")
+			{
+				projects.POST("", s.handler.CreateProject)
+				projects.GET("", s.handler.GetProjects)
+				projects.GET("/:id", s.handler.GetProject)
+				projects.PUT("/:id", s.handler.UpdateProject)
+				projects.DELETE("/:id", s.handler.DeleteProject)
+				projects.GET("/:id/members", s.handler.GetProjectMembers)
+				projects.PUT("/:id/members", s.handler.SetProjectMember)
+				projects.DELETE("/:id/members/:user_id", s.handler.RemoveProjectMember)
+			}
+
+			// Board routes
+			boards := protected.Group("/boards")
+			{
+				boards.POST("", s.handler.CreateBoard)
+				boards.GET("", s.handler.GetBoards)
+				boards.GET("/:id", s.handler.GetBoard)
+				boards.PUT("/:id", s.handler.UpdateBoard)
+				boards.DELETE("/:id", s.handler.DeleteBoard)
+				boards.POST("/:id/cards/:task_id/move", s.handler.MoveBoardCard)
+			}
+
+			// Sprint routes
+			sprints := protected.Group("/sprints")
+			{
+				sprints.POST("", s.handler.CreateSprint)
+				sprints.GET("", s.handler.GetSprints)
+				sprints.GET("/velocity", s.handler.GetSprintVelocity)
+				sprints.GET("/:id", s.handler.GetSprint)
+				sprints.PUT("/:id", s.handler.UpdateSprint)
+				sprints.DELETE("/:id", s.handler.DeleteSprint)
+				sprints.POST("/:id/tasks", s.handler.AddSprintTask)
+				sprints.DELETE("/:id/tasks/:task_id", s.handler.RemoveSprintTask)
+				sprints.POST("/:id/close", s.handler.CloseSprint)
+				sprints.GET("/:id/report", s.handler.GetSprintReport)
+			}
+
+			// Custom field routes; values are set under /tasks/:id/custom-fields
+			customFields := protected.Group("/custom-fields")
+			{
+				customFields.POST("", s.handler.CreateCustomField)
+				customFields.GET("", s.handler.GetCustomFields)
+				customFields.GET("/:id", s.handler.GetCustomField)
+				customFields.PUT("/:id", s.handler.UpdateCustomField)
+				customFields.DELETE("/:id", s.handler.DeleteCustomField)
+			}
+
+			// Template routes
+			templates := protected.Group("/templates")
+			{
+				templates.POST("", s.handler.CreateTemplate)
+				templates.GET("", s.handler.GetTemplates)
+				templates.GET("/:id", s.handler.GetTemplate)
+				templates.PUT("/:id", s.handler.UpdateTemplate)
+				templates.DELETE("/:id", s.handler.DeleteTemplate)
+				templates.POST("/:id/instantiate", s.handler.InstantiateTemplate)
+			}
+
+			// Next-up ranking weights of the authenticated user
+			scoring := protected.Group("/scoring-weights")
+			{
+				scoring.GET("", s.handler.GetScoringWeights)
+				scoring.PUT("", s.handler.SetScoringWeights)
+				scoring.DELETE("", s.handler.ResetScoringWeights)
+			}
+
+			// Workflow routes
+			protected.GET("/workflow", s.handler.GetWorkflow)
+
+			// Undo and redo the authenticated user's changes to their tasks
+			protected.POST("/undo", s.handler.UndoCommand)
+			protected.POST("/redo", s.handler.RedoCommand)
+
 			// Category routes
 			categories := protected.Group("/categories")
 			{
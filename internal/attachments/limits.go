@@ -0,0 +1,76 @@
+package attachments
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// sniffLength is how many leading bytes DetectContentType looks at
+const sniffLength = 512
+
+// Limits restricts what can be uploaded
+type Limits struct {
+	// MaxSize is the largest accepted upload in bytes; 0 means no limit
+	MaxSize int64
+	// AllowedTypes lists accepted MIME types, either exact such as
+	// application/pdf or a whole family such as image/*. An empty list
+	// accepts every type.
+	AllowedTypes []string
+}
+
+// Allows reports whether content of the given MIME type may be uploaded
+func (l Limits) Allows(contentType string) bool {
+	if len(l.AllowedTypes) == 0 {
+		return true
+	}
+
+	mediaType := baseType(contentType)
+	for _, allowed := range l.AllowedTypes {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if allowed == "*/*" || allowed == mediaType {
+			return true
+		}
+		if family := strings.TrimSuffix(allowed, "*"); family != allowed && strings.HasPrefix(mediaType, family) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckType returns an error when content of the given MIME type may not be uploaded
+func (l Limits) CheckType(contentType string) error {
+	if !l.Allows(contentType) {
+		return fmt.Errorf("attachment type %s is not allowed", baseType(contentType))
+	}
+	return nil
+}
+
+// DetectContentType determines the MIME type of an upload from its leading
+// bytes. The file name extension is only consulted when the content itself
+// is not recognised, so a renamed file cannot claim a different type.
+func DetectContentType(fileName string, head []byte) string {
+	if len(head) > sniffLength {
+		head = head[:sniffLength]
+	}
+
+	contentType := http.DetectContentType(head)
+	if contentType == "application/octet-stream" {
+		if byExtension := mime.TypeByExtension(strings.ToLower(filepath.Ext(fileName))); byExtension != "" {
+			return byExtension
+		}
+	}
+
+	return contentType
+}
+
+// baseType strips the parameters, such as the charset, from a MIME type
+func baseType(contentType string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
@@ -0,0 +1,209 @@
+package attachments
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrTooLarge is returned by Put when the content exceeds the size limit
+var ErrTooLarge = errors.New("attachment is too large")
+
+// Blob describes stored content
+type Blob struct {
+	SHA256  string
+	Size    int64
+	ModTime time.Time
+}
+
+// Store keeps file contents on the local filesystem, addressed by their
+// SHA-256 digest so that identical content is stored once. The content with
+// digest abcd... lives at <root>/ab/abcd....
+type Store struct {
+	root string
+	// mu orders storing content against removing it, so that content
+	// stored again while it is being removed is never lost
+	mu sync.Mutex
+}
+
+// NewStore creates a store rooted at dir, creating the directory if needed
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create attachment directory: %w", err)
+	}
+
+	return &Store{root: dir}, nil
+}
+
+// Root returns the directory of the store
+func (s *Store) Root() string {
+	return s.root
+}
+
+// path returns where the content with the given digest is stored
+func (s *Store) path(sum string) (string, error) {
+	if !validDigest(sum) {
+		return "", fmt.Errorf("invalid SHA-256 digest %q", sum)
+	}
+	return filepath.Join(s.root, sum[:2], sum), nil
+}
+
+// validDigest reports whether sum is a lowercase hex SHA-256 digest, which
+// also keeps it from escaping the store directory
+func validDigest(sum string) bool {
+	if len(sum) != sha256.Size*2 {
+		return false
+	}
+	for _, c := range sum {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// Put stores the content read from r and returns its digest and size. When
+// maxSize is positive, content longer than maxSize bytes is rejected with
+// ErrTooLarge. Storing content that is already present only refreshes its
+// modification time.
+func (s *Store) Put(r io.Reader, maxSize int64) (*Blob, error) {
+	tmp, err := os.CreateTemp(s.root, ".upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if maxSize > 0 {
+		r = io.LimitReader(r, maxSize+1)
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+	if maxSize > 0 && size > maxSize {
+		return nil, ErrTooLarge
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+
+	sum := hex.EncodeToString(hash.Sum(nil))
+	path, _ := s.path(sum)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+
+	if _, err := os.Stat(path); err == nil {
+		if err := os.Chtimes(path, now, now); err != nil {
+			return nil, fmt.Errorf("failed to touch attachment: %w", err)
+		}
+		return &Blob{SHA256: sum, Size: size, ModTime: now}, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create attachment directory: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+
+	return &Blob{SHA256: sum, Size: size, ModTime: now}, nil
+}
+
+// Open opens the content with the given digest for reading
+func (s *Store) Open(sum string) (*os.File, error) {
+	path, err := s.path(sum)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("attachment content %s not found", sum)
+		}
+		return nil, fmt.Errorf("failed to open attachment: %w", err)
+	}
+
+	return file, nil
+}
+
+// Remove deletes the content with the given digest. Removing missing
+// content is not an error.
+func (s *Store) Remove(sum string) error {
+	path, err := s.path(sum)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove attachment: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveUnmodifiedSince deletes the content with the given digest unless it
+// was stored or stored again at or after since, and reports whether it was
+// removed. Missing content is not an error.
+func (s *Store) RemoveUnmodifiedSince(sum string, since time.Time) (bool, error) {
+	path, err := s.path(sum)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to remove attachment: %w", err)
+	}
+	if !info.ModTime().Before(since) {
+		return false, nil
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, fmt.Errorf("failed to remove attachment: %w", err)
+	}
+	return true, nil
+}
+
+// List returns every piece of content in the store
+func (s *Store) List() ([]Blob, error) {
+	var blobs []Blob
+	err := filepath.WalkDir(s.root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !validDigest(entry.Name()) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		blobs = append(blobs, Blob{SHA256: entry.Name(), Size: info.Size(), ModTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+
+	return blobs, nil
+}
@@ -0,0 +1,119 @@
+package attachments
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStorePut(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	first, err := store.Put(strings.NewReader("hello"), 0)
+	if err != nil {
+		t.Fatalf("Failed to put content: %v", err)
+	}
+	if first.SHA256 != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" || first.Size != 5 {
+		t.Errorf("Unexpected blob %+v", first)
+	}
+	if _, err := os.Stat(filepath.Join(store.Root(), "2c", first.SHA256)); err != nil {
+		t.Errorf("Expected the content under its digest: %v", err)
+	}
+
+	// The same content is stored once
+	second, err := store.Put(strings.NewReader("hello"), 0)
+	if err != nil || second.SHA256 != first.SHA256 {
+		t.Fatalf("Expected the same digest, got %+v (%v)", second, err)
+	}
+	if blobs, err := store.List(); err != nil || len(blobs) != 1 {
+		t.Errorf("Expected a single stored file, got %+v (%v)", blobs, err)
+	}
+
+	if _, err := store.Put(strings.NewReader("too long"), 4); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Expected ErrTooLarge, got %v", err)
+	}
+	if blobs, _ := store.List(); len(blobs) != 1 {
+		t.Errorf("Expected rejected content not to be stored, got %+v", blobs)
+	}
+
+	file, err := store.Open(first.SHA256)
+	if err != nil {
+		t.Fatalf("Failed to open content: %v", err)
+	}
+	content, _ := io.ReadAll(file)
+	file.Close()
+	if !bytes.Equal(content, []byte("hello")) {
+		t.Errorf("Expected the stored content, got %q", content)
+	}
+
+	if _, err := store.Open("../../etc/passwd"); err == nil {
+		t.Error("Expected error for an invalid digest")
+	}
+
+	// Content stored again since the cutoff is kept
+	if removed, err := store.RemoveUnmodifiedSince(first.SHA256, time.Now().Add(-time.Minute)); err != nil || removed {
+		t.Errorf("Expected recently stored content to be kept, got %v (%v)", removed, err)
+	}
+	if removed, err := store.RemoveUnmodifiedSince(first.SHA256, time.Now().Add(time.Second)); err != nil || !removed {
+		t.Errorf("Expected older content to be removed, got %v (%v)", removed, err)
+	}
+	if _, err := store.Put(strings.NewReader("hello"), 0); err != nil {
+		t.Fatalf("Failed to put content: %v", err)
+	}
+
+	if err := store.Remove(first.SHA256); err != nil {
+		t.Fatalf("Failed to remove content: %v", err)
+	}
+	if err := store.Remove(first.SHA256); err != nil {
+		t.Errorf("Removing missing content should not fail: %v", err)
+	}
+	if _, err := store.Open(first.SHA256); err == nil {
+		t.Error("Expected error when opening removed content")
+	}
+}
+
+func TestLimits(t *testing.T) {
+	limits := Limits{AllowedTypes: []string{"image/*", "application/pdf"}}
+
+	tests := []struct {
+		contentType string
+		want        bool
+	}{
+		{"image/png", true},
+		{"IMAGE/JPEG", true},
+		{"application/pdf", true},
+		{"text/plain; charset=utf-8", false},
+		{"application/pdfx", false},
+	}
+	for _, tt := range tests {
+		if got := limits.Allows(tt.contentType); got != tt.want {
+			t.Errorf("Allows(%q) = %v, want %v", tt.contentType, got, tt.want)
+		}
+	}
+
+	if !(Limits{}).Allows("application/x-msdownload") {
+		t.Error("Expected no allowed types to accept everything")
+	}
+}
+
+func TestDetectContentType(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR")
+
+	if got := DetectContentType("notes.txt", png); got != "image/png" {
+		t.Errorf("Expected the content to decide the type, got %q", got)
+	}
+	if got := DetectContentType("report.pdf", []byte{0x00, 0x01, 0x02}); got != "application/pdf" {
+		t.Errorf("Expected the extension for unrecognised content, got %q", got)
+	}
+	if got := DetectContentType("blob", []byte{0x00, 0x01, 0x02}); got != "application/octet-stream" {
+		t.Errorf("Expected a generic type, got %q", got)
+	}
+}
@@ -14,6 +14,12 @@ type Config struct {
 	// Application configuration
 	App AppConfig
 	
+	// Task behaviour configuration
+	Tasks TaskConfig
+	
+	// Attachment storage configuration
+	Attachments AttachmentConfig
+	
 	// Feature flags
 	Features FeatureFlags
 }
@@ -39,6 +45,33 @@ type AppConfig struct {
 	LogLevel    string
 }
 
+// TaskConfig holds task behaviour configuration
+type TaskConfig struct {
+	SubtaskDeleteRule   string // cascade, orphan, reparent, restrict
+	SubtaskCompleteRule string // ignore, cascade, require
+	AuditLog            bool   // log every task event
+	// Completed tasks are archived after AutoArchiveDays; 0 disables auto-archiving
+	AutoArchiveDays            int
+	AutoArchiveIntervalMinutes int
+	// Deleted tasks are purged from the trash after TrashRetentionDays
+	TrashRetentionDays         int
+	TrashPurgeIntervalMinutes  int
+	// Snoozed tasks are checked for waking up every SnoozeWakeIntervalMinutes
+	SnoozeWakeIntervalMinutes  int
+}
+
+// AttachmentConfig holds attachment storage configuration
+type AttachmentConfig struct {
+	Dir          string   // directory holding the content-addressed files
+	MaxSizeMB    int      // largest accepted upload
+	AllowedTypes []string // MIME types such as image/png, or families such as image/*
+}
+
+// MaxSizeBytes returns the largest accepted upload in bytes
+func (ac *AttachmentConfig) MaxSizeBytes() int64 {
+	return int64(ac.MaxSizeMB) << 20
+}
+
 // FeatureFlags holds feature toggle configuration
 type FeatureFlags struct {
 	DatabaseEnabled    bool
@@ -70,6 +103,21 @@ func LoadConfig() *Config {
 			Environment: getEnv("ENVIRONMENT", "development"),
 			LogLevel:    getEnv("LOG_LEVEL", "info"),
 		},
+		Tasks: TaskConfig{
+			SubtaskDeleteRule:   getEnv("SUBTASK_DELETE_RULE", "cascade"),
+			SubtaskCompleteRule: getEnv("SUBTASK_COMPLETE_RULE", "ignore"),
+			AuditLog:            getEnvAsBool("TASK_AUDIT_LOG", false),
+			AutoArchiveDays:            getEnvAsInt("AUTO_ARCHIVE_DAYS", 0),
+			AutoArchiveIntervalMinutes: getEnvAsInt("AUTO_ARCHIVE_INTERVAL_MINUTES", 60),
+			TrashRetentionDays:         getEnvAsInt("TRASH_RETENTION_DAYS", 30),
+			TrashPurgeIntervalMinutes:  getEnvAsInt("TRASH_PURGE_INTERVAL_MINUTES", 60),
+			SnoozeWakeIntervalMinutes:  getEnvAsInt("SNOOZE_WAKE_INTERVAL_MINUTES", 1),
+		},
+		Attachments: AttachmentConfig{
+			Dir:          getEnv("ATTACHMENTS_DIR", "data/attachments"),
+			MaxSizeMB:    getEnvAsInt("ATTACHMENT_MAX_SIZE_MB", 10),
+			AllowedTypes: getEnvAsList("ATTACHMENT_ALLOWED_TYPES", []string{"image/*", "text/*", "application/pdf", "application/json", "application/zip"}),
+		},
 		Features: FeatureFlags{
 			DatabaseEnabled:     getEnvAsBool("FEATURE_DATABASE", true),
 			CategoriesEnabled:   getEnvAsBool("FEATURE_CATEGORIES", false),
@@ -139,6 +187,19 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+func getEnvAsList(key string, defaultValue []string) []string {
+	if value := os.Getenv(key); value != "" {
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items
+	}
+	return defaultValue
+}
+
 // GetDSN returns the appropriate DSN based on the database driver
 func (dc *DatabaseConfig) GetDSN() string {
 	if dc.DSN != "" {
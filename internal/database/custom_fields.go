@@ -0,0 +1,139 @@
+package database
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Custom field types
+const (
+	CustomFieldText   = "text"
+	CustomFieldNumber = "number"
+	CustomFieldDate   = "date"
+	CustomFieldEnum   = "enum"
+	CustomFieldUser   = "user"
+	CustomFieldURL    = "url"
+)
+
+// CustomFieldTypes lists the custom field types in the order they are documented
+var CustomFieldTypes = []string{CustomFieldText, CustomFieldNumber, CustomFieldDate, CustomFieldEnum, CustomFieldUser, CustomFieldURL}
+
+// customDateLayout is the normalized form of date values
+const customDateLayout = "2006-01-02"
+
+// maxCustomTextLength bounds text values, in characters
+const maxCustomTextLength = 1000
+
+// NormalizeValue checks a value against the type of the field and returns the
+// form it is stored in: numbers without trailing zeros, dates as YYYY-MM-DD,
+// enum values spelled as the option, user IDs as decimals and URLs parsed.
+// Whether a user value names an existing user is left to the caller.
+func (f *CustomField) NormalizeValue(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	invalid := func(reason string) error {
+		return fmt.Errorf("invalid value %q for custom field %q: %s", value, f.Name, reason)
+	}
+	if value == "" {
+		return "", invalid("value is empty")
+	}
+
+	switch f.Type {
+	case CustomFieldText:
+		if utf8.RuneCountInString(value) > maxCustomTextLength {
+			return "", invalid(fmt.Sprintf("text is longer than %d characters", maxCustomTextLength))
+		}
+		return value, nil
+	case CustomFieldNumber:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			return "", invalid("not a number")
+		}
+		return strconv.FormatFloat(number, 'f', -1, 64), nil
+	case CustomFieldDate:
+		date, err := time.Parse(customDateLayout, value)
+		if err != nil {
+			if date, err = time.Parse(time.RFC3339, value); err != nil {
+				return "", invalid("use a YYYY-MM-DD date")
+			}
+		}
+		return date.Format(customDateLayout), nil
+	case CustomFieldEnum:
+		for _, option := range f.Options {
+			if strings.EqualFold(option, value) {
+				return option, nil
+			}
+		}
+		return "", invalid("use one of " + strings.Join(f.Options, ", "))
+	case CustomFieldUser:
+		id, err := strconv.Atoi(value)
+		if err != nil || id <= 0 {
+			return "", invalid("not a user ID")
+		}
+		return strconv.Itoa(id), nil
+	case CustomFieldURL:
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "", invalid("not an http or https URL")
+		}
+		return u.String(), nil
+	default:
+		return "", fmt.Errorf("invalid custom field %q: unknown type %q", f.Name, f.Type)
+	}
+}
+
+// CompareValues orders two normalized values of the field: numbers and user
+// IDs numerically, dates chronologically, enum values in the order of the
+// options and other values alphabetically, ignoring case
+func (f *CustomField) CompareValues(a, b string) int {
+	switch f.Type {
+	case CustomFieldNumber, CustomFieldUser:
+		x, _ := strconv.ParseFloat(a, 64)
+		y, _ := strconv.ParseFloat(b, 64)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	case CustomFieldDate:
+		return strings.Compare(a, b)
+	case CustomFieldEnum:
+		return f.optionIndex(a) - f.optionIndex(b)
+	default:
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	}
+}
+
+// optionIndex returns the position of an enum value among the options, or
+// the number of options for a value that is no longer among them
+func (f *CustomField) optionIndex(value string) int {
+	for i, option := range f.Options {
+		if option == value {
+			return i
+		}
+	}
+	return len(f.Options)
+}
+
+// AppliesTo reports whether the field applies to a task: it belongs to the
+// category or the project of the task
+func (f *CustomField) AppliesTo(task *DatabaseTask) bool {
+	if f.CategoryID != nil {
+		return task.CategoryID != nil && *task.CategoryID == *f.CategoryID
+	}
+	return f.ProjectID != nil && task.ProjectID != nil && *task.ProjectID == *f.ProjectID
+}
+
+// sameID reports whether two optional IDs are equal
+func sameID(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
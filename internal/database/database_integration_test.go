@@ -2,6 +2,7 @@ package database
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -150,3 +151,111 @@ func TestTaskCRUD(t *testing.T) {
 		t.Fatalf("Should delete task successfully: %v", err)
 	}
 }
+
+func TestTransaction(t *testing.T) {
+	_, repository, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	newTask := func(title string) *DatabaseTask {
+		return &DatabaseTask{Title: title, Priority: 1, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	}
+
+	// A failing transaction leaves nothing behind
+	failed := errors.New("stop")
+	err := repository.Transaction(func(tx Repository) error {
+		if err := tx.CreateTask(newTask("Rolled back")); err != nil {
+			return err
+		}
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("Expected the error of the transaction, got %v", err)
+	}
+	if tasks, _ := repository.GetAllTasks(); len(tasks) != 0 {
+		t.Fatalf("Expected no tasks after a rollback, got %d", len(tasks))
+	}
+
+	// Nested transactions only undo their own changes
+	err = repository.Transaction(func(tx Repository) error {
+		if err := tx.CreateTask(newTask("Kept")); err != nil {
+			return err
+		}
+		if err := tx.Transaction(func(inner Repository) error {
+			if err := inner.CreateTask(newTask("Undone")); err != nil {
+				return err
+			}
+			return failed
+		}); !errors.Is(err, failed) {
+			return fmt.Errorf("expected the error of the savepoint, got %v", err)
+		}
+		return tx.Transaction(func(inner Repository) error {
+			return inner.CreateTask(newTask("Also kept"))
+		})
+	})
+	if err != nil {
+		t.Fatalf("Failed to run transaction: %v", err)
+	}
+
+	tasks, err := repository.GetAllTasks()
+	if err != nil {
+		t.Fatalf("Failed to get tasks: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("Expected the two kept tasks, got %+v", tasks)
+	}
+	for _, task := range tasks {
+		if task.Title == "Undone" {
+			t.Errorf("Expected the savepoint to be rolled back, got %+v", task)
+		}
+	}
+}
+
+func TestTaskRanksSkipTrashedTasks(t *testing.T) {
+	db, repo, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	var ids []int
+	for _, title := range []string{"First", "Second", "Third"} {
+		task := &DatabaseTask{Title: title, Priority: 2}
+		if err := repo.CreateTask(task); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		ids = append(ids, task.ID)
+	}
+	list := PersonalTaskList(1)
+	for i, key := range []string{"a", "m", "t"} {
+		if err := repo.SetTaskRank(&TaskRank{List: list, TaskID: ids[i], Key: key}); err != nil {
+			t.Fatalf("Failed to set task rank: %v", err)
+		}
+	}
+
+	if err := repo.DeleteTask(ids[1]); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+	ranks, err := repo.GetTaskRanks(list)
+	if err != nil {
+		t.Fatalf("Failed to get task ranks: %v", err)
+	}
+	if len(ranks) != 2 || ranks[0].TaskID != ids[0] || ranks[1].TaskID != ids[2] {
+		t.Fatalf("Expected the trashed task to be left out, got %+v", ranks)
+	}
+
+	// The key of the trashed task is free to be placed between its neighbours
+	if err := repo.SetTaskRank(&TaskRank{List: list, TaskID: ids[2], Key: "m"}); err != nil {
+		t.Fatalf("Failed to take the key of a trashed task: %v", err)
+	}
+
+	if err := repo.DeleteTask(ids[2]); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+	if _, err := repo.PurgeDeletedTasksBefore(time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Failed to purge tasks: %v", err)
+	}
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM task_ranks WHERE task_id IN (?, ?)`, ids[1], ids[2]).Scan(&count); err != nil {
+		t.Fatalf("Failed to count task ranks: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected the ranks of purged tasks to be removed, got %d", count)
+	}
+}
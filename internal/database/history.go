@@ -0,0 +1,124 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// actorKey is the context key holding the ID of the user making a change
+type actorKey struct{}
+
+// WithActor returns a context that attributes the task changes made with it
+// to the given user. Pass it to Repository.WithContext.
+func WithActor(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, actorKey{}, userID)
+}
+
+// actorFromContext returns the user set by WithActor, or nil for changes made
+// by the system itself
+func actorFromContext(ctx context.Context) *int {
+	if userID, ok := ctx.Value(actorKey{}).(int); ok {
+		return &userID
+	}
+	return nil
+}
+
+// historyChange is a single field change to be recorded in task_history.
+// A nil value means the field was unset.
+type historyChange struct {
+	field    string
+	oldValue *string
+	newValue *string
+}
+
+// recordHistory stores changes of a task, attributed to the actor of the
+// repository context, as part of tx
+func (r *SQLiteRepository) recordHistory(tx *txn, taskID int, changes ...historyChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	query := `
+	INSERT INTO task_history (task_id, user_id, field, old_value, new_value, changed_at)
+	VALUES (?, ?, ?, ?, ?, ?)`
+
+	actor := actorFromContext(r.ctx)
+	changedAt := time.Now()
+	for _, change := range changes {
+		_, err := tx.ExecContext(r.ctx, query, taskID, actor, change.field, change.oldValue, change.newValue, changedAt)
+		if err != nil {
+			return fmt.Errorf("failed to record task history: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// taskChanges lists the fields that differ between the stored and the updated task
+func taskChanges(old, updated *DatabaseTask) []historyChange {
+	var changes []historyChange
+	compare := func(field string, oldValue, newValue *string) {
+		if (oldValue == nil) != (newValue == nil) || (oldValue != nil && *oldValue != *newValue) {
+			changes = append(changes, historyChange{field: field, oldValue: oldValue, newValue: newValue})
+		}
+	}
+
+	compare("title", textValue(old.Title), textValue(updated.Title))
+	compare("description", textValue(old.Description), textValue(updated.Description))
+	compare("priority", intValue(&old.Priority), intValue(&updated.Priority))
+	compare("status", intValue(&old.Status), intValue(&updated.Status))
+	compare("due_date", timeValue(old.DueDate), timeValue(updated.DueDate))
+	compare("category_id", intValue(old.CategoryID), intValue(updated.CategoryID))
+	compare("user_id", intValue(old.UserID), intValue(updated.UserID))
+	compare("is_archived", textValue(strconv.FormatBool(old.IsArchived)), textValue(strconv.FormatBool(updated.IsArchived)))
+	compare("recurrence_rule", old.RecurrenceRule, updated.RecurrenceRule)
+	compare("parent_id", intValue(old.ParentID), intValue(updated.ParentID))
+	compare("estimate_minutes", intValue(old.EstimateMinutes), intValue(updated.EstimateMinutes))
+	compare("project_id", intValue(old.ProjectID), intValue(updated.ProjectID))
+	compare("sprint_id", intValue(old.SprintID), intValue(updated.SprintID))
+	compare("hidden_until", timeValue(old.HiddenUntil), timeValue(updated.HiddenUntil))
+
+	return changes
+}
+
+// History values are stored as text
+
+func textValue(s string) *string {
+	return &s
+}
+
+func intValue(i *int) *string {
+	if i == nil {
+		return nil
+	}
+	return textValue(strconv.Itoa(*i))
+}
+
+func timeValue(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	return textValue(t.UTC().Format(time.RFC3339))
+}
+
+// assigneesValue formats assignees as user_id:role pairs ordered by user, or
+// nil when there are none
+func assigneesValue(assignees []TaskAssignee) *string {
+	if len(assignees) == 0 {
+		return nil
+	}
+
+	sorted := make([]TaskAssignee, len(assignees))
+	copy(sorted, assignees)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].UserID < sorted[j].UserID })
+
+	pairs := make([]string, len(sorted))
+	for i, assignee := range sorted {
+		pairs[i] = strconv.Itoa(assignee.UserID) + ":" + assignee.Role
+	}
+	return textValue(strings.Join(pairs, ","))
+}
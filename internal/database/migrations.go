@@ -105,6 +105,96 @@ func (mm *MigrationManager) getMigrations() []Migration {
 			Name:    "create_task_dependencies_table",
 			Run:     mm.createTaskDependenciesTable,
 		},
+		{
+			Version: 10,
+			Name:    "add_recurrence_rule_to_tasks",
+			Run:     mm.addRecurrenceRuleToTasks,
+		},
+		{
+			Version: 11,
+			Name:    "add_parent_id_to_tasks",
+			Run:     mm.addParentIDToTasks,
+		},
+		{
+			Version: 12,
+			Name:    "add_deleted_at_to_tasks",
+			Run:     mm.addDeletedAtToTasks,
+		},
+		{
+			Version: 13,
+			Name:    "create_task_history_table",
+			Run:     mm.createTaskHistoryTable,
+		},
+		{
+			Version: 14,
+			Name:    "create_time_entries_table",
+			Run:     mm.createTimeEntriesTable,
+		},
+		{
+			Version: 15,
+			Name:    "create_task_comments_table",
+			Run:     mm.createTaskCommentsTable,
+		},
+		{
+			Version: 16,
+			Name:    "create_task_attachments_table",
+			Run:     mm.createTaskAttachmentsTable,
+		},
+		{
+			Version: 17,
+			Name:    "create_task_assignees_table",
+			Run:     mm.createTaskAssigneesTable,
+		},
+		{
+			Version: 18,
+			Name:    "create_projects_tables",
+			Run:     mm.createProjectsTables,
+		},
+		{
+			Version: 19,
+			Name:    "create_boards_tables",
+			Run:     mm.createBoardsTables,
+		},
+		{
+			Version: 20,
+			Name:    "create_sprints_table",
+			Run:     mm.createSprintsTable,
+		},
+		{
+			Version: 21,
+			Name:    "create_custom_fields_tables",
+			Run:     mm.createCustomFieldsTables,
+		},
+		{
+			Version: 22,
+			Name:    "create_checklists_and_templates_tables",
+			Run:     mm.createChecklistsAndTemplatesTables,
+		},
+		{
+			Version: 23,
+			Name:    "add_hidden_until_to_tasks",
+			Run:     mm.addHiddenUntilToTasks,
+		},
+		{
+			Version: 24,
+			Name:    "create_scoring_weights_table",
+			Run:     mm.createScoringWeightsTable,
+		},
+		{
+			Version: 25,
+			Name:    "create_task_ranks_table",
+			Run:     mm.createTaskRanksTable,
+		},
+		{
+			Version: 26,
+			Name:    "create_command_journal_table",
+			Run:     mm.createCommandJournalTable,
+		},
+		{
+			Version: 27,
+			Name:    "create_task_links_table",
+			Run:     mm.createTaskLinksTable,
+		},
 	}
 }
 
@@ -250,3 +340,527 @@ func (mm *MigrationManager) createTaskDependenciesTable(db *sql.DB) error {
 	_, err := db.Exec(query)
 	return err
 }
+
+func (mm *MigrationManager) addRecurrenceRuleToTasks(db *sql.DB) error {
+	// Recurrence rules are stored as RRULE strings, e.g. "FREQ=WEEKLY;INTERVAL=1;BYDAY=MO"
+	query := `ALTER TABLE tasks ADD COLUMN recurrence_rule TEXT`
+	
+	_, err := db.Exec(query)
+	return err
+}
+
+func (mm *MigrationManager) addParentIDToTasks(db *sql.DB) error {
+	// Subtasks point at their parent; top-level tasks have a NULL parent_id
+	queries := []string{
+		`ALTER TABLE tasks ADD COLUMN parent_id INTEGER REFERENCES tasks(id)`,
+		`CREATE INDEX IF NOT EXISTS idx_tasks_parent_id ON tasks(parent_id)`,
+	}
+	
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
+func (mm *MigrationManager) addDeletedAtToTasks(db *sql.DB) error {
+	// Deleted tasks stay in the table, with deleted_at set, until they are purged
+	queries := []string{
+		`ALTER TABLE tasks ADD COLUMN deleted_at DATETIME`,
+		`CREATE INDEX IF NOT EXISTS idx_tasks_deleted_at ON tasks(deleted_at)`,
+	}
+	
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
+func (mm *MigrationManager) createTaskHistoryTable(db *sql.DB) error {
+	// One row per changed field; user_id is NULL for changes made by the system
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS task_history (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			task_id INTEGER NOT NULL,
+			user_id INTEGER,
+			field TEXT NOT NULL,
+			old_value TEXT,
+			new_value TEXT,
+			changed_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_task_history_task_id ON task_history(task_id)`,
+	}
+	
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
+func (mm *MigrationManager) createTimeEntriesTable(db *sql.DB) error {
+	// A running timer has a NULL ended_at; the partial unique index allows at
+	// most one of them per user (user_id is NULL for the CLI)
+	queries := []string{
+		`ALTER TABLE tasks ADD COLUMN estimate_minutes INTEGER`,
+		`CREATE TABLE IF NOT EXISTS time_entries (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			task_id INTEGER NOT NULL,
+			user_id INTEGER,
+			started_at DATETIME NOT NULL,
+			ended_at DATETIME,
+			duration_seconds INTEGER NOT NULL DEFAULT 0,
+			note TEXT NOT NULL DEFAULT '',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_time_entries_task_id ON time_entries(task_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_time_entries_started_at ON time_entries(started_at)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_time_entries_running ON time_entries(IFNULL(user_id, 0)) WHERE ended_at IS NULL`,
+	}
+	
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
+func (mm *MigrationManager) createTaskCommentsTable(db *sql.DB) error {
+	// Replies point at their parent comment; deleted comments keep their row,
+	// with deleted_at set, so that the replies below them stay threaded
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS task_comments (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			task_id INTEGER NOT NULL,
+			parent_id INTEGER,
+			user_id INTEGER NOT NULL,
+			body TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			deleted_at DATETIME,
+			FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+			FOREIGN KEY (parent_id) REFERENCES task_comments(id),
+			FOREIGN KEY (user_id) REFERENCES users(id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_task_comments_task_id ON task_comments(task_id)`,
+	}
+	
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
+func (mm *MigrationManager) createTaskAttachmentsTable(db *sql.DB) error {
+	// The file contents live on disk under their SHA-256 digest; several rows
+	// can share one file when the same content is uploaded more than once
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS task_attachments (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			task_id INTEGER NOT NULL,
+			user_id INTEGER,
+			file_name TEXT NOT NULL,
+			content_type TEXT NOT NULL,
+			size INTEGER NOT NULL,
+			sha256 TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+			FOREIGN KEY (user_id) REFERENCES users(id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_task_attachments_task_id ON task_attachments(task_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_task_attachments_sha256 ON task_attachments(sha256)`,
+	}
+	
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
+func (mm *MigrationManager) createTaskAssigneesTable(db *sql.DB) error {
+	// Each user has at most one role on a task: assignee, reviewer or watcher
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS task_assignees (
+			task_id INTEGER NOT NULL,
+			user_id INTEGER NOT NULL,
+			role TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (task_id, user_id),
+			FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_task_assignees_user_id ON task_assignees(user_id, role)`,
+	}
+	
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
+func (mm *MigrationManager) createProjectsTables(db *sql.DB) error {
+	// Projects own tasks, categories and tags. SQLite cannot drop the UNIQUE
+	// constraint on category and tag names, so both tables are rebuilt with
+	// names that are unique per project instead; NULL project_id is the
+	// global scope that existing rows end up in.
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS projects (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			description TEXT,
+			owner_id INTEGER,
+			default_priority INTEGER NOT NULL DEFAULT 2,
+			workflow TEXT NOT NULL DEFAULT '',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE SET NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS project_members (
+			project_id INTEGER NOT NULL,
+			user_id INTEGER NOT NULL,
+			role TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (project_id, user_id),
+			FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_project_members_user_id ON project_members(user_id)`,
+		`ALTER TABLE tasks ADD COLUMN project_id INTEGER REFERENCES projects(id)`,
+		`CREATE INDEX IF NOT EXISTS idx_tasks_project_id ON tasks(project_id)`,
+		`CREATE TABLE categories_new (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			project_id INTEGER,
+			name TEXT NOT NULL,
+			description TEXT,
+			color TEXT DEFAULT '#007bff',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+		)`,
+		`INSERT INTO categories_new (id, name, description, color, created_at, updated_at)
+			SELECT id, name, description, color, created_at, updated_at FROM categories`,
+		`DROP TABLE categories`,
+		`ALTER TABLE categories_new RENAME TO categories`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_project_name ON categories(COALESCE(project_id, 0), name)`,
+		`CREATE TABLE tags_new (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			project_id INTEGER,
+			name TEXT NOT NULL,
+			color TEXT DEFAULT '#6c757d',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+		)`,
+		`INSERT INTO tags_new (id, name, color, created_at)
+			SELECT id, name, color, created_at FROM tags`,
+		`DROP TABLE tags`,
+		`ALTER TABLE tags_new RENAME TO tags`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_project_name ON tags(COALESCE(project_id, 0), name)`,
+	}
+	
+	// The rebuild drops tables, so it either happens completely or not at all
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	
+	for _, query := range queries {
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
+	
+	return tx.Commit()
+}
+
+func (mm *MigrationManager) createBoardsTables(db *sql.DB) error {
+	// Columns select tasks by tag or, without a tag, by status. Cards only
+	// keep the manual order of tasks within the column they were placed in.
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS boards (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			owner_id INTEGER,
+			project_id INTEGER,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE,
+			FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_boards_owner_id ON boards(owner_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_boards_project_id ON boards(project_id)`,
+		`CREATE TABLE IF NOT EXISTS board_columns (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			board_id INTEGER NOT NULL,
+			name TEXT NOT NULL,
+			position INTEGER NOT NULL,
+			status INTEGER,
+			tag_id INTEGER,
+			wip_limit INTEGER NOT NULL DEFAULT 0,
+			FOREIGN KEY (board_id) REFERENCES boards(id) ON DELETE CASCADE,
+			FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE SET NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_board_columns_board_id ON board_columns(board_id, position)`,
+		`CREATE TABLE IF NOT EXISTS board_cards (
+			board_id INTEGER NOT NULL,
+			task_id INTEGER NOT NULL,
+			column_id INTEGER NOT NULL,
+			position INTEGER NOT NULL,
+			PRIMARY KEY (board_id, task_id),
+			FOREIGN KEY (board_id) REFERENCES boards(id) ON DELETE CASCADE,
+			FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+			FOREIGN KEY (column_id) REFERENCES board_columns(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_board_cards_column_id ON board_cards(column_id, position)`,
+	}
+	
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
+func (mm *MigrationManager) createSprintsTable(db *sql.DB) error {
+	// A task is in at most one sprint at a time. Sprint reports rebuild past
+	// days from the sprint_id changes in task_history.
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS sprints (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			goal TEXT NOT NULL DEFAULT '',
+			owner_id INTEGER,
+			project_id INTEGER,
+			start_date DATETIME NOT NULL,
+			end_date DATETIME NOT NULL,
+			closed_at DATETIME,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE,
+			FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_sprints_owner_id ON sprints(owner_id, start_date)`,
+		`CREATE INDEX IF NOT EXISTS idx_sprints_project_id ON sprints(project_id, start_date)`,
+		`ALTER TABLE tasks ADD COLUMN sprint_id INTEGER REFERENCES sprints(id)`,
+		`CREATE INDEX IF NOT EXISTS idx_tasks_sprint_id ON tasks(sprint_id)`,
+	}
+	
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
+func (mm *MigrationManager) createCustomFieldsTables(db *sql.DB) error {
+	// A field belongs to a category or to a project and applies to their
+	// tasks. Values are kept in a normalized text form that sorts and
+	// compares per field type.
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS custom_fields (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			type TEXT NOT NULL,
+			options TEXT NOT NULL DEFAULT '',
+			category_id INTEGER,
+			project_id INTEGER,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE,
+			FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_custom_fields_category_id ON custom_fields(category_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_custom_fields_project_id ON custom_fields(project_id)`,
+		`CREATE TABLE IF NOT EXISTS task_custom_values (
+			task_id INTEGER NOT NULL,
+			field_id INTEGER NOT NULL,
+			value TEXT NOT NULL,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (task_id, field_id),
+			FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+			FOREIGN KEY (field_id) REFERENCES custom_fields(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_task_custom_values_field_id ON task_custom_values(field_id, value)`,
+	}
+	
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
+// createChecklistsAndTemplatesTables creates the task_checklist_items and task_templates tables
+func (mm *MigrationManager) createChecklistsAndTemplatesTables(db *sql.DB) error {
+	// Checklist items are ordered by position within their task. A template
+	// keeps the definitions of its tasks as one JSON document.
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS task_checklist_items (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			task_id INTEGER NOT NULL,
+			text TEXT NOT NULL,
+			is_done BOOLEAN NOT NULL DEFAULT 0,
+			position INTEGER NOT NULL,
+			completed_at DATETIME,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_task_checklist_items_task_id ON task_checklist_items(task_id, position)`,
+		`CREATE TABLE IF NOT EXISTS task_templates (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			description TEXT NOT NULL DEFAULT '',
+			owner_id INTEGER,
+			project_id INTEGER,
+			tasks TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE SET NULL,
+			FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_task_templates_owner_id ON task_templates(owner_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_task_templates_project_id ON task_templates(project_id)`,
+	}
+	
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
+// addHiddenUntilToTasks adds the time snoozed tasks stay hidden until
+func (mm *MigrationManager) addHiddenUntilToTasks(db *sql.DB) error {
+	queries := []string{
+		`ALTER TABLE tasks ADD COLUMN hidden_until DATETIME`,
+		`CREATE INDEX IF NOT EXISTS idx_tasks_hidden_until ON tasks(hidden_until)`,
+	}
+	
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
+// createScoringWeightsTable creates the table of the per-user weights of the
+// next-up ranking. Users without a row use the default weights.
+func (mm *MigrationManager) createScoringWeightsTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS scoring_weights (
+		user_id INTEGER PRIMARY KEY,
+		weights TEXT NOT NULL,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	)`
+	
+	_, err := db.Exec(query)
+	return err
+}
+
+// createTaskRanksTable creates the table of manual task positions. A task has
+// at most one position per list, and no two tasks of a list share a key.
+func (mm *MigrationManager) createTaskRanksTable(db *sql.DB) error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS task_ranks (
+			list_key TEXT NOT NULL,
+			task_id INTEGER NOT NULL,
+			rank_key TEXT NOT NULL,
+			PRIMARY KEY (list_key, task_id),
+			FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+		)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_task_ranks_list_key ON task_ranks(list_key, rank_key)`,
+		`CREATE INDEX IF NOT EXISTS idx_task_ranks_task_id ON task_ranks(task_id)`,
+	}
+	
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
+// createCommandJournalTable creates the undo journal of task mutations. Rows
+// without a user belong to the local command line user.
+func (mm *MigrationManager) createCommandJournalTable(db *sql.DB) error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS command_journal (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER,
+			kind TEXT NOT NULL,
+			task_id INTEGER NOT NULL,
+			changes TEXT NOT NULL,
+			undone BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_command_journal_user_id ON command_journal(user_id, id)`,
+	}
+	
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
+func (mm *MigrationManager) createTaskLinksTable(db *sql.DB) error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS task_links (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			task_id INTEGER NOT NULL,
+			linked_task_id INTEGER NOT NULL,
+			link_type TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+			FOREIGN KEY (linked_task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+			UNIQUE(task_id, linked_task_id, link_type)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_task_links_linked_task_id ON task_links(linked_task_id)`,
+	}
+	
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
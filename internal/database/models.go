@@ -1,6 +1,8 @@
 package database
 
 import (
+	"encoding/json"
+	"fmt"
 	"time"
 )
 
@@ -18,11 +20,22 @@ type DatabaseTask struct {
 	CategoryID  *int      `json:"category_id,omitempty" db:"category_id"`
 	UserID      *int      `json:"user_id,omitempty" db:"user_id"`
 	IsArchived  bool      `json:"is_archived" db:"is_archived"`
+	RecurrenceRule *string `json:"recurrence_rule,omitempty" db:"recurrence_rule"`
+	ParentID       *int    `json:"parent_id,omitempty" db:"parent_id"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	EstimateMinutes *int      `json:"estimate_minutes,omitempty" db:"estimate_minutes"`
+	ProjectID       *int      `json:"project_id,omitempty" db:"project_id"`
+	SprintID        *int      `json:"sprint_id,omitempty" db:"sprint_id"`
+	// HiddenUntil keeps a snoozed task out of the default listings until then
+	HiddenUntil     *time.Time `json:"hidden_until,omitempty" db:"hidden_until"`
+	// CommentCount is computed when the task is read and is never written
+	CommentCount    int       `json:"comment_count" db:"comment_count"`
 }
 
 // Category represents task categories (Phase 2)
 type Category struct {
 	ID          int       `json:"id" db:"id"`
+	ProjectID   *int      `json:"project_id,omitempty" db:"project_id"` // nil for global categories
 	Name        string    `json:"name" db:"name"`
 	Description string    `json:"description" db:"description"`
 	Color       string    `json:"color" db:"color"`
@@ -33,6 +46,7 @@ type Category struct {
 // Tag represents task tags (Phase 2)
 type Tag struct {
 	ID        int       `json:"id" db:"id"`
+	ProjectID *int      `json:"project_id,omitempty" db:"project_id"` // nil for global tags
 	Name      string    `json:"name" db:"name"`
 	Color     string    `json:"color" db:"color"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
@@ -52,6 +66,152 @@ type TaskDependency struct {
 	CreatedAt        time.Time `json:"created_at" db:"created_at"`
 }
 
+// BlocksLinkType is the type GetTaskLinks gives task dependencies: the task
+// depended on blocks the task that depends on it
+const BlocksLinkType = "blocks"
+
+// TaskLink is a typed link from one task to another, such as a task that
+// duplicates another one. Blocking links are stored as task dependencies.
+type TaskLink struct {
+	TaskID       int       `json:"task_id" db:"task_id"`
+	LinkedTaskID int       `json:"linked_task_id" db:"linked_task_id"`
+	Type         string    `json:"link_type" db:"link_type"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// TaskHistoryEntry records a change to one field of a task. OldValue and
+// NewValue hold the column values as text and are nil when the field was unset.
+type TaskHistoryEntry struct {
+	ID        int       `json:"id" db:"id"`
+	TaskID    int       `json:"task_id" db:"task_id"`
+	UserID    *int      `json:"user_id,omitempty" db:"user_id"` // nil for system changes
+	Field     string    `json:"field" db:"field"`
+	OldValue  *string   `json:"old_value,omitempty" db:"old_value"`
+	NewValue  *string   `json:"new_value,omitempty" db:"new_value"`
+	ChangedAt time.Time `json:"changed_at" db:"changed_at"`
+}
+
+// TimeEntry records time spent on a task. A running timer has no EndedAt;
+// DurationSeconds is set once the entry is complete.
+type TimeEntry struct {
+	ID              int        `json:"id" db:"id"`
+	TaskID          int        `json:"task_id" db:"task_id"`
+	UserID          *int       `json:"user_id,omitempty" db:"user_id"` // nil for the CLI
+	StartedAt       time.Time  `json:"started_at" db:"started_at"`
+	EndedAt         *time.Time `json:"ended_at,omitempty" db:"ended_at"`
+	DurationSeconds int        `json:"duration_seconds" db:"duration_seconds"`
+	Note            string     `json:"note" db:"note"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Comment is a markdown comment on a task. Replies have a ParentID; a deleted
+// comment keeps its place in the thread with an empty body.
+type Comment struct {
+	ID        int        `json:"id" db:"id"`
+	TaskID    int        `json:"task_id" db:"task_id"`
+	ParentID  *int       `json:"parent_id,omitempty" db:"parent_id"`
+	UserID    int        `json:"user_id" db:"user_id"`
+	Username  string     `json:"username" db:"username"` // read from users, never written
+	Body      string     `json:"body" db:"body"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+}
+
+// Attachment is a file attached to a task. The contents are stored once per
+// SHA-256 digest, outside the database.
+type Attachment struct {
+	ID          int       `json:"id" db:"id"`
+	TaskID      int       `json:"task_id" db:"task_id"`
+	UserID      *int      `json:"user_id,omitempty" db:"user_id"`
+	Username    string    `json:"username,omitempty" db:"username"` // read from users, never written
+	FileName    string    `json:"file_name" db:"file_name"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	Size        int64     `json:"size" db:"size"`
+	SHA256      string    `json:"sha256" db:"sha256"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// TaskAssignee is a user other than the owner who takes part in a task, as
+// an assignee, a reviewer or a watcher
+type TaskAssignee struct {
+	TaskID    int       `json:"task_id" db:"task_id"`
+	UserID    int       `json:"user_id" db:"user_id"`
+	Username  string    `json:"username" db:"username"` // read from users, never written
+	Role      string    `json:"role" db:"role"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Project groups tasks, categories and tags of a team. DefaultPriority and
+// Workflow are the project settings; Workflow holds the allowed status
+// transitions as JSON and is empty when the project uses the global workflow.
+type Project struct {
+	ID              int       `json:"id" db:"id"`
+	Name            string    `json:"name" db:"name"`
+	Description     string    `json:"description" db:"description"`
+	OwnerID         *int      `json:"owner_id,omitempty" db:"owner_id"`
+	DefaultPriority int       `json:"default_priority" db:"default_priority"`
+	Workflow        string    `json:"workflow" db:"workflow"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ProjectMember gives a user access to a project, as an owner or a member
+type ProjectMember struct {
+	ProjectID int       `json:"project_id" db:"project_id"`
+	UserID    int       `json:"user_id" db:"user_id"`
+	Username  string    `json:"username" db:"username"` // read from users, never written
+	Role      string    `json:"role" db:"role"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Board is a kanban board over the tasks of its owner, or over the tasks of
+// a project when ProjectID is set
+type Board struct {
+	ID        int       `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	OwnerID   *int      `json:"owner_id,omitempty" db:"owner_id"`
+	ProjectID *int      `json:"project_id,omitempty" db:"project_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// BoardColumn is a column of a board that shows the tasks with its tag or,
+// when TagID is nil, the tasks with its status. A WIPLimit of 0 means the
+// column has no work-in-progress limit.
+type BoardColumn struct {
+	ID       int    `json:"id" db:"id"`
+	BoardID  int    `json:"board_id" db:"board_id"`
+	Name     string `json:"name" db:"name"`
+	Position int    `json:"position" db:"position"`
+	Status   *int   `json:"status,omitempty" db:"status"`
+	TagID    *int   `json:"tag_id,omitempty" db:"tag_id"`
+	TagName  string `json:"tag_name,omitempty" db:"tag_name"` // read from tags, never written
+	WIPLimit int    `json:"wip_limit" db:"wip_limit"`
+}
+
+// BoardCard is the manual position of a task within a column of a board
+type BoardCard struct {
+	BoardID  int `json:"board_id" db:"board_id"`
+	TaskID   int `json:"task_id" db:"task_id"`
+	ColumnID int `json:"column_id" db:"column_id"`
+	Position int `json:"position" db:"position"`
+}
+
+// BoardCardMove moves a task into a column of a board. Status, when set,
+// becomes the status of the task; AddTagID and RemoveTagIDs change its tags.
+// Order lists the tasks of the column, including the moved one, in their new
+// order.
+type BoardCardMove struct {
+	BoardID      int
+	ColumnID     int
+	TaskID       int
+	Status       *int
+	AddTagID     *int
+	RemoveTagIDs []int
+	Order        []int
+}
+
 // User represents system users (Phase 5)
 type User struct {
 	ID        int       `json:"id" db:"id"`
@@ -63,3 +223,136 @@ type User struct {
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// Sprint is an iteration from StartDate to EndDate, both dates included. It
+// belongs to its owner or, when ProjectID is set, to a project. ClosedAt is
+// set once the sprint is closed.
+type Sprint struct {
+	ID        int        `json:"id" db:"id"`
+	Name      string     `json:"name" db:"name"`
+	Goal      string     `json:"goal" db:"goal"`
+	OwnerID   *int       `json:"owner_id,omitempty" db:"owner_id"`
+	ProjectID *int       `json:"project_id,omitempty" db:"project_id"`
+	StartDate time.Time  `json:"start_date" db:"start_date"`
+	EndDate   time.Time  `json:"end_date" db:"end_date"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty" db:"closed_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// CustomField is a typed field defined for the tasks of a category or, when
+// ProjectID is set, of a project. Options lists the allowed values of enum
+// fields.
+type CustomField struct {
+	ID         int       `json:"id" db:"id"`
+	Name       string    `json:"name" db:"name"`
+	Type       string    `json:"type" db:"type"`
+	Options    []string  `json:"options,omitempty" db:"options"`
+	CategoryID *int      `json:"category_id,omitempty" db:"category_id"`
+	ProjectID  *int      `json:"project_id,omitempty" db:"project_id"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CustomFieldValue is the value of a custom field on a task, kept in the
+// normalized form returned by CustomField.NormalizeValue
+type CustomFieldValue struct {
+	TaskID    int       `json:"task_id" db:"task_id"`
+	FieldID   int       `json:"field_id" db:"field_id"`
+	Name      string    `json:"name" db:"name"` // read from custom_fields, never written
+	Type      string    `json:"type" db:"type"` // read from custom_fields, never written
+	Value     string    `json:"value" db:"value"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ChecklistItem is an item of the checklist of a task. Items are ordered by
+// Position, starting at 0; CompletedAt is set while the item is done.
+type ChecklistItem struct {
+	ID          int        `json:"id" db:"id"`
+	TaskID      int        `json:"task_id" db:"task_id"`
+	Text        string     `json:"text" db:"text"`
+	Done        bool       `json:"done" db:"is_done"`
+	Position    int        `json:"position" db:"position"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// TaskTemplate describes a set of tasks that are created together. It
+// belongs to its owner or, when ProjectID is set, to a project. Tasks is
+// stored as JSON.
+type TaskTemplate struct {
+	ID          int            `json:"id" db:"id"`
+	Name        string         `json:"name" db:"name"`
+	Description string         `json:"description" db:"description"`
+	OwnerID     *int           `json:"owner_id,omitempty" db:"owner_id"`
+	ProjectID   *int           `json:"project_id,omitempty" db:"project_id"`
+	Tasks       []TemplateTask `json:"tasks" db:"tasks"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// TemplateTask is a task of a template. DueOffsetDays places the due date
+// relative to the day the template is instantiated. Parent and DependsOn
+// refer to earlier tasks of the same template by index.
+type TemplateTask struct {
+	Title         string   `json:"title"`
+	Description   string   `json:"description,omitempty"`
+	Priority      int      `json:"priority"`
+	CategoryID    *int     `json:"category_id,omitempty"`
+	TagIDs        []int    `json:"tag_ids,omitempty"`
+	DueOffsetDays *int     `json:"due_offset_days,omitempty"`
+	Checklist     []string `json:"checklist,omitempty"`
+	Parent        *int     `json:"parent,omitempty"`
+	DependsOn     []int    `json:"depends_on,omitempty"`
+}
+
+// ScoringWeights are the weights a user gives to the factors of the next-up
+// ranking, by factor name. Weights is stored as JSON.
+type ScoringWeights struct {
+	UserID    int                `json:"user_id" db:"user_id"`
+	Weights   map[string]float64 `json:"weights" db:"weights"`
+	UpdatedAt time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+// TaskRank is the position of a task in a manually ordered list. Tasks are
+// ordered by Key, a rank key from the rank package, and tasks without a
+// TaskRank in a list come after those with one.
+type TaskRank struct {
+	List   string `json:"list" db:"list_key"`
+	TaskID int    `json:"task_id" db:"task_id"`
+	Key    string `json:"key" db:"rank_key"`
+}
+
+// PersonalTaskList returns the list key of a user's own task order
+func PersonalTaskList(userID int) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+// ProjectTaskList returns the list key of a project's shared task order
+func ProjectTaskList(projectID int) string {
+	return fmt.Sprintf("project:%d", projectID)
+}
+
+// JournalCommand is a recorded task mutation in a user's undo journal.
+// UserID is nil for the journal of the local command line user, and Changes
+// holds the task states before and after the command as JSON.
+type JournalCommand struct {
+	ID        int             `json:"id" db:"id"`
+	UserID    *int            `json:"user_id,omitempty" db:"user_id"`
+	Kind      string          `json:"kind" db:"kind"`
+	TaskID    int             `json:"task_id" db:"task_id"`
+	Changes   json.RawMessage `json:"changes" db:"changes"`
+	Undone    bool            `json:"undone" db:"undone"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
+
+// NewTask is a task created by CreateTasks together with its tags and
+// checklist. Parent and DependsOn refer to earlier tasks of the same call by
+// index.
+type NewTask struct {
+	Task      *DatabaseTask
+	TagIDs    []int
+	Checklist []string
+	Parent    *int
+	DependsOn []int
+}
@@ -1,13 +1,25 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 )
 
 // Repository defines the interface for task data operations
 type Repository interface {
+	// WithContext returns a repository whose queries run with ctx, so that
+	// cancellation and deadlines reach the database
+	WithContext(ctx context.Context) Repository
+
+	// Transaction runs fn with a repository whose operations all take part in
+	// one transaction, committed only when fn returns nil. Nested calls use a
+	// savepoint, so that a failing fn only undoes its own changes.
+	Transaction(fn func(repository Repository) error) error
+	
 	// Task operations
 	CreateTask(task *DatabaseTask) error
 	GetTask(id int) (*DatabaseTask, error)
@@ -20,6 +32,10 @@ type Repository interface {
 	
 	// Future operations (will be implemented in later phases)
 	GetTasksByUser(userID int) ([]DatabaseTask, error)
+	// GetTasksByAssignee returns the tasks the user takes part in with the
+	// given role, or with any role when role is empty
+	GetTasksByAssignee(userID int, role string) ([]DatabaseTask, error)
+	GetTasksByProject(projectID int) ([]DatabaseTask, error)
 	GetTasksByCategory(categoryID int) ([]DatabaseTask, error)
 	SearchTasks(query string) ([]DatabaseTask, error)
 	SearchTasksByUser(userID int, query string) ([]DatabaseTask, error)
@@ -34,10 +50,155 @@ type Repository interface {
 	GetTasksThatTaskDependsOn(taskID int) ([]DatabaseTask, error)
 	CheckCircularDependency(taskID, dependsOnTaskID int) (bool, error)
 	
+	// Task link operations
+	AddTaskLink(link *TaskLink) error
+	RemoveTaskLink(taskID, linkedTaskID int, linkType string) error
+	GetTaskLinks(taskID int) ([]TaskLink, error)
+	
+	// Task hierarchy operations
+	GetChildTasks(parentID int) ([]DatabaseTask, error)
+	GetAncestorTasks(taskID int) ([]DatabaseTask, error)
+	GetDescendantTasks(taskID int) ([]DatabaseTask, error)
+	
+	// Archive operations
+	GetArchivedTasks() ([]DatabaseTask, error)
+	GetArchivedTasksByUser(userID int) ([]DatabaseTask, error)
+	GetCompletedTasksBefore(cutoff time.Time) ([]DatabaseTask, error)
+	// GetWokenTasks returns snoozed tasks whose hidden_until has passed
+	GetWokenTasks(cutoff time.Time) ([]DatabaseTask, error)
+	
+	// Trash operations: DeleteTask moves a task to the trash
+	GetDeletedTask(id int) (*DatabaseTask, error)
+	GetDeletedTasks() ([]DatabaseTask, error)
+	GetDeletedTasksByUser(userID int) ([]DatabaseTask, error)
+	RestoreTask(id int) error
+	PurgeDeletedTasksBefore(cutoff time.Time) (int, error)
+	
+	// Task history operations: every task mutation above is recorded
+	GetTaskHistory(taskID int) ([]TaskHistoryEntry, error)
+	
+	// Time tracking operations: userID is nil for time tracked from the CLI
+	StartTimer(entry *TimeEntry) error
+	StopTimer(userID *int) (*TimeEntry, error)
+	GetRunningTimer(userID *int) (*TimeEntry, error)
+	CreateTimeEntry(entry *TimeEntry) error
+	GetTimeEntries(taskID int) ([]TimeEntry, error)
+	GetTimeEntriesBetween(from, to time.Time) ([]TimeEntry, error)
+	
+	// Comment operations: DeleteComment keeps the comment as a placeholder
+	// so that its replies stay threaded
+	CreateComment(comment *Comment) error
+	GetComment(id int) (*Comment, error)
+	GetTaskComments(taskID int) ([]Comment, error)
+	UpdateComment(comment *Comment) error
+	DeleteComment(id int) error
+	
+	// Assignee operations: SetTaskAssignees replaces every assignee of a task
+	SetTaskAssignees(taskID int, assignees []TaskAssignee) error
+	GetTaskAssignees(taskID int) ([]TaskAssignee, error)
+	
+	// Attachment operations: several attachments can share the file of one
+	// SHA-256 digest, so CountAttachmentsBySHA tells when it can be removed
+	CreateAttachment(attachment *Attachment) error
+	GetAttachment(id int) (*Attachment, error)
+	GetTaskAttachments(taskID int) ([]Attachment, error)
+	DeleteAttachment(id int) error
+	CountAttachmentsBySHA(sha256 string) (int, error)
+	
+	// Project operations: CreateProject makes the owner a member, and
+	// SetProjectMember adds a member or changes their role
+	CreateProject(project *Project) error
+	GetProject(id int) (*Project, error)
+	GetProjectsByUser(userID int) ([]Project, error)
+	UpdateProject(project *Project) error
+	DeleteProject(id int) error
+	SetProjectMember(member *ProjectMember) error
+	RemoveProjectMember(projectID, userID int) error
+	GetProjectMembers(projectID int) ([]ProjectMember, error)
+	
+	// Board operations: UpdateBoard keeps the columns with a known ID, adds
+	// the others and removes those left out with their cards. MoveBoardCard
+	// applies a whole move in a single transaction.
+	CreateBoard(board *Board, columns []BoardColumn) error
+	GetBoard(id int) (*Board, error)
+	GetBoardsByUser(userID int) ([]Board, error)
+	GetBoardsByTask(taskID int) ([]Board, error)
+	UpdateBoard(board *Board, columns []BoardColumn) error
+	DeleteBoard(id int) error
+	GetBoardColumns(boardID int) ([]BoardColumn, error)
+	GetBoardCards(boardID int) ([]BoardCard, error)
+	MoveBoardCard(move *BoardCardMove) error
+	
+	// Sprint operations: tasks join and leave sprints through UpdateTask.
+	// CloseSprint moves the unfinished tasks on to another sprint or back to
+	// the backlog, and DeleteSprint moves all of them back to the backlog.
+	CreateSprint(sprint *Sprint) error
+	GetSprint(id int) (*Sprint, error)
+	GetSprints(ownerID int, projectID *int) ([]Sprint, error)
+	UpdateSprint(sprint *Sprint) error
+	DeleteSprint(id int) error
+	CloseSprint(id int, carryOverTo *int) (*Sprint, error)
+	GetTasksBySprint(sprintID int) ([]DatabaseTask, error)
+	GetSprintHistoryTasks(sprintID int) ([]DatabaseTask, error)
+	
+	// Custom field operations: a field belongs to a category or a project and
+	// applies to their tasks. Values are stored normalized, and
+	// SetTaskCustomValue removes the value when it is empty.
+	CreateCustomField(field *CustomField) error
+	GetCustomField(id int) (*CustomField, error)
+	GetCustomFields(categoryID, projectID *int) ([]CustomField, error)
+	GetAllCustomFields() ([]CustomField, error)
+	UpdateCustomField(field *CustomField) error
+	DeleteCustomField(id int) error
+	GetTaskCustomValues(taskID int) ([]CustomFieldValue, error)
+	SetTaskCustomValue(taskID, fieldID int, value string) error
+	
+	// Checklist operations: CreateChecklistItem appends the item to the
+	// checklist of its task, and UpdateChecklistItem moves it to its Position
+	// while keeping the positions of the other items contiguous.
+	CreateChecklistItem(item *ChecklistItem) error
+	GetChecklistItem(id int) (*ChecklistItem, error)
+	GetChecklistItems(taskID int) ([]ChecklistItem, error)
+	UpdateChecklistItem(item *ChecklistItem) error
+	DeleteChecklistItem(id int) error
+	
+	// Template operations: CreateTasks creates tasks with their tags,
+	// checklists, parents and dependencies in one transaction, which is how
+	// templates are instantiated.
+	CreateTemplate(template *TaskTemplate) error
+	GetTemplate(id int) (*TaskTemplate, error)
+	GetTemplates(ownerID int, projectID *int) ([]TaskTemplate, error)
+	UpdateTemplate(template *TaskTemplate) error
+	DeleteTemplate(id int) error
+	CreateTasks(tasks []NewTask) error
+	
+	// Scoring weight operations: GetScoringWeights returns nil when the user
+	// has not set any weights, and SetScoringWeights replaces them.
+	GetScoringWeights(userID int) (*ScoringWeights, error)
+	SetScoringWeights(weights *ScoringWeights) error
+	DeleteScoringWeights(userID int) error
+	
+	// Task rank operations: GetTaskRanks returns the positions of a list in
+	// order, SetTaskRank moves one task, and ReplaceTaskRanks rewrites the
+	// positions of a whole list when it is rebalanced.
+	GetTaskRanks(list string) ([]TaskRank, error)
+	SetTaskRank(rank *TaskRank) error
+	ReplaceTaskRanks(list string, ranks []TaskRank) error
+	
+	// Command journal operations: a nil user ID stands for the local command
+	// line user. CreateJournalCommand drops the user's undone commands and
+	// keeps only the latest limit; the undoable and redoable commands are nil
+	// when there are none.
+	CreateJournalCommand(command *JournalCommand, limit int) error
+	GetUndoableCommand(userID *int) (*JournalCommand, error)
+	GetRedoableCommand(userID *int) (*JournalCommand, error)
+	SetJournalCommandUndone(id int, undone bool) error
+	
 	// Category operations (Phase 2)
 	CreateCategory(category *Category) error
 	GetCategory(id int) (*Category, error)
 	GetAllCategories() ([]Category, error)
+	GetCategoriesByProject(projectID *int) ([]Category, error)
 	UpdateCategory(category *Category) error
 	DeleteCategory(id int) error
 	
@@ -45,6 +206,7 @@ type Repository interface {
 	CreateTag(tag *Tag) error
 	GetTag(id int) (*Tag, error)
 	GetAllTags() ([]Tag, error)
+	GetTagsByProject(projectID *int) ([]Tag, error)
 	UpdateTag(tag *Tag) error
 	DeleteTag(id int) error
 	
@@ -66,22 +228,118 @@ type Repository interface {
 
 // SQLiteRepository implements Repository interface for SQLite
 type SQLiteRepository struct {
-	db *sql.DB
+	db  *sql.DB
+	ctx context.Context
+	// tx is the transaction every operation joins, if any, and depth the
+	// number of savepoints it is nested in
+	tx    *sql.Tx
+	depth int
 }
 
 // NewSQLiteRepository creates a new SQLite repository
 func NewSQLiteRepository(db *sql.DB) Repository {
-	return &SQLiteRepository{db: db}
+	return &SQLiteRepository{db: db, ctx: context.Background()}
+}
+
+// WithContext returns a copy of the repository that runs every query with ctx
+func (r *SQLiteRepository) WithContext(ctx context.Context) Repository {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	bound := *r
+	bound.ctx = ctx
+	return &bound
+}
+
+// taskColumns is the column list selected by every task query, in the
+// order expected by scanTask. Queries must alias the tasks table as t.
+const taskColumns = `t.id, t.title, t.description, t.priority, t.status, t.created_at, t.updated_at, t.due_date, t.user_id, t.category_id, t.is_archived, t.recurrence_rule, t.parent_id, t.deleted_at, t.estimate_minutes, t.project_id, t.sprint_id, t.hidden_until,
+	(SELECT COUNT(*) FROM task_comments tc WHERE tc.task_id = t.id AND tc.deleted_at IS NULL) AS comment_count`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanTask scans a single row selected with taskColumns
+func scanTask(row rowScanner) (*DatabaseTask, error) {
+	task := &DatabaseTask{}
+	err := row.Scan(
+		&task.ID,
+		&task.Title,
+		&task.Description,
+		&task.Priority,
+		&task.Status,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+		&task.DueDate,
+		&task.UserID,
+		&task.CategoryID,
+		&task.IsArchived,
+		&task.RecurrenceRule,
+		&task.ParentID,
+		&task.DeletedAt,
+		&task.EstimateMinutes,
+		&task.ProjectID,
+		&task.SprintID,
+		&task.HiddenUntil,
+		&task.CommentCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	
+	return task, nil
+}
+
+// scanTasks scans all rows selected with taskColumns
+func scanTasks(rows *sql.Rows) ([]DatabaseTask, error) {
+	var tasks []DatabaseTask
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		tasks = append(tasks, *task)
+	}
+	
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tasks: %w", err)
+	}
+	
+	return tasks, nil
 }
 
 // Task operations implementation
 
 func (r *SQLiteRepository) CreateTask(task *DatabaseTask) error {
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin task creation: %w", err)
+	}
+	defer tx.Rollback()
+	
+	if err := r.insertTask(tx, task); err != nil {
+		return err
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit task creation: %w", err)
+	}
+	
+	task.CreatedAt = time.Now()
+	task.UpdatedAt = time.Now()
+	
+	return nil
+}
+
+// insertTask inserts a task within tx, records its creation and sets its ID
+func (r *SQLiteRepository) insertTask(tx *txn, task *DatabaseTask) error {
 	query := `
-	INSERT INTO tasks (title, description, priority, status, due_date, user_id, category_id, is_archived)
-	VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	INSERT INTO tasks (title, description, priority, status, due_date, user_id, category_id, is_archived, recurrence_rule, parent_id, estimate_minutes, project_id, sprint_id, hidden_until)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	
-	result, err := r.db.Exec(query, 
+	result, err := tx.ExecContext(r.ctx, query, 
 		task.Title, 
 		task.Description, 
 		task.Priority, 
@@ -89,7 +347,13 @@ func (r *SQLiteRepository) CreateTask(task *DatabaseTask) error {
 		task.DueDate, 
 		task.UserID, 
 		task.CategoryID, 
-		task.IsArchived)
+		task.IsArchived,
+		task.RecurrenceRule,
+		task.ParentID,
+		task.EstimateMinutes,
+		task.ProjectID,
+		task.SprintID,
+		task.HiddenUntil)
 	
 	if err != nil {
 		return fmt.Errorf("failed to create task: %w", err)
@@ -100,33 +364,20 @@ func (r *SQLiteRepository) CreateTask(task *DatabaseTask) error {
 		return fmt.Errorf("failed to get task ID: %w", err)
 	}
 	
-	task.ID = int(id)
-	task.CreatedAt = time.Now()
-	task.UpdatedAt = time.Now()
+	if err := r.recordHistory(tx, int(id), historyChange{field: "created", newValue: textValue(task.Title)}); err != nil {
+		return err
+	}
 	
+	task.ID = int(id)
 	return nil
 }
 
 func (r *SQLiteRepository) GetTask(id int) (*DatabaseTask, error) {
 	query := `
-	SELECT id, title, description, priority, status, created_at, updated_at, due_date, user_id, category_id, is_archived
-	FROM tasks WHERE id = ?`
-	
-	task := &DatabaseTask{}
-	err := r.db.QueryRow(query, id).Scan(
-		&task.ID,
-		&task.Title,
-		&task.Description,
-		&task.Priority,
-		&task.Status,
-		&task.CreatedAt,
-		&task.UpdatedAt,
-		&task.DueDate,
-		&task.UserID,
-		&task.CategoryID,
-		&task.IsArchived,
-	)
+	SELECT ` + taskColumns + `
+	FROM tasks t WHERE t.id = ? AND t.deleted_at IS NULL`
 	
+	task, err := scanTask(r.conn().QueryRowContext(r.ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("task with ID %d not found", id)
@@ -140,12 +391,30 @@ func (r *SQLiteRepository) GetTask(id int) (*DatabaseTask, error) {
 func (r *SQLiteRepository) UpdateTask(task *DatabaseTask) error {
 	query := `
 	UPDATE tasks 
-	SET title = ?, description = ?, priority = ?, status = ?, updated_at = ?, due_date = ?, user_id = ?, category_id = ?, is_archived = ?
-	WHERE id = ?`
+	SET title = ?, description = ?, priority = ?, status = ?, updated_at = ?, due_date = ?, user_id = ?, category_id = ?, is_archived = ?, recurrence_rule = ?, parent_id = ?, estimate_minutes = ?, project_id = ?, sprint_id = ?, hidden_until = ?
+	WHERE id = ? AND deleted_at IS NULL`
+	
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin task update: %w", err)
+	}
+	defer tx.Rollback()
+	
+	// The stored task is read in the same transaction so that the history
+	// records exactly what this update replaced
+	current, err := scanTask(tx.QueryRowContext(r.ctx, `
+	SELECT `+taskColumns+`
+	FROM tasks t WHERE t.id = ? AND t.deleted_at IS NULL`, task.ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("task with ID %d not found", task.ID)
+		}
+		return fmt.Errorf("failed to update task: %w", err)
+	}
 	
 	task.UpdatedAt = time.Now()
 	
-	result, err := r.db.Exec(query,
+	_, err = tx.ExecContext(r.ctx, query,
 		task.Title,
 		task.Description,
 		task.Priority,
@@ -155,6 +424,12 @@ func (r *SQLiteRepository) UpdateTask(task *DatabaseTask) error {
 		task.UserID,
 		task.CategoryID,
 		task.IsArchived,
+		task.RecurrenceRule,
+		task.ParentID,
+		task.EstimateMinutes,
+		task.ProjectID,
+		task.SprintID,
+		task.HiddenUntil,
 		task.ID,
 	)
 	
@@ -162,22 +437,41 @@ func (r *SQLiteRepository) UpdateTask(task *DatabaseTask) error {
 		return fmt.Errorf("failed to update task: %w", err)
 	}
 	
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+	if err := r.recordHistory(tx, task.ID, taskChanges(current, task)...); err != nil {
+		return err
 	}
 	
-	if rowsAffected == 0 {
-		return fmt.Errorf("task with ID %d not found", task.ID)
+	// Values of custom fields stop applying when the task leaves their
+	// category or project
+	if !sameID(current.CategoryID, task.CategoryID) || !sameID(current.ProjectID, task.ProjectID) {
+		query := `
+		DELETE FROM task_custom_values
+		WHERE task_id = ? AND field_id NOT IN (SELECT id FROM custom_fields WHERE category_id = ? OR project_id = ?)`
+		if _, err := tx.ExecContext(r.ctx, query, task.ID, task.CategoryID, task.ProjectID); err != nil {
+			return fmt.Errorf("failed to remove custom field values: %w", err)
+		}
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit task update: %w", err)
 	}
 	
 	return nil
 }
 
+// DeleteTask moves a task to the trash. Its tags and dependencies are kept
+// so that RestoreTask can bring it back unchanged.
 func (r *SQLiteRepository) DeleteTask(id int) error {
-	query := `DELETE FROM tasks WHERE id = ?`
+	query := `UPDATE tasks SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`
+	
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin task deletion: %w", err)
+	}
+	defer tx.Rollback()
 	
-	result, err := r.db.Exec(query, id)
+	deletedAt := time.Now()
+	result, err := tx.ExecContext(r.ctx, query, deletedAt, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete task: %w", err)
 	}
@@ -191,160 +485,83 @@ func (r *SQLiteRepository) DeleteTask(id int) error {
 		return fmt.Errorf("task with ID %d not found", id)
 	}
 	
+	if err := r.recordHistory(tx, id, historyChange{field: "deleted_at", newValue: timeValue(&deletedAt)}); err != nil {
+		return err
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit task deletion: %w", err)
+	}
+	
 	return nil
 }
 
 func (r *SQLiteRepository) GetAllTasks() ([]DatabaseTask, error) {
 	query := `
-	SELECT id, title, description, priority, status, created_at, updated_at, due_date, user_id, category_id, is_archived
-	FROM tasks 
-	WHERE is_archived = FALSE
+	SELECT ` + taskColumns + `
+	FROM tasks t 
+	WHERE is_archived = FALSE AND deleted_at IS NULL
 	ORDER BY created_at DESC`
 	
-	rows, err := r.db.Query(query)
+	rows, err := r.conn().QueryContext(r.ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tasks: %w", err)
 	}
 	defer rows.Close()
 	
-	var tasks []DatabaseTask
-	for rows.Next() {
-		task := DatabaseTask{}
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Priority,
-			&task.Status,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-			&task.DueDate,
-			&task.UserID,
-			&task.CategoryID,
-			&task.IsArchived,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan task: %w", err)
-		}
-		tasks = append(tasks, task)
-	}
-	
-	return tasks, nil
+	return scanTasks(rows)
 }
 
 func (r *SQLiteRepository) GetTasksByStatus(status int) ([]DatabaseTask, error) {
 	query := `
-	SELECT id, title, description, priority, status, created_at, updated_at, due_date, user_id, category_id, is_archived
-	FROM tasks 
-	WHERE status = ? AND is_archived = FALSE
+	SELECT ` + taskColumns + `
+	FROM tasks t 
+	WHERE status = ? AND is_archived = FALSE AND deleted_at IS NULL
 	ORDER BY created_at DESC`
 	
-	rows, err := r.db.Query(query, status)
+	rows, err := r.conn().QueryContext(r.ctx, query, status)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tasks by status: %w", err)
 	}
 	defer rows.Close()
 	
-	var tasks []DatabaseTask
-	for rows.Next() {
-		task := DatabaseTask{}
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Priority,
-			&task.Status,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-			&task.DueDate,
-			&task.UserID,
-			&task.CategoryID,
-			&task.IsArchived,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan task: %w", err)
-		}
-		tasks = append(tasks, task)
-	}
-	
-	return tasks, nil
+	return scanTasks(rows)
 }
 
 func (r *SQLiteRepository) GetTasksByPriority(priority int) ([]DatabaseTask, error) {
 	query := `
-	SELECT id, title, description, priority, status, created_at, updated_at, due_date, user_id, category_id, is_archived
-	FROM tasks 
-	WHERE priority = ? AND is_archived = FALSE
+	SELECT ` + taskColumns + `
+	FROM tasks t 
+	WHERE priority = ? AND is_archived = FALSE AND deleted_at IS NULL
 	ORDER BY created_at DESC`
 	
-	rows, err := r.db.Query(query, priority)
+	rows, err := r.conn().QueryContext(r.ctx, query, priority)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tasks by priority: %w", err)
 	}
 	defer rows.Close()
 	
-	var tasks []DatabaseTask
-	for rows.Next() {
-		task := DatabaseTask{}
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Priority,
-			&task.Status,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-			&task.DueDate,
-			&task.UserID,
-			&task.CategoryID,
-			&task.IsArchived,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan task: %w", err)
-		}
-		tasks = append(tasks, task)
-	}
-	
-	return tasks, nil
+	return scanTasks(rows)
 }
 
 func (r *SQLiteRepository) GetOverdueTasks() ([]DatabaseTask, error) {
 	query := `
-	SELECT id, title, description, priority, status, created_at, updated_at, due_date, user_id, category_id, is_archived
-	FROM tasks 
-	WHERE due_date < ? AND status != 2 AND is_archived = FALSE
+	SELECT ` + taskColumns + `
+	FROM tasks t 
+	WHERE due_date < ? AND status != 2 AND is_archived = FALSE AND deleted_at IS NULL
+	AND (hidden_until IS NULL OR hidden_until <= ?)
 	ORDER BY due_date ASC`
 	
+	// Snoozed tasks are not overdue until they wake up. hidden_until is
+	// stored in UTC, since times are compared as text.
 	now := time.Now()
-	rows, err := r.db.Query(query, now)
+	rows, err := r.conn().QueryContext(r.ctx, query, now, now.UTC())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get overdue tasks: %w", err)
 	}
 	defer rows.Close()
 	
-	var tasks []DatabaseTask
-	for rows.Next() {
-		task := DatabaseTask{}
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Priority,
-			&task.Status,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-			&task.DueDate,
-			&task.UserID,
-			&task.CategoryID,
-			&task.IsArchived,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan task: %w", err)
-		}
-		tasks = append(tasks, task)
-	}
-	
-	return tasks, nil
+	return scanTasks(rows)
 }
 
 // Placeholder implementations for future phases
@@ -352,86 +569,78 @@ func (r *SQLiteRepository) GetOverdueTasks() ([]DatabaseTask, error) {
 
 func (r *SQLiteRepository) GetTasksByCategory(categoryID int) ([]DatabaseTask, error) {
 	query := `
-	SELECT t.id, t.title, t.description, t.priority, t.status, t.created_at, t.updated_at, t.due_date, t.user_id, t.category_id, t.is_archived
+	SELECT ` + taskColumns + `
 	FROM tasks t
-	WHERE t.category_id = ? AND t.is_archived = FALSE
+	WHERE t.category_id = ? AND t.is_archived = FALSE AND t.deleted_at IS NULL
 	ORDER BY t.created_at DESC`
 	
-	rows, err := r.db.Query(query, categoryID)
+	rows, err := r.conn().QueryContext(r.ctx, query, categoryID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tasks by category: %w", err)
 	}
 	defer rows.Close()
 	
-	var tasks []DatabaseTask
-	for rows.Next() {
-		task := DatabaseTask{}
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Priority,
-			&task.Status,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-			&task.DueDate,
-			&task.UserID,
-			&task.CategoryID,
-			&task.IsArchived,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan task: %w", err)
-		}
-		tasks = append(tasks, task)
-	}
-	
-	return tasks, nil
+	return scanTasks(rows)
 }
 
 func (r *SQLiteRepository) GetTasksByUser(userID int) ([]DatabaseTask, error) {
 	query := `
-	SELECT t.id, t.title, t.description, t.priority, t.status, t.created_at, t.updated_at, t.due_date, t.user_id, t.category_id, t.is_archived
+	SELECT ` + taskColumns + `
 	FROM tasks t
-	WHERE t.user_id = ? AND t.is_archived = FALSE
+	WHERE t.user_id = ? AND t.is_archived = FALSE AND t.deleted_at IS NULL
 	ORDER BY t.created_at DESC`
 	
-	rows, err := r.db.Query(query, userID)
+	rows, err := r.conn().QueryContext(r.ctx, query, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tasks by user: %w", err)
 	}
 	defer rows.Close()
 	
-	var tasks []DatabaseTask
-	for rows.Next() {
-		task := DatabaseTask{}
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Priority,
-			&task.Status,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-			&task.DueDate,
-			&task.UserID,
-			&task.CategoryID,
-			&task.IsArchived,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan task: %w", err)
-		}
-		tasks = append(tasks, task)
+	return scanTasks(rows)
+}
+
+// GetTasksByProject returns the tasks of a project that are not in the trash,
+// archived ones included
+func (r *SQLiteRepository) GetTasksByProject(projectID int) ([]DatabaseTask, error) {
+	query := `
+	SELECT ` + taskColumns + `
+	FROM tasks t
+	WHERE t.project_id = ? AND t.deleted_at IS NULL
+	ORDER BY t.created_at DESC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks by project: %w", err)
 	}
+	defer rows.Close()
 	
-	return tasks, nil
+	return scanTasks(rows)
+}
+
+func (r *SQLiteRepository) GetTasksByAssignee(userID int, role string) ([]DatabaseTask, error) {
+	query := `
+	SELECT ` + taskColumns + `
+	FROM tasks t
+	INNER JOIN task_assignees ta ON ta.task_id = t.id
+	WHERE ta.user_id = ? AND (? = '' OR ta.role = ?)
+	AND t.is_archived = FALSE AND t.deleted_at IS NULL
+	ORDER BY t.created_at DESC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, userID, role, role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks by assignee: %w", err)
+	}
+	defer rows.Close()
+	
+	return scanTasks(rows)
 }
 
 func (r *SQLiteRepository) SearchTasks(query string) ([]DatabaseTask, error) {
 	searchQuery := "%" + query + "%"
 	sqlQuery := `
-	SELECT t.id, t.title, t.description, t.priority, t.status, t.created_at, t.updated_at, t.due_date, t.user_id, t.category_id, t.is_archived
+	SELECT ` + taskColumns + `
 	FROM tasks t
-	WHERE t.is_archived = FALSE 
+	WHERE t.is_archived = FALSE AND t.deleted_at IS NULL 
 	AND (t.title LIKE ? OR t.description LIKE ?)
 	ORDER BY 
 		CASE 
@@ -441,44 +650,22 @@ func (r *SQLiteRepository) SearchTasks(query string) ([]DatabaseTask, error) {
 		END,
 		t.created_at DESC`
 	
-	rows, err := r.db.Query(sqlQuery, searchQuery, searchQuery, searchQuery, searchQuery)
+	rows, err := r.conn().QueryContext(r.ctx, sqlQuery, searchQuery, searchQuery, searchQuery, searchQuery)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search tasks: %w", err)
 	}
 	defer rows.Close()
 	
-	var tasks []DatabaseTask
-	for rows.Next() {
-		task := DatabaseTask{}
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Priority,
-			&task.Status,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-			&task.DueDate,
-			&task.UserID,
-			&task.CategoryID,
-			&task.IsArchived,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan task: %w", err)
-		}
-		tasks = append(tasks, task)
-	}
-	
-	return tasks, nil
+	return scanTasks(rows)
 }
 
 // SearchTasksByUser searches tasks for a specific user
 func (r *SQLiteRepository) SearchTasksByUser(userID int, query string) ([]DatabaseTask, error) {
 	searchQuery := "%" + query + "%"
 	sqlQuery := `
-	SELECT t.id, t.title, t.description, t.priority, t.status, t.created_at, t.updated_at, t.due_date, t.user_id, t.category_id, t.is_archived
+	SELECT ` + taskColumns + `
 	FROM tasks t
-	WHERE t.user_id = ? AND t.is_archived = FALSE 
+	WHERE t.user_id = ? AND t.is_archived = FALSE AND t.deleted_at IS NULL 
 	AND (t.title LIKE ? OR t.description LIKE ?)
 	ORDER BY 
 		CASE 
@@ -488,127 +675,62 @@ func (r *SQLiteRepository) SearchTasksByUser(userID int, query string) ([]Databa
 		END,
 		t.created_at DESC`
 	
-	rows, err := r.db.Query(sqlQuery, userID, searchQuery, searchQuery, searchQuery, searchQuery)
+	rows, err := r.conn().QueryContext(r.ctx, sqlQuery, userID, searchQuery, searchQuery, searchQuery, searchQuery)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search user tasks: %w", err)
 	}
 	defer rows.Close()
 	
-	var tasks []DatabaseTask
-	for rows.Next() {
-		task := DatabaseTask{}
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Priority,
-			&task.Status,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-			&task.DueDate,
-			&task.UserID,
-			&task.CategoryID,
-			&task.IsArchived,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan task: %w", err)
-		}
-		tasks = append(tasks, task)
-	}
-	
-	return tasks, nil
+	return scanTasks(rows)
 }
 
 // SearchTasksByTag searches tasks by tag name
 func (r *SQLiteRepository) SearchTasksByTag(tagName string) ([]DatabaseTask, error) {
 	searchQuery := "%" + tagName + "%"
 	sqlQuery := `
-	SELECT DISTINCT t.id, t.title, t.description, t.priority, t.status, t.created_at, t.updated_at, t.due_date, t.user_id, t.category_id, t.is_archived
+	SELECT DISTINCT ` + taskColumns + `
 	FROM tasks t
 	INNER JOIN task_tags tt ON t.id = tt.task_id
 	INNER JOIN tags tg ON tt.tag_id = tg.id
-	WHERE t.is_archived = FALSE AND tg.name LIKE ?
+	WHERE t.is_archived = FALSE AND t.deleted_at IS NULL AND tg.name LIKE ?
 	ORDER BY t.created_at DESC`
 	
-	rows, err := r.db.Query(sqlQuery, searchQuery)
+	rows, err := r.conn().QueryContext(r.ctx, sqlQuery, searchQuery)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search tasks by tag: %w", err)
 	}
 	defer rows.Close()
 	
-	var tasks []DatabaseTask
-	for rows.Next() {
-		task := DatabaseTask{}
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Priority,
-			&task.Status,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-			&task.DueDate,
-			&task.UserID,
-			&task.CategoryID,
-			&task.IsArchived,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan task: %w", err)
-		}
-		tasks = append(tasks, task)
-	}
-	
-	return tasks, nil
+	return scanTasks(rows)
 }
 
 // SearchTasksByCategory searches tasks by category name
 func (r *SQLiteRepository) SearchTasksByCategory(categoryName string) ([]DatabaseTask, error) {
 	searchQuery := "%" + categoryName + "%"
 	sqlQuery := `
-	SELECT t.id, t.title, t.description, t.priority, t.status, t.created_at, t.updated_at, t.due_date, t.user_id, t.category_id, t.is_archived
+	SELECT ` + taskColumns + `
 	FROM tasks t
 	INNER JOIN categories c ON t.category_id = c.id
-	WHERE t.is_archived = FALSE AND c.name LIKE ?
+	WHERE t.is_archived = FALSE AND t.deleted_at IS NULL AND c.name LIKE ?
 	ORDER BY t.created_at DESC`
 	
-	rows, err := r.db.Query(sqlQuery, searchQuery)
+	rows, err := r.conn().QueryContext(r.ctx, sqlQuery, searchQuery)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search tasks by category: %w", err)
 	}
 	defer rows.Close()
 	
-	var tasks []DatabaseTask
-	for rows.Next() {
-		task := DatabaseTask{}
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Priority,
-			&task.Status,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-			&task.DueDate,
-			&task.UserID,
-			&task.CategoryID,
-			&task.IsArchived,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan task: %w", err)
-		}
-		tasks = append(tasks, task)
-	}
-	
-	return tasks, nil
+	return scanTasks(rows)
 }
 
 // Category operations (Phase 2)
 func (r *SQLiteRepository) CreateCategory(category *Category) error {
 	query := `
-	INSERT INTO categories (name, description, color)
-	VALUES (?, ?, ?)`
+	INSERT INTO categories (project_id, name, description, color)
+	VALUES (?, ?, ?, ?)`
 	
-	result, err := r.db.Exec(query, 
+	result, err := r.conn().ExecContext(r.ctx, query, 
+		category.ProjectID,
 		category.Name, 
 		category.Description, 
 		category.Color)
@@ -631,12 +753,13 @@ func (r *SQLiteRepository) CreateCategory(category *Category) error {
 
 func (r *SQLiteRepository) GetCategory(id int) (*Category, error) {
 	query := `
-	SELECT id, name, description, color, created_at, updated_at
+	SELECT id, project_id, name, description, color, created_at, updated_at
 	FROM categories WHERE id = ?`
 	
 	category := &Category{}
-	err := r.db.QueryRow(query, id).Scan(
+	err := r.conn().QueryRowContext(r.ctx, query, id).Scan(
 		&category.ID,
+		&category.ProjectID,
 		&category.Name,
 		&category.Description,
 		&category.Color,
@@ -656,21 +779,45 @@ func (r *SQLiteRepository) GetCategory(id int) (*Category, error) {
 
 func (r *SQLiteRepository) GetAllCategories() ([]Category, error) {
 	query := `
-	SELECT id, name, description, color, created_at, updated_at
+	SELECT id, project_id, name, description, color, created_at, updated_at
+	FROM categories 
+	ORDER BY name ASC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+	defer rows.Close()
+	
+	return scanCategories(rows)
+}
+
+// GetCategoriesByProject returns the categories of a project, or the global
+// categories when projectID is nil
+func (r *SQLiteRepository) GetCategoriesByProject(projectID *int) ([]Category, error) {
+	query := `
+	SELECT id, project_id, name, description, color, created_at, updated_at
 	FROM categories 
+	WHERE COALESCE(project_id, 0) = ?
 	ORDER BY name ASC`
 	
-	rows, err := r.db.Query(query)
+	rows, err := r.conn().QueryContext(r.ctx, query, scopeID(projectID))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get categories: %w", err)
 	}
 	defer rows.Close()
 	
+	return scanCategories(rows)
+}
+
+// scanCategories scans all rows of a category query
+func scanCategories(rows *sql.Rows) ([]Category, error) {
 	var categories []Category
 	for rows.Next() {
 		category := Category{}
 		err := rows.Scan(
 			&category.ID,
+			&category.ProjectID,
 			&category.Name,
 			&category.Description,
 			&category.Color,
@@ -683,9 +830,22 @@ func (r *SQLiteRepository) GetAllCategories() ([]Category, error) {
 		categories = append(categories, category)
 	}
 	
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate categories: %w", err)
+	}
+	
 	return categories, nil
 }
 
+// scopeID maps a project to the value the unique name indexes use for it,
+// with 0 standing for the global scope
+func scopeID(projectID *int) int {
+	if projectID == nil {
+		return 0
+	}
+	return *projectID
+}
+
 func (r *SQLiteRepository) UpdateCategory(category *Category) error {
 	query := `
 	UPDATE categories 
@@ -694,7 +854,7 @@ func (r *SQLiteRepository) UpdateCategory(category *Category) error {
 	
 	category.UpdatedAt = time.Now()
 	
-	result, err := r.db.Exec(query,
+	result, err := r.conn().ExecContext(r.ctx, query,
 		category.Name,
 		category.Description,
 		category.Color,
@@ -718,10 +878,26 @@ func (r *SQLiteRepository) UpdateCategory(category *Category) error {
 	return nil
 }
 
+// DeleteCategory deletes a category together with its custom fields and their values
 func (r *SQLiteRepository) DeleteCategory(id int) error {
-	query := `DELETE FROM categories WHERE id = ?`
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin category deletion: %w", err)
+	}
+	defer tx.Rollback()
+	
+	// Foreign keys are not enforced, so related rows are removed explicitly
+	queries := []string{
+		`DELETE FROM task_custom_values WHERE field_id IN (SELECT id FROM custom_fields WHERE category_id = ?)`,
+		`DELETE FROM custom_fields WHERE category_id = ?`,
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(r.ctx, query, id); err != nil {
+			return fmt.Errorf("failed to delete category: %w", err)
+		}
+	}
 	
-	result, err := r.db.Exec(query, id)
+	result, err := tx.ExecContext(r.ctx, `DELETE FROM categories WHERE id = ?`, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete category: %w", err)
 	}
@@ -735,16 +911,21 @@ func (r *SQLiteRepository) DeleteCategory(id int) error {
 		return fmt.Errorf("category with ID %d not found", id)
 	}
 	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit category deletion: %w", err)
+	}
+	
 	return nil
 }
 
 // Tag operations (Phase 2)
 func (r *SQLiteRepository) CreateTag(tag *Tag) error {
 	query := `
-	INSERT INTO tags (name, color)
-	VALUES (?, ?)`
+	INSERT INTO tags (project_id, name, color)
+	VALUES (?, ?, ?)`
 	
-	result, err := r.db.Exec(query, 
+	result, err := r.conn().ExecContext(r.ctx, query, 
+		tag.ProjectID,
 		tag.Name, 
 		tag.Color)
 	
@@ -765,12 +946,13 @@ func (r *SQLiteRepository) CreateTag(tag *Tag) error {
 
 func (r *SQLiteRepository) GetTag(id int) (*Tag, error) {
 	query := `
-	SELECT id, name, color, created_at
+	SELECT id, project_id, name, color, created_at
 	FROM tags WHERE id = ?`
 	
 	tag := &Tag{}
-	err := r.db.QueryRow(query, id).Scan(
+	err := r.conn().QueryRowContext(r.ctx, query, id).Scan(
 		&tag.ID,
+		&tag.ProjectID,
 		&tag.Name,
 		&tag.Color,
 		&tag.CreatedAt,
@@ -788,31 +970,59 @@ func (r *SQLiteRepository) GetTag(id int) (*Tag, error) {
 
 func (r *SQLiteRepository) GetAllTags() ([]Tag, error) {
 	query := `
-	SELECT id, name, color, created_at
+	SELECT id, project_id, name, color, created_at
 	FROM tags 
 	ORDER BY name ASC`
 	
-	rows, err := r.db.Query(query)
+	rows, err := r.conn().QueryContext(r.ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tags: %w", err)
 	}
 	defer rows.Close()
 	
-	var tags []Tag
-	for rows.Next() {
-		tag := Tag{}
-		err := rows.Scan(
-			&tag.ID,
-			&tag.Name,
-			&tag.Color,
-			&tag.CreatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan tag: %w", err)
-		}
+	return scanTags(rows)
+}
+
+// GetTagsByProject returns the tags of a project, or the global tags when
+// projectID is nil
+func (r *SQLiteRepository) GetTagsByProject(projectID *int) ([]Tag, error) {
+	query := `
+	SELECT id, project_id, name, color, created_at
+	FROM tags 
+	WHERE COALESCE(project_id, 0) = ?
+	ORDER BY name ASC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, scopeID(projectID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	defer rows.Close()
+	
+	return scanTags(rows)
+}
+
+// scanTags scans all rows of a tag query
+func scanTags(rows *sql.Rows) ([]Tag, error) {
+	var tags []Tag
+	for rows.Next() {
+		tag := Tag{}
+		err := rows.Scan(
+			&tag.ID,
+			&tag.ProjectID,
+			&tag.Name,
+			&tag.Color,
+			&tag.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
 		tags = append(tags, tag)
 	}
 	
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tags: %w", err)
+	}
+	
 	return tags, nil
 }
 
@@ -822,7 +1032,7 @@ func (r *SQLiteRepository) UpdateTag(tag *Tag) error {
 	SET name = ?, color = ?
 	WHERE id = ?`
 	
-	result, err := r.db.Exec(query,
+	result, err := r.conn().ExecContext(r.ctx, query,
 		tag.Name,
 		tag.Color,
 		tag.ID,
@@ -847,7 +1057,7 @@ func (r *SQLiteRepository) UpdateTag(tag *Tag) error {
 func (r *SQLiteRepository) DeleteTag(id int) error {
 	query := `DELETE FROM tags WHERE id = ?`
 	
-	result, err := r.db.Exec(query, id)
+	result, err := r.conn().ExecContext(r.ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete tag: %w", err)
 	}
@@ -870,18 +1080,38 @@ func (r *SQLiteRepository) AddTagToTask(taskID, tagID int) error {
 	INSERT INTO task_tags (task_id, tag_id)
 	VALUES (?, ?)`
 	
-	_, err := r.db.Exec(query, taskID, tagID)
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin adding tag: %w", err)
+	}
+	defer tx.Rollback()
+	
+	_, err = tx.ExecContext(r.ctx, query, taskID, tagID)
 	if err != nil {
 		return fmt.Errorf("failed to add tag to task: %w", err)
 	}
 	
+	if err := r.recordHistory(tx, taskID, historyChange{field: "tag_id", newValue: intValue(&tagID)}); err != nil {
+		return err
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit adding tag: %w", err)
+	}
+	
 	return nil
 }
 
 func (r *SQLiteRepository) RemoveTagFromTask(taskID, tagID int) error {
 	query := `DELETE FROM task_tags WHERE task_id = ? AND tag_id = ?`
 	
-	result, err := r.db.Exec(query, taskID, tagID)
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin removing tag: %w", err)
+	}
+	defer tx.Rollback()
+	
+	result, err := tx.ExecContext(r.ctx, query, taskID, tagID)
 	if err != nil {
 		return fmt.Errorf("failed to remove tag from task: %w", err)
 	}
@@ -895,78 +1125,49 @@ func (r *SQLiteRepository) RemoveTagFromTask(taskID, tagID int) error {
 		return fmt.Errorf("tag-task relationship not found")
 	}
 	
+	if err := r.recordHistory(tx, taskID, historyChange{field: "tag_id", oldValue: intValue(&tagID)}); err != nil {
+		return err
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit removing tag: %w", err)
+	}
+	
 	return nil
 }
 
 func (r *SQLiteRepository) GetTaskTags(taskID int) ([]Tag, error) {
 	query := `
-	SELECT t.id, t.name, t.color, t.created_at
+	SELECT t.id, t.project_id, t.name, t.color, t.created_at
 	FROM tags t
 	INNER JOIN task_tags tt ON t.id = tt.tag_id
 	WHERE tt.task_id = ?
 	ORDER BY t.name ASC`
 	
-	rows, err := r.db.Query(query, taskID)
+	rows, err := r.conn().QueryContext(r.ctx, query, taskID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get task tags: %w", err)
 	}
 	defer rows.Close()
 	
-	var tags []Tag
-	for rows.Next() {
-		tag := Tag{}
-		err := rows.Scan(
-			&tag.ID,
-			&tag.Name,
-			&tag.Color,
-			&tag.CreatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan tag: %w", err)
-		}
-		tags = append(tags, tag)
-	}
-	
-	return tags, nil
+	return scanTags(rows)
 }
 
 func (r *SQLiteRepository) GetTasksByTag(tagID int) ([]DatabaseTask, error) {
 	query := `
-	SELECT t.id, t.title, t.description, t.priority, t.status, t.created_at, t.updated_at, t.due_date, t.user_id, t.category_id, t.is_archived
+	SELECT ` + taskColumns + `
 	FROM tasks t
 	INNER JOIN task_tags tt ON t.id = tt.task_id
-	WHERE tt.tag_id = ? AND t.is_archived = FALSE
+	WHERE tt.tag_id = ? AND t.is_archived = FALSE AND t.deleted_at IS NULL
 	ORDER BY t.created_at DESC`
 	
-	rows, err := r.db.Query(query, tagID)
+	rows, err := r.conn().QueryContext(r.ctx, query, tagID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tasks by tag: %w", err)
 	}
 	defer rows.Close()
 	
-	var tasks []DatabaseTask
-	for rows.Next() {
-		task := DatabaseTask{}
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Priority,
-			&task.Status,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-			&task.DueDate,
-			&task.UserID,
-			&task.CategoryID,
-			&task.IsArchived,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan task: %w", err)
-		}
-		tasks = append(tasks, task)
-	}
-	
-	return tasks, nil
+	return scanTasks(rows)
 }
 
 // Task dependency operations (Phase 2)
@@ -986,18 +1187,38 @@ func (r *SQLiteRepository) AddTaskDependency(taskID, dependsOnTaskID int) error
 	INSERT INTO task_dependencies (task_id, depends_on_task_id)
 	VALUES (?, ?)`
 	
-	_, err = r.db.Exec(query, taskID, dependsOnTaskID)
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin adding task dependency: %w", err)
+	}
+	defer tx.Rollback()
+	
+	_, err = tx.ExecContext(r.ctx, query, taskID, dependsOnTaskID)
 	if err != nil {
 		return fmt.Errorf("failed to add task dependency: %w", err)
 	}
 	
+	if err := r.recordHistory(tx, taskID, historyChange{field: "depends_on_task_id", newValue: intValue(&dependsOnTaskID)}); err != nil {
+		return err
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit task dependency: %w", err)
+	}
+	
 	return nil
 }
 
 func (r *SQLiteRepository) RemoveTaskDependency(taskID, dependsOnTaskID int) error {
 	query := `DELETE FROM task_dependencies WHERE task_id = ? AND depends_on_task_id = ?`
 	
-	result, err := r.db.Exec(query, taskID, dependsOnTaskID)
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin removing task dependency: %w", err)
+	}
+	defer tx.Rollback()
+	
+	result, err := tx.ExecContext(r.ctx, query, taskID, dependsOnTaskID)
 	if err != nil {
 		return fmt.Errorf("failed to remove task dependency: %w", err)
 	}
@@ -1011,17 +1232,26 @@ func (r *SQLiteRepository) RemoveTaskDependency(taskID, dependsOnTaskID int) err
 		return fmt.Errorf("task dependency not found")
 	}
 	
+	if err := r.recordHistory(tx, taskID, historyChange{field: "depends_on_task_id", oldValue: intValue(&dependsOnTaskID)}); err != nil {
+		return err
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit removing task dependency: %w", err)
+	}
+	
 	return nil
 }
 
 func (r *SQLiteRepository) GetTaskDependencies(taskID int) ([]TaskDependency, error) {
 	query := `
-	SELECT id, task_id, depends_on_task_id, created_at
-	FROM task_dependencies
-	WHERE task_id = ?
-	ORDER BY created_at ASC`
+	SELECT td.id, td.task_id, td.depends_on_task_id, td.created_at
+	FROM task_dependencies td
+	INNER JOIN tasks t ON t.id = td.depends_on_task_id
+	WHERE td.task_id = ? AND t.deleted_at IS NULL
+	ORDER BY td.created_at ASC`
 	
-	rows, err := r.db.Query(query, taskID)
+	rows, err := r.conn().QueryContext(r.ctx, query, taskID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get task dependencies: %w", err)
 	}
@@ -1047,80 +1277,36 @@ func (r *SQLiteRepository) GetTaskDependencies(taskID int) ([]TaskDependency, er
 
 func (r *SQLiteRepository) GetTasksThatDependOn(taskID int) ([]DatabaseTask, error) {
 	query := `
-	SELECT t.id, t.title, t.description, t.priority, t.status, t.created_at, t.updated_at, t.due_date, t.user_id, t.category_id, t.is_archived
+	SELECT ` + taskColumns + `
 	FROM tasks t
 	INNER JOIN task_dependencies td ON t.id = td.task_id
-	WHERE td.depends_on_task_id = ? AND t.is_archived = FALSE
+	WHERE td.depends_on_task_id = ? AND t.is_archived = FALSE AND t.deleted_at IS NULL
 	ORDER BY t.created_at DESC`
 	
-	rows, err := r.db.Query(query, taskID)
+	rows, err := r.conn().QueryContext(r.ctx, query, taskID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tasks that depend on task: %w", err)
 	}
 	defer rows.Close()
 	
-	var tasks []DatabaseTask
-	for rows.Next() {
-		task := DatabaseTask{}
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Priority,
-			&task.Status,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-			&task.DueDate,
-			&task.UserID,
-			&task.CategoryID,
-			&task.IsArchived,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan task: %w", err)
-		}
-		tasks = append(tasks, task)
-	}
-	
-	return tasks, nil
+	return scanTasks(rows)
 }
 
 func (r *SQLiteRepository) GetTasksThatTaskDependsOn(taskID int) ([]DatabaseTask, error) {
 	query := `
-	SELECT t.id, t.title, t.description, t.priority, t.status, t.created_at, t.updated_at, t.due_date, t.user_id, t.category_id, t.is_archived
+	SELECT ` + taskColumns + `
 	FROM tasks t
 	INNER JOIN task_dependencies td ON t.id = td.depends_on_task_id
-	WHERE td.task_id = ? AND t.is_archived = FALSE
+	WHERE td.task_id = ? AND t.is_archived = FALSE AND t.deleted_at IS NULL
 	ORDER BY t.created_at DESC`
 	
-	rows, err := r.db.Query(query, taskID)
+	rows, err := r.conn().QueryContext(r.ctx, query, taskID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tasks that task depends on: %w", err)
 	}
 	defer rows.Close()
 	
-	var tasks []DatabaseTask
-	for rows.Next() {
-		task := DatabaseTask{}
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Priority,
-			&task.Status,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-			&task.DueDate,
-			&task.UserID,
-			&task.CategoryID,
-			&task.IsArchived,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan task: %w", err)
-		}
-		tasks = append(tasks, task)
-	}
-	
-	return tasks, nil
+	return scanTasks(rows)
 }
 
 func (r *SQLiteRepository) CheckCircularDependency(taskID, dependsOnTaskID int) (bool, error) {
@@ -1146,7 +1332,7 @@ func (r *SQLiteRepository) CheckCircularDependency(taskID, dependsOnTaskID int)
 	SELECT COUNT(*) FROM dependency_chain WHERE depends_on_task_id = ?`
 	
 	var count int
-	err := r.db.QueryRow(query, dependsOnTaskID, taskID).Scan(&count)
+	err := r.conn().QueryRowContext(r.ctx, query, dependsOnTaskID, taskID).Scan(&count)
 	if err != nil {
 		return false, fmt.Errorf("failed to check circular dependency: %w", err)
 	}
@@ -1154,6 +1340,3102 @@ func (r *SQLiteRepository) CheckCircularDependency(taskID, dependsOnTaskID int)
 	return count > 0, nil
 }
 
+// Task link operations
+
+// AddTaskLink stores a typed link between two tasks. Blocking links are task
+// dependencies and are added with AddTaskDependency instead.
+func (r *SQLiteRepository) AddTaskLink(link *TaskLink) error {
+	query := `
+	INSERT INTO task_links (task_id, linked_task_id, link_type, created_at)
+	VALUES (?, ?, ?, ?)`
+	
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin adding task link: %w", err)
+	}
+	defer tx.Rollback()
+	
+	link.CreatedAt = time.Now()
+	_, err = tx.ExecContext(r.ctx, query, link.TaskID, link.LinkedTaskID, link.Type, link.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add task link: %w", err)
+	}
+	
+	if err := r.recordHistory(tx, link.TaskID, historyChange{field: link.Type, newValue: intValue(&link.LinkedTaskID)}); err != nil {
+		return err
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit task link: %w", err)
+	}
+	
+	return nil
+}
+
+func (r *SQLiteRepository) RemoveTaskLink(taskID, linkedTaskID int, linkType string) error {
+	query := `DELETE FROM task_links WHERE task_id = ? AND linked_task_id = ? AND link_type = ?`
+	
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin removing task link: %w", err)
+	}
+	defer tx.Rollback()
+	
+	result, err := tx.ExecContext(r.ctx, query, taskID, linkedTaskID, linkType)
+	if err != nil {
+		return fmt.Errorf("failed to remove task link: %w", err)
+	}
+	
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	
+	if rowsAffected == 0 {
+		return fmt.Errorf("task link not found")
+	}
+	
+	if err := r.recordHistory(tx, taskID, historyChange{field: linkType, oldValue: intValue(&linkedTaskID)}); err != nil {
+		return err
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit removing task link: %w", err)
+	}
+	
+	return nil
+}
+
+// GetTaskLinks returns the links from and to a task whose other task is not
+// in the trash, oldest first. Dependencies are included as BlocksLinkType
+// links from the task depended on to the task that depends on it.
+func (r *SQLiteRepository) GetTaskLinks(taskID int) ([]TaskLink, error) {
+	query := `
+	SELECT l.task_id, l.linked_task_id, l.link_type, l.created_at
+	FROM (
+		SELECT depends_on_task_id AS task_id, task_id AS linked_task_id, '` + BlocksLinkType + `' AS link_type, created_at
+		FROM task_dependencies
+		UNION ALL
+		SELECT task_id, linked_task_id, link_type, created_at
+		FROM task_links
+	) l
+	INNER JOIN tasks t ON t.id = CASE WHEN l.task_id = :task THEN l.linked_task_id ELSE l.task_id END
+	WHERE (l.task_id = :task OR l.linked_task_id = :task) AND t.deleted_at IS NULL
+	ORDER BY l.created_at ASC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, sql.Named("task", taskID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task links: %w", err)
+	}
+	defer rows.Close()
+	
+	var links []TaskLink
+	for rows.Next() {
+		link := TaskLink{}
+		if err := rows.Scan(&link.TaskID, &link.LinkedTaskID, &link.Type, &link.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan task link: %w", err)
+		}
+		links = append(links, link)
+	}
+	
+	return links, nil
+}
+
+// Task hierarchy operations
+
+func (r *SQLiteRepository) GetChildTasks(parentID int) ([]DatabaseTask, error) {
+	query := `
+	SELECT ` + taskColumns + `
+	FROM tasks t
+	WHERE t.parent_id = ? AND t.is_archived = FALSE AND t.deleted_at IS NULL
+	ORDER BY t.created_at ASC, t.id ASC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get child tasks: %w", err)
+	}
+	defer rows.Close()
+	
+	return scanTasks(rows)
+}
+
+// GetAncestorTasks returns the ancestors of a task, nearest parent first
+func (r *SQLiteRepository) GetAncestorTasks(taskID int) ([]DatabaseTask, error) {
+	// UNION (rather than UNION ALL) stops the recursion if the data ever contains a cycle
+	query := `
+	WITH RECURSIVE ancestors(id) AS (
+		SELECT parent_id FROM tasks WHERE id = ? AND parent_id IS NOT NULL
+		
+		UNION
+		
+		SELECT p.parent_id
+		FROM tasks p
+		INNER JOIN ancestors a ON p.id = a.id
+		WHERE p.parent_id IS NOT NULL
+	)
+	SELECT ` + taskColumns + `
+	FROM tasks t
+	WHERE t.id IN (SELECT id FROM ancestors) AND t.deleted_at IS NULL`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ancestor tasks: %w", err)
+	}
+	defer rows.Close()
+	
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, err
+	}
+	
+	byID := make(map[int]DatabaseTask, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+	
+	// Order the chain by walking up from the task itself
+	child, err := r.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	
+	ancestors := make([]DatabaseTask, 0, len(tasks))
+	for parentID := child.ParentID; parentID != nil; {
+		parent, ok := byID[*parentID]
+		if !ok {
+			break
+		}
+		delete(byID, *parentID)
+		ancestors = append(ancestors, parent)
+		parentID = parent.ParentID
+	}
+	
+	return ancestors, nil
+}
+
+// GetDescendantTasks returns every task below the given task at any depth,
+// including archived but not trashed ones, so that cascades reach the whole subtree
+func (r *SQLiteRepository) GetDescendantTasks(taskID int) ([]DatabaseTask, error) {
+	query := `
+	WITH RECURSIVE descendants(id) AS (
+		SELECT id FROM tasks WHERE parent_id = ?
+		
+		UNION
+		
+		SELECT c.id
+		FROM tasks c
+		INNER JOIN descendants d ON c.parent_id = d.id
+	)
+	SELECT ` + taskColumns + `
+	FROM tasks t
+	WHERE t.id IN (SELECT id FROM descendants) AND t.id != ? AND t.deleted_at IS NULL
+	ORDER BY t.created_at ASC, t.id ASC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, taskID, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get descendant tasks: %w", err)
+	}
+	defer rows.Close()
+	
+	return scanTasks(rows)
+}
+
+// Archive operations
+
+// GetArchivedTasks returns every archived task, most recently changed first
+func (r *SQLiteRepository) GetArchivedTasks() ([]DatabaseTask, error) {
+	query := `
+	SELECT ` + taskColumns + `
+	FROM tasks t
+	WHERE t.is_archived = TRUE AND t.deleted_at IS NULL
+	ORDER BY t.updated_at DESC, t.id DESC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived tasks: %w", err)
+	}
+	defer rows.Close()
+	
+	return scanTasks(rows)
+}
+
+// GetArchivedTasksByUser returns the archived tasks of a user, most recently changed first
+func (r *SQLiteRepository) GetArchivedTasksByUser(userID int) ([]DatabaseTask, error) {
+	query := `
+	SELECT ` + taskColumns + `
+	FROM tasks t
+	WHERE t.user_id = ? AND t.is_archived = TRUE AND t.deleted_at IS NULL
+	ORDER BY t.updated_at DESC, t.id DESC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived tasks by user: %w", err)
+	}
+	defer rows.Close()
+	
+	return scanTasks(rows)
+}
+
+// GetCompletedTasksBefore returns unarchived completed tasks that were last
+// changed before cutoff. A task is never changed after it is completed without
+// bumping updated_at, so these were all completed before cutoff as well.
+func (r *SQLiteRepository) GetCompletedTasksBefore(cutoff time.Time) ([]DatabaseTask, error) {
+	query := `
+	SELECT ` + taskColumns + `
+	FROM tasks t
+	WHERE t.status = 2 AND t.is_archived = FALSE AND t.deleted_at IS NULL AND t.updated_at < ?
+	ORDER BY t.updated_at ASC, t.id ASC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get completed tasks: %w", err)
+	}
+	defer rows.Close()
+	
+	return scanTasks(rows)
+}
+
+// GetWokenTasks returns tasks that were snoozed until cutoff or earlier and
+// have not been woken up yet, in the order they wake up
+func (r *SQLiteRepository) GetWokenTasks(cutoff time.Time) ([]DatabaseTask, error) {
+	query := `
+	SELECT ` + taskColumns + `
+	FROM tasks t
+	WHERE t.hidden_until IS NOT NULL AND t.hidden_until <= ? AND t.deleted_at IS NULL
+	ORDER BY t.hidden_until ASC, t.id ASC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, cutoff.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get woken tasks: %w", err)
+	}
+	defer rows.Close()
+	
+	return scanTasks(rows)
+}
+
+// Trash operations
+
+// GetDeletedTask returns a task from the trash
+func (r *SQLiteRepository) GetDeletedTask(id int) (*DatabaseTask, error) {
+	query := `
+	SELECT ` + taskColumns + `
+	FROM tasks t WHERE t.id = ? AND t.deleted_at IS NOT NULL`
+	
+	task, err := scanTask(r.conn().QueryRowContext(r.ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("task with ID %d not found in trash", id)
+		}
+		return nil, fmt.Errorf("failed to get deleted task: %w", err)
+	}
+	
+	return task, nil
+}
+
+// GetDeletedTasks returns every task in the trash, most recently deleted first
+func (r *SQLiteRepository) GetDeletedTasks() ([]DatabaseTask, error) {
+	query := `
+	SELECT ` + taskColumns + `
+	FROM tasks t
+	WHERE t.deleted_at IS NOT NULL
+	ORDER BY t.deleted_at DESC, t.id DESC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted tasks: %w", err)
+	}
+	defer rows.Close()
+	
+	return scanTasks(rows)
+}
+
+// GetDeletedTasksByUser returns the trashed tasks of a user, most recently deleted first
+func (r *SQLiteRepository) GetDeletedTasksByUser(userID int) ([]DatabaseTask, error) {
+	query := `
+	SELECT ` + taskColumns + `
+	FROM tasks t
+	WHERE t.user_id = ? AND t.deleted_at IS NOT NULL
+	ORDER BY t.deleted_at DESC, t.id DESC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted tasks by user: %w", err)
+	}
+	defer rows.Close()
+	
+	return scanTasks(rows)
+}
+
+// RestoreTask takes a task out of the trash
+func (r *SQLiteRepository) RestoreTask(id int) error {
+	query := `UPDATE tasks SET deleted_at = NULL, updated_at = ? WHERE id = ? AND deleted_at IS NOT NULL`
+	
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin task restore: %w", err)
+	}
+	defer tx.Rollback()
+	
+	var deletedAt time.Time
+	err = tx.QueryRowContext(r.ctx, `SELECT deleted_at FROM tasks WHERE id = ? AND deleted_at IS NOT NULL`, id).Scan(&deletedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("task with ID %d not found in trash", id)
+		}
+		return fmt.Errorf("failed to restore task: %w", err)
+	}
+	
+	if _, err := tx.ExecContext(r.ctx, query, time.Now(), id); err != nil {
+		return fmt.Errorf("failed to restore task: %w", err)
+	}
+	
+	if err := r.recordHistory(tx, id, historyChange{field: "deleted_at", oldValue: timeValue(&deletedAt)}); err != nil {
+		return err
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit task restore: %w", err)
+	}
+	
+	return nil
+}
+
+// PurgeDeletedTasksBefore permanently removes the tasks that were moved to the
+// trash before cutoff, together with every row that refers to them, and
+// returns how many tasks were removed
+func (r *SQLiteRepository) PurgeDeletedTasksBefore(cutoff time.Time) (int, error) {
+	tx, err := r.begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin purge: %w", err)
+	}
+	defer tx.Rollback()
+	
+	// Foreign keys are not enforced, so related rows are removed explicitly
+	purged := `SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < :cutoff`
+	queries := []string{
+		`DELETE FROM task_tags WHERE task_id IN (` + purged + `)`,
+		`DELETE FROM task_history WHERE task_id IN (` + purged + `)`,
+		`DELETE FROM time_entries WHERE task_id IN (` + purged + `)`,
+		`DELETE FROM task_comments WHERE task_id IN (` + purged + `)`,
+		`DELETE FROM task_attachments WHERE task_id IN (` + purged + `)`,
+		`DELETE FROM task_assignees WHERE task_id IN (` + purged + `)`,
+		`DELETE FROM task_custom_values WHERE task_id IN (` + purged + `)`,
+		`DELETE FROM task_checklist_items WHERE task_id IN (` + purged + `)`,
+		`DELETE FROM board_cards WHERE task_id IN (` + purged + `)`,
+		`DELETE FROM task_dependencies WHERE task_id IN (` + purged + `) OR depends_on_task_id IN (` + purged + `)`,
+		`DELETE FROM task_links WHERE task_id IN (` + purged + `) OR linked_task_id IN (` + purged + `)`,
+		`DELETE FROM task_ranks WHERE task_id IN (` + purged + `)`,
+		`UPDATE tasks SET parent_id = NULL WHERE parent_id IN (` + purged + `)`,
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(r.ctx, query, sql.Named("cutoff", cutoff)); err != nil {
+			return 0, fmt.Errorf("failed to purge deleted tasks: %w", err)
+		}
+	}
+	
+	result, err := tx.ExecContext(r.ctx, `DELETE FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted tasks: %w", err)
+	}
+	
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit purge: %w", err)
+	}
+	
+	return int(rowsAffected), nil
+}
+
+// Task history operations
+
+// GetTaskHistory returns the recorded changes of a task, oldest first
+func (r *SQLiteRepository) GetTaskHistory(taskID int) ([]TaskHistoryEntry, error) {
+	query := `
+	SELECT id, task_id, user_id, field, old_value, new_value, changed_at
+	FROM task_history
+	WHERE task_id = ?
+	ORDER BY changed_at ASC, id ASC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task history: %w", err)
+	}
+	defer rows.Close()
+	
+	var history []TaskHistoryEntry
+	for rows.Next() {
+		entry := TaskHistoryEntry{}
+		err := rows.Scan(
+			&entry.ID,
+			&entry.TaskID,
+			&entry.UserID,
+			&entry.Field,
+			&entry.OldValue,
+			&entry.NewValue,
+			&entry.ChangedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task history: %w", err)
+		}
+		history = append(history, entry)
+	}
+	
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate task history: %w", err)
+	}
+	
+	return history, nil
+}
+
+// Time tracking operations
+
+// timeEntryColumns is the column list selected by every time entry query,
+// in the order expected by scanTimeEntry
+const timeEntryColumns = `e.id, e.task_id, e.user_id, e.started_at, e.ended_at, e.duration_seconds, e.note, e.created_at`
+
+// scanTimeEntry scans a single row selected with timeEntryColumns
+func scanTimeEntry(row rowScanner) (*TimeEntry, error) {
+	entry := &TimeEntry{}
+	err := row.Scan(
+		&entry.ID,
+		&entry.TaskID,
+		&entry.UserID,
+		&entry.StartedAt,
+		&entry.EndedAt,
+		&entry.DurationSeconds,
+		&entry.Note,
+		&entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	
+	return entry, nil
+}
+
+// scanTimeEntries scans all rows selected with timeEntryColumns
+func scanTimeEntries(rows *sql.Rows) ([]TimeEntry, error) {
+	var entries []TimeEntry
+	for rows.Next() {
+		entry, err := scanTimeEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan time entry: %w", err)
+		}
+		entries = append(entries, *entry)
+	}
+	
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate time entries: %w", err)
+	}
+	
+	return entries, nil
+}
+
+// StartTimer starts a timer for entry.UserID on entry.TaskID. A user can only
+// run one timer at a time.
+func (r *SQLiteRepository) StartTimer(entry *TimeEntry) error {
+	query := `
+	INSERT INTO time_entries (task_id, user_id, started_at, note)
+	VALUES (?, ?, ?, ?)`
+	
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin timer start: %w", err)
+	}
+	defer tx.Rollback()
+	
+	var runningTaskID int
+	err = tx.QueryRowContext(r.ctx, `SELECT task_id FROM time_entries WHERE user_id IS ? AND ended_at IS NULL`, entry.UserID).Scan(&runningTaskID)
+	if err == nil {
+		return fmt.Errorf("a timer is already running on task %d", runningTaskID)
+	}
+	if err != sql.ErrNoRows {
+		return fmt.Errorf("failed to start timer: %w", err)
+	}
+	
+	entry.StartedAt = time.Now().UTC()
+	result, err := tx.ExecContext(r.ctx, query, entry.TaskID, entry.UserID, entry.StartedAt, entry.Note)
+	if err != nil {
+		return fmt.Errorf("failed to start timer: %w", err)
+	}
+	
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get time entry ID: %w", err)
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit timer start: %w", err)
+	}
+	
+	entry.ID = int(id)
+	entry.EndedAt = nil
+	entry.DurationSeconds = 0
+	entry.CreatedAt = entry.StartedAt
+	
+	return nil
+}
+
+// StopTimer stops the running timer of a user and returns the completed entry
+func (r *SQLiteRepository) StopTimer(userID *int) (*TimeEntry, error) {
+	tx, err := r.begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin timer stop: %w", err)
+	}
+	defer tx.Rollback()
+	
+	entry, err := scanTimeEntry(tx.QueryRowContext(r.ctx, `
+	SELECT `+timeEntryColumns+`
+	FROM time_entries e WHERE e.user_id IS ? AND e.ended_at IS NULL`, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("no timer is running")
+		}
+		return nil, fmt.Errorf("failed to stop timer: %w", err)
+	}
+	
+	endedAt := time.Now().UTC()
+	entry.EndedAt = &endedAt
+	entry.DurationSeconds = int(endedAt.Sub(entry.StartedAt).Seconds())
+	
+	_, err = tx.ExecContext(r.ctx, `UPDATE time_entries SET ended_at = ?, duration_seconds = ? WHERE id = ?`,
+		entry.EndedAt, entry.DurationSeconds, entry.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stop timer: %w", err)
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit timer stop: %w", err)
+	}
+	
+	return entry, nil
+}
+
+// GetRunningTimer returns the running timer of a user
+func (r *SQLiteRepository) GetRunningTimer(userID *int) (*TimeEntry, error) {
+	query := `
+	SELECT ` + timeEntryColumns + `
+	FROM time_entries e WHERE e.user_id IS ? AND e.ended_at IS NULL`
+	
+	entry, err := scanTimeEntry(r.conn().QueryRowContext(r.ctx, query, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("no timer is running")
+		}
+		return nil, fmt.Errorf("failed to get running timer: %w", err)
+	}
+	
+	return entry, nil
+}
+
+// CreateTimeEntry stores a completed entry that was logged by hand. EndedAt
+// is derived from StartedAt and DurationSeconds.
+func (r *SQLiteRepository) CreateTimeEntry(entry *TimeEntry) error {
+	query := `
+	INSERT INTO time_entries (task_id, user_id, started_at, ended_at, duration_seconds, note)
+	VALUES (?, ?, ?, ?, ?, ?)`
+	
+	entry.StartedAt = entry.StartedAt.UTC()
+	endedAt := entry.StartedAt.Add(time.Duration(entry.DurationSeconds) * time.Second)
+	entry.EndedAt = &endedAt
+	
+	result, err := r.conn().ExecContext(r.ctx, query, entry.TaskID, entry.UserID, entry.StartedAt, entry.EndedAt, entry.DurationSeconds, entry.Note)
+	if err != nil {
+		return fmt.Errorf("failed to create time entry: %w", err)
+	}
+	
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get time entry ID: %w", err)
+	}
+	
+	entry.ID = int(id)
+	entry.CreatedAt = time.Now()
+	
+	return nil
+}
+
+// GetTimeEntries returns the time entries of a task, including a running
+// timer, oldest first
+func (r *SQLiteRepository) GetTimeEntries(taskID int) ([]TimeEntry, error) {
+	query := `
+	SELECT ` + timeEntryColumns + `
+	FROM time_entries e
+	WHERE e.task_id = ?
+	ORDER BY e.started_at ASC, e.id ASC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time entries: %w", err)
+	}
+	defer rows.Close()
+	
+	return scanTimeEntries(rows)
+}
+
+// GetTimeEntriesBetween returns the completed time entries of tasks that are
+// not in the trash which started in [from, to), oldest first
+func (r *SQLiteRepository) GetTimeEntriesBetween(from, to time.Time) ([]TimeEntry, error) {
+	query := `
+	SELECT ` + timeEntryColumns + `
+	FROM time_entries e
+	INNER JOIN tasks t ON e.task_id = t.id
+	WHERE e.ended_at IS NOT NULL AND e.started_at >= ? AND e.started_at < ? AND t.deleted_at IS NULL
+	ORDER BY e.started_at ASC, e.id ASC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time entries: %w", err)
+	}
+	defer rows.Close()
+	
+	return scanTimeEntries(rows)
+}
+
+// Comment operations
+
+// commentColumns is the column list selected by every comment query, in the
+// order expected by scanComment. Queries must alias task_comments as c and
+// join users as u.
+const commentColumns = `c.id, c.task_id, c.parent_id, c.user_id, u.username, c.body, c.created_at, c.updated_at, c.deleted_at`
+
+// scanComment scans a single row selected with commentColumns
+func scanComment(row rowScanner) (*Comment, error) {
+	comment := &Comment{}
+	err := row.Scan(
+		&comment.ID,
+		&comment.TaskID,
+		&comment.ParentID,
+		&comment.UserID,
+		&comment.Username,
+		&comment.Body,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+		&comment.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	
+	return comment, nil
+}
+
+func (r *SQLiteRepository) CreateComment(comment *Comment) error {
+	query := `
+	INSERT INTO task_comments (task_id, parent_id, user_id, body, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?)`
+	
+	now := time.Now()
+	result, err := r.conn().ExecContext(r.ctx, query, comment.TaskID, comment.ParentID, comment.UserID, comment.Body, now, now)
+	if err != nil {
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+	
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get comment ID: %w", err)
+	}
+	
+	comment.ID = int(id)
+	comment.CreatedAt = now
+	comment.UpdatedAt = now
+	
+	return nil
+}
+
+func (r *SQLiteRepository) GetComment(id int) (*Comment, error) {
+	query := `
+	SELECT ` + commentColumns + `
+	FROM task_comments c
+	INNER JOIN users u ON c.user_id = u.id
+	WHERE c.id = ?`
+	
+	comment, err := scanComment(r.conn().QueryRowContext(r.ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("comment with ID %d not found", id)
+		}
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+	
+	return comment, nil
+}
+
+// GetTaskComments returns every comment of a task, including deleted
+// placeholders, oldest first
+func (r *SQLiteRepository) GetTaskComments(taskID int) ([]Comment, error) {
+	query := `
+	SELECT ` + commentColumns + `
+	FROM task_comments c
+	INNER JOIN users u ON c.user_id = u.id
+	WHERE c.task_id = ?
+	ORDER BY c.created_at ASC, c.id ASC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+	defer rows.Close()
+	
+	var comments []Comment
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		comments = append(comments, *comment)
+	}
+	
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate comments: %w", err)
+	}
+	
+	return comments, nil
+}
+
+// UpdateComment replaces the body of a comment that has not been deleted
+func (r *SQLiteRepository) UpdateComment(comment *Comment) error {
+	query := `UPDATE task_comments SET body = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL`
+	
+	comment.UpdatedAt = time.Now()
+	result, err := r.conn().ExecContext(r.ctx, query, comment.Body, comment.UpdatedAt, comment.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+	
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	
+	if rowsAffected == 0 {
+		return fmt.Errorf("comment with ID %d not found", comment.ID)
+	}
+	
+	return nil
+}
+
+// DeleteComment clears the body of a comment and marks it as deleted
+func (r *SQLiteRepository) DeleteComment(id int) error {
+	query := `UPDATE task_comments SET body = '', deleted_at = ? WHERE id = ? AND deleted_at IS NULL`
+	
+	result, err := r.conn().ExecContext(r.ctx, query, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+	
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	
+	if rowsAffected == 0 {
+		return fmt.Errorf("comment with ID %d not found", id)
+	}
+	
+	return nil
+}
+
+// Assignee operations
+
+// SetTaskAssignees replaces the assignees of a task and records the change in
+// the task history
+func (r *SQLiteRepository) SetTaskAssignees(taskID int, assignees []TaskAssignee) error {
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin assignee update: %w", err)
+	}
+	defer tx.Rollback()
+	
+	old, err := queryTaskAssignees(r.ctx, tx, taskID)
+	if err != nil {
+		return err
+	}
+	
+	if _, err := tx.ExecContext(r.ctx, `DELETE FROM task_assignees WHERE task_id = ?`, taskID); err != nil {
+		return fmt.Errorf("failed to clear assignees: %w", err)
+	}
+	
+	now := time.Now()
+	for _, assignee := range assignees {
+		query := `INSERT INTO task_assignees (task_id, user_id, role, created_at) VALUES (?, ?, ?, ?)`
+		if _, err := tx.ExecContext(r.ctx, query, taskID, assignee.UserID, assignee.Role, now); err != nil {
+			return fmt.Errorf("failed to add assignee: %w", err)
+		}
+	}
+	
+	oldValue, newValue := assigneesValue(old), assigneesValue(assignees)
+	if (oldValue == nil) != (newValue == nil) || (oldValue != nil && *oldValue != *newValue) {
+		change := historyChange{field: "assignees", oldValue: oldValue, newValue: newValue}
+		if err := r.recordHistory(tx, taskID, change); err != nil {
+			return err
+		}
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit assignee update: %w", err)
+	}
+	
+	return nil
+}
+
+// GetTaskAssignees returns the assignees of a task, ordered by user
+func (r *SQLiteRepository) GetTaskAssignees(taskID int) ([]TaskAssignee, error) {
+	return queryTaskAssignees(r.ctx, r.conn(), taskID)
+}
+
+// queryer is implemented by both *sql.DB and *sql.Tx
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func queryTaskAssignees(ctx context.Context, q queryer, taskID int) ([]TaskAssignee, error) {
+	query := `
+	SELECT ta.task_id, ta.user_id, u.username, ta.role, ta.created_at
+	FROM task_assignees ta
+	INNER JOIN users u ON ta.user_id = u.id
+	WHERE ta.task_id = ?
+	ORDER BY ta.user_id ASC`
+	
+	rows, err := q.QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assignees: %w", err)
+	}
+	defer rows.Close()
+	
+	var assignees []TaskAssignee
+	for rows.Next() {
+		var assignee TaskAssignee
+		if err := rows.Scan(&assignee.TaskID, &assignee.UserID, &assignee.Username, &assignee.Role, &assignee.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan assignee: %w", err)
+		}
+		assignees = append(assignees, assignee)
+	}
+	
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate assignees: %w", err)
+	}
+	
+	return assignees, nil
+}
+
+// Attachment operations
+
+// attachmentColumns is the column list selected by every attachment query, in
+// the order expected by scanAttachment. Queries must alias task_attachments as
+// a and left join users as u.
+const attachmentColumns = `a.id, a.task_id, a.user_id, COALESCE(u.username, ''), a.file_name, a.content_type, a.size, a.sha256, a.created_at`
+
+// scanAttachment scans a single row selected with attachmentColumns
+func scanAttachment(row rowScanner) (*Attachment, error) {
+	attachment := &Attachment{}
+	err := row.Scan(
+		&attachment.ID,
+		&attachment.TaskID,
+		&attachment.UserID,
+		&attachment.Username,
+		&attachment.FileName,
+		&attachment.ContentType,
+		&attachment.Size,
+		&attachment.SHA256,
+		&attachment.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	
+	return attachment, nil
+}
+
+func (r *SQLiteRepository) CreateAttachment(attachment *Attachment) error {
+	query := `
+	INSERT INTO task_attachments (task_id, user_id, file_name, content_type, size, sha256, created_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?)`
+	
+	now := time.Now()
+	result, err := r.conn().ExecContext(r.ctx, query,
+		attachment.TaskID,
+		attachment.UserID,
+		attachment.FileName,
+		attachment.ContentType,
+		attachment.Size,
+		attachment.SHA256,
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create attachment: %w", err)
+	}
+	
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get attachment ID: %w", err)
+	}
+	
+	attachment.ID = int(id)
+	attachment.CreatedAt = now
+	
+	return nil
+}
+
+func (r *SQLiteRepository) GetAttachment(id int) (*Attachment, error) {
+	query := `
+	SELECT ` + attachmentColumns + `
+	FROM task_attachments a
+	LEFT JOIN users u ON a.user_id = u.id
+	WHERE a.id = ?`
+	
+	attachment, err := scanAttachment(r.conn().QueryRowContext(r.ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("attachment with ID %d not found", id)
+		}
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+	
+	return attachment, nil
+}
+
+// GetTaskAttachments returns the attachments of a task, oldest first
+func (r *SQLiteRepository) GetTaskAttachments(taskID int) ([]Attachment, error) {
+	query := `
+	SELECT ` + attachmentColumns + `
+	FROM task_attachments a
+	LEFT JOIN users u ON a.user_id = u.id
+	WHERE a.task_id = ?
+	ORDER BY a.created_at ASC, a.id ASC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+	defer rows.Close()
+	
+	var attachments []Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		attachments = append(attachments, *attachment)
+	}
+	
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate attachments: %w", err)
+	}
+	
+	return attachments, nil
+}
+
+// DeleteAttachment removes the record of an attachment. The stored file is
+// left alone since other attachments may share it.
+func (r *SQLiteRepository) DeleteAttachment(id int) error {
+	result, err := r.conn().ExecContext(r.ctx, `DELETE FROM task_attachments WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+	
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	
+	if rowsAffected == 0 {
+		return fmt.Errorf("attachment with ID %d not found", id)
+	}
+	
+	return nil
+}
+
+// CountAttachmentsBySHA returns how many attachments refer to the file with
+// the given digest
+func (r *SQLiteRepository) CountAttachmentsBySHA(sha256 string) (int, error) {
+	var count int
+	err := r.conn().QueryRowContext(r.ctx, `SELECT COUNT(*) FROM task_attachments WHERE sha256 = ?`, sha256).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count attachments: %w", err)
+	}
+	
+	return count, nil
+}
+
+// Project operations
+
+// projectColumns is the column list selected by every project query, in the
+// order expected by scanProject. Queries must alias the projects table as p.
+const projectColumns = `p.id, p.name, COALESCE(p.description, ''), p.owner_id, p.default_priority, p.workflow, p.created_at, p.updated_at`
+
+// scanProject scans a single row selected with projectColumns
+func scanProject(row rowScanner) (*Project, error) {
+	project := &Project{}
+	err := row.Scan(
+		&project.ID,
+		&project.Name,
+		&project.Description,
+		&project.OwnerID,
+		&project.DefaultPriority,
+		&project.Workflow,
+		&project.CreatedAt,
+		&project.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	
+	return project, nil
+}
+
+// CreateProject creates a project and makes its owner a member with the owner role
+func (r *SQLiteRepository) CreateProject(project *Project) error {
+	query := `
+	INSERT INTO projects (name, description, owner_id, default_priority, workflow, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?)`
+	
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin project creation: %w", err)
+	}
+	defer tx.Rollback()
+	
+	now := time.Now()
+	result, err := tx.ExecContext(r.ctx, query,
+		project.Name,
+		project.Description,
+		project.OwnerID,
+		project.DefaultPriority,
+		project.Workflow,
+		now,
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create project: %w", err)
+	}
+	
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get project ID: %w", err)
+	}
+	
+	if project.OwnerID != nil {
+		query := `INSERT INTO project_members (project_id, user_id, role, created_at) VALUES (?, ?, 'owner', ?)`
+		if _, err := tx.ExecContext(r.ctx, query, id, *project.OwnerID, now); err != nil {
+			return fmt.Errorf("failed to add project owner: %w", err)
+		}
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit project creation: %w", err)
+	}
+	
+	project.ID = int(id)
+	project.CreatedAt = now
+	project.UpdatedAt = now
+	
+	return nil
+}
+
+func (r *SQLiteRepository) GetProject(id int) (*Project, error) {
+	query := `
+	SELECT ` + projectColumns + `
+	FROM projects p WHERE p.id = ?`
+	
+	project, err := scanProject(r.conn().QueryRowContext(r.ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("project with ID %d not found", id)
+		}
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	
+	return project, nil
+}
+
+// GetProjectsByUser returns the projects the user is a member of, by name
+func (r *SQLiteRepository) GetProjectsByUser(userID int) ([]Project, error) {
+	query := `
+	SELECT ` + projectColumns + `
+	FROM projects p
+	INNER JOIN project_members pm ON pm.project_id = p.id
+	WHERE pm.user_id = ?
+	ORDER BY p.name ASC, p.id ASC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get projects by user: %w", err)
+	}
+	defer rows.Close()
+	
+	var projects []Project
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan project: %w", err)
+		}
+		projects = append(projects, *project)
+	}
+	
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate projects: %w", err)
+	}
+	
+	return projects, nil
+}
+
+// UpdateProject updates the name, description and settings of a project
+func (r *SQLiteRepository) UpdateProject(project *Project) error {
+	query := `
+	UPDATE projects 
+	SET name = ?, description = ?, default_priority = ?, workflow = ?, updated_at = ?
+	WHERE id = ?`
+	
+	project.UpdatedAt = time.Now()
+	
+	result, err := r.conn().ExecContext(r.ctx, query,
+		project.Name,
+		project.Description,
+		project.DefaultPriority,
+		project.Workflow,
+		project.UpdatedAt,
+		project.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update project: %w", err)
+	}
+	
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	
+	if rowsAffected == 0 {
+		return fmt.Errorf("project with ID %d not found", project.ID)
+	}
+	
+	return nil
+}
+
+// DeleteProject deletes a project together with its categories, tags,
+// boards, sprints, custom fields, templates and members. Projects that still have tasks
+// outside the trash cannot be deleted; trashed tasks are moved out of the
+// project.
+func (r *SQLiteRepository) DeleteProject(id int) error {
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin project deletion: %w", err)
+	}
+	defer tx.Rollback()
+	
+	var tasks int
+	query := `SELECT COUNT(*) FROM tasks WHERE project_id = ? AND deleted_at IS NULL`
+	if err := tx.QueryRowContext(r.ctx, query, id).Scan(&tasks); err != nil {
+		return fmt.Errorf("failed to count project tasks: %w", err)
+	}
+	if tasks > 0 {
+		return fmt.Errorf("project with ID %d still has %d tasks", id, tasks)
+	}
+	
+	// Foreign keys are not enforced, so related rows are removed explicitly
+	queries := []string{
+		`UPDATE tasks SET category_id = NULL WHERE category_id IN (SELECT id FROM categories WHERE project_id = :id)`,
+		`UPDATE tasks SET project_id = NULL WHERE project_id = :id`,
+		`DELETE FROM task_custom_values WHERE field_id IN (SELECT id FROM custom_fields WHERE project_id = :id OR category_id IN (SELECT id FROM categories WHERE project_id = :id))`,
+		`DELETE FROM custom_fields WHERE project_id = :id OR category_id IN (SELECT id FROM categories WHERE project_id = :id)`,
+		`DELETE FROM task_tags WHERE tag_id IN (SELECT id FROM tags WHERE project_id = :id)`,
+		`DELETE FROM tags WHERE project_id = :id`,
+		`DELETE FROM categories WHERE project_id = :id`,
+		`DELETE FROM project_members WHERE project_id = :id`,
+		`DELETE FROM board_cards WHERE board_id IN (SELECT id FROM boards WHERE project_id = :id)`,
+		`DELETE FROM board_columns WHERE board_id IN (SELECT id FROM boards WHERE project_id = :id)`,
+		`DELETE FROM boards WHERE project_id = :id`,
+		`UPDATE tasks SET sprint_id = NULL WHERE sprint_id IN (SELECT id FROM sprints WHERE project_id = :id)`,
+		`DELETE FROM sprints WHERE project_id = :id`,
+		`DELETE FROM task_templates WHERE project_id = :id`,
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(r.ctx, query, sql.Named("id", id)); err != nil {
+			return fmt.Errorf("failed to delete project: %w", err)
+		}
+	}
+	
+	result, err := tx.ExecContext(r.ctx, `DELETE FROM projects WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+	
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	
+	if rowsAffected == 0 {
+		return fmt.Errorf("project with ID %d not found", id)
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit project deletion: %w", err)
+	}
+	
+	return nil
+}
+
+// SetProjectMember adds a user to a project, or changes the role of a member
+func (r *SQLiteRepository) SetProjectMember(member *ProjectMember) error {
+	query := `
+	INSERT INTO project_members (project_id, user_id, role, created_at)
+	VALUES (?, ?, ?, ?)
+	ON CONFLICT (project_id, user_id) DO UPDATE SET role = excluded.role`
+	
+	now := time.Now()
+	if _, err := r.conn().ExecContext(r.ctx, query, member.ProjectID, member.UserID, member.Role, now); err != nil {
+		return fmt.Errorf("failed to set project member: %w", err)
+	}
+	
+	return nil
+}
+
+func (r *SQLiteRepository) RemoveProjectMember(projectID, userID int) error {
+	query := `DELETE FROM project_members WHERE project_id = ? AND user_id = ?`
+	
+	result, err := r.conn().ExecContext(r.ctx, query, projectID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove project member: %w", err)
+	}
+	
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	
+	if rowsAffected == 0 {
+		return fmt.Errorf("member with user ID %d not found", userID)
+	}
+	
+	return nil
+}
+
+// GetProjectMembers returns the members of a project, ordered by user
+func (r *SQLiteRepository) GetProjectMembers(projectID int) ([]ProjectMember, error) {
+	query := `
+	SELECT pm.project_id, pm.user_id, u.username, pm.role, pm.created_at
+	FROM project_members pm
+	INNER JOIN users u ON pm.user_id = u.id
+	WHERE pm.project_id = ?
+	ORDER BY pm.user_id ASC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project members: %w", err)
+	}
+	defer rows.Close()
+	
+	var members []ProjectMember
+	for rows.Next() {
+		var member ProjectMember
+		if err := rows.Scan(&member.ProjectID, &member.UserID, &member.Username, &member.Role, &member.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan project member: %w", err)
+		}
+		members = append(members, member)
+	}
+	
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate project members: %w", err)
+	}
+	
+	return members, nil
+}
+
+// Board operations
+
+// boardColumns is the column list selected by every board query, in the
+// order expected by scanBoard. Queries must alias the boards table as b.
+const boardColumns = `b.id, b.name, b.owner_id, b.project_id, b.created_at, b.updated_at`
+
+// scanBoard scans a single row selected with boardColumns
+func scanBoard(row rowScanner) (*Board, error) {
+	board := &Board{}
+	err := row.Scan(
+		&board.ID,
+		&board.Name,
+		&board.OwnerID,
+		&board.ProjectID,
+		&board.CreatedAt,
+		&board.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	
+	return board, nil
+}
+
+// CreateBoard creates a board with its columns, in the given order
+func (r *SQLiteRepository) CreateBoard(board *Board, columns []BoardColumn) error {
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin board creation: %w", err)
+	}
+	defer tx.Rollback()
+	
+	now := time.Now()
+	query := `
+	INSERT INTO boards (name, owner_id, project_id, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?)`
+	
+	result, err := tx.ExecContext(r.ctx, query, board.Name, board.OwnerID, board.ProjectID, now, now)
+	if err != nil {
+		return fmt.Errorf("failed to create board: %w", err)
+	}
+	
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get board ID: %w", err)
+	}
+	
+	for i := range columns {
+		columns[i].ID = 0
+		if err := r.saveBoardColumn(tx, int(id), i, &columns[i]); err != nil {
+			return err
+		}
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit board creation: %w", err)
+	}
+	
+	board.ID = int(id)
+	board.CreatedAt = now
+	board.UpdatedAt = now
+	
+	return nil
+}
+
+// saveBoardColumn inserts a column without an ID, or updates an existing
+// column of the board, at the given position
+func (r *SQLiteRepository) saveBoardColumn(tx *txn, boardID, position int, column *BoardColumn) error {
+	column.BoardID = boardID
+	column.Position = position
+	
+	if column.ID == 0 {
+		query := `
+		INSERT INTO board_columns (board_id, name, position, status, tag_id, wip_limit)
+		VALUES (?, ?, ?, ?, ?, ?)`
+		
+		result, err := tx.ExecContext(r.ctx, query, boardID, column.Name, position, column.Status, column.TagID, column.WIPLimit)
+		if err != nil {
+			return fmt.Errorf("failed to create board column: %w", err)
+		}
+		
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get board column ID: %w", err)
+		}
+		column.ID = int(id)
+		return nil
+	}
+	
+	query := `
+	UPDATE board_columns
+	SET name = ?, position = ?, status = ?, tag_id = ?, wip_limit = ?
+	WHERE id = ? AND board_id = ?`
+	
+	result, err := tx.ExecContext(r.ctx, query, column.Name, position, column.Status, column.TagID, column.WIPLimit, column.ID, boardID)
+	if err != nil {
+		return fmt.Errorf("failed to update board column: %w", err)
+	}
+	
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	
+	if rowsAffected == 0 {
+		return fmt.Errorf("board column with ID %d not found", column.ID)
+	}
+	
+	return nil
+}
+
+func (r *SQLiteRepository) GetBoard(id int) (*Board, error) {
+	query := `
+	SELECT ` + boardColumns + `
+	FROM boards b WHERE b.id = ?`
+	
+	board, err := scanBoard(r.conn().QueryRowContext(r.ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("board with ID %d not found", id)
+		}
+		return nil, fmt.Errorf("failed to get board: %w", err)
+	}
+	
+	return board, nil
+}
+
+// GetBoardsByUser returns the boards the user owns and the boards of the
+// projects they are a member of, by name
+func (r *SQLiteRepository) GetBoardsByUser(userID int) ([]Board, error) {
+	query := `
+	SELECT ` + boardColumns + `
+	FROM boards b
+	WHERE (b.project_id IS NULL AND b.owner_id = :user)
+		OR b.project_id IN (SELECT project_id FROM project_members WHERE user_id = :user)
+	ORDER BY b.name ASC, b.id ASC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, sql.Named("user", userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get boards by user: %w", err)
+	}
+	defer rows.Close()
+	
+	var boards []Board
+	for rows.Next() {
+		board, err := scanBoard(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan board: %w", err)
+		}
+		boards = append(boards, *board)
+	}
+	
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate boards: %w", err)
+	}
+	
+	return boards, nil
+}
+
+// GetBoardsByTask returns the boards whose scope includes the task: the
+// personal boards of its owner and the boards of its project, by name
+func (r *SQLiteRepository) GetBoardsByTask(taskID int) ([]Board, error) {
+	query := `
+	SELECT ` + boardColumns + `
+	FROM boards b
+	JOIN tasks t ON t.id = ?
+	WHERE (b.project_id IS NULL AND b.owner_id = t.user_id)
+		OR b.project_id = t.project_id
+	ORDER BY b.name ASC, b.id ASC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get boards by task: %w", err)
+	}
+	defer rows.Close()
+	
+	var boards []Board
+	for rows.Next() {
+		board, err := scanBoard(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan board: %w", err)
+		}
+		boards = append(boards, *board)
+	}
+	
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate boards: %w", err)
+	}
+	
+	return boards, nil
+}
+
+// UpdateBoard renames a board and replaces its columns. Columns with an ID
+// are kept with their cards, columns without one are added and the columns
+// left out are removed.
+func (r *SQLiteRepository) UpdateBoard(board *Board, columns []BoardColumn) error {
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin board update: %w", err)
+	}
+	defer tx.Rollback()
+	
+	board.UpdatedAt = time.Now()
+	result, err := tx.ExecContext(r.ctx, `UPDATE boards SET name = ?, updated_at = ? WHERE id = ?`, board.Name, board.UpdatedAt, board.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update board: %w", err)
+	}
+	
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	
+	if rowsAffected == 0 {
+		return fmt.Errorf("board with ID %d not found", board.ID)
+	}
+	
+	kept := []interface{}{board.ID}
+	placeholders := ""
+	for _, column := range columns {
+		if column.ID != 0 {
+			kept = append(kept, column.ID)
+			placeholders += ", ?"
+		}
+	}
+	
+	// Foreign keys are not enforced, so the cards of removed columns are removed explicitly
+	removed := `SELECT id FROM board_columns WHERE board_id = ? AND id NOT IN (0` + placeholders + `)`
+	if _, err := tx.ExecContext(r.ctx, `DELETE FROM board_cards WHERE column_id IN (`+removed+`)`, kept...); err != nil {
+		return fmt.Errorf("failed to remove board cards: %w", err)
+	}
+	if _, err := tx.ExecContext(r.ctx, `DELETE FROM board_columns WHERE id IN (`+removed+`)`, kept...); err != nil {
+		return fmt.Errorf("failed to remove board columns: %w", err)
+	}
+	
+	for i := range columns {
+		if err := r.saveBoardColumn(tx, board.ID, i, &columns[i]); err != nil {
+			return err
+		}
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit board update: %w", err)
+	}
+	
+	return nil
+}
+
+// DeleteBoard deletes a board with its columns and cards. The tasks are kept.
+func (r *SQLiteRepository) DeleteBoard(id int) error {
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin board deletion: %w", err)
+	}
+	defer tx.Rollback()
+	
+	for _, query := range []string{
+		`DELETE FROM board_cards WHERE board_id = ?`,
+		`DELETE FROM board_columns WHERE board_id = ?`,
+	} {
+		if _, err := tx.ExecContext(r.ctx, query, id); err != nil {
+			return fmt.Errorf("failed to delete board: %w", err)
+		}
+	}
+	
+	result, err := tx.ExecContext(r.ctx, `DELETE FROM boards WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete board: %w", err)
+	}
+	
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	
+	if rowsAffected == 0 {
+		return fmt.Errorf("board with ID %d not found", id)
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit board deletion: %w", err)
+	}
+	
+	return nil
+}
+
+// GetBoardColumns returns the columns of a board from left to right
+func (r *SQLiteRepository) GetBoardColumns(boardID int) ([]BoardColumn, error) {
+	query := `
+	SELECT c.id, c.board_id, c.name, c.position, c.status, c.tag_id, COALESCE(t.name, ''), c.wip_limit
+	FROM board_columns c
+	LEFT JOIN tags t ON c.tag_id = t.id
+	WHERE c.board_id = ?
+	ORDER BY c.position ASC, c.id ASC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, boardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get board columns: %w", err)
+	}
+	defer rows.Close()
+	
+	var columns []BoardColumn
+	for rows.Next() {
+		var column BoardColumn
+		err := rows.Scan(
+			&column.ID,
+			&column.BoardID,
+			&column.Name,
+			&column.Position,
+			&column.Status,
+			&column.TagID,
+			&column.TagName,
+			&column.WIPLimit,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan board column: %w", err)
+		}
+		columns = append(columns, column)
+	}
+	
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate board columns: %w", err)
+	}
+	
+	return columns, nil
+}
+
+// GetBoardCards returns the stored card positions of a board, by column and position
+func (r *SQLiteRepository) GetBoardCards(boardID int) ([]BoardCard, error) {
+	query := `
+	SELECT board_id, task_id, column_id, position
+	FROM board_cards
+	WHERE board_id = ?
+	ORDER BY column_id ASC, position ASC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, boardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get board cards: %w", err)
+	}
+	defer rows.Close()
+	
+	var cards []BoardCard
+	for rows.Next() {
+		var card BoardCard
+		if err := rows.Scan(&card.BoardID, &card.TaskID, &card.ColumnID, &card.Position); err != nil {
+			return nil, fmt.Errorf("failed to scan board card: %w", err)
+		}
+		cards = append(cards, card)
+	}
+	
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate board cards: %w", err)
+	}
+	
+	return cards, nil
+}
+
+// MoveBoardCard changes the status and tags of a task and renumbers the
+// cards of the target column, all in one transaction. Status and tag changes
+// are recorded in the task history.
+func (r *SQLiteRepository) MoveBoardCard(move *BoardCardMove) error {
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin card move: %w", err)
+	}
+	defer tx.Rollback()
+	
+	current, err := scanTask(tx.QueryRowContext(r.ctx, `
+	SELECT `+taskColumns+`
+	FROM tasks t WHERE t.id = ? AND t.deleted_at IS NULL`, move.TaskID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("task with ID %d not found", move.TaskID)
+		}
+		return fmt.Errorf("failed to move card: %w", err)
+	}
+	
+	if move.Status != nil && *move.Status != current.Status {
+		query := `UPDATE tasks SET status = ?, updated_at = ? WHERE id = ?`
+		if _, err := tx.ExecContext(r.ctx, query, *move.Status, time.Now(), move.TaskID); err != nil {
+			return fmt.Errorf("failed to update task status: %w", err)
+		}
+		change := historyChange{field: "status", oldValue: intValue(&current.Status), newValue: intValue(move.Status)}
+		if err := r.recordHistory(tx, move.TaskID, change); err != nil {
+			return err
+		}
+	}
+	
+	for _, tagID := range move.RemoveTagIDs {
+		result, err := tx.ExecContext(r.ctx, `DELETE FROM task_tags WHERE task_id = ? AND tag_id = ?`, move.TaskID, tagID)
+		if err != nil {
+			return fmt.Errorf("failed to remove tag from task: %w", err)
+		}
+		if removed, err := result.RowsAffected(); err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		} else if removed > 0 {
+			if err := r.recordHistory(tx, move.TaskID, historyChange{field: "tag_id", oldValue: intValue(&tagID)}); err != nil {
+				return err
+			}
+		}
+	}
+	
+	if move.AddTagID != nil {
+		result, err := tx.ExecContext(r.ctx, `INSERT OR IGNORE INTO task_tags (task_id, tag_id) VALUES (?, ?)`, move.TaskID, *move.AddTagID)
+		if err != nil {
+			return fmt.Errorf("failed to add tag to task: %w", err)
+		}
+		if added, err := result.RowsAffected(); err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		} else if added > 0 {
+			if err := r.recordHistory(tx, move.TaskID, historyChange{field: "tag_id", newValue: intValue(move.AddTagID)}); err != nil {
+				return err
+			}
+		}
+	}
+	
+	query := `
+	INSERT INTO board_cards (board_id, task_id, column_id, position)
+	VALUES (?, ?, ?, ?)
+	ON CONFLICT (board_id, task_id) DO UPDATE SET column_id = excluded.column_id, position = excluded.position`
+	for position, taskID := range move.Order {
+		if _, err := tx.ExecContext(r.ctx, query, move.BoardID, taskID, move.ColumnID, position); err != nil {
+			return fmt.Errorf("failed to order board cards: %w", err)
+		}
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit card move: %w", err)
+	}
+	
+	return nil
+}
+
+// Sprint operations
+
+// sprintColumns is the column list selected by every sprint query, in the
+// order expected by scanSprint. Queries must alias the sprints table as s.
+const sprintColumns = `s.id, s.name, s.goal, s.owner_id, s.project_id, s.start_date, s.end_date, s.closed_at, s.created_at, s.updated_at`
+
+// scanSprint scans a single row selected with sprintColumns
+func scanSprint(row rowScanner) (*Sprint, error) {
+	sprint := &Sprint{}
+	err := row.Scan(
+		&sprint.ID,
+		&sprint.Name,
+		&sprint.Goal,
+		&sprint.OwnerID,
+		&sprint.ProjectID,
+		&sprint.StartDate,
+		&sprint.EndDate,
+		&sprint.ClosedAt,
+		&sprint.CreatedAt,
+		&sprint.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	
+	return sprint, nil
+}
+
+func (r *SQLiteRepository) CreateSprint(sprint *Sprint) error {
+	query := `
+	INSERT INTO sprints (name, goal, owner_id, project_id, start_date, end_date, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	
+	now := time.Now()
+	result, err := r.conn().ExecContext(r.ctx, query,
+		sprint.Name,
+		sprint.Goal,
+		sprint.OwnerID,
+		sprint.ProjectID,
+		sprint.StartDate,
+		sprint.EndDate,
+		now,
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create sprint: %w", err)
+	}
+	
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get sprint ID: %w", err)
+	}
+	
+	sprint.ID = int(id)
+	sprint.CreatedAt = now
+	sprint.UpdatedAt = now
+	
+	return nil
+}
+
+func (r *SQLiteRepository) GetSprint(id int) (*Sprint, error) {
+	query := `
+	SELECT ` + sprintColumns + `
+	FROM sprints s WHERE s.id = ?`
+	
+	sprint, err := scanSprint(r.conn().QueryRowContext(r.ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("sprint with ID %d not found", id)
+		}
+		return nil, fmt.Errorf("failed to get sprint: %w", err)
+	}
+	
+	return sprint, nil
+}
+
+// GetSprints returns the sprints of a project or, when projectID is nil, the
+// personal sprints of the owner, by start date
+func (r *SQLiteRepository) GetSprints(ownerID int, projectID *int) ([]Sprint, error) {
+	query := `
+	SELECT ` + sprintColumns + `
+	FROM sprints s
+	WHERE s.project_id IS NULL AND s.owner_id = ?
+	ORDER BY s.start_date ASC, s.id ASC`
+	args := []interface{}{ownerID}
+	
+	if projectID != nil {
+		query = `
+		SELECT ` + sprintColumns + `
+		FROM sprints s
+		WHERE s.project_id = ?
+		ORDER BY s.start_date ASC, s.id ASC`
+		args = []interface{}{*projectID}
+	}
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sprints: %w", err)
+	}
+	defer rows.Close()
+	
+	var sprints []Sprint
+	for rows.Next() {
+		sprint, err := scanSprint(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sprint: %w", err)
+		}
+		sprints = append(sprints, *sprint)
+	}
+	
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate sprints: %w", err)
+	}
+	
+	return sprints, nil
+}
+
+func (r *SQLiteRepository) UpdateSprint(sprint *Sprint) error {
+	query := `
+	UPDATE sprints
+	SET name = ?, goal = ?, start_date = ?, end_date = ?, updated_at = ?
+	WHERE id = ?`
+	
+	sprint.UpdatedAt = time.Now()
+	result, err := r.conn().ExecContext(r.ctx, query,
+		sprint.Name,
+		sprint.Goal,
+		sprint.StartDate,
+		sprint.EndDate,
+		sprint.UpdatedAt,
+		sprint.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update sprint: %w", err)
+	}
+	
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	
+	if rowsAffected == 0 {
+		return fmt.Errorf("sprint with ID %d not found", sprint.ID)
+	}
+	
+	return nil
+}
+
+// moveSprintTasks moves the tasks of a sprint selected by condition to
+// another sprint, or to the backlog when sprintID is nil, recording the
+// change in their history
+func (r *SQLiteRepository) moveSprintTasks(tx *txn, fromID int, condition string, sprintID *int) error {
+	rows, err := tx.QueryContext(r.ctx, `SELECT id FROM tasks WHERE sprint_id = ? AND `+condition, fromID)
+	if err != nil {
+		return fmt.Errorf("failed to get sprint tasks: %w", err)
+	}
+	
+	var taskIDs []int
+	for rows.Next() {
+		var taskID int
+		if err := rows.Scan(&taskID); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan sprint task: %w", err)
+		}
+		taskIDs = append(taskIDs, taskID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate sprint tasks: %w", err)
+	}
+	
+	now := time.Now()
+	for _, taskID := range taskIDs {
+		if _, err := tx.ExecContext(r.ctx, `UPDATE tasks SET sprint_id = ?, updated_at = ? WHERE id = ?`, sprintID, now, taskID); err != nil {
+			return fmt.Errorf("failed to move sprint task: %w", err)
+		}
+		change := historyChange{field: "sprint_id", oldValue: intValue(&fromID), newValue: intValue(sprintID)}
+		if err := r.recordHistory(tx, taskID, change); err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
+// DeleteSprint deletes a sprint and moves its tasks, trashed ones included,
+// back to the backlog
+func (r *SQLiteRepository) DeleteSprint(id int) error {
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin sprint deletion: %w", err)
+	}
+	defer tx.Rollback()
+	
+	if err := r.moveSprintTasks(tx, id, "1 = 1", nil); err != nil {
+		return err
+	}
+	
+	result, err := tx.ExecContext(r.ctx, `DELETE FROM sprints WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete sprint: %w", err)
+	}
+	
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	
+	if rowsAffected == 0 {
+		return fmt.Errorf("sprint with ID %d not found", id)
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit sprint deletion: %w", err)
+	}
+	
+	return nil
+}
+
+// CloseSprint closes an open sprint and moves its tasks that are neither
+// completed nor cancelled to the carryOverTo sprint, or to the backlog when
+// it is nil. The tasks are moved after the closing time, so reports taken at
+// that time still see them in the closed sprint.
+func (r *SQLiteRepository) CloseSprint(id int, carryOverTo *int) (*Sprint, error) {
+	tx, err := r.begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin sprint closing: %w", err)
+	}
+	defer tx.Rollback()
+	
+	closedAt := time.Now()
+	result, err := tx.ExecContext(r.ctx, `UPDATE sprints SET closed_at = ?, updated_at = ? WHERE id = ? AND closed_at IS NULL`, closedAt, closedAt, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to close sprint: %w", err)
+	}
+	
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	
+	if rowsAffected == 0 {
+		return nil, fmt.Errorf("sprint with ID %d not found or already closed", id)
+	}
+	
+	// Statuses 2 and 3 are completed and cancelled
+	if err := r.moveSprintTasks(tx, id, "status NOT IN (2, 3) AND deleted_at IS NULL", carryOverTo); err != nil {
+		return nil, err
+	}
+	
+	sprint, err := scanSprint(tx.QueryRowContext(r.ctx, `SELECT `+sprintColumns+` FROM sprints s WHERE s.id = ?`, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sprint: %w", err)
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit sprint closing: %w", err)
+	}
+	
+	return sprint, nil
+}
+
+// GetTasksBySprint returns the tasks currently in a sprint, archived ones
+// included, by ID
+func (r *SQLiteRepository) GetTasksBySprint(sprintID int) ([]DatabaseTask, error) {
+	query := `
+	SELECT ` + taskColumns + `
+	FROM tasks t
+	WHERE t.sprint_id = ? AND t.deleted_at IS NULL
+	ORDER BY t.id ASC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, sprintID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks by sprint: %w", err)
+	}
+	defer rows.Close()
+	
+	return scanTasks(rows)
+}
+
+// GetSprintHistoryTasks returns every task that is or ever was in a sprint,
+// trashed ones included, by ID. Reports replay their history to tell which
+// of them were in the sprint on a given day.
+func (r *SQLiteRepository) GetSprintHistoryTasks(sprintID int) ([]DatabaseTask, error) {
+	query := `
+	SELECT ` + taskColumns + `
+	FROM tasks t
+	WHERE t.sprint_id = :sprint
+		OR t.id IN (
+			SELECT task_id FROM task_history
+			WHERE field = 'sprint_id' AND (old_value = :value OR new_value = :value)
+		)
+	ORDER BY t.id ASC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, sql.Named("sprint", sprintID), sql.Named("value", strconv.Itoa(sprintID)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sprint history tasks: %w", err)
+	}
+	defer rows.Close()
+	
+	return scanTasks(rows)
+}
+
+// Custom field operations
+
+// customFieldColumns is the column list selected by every custom field query,
+// in the order expected by scanCustomField. Queries must alias the
+// custom_fields table as f.
+const customFieldColumns = `f.id, f.name, f.type, f.options, f.category_id, f.project_id, f.created_at, f.updated_at`
+
+// scanCustomField scans a single row selected with customFieldColumns. The
+// options of enum fields are stored as a JSON list.
+func scanCustomField(row rowScanner) (*CustomField, error) {
+	field := &CustomField{}
+	var options string
+	err := row.Scan(
+		&field.ID,
+		&field.Name,
+		&field.Type,
+		&options,
+		&field.CategoryID,
+		&field.ProjectID,
+		&field.CreatedAt,
+		&field.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	
+	if options != "" {
+		if err := json.Unmarshal([]byte(options), &field.Options); err != nil {
+			return nil, fmt.Errorf("invalid options of custom field %d: %w", field.ID, err)
+		}
+	}
+	
+	return field, nil
+}
+
+// customFieldOptions encodes the options of a field for storage
+func customFieldOptions(field *CustomField) (string, error) {
+	if len(field.Options) == 0 {
+		return "", nil
+	}
+	
+	options, err := json.Marshal(field.Options)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode custom field options: %w", err)
+	}
+	return string(options), nil
+}
+
+// scanCustomFields scans every row selected with customFieldColumns
+func scanCustomFields(rows *sql.Rows) ([]CustomField, error) {
+	var fields []CustomField
+	for rows.Next() {
+		field, err := scanCustomField(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan custom field: %w", err)
+		}
+		fields = append(fields, *field)
+	}
+	
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate custom fields: %w", err)
+	}
+	
+	return fields, nil
+}
+
+func (r *SQLiteRepository) CreateCustomField(field *CustomField) error {
+	query := `
+	INSERT INTO custom_fields (name, type, options, category_id, project_id, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?)`
+	
+	options, err := customFieldOptions(field)
+	if err != nil {
+		return err
+	}
+	
+	now := time.Now()
+	result, err := r.conn().ExecContext(r.ctx, query,
+		field.Name,
+		field.Type,
+		options,
+		field.CategoryID,
+		field.ProjectID,
+		now,
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create custom field: %w", err)
+	}
+	
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get custom field ID: %w", err)
+	}
+	
+	field.ID = int(id)
+	field.CreatedAt = now
+	field.UpdatedAt = now
+	
+	return nil
+}
+
+func (r *SQLiteRepository) GetCustomField(id int) (*CustomField, error) {
+	query := `
+	SELECT ` + customFieldColumns + `
+	FROM custom_fields f WHERE f.id = ?`
+	
+	field, err := scanCustomField(r.conn().QueryRowContext(r.ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("custom field with ID %d not found", id)
+		}
+		return nil, fmt.Errorf("failed to get custom field: %w", err)
+	}
+	
+	return field, nil
+}
+
+// GetCustomFields returns the fields of a category and of a project, either
+// of which may be nil, by name
+func (r *SQLiteRepository) GetCustomFields(categoryID, projectID *int) ([]CustomField, error) {
+	query := `
+	SELECT ` + customFieldColumns + `
+	FROM custom_fields f
+	WHERE f.category_id = ? OR f.project_id = ?
+	ORDER BY f.name ASC, f.id ASC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, categoryID, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom fields: %w", err)
+	}
+	defer rows.Close()
+	
+	return scanCustomFields(rows)
+}
+
+// GetAllCustomFields returns every custom field, by ID
+func (r *SQLiteRepository) GetAllCustomFields() ([]CustomField, error) {
+	query := `
+	SELECT ` + customFieldColumns + `
+	FROM custom_fields f
+	ORDER BY f.id ASC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom fields: %w", err)
+	}
+	defer rows.Close()
+	
+	return scanCustomFields(rows)
+}
+
+// UpdateCustomField updates the name and options of a field. The values of
+// an enum field that are no longer among its options are removed.
+func (r *SQLiteRepository) UpdateCustomField(field *CustomField) error {
+	options, err := customFieldOptions(field)
+	if err != nil {
+		return err
+	}
+	
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin custom field update: %w", err)
+	}
+	defer tx.Rollback()
+	
+	field.UpdatedAt = time.Now()
+	result, err := tx.ExecContext(r.ctx, `UPDATE custom_fields SET name = ?, options = ?, updated_at = ? WHERE id = ?`,
+		field.Name, options, field.UpdatedAt, field.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update custom field: %w", err)
+	}
+	
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	
+	if rowsAffected == 0 {
+		return fmt.Errorf("custom field with ID %d not found", field.ID)
+	}
+	
+	if field.Type == CustomFieldEnum {
+		rows, err := tx.QueryContext(r.ctx, `SELECT DISTINCT value FROM task_custom_values WHERE field_id = ?`, field.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get custom field values: %w", err)
+		}
+		var stale []string
+		for rows.Next() {
+			var value string
+			if err := rows.Scan(&value); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan custom field value: %w", err)
+			}
+			if field.optionIndex(value) == len(field.Options) {
+				stale = append(stale, value)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to iterate custom field values: %w", err)
+		}
+		
+		for _, value := range stale {
+			if _, err := tx.ExecContext(r.ctx, `DELETE FROM task_custom_values WHERE field_id = ? AND value = ?`, field.ID, value); err != nil {
+				return fmt.Errorf("failed to remove custom field values: %w", err)
+			}
+		}
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit custom field update: %w", err)
+	}
+	
+	return nil
+}
+
+// DeleteCustomField deletes a field with its values
+func (r *SQLiteRepository) DeleteCustomField(id int) error {
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin custom field deletion: %w", err)
+	}
+	defer tx.Rollback()
+	
+	// Foreign keys are not enforced, so the values are removed explicitly
+	if _, err := tx.ExecContext(r.ctx, `DELETE FROM task_custom_values WHERE field_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete custom field values: %w", err)
+	}
+	
+	result, err := tx.ExecContext(r.ctx, `DELETE FROM custom_fields WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete custom field: %w", err)
+	}
+	
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	
+	if rowsAffected == 0 {
+		return fmt.Errorf("custom field with ID %d not found", id)
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit custom field deletion: %w", err)
+	}
+	
+	return nil
+}
+
+// GetTaskCustomValues returns the custom field values of a task, by field name
+func (r *SQLiteRepository) GetTaskCustomValues(taskID int) ([]CustomFieldValue, error) {
+	query := `
+	SELECT v.task_id, v.field_id, f.name, f.type, v.value, v.updated_at
+	FROM task_custom_values v
+	INNER JOIN custom_fields f ON v.field_id = f.id
+	WHERE v.task_id = ?
+	ORDER BY f.name ASC, f.id ASC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom field values: %w", err)
+	}
+	defer rows.Close()
+	
+	var values []CustomFieldValue
+	for rows.Next() {
+		var value CustomFieldValue
+		if err := rows.Scan(&value.TaskID, &value.FieldID, &value.Name, &value.Type, &value.Value, &value.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan custom field value: %w", err)
+		}
+		values = append(values, value)
+	}
+	
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate custom field values: %w", err)
+	}
+	
+	return values, nil
+}
+
+// SetTaskCustomValue sets the value of a custom field on a task, or removes
+// it when value is empty
+func (r *SQLiteRepository) SetTaskCustomValue(taskID, fieldID int, value string) error {
+	if value == "" {
+		if _, err := r.conn().ExecContext(r.ctx, `DELETE FROM task_custom_values WHERE task_id = ? AND field_id = ?`, taskID, fieldID); err != nil {
+			return fmt.Errorf("failed to remove custom field value: %w", err)
+		}
+		return nil
+	}
+	
+	query := `
+	INSERT INTO task_custom_values (task_id, field_id, value, updated_at)
+	VALUES (?, ?, ?, ?)
+	ON CONFLICT (task_id, field_id) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`
+	
+	if _, err := r.conn().ExecContext(r.ctx, query, taskID, fieldID, value, time.Now()); err != nil {
+		return fmt.Errorf("failed to set custom field value: %w", err)
+	}
+	
+	return nil
+}
+
+// Checklist operations
+
+// checklistItemColumns is the column list selected by every checklist query,
+// in the order expected by scanChecklistItem. Queries must alias the
+// task_checklist_items table as ci.
+const checklistItemColumns = `ci.id, ci.task_id, ci.text, ci.is_done, ci.position, ci.completed_at, ci.created_at, ci.updated_at`
+
+// scanChecklistItem scans a single row selected with checklistItemColumns
+func scanChecklistItem(row rowScanner) (*ChecklistItem, error) {
+	item := &ChecklistItem{}
+	err := row.Scan(
+		&item.ID,
+		&item.TaskID,
+		&item.Text,
+		&item.Done,
+		&item.Position,
+		&item.CompletedAt,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	
+	return item, nil
+}
+
+func (r *SQLiteRepository) CreateChecklistItem(item *ChecklistItem) error {
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin checklist item creation: %w", err)
+	}
+	defer tx.Rollback()
+	
+	if err := r.insertChecklistItem(tx, item); err != nil {
+		return err
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit checklist item creation: %w", err)
+	}
+	
+	return nil
+}
+
+// insertChecklistItem appends an item to the checklist of its task within tx
+func (r *SQLiteRepository) insertChecklistItem(tx *txn, item *ChecklistItem) error {
+	query := `SELECT COALESCE(MAX(position) + 1, 0) FROM task_checklist_items WHERE task_id = ?`
+	if err := tx.QueryRowContext(r.ctx, query, item.TaskID).Scan(&item.Position); err != nil {
+		return fmt.Errorf("failed to get checklist position: %w", err)
+	}
+	
+	query = `
+	INSERT INTO task_checklist_items (task_id, text, is_done, position, completed_at, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?)`
+	
+	now := time.Now()
+	if item.Done && item.CompletedAt == nil {
+		item.CompletedAt = &now
+	}
+	result, err := tx.ExecContext(r.ctx, query,
+		item.TaskID,
+		item.Text,
+		item.Done,
+		item.Position,
+		item.CompletedAt,
+		now,
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create checklist item: %w", err)
+	}
+	
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get checklist item ID: %w", err)
+	}
+	
+	item.ID = int(id)
+	item.CreatedAt = now
+	item.UpdatedAt = now
+	
+	return nil
+}
+
+func (r *SQLiteRepository) GetChecklistItem(id int) (*ChecklistItem, error) {
+	query := `
+	SELECT ` + checklistItemColumns + `
+	FROM task_checklist_items ci WHERE ci.id = ?`
+	
+	item, err := scanChecklistItem(r.conn().QueryRowContext(r.ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("checklist item with ID %d not found", id)
+		}
+		return nil, fmt.Errorf("failed to get checklist item: %w", err)
+	}
+	
+	return item, nil
+}
+
+// GetChecklistItems returns the checklist of a task, by position
+func (r *SQLiteRepository) GetChecklistItems(taskID int) ([]ChecklistItem, error) {
+	query := `
+	SELECT ` + checklistItemColumns + `
+	FROM task_checklist_items ci
+	WHERE ci.task_id = ?
+	ORDER BY ci.position ASC`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checklist items: %w", err)
+	}
+	defer rows.Close()
+	
+	var items []ChecklistItem
+	for rows.Next() {
+		item, err := scanChecklistItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan checklist item: %w", err)
+		}
+		items = append(items, *item)
+	}
+	
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate checklist items: %w", err)
+	}
+	
+	return items, nil
+}
+
+// UpdateChecklistItem updates the text and completion of an item and moves
+// it to its position, which is clamped to the checklist. CompletedAt is set
+// when the item becomes done and cleared when it is no longer done.
+func (r *SQLiteRepository) UpdateChecklistItem(item *ChecklistItem) error {
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin checklist item update: %w", err)
+	}
+	defer tx.Rollback()
+	
+	var oldPosition, count int
+	query := `
+	SELECT position, (SELECT COUNT(*) FROM task_checklist_items WHERE task_id = ci.task_id)
+	FROM task_checklist_items ci WHERE ci.id = ?`
+	if err := tx.QueryRowContext(r.ctx, query, item.ID).Scan(&oldPosition, &count); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("checklist item with ID %d not found", item.ID)
+		}
+		return fmt.Errorf("failed to get checklist item: %w", err)
+	}
+	
+	if item.Position < 0 {
+		item.Position = 0
+	}
+	if item.Position > count-1 {
+		item.Position = count - 1
+	}
+	
+	// Close the gap left by the item and open one where it goes
+	shift := `
+	UPDATE task_checklist_items SET position = position - 1
+	WHERE task_id = ? AND position > ? AND position <= ?`
+	args := []interface{}{item.TaskID, oldPosition, item.Position}
+	if item.Position < oldPosition {
+		shift = `
+		UPDATE task_checklist_items SET position = position + 1
+		WHERE task_id = ? AND position >= ? AND position < ?`
+		args = []interface{}{item.TaskID, item.Position, oldPosition}
+	}
+	if _, err := tx.ExecContext(r.ctx, shift, args...); err != nil {
+		return fmt.Errorf("failed to move checklist item: %w", err)
+	}
+	
+	item.UpdatedAt = time.Now()
+	if !item.Done {
+		item.CompletedAt = nil
+	} else if item.CompletedAt == nil {
+		item.CompletedAt = &item.UpdatedAt
+	}
+	
+	query = `
+	UPDATE task_checklist_items
+	SET text = ?, is_done = ?, position = ?, completed_at = ?, updated_at = ?
+	WHERE id = ?`
+	if _, err := tx.ExecContext(r.ctx, query,
+		item.Text,
+		item.Done,
+		item.Position,
+		item.CompletedAt,
+		item.UpdatedAt,
+		item.ID,
+	); err != nil {
+		return fmt.Errorf("failed to update checklist item: %w", err)
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit checklist item update: %w", err)
+	}
+	
+	return nil
+}
+
+// DeleteChecklistItem deletes an item and moves the items after it up
+func (r *SQLiteRepository) DeleteChecklistItem(id int) error {
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin checklist item deletion: %w", err)
+	}
+	defer tx.Rollback()
+	
+	var taskID, position int
+	query := `SELECT task_id, position FROM task_checklist_items WHERE id = ?`
+	if err := tx.QueryRowContext(r.ctx, query, id).Scan(&taskID, &position); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("checklist item with ID %d not found", id)
+		}
+		return fmt.Errorf("failed to get checklist item: %w", err)
+	}
+	
+	if _, err := tx.ExecContext(r.ctx, `DELETE FROM task_checklist_items WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete checklist item: %w", err)
+	}
+	
+	query = `
+	UPDATE task_checklist_items SET position = position - 1
+	WHERE task_id = ? AND position > ?`
+	if _, err := tx.ExecContext(r.ctx, query, taskID, position); err != nil {
+		return fmt.Errorf("failed to move checklist items: %w", err)
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit checklist item deletion: %w", err)
+	}
+	
+	return nil
+}
+
+// Template operations
+
+// templateColumns is the column list selected by every template query, in
+// the order expected by scanTemplate. Queries must alias the task_templates
+// table as tt.
+const templateColumns = `tt.id, tt.name, tt.description, tt.owner_id, tt.project_id, tt.tasks, tt.created_at, tt.updated_at`
+
+// scanTemplate scans a single row selected with templateColumns. The tasks
+// of a template are stored as a JSON list.
+func scanTemplate(row rowScanner) (*TaskTemplate, error) {
+	template := &TaskTemplate{}
+	var tasks string
+	err := row.Scan(
+		&template.ID,
+		&template.Name,
+		&template.Description,
+		&template.OwnerID,
+		&template.ProjectID,
+		&tasks,
+		&template.CreatedAt,
+		&template.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	
+	if err := json.Unmarshal([]byte(tasks), &template.Tasks); err != nil {
+		return nil, fmt.Errorf("invalid tasks of template %d: %w", template.ID, err)
+	}
+	
+	return template, nil
+}
+
+// templateTasks encodes the tasks of a template for storage
+func templateTasks(template *TaskTemplate) (string, error) {
+	tasks, err := json.Marshal(template.Tasks)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode template tasks: %w", err)
+	}
+	return string(tasks), nil
+}
+
+func (r *SQLiteRepository) CreateTemplate(template *TaskTemplate) error {
+	query := `
+	INSERT INTO task_templates (name, description, owner_id, project_id, tasks, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?)`
+	
+	tasks, err := templateTasks(template)
+	if err != nil {
+		return err
+	}
+	
+	now := time.Now()
+	result, err := r.conn().ExecContext(r.ctx, query,
+		template.Name,
+		template.Description,
+		template.OwnerID,
+		template.ProjectID,
+		tasks,
+		now,
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create template: %w", err)
+	}
+	
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get template ID: %w", err)
+	}
+	
+	template.ID = int(id)
+	template.CreatedAt = now
+	template.UpdatedAt = now
+	
+	return nil
+}
+
+func (r *SQLiteRepository) GetTemplate(id int) (*TaskTemplate, error) {
+	query := `
+	SELECT ` + templateColumns + `
+	FROM task_templates tt WHERE tt.id = ?`
+	
+	template, err := scanTemplate(r.conn().QueryRowContext(r.ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("template with ID %d not found", id)
+		}
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+	
+	return template, nil
+}
+
+// GetTemplates returns the templates of a project or, when projectID is nil,
+// the personal templates of the owner, by name
+func (r *SQLiteRepository) GetTemplates(ownerID int, projectID *int) ([]TaskTemplate, error) {
+	query := `
+	SELECT ` + templateColumns + `
+	FROM task_templates tt
+	WHERE tt.project_id IS NULL AND tt.owner_id = ?
+	ORDER BY tt.name COLLATE NOCASE ASC, tt.id ASC`
+	args := []interface{}{ownerID}
+	
+	if projectID != nil {
+		query = `
+		SELECT ` + templateColumns + `
+		FROM task_templates tt
+		WHERE tt.project_id = ?
+		ORDER BY tt.name COLLATE NOCASE ASC, tt.id ASC`
+		args = []interface{}{*projectID}
+	}
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get templates: %w", err)
+	}
+	defer rows.Close()
+	
+	var templates []TaskTemplate
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan template: %w", err)
+		}
+		templates = append(templates, *template)
+	}
+	
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate templates: %w", err)
+	}
+	
+	return templates, nil
+}
+
+func (r *SQLiteRepository) UpdateTemplate(template *TaskTemplate) error {
+	query := `
+	UPDATE task_templates
+	SET name = ?, description = ?, tasks = ?, updated_at = ?
+	WHERE id = ?`
+	
+	tasks, err := templateTasks(template)
+	if err != nil {
+		return err
+	}
+	
+	template.UpdatedAt = time.Now()
+	result, err := r.conn().ExecContext(r.ctx, query,
+		template.Name,
+		template.Description,
+		tasks,
+		template.UpdatedAt,
+		template.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update template: %w", err)
+	}
+	
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	
+	if rowsAffected == 0 {
+		return fmt.Errorf("template with ID %d not found", template.ID)
+	}
+	
+	return nil
+}
+
+func (r *SQLiteRepository) DeleteTemplate(id int) error {
+	result, err := r.conn().ExecContext(r.ctx, `DELETE FROM task_templates WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+	
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	
+	if rowsAffected == 0 {
+		return fmt.Errorf("template with ID %d not found", id)
+	}
+	
+	return nil
+}
+
+// CreateTasks creates tasks with their tags, checklists, parents and
+// dependencies. Either every task is created or none is.
+func (r *SQLiteRepository) CreateTasks(tasks []NewTask) error {
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin task creation: %w", err)
+	}
+	defer tx.Rollback()
+	
+	earlier := func(i int, index int) (*DatabaseTask, error) {
+		if index < 0 || index >= i {
+			return nil, fmt.Errorf("invalid task %d: it can only refer to earlier tasks", i)
+		}
+		return tasks[index].Task, nil
+	}
+	
+	for i, newTask := range tasks {
+		if newTask.Parent != nil {
+			parent, err := earlier(i, *newTask.Parent)
+			if err != nil {
+				return err
+			}
+			newTask.Task.ParentID = &parent.ID
+		}
+		if err := r.insertTask(tx, newTask.Task); err != nil {
+			return err
+		}
+		taskID := newTask.Task.ID
+		
+		for _, tagID := range newTask.TagIDs {
+			tagID := tagID
+			if _, err := tx.ExecContext(r.ctx, `INSERT INTO task_tags (task_id, tag_id) VALUES (?, ?)`, taskID, tagID); err != nil {
+				return fmt.Errorf("failed to add tag to task: %w", err)
+			}
+			if err := r.recordHistory(tx, taskID, historyChange{field: "tag_id", newValue: intValue(&tagID)}); err != nil {
+				return err
+			}
+		}
+		
+		for _, text := range newTask.Checklist {
+			if err := r.insertChecklistItem(tx, &ChecklistItem{TaskID: taskID, Text: text}); err != nil {
+				return err
+			}
+		}
+		
+		for _, index := range newTask.DependsOn {
+			dependency, err := earlier(i, index)
+			if err != nil {
+				return err
+			}
+			query := `INSERT INTO task_dependencies (task_id, depends_on_task_id) VALUES (?, ?)`
+			if _, err := tx.ExecContext(r.ctx, query, taskID, dependency.ID); err != nil {
+				return fmt.Errorf("failed to add task dependency: %w", err)
+			}
+			if err := r.recordHistory(tx, taskID, historyChange{field: "depends_on_task_id", newValue: intValue(&dependency.ID)}); err != nil {
+				return err
+			}
+		}
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit task creation: %w", err)
+	}
+	
+	now := time.Now()
+	for _, newTask := range tasks {
+		newTask.Task.CreatedAt = now
+		newTask.Task.UpdatedAt = now
+	}
+	
+	return nil
+}
+
+// Scoring weight operations
+
+// GetScoringWeights returns the scoring weights of a user, or nil when the
+// user has not set any
+func (r *SQLiteRepository) GetScoringWeights(userID int) (*ScoringWeights, error) {
+	query := `SELECT user_id, weights, updated_at FROM scoring_weights WHERE user_id = ?`
+	
+	weights := &ScoringWeights{}
+	var encoded string
+	err := r.conn().QueryRowContext(r.ctx, query, userID).Scan(&weights.UserID, &encoded, &weights.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get scoring weights: %w", err)
+	}
+	
+	if err := json.Unmarshal([]byte(encoded), &weights.Weights); err != nil {
+		return nil, fmt.Errorf("invalid scoring weights of user %d: %w", userID, err)
+	}
+	
+	return weights, nil
+}
+
+// SetScoringWeights stores the scoring weights of a user, replacing any
+// earlier ones
+func (r *SQLiteRepository) SetScoringWeights(weights *ScoringWeights) error {
+	encoded, err := json.Marshal(weights.Weights)
+	if err != nil {
+		return fmt.Errorf("failed to encode scoring weights: %w", err)
+	}
+	
+	query := `
+	INSERT INTO scoring_weights (user_id, weights, updated_at)
+	VALUES (?, ?, ?)
+	ON CONFLICT (user_id) DO UPDATE SET weights = excluded.weights, updated_at = excluded.updated_at`
+	
+	now := time.Now()
+	if _, err := r.conn().ExecContext(r.ctx, query, weights.UserID, string(encoded), now); err != nil {
+		return fmt.Errorf("failed to set scoring weights: %w", err)
+	}
+	
+	weights.UpdatedAt = now
+	return nil
+}
+
+// DeleteScoringWeights removes the scoring weights of a user, who goes back
+// to the defaults
+func (r *SQLiteRepository) DeleteScoringWeights(userID int) error {
+	if _, err := r.conn().ExecContext(r.ctx, `DELETE FROM scoring_weights WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to delete scoring weights: %w", err)
+	}
+	
+	return nil
+}
+
+// Task rank operations
+
+// GetTaskRanks returns the task positions of a list, ordered by key. Tasks in
+// the trash keep their position for when they are restored, but are left out.
+func (r *SQLiteRepository) GetTaskRanks(list string) ([]TaskRank, error) {
+	query := `
+	SELECT tr.list_key, tr.task_id, tr.rank_key
+	FROM task_ranks tr
+	JOIN tasks t ON t.id = tr.task_id
+	WHERE tr.list_key = ? AND t.deleted_at IS NULL
+	ORDER BY tr.rank_key`
+	
+	rows, err := r.conn().QueryContext(r.ctx, query, list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task ranks: %w", err)
+	}
+	defer rows.Close()
+	
+	var ranks []TaskRank
+	for rows.Next() {
+		var rank TaskRank
+		if err := rows.Scan(&rank.List, &rank.TaskID, &rank.Key); err != nil {
+			return nil, fmt.Errorf("failed to scan task rank: %w", err)
+		}
+		ranks = append(ranks, rank)
+	}
+	
+	return ranks, rows.Err()
+}
+
+// SetTaskRank stores the position of a task in a list, replacing its
+// earlier position there. A task in the trash that holds the same key loses
+// its position, as keys are placed between the tasks GetTaskRanks returns.
+func (r *SQLiteRepository) SetTaskRank(rank *TaskRank) error {
+	query := `
+	INSERT INTO task_ranks (list_key, task_id, rank_key)
+	VALUES (?, ?, ?)
+	ON CONFLICT (list_key, task_id) DO UPDATE SET rank_key = excluded.rank_key`
+	
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin task rank update: %w", err)
+	}
+	defer tx.Rollback()
+	
+	_, err = tx.ExecContext(r.ctx, `
+	DELETE FROM task_ranks
+	WHERE list_key = ? AND rank_key = ? AND task_id != ?
+	AND task_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL)`, rank.List, rank.Key, rank.TaskID)
+	if err != nil {
+		return fmt.Errorf("failed to set task rank: %w", err)
+	}
+	
+	if _, err := tx.ExecContext(r.ctx, query, rank.List, rank.TaskID, rank.Key); err != nil {
+		return fmt.Errorf("failed to set task rank: %w", err)
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit task rank update: %w", err)
+	}
+	
+	return nil
+}
+
+// ReplaceTaskRanks replaces all task positions of a list in one transaction
+func (r *SQLiteRepository) ReplaceTaskRanks(list string, ranks []TaskRank) error {
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin task rank update: %w", err)
+	}
+	defer tx.Rollback()
+	
+	if _, err := tx.ExecContext(r.ctx, `DELETE FROM task_ranks WHERE list_key = ?`, list); err != nil {
+		return fmt.Errorf("failed to clear task ranks: %w", err)
+	}
+	
+	query := `INSERT INTO task_ranks (list_key, task_id, rank_key) VALUES (?, ?, ?)`
+	for _, rank := range ranks {
+		if _, err := tx.ExecContext(r.ctx, query, list, rank.TaskID, rank.Key); err != nil {
+			return fmt.Errorf("failed to set task rank: %w", err)
+		}
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit task rank update: %w", err)
+	}
+	
+	return nil
+}
+
+// Command journal operations
+
+// CreateJournalCommand records a command at the end of a user's journal. The
+// commands the user had undone can no longer be redone and are dropped, and
+// only the latest limit commands are kept.
+func (r *SQLiteRepository) CreateJournalCommand(command *JournalCommand, limit int) error {
+	tx, err := r.begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin journal update: %w", err)
+	}
+	defer tx.Rollback()
+	
+	if _, err := tx.ExecContext(r.ctx, `DELETE FROM command_journal WHERE user_id IS ? AND undone = TRUE`, command.UserID); err != nil {
+		return fmt.Errorf("failed to drop undone commands: %w", err)
+	}
+	
+	query := `
+	INSERT INTO command_journal (user_id, kind, task_id, changes, undone, created_at)
+	VALUES (?, ?, ?, ?, FALSE, ?)`
+	
+	now := time.Now()
+	result, err := tx.ExecContext(r.ctx, query, command.UserID, command.Kind, command.TaskID, string(command.Changes), now)
+	if err != nil {
+		return fmt.Errorf("failed to create journal command: %w", err)
+	}
+	
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get journal command ID: %w", err)
+	}
+	
+	trim := `
+	DELETE FROM command_journal WHERE user_id IS ? AND id NOT IN (
+		SELECT id FROM command_journal WHERE user_id IS ? ORDER BY id DESC LIMIT ?
+	)`
+	if _, err := tx.ExecContext(r.ctx, trim, command.UserID, command.UserID, limit); err != nil {
+		return fmt.Errorf("failed to trim journal: %w", err)
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit journal update: %w", err)
+	}
+	
+	command.ID = int(id)
+	command.Undone = false
+	command.CreatedAt = now
+	return nil
+}
+
+// GetUndoableCommand returns the latest command of a user that has not been
+// undone, or nil when there is none
+func (r *SQLiteRepository) GetUndoableCommand(userID *int) (*JournalCommand, error) {
+	return r.getJournalCommand(`WHERE user_id IS ? AND undone = FALSE ORDER BY id DESC LIMIT 1`, userID)
+}
+
+// GetRedoableCommand returns the earliest command of a user that has been
+// undone, or nil when there is none
+func (r *SQLiteRepository) GetRedoableCommand(userID *int) (*JournalCommand, error) {
+	return r.getJournalCommand(`WHERE user_id IS ? AND undone = TRUE ORDER BY id ASC LIMIT 1`, userID)
+}
+
+func (r *SQLiteRepository) getJournalCommand(condition string, userID *int) (*JournalCommand, error) {
+	query := `SELECT id, user_id, kind, task_id, changes, undone, created_at FROM command_journal ` + condition
+	
+	command := &JournalCommand{}
+	var changes string
+	err := r.conn().QueryRowContext(r.ctx, query, userID).Scan(
+		&command.ID,
+		&command.UserID,
+		&command.Kind,
+		&command.TaskID,
+		&changes,
+		&command.Undone,
+		&command.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get journal command: %w", err)
+	}
+	
+	command.Changes = json.RawMessage(changes)
+	return command, nil
+}
+
+// SetJournalCommandUndone marks a command as undone or as done again
+func (r *SQLiteRepository) SetJournalCommandUndone(id int, undone bool) error {
+	result, err := r.conn().ExecContext(r.ctx, `UPDATE command_journal SET undone = ? WHERE id = ?`, undone, id)
+	if err != nil {
+		return fmt.Errorf("failed to update journal command: %w", err)
+	}
+	
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("journal command with ID %d not found", id)
+	}
+	
+	return nil
+}
+
 // User operations (Phase 2)
 
 func (r *SQLiteRepository) CreateUser(user *User) error {
@@ -1161,7 +4443,7 @@ func (r *SQLiteRepository) CreateUser(user *User) error {
 	INSERT INTO users (username, email, password, is_active)
 	VALUES (?, ?, ?, ?)`
 	
-	result, err := r.db.Exec(query,
+	result, err := r.conn().ExecContext(r.ctx, query,
 		user.Username,
 		user.Email,
 		user.Password,
@@ -1190,7 +4472,7 @@ func (r *SQLiteRepository) GetUser(id int) (*User, error) {
 	FROM users WHERE id = ?`
 	
 	user := &User{}
-	err := r.db.QueryRow(query, id).Scan(
+	err := r.conn().QueryRowContext(r.ctx, query, id).Scan(
 		&user.ID,
 		&user.Username,
 		&user.Email,
@@ -1216,7 +4498,7 @@ func (r *SQLiteRepository) GetUserByUsername(username string) (*User, error) {
 	FROM users WHERE username = ?`
 	
 	user := &User{}
-	err := r.db.QueryRow(query, username).Scan(
+	err := r.conn().QueryRowContext(r.ctx, query, username).Scan(
 		&user.ID,
 		&user.Username,
 		&user.Email,
@@ -1242,7 +4524,7 @@ func (r *SQLiteRepository) GetUserByEmail(email string) (*User, error) {
 	FROM users WHERE email = ?`
 	
 	user := &User{}
-	err := r.db.QueryRow(query, email).Scan(
+	err := r.conn().QueryRowContext(r.ctx, query, email).Scan(
 		&user.ID,
 		&user.Username,
 		&user.Email,
@@ -1268,7 +4550,7 @@ func (r *SQLiteRepository) UpdateUser(user *User) error {
 	SET username = ?, email = ?, password = ?, is_active = ?, updated_at = CURRENT_TIMESTAMP
 	WHERE id = ?`
 	
-	result, err := r.db.Exec(query,
+	result, err := r.conn().ExecContext(r.ctx, query,
 		user.Username,
 		user.Email,
 		user.Password,
@@ -1296,7 +4578,7 @@ func (r *SQLiteRepository) UpdateUser(user *User) error {
 func (r *SQLiteRepository) DeleteUser(id int) error {
 	query := `DELETE FROM users WHERE id = ?`
 	
-	result, err := r.db.Exec(query, id)
+	result, err := r.conn().ExecContext(r.ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
@@ -1319,7 +4601,7 @@ func (r *SQLiteRepository) GetAllUsers() ([]User, error) {
 	FROM users 
 	ORDER BY created_at DESC`
 	
-	rows, err := r.db.Query(query)
+	rows, err := r.conn().QueryContext(r.ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get users: %w", err)
 	}
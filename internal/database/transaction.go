@@ -0,0 +1,107 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// dbConn runs statements; it is implemented by both *sql.DB and *sql.Tx
+type dbConn interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// txn is a transaction started by a repository method. When the repository is
+// bound to an outer transaction the method joins it instead: Commit and
+// Rollback are then left to whoever started the outer transaction.
+type txn struct {
+	*sql.Tx
+	joined bool
+}
+
+// Commit commits the transaction unless it was joined
+func (t *txn) Commit() error {
+	if t.joined {
+		return nil
+	}
+	return t.Tx.Commit()
+}
+
+// Rollback rolls the transaction back unless it was joined
+func (t *txn) Rollback() error {
+	if t.joined {
+		return nil
+	}
+	return t.Tx.Rollback()
+}
+
+// conn returns the transaction the repository is bound to, or the database
+func (r *SQLiteRepository) conn() dbConn {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// begin starts a transaction, or joins the one the repository is bound to
+func (r *SQLiteRepository) begin() (*txn, error) {
+	if r.tx != nil {
+		return &txn{Tx: r.tx, joined: true}, nil
+	}
+
+	tx, err := r.db.BeginTx(r.ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &txn{Tx: tx}, nil
+}
+
+// Transaction runs fn with a repository whose operations all take part in
+// one transaction, which is committed when fn returns nil and rolled back
+// otherwise. Nested calls run fn within a savepoint of the outer transaction,
+// so that a failing fn only undoes its own changes.
+func (r *SQLiteRepository) Transaction(fn func(repository Repository) error) error {
+	if r.tx != nil {
+		return r.savepoint(fn)
+	}
+
+	tx, err := r.db.BeginTx(r.ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(&SQLiteRepository{db: r.db, ctx: r.ctx, tx: tx}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// savepoint runs fn within a savepoint of the transaction the repository is bound to
+func (r *SQLiteRepository) savepoint(fn func(repository Repository) error) error {
+	name := fmt.Sprintf("savepoint_%d", r.depth+1)
+	if _, err := r.tx.ExecContext(r.ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+
+	if err := fn(&SQLiteRepository{db: r.db, ctx: r.ctx, tx: r.tx, depth: r.depth + 1}); err != nil {
+		if _, rollbackErr := r.tx.ExecContext(r.ctx, "ROLLBACK TO "+name); rollbackErr != nil {
+			return fmt.Errorf("failed to roll back to savepoint: %w", rollbackErr)
+		}
+		if _, releaseErr := r.tx.ExecContext(r.ctx, "RELEASE "+name); releaseErr != nil {
+			return fmt.Errorf("failed to release savepoint: %w", releaseErr)
+		}
+		return err
+	}
+
+	if _, err := r.tx.ExecContext(r.ctx, "RELEASE "+name); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
+	return nil
+}
@@ -4,17 +4,21 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
+	"learn-go-capstone/internal/attachments"
 	"learn-go-capstone/internal/database"
 )
 
 // ExportService handles data export operations
 type ExportService struct {
 	repository database.Repository
+	store      *attachments.Store
 }
 
 // NewExportService creates a new export service
@@ -24,6 +28,12 @@ func NewExportService(repository database.Repository) *ExportService {
 	}
 }
 
+// SetAttachmentStore sets the store that attachment contents are read from.
+// Without a store, exports only describe the attachments.
+func (es *ExportService) SetAttachmentStore(store *attachments.Store) {
+	es.store = store
+}
+
 // ExportTasks exports tasks to a file
 func (es *ExportService) ExportTasks(options ExportOptions) (*ExportResult, error) {
 	// Get tasks based on filters
@@ -84,6 +94,9 @@ func (es *ExportService) getFilteredTasks(options ExportOptions) ([]database.Dat
 		if options.UserID != nil && (task.UserID == nil || *task.UserID != *options.UserID) {
 			continue
 		}
+		if options.ProjectID != nil && (task.ProjectID == nil || *task.ProjectID != *options.ProjectID) {
+			continue
+		}
 		if options.Status != nil && task.Status != *options.Status {
 			continue
 		}
@@ -116,6 +129,7 @@ func (es *ExportService) convertToExportData(tasks []database.DatabaseTask, opti
 	}
 
 	// Convert tasks
+	exportedLinks := make(map[string]bool)
 	for _, task := range tasks {
 		taskExport := TaskExport{
 			ID:          task.ID,
@@ -128,9 +142,14 @@ func (es *ExportService) convertToExportData(tasks []database.DatabaseTask, opti
 			DueDate:     task.DueDate,
 			UserID:      task.UserID,
 			CategoryID:  task.CategoryID,
+			ProjectID:   task.ProjectID,
 			IsArchived:  task.IsArchived,
 		}
 
+		if task.RecurrenceRule != nil {
+			taskExport.RecurrenceRule = *task.RecurrenceRule
+		}
+
 		// Add category if requested and available
 		if options.IncludeCategories && task.CategoryID != nil {
 			category, err := es.repository.GetCategory(*task.CategoryID)
@@ -168,12 +187,66 @@ func (es *ExportService) convertToExportData(tasks []database.DatabaseTask, opti
 			taskExport.Tags = []TagExport{}
 		}
 
+		// Add custom field values
+		values, err := es.repository.GetTaskCustomValues(task.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range values {
+			taskExport.CustomFields = append(taskExport.CustomFields, CustomValueExport{
+				FieldID: value.FieldID,
+				Name:    value.Name,
+				Value:   value.Value,
+			})
+		}
+
+		// Add the links of the task in both directions, once each
+		links, err := es.repository.GetTaskLinks(task.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, link := range links {
+			if link.Type == database.BlocksLinkType && link.LinkedTaskID == task.ID {
+				taskExport.Dependencies = append(taskExport.Dependencies, link.TaskID)
+			}
+			key := fmt.Sprintf("%d:%s:%d", link.TaskID, link.Type, link.LinkedTaskID)
+			if exportedLinks[key] {
+				continue
+			}
+			exportedLinks[key] = true
+			exportData.Links = append(exportData.Links, LinkExport{
+				TaskID:       link.TaskID,
+				LinkedTaskID: link.LinkedTaskID,
+				Type:         link.Type,
+				CreatedAt:    link.CreatedAt,
+			})
+		}
+
 		exportData.Tasks = append(exportData.Tasks, taskExport)
 	}
+	exportData.Metadata.TotalLinks = len(exportData.Links)
+
+	// Add the definitions of the custom fields in scope
+	fields, err := es.getCustomFields(options)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range fields {
+		exportData.CustomFields = append(exportData.CustomFields, CustomFieldExport{
+			ID:         field.ID,
+			Name:       field.Name,
+			Type:       field.Type,
+			Options:    field.Options,
+			CategoryID: field.CategoryID,
+			ProjectID:  field.ProjectID,
+			CreatedAt:  field.CreatedAt,
+		})
+	}
+	exportData.Metadata.TotalCustomFields = len(exportData.CustomFields)
 
 	// Add categories if requested
 	if options.IncludeCategories {
-		categories, err := es.repository.GetAllCategories()
+		categories, err := es.getCategories(options)
 		if err == nil {
 			exportData.Categories = make([]CategoryExport, 0, len(categories))
 			for _, cat := range categories {
@@ -192,7 +265,7 @@ func (es *ExportService) convertToExportData(tasks []database.DatabaseTask, opti
 
 	// Add tags if requested
 	if options.IncludeTags {
-		tags, err := es.repository.GetAllTags()
+		tags, err := es.getTags(options)
 		if err == nil {
 			exportData.Tags = make([]TagExport, 0, len(tags))
 			for _, tag := range tags {
@@ -207,6 +280,13 @@ func (es *ExportService) convertToExportData(tasks []database.DatabaseTask, opti
 		}
 	}
 
+	// Add attachments if requested
+	if options.IncludeAttachments {
+		if err := es.addAttachments(exportData, tasks); err != nil {
+			return nil, err
+		}
+	}
+
 	// Add users if requested
 	if options.IncludeUsers {
 		users, err := es.repository.GetAllUsers()
@@ -229,6 +309,99 @@ func (es *ExportService) convertToExportData(tasks []database.DatabaseTask, opti
 	return exportData, nil
 }
 
+// getCategories returns the categories of the exported project, or every
+// category when the export is not limited to a project
+func (es *ExportService) getCategories(options ExportOptions) ([]database.Category, error) {
+	if options.ProjectID != nil {
+		return es.repository.GetCategoriesByProject(options.ProjectID)
+	}
+	return es.repository.GetAllCategories()
+}
+
+// getTags returns the tags of the exported project, or every tag when the
+// export is not limited to a project
+func (es *ExportService) getTags(options ExportOptions) ([]database.Tag, error) {
+	if options.ProjectID != nil {
+		return es.repository.GetTagsByProject(options.ProjectID)
+	}
+	return es.repository.GetAllTags()
+}
+
+// getCustomFields returns the custom fields of the exported project and its
+// categories, or every custom field when the export is not limited to a project
+func (es *ExportService) getCustomFields(options ExportOptions) ([]database.CustomField, error) {
+	fields, err := es.repository.GetAllCustomFields()
+	if err != nil || options.ProjectID == nil {
+		return fields, err
+	}
+
+	categories, err := es.repository.GetCategoriesByProject(options.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	inProject := make(map[int]bool, len(categories))
+	for _, category := range categories {
+		inProject[category.ID] = true
+	}
+
+	var scoped []database.CustomField
+	for _, field := range fields {
+		if (field.ProjectID != nil && *field.ProjectID == *options.ProjectID) ||
+			(field.CategoryID != nil && inProject[*field.CategoryID]) {
+			scoped = append(scoped, field)
+		}
+	}
+	return scoped, nil
+}
+
+// addAttachments adds the attachments of the exported tasks, reading their
+// contents from the attachment store when one is set
+func (es *ExportService) addAttachments(exportData *ExportData, tasks []database.DatabaseTask) error {
+	for _, task := range tasks {
+		taskAttachments, err := es.repository.GetTaskAttachments(task.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, attachment := range taskAttachments {
+			attachmentExport := AttachmentExport{
+				ID:          attachment.ID,
+				TaskID:      attachment.TaskID,
+				UserID:      attachment.UserID,
+				FileName:    attachment.FileName,
+				ContentType: attachment.ContentType,
+				Size:        attachment.Size,
+				SHA256:      attachment.SHA256,
+				CreatedAt:   attachment.CreatedAt,
+			}
+
+			if es.store != nil {
+				content, err := es.readAttachment(attachment.SHA256)
+				if err != nil {
+					return fmt.Errorf("failed to export attachment %d: %w", attachment.ID, err)
+				}
+				attachmentExport.Content = content
+			}
+
+			exportData.Attachments = append(exportData.Attachments, attachmentExport)
+		}
+	}
+
+	exportData.Metadata.TotalAttachments = len(exportData.Attachments)
+	return nil
+}
+
+// readAttachment reads the stored content with the given digest
+func (es *ExportService) readAttachment(sum string) ([]byte, error) {
+	file, err := es.store.Open(sum)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(file)
+}
+
 // generateFileName generates a filename for the export
 func (es *ExportService) generateFileName(format ExportFormat) string {
 	timestamp := time.Now().Format("20060102_150405")
@@ -288,12 +461,22 @@ func (es *ExportService) exportToCSV(data *ExportData, fileName string) (string,
 	// Write header
 	header := []string{
 		"id", "title", "description", "priority", "status", "created_at", "updated_at",
-		"due_date", "user_id", "category_id", "is_archived",
+		"due_date", "user_id", "category_id", "is_archived", "recurrence_rule",
+		"links",
+	}
+	for _, field := range data.CustomFields {
+		header = append(header, customFieldColumn(field.ID, field.Name))
 	}
 	if err := writer.Write(header); err != nil {
 		return "", 0, fmt.Errorf("failed to write header: %w", err)
 	}
 
+	// Links are written on the task they start at, as type:linked_id pairs
+	links := make(map[int][]string)
+	for _, link := range data.Links {
+		links[link.TaskID] = append(links[link.TaskID], fmt.Sprintf("%s:%d", link.Type, link.LinkedTaskID))
+	}
+
 	// Write data rows
 	for _, task := range data.Tasks {
 		row := []string{
@@ -330,6 +513,21 @@ func (es *ExportService) exportToCSV(data *ExportData, fileName string) (string,
 		// Add archived status
 		row = append(row, strconv.FormatBool(task.IsArchived))
 
+		// Add recurrence rule
+		row = append(row, task.RecurrenceRule)
+
+		// Add links
+		row = append(row, strings.Join(links[task.ID], ";"))
+
+		// Add one column per custom field
+		values := make(map[int]string, len(task.CustomFields))
+		for _, value := range task.CustomFields {
+			values[value.FieldID] = value.Value
+		}
+		for _, field := range data.CustomFields {
+			row = append(row, values[field.ID])
+		}
+
 		if err := writer.Write(row); err != nil {
 			file.Close()
 			return "", 0, fmt.Errorf("failed to write row: %w", err)
@@ -349,6 +547,11 @@ func (es *ExportService) exportToCSV(data *ExportData, fileName string) (string,
 	return filePath, fileInfo.Size(), nil
 }
 
+// customFieldColumn returns the CSV column name of a custom field
+func customFieldColumn(id int, name string) string {
+	return fmt.Sprintf("%s%d:%s", customFieldColumnPrefix, id, name)
+}
+
 // GetExportHistory returns a list of export files
 func (es *ExportService) GetExportHistory() ([]ExportResult, error) {
 	// This would typically read from a database or file system
@@ -3,6 +3,8 @@ package export
 import (
 	"encoding/json"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -399,3 +401,263 @@ func TestExportOptions(t *testing.T) {
 		t.Error("Expected default to not be dry run")
 	}
 }
+
+func TestRecurrenceRuleRoundTrip(t *testing.T) {
+	openRepository := func(name string) database.Repository {
+		db, err := database.Connect(&database.Config{
+			Driver: "sqlite3",
+			DSN:    "file:" + name + "?mode=memory&cache=shared",
+		})
+		if err != nil {
+			t.Fatalf("Failed to connect to database: %v", err)
+		}
+		t.Cleanup(func() { database.Close(db) })
+		
+		if err := database.NewMigrationManager(db).Migrate(); err != nil {
+			t.Fatalf("Failed to run migrations: %v", err)
+		}
+		return database.NewSQLiteRepository(db)
+	}
+	
+	source := openRepository("recurrence_source")
+	rule := "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=5"
+	err := source.CreateTask(&database.DatabaseTask{
+		Title:          "Water the plants",
+		Priority:       2,
+		RecurrenceRule: &rule,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	
+	exportService := NewExportService(source)
+	for _, format := range []ExportFormat{FormatJSON, FormatCSV} {
+		result, err := exportService.ExportTasks(ExportOptions{Format: format})
+		if err != nil {
+			t.Fatalf("Failed to export %s: %v", format, err)
+		}
+		defer os.Remove(result.FilePath)
+		
+		target := openRepository("recurrence_target_" + string(format))
+		importResult, err := NewImportService(target).ImportTasks(result.FilePath, ImportOptions{
+			Format:       format,
+			ValidateData: true,
+		})
+		if err != nil {
+			t.Fatalf("Failed to import %s: %v", format, err)
+		}
+		if importResult.Imported != 1 {
+			t.Fatalf("Expected 1 imported %s task, got %d (%v)", format, importResult.Imported, importResult.ErrorDetails)
+		}
+		
+		tasks, err := target.GetAllTasks()
+		if err != nil {
+			t.Fatalf("Failed to get imported tasks: %v", err)
+		}
+		if len(tasks) != 1 || tasks[0].RecurrenceRule == nil || *tasks[0].RecurrenceRule != rule {
+			t.Errorf("Expected %s import to keep recurrence rule %q, got %+v", format, rule, tasks)
+		}
+	}
+}
+
+func TestCustomFieldRoundTrip(t *testing.T) {
+	openRepository := func(name string) database.Repository {
+		db, err := database.Connect(&database.Config{
+			Driver: "sqlite3",
+			DSN:    "file:" + name + "?mode=memory&cache=shared",
+		})
+		if err != nil {
+			t.Fatalf("Failed to connect to database: %v", err)
+		}
+		t.Cleanup(func() { database.Close(db) })
+		
+		if err := database.NewMigrationManager(db).Migrate(); err != nil {
+			t.Fatalf("Failed to run migrations: %v", err)
+		}
+		return database.NewSQLiteRepository(db)
+	}
+	
+	source := openRepository("custom_fields_source")
+	user := &database.User{Username: "reviewer", Email: "reviewer@example.com", Password: "password123", IsActive: true}
+	if err := source.CreateUser(user); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	category := &database.Category{Name: "Bugs"}
+	if err := source.CreateCategory(category); err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	severity := &database.CustomField{Name: "Severity", Type: database.CustomFieldEnum, Options: []string{"low", "high"}, CategoryID: &category.ID}
+	reviewer := &database.CustomField{Name: "Reviewer", Type: database.CustomFieldUser, CategoryID: &category.ID}
+	for _, field := range []*database.CustomField{severity, reviewer} {
+		if err := source.CreateCustomField(field); err != nil {
+			t.Fatalf("Failed to create custom field: %v", err)
+		}
+	}
+	task := &database.DatabaseTask{Title: "Crash on start", Priority: 3, CategoryID: &category.ID}
+	if err := source.CreateTask(task); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if err := source.SetTaskCustomValue(task.ID, severity.ID, "high"); err != nil {
+		t.Fatalf("Failed to set custom value: %v", err)
+	}
+	if err := source.SetTaskCustomValue(task.ID, reviewer.ID, strconv.Itoa(user.ID)); err != nil {
+		t.Fatalf("Failed to set custom value: %v", err)
+	}
+	
+	exportService := NewExportService(source)
+	
+	// JSON recreates the fields of imported categories and maps user values
+	result, err := exportService.ExportTasks(ExportOptions{Format: FormatJSON, IncludeCategories: true, IncludeUsers: true})
+	if err != nil {
+		t.Fatalf("Failed to export JSON: %v", err)
+	}
+	defer os.Remove(result.FilePath)
+	
+	target := openRepository("custom_fields_target")
+	// Shift the IDs of imported users so that unmapped user values would show
+	if err := target.CreateUser(&database.User{Username: "someone", Email: "someone@example.com", Password: "password123"}); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	importResult, err := NewImportService(target).ImportTasks(result.FilePath, ImportOptions{Format: FormatJSON, ValidateData: true})
+	if err != nil {
+		t.Fatalf("Failed to import JSON: %v", err)
+	}
+	if importResult.Imported != 1 || importResult.Errors != 0 {
+		t.Fatalf("Expected 1 imported task without errors, got %+v", importResult)
+	}
+	
+	tasks, err := target.GetAllTasks()
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("Failed to get imported tasks: %v (%+v)", err, tasks)
+	}
+	imported, err := target.GetUserByUsername("reviewer")
+	if err != nil {
+		t.Fatalf("Failed to get imported user: %v", err)
+	}
+	values, err := target.GetTaskCustomValues(tasks[0].ID)
+	if err != nil {
+		t.Fatalf("Failed to get custom values: %v", err)
+	}
+	want := map[string]string{"Severity": "high", "Reviewer": strconv.Itoa(imported.ID)}
+	if len(values) != len(want) {
+		t.Fatalf("Expected %d imported values, got %+v", len(want), values)
+	}
+	for _, value := range values {
+		if want[value.Name] != value.Value {
+			t.Errorf("Expected imported %s to be %q, got %q", value.Name, want[value.Name], value.Value)
+		}
+	}
+	
+	// CSV has a column per field and imports into the existing fields
+	result, err = exportService.ExportTasks(ExportOptions{Format: FormatCSV})
+	if err != nil {
+		t.Fatalf("Failed to export CSV: %v", err)
+	}
+	defer os.Remove(result.FilePath)
+	
+	content, err := os.ReadFile(result.FilePath)
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %v", err)
+	}
+	column := customFieldColumn(severity.ID, severity.Name)
+	if !strings.Contains(string(content), column) {
+		t.Errorf("Expected CSV header to contain %q, got %s", column, content)
+	}
+	
+	importResult, err = NewImportService(source).ImportTasks(result.FilePath, ImportOptions{Format: FormatCSV, ValidateData: true})
+	if err != nil {
+		t.Fatalf("Failed to import CSV: %v", err)
+	}
+	if importResult.Imported != 1 || importResult.Errors != 0 {
+		t.Fatalf("Expected 1 imported task without errors, got %+v", importResult)
+	}
+	tasks, err = source.GetAllTasks()
+	if err != nil || len(tasks) != 2 {
+		t.Fatalf("Expected the CSV task next to the original: %v (%+v)", err, tasks)
+	}
+	for _, task := range tasks {
+		if values, _ := source.GetTaskCustomValues(task.ID); len(values) != 2 {
+			t.Errorf("Expected task %d to have both custom values, got %+v", task.ID, values)
+		}
+	}
+}
+
+func TestExportTaskLinks(t *testing.T) {
+	db, err := database.Connect(&database.Config{
+		Driver: "sqlite3",
+		DSN:    "file:export_links?mode=memory&cache=shared",
+	})
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.Close(db)
+	
+	if err := database.NewMigrationManager(db).Migrate(); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+	repository := database.NewSQLiteRepository(db)
+	
+	var ids []int
+	for _, title := range []string{"Design", "Build", "Build again"} {
+		task := &database.DatabaseTask{Title: title, Priority: 2}
+		if err := repository.CreateTask(task); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		ids = append(ids, task.ID)
+	}
+	// Design blocks Build, and Build again duplicates Build
+	if err := repository.AddTaskDependency(ids[1], ids[0]); err != nil {
+		t.Fatalf("Failed to add dependency: %v", err)
+	}
+	if err := repository.AddTaskLink(&database.TaskLink{TaskID: ids[2], LinkedTaskID: ids[1], Type: "duplicates"}); err != nil {
+		t.Fatalf("Failed to add link: %v", err)
+	}
+	
+	result, err := NewExportService(repository).ExportTasks(ExportOptions{Format: FormatJSON})
+	if err != nil {
+		t.Fatalf("Failed to export JSON: %v", err)
+	}
+	defer os.Remove(result.FilePath)
+	
+	content, err := os.ReadFile(result.FilePath)
+	if err != nil {
+		t.Fatalf("Failed to read JSON: %v", err)
+	}
+	var data ExportData
+	if err := json.Unmarshal(content, &data); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if data.Metadata.TotalLinks != 2 || len(data.Links) != 2 {
+		t.Fatalf("Expected each link to be exported once, got %+v", data.Links)
+	}
+	want := map[string]bool{
+		strconv.Itoa(ids[0]) + " blocks " + strconv.Itoa(ids[1]):     true,
+		strconv.Itoa(ids[2]) + " duplicates " + strconv.Itoa(ids[1]): true,
+	}
+	for _, link := range data.Links {
+		if !want[strconv.Itoa(link.TaskID)+" "+link.Type+" "+strconv.Itoa(link.LinkedTaskID)] {
+			t.Errorf("Unexpected link %+v", link)
+		}
+	}
+	for _, task := range data.Tasks {
+		if task.ID == ids[1] && (len(task.Dependencies) != 1 || task.Dependencies[0] != ids[0]) {
+			t.Errorf("Expected task %d to depend on task %d, got %v", task.ID, ids[0], task.Dependencies)
+		}
+	}
+	
+	result, err = NewExportService(repository).ExportTasks(ExportOptions{Format: FormatCSV})
+	if err != nil {
+		t.Fatalf("Failed to export CSV: %v", err)
+	}
+	defer os.Remove(result.FilePath)
+	
+	content, err = os.ReadFile(result.FilePath)
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %v", err)
+	}
+	for _, cell := range []string{"blocks:" + strconv.Itoa(ids[1]), "duplicates:" + strconv.Itoa(ids[1])} {
+		if !strings.Contains(string(content), cell) {
+			t.Errorf("Expected CSV to contain %q, got %s", cell, content)
+		}
+	}
+}
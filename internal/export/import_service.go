@@ -169,6 +169,38 @@ func (is *ImportService) importFromJSON(file io.Reader, options ImportOptions) (
 		}
 	}
 
+	// Import the custom fields of imported categories. Tasks are imported
+	// outside any project, so project fields are left out.
+	fieldMap := make(map[int]*database.CustomField) // old ID -> new field
+	for _, fieldExport := range exportData.CustomFields {
+		if fieldExport.CategoryID == nil {
+			continue
+		}
+		newCategoryID, exists := categoryMap[*fieldExport.CategoryID]
+		if !exists {
+			continue
+		}
+
+		field := &database.CustomField{
+			Name:       fieldExport.Name,
+			Type:       fieldExport.Type,
+			Options:    fieldExport.Options,
+			CategoryID: &newCategoryID,
+		}
+		if err := is.repository.CreateCustomField(field); err != nil {
+			result.ErrorDetails = append(result.ErrorDetails, ImportError{
+				Row:     0,
+				Field:   "custom_field",
+				Value:   fieldExport.Name,
+				Message: fmt.Sprintf("failed to create custom field: %v", err),
+			})
+			result.Errors++
+			continue
+		}
+
+		fieldMap[fieldExport.ID] = field
+	}
+
 	// Import tasks
 	for i, taskExport := range exportData.Tasks {
 		// Validate task data
@@ -203,6 +235,11 @@ func (is *ImportService) importFromJSON(file io.Reader, options ImportOptions) (
 			IsArchived:  taskExport.IsArchived,
 		}
 
+		if taskExport.RecurrenceRule != "" {
+			recurrenceRule := taskExport.RecurrenceRule
+			task.RecurrenceRule = &recurrenceRule
+		}
+
 		// Map category ID
 		if taskExport.CategoryID != nil {
 			if newCategoryID, exists := categoryMap[*taskExport.CategoryID]; exists {
@@ -247,6 +284,41 @@ func (is *ImportService) importFromJSON(file io.Reader, options ImportOptions) (
 			}
 		}
 
+		// Import custom field values of imported fields
+		for _, valueExport := range taskExport.CustomFields {
+			field, exists := fieldMap[valueExport.FieldID]
+			if !exists {
+				continue
+			}
+
+			value := valueExport.Value
+			if field.Type == database.CustomFieldUser {
+				oldUserID, _ := strconv.Atoi(value)
+				newUserID, mapped := userMap[oldUserID]
+				if !mapped {
+					result.ErrorDetails = append(result.ErrorDetails, ImportError{
+						Row:     i + 1,
+						Field:   "custom_field",
+						Value:   value,
+						Message: fmt.Sprintf("custom field %q names a user that was not imported", field.Name),
+					})
+					result.Errors++
+					continue
+				}
+				value = strconv.Itoa(newUserID)
+			}
+
+			if err := is.importCustomValue(task, field, value); err != nil {
+				result.ErrorDetails = append(result.ErrorDetails, ImportError{
+					Row:     i + 1,
+					Field:   "custom_field",
+					Value:   valueExport.Value,
+					Message: fmt.Sprintf("failed to set custom field: %v", err),
+				})
+				result.Errors++
+			}
+		}
+
 		result.Imported++
 	}
 
@@ -273,6 +345,14 @@ func (is *ImportService) importFromCSV(file io.Reader, options ImportOptions) (*
 		return nil, fmt.Errorf("invalid CSV header: expected at least %d columns, got %d", len(expectedHeader), len(header))
 	}
 
+	// Custom field columns refer to fields that already exist
+	customColumns := make(map[int]int) // column index -> field ID
+	for i, column := range header {
+		if fieldID, ok := parseCustomFieldColumn(column); ok {
+			customColumns[i] = fieldID
+		}
+	}
+
 	result := &ImportResult{
 		TotalRecords: 0,
 		Imported:     0,
@@ -337,6 +417,27 @@ func (is *ImportService) importFromCSV(file io.Reader, options ImportOptions) (*
 			continue
 		}
 
+		// Set custom field values
+		for column, fieldID := range customColumns {
+			if column >= len(record) || strings.TrimSpace(record[column]) == "" {
+				continue
+			}
+
+			field, err := is.repository.GetCustomField(fieldID)
+			if err == nil {
+				err = is.importCustomValue(task, field, record[column])
+			}
+			if err != nil {
+				result.ErrorDetails = append(result.ErrorDetails, ImportError{
+					Row:     rowNum,
+					Field:   header[column],
+					Value:   record[column],
+					Message: fmt.Sprintf("failed to set custom field: %v", err),
+				})
+				result.Errors++
+			}
+		}
+
 		result.Imported++
 		rowNum++
 	}
@@ -473,9 +574,54 @@ func (is *ImportService) parseTaskFromCSV(record []string, rowNum int) (*databas
 	}
 	task.IsArchived = isArchived
 
+	// Parse recurrence_rule (optional, absent in files exported before it existed)
+	if len(record) > 11 && record[11] != "" {
+		recurrenceRule := record[11]
+		task.RecurrenceRule = &recurrenceRule
+	}
+
 	return task, nil
 }
 
+// parseCustomFieldColumn returns the field ID of a custom_field:<id>:<name>
+// CSV column
+func parseCustomFieldColumn(column string) (int, bool) {
+	if !strings.HasPrefix(column, customFieldColumnPrefix) {
+		return 0, false
+	}
+
+	id := strings.TrimPrefix(column, customFieldColumnPrefix)
+	if i := strings.Index(id, ":"); i >= 0 {
+		id = id[:i]
+	}
+	fieldID, err := strconv.Atoi(id)
+	if err != nil || fieldID <= 0 {
+		return 0, false
+	}
+	return fieldID, true
+}
+
+// importCustomValue checks a custom field value of an imported task and
+// stores it in normalized form
+func (is *ImportService) importCustomValue(task *database.DatabaseTask, field *database.CustomField, value string) error {
+	if !field.AppliesTo(task) {
+		return fmt.Errorf("custom field %q does not apply to the task", field.Name)
+	}
+
+	normalized, err := field.NormalizeValue(value)
+	if err != nil {
+		return err
+	}
+	if field.Type == database.CustomFieldUser {
+		userID, _ := strconv.Atoi(normalized)
+		if _, err := is.repository.GetUser(userID); err != nil {
+			return fmt.Errorf("invalid value %q for custom field %q: no such user", value, field.Name)
+		}
+	}
+
+	return is.repository.SetTaskCustomValue(task.ID, field.ID, normalized)
+}
+
 // validateTaskExport validates a task export
 func (is *ImportService) validateTaskExport(task TaskExport) error {
 	if task.Title == "" {
@@ -487,6 +633,9 @@ func (is *ImportService) validateTaskExport(task TaskExport) error {
 	if task.Status < 0 || task.Status > 3 {
 		return fmt.Errorf("status must be between 0 and 3")
 	}
+	if err := validateRecurrenceRule(task.RecurrenceRule); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -501,5 +650,19 @@ func (is *ImportService) validateTask(task *database.DatabaseTask) error {
 	if task.Status < 0 || task.Status > 3 {
 		return fmt.Errorf("status must be between 0 and 3")
 	}
+	if task.RecurrenceRule != nil {
+		if err := validateRecurrenceRule(*task.RecurrenceRule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateRecurrenceRule performs a basic sanity check of an RRULE string.
+// Full parsing happens in the task package when the task is loaded.
+func validateRecurrenceRule(rule string) error {
+	if rule != "" && !strings.Contains(strings.ToUpper(rule), "FREQ=") {
+		return fmt.Errorf("recurrence rule must contain FREQ")
+	}
 	return nil
 }
@@ -18,9 +18,15 @@ type ExportOptions struct {
 	IncludeTags bool         `json:"include_tags"`
 	IncludeCategories bool   `json:"include_categories"`
 	IncludeUsers bool        `json:"include_users"`
+	// IncludeAttachments adds the attachments of the exported tasks, with
+	// their contents, to JSON exports
+	IncludeAttachments bool  `json:"include_attachments"`
 	DateFrom    *time.Time   `json:"date_from,omitempty"`
 	DateTo      *time.Time   `json:"date_to,omitempty"`
 	UserID      *int         `json:"user_id,omitempty"`
+	// ProjectID limits the export to one project, whose categories and tags
+	// replace the global ones
+	ProjectID   *int         `json:"project_id,omitempty"`
 	Status      *int         `json:"status,omitempty"`
 	Priority    *int         `json:"priority,omitempty"`
 }
@@ -78,13 +84,40 @@ type TaskExport struct {
 	DueDate     *time.Time          `json:"due_date,omitempty" csv:"due_date"`
 	UserID      *int                `json:"user_id,omitempty" csv:"user_id"`
 	CategoryID  *int                `json:"category_id,omitempty" csv:"category_id"`
+	ProjectID   *int                `json:"project_id,omitempty" csv:"-"`
 	IsArchived  bool                `json:"is_archived" csv:"is_archived"`
 	Category    *CategoryExport     `json:"category,omitempty" csv:"-"`
 	Tags        []TagExport         `json:"tags,omitempty" csv:"-"`
 	User        *UserExport         `json:"user,omitempty" csv:"-"`
+	// Dependencies are the IDs of the tasks that block this one
 	Dependencies []int              `json:"dependencies,omitempty" csv:"dependencies"`
+	RecurrenceRule string           `json:"recurrence_rule,omitempty" csv:"recurrence_rule"`
+	// CustomFields are written to CSV as one custom_field:<id>:<name> column per field
+	CustomFields []CustomValueExport `json:"custom_fields,omitempty" csv:"-"`
 }
 
+// CustomFieldExport represents a custom field definition in export format
+type CustomFieldExport struct {
+	ID         int       `json:"id"`
+	Name       string    `json:"name"`
+	Type       string    `json:"type"`
+	Options    []string  `json:"options,omitempty"`
+	CategoryID *int      `json:"category_id,omitempty"`
+	ProjectID  *int      `json:"project_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CustomValueExport represents the value of a custom field on a task
+type CustomValueExport struct {
+	FieldID int    `json:"field_id"`
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+}
+
+// customFieldColumnPrefix starts the CSV column of a custom field, which is
+// followed by the field ID and name: custom_field:<id>:<name>
+const customFieldColumnPrefix = "custom_field:"
+
 // CategoryExport represents a category in export format
 type CategoryExport struct {
 	ID          int       `json:"id" csv:"id"`
@@ -113,6 +146,29 @@ type UserExport struct {
 	UpdatedAt time.Time `json:"updated_at" csv:"updated_at"`
 }
 
+// AttachmentExport represents a task attachment in export format. Content is
+// encoded as base64 in JSON.
+type AttachmentExport struct {
+	ID          int       `json:"id"`
+	TaskID      int       `json:"task_id"`
+	UserID      *int      `json:"user_id,omitempty"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	SHA256      string    `json:"sha256"`
+	CreatedAt   time.Time `json:"created_at"`
+	Content     []byte    `json:"content,omitempty"`
+}
+
+// LinkExport represents a link between two tasks in export format. Type is
+// the link as seen from TaskID: blocks, relates_to, duplicates or clones.
+type LinkExport struct {
+	TaskID       int       `json:"task_id"`
+	LinkedTaskID int       `json:"linked_task_id"`
+	Type         string    `json:"type"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
 // ExportData represents the complete export data structure
 type ExportData struct {
 	Version     string           `json:"version"`
@@ -121,6 +177,9 @@ type ExportData struct {
 	Categories  []CategoryExport `json:"categories,omitempty"`
 	Tags        []TagExport      `json:"tags,omitempty"`
 	Users       []UserExport     `json:"users,omitempty"`
+	Attachments []AttachmentExport `json:"attachments,omitempty"`
+	CustomFields []CustomFieldExport `json:"custom_fields,omitempty"`
+	Links       []LinkExport     `json:"links,omitempty"`
 	Metadata    ExportMetadata   `json:"metadata"`
 }
 
@@ -130,6 +189,9 @@ type ExportMetadata struct {
 	TotalCategories int `json:"total_categories"`
 	TotalTags      int `json:"total_tags"`
 	TotalUsers     int `json:"total_users"`
+	TotalAttachments int `json:"total_attachments"`
+	TotalCustomFields int `json:"total_custom_fields"`
+	TotalLinks     int `json:"total_links"`
 	ExportOptions  ExportOptions `json:"export_options"`
 }
 
@@ -46,7 +46,9 @@ const (
 	TriggerStatusChange NotificationTrigger = "status_change"
 	TriggerCreated      NotificationTrigger = "created"
 	TriggerUpdated      NotificationTrigger = "updated"
+	TriggerMention      NotificationTrigger = "mention"
 	TriggerCustom       NotificationTrigger = "custom"
+	TriggerSnoozeEnded  NotificationTrigger = "snooze_ended"
 )
 
 // Notification represents a notification in the system
@@ -86,7 +86,7 @@ func (s *Scheduler) processScheduledNotifications() {
 
 // checkOverdueTasks checks for overdue tasks and creates reminders
 func (s *Scheduler) checkOverdueTasks() {
-	// Get all overdue tasks
+	// Get all overdue tasks; snoozed tasks are left out until they wake up
 	overdueTasks, err := s.repository.GetOverdueTasks()
 	if err != nil {
 		log.Printf("Error getting overdue tasks: %v", err)
@@ -136,10 +136,10 @@ func (s *Scheduler) checkDueSoonTasks() {
 	now := time.Now()
 	reminderTime := now.Add(60 * time.Minute) // 1 hour from now
 	
-	// Find tasks due within the next hour
+	// Find tasks due within the next hour; snoozed tasks stay quiet
 	dueSoonCount := 0
 	for _, task := range allTasks {
-		if task.DueDate == nil || task.UserID == nil {
+		if task.DueDate == nil || task.UserID == nil || (task.HiddenUntil != nil && task.HiddenUntil.After(now)) {
 			continue
 		}
 		
@@ -0,0 +1,667 @@
+// Package quickadd parses one-line task entries such as
+// "Pay rent tomorrow 5pm !urgent #finance @home every month".
+//
+// Words starting with ! set the priority (!low, !medium, !high, !urgent or
+// !1 to !4), # adds a tag when followed by a letter and @ sets the category. Due dates are written as
+// today, tomorrow, a weekday, "next week", "in 3 days", 2024-12-31 or
+// "dec 31", optionally followed by a time such as 5pm, 17:30 or noon.
+// Recurrence is written as daily, weekly, monthly, yearly or "every ...".
+// Everything else makes up the title; text in double quotes is always kept
+// in the title as written.
+package quickadd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Entry is a task described by a quick-add line
+type Entry struct {
+	Title string
+	// DueDate is set when the line names a day, a time or a recurrence.
+	// Without a time it is midnight of the day.
+	DueDate *time.Time
+	// Priority is 1 (low) to 4 (urgent), or 0 when the line gives none
+	Priority int
+	Tags     []string
+	Category string
+	// Recurrence is an RRULE such as "FREQ=WEEKLY;BYDAY=MO", or empty
+	Recurrence string
+}
+
+// Priorities by name, matching task.Low to task.Urgent
+var priorities = map[string]int{
+	"low": 1, "medium": 2, "high": 3, "urgent": 4,
+	"1": 1, "2": 2, "3": 3, "4": 4,
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday,
+	"wednesday": time.Wednesday, "thursday": time.Thursday, "friday": time.Friday,
+	"saturday": time.Saturday,
+}
+
+// weekdayAbbreviations are only recognized after a word such as "on" or
+// "every", since "sun" or "wed" are common words on their own
+var weekdayAbbreviations = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wed": time.Wednesday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"fri": time.Friday, "sat": time.Saturday,
+}
+
+var weekdayCodes = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+var months = map[string]time.Month{
+	"jan": time.January, "january": time.January, "feb": time.February, "february": time.February,
+	"mar": time.March, "march": time.March, "apr": time.April, "april": time.April,
+	"may": time.May, "jun": time.June, "june": time.June, "jul": time.July, "july": time.July,
+	"aug": time.August, "august": time.August, "sep": time.September, "sept": time.September,
+	"september": time.September, "oct": time.October, "october": time.October,
+	"nov": time.November, "november": time.November, "dec": time.December, "december": time.December,
+}
+
+// units are the lengths used by "in 3 days" and "every 2 weeks", with their RRULE frequency
+var units = map[string]string{
+	"day": "DAILY", "days": "DAILY", "week": "WEEKLY", "weeks": "WEEKLY",
+	"month": "MONTHLY", "months": "MONTHLY", "year": "YEARLY", "years": "YEARLY",
+}
+
+// token is a word of the line. Quoted text is a single literal token.
+type token struct {
+	text    string
+	literal bool
+}
+
+// word returns the token for matching: lower case, without trailing punctuation
+func (t token) word() string {
+	if t.literal {
+		return ""
+	}
+	return strings.ToLower(strings.TrimRight(t.text, ",.;"))
+}
+
+// parser holds the state of a single Parse call
+type parser struct {
+	now    time.Time
+	tokens []token
+	pos    int
+
+	date       *time.Time
+	clock      *time.Duration // time of day
+	frequency  string
+	interval   int
+	byWeekday  []time.Weekday
+	entry      Entry
+	titleWords []string
+}
+
+// Parse reads a quick-add line. Relative dates count from now, and dates and
+// times are in the location of now. Errors start with "invalid".
+func Parse(line string, now time.Time) (*Entry, error) {
+	tokens, err := tokenize(line)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{now: now, tokens: tokens}
+	for p.pos < len(p.tokens) {
+		consumed, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if consumed == 0 {
+			p.titleWords = append(p.titleWords, p.tokens[p.pos].text)
+			consumed = 1
+		}
+		p.pos += consumed
+	}
+
+	p.entry.Title = strings.Join(p.titleWords, " ")
+	if p.entry.Title == "" {
+		return nil, errors.New("invalid entry: the title is missing")
+	}
+	p.entry.DueDate = p.dueDate()
+	if p.frequency != "" {
+		p.entry.Recurrence = p.rule()
+	}
+
+	return &p.entry, nil
+}
+
+// ParseDate reads a due date on its own, such as "tomorrow 5pm" or
+// "2024-12-31", in the location of now
+func ParseDate(text string, now time.Time) (time.Time, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	p := &parser{now: now, tokens: tokens}
+	for p.pos < len(p.tokens) {
+		consumed := 0
+		if p.date == nil {
+			if consumed, err = p.parseDate(); err != nil {
+				return time.Time{}, err
+			}
+		}
+		if consumed == 0 && p.clock == nil {
+			consumed = p.parseClock()
+		}
+		if consumed == 0 {
+			return time.Time{}, fmt.Errorf("invalid date %q", text)
+		}
+		p.pos += consumed
+	}
+
+	due := p.dueDate()
+	if due == nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", text)
+	}
+	return *due, nil
+}
+
+// ParseSnooze reads how long to snooze a task for, such as "3 days",
+// "2 weeks", "until monday" or "until tomorrow 9am", and returns when the task
+// wakes up. Without a time it wakes up at the start of the day.
+func ParseSnooze(text string, now time.Time) (time.Time, error) {
+	words := strings.Fields(strings.ToLower(text))
+	if len(words) > 0 && (words[0] == "until" || words[0] == "for") {
+		words = words[1:]
+	}
+	if len(words) == 2 {
+		if _, err := strconv.Atoi(words[0]); err == nil {
+			if _, ok := units[words[1]]; ok {
+				words = append([]string{"in"}, words...)
+			}
+		}
+	}
+
+	until, err := ParseDate(strings.Join(words, " "), now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid snooze %q", text)
+	}
+	return until, nil
+}
+
+// tokenize splits a line into words, keeping double-quoted text together
+func tokenize(line string) ([]token, error) {
+	var tokens []token
+	for {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			return tokens, nil
+		}
+
+		if line[0] == '"' {
+			end := strings.IndexByte(line[1:], '"')
+			if end < 0 {
+				return nil, errors.New("invalid entry: unterminated quote")
+			}
+			if text := strings.TrimSpace(line[1 : end+1]); text != "" {
+				tokens = append(tokens, token{text: text, literal: true})
+			}
+			line = line[end+2:]
+			continue
+		}
+
+		end := strings.IndexAny(line, " \t\n")
+		if end < 0 {
+			end = len(line)
+		}
+		tokens = append(tokens, token{text: line[:end]})
+		line = line[end:]
+	}
+}
+
+// peek returns the word at offset from the current token, or "" past the end
+func (p *parser) peek(offset int) string {
+	if p.pos+offset >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos+offset].word()
+}
+
+// next consumes a marker, a recurrence, a date or a time at the current
+// token and returns the number of tokens used, or 0 for a title word
+func (p *parser) next() (int, error) {
+	tok := p.tokens[p.pos]
+	if tok.literal {
+		return 0, nil
+	}
+
+	if len(tok.text) > 1 {
+		switch tok.text[0] {
+		case '!':
+			priority, ok := priorities[strings.ToLower(tok.text[1:])]
+			if !ok {
+				return 0, fmt.Errorf("invalid priority %q: use !low, !medium, !high, !urgent or !1 to !4", tok.text)
+			}
+			if p.entry.Priority != 0 {
+				return 0, errors.New("invalid entry: more than one priority")
+			}
+			p.entry.Priority = priority
+			return 1, nil
+
+		case '#':
+			// "#42" refers to an issue, so tags must start with a letter
+			if first, _ := utf8.DecodeRuneInString(tok.text[1:]); !unicode.IsLetter(first) {
+				return 0, nil
+			}
+			p.addTag(tok.text[1:])
+			return 1, nil
+
+		case '@':
+			if p.entry.Category != "" {
+				return 0, errors.New("invalid entry: more than one category")
+			}
+			p.entry.Category = tok.text[1:]
+			return 1, nil
+		}
+	}
+
+	if p.frequency == "" {
+		if consumed := p.parseRecurrence(); consumed > 0 {
+			return consumed, nil
+		}
+	}
+	if p.date == nil {
+		if consumed, err := p.parseDate(); consumed > 0 || err != nil {
+			return consumed, err
+		}
+	}
+	if p.clock == nil {
+		return p.parseClock(), nil
+	}
+	return 0, nil
+}
+
+// addTag adds a tag unless it was given before, ignoring case
+func (p *parser) addTag(name string) {
+	for _, tag := range p.entry.Tags {
+		if strings.EqualFold(tag, name) {
+			return
+		}
+	}
+	p.entry.Tags = append(p.entry.Tags, name)
+}
+
+// parseRecurrence reads "daily", "every day", "every 2 weeks", "every other
+// month", "every weekday" or "every mon,fri". Weekly recurrences may name
+// their days after "on", as in "every 2 weeks on monday".
+func (p *parser) parseRecurrence() int {
+	consumed := p.parseFrequency()
+	if consumed > 0 && p.frequency == "WEEKLY" && len(p.byWeekday) == 0 && p.peek(consumed) == "on" {
+		if days, ok := parseWeekdays(p.peek(consumed + 1)); ok {
+			p.byWeekday = days
+			consumed += 2
+		}
+	}
+	return consumed
+}
+
+// parseFrequency reads the frequency and interval of a recurrence
+func (p *parser) parseFrequency() int {
+	switch p.peek(0) {
+	case "daily":
+		p.frequency = "DAILY"
+		return 1
+	case "weekly":
+		p.frequency = "WEEKLY"
+		return 1
+	case "monthly":
+		p.frequency = "MONTHLY"
+		return 1
+	case "yearly", "annually":
+		p.frequency = "YEARLY"
+		return 1
+	case "every":
+	default:
+		return 0
+	}
+
+	word := p.peek(1)
+	if frequency, ok := units[word]; ok && !strings.HasSuffix(word, "s") {
+		p.frequency = frequency
+		return 2
+	}
+	if word == "other" {
+		if frequency, ok := units[p.peek(2)]; ok {
+			p.frequency, p.interval = frequency, 2
+			return 3
+		}
+		return 0
+	}
+	if interval, err := strconv.Atoi(word); err == nil && interval > 0 {
+		if frequency, ok := units[p.peek(2)]; ok {
+			p.frequency, p.interval = frequency, interval
+			return 3
+		}
+		return 0
+	}
+	if word == "weekday" || word == "weekdays" {
+		p.frequency = "WEEKLY"
+		p.byWeekday = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+		return 2
+	}
+
+	days, ok := parseWeekdays(word)
+	if !ok {
+		return 0
+	}
+	p.frequency = "WEEKLY"
+	p.byWeekday = days
+	return 2
+}
+
+// parseWeekdays reads a comma-separated list of weekdays such as "mon,fri"
+func parseWeekdays(word string) ([]time.Weekday, bool) {
+	var days []time.Weekday
+	for _, name := range strings.Split(word, ",") {
+		day, ok := weekdays[name]
+		if !ok {
+			day, ok = weekdayAbbreviations[name]
+		}
+		if !ok {
+			return nil, false
+		}
+		days = append(days, day)
+	}
+	return days, true
+}
+
+// parseDate reads a day, optionally introduced by "on", "by" or "due"
+func (p *parser) parseDate() (int, error) {
+	offset := 0
+	switch p.peek(0) {
+	case "on", "by", "due":
+		offset = 1
+	}
+
+	consumed, err := p.parseDay(offset)
+	if consumed == 0 || err != nil {
+		return 0, err
+	}
+	return offset + consumed, nil
+}
+
+// parseDay reads a day at offset. Weekday abbreviations need a word before them.
+func (p *parser) parseDay(offset int) (int, error) {
+	today := time.Date(p.now.Year(), p.now.Month(), p.now.Day(), 0, 0, 0, 0, p.now.Location())
+	word := p.peek(offset)
+
+	switch word {
+	case "today":
+		p.date = &today
+		return 1, nil
+	case "tomorrow":
+		day := today.AddDate(0, 0, 1)
+		p.date = &day
+		return 1, nil
+	case "next", "this":
+		next := p.peek(offset + 1)
+		if weekday, ok := lookupWeekday(next, true); ok {
+			day := nextWeekday(today, weekday, word == "next")
+			p.date = &day
+			return 2, nil
+		}
+		if word == "next" {
+			if _, ok := units[next]; ok && !strings.HasSuffix(next, "s") {
+				day := addUnits(today, next, 1)
+				p.date = &day
+				return 2, nil
+			}
+		}
+		return 0, nil
+	case "in":
+		amount := p.peek(offset + 1)
+		count, err := strconv.Atoi(amount)
+		if amount == "a" || amount == "an" {
+			count, err = 1, nil
+		}
+		unit := p.peek(offset + 2)
+		if _, ok := units[unit]; err != nil || count <= 0 || !ok {
+			return 0, nil
+		}
+		day := addUnits(today, unit, count)
+		p.date = &day
+		return 3, nil
+	}
+
+	if weekday, ok := lookupWeekday(word, offset > 0); ok {
+		day := nextWeekday(today, weekday, false)
+		p.date = &day
+		return 1, nil
+	}
+
+	if len(word) == len("2006-01-02") && strings.Count(word, "-") == 2 {
+		day, err := time.ParseInLocation("2006-01-02", word, p.now.Location())
+		if err != nil {
+			return 0, fmt.Errorf("invalid date %q", word)
+		}
+		p.date = &day
+		return 1, nil
+	}
+
+	// "dec 31", "31 dec" and "december 31st", optionally followed by a year
+	month, monthOK := months[word]
+	dayOfMonth, dayOK := parseDayOfMonth(word)
+	consumed := 0
+	if monthOK {
+		if dayOfMonth, dayOK = parseDayOfMonth(p.peek(offset + 1)); dayOK {
+			consumed = 2
+		}
+	} else if dayOK {
+		if month, monthOK = months[p.peek(offset+1)]; monthOK {
+			consumed = 2
+		}
+	}
+	if consumed == 0 {
+		return 0, nil
+	}
+
+	year, yearGiven := today.Year(), false
+	if next := p.peek(offset + consumed); len(next) == 4 {
+		if y, err := strconv.Atoi(next); err == nil {
+			year, yearGiven = y, true
+			consumed++
+		}
+	}
+
+	day := time.Date(year, month, dayOfMonth, 0, 0, 0, 0, p.now.Location())
+	if day.Month() != month {
+		return 0, fmt.Errorf("invalid date: %s has no day %d", month, dayOfMonth)
+	}
+	if !yearGiven && day.Before(today) {
+		day = day.AddDate(1, 0, 0)
+	}
+	p.date = &day
+	return consumed, nil
+}
+
+// parseClock reads a time of day such as 5pm, 5:30 pm, 17:30 or noon,
+// optionally introduced by "at"
+func (p *parser) parseClock() int {
+	offset := 0
+	if p.peek(0) == "at" {
+		offset = 1
+	}
+
+	word := p.peek(offset)
+	consumed := 1
+	if word == "noon" {
+		clock := 12 * time.Hour
+		p.clock = &clock
+		return offset + 1
+	}
+
+	suffix := ""
+	switch {
+	case strings.HasSuffix(word, "am"), strings.HasSuffix(word, "pm"):
+		suffix = word[len(word)-2:]
+		word = word[:len(word)-2]
+	case p.peek(offset+1) == "am" || p.peek(offset+1) == "pm":
+		suffix = p.peek(offset + 1)
+		consumed = 2
+	}
+	if suffix == "" && !strings.Contains(word, ":") {
+		return 0
+	}
+
+	hourText, minuteText := word, "0"
+	if i := strings.IndexByte(word, ':'); i >= 0 {
+		hourText, minuteText = word[:i], word[i+1:]
+		if len(minuteText) != 2 {
+			return 0
+		}
+	}
+	hour, err := strconv.Atoi(hourText)
+	if err != nil {
+		return 0
+	}
+	minute, err := strconv.Atoi(minuteText)
+	if err != nil || minute < 0 || minute > 59 {
+		return 0
+	}
+
+	if suffix == "" {
+		if hour < 0 || hour > 23 {
+			return 0
+		}
+	} else {
+		if hour < 1 || hour > 12 {
+			return 0
+		}
+		hour %= 12
+		if suffix == "pm" {
+			hour += 12
+		}
+	}
+
+	clock := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute
+	p.clock = &clock
+	return offset + consumed
+}
+
+// dueDate combines the day, the time and the recurrence into the due date.
+// A time without a day is the next time that comes, and a recurrence without
+// a day starts with its first occurrence.
+func (p *parser) dueDate() *time.Time {
+	if p.date == nil && p.clock == nil && p.frequency == "" {
+		return nil
+	}
+
+	var clock time.Duration
+	if p.clock != nil {
+		clock = *p.clock
+	}
+	at := func(day time.Time) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location()).Add(clock)
+	}
+
+	if p.date != nil {
+		due := at(*p.date)
+		return &due
+	}
+
+	day := time.Date(p.now.Year(), p.now.Month(), p.now.Day(), 0, 0, 0, 0, p.now.Location())
+	for i := 0; i < 8; i++ {
+		candidate := day.AddDate(0, 0, i)
+		if len(p.byWeekday) > 0 && !containsWeekday(p.byWeekday, candidate.Weekday()) {
+			continue
+		}
+		if p.clock != nil && !at(candidate).After(p.now) {
+			continue
+		}
+		due := at(candidate)
+		return &due
+	}
+	return nil
+}
+
+// rule formats the recurrence as an RRULE
+func (p *parser) rule() string {
+	parts := []string{"FREQ=" + p.frequency}
+	if p.interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(p.interval))
+	}
+	if len(p.byWeekday) > 0 {
+		codes := make([]string, len(p.byWeekday))
+		for i, weekday := range p.byWeekday {
+			codes[i] = weekdayCodes[weekday]
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	return strings.Join(parts, ";")
+}
+
+// lookupWeekday finds a weekday by its full name, or by its abbreviation when
+// abbreviations are allowed
+func lookupWeekday(word string, abbreviated bool) (time.Weekday, bool) {
+	if weekday, ok := weekdays[word]; ok {
+		return weekday, true
+	}
+	if abbreviated {
+		weekday, ok := weekdayAbbreviations[word]
+		return weekday, ok
+	}
+	return 0, false
+}
+
+// nextWeekday returns the first day on or after today that falls on weekday,
+// or strictly after today when skipToday is set
+func nextWeekday(today time.Time, weekday time.Weekday, skipToday bool) time.Time {
+	days := (int(weekday) - int(today.Weekday()) + 7) % 7
+	if days == 0 && skipToday {
+		days = 7
+	}
+	return today.AddDate(0, 0, days)
+}
+
+// addUnits moves a day forward by count days, weeks, months or years. Months
+// and years keep the day of the month where it exists, and otherwise end up
+// on the last day of the month.
+func addUnits(day time.Time, unit string, count int) time.Time {
+	switch units[unit] {
+	case "DAILY":
+		return day.AddDate(0, 0, count)
+	case "WEEKLY":
+		return day.AddDate(0, 0, 7*count)
+	}
+
+	months := count
+	if units[unit] == "YEARLY" {
+		months = 12 * count
+	}
+	first := time.Date(day.Year(), day.Month()+time.Month(months), 1, 0, 0, 0, 0, day.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	if day.Day() < last {
+		last = day.Day()
+	}
+	return first.AddDate(0, 0, last-1)
+}
+
+// parseDayOfMonth reads 1 to 31, optionally followed by st, nd, rd or th
+func parseDayOfMonth(word string) (int, bool) {
+	for _, suffix := range []string{"st", "nd", "rd", "th"} {
+		word = strings.TrimSuffix(word, suffix)
+	}
+	day, err := strconv.Atoi(word)
+	if err != nil || day < 1 || day > 31 {
+		return 0, false
+	}
+	return day, true
+}
+
+func containsWeekday(days []time.Weekday, weekday time.Weekday) bool {
+	for _, day := range days {
+		if day == weekday {
+			return true
+		}
+	}
+	return false
+}
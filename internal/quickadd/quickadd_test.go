@@ -0,0 +1,99 @@
+package quickadd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	// Wednesday afternoon
+	now := time.Date(2024, time.March, 13, 14, 30, 0, 0, time.UTC)
+	day := func(month time.Month, d, hour, minute int) *time.Time {
+		due := time.Date(2024, month, d, hour, minute, 0, 0, time.UTC)
+		return &due
+	}
+
+	tests := []struct {
+		line string
+		want Entry
+	}{
+		{"Pay rent tomorrow 5pm !urgent #finance @home every month", Entry{
+			Title: "Pay rent", DueDate: day(time.March, 14, 17, 0), Priority: 4,
+			Tags: []string{"finance"}, Category: "home", Recurrence: "FREQ=MONTHLY",
+		}},
+		{"Buy 3 apples", Entry{Title: "Buy 3 apples"}},
+		{"Call mom on fri at 5:30 pm !2", Entry{Title: "Call mom", DueDate: day(time.March, 15, 17, 30), Priority: 2}},
+		{"Standup wednesday 9am", Entry{Title: "Standup", DueDate: day(time.March, 13, 9, 0)}},
+		{"Standup next wednesday", Entry{Title: "Standup", DueDate: day(time.March, 20, 0, 0)}},
+		{"Report in 2 weeks #work #Work", Entry{Title: "Report", DueDate: day(time.March, 27, 0, 0), Tags: []string{"work"}}},
+		{"Renew next month", Entry{Title: "Renew", DueDate: day(time.April, 13, 0, 0)}},
+		{"File taxes by apr 15th", Entry{Title: "File taxes", DueDate: day(time.April, 15, 0, 0)}},
+		{"Plan party 1 jan", Entry{Title: "Plan party", DueDate: func() *time.Time {
+			due := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+			return &due
+		}()}},
+		{"Launch 2024-06-01 17:00", Entry{Title: "Launch", DueDate: day(time.June, 1, 17, 0)}},
+		{"Lunch noon", Entry{Title: "Lunch", DueDate: day(time.March, 14, 12, 0)}},
+		{"Gym every mon,fri 7am", Entry{Title: "Gym", DueDate: day(time.March, 15, 7, 0), Recurrence: "FREQ=WEEKLY;BYDAY=MO,FR"}},
+		{"Water plants every other day", Entry{Title: "Water plants", DueDate: day(time.March, 13, 0, 0), Recurrence: "FREQ=DAILY;INTERVAL=2"}},
+		{"Timesheet every weekday", Entry{Title: "Timesheet", DueDate: day(time.March, 13, 0, 0), Recurrence: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"}},
+		{"Plan Q4 every 2 weeks on monday", Entry{Title: "Plan Q4", DueDate: day(time.March, 18, 0, 0), Recurrence: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO"}},
+		{"Review weekly on tue,thu", Entry{Title: "Review", DueDate: day(time.March, 14, 0, 0), Recurrence: "FREQ=WEEKLY;BYDAY=TU,TH"}},
+		{"Fix issue #42", Entry{Title: "Fix issue #42"}},
+		{"Fix #42 #bugs", Entry{Title: "Fix #42", Tags: []string{"bugs"}}},
+		{`Read "next monday" notes`, Entry{Title: "Read next monday notes"}},
+		{"Sit in the sun", Entry{Title: "Sit in the sun"}},
+	}
+
+	for _, test := range tests {
+		got, err := Parse(test.line, now)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", test.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(*got, test.want) {
+			t.Errorf("Parse(%q) = %+v, want %+v", test.line, *got, test.want)
+		}
+	}
+
+	for _, line := range []string{"", "!urgent #home", "Pay !5", "Pay @home @work", "Pay !low !high", "Launch 2024-02-30", "Launch feb 30", `Read "notes`} {
+		if _, err := Parse(line, now); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+			t.Errorf("Expected Parse(%q) to fail, got %v", line, err)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	now := time.Date(2024, time.March, 13, 14, 30, 0, 0, time.UTC)
+
+	due, err := ParseDate("tomorrow 5pm", now)
+	if err != nil || !due.Equal(time.Date(2024, time.March, 14, 17, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected tomorrow at 5pm, got %v (%v)", due, err)
+	}
+	if _, err := ParseDate("tomorrow or so", now); err == nil {
+		t.Errorf("Expected trailing words to fail")
+	}
+}
+
+func TestParseSnooze(t *testing.T) {
+	now := time.Date(2024, time.March, 13, 14, 30, 0, 0, time.UTC)
+
+	tests := map[string]time.Time{
+		"3 days":             time.Date(2024, time.March, 16, 0, 0, 0, 0, time.UTC),
+		"for 2 weeks":        time.Date(2024, time.March, 27, 0, 0, 0, 0, time.UTC),
+		"until Monday":       time.Date(2024, time.March, 18, 0, 0, 0, 0, time.UTC),
+		"until tomorrow 9am": time.Date(2024, time.March, 14, 9, 0, 0, 0, time.UTC),
+		"next month":         time.Date(2024, time.April, 13, 0, 0, 0, 0, time.UTC),
+	}
+	for text, want := range tests {
+		if got, err := ParseSnooze(text, now); err != nil || !got.Equal(want) {
+			t.Errorf("ParseSnooze(%q) = %v (%v), want %v", text, got, err, want)
+		}
+	}
+
+	if _, err := ParseSnooze("a while", now); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+		t.Errorf("Expected an unknown snooze to fail, got %v", err)
+	}
+}
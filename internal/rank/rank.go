@@ -0,0 +1,128 @@
+// Package rank generates lexicographic rank keys for manually ordered lists.
+//
+// A key is a base-62 fraction written without its leading "0.": "V" sorts
+// between "1" and "k", and "V" and "W" have "VV" between them. Keys compare
+// as plain strings, so a list is ordered with ORDER BY on the key column, and
+// moving an item only changes the key of that item. Keys never end in the
+// lowest digit, so there is always room between two of them.
+package rank
+
+import (
+	"fmt"
+	"strings"
+)
+
+// digits are the digits of a key, in ascending byte order
+const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+const base = len(digits)
+
+// MaxLength is the key length beyond which a list should be rebalanced
+const MaxLength = 12
+
+// Between returns a key that sorts strictly between a and b. An empty a
+// stands for the start of the list and an empty b for its end.
+func Between(a, b string) (string, error) {
+	for _, key := range []string{a, b} {
+		if err := Validate(key); key != "" && err != nil {
+			return "", err
+		}
+	}
+	if b != "" && a >= b {
+		return "", fmt.Errorf("invalid rank keys: %q does not sort before %q", a, b)
+	}
+
+	return midpoint(a, b), nil
+}
+
+// midpoint returns a key between a and b, which must be valid and in order
+func midpoint(a, b string) string {
+	if b != "" {
+		// Keep the prefix a and b share, reading missing digits of a as zeros
+		n := 0
+		for n < len(b) && digitAt(a, n) == strings.IndexByte(digits, b[n]) {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + midpoint(rest, b[n:])
+		}
+	}
+
+	low := digitAt(a, 0)
+	high := base
+	if b != "" {
+		high = strings.IndexByte(digits, b[0])
+	}
+	if high-low > 1 {
+		return string(digits[(low+high)/2])
+	}
+
+	// The first digits are adjacent: b's first digit alone sorts between
+	// them when b goes on, and otherwise a key longer than a does
+	if len(b) > 1 {
+		return b[:1]
+	}
+	rest := ""
+	if len(a) > 1 {
+		rest = a[1:]
+	}
+	return string(digits[low]) + midpoint(rest, "")
+}
+
+// digitAt returns the value of the digit of key at i, zero past its end
+func digitAt(key string, i int) int {
+	if i >= len(key) {
+		return 0
+	}
+	return strings.IndexByte(digits, key[i])
+}
+
+// Spread returns n keys in ascending order, evenly spaced and as short as
+// possible, for rebalancing a list of n items
+func Spread(n int) []string {
+	if n <= 0 {
+		return nil
+	}
+
+	// Leave as much room around each key as the shortest keys allow
+	length := 1
+	slots := base
+	for slots <= n {
+		length++
+		slots *= base
+	}
+
+	keys := make([]string, n)
+	for i := range keys {
+		value := (i + 1) * slots / (n + 1)
+		key := make([]byte, length)
+		for j := length - 1; j >= 0; j-- {
+			key[j] = digits[value%base]
+			value /= base
+		}
+		keys[i] = strings.TrimRight(string(key), digits[:1])
+	}
+
+	return keys
+}
+
+// Validate checks that key is a non-empty key of base-62 digits that does
+// not end in the lowest digit
+func Validate(key string) error {
+	if key == "" {
+		return fmt.Errorf("invalid rank key: empty")
+	}
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(digits, key[i]) < 0 {
+			return fmt.Errorf("invalid rank key %q: %q is not a digit", key, key[i])
+		}
+	}
+	if key[len(key)-1] == digits[0] {
+		return fmt.Errorf("invalid rank key %q: it ends in %q", key, digits[0])
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package rank
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		a, b, want string
+	}{
+		{"", "", "V"},
+		{"V", "", "k"},
+		{"", "V", "F"},
+		{"1", "3", "2"},
+		{"1", "2", "1V"},
+		{"V", "W", "VV"},
+		{"V", "V1", "V0V"},
+		{"y", "", "z"},
+		{"z", "", "zV"},
+		{"", "1", "0V"},
+		{"1z", "2", "1zV"},
+		{"1", "21", "2"},
+	}
+	for _, test := range tests {
+		got, err := Between(test.a, test.b)
+		if err != nil || got != test.want {
+			t.Errorf("Between(%q, %q) = %q (%v), want %q", test.a, test.b, got, err, test.want)
+		}
+	}
+
+	for _, pair := range [][2]string{{"V", "V"}, {"W", "V"}, {"V0", ""}, {"", "a-"}} {
+		if _, err := Between(pair[0], pair[1]); err == nil {
+			t.Errorf("Expected Between(%q, %q) to fail", pair[0], pair[1])
+		}
+	}
+}
+
+func TestBetweenKeepsOrder(t *testing.T) {
+	// Insert repeatedly at the front, at the back and after the first key
+	keys := []string{"V"}
+	for i := 0; i < 200; i++ {
+		var a, b string
+		switch i % 3 {
+		case 0:
+			b = keys[0]
+		case 1:
+			a = keys[len(keys)-1]
+		default:
+			a, b = keys[0], keys[1]
+		}
+		key, err := Between(a, b)
+		if err != nil {
+			t.Fatalf("Between(%q, %q) failed: %v", a, b, err)
+		}
+		if (a != "" && key <= a) || (b != "" && key >= b) || Validate(key) != nil {
+			t.Fatalf("Between(%q, %q) = %q is out of order", a, b, key)
+		}
+		keys = append(keys, key)
+		sort.Strings(keys)
+	}
+}
+
+func TestSpread(t *testing.T) {
+	for _, n := range []int{1, 2, 61, 62, 1000} {
+		keys := Spread(n)
+		if len(keys) != n {
+			t.Fatalf("Spread(%d) returned %d keys", n, len(keys))
+		}
+		for i, key := range keys {
+			if err := Validate(key); err != nil {
+				t.Fatalf("Spread(%d) returned an invalid key: %v", n, err)
+			}
+			if i > 0 && keys[i-1] >= key {
+				t.Fatalf("Spread(%d) is out of order at %d: %q, %q", n, i, keys[i-1], key)
+			}
+		}
+	}
+	if keys := Spread(1); keys[0] != "V" {
+		t.Errorf("Expected a single key in the middle, got %q", keys[0])
+	}
+	if keys := Spread(1000); len(keys[999]) > 2 {
+		t.Errorf("Expected two-digit keys for 1000 items, got %q", keys[999])
+	}
+}
@@ -1,13 +1,18 @@
 package search
 
 import (
+	"strconv"
+	"strings"
 	"time"
+
+	"learn-go-capstone/internal/database"
 )
 
 // SearchQuery represents a search query with various filters
 type SearchQuery struct {
 	Query       string    `json:"query"`        // Text search query
 	UserID      *int      `json:"user_id"`      // Filter by user ID
+	ProjectID   *int      `json:"project_id"`   // Filter by project ID
 	Status      *int      `json:"status"`       // Filter by status
 	Priority    *int      `json:"priority"`     // Filter by priority
 	CategoryID  *int      `json:"category_id"`  // Filter by category ID
@@ -17,12 +22,29 @@ type SearchQuery struct {
 	DueDateFrom *time.Time `json:"due_date_from"` // Filter by due date from
 	DueDateTo   *time.Time `json:"due_date_to"`   // Filter by due date to
 	IsOverdue   *bool     `json:"is_overdue"`   // Filter by overdue status
+	IncludeArchived bool  `json:"include_archived"` // Also match archived tasks
+	CustomFields []CustomFieldFilter `json:"custom_fields"` // Filter by custom field values
 	Limit       int       `json:"limit"`        // Limit number of results
 	Offset      int       `json:"offset"`       // Offset for pagination
-	SortBy      string    `json:"sort_by"`      // Sort field (title, created_at, due_date, priority)
+	SortBy      string    `json:"sort_by"`      // Sort field (title, created_at, due_date, priority, position, custom:<field_id>)
 	SortOrder   string    `json:"sort_order"`   // Sort order (asc, desc)
 }
 
+// CustomSortPrefix starts the sort field that orders tasks by a custom field,
+// followed by the field ID
+const CustomSortPrefix = "custom:"
+
+// CustomFieldFilter matches tasks by the value of a custom field. Value
+// matches text fields by substring and other fields exactly; Min and Max
+// bound the value in the order of the field type. Tasks without a value for
+// the field never match.
+type CustomFieldFilter struct {
+	FieldID int    `json:"field_id"`
+	Value   string `json:"value,omitempty"`
+	Min     string `json:"min,omitempty"`
+	Max     string `json:"max,omitempty"`
+}
+
 // SearchResult represents a search result with metadata
 type SearchResult struct {
 	Tasks      []TaskResult `json:"tasks"`
@@ -45,6 +67,7 @@ type TaskResult struct {
 	DueDate     *time.Time `json:"due_date,omitempty"`
 	UserID      *int      `json:"user_id,omitempty"`
 	CategoryID  *int      `json:"category_id,omitempty"`
+	ProjectID   *int      `json:"project_id,omitempty"`
 	IsArchived  bool      `json:"is_archived"`
 	// Additional fields for search results
 	Category    *CategoryResult `json:"category,omitempty"`
@@ -153,6 +176,10 @@ func (sq *SearchQuery) Validate() error {
 	}
 	if sq.SortOrder == "" {
 		sq.SortOrder = "desc"
+		// A manual order reads from the top down
+		if sq.SortBy == "position" {
+			sq.SortOrder = "asc"
+		}
 	}
 	
 	// Validate sort fields
@@ -163,8 +190,9 @@ func (sq *SearchQuery) Validate() error {
 		"due_date":   true,
 		"priority":   true,
 		"status":     true,
+		"position":   true,
 	}
-	if !validSortFields[sq.SortBy] {
+	if _, custom := sq.CustomSortField(); !validSortFields[sq.SortBy] && !custom {
 		sq.SortBy = "created_at"
 	}
 	
@@ -175,3 +203,29 @@ func (sq *SearchQuery) Validate() error {
 	
 	return nil
 }
+
+// PositionList returns the manually ordered list that the position sort
+// follows: the project's shared order when the query is scoped to a project,
+// and otherwise the user's own order. It is empty when the query has neither.
+func (sq *SearchQuery) PositionList() string {
+	switch {
+	case sq.ProjectID != nil:
+		return database.ProjectTaskList(*sq.ProjectID)
+	case sq.UserID != nil:
+		return database.PersonalTaskList(*sq.UserID)
+	}
+	return ""
+}
+
+// CustomSortField returns the ID of the custom field the query sorts by, if any
+func (sq *SearchQuery) CustomSortField() (int, bool) {
+	if !strings.HasPrefix(sq.SortBy, CustomSortPrefix) {
+		return 0, false
+	}
+	
+	fieldID, err := strconv.Atoi(strings.TrimPrefix(sq.SortBy, CustomSortPrefix))
+	if err != nil || fieldID <= 0 {
+		return 0, false
+	}
+	return fieldID, true
+}
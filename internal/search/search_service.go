@@ -2,6 +2,7 @@ package search
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -31,7 +32,7 @@ func (ss *SearchService) SearchTasks(query SearchQuery) (*SearchResult, error) {
 	sqlQuery, args := ss.buildSearchQuery(query)
 	
 	// Execute search
-	dbTasks, err := ss.executeSearch(sqlQuery, args)
+	dbTasks, err := ss.executeSearch(sqlQuery, args, query)
 	if err != nil {
 		return nil, err
 	}
@@ -210,8 +211,11 @@ func (ss *SearchService) buildSearchQuery(query SearchQuery) (string, []interfac
 	LEFT JOIN task_tags tt ON t.id = tt.task_id
 	LEFT JOIN tags tg ON tt.tag_id = tg.id`
 	
-	// Always exclude archived tasks
-	conditions = append(conditions, "t.is_archived = FALSE")
+	// Trashed tasks never match; archived ones only when asked for
+	conditions = append(conditions, "t.deleted_at IS NULL")
+	if !query.IncludeArchived {
+		conditions = append(conditions, "t.is_archived = FALSE")
+	}
 	
 	// Text search
 	if query.Query != "" {
@@ -226,6 +230,12 @@ func (ss *SearchService) buildSearchQuery(query SearchQuery) (string, []interfac
 		args = append(args, *query.UserID)
 	}
 	
+	// Project filter
+	if query.ProjectID != nil {
+		conditions = append(conditions, "t.project_id = ?")
+		args = append(args, *query.ProjectID)
+	}
+	
 	// Status filter
 	if query.Status != nil {
 		conditions = append(conditions, "t.status = ?")
@@ -280,6 +290,12 @@ func (ss *SearchService) buildSearchQuery(query SearchQuery) (string, []interfac
 		args = append(args, time.Now())
 	}
 	
+	// Custom field filters; values compare by field type, as in matchesFilters
+	for _, filter := range query.CustomFields {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM task_custom_values cv WHERE cv.task_id = t.id AND cv.field_id = ?)")
+		args = append(args, filter.FieldID)
+	}
+	
 	// Build WHERE clause
 	whereClause := ""
 	if len(conditions) > 0 {
@@ -287,7 +303,8 @@ func (ss *SearchService) buildSearchQuery(query SearchQuery) (string, []interfac
 	}
 	
 	// Build ORDER BY clause
-	orderBy := ss.buildOrderByClause(query)
+	orderBy, orderArgs := ss.buildOrderByClause(query)
+	args = append(args, orderArgs...)
 	
 	// Build LIMIT and OFFSET
 	limitClause := fmt.Sprintf("LIMIT %d OFFSET %d", query.Limit, query.Offset)
@@ -301,9 +318,10 @@ func (ss *SearchService) buildSearchQuery(query SearchQuery) (string, []interfac
 	return finalQuery, args
 }
 
-// buildOrderByClause builds the ORDER BY clause
-func (ss *SearchService) buildOrderByClause(query SearchQuery) string {
+// buildOrderByClause builds the ORDER BY clause and the arguments of its placeholders
+func (ss *SearchService) buildOrderByClause(query SearchQuery) (string, []interface{}) {
 	orderBy := "ORDER BY "
+	var args []interface{}
 	
 	switch query.SortBy {
 	case "title":
@@ -318,8 +336,22 @@ func (ss *SearchService) buildOrderByClause(query SearchQuery) string {
 		orderBy += "t.priority"
 	case "status":
 		orderBy += "t.status"
+	case "position":
+		if list := query.PositionList(); list != "" {
+			// Unranked tasks come last, then the rest in creation order
+			key := "(SELECT tr.rank_key FROM task_ranks tr WHERE tr.task_id = t.id AND tr.list_key = ?)"
+			orderBy += key + " IS NULL, " + key
+			args = append(args, list, list)
+		} else {
+			orderBy += "t.created_at"
+		}
 	default:
-		orderBy += "t.created_at"
+		if fieldID, ok := query.CustomSortField(); ok {
+			orderBy += "(SELECT cv.value FROM task_custom_values cv WHERE cv.task_id = t.id AND cv.field_id = ?)"
+			args = append(args, fieldID)
+		} else {
+			orderBy += "t.created_at"
+		}
 	}
 	
 	if query.SortOrder == "asc" {
@@ -328,36 +360,28 @@ func (ss *SearchService) buildOrderByClause(query SearchQuery) string {
 		orderBy += " DESC"
 	}
 	
-	return orderBy
+	return orderBy, args
 }
 
 // executeSearch executes the search query
-func (ss *SearchService) executeSearch(sqlQuery string, args []interface{}) ([]database.DatabaseTask, error) {
+func (ss *SearchService) executeSearch(sqlQuery string, args []interface{}, searchQuery SearchQuery) ([]database.DatabaseTask, error) {
 	// This is a simplified version - in a real implementation, you'd use the database connection
 	// For now, we'll use the existing repository methods
 	
 	// If it's a simple text search, use the existing method
-	if len(args) == 4 && len(args[0].(string)) > 2 { // Basic text search
-		query := args[0].(string)
+	if text, ok := args[0].(string); ok && len(args) == 4 && len(text) > 2 && !searchQuery.IncludeArchived { // Basic text search
+		query := text
 		query = query[1 : len(query)-1] // Remove % characters
 		return ss.repository.SearchTasks(query)
 	}
 	
 	// For complex queries, we'd need to implement a more sophisticated query builder
 	// For now, fall back to getting all tasks and filtering in memory
-	allTasks, err := ss.repository.GetAllTasks()
+	filteredTasks, err := ss.filterTasks(searchQuery)
 	if err != nil {
 		return nil, err
 	}
 	
-	// Apply basic filters
-	var filteredTasks []database.DatabaseTask
-	for _, task := range allTasks {
-		if ss.matchesFilters(task, args) {
-			filteredTasks = append(filteredTasks, task)
-		}
-	}
-	
 	// Apply pagination
 	// Extract limit and offset from args (they should be the last two arguments)
 	if len(args) >= 2 {
@@ -381,22 +405,311 @@ func (ss *SearchService) executeSearch(sqlQuery string, args []interface{}) ([]d
 	return filteredTasks, nil
 }
 
-// matchesFilters checks if a task matches the search filters
-func (ss *SearchService) matchesFilters(task database.DatabaseTask, args []interface{}) bool {
-	// This is a simplified implementation
-	// In a real system, you'd parse the args and apply filters properly
+// filterTasks loads the tasks and keeps those matching the search filters,
+// in the order of the sort field
+func (ss *SearchService) filterTasks(query SearchQuery) ([]database.DatabaseTask, error) {
+	fields, err := ss.customFields(&query)
+	if err != nil {
+		return nil, err
+	}
+	
+	allTasks, err := ss.loadTasks(query.IncludeArchived)
+	if err != nil {
+		return nil, err
+	}
+	
+	var filteredTasks []database.DatabaseTask
+	for _, task := range allTasks {
+		matches, err := ss.matchesFilters(task, query, fields)
+		if err != nil {
+			return nil, err
+		}
+		if matches {
+			filteredTasks = append(filteredTasks, task)
+		}
+	}
+	
+	if err := ss.sortTasks(filteredTasks, query, fields); err != nil {
+		return nil, err
+	}
+	
+	return filteredTasks, nil
+}
+
+// customFields loads the custom fields that the query filters or sorts by,
+// and normalizes the filter values so that they compare with stored values
+func (ss *SearchService) customFields(query *SearchQuery) (map[int]*database.CustomField, error) {
+	fields := make(map[int]*database.CustomField)
+	load := func(fieldID int) (*database.CustomField, error) {
+		if field, ok := fields[fieldID]; ok {
+			return field, nil
+		}
+		field, err := ss.repository.GetCustomField(fieldID)
+		if err != nil {
+			return nil, err
+		}
+		fields[fieldID] = field
+		return field, nil
+	}
+	
+	// The filters are copied, leaving those of the caller as they were
+	query.CustomFields = append([]CustomFieldFilter(nil), query.CustomFields...)
+	for i := range query.CustomFields {
+		filter := &query.CustomFields[i]
+		field, err := load(filter.FieldID)
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range []*string{&filter.Value, &filter.Min, &filter.Max} {
+			if *value == "" {
+				continue
+			}
+			if *value, err = field.NormalizeValue(*value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	
+	if fieldID, ok := query.CustomSortField(); ok {
+		if _, err := load(fieldID); err != nil {
+			return nil, err
+		}
+	}
+	
+	return fields, nil
+}
+
+// customValues returns the custom field values of a task by field ID
+func (ss *SearchService) customValues(taskID int) (map[int]string, error) {
+	dbValues, err := ss.repository.GetTaskCustomValues(taskID)
+	if err != nil {
+		return nil, err
+	}
+	
+	values := make(map[int]string, len(dbValues))
+	for _, value := range dbValues {
+		values[value.FieldID] = value.Value
+	}
+	return values, nil
+}
+
+// matchesCustomField checks a normalized custom field value against a filter
+func matchesCustomField(field *database.CustomField, filter CustomFieldFilter, value string) bool {
+	if filter.Value != "" {
+		if field.Type == database.CustomFieldText {
+			if !strings.Contains(strings.ToLower(value), strings.ToLower(filter.Value)) {
+				return false
+			}
+		} else if field.CompareValues(value, filter.Value) != 0 {
+			return false
+		}
+	}
+	if filter.Min != "" && field.CompareValues(value, filter.Min) < 0 {
+		return false
+	}
+	if filter.Max != "" && field.CompareValues(value, filter.Max) > 0 {
+		return false
+	}
 	return true
 }
 
+// sortTasks orders tasks by the sort field of the query. Tasks without a due
+// date, without a value for a custom sort field, or without a position in the
+// list a position sort follows, come last in either order.
+func (ss *SearchService) sortTasks(tasks []database.DatabaseTask, query SearchQuery, fields map[int]*database.CustomField) error {
+	fieldID, custom := query.CustomSortField()
+	list := ""
+	if query.SortBy == "position" {
+		list = query.PositionList()
+	}
+	values := make(map[int]string)
+	if list != "" {
+		ranks, err := ss.repository.GetTaskRanks(list)
+		if err != nil {
+			return err
+		}
+		for _, rank := range ranks {
+			values[rank.TaskID] = rank.Key
+		}
+	}
+	if custom {
+		for _, task := range tasks {
+			taskValues, err := ss.customValues(task.ID)
+			if err != nil {
+				return err
+			}
+			if value, ok := taskValues[fieldID]; ok {
+				values[task.ID] = value
+			}
+		}
+	}
+	
+	// missing reports whether a task has no value to sort by
+	missing := func(task *database.DatabaseTask) bool {
+		if custom || list != "" {
+			_, ok := values[task.ID]
+			return !ok
+		}
+		return query.SortBy == "due_date" && task.DueDate == nil
+	}
+	compareTimes := func(a, b time.Time) int {
+		switch {
+		case a.Before(b):
+			return -1
+		case a.After(b):
+			return 1
+		}
+		return 0
+	}
+	compare := func(a, b *database.DatabaseTask) int {
+		switch {
+		case custom:
+			return fields[fieldID].CompareValues(values[a.ID], values[b.ID])
+		case list != "":
+			return strings.Compare(values[a.ID], values[b.ID])
+		case query.SortBy == "title":
+			return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+		case query.SortBy == "updated_at":
+			return compareTimes(a.UpdatedAt, b.UpdatedAt)
+		case query.SortBy == "due_date":
+			return compareTimes(*a.DueDate, *b.DueDate)
+		case query.SortBy == "priority":
+			return a.Priority - b.Priority
+		case query.SortBy == "status":
+			return a.Status - b.Status
+		default:
+			return compareTimes(a.CreatedAt, b.CreatedAt)
+		}
+	}
+	
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := &tasks[i], &tasks[j]
+		if missingA, missingB := missing(a), missing(b); missingA || missingB {
+			return !missingA && missingB
+		}
+		if query.SortOrder == "asc" {
+			return compare(a, b) < 0
+		}
+		return compare(a, b) > 0
+	})
+	return nil
+}
+
+// matchesFilters checks if a task matches the search filters, applying the
+// same conditions as buildSearchQuery
+func (ss *SearchService) matchesFilters(task database.DatabaseTask, query SearchQuery, fields map[int]*database.CustomField) (bool, error) {
+	sameID := func(filter, value *int) bool {
+		return filter == nil || (value != nil && *value == *filter)
+	}
+	
+	if !sameID(query.UserID, task.UserID) || !sameID(query.ProjectID, task.ProjectID) || !sameID(query.CategoryID, task.CategoryID) {
+		return false, nil
+	}
+	if query.Status != nil && task.Status != *query.Status {
+		return false, nil
+	}
+	if query.Priority != nil && task.Priority != *query.Priority {
+		return false, nil
+	}
+	if query.DateFrom != nil && task.CreatedAt.Before(*query.DateFrom) {
+		return false, nil
+	}
+	if query.DateTo != nil && task.CreatedAt.After(*query.DateTo) {
+		return false, nil
+	}
+	if query.DueDateFrom != nil && (task.DueDate == nil || task.DueDate.Before(*query.DueDateFrom)) {
+		return false, nil
+	}
+	if query.DueDateTo != nil && (task.DueDate == nil || task.DueDate.After(*query.DueDateTo)) {
+		return false, nil
+	}
+	if query.IsOverdue != nil && *query.IsOverdue && (task.DueDate == nil || !task.DueDate.Before(time.Now()) || task.Status == 2) {
+		return false, nil
+	}
+	
+	if len(query.CustomFields) > 0 {
+		values, err := ss.customValues(task.ID)
+		if err != nil {
+			return false, err
+		}
+		for _, filter := range query.CustomFields {
+			value, ok := values[filter.FieldID]
+			if !ok || !matchesCustomField(fields[filter.FieldID], filter, value) {
+				return false, nil
+			}
+		}
+	}
+	
+	if query.Query == "" && len(query.TagNames) == 0 {
+		return true, nil
+	}
+	
+	// Like the LIKE conditions, text and tag filters ignore case and also
+	// look at the names of the category and the tags
+	tags, err := ss.repository.GetTaskTags(task.ID)
+	if err != nil {
+		return false, err
+	}
+	contains := func(s, substr string) bool {
+		return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+	}
+	
+	if len(query.TagNames) > 0 {
+		tagged := false
+		for _, tag := range tags {
+			for _, tagName := range query.TagNames {
+				tagged = tagged || contains(tag.Name, tagName)
+			}
+		}
+		if !tagged {
+			return false, nil
+		}
+	}
+	
+	if query.Query == "" || contains(task.Title, query.Query) || contains(task.Description, query.Query) {
+		return true, nil
+	}
+	if task.CategoryID != nil {
+		if category, err := ss.repository.GetCategory(*task.CategoryID); err == nil && contains(category.Name, query.Query) {
+			return true, nil
+		}
+	}
+	for _, tag := range tags {
+		if contains(tag.Name, query.Query) {
+			return true, nil
+		}
+	}
+	
+	return false, nil
+}
+
 // getTotalCount gets the total count of matching tasks
 func (ss *SearchService) getTotalCount(query SearchQuery) (int, error) {
 	// Simplified implementation - in practice, you'd run a COUNT query
-	allTasks, err := ss.repository.GetAllTasks()
+	tasks, err := ss.filterTasks(query)
 	if err != nil {
 		return 0, err
 	}
 	
-	return len(allTasks), nil
+	return len(tasks), nil
+}
+
+// loadTasks returns the active tasks, followed by the archived ones if requested
+func (ss *SearchService) loadTasks(includeArchived bool) ([]database.DatabaseTask, error) {
+	tasks, err := ss.repository.GetAllTasks()
+	if err != nil {
+		return nil, err
+	}
+	
+	if includeArchived {
+		archived, err := ss.repository.GetArchivedTasks()
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, archived...)
+	}
+	
+	return tasks, nil
 }
 
 // convertToTaskResults converts database tasks to search results
@@ -415,6 +728,7 @@ func (ss *SearchService) convertToTaskResults(dbTasks []database.DatabaseTask) [
 			DueDate:     dbTask.DueDate,
 			UserID:      dbTask.UserID,
 			CategoryID:  dbTask.CategoryID,
+			ProjectID:   dbTask.ProjectID,
 			IsArchived:  dbTask.IsArchived,
 		}
 		
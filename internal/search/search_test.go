@@ -288,6 +288,80 @@ func TestSearchQueryValidation(t *testing.T) {
 	if query.SortOrder != "desc" {
 		t.Errorf("Expected invalid sort order to be reset to 'desc', got '%s'", query.SortOrder)
 	}
+	
+	// Test position sort, which reads from the top down by default
+	query = SearchQuery{SortBy: "position"}
+	if err := query.Validate(); err != nil {
+		t.Fatalf("Expected validation to pass, got error: %v", err)
+	}
+	
+	if query.SortBy != "position" || query.SortOrder != "asc" {
+		t.Errorf("Expected position sort in ascending order, got '%s' '%s'", query.SortBy, query.SortOrder)
+	}
+}
+
+func TestSearchPositionSort(t *testing.T) {
+	tempDB := "test_position.db"
+	defer os.Remove(tempDB)
+	
+	db, err := database.Connect(&database.Config{Driver: "sqlite3", DSN: tempDB})
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.Close(db)
+	
+	if err := database.NewMigrationManager(db).Migrate(); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+	
+	repository := database.NewSQLiteRepository(db)
+	searchService := NewSearchService(repository)
+	
+	user := &database.User{Username: "user", Email: "user@example.com", Password: "password123", IsActive: true}
+	if err := repository.CreateUser(user); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	
+	var tasks []*database.DatabaseTask
+	for i := 0; i < 4; i++ {
+		task := &database.DatabaseTask{
+			Title:    fmt.Sprintf("Task %d", i),
+			Priority: 1,
+			UserID:   &user.ID,
+		}
+		if err := repository.CreateTask(task); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		tasks = append(tasks, task)
+	}
+	
+	// Tasks 2, 0 and 1 are ordered by hand, task 3 is not
+	list := database.PersonalTaskList(user.ID)
+	for i, key := range map[int]string{2: "F", 0: "V", 1: "k"} {
+		if err := repository.SetTaskRank(&database.TaskRank{List: list, TaskID: tasks[i].ID, Key: key}); err != nil {
+			t.Fatalf("Failed to set task rank: %v", err)
+		}
+	}
+	
+	for _, order := range []string{"asc", "desc"} {
+		result, err := searchService.SearchTasks(SearchQuery{UserID: &user.ID, SortBy: "position", SortOrder: order})
+		if err != nil {
+			t.Fatalf("Failed to search tasks: %v", err)
+		}
+		
+		want := []int{tasks[2].ID, tasks[0].ID, tasks[1].ID, tasks[3].ID}
+		if order == "desc" {
+			want = []int{tasks[1].ID, tasks[0].ID, tasks[2].ID, tasks[3].ID}
+		}
+		if len(result.Tasks) != len(want) {
+			t.Fatalf("Expected %d tasks, got %d", len(want), len(result.Tasks))
+		}
+		for i, task := range result.Tasks {
+			if task.ID != want[i] {
+				t.Errorf("Expected task %d at position %d in %s order, got %d", want[i], i, order, task.ID)
+			}
+		}
+	}
 }
 
 func TestSearchResultPagination(t *testing.T) {
@@ -0,0 +1,247 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"learn-go-capstone/internal/database"
+)
+
+// setArchived archives or unarchives an in-memory task
+func (tm *TaskManager) setArchived(id int, archived bool) error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	for i := range tm.tasks {
+		if tm.tasks[i].ID == id && tm.tasks[i].DeletedAt == nil {
+			if tm.tasks[i].IsArchived == archived {
+				return nil
+			}
+			tm.tasks[i].IsArchived = archived
+			tm.tasks[i].UpdatedAt = time.Now()
+			tm.events.Publish(taskEvent(EventTaskUpdated, tm.tasks[i], nil))
+			return nil
+		}
+	}
+
+	return fmt.Errorf("task with ID %d not found", id)
+}
+
+// ArchiveTask hides a task from every listing without deleting it
+func (tm *TaskManager) ArchiveTask(id int) error {
+	return tm.setArchived(id, true)
+}
+
+// UnarchiveTask brings an archived task back into the listings
+func (tm *TaskManager) UnarchiveTask(id int) error {
+	return tm.setArchived(id, false)
+}
+
+// GetArchivedTasks returns the archived tasks
+func (tm *TaskManager) GetArchivedTasks() []Task {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	var archived []Task
+	for _, task := range tm.tasks {
+		if task.IsArchived && task.DeletedAt == nil {
+			archived = append(archived, task)
+		}
+	}
+
+	return archived
+}
+
+// setDatabaseTaskArchived stores the archived flag of a database task and
+// publishes the change. Unchanged tasks are left alone.
+func setDatabaseTaskArchived(repository database.Repository, events *EventBus, dbTask *database.DatabaseTask, archived bool) error {
+	if dbTask.IsArchived == archived {
+		return nil
+	}
+
+	dbTask.IsArchived = archived
+	if err := repository.UpdateTask(dbTask); err != nil {
+		return err
+	}
+
+	events.Publish(databaseTaskEvent(EventTaskUpdated, dbTask))
+	return nil
+}
+
+// ArchiveTask hides a task from every listing without deleting it
+func (htm *HybridTaskManager) ArchiveTask(id int) error {
+	return htm.ArchiveTaskContext(context.Background(), id)
+}
+
+// UnarchiveTask brings an archived task back into the listings
+func (htm *HybridTaskManager) UnarchiveTask(id int) error {
+	return htm.UnarchiveTaskContext(context.Background(), id)
+}
+
+// GetArchivedTasks returns the archived tasks, falling back to memory if the database fails
+func (htm *HybridTaskManager) GetArchivedTasks() []Task {
+	tasks, err := htm.GetArchivedTasksContext(context.Background())
+	if err != nil {
+		return htm.memoryManager.GetArchivedTasks()
+	}
+	return tasks
+}
+
+// ArchiveTaskContext hides a task from every listing without deleting it
+func (htm *HybridTaskManager) ArchiveTaskContext(ctx context.Context, id int) error {
+	return htm.setArchived(ctx, id, true)
+}
+
+// UnarchiveTaskContext brings an archived task back into the listings
+func (htm *HybridTaskManager) UnarchiveTaskContext(ctx context.Context, id int) error {
+	return htm.setArchived(ctx, id, false)
+}
+
+// GetArchivedTasksContext returns the archived tasks
+func (htm *HybridTaskManager) GetArchivedTasksContext(ctx context.Context) ([]Task, error) {
+	return htm.listTasks(ctx, func(repository database.Repository) ([]database.DatabaseTask, error) {
+		return repository.GetArchivedTasks()
+	}, htm.memoryManager.GetArchivedTasks)
+}
+
+func (htm *HybridTaskManager) setArchived(ctx context.Context, id int, archived bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	htm.mu.Lock()
+	defer htm.mu.Unlock()
+
+	switch htm.storageType {
+	case DatabaseStorage, HybridStorage:
+		if err := htm.setDatabaseArchived(ctx, id, archived); err != nil {
+			return err
+		}
+		htm.refreshMemory(htm.repository.WithContext(ctx), []int{id})
+		return nil
+
+	default:
+		return htm.memoryManager.setArchived(id, archived)
+	}
+}
+
+func (htm *HybridTaskManager) setDatabaseArchived(ctx context.Context, id int, archived bool) error {
+	repository := htm.repository.WithContext(ctx)
+	dbTask, err := repository.GetTask(id)
+	if err != nil {
+		return err
+	}
+
+	return setDatabaseTaskArchived(repository, htm.events, dbTask, archived)
+}
+
+// ArchivePolicy configures automatic archiving of completed tasks
+type ArchivePolicy struct {
+	// CompletedFor is how long a task must have been completed before it is archived
+	CompletedFor time.Duration
+	// Interval is how often the policy is applied
+	Interval time.Duration
+}
+
+// Validate checks that the policy can be run
+func (p ArchivePolicy) Validate() error {
+	if p.CompletedFor <= 0 {
+		return errors.New("archive age must be positive")
+	}
+	if p.Interval <= 0 {
+		return errors.New("archive interval must be positive")
+	}
+	return nil
+}
+
+// AutoArchiver periodically archives tasks that were completed long ago
+type AutoArchiver struct {
+	repository database.Repository
+	policy     ArchivePolicy
+	events     *EventBus
+	ctx        context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// NewAutoArchiver creates an auto-archiver for the given policy
+func NewAutoArchiver(repository database.Repository, policy ArchivePolicy) *AutoArchiver {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &AutoArchiver{
+		repository: repository,
+		policy:     policy,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// SetEventBus sets the bus that archived tasks are published to
+func (a *AutoArchiver) SetEventBus(bus *EventBus) {
+	a.events = bus
+}
+
+// Start applies the policy once and then on every interval until Stop is called
+func (a *AutoArchiver) Start() {
+	runPeriodically(a.ctx, &a.wg, a.policy.Interval, func() {
+		if archived, err := a.ArchiveCompletedTasks(time.Now()); err != nil {
+			log.Printf("Auto-archive failed: %v", err)
+		} else if archived > 0 {
+			log.Printf("Auto-archived %d completed tasks", archived)
+		}
+	})
+}
+
+// runPeriodically calls run right away and then on every interval until ctx
+// is cancelled. wg tracks the goroutine so that callers can wait for it.
+func runPeriodically(ctx context.Context, wg *sync.WaitGroup, interval time.Duration, run func()) {
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			run()
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the auto-archiver and waits for a running pass to finish
+func (a *AutoArchiver) Stop() {
+	a.cancel()
+	a.wg.Wait()
+}
+
+// ArchiveCompletedTasks archives every task completed more than
+// policy.CompletedFor before now and returns how many were archived
+func (a *AutoArchiver) ArchiveCompletedTasks(now time.Time) (int, error) {
+	repository := a.repository.WithContext(a.ctx)
+
+	dbTasks, err := repository.GetCompletedTasksBefore(now.Add(-a.policy.CompletedFor))
+	if err != nil {
+		return 0, err
+	}
+
+	archived := 0
+	for i := range dbTasks {
+		if err := setDatabaseTaskArchived(repository, a.events, &dbTasks[i], true); err != nil {
+			return archived, err
+		}
+		archived++
+	}
+
+	return archived, nil
+}
@@ -0,0 +1,81 @@
+package task
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTaskManagerArchive(t *testing.T) {
+	tm := NewTaskManager()
+	kept := tm.AddTask("Keep", "", High, nil)
+	old := tm.AddTask("Old", "", Low, nil)
+
+	if err := tm.ArchiveTask(old.ID); err != nil {
+		t.Fatalf("Failed to archive task: %v", err)
+	}
+	if err := tm.ArchiveTask(42); err == nil {
+		t.Error("Expected error when archiving a missing task")
+	}
+
+	all := tm.GetAllTasks()
+	if len(all) != 1 || all[0].ID != kept.ID {
+		t.Errorf("Expected only the unarchived task, got %+v", all)
+	}
+	if len(tm.GetTasksByPriority(Low)) != 0 || len(tm.SortTasksByPriority()) != 1 {
+		t.Error("Expected archived tasks to be left out of filtered listings")
+	}
+
+	archived := tm.GetArchivedTasks()
+	if len(archived) != 1 || archived[0].ID != old.ID || !archived[0].IsArchived {
+		t.Errorf("Expected the archived task, got %+v", archived)
+	}
+
+	if err := tm.UnarchiveTask(old.ID); err != nil {
+		t.Fatalf("Failed to unarchive task: %v", err)
+	}
+	if len(tm.GetAllTasks()) != 2 {
+		t.Error("Expected the unarchived task to be listed again")
+	}
+}
+
+func TestAutoArchiverArchivesOldCompletedTasks(t *testing.T) {
+	repository := setupTestRepository(t)
+	htm := NewHybridTaskManager(repository, DatabaseStorage)
+
+	done := htm.AddTask("Done", "", Medium, nil)
+	open := htm.AddTask("Open", "", Medium, nil)
+	if err := htm.UpdateTaskStatus(done.ID, Completed); err != nil {
+		t.Fatalf("Failed to complete task: %v", err)
+	}
+
+	archiver := NewAutoArchiver(repository, ArchivePolicy{CompletedFor: 7 * 24 * time.Hour, Interval: time.Hour})
+	bus := NewEventBus()
+	archiver.SetEventBus(bus)
+	var events recorder
+	bus.Subscribe(events.handle)
+
+	// Nothing has been completed for a week yet
+	count, err := archiver.ArchiveCompletedTasks(time.Now())
+	if err != nil || count != 0 {
+		t.Fatalf("Expected nothing to be archived, got %d (%v)", count, err)
+	}
+
+	count, err = archiver.ArchiveCompletedTasks(time.Now().Add(8 * 24 * time.Hour))
+	if err != nil || count != 1 {
+		t.Fatalf("Expected one task to be archived, got %d (%v)", count, err)
+	}
+	assertEventTypes(t, events.types(), EventTaskUpdated)
+
+	all := htm.GetAllTasks()
+	if len(all) != 1 || all[0].ID != open.ID {
+		t.Errorf("Expected only the open task to be listed, got %+v", all)
+	}
+	archived := htm.GetArchivedTasks()
+	if len(archived) != 1 || archived[0].ID != done.ID {
+		t.Errorf("Expected the completed task to be archived, got %+v", archived)
+	}
+
+	if err := (ArchivePolicy{CompletedFor: time.Hour}).Validate(); err == nil {
+		t.Error("Expected a policy without interval to be invalid")
+	}
+}
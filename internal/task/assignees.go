@@ -0,0 +1,82 @@
+package task
+
+import (
+	"fmt"
+
+	"learn-go-capstone/internal/database"
+)
+
+// AssigneeRole is the part a user other than the owner takes in a task
+type AssigneeRole string
+
+const (
+	// RoleAssignee works on the task and can change its status
+	RoleAssignee AssigneeRole = "assignee"
+	// RoleReviewer reviews the task and can change its status
+	RoleReviewer AssigneeRole = "reviewer"
+	// RoleWatcher only follows the task
+	RoleWatcher AssigneeRole = "watcher"
+)
+
+// ParseAssigneeRole parses the name of an assignee role
+func ParseAssigneeRole(value string) (AssigneeRole, error) {
+	switch role := AssigneeRole(value); role {
+	case RoleAssignee, RoleReviewer, RoleWatcher:
+		return role, nil
+	default:
+		return "", fmt.Errorf("invalid assignee role %q: must be assignee, reviewer or watcher", value)
+	}
+}
+
+// CanUpdateStatus reports whether users with the role may change the status
+// of the task
+func (r AssigneeRole) CanUpdateStatus() bool {
+	return r == RoleAssignee || r == RoleReviewer
+}
+
+// Assignee is a user taking part in a task. Everyone on a task, whatever the
+// role, can read it and is notified when its status changes or it is overdue.
+type Assignee struct {
+	UserID   int          `json:"user_id"`
+	Username string       `json:"username"`
+	Role     AssigneeRole `json:"role"`
+}
+
+func convertFromDatabaseAssignees(dbAssignees []database.TaskAssignee) []Assignee {
+	if len(dbAssignees) == 0 {
+		return nil
+	}
+
+	assignees := make([]Assignee, len(dbAssignees))
+	for i, dbAssignee := range dbAssignees {
+		assignees[i] = Assignee{
+			UserID:   dbAssignee.UserID,
+			Username: dbAssignee.Username,
+			Role:     AssigneeRole(dbAssignee.Role),
+		}
+	}
+	return assignees
+}
+
+// taskWatchers returns the owner of a task and every user taking part in it
+func taskWatchers(repository database.Repository, dbTask *database.DatabaseTask) ([]int, error) {
+	var watchers []int
+	seen := make(map[int]bool)
+	if dbTask.UserID != nil {
+		watchers = append(watchers, *dbTask.UserID)
+		seen[*dbTask.UserID] = true
+	}
+
+	assignees, err := repository.GetTaskAssignees(dbTask.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, assignee := range assignees {
+		if !seen[assignee.UserID] {
+			seen[assignee.UserID] = true
+			watchers = append(watchers, assignee.UserID)
+		}
+	}
+
+	return watchers, nil
+}
@@ -0,0 +1,142 @@
+package task
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"learn-go-capstone/internal/notifications"
+)
+
+func TestTaskAssignees(t *testing.T) {
+	repository := setupTestRepository(t)
+	um := NewUserManager(repository)
+
+	owner, err := um.RegisterUser("owner", "owner@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	dev, err := um.RegisterUser("dev", "dev@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	qa, err := um.RegisterUser("qa", "qa@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	boss, err := um.RegisterUser("boss", "boss@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	outsider, err := um.RegisterUser("outsider", "outsider@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+
+	created, err := um.CreateUserTask(owner.ID, "Ship release", "", High, nil)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	invalid := [][]Assignee{
+		{{Username: "nobody", Role: RoleAssignee}},
+		{{Username: "dev", Role: "lead"}},
+		{{Username: "dev", Role: RoleAssignee}, {Username: "dev", Role: RoleWatcher}},
+	}
+	for _, assignees := range invalid {
+		if _, err := um.SetUserTaskAssignees(owner.ID, created.ID, assignees); err == nil {
+			t.Errorf("Expected error for assignees %+v", assignees)
+		}
+	}
+	if _, err := um.SetUserTaskAssignees(dev.ID, created.ID, []Assignee{{Username: "dev", Role: RoleAssignee}}); err == nil {
+		t.Error("Expected error when a non-owner sets assignees")
+	}
+
+	updated, err := um.SetUserTaskAssignees(owner.ID, created.ID, []Assignee{
+		{Username: "dev", Role: RoleAssignee},
+		{Username: "qa", Role: RoleReviewer},
+		{UserID: boss.ID, Role: RoleWatcher},
+	})
+	if err != nil {
+		t.Fatalf("Failed to set assignees: %v", err)
+	}
+	want := []Assignee{
+		{UserID: dev.ID, Username: "dev", Role: RoleAssignee},
+		{UserID: qa.ID, Username: "qa", Role: RoleReviewer},
+		{UserID: boss.ID, Username: "boss", Role: RoleWatcher},
+	}
+	if !reflect.DeepEqual(updated.Assignees, want) {
+		t.Errorf("Expected assignees %+v, got %+v", want, updated.Assignees)
+	}
+
+	// Everyone on the task can read it, nobody else can
+	for _, userID := range []int{dev.ID, qa.ID, boss.ID} {
+		if _, err := um.GetVisibleTask(userID, created.ID); err != nil {
+			t.Errorf("Expected user %d to see the task: %v", userID, err)
+		}
+	}
+	if _, err := um.GetVisibleTask(outsider.ID, created.ID); err == nil {
+		t.Error("Expected error when an outsider reads the task")
+	}
+
+	// Assignees and reviewers can move the task, watchers only follow it
+	if err := um.UpdateUserTaskStatus(dev.ID, created.ID, InProgress); err != nil {
+		t.Errorf("Expected the assignee to change the status: %v", err)
+	}
+	if err := um.UpdateUserTaskStatus(boss.ID, created.ID, Completed); err == nil {
+		t.Error("Expected error when a watcher changes the status")
+	}
+	if err := um.UpdateUserTaskStatus(outsider.ID, created.ID, Completed); err == nil {
+		t.Error("Expected error when an outsider changes the status")
+	}
+
+	reviews, err := um.GetAssignedTasks(qa.ID, RoleReviewer)
+	if err != nil || len(reviews) != 1 || reviews[0].ID != created.ID {
+		t.Errorf("Expected the task among qa's reviews, got %+v (%v)", reviews, err)
+	}
+	if watched, err := um.GetAssignedTasks(qa.ID, RoleWatcher); err != nil || len(watched) != 0 {
+		t.Errorf("Expected qa to watch nothing, got %+v (%v)", watched, err)
+	}
+	if all, err := um.GetAssignedTasks(boss.ID, ""); err != nil || len(all) != 1 {
+		t.Errorf("Expected boss to take part in one task, got %+v (%v)", all, err)
+	}
+	if _, err := um.GetAssignedTasks(boss.ID, "lead"); err == nil {
+		t.Error("Expected error for an unknown role")
+	}
+
+	history, err := um.GetUserTaskHistory(owner.ID, created.ID)
+	if err != nil {
+		t.Fatalf("Failed to get history: %v", err)
+	}
+	recorded := false
+	for _, entry := range history {
+		if entry.Field == "assignees" {
+			recorded = true
+		}
+	}
+	if !recorded {
+		t.Errorf("Expected the assignee change in the history, got %+v", history)
+	}
+
+	// Watchers are notified about status changes and overdue tasks
+	notificationService := notifications.NewNotificationService(repository, notifications.NotificationConfig{})
+	defer notificationService.Stop()
+	nm := NewNotificationManager(repository, notificationService)
+	var notified []int
+	nm.notifyWatchers(created.ID, func(userID int) error {
+		notified = append(notified, userID)
+		return nil
+	})
+	sort.Ints(notified)
+	if wantNotified := []int{owner.ID, dev.ID, qa.ID, boss.ID}; !reflect.DeepEqual(notified, wantNotified) {
+		t.Errorf("Expected %v to be notified, got %v", wantNotified, notified)
+	}
+
+	cleared, err := um.SetUserTaskAssignees(owner.ID, created.ID, nil)
+	if err != nil || len(cleared.Assignees) != 0 {
+		t.Errorf("Expected the assignees to be removed, got %+v (%v)", cleared, err)
+	}
+	if _, err := um.GetVisibleTask(dev.ID, created.ID); err == nil {
+		t.Error("Expected a removed assignee to lose access")
+	}
+}
@@ -0,0 +1,298 @@
+package task
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"learn-go-capstone/internal/attachments"
+	"learn-go-capstone/internal/database"
+)
+
+// maxAttachmentNameLength bounds the file name kept for an attachment, in bytes
+const maxAttachmentNameLength = 255
+
+// unreferencedGracePeriod is how long content stays in the store after it was
+// last stored, even once no attachment refers to it. An upload of the same
+// content may have stored it and not recorded its attachment yet.
+const unreferencedGracePeriod = 10 * time.Minute
+
+// Attachment is a file attached to a task
+type Attachment struct {
+	ID          int       `json:"id"`
+	TaskID      int       `json:"task_id"`
+	UploaderID  *int      `json:"uploader_id,omitempty"`
+	Uploader    string    `json:"uploader,omitempty"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	SHA256      string    `json:"sha256"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func convertFromDatabaseAttachment(da *database.Attachment) Attachment {
+	return Attachment{
+		ID:          da.ID,
+		TaskID:      da.TaskID,
+		UploaderID:  da.UserID,
+		Uploader:    da.Username,
+		FileName:    da.FileName,
+		ContentType: da.ContentType,
+		Size:        da.Size,
+		SHA256:      da.SHA256,
+		CreatedAt:   da.CreatedAt,
+	}
+}
+
+// cleanAttachmentName keeps the base name of an uploaded file, as clients
+// may send a full path
+func cleanAttachmentName(fileName string) (string, error) {
+	fileName = strings.ReplaceAll(fileName, "\\", "/")
+	fileName = strings.TrimSpace(filepath.Base(fileName))
+	if fileName == "" || fileName == "." || fileName == "/" {
+		return "", errors.New("attachment file name cannot be empty")
+	}
+	if len(fileName) > maxAttachmentNameLength {
+		return "", fmt.Errorf("attachment file name cannot be longer than %d characters", maxAttachmentNameLength)
+	}
+	return fileName, nil
+}
+
+// AttachmentManager manages files attached to tasks. The contents are kept in
+// a content-addressed store. Everyone who can see a task can read its
+// attachments and add to them; an attachment is removed by its uploader or
+// the owner of the task.
+type AttachmentManager struct {
+	repository database.Repository
+	store      *attachments.Store
+	limits     attachments.Limits
+}
+
+// NewAttachmentManager creates a new attachment manager
+func NewAttachmentManager(repository database.Repository, store *attachments.Store, limits attachments.Limits) *AttachmentManager {
+	return &AttachmentManager{
+		repository: repository,
+		store:      store,
+		limits:     limits,
+	}
+}
+
+// WithContext returns a copy of the attachment manager whose queries run with ctx
+func (am *AttachmentManager) WithContext(ctx context.Context) *AttachmentManager {
+	if am.repository == nil {
+		return am
+	}
+
+	bound := *am
+	bound.repository = am.repository.WithContext(ctx)
+	return &bound
+}
+
+// Limits returns the limits uploads are checked against
+func (am *AttachmentManager) Limits() attachments.Limits {
+	return am.limits
+}
+
+// visibleTask returns a task the user can see
+func (am *AttachmentManager) visibleTask(userID, taskID int) (*database.DatabaseTask, error) {
+	dbTask, err := am.repository.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if visible, err := canSeeTask(am.repository, userID, dbTask); err != nil {
+		return nil, err
+	} else if !visible {
+		return nil, errors.New("access denied: task does not belong to user or user ID is missing")
+	}
+
+	return dbTask, nil
+}
+
+// taskAttachment returns an attachment of a task the user can see, together
+// with the task
+func (am *AttachmentManager) taskAttachment(userID, taskID, attachmentID int) (*database.DatabaseTask, *database.Attachment, error) {
+	dbTask, err := am.visibleTask(userID, taskID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attachment, err := am.repository.GetAttachment(attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if attachment.TaskID != taskID {
+		return nil, nil, fmt.Errorf("attachment with ID %d not found", attachmentID)
+	}
+
+	return dbTask, attachment, nil
+}
+
+// AddAttachment stores content as a new attachment of a task. The MIME type
+// is detected from the content and checked against the limits, as is the size.
+func (am *AttachmentManager) AddAttachment(userID, taskID int, fileName string, content io.Reader) (*Attachment, error) {
+	fileName, err := cleanAttachmentName(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := am.visibleTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(content)
+	head, err := reader.Peek(512)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+
+	contentType := attachments.DetectContentType(fileName, head)
+	if err := am.limits.CheckType(contentType); err != nil {
+		return nil, err
+	}
+
+	blob, err := am.store.Put(reader, am.limits.MaxSize)
+	if err != nil {
+		if errors.Is(err, attachments.ErrTooLarge) {
+			return nil, fmt.Errorf("attachment is larger than the limit of %d bytes", am.limits.MaxSize)
+		}
+		return nil, err
+	}
+
+	dbAttachment := &database.Attachment{
+		TaskID:      taskID,
+		UserID:      &userID,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        blob.Size,
+		SHA256:      blob.SHA256,
+	}
+	if err := am.repository.CreateAttachment(dbAttachment); err != nil {
+		am.removeIfUnreferenced(blob.SHA256)
+		return nil, err
+	}
+
+	created, err := am.repository.GetAttachment(dbAttachment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := convertFromDatabaseAttachment(created)
+	return &attachment, nil
+}
+
+// GetAttachments returns the attachments of a task, oldest first
+func (am *AttachmentManager) GetAttachments(userID, taskID int) ([]Attachment, error) {
+	if _, err := am.visibleTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	dbAttachments, err := am.repository.GetTaskAttachments(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Attachment, len(dbAttachments))
+	for i := range dbAttachments {
+		result[i] = convertFromDatabaseAttachment(&dbAttachments[i])
+	}
+
+	return result, nil
+}
+
+// OpenAttachment returns an attachment of a task together with its content.
+// The caller must close the content.
+func (am *AttachmentManager) OpenAttachment(userID, taskID, attachmentID int) (*Attachment, io.ReadCloser, error) {
+	_, dbAttachment, err := am.taskAttachment(userID, taskID, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := am.store.Open(dbAttachment.SHA256)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attachment := convertFromDatabaseAttachment(dbAttachment)
+	return &attachment, content, nil
+}
+
+// DeleteAttachment removes an attachment of a task that the user uploaded or
+// whose task the user owns. Its content is removed from the store unless
+// another attachment shares it.
+func (am *AttachmentManager) DeleteAttachment(userID, taskID, attachmentID int) error {
+	dbTask, dbAttachment, err := am.taskAttachment(userID, taskID, attachmentID)
+	if err != nil {
+		return err
+	}
+
+	isOwner := dbTask.UserID != nil && *dbTask.UserID == userID
+	isUploader := dbAttachment.UserID != nil && *dbAttachment.UserID == userID
+	if !isOwner && !isUploader {
+		return errors.New("access denied: attachment was uploaded by another user")
+	}
+
+	if err := am.repository.DeleteAttachment(attachmentID); err != nil {
+		return err
+	}
+
+	am.removeIfUnreferenced(dbAttachment.SHA256)
+	return nil
+}
+
+// removeIfUnreferenced removes content that no attachment refers to, unless
+// it was stored within unreferencedGracePeriod. Failures are only logged:
+// the content is left for RemoveUnreferencedFiles.
+func (am *AttachmentManager) removeIfUnreferenced(sum string) {
+	count, err := am.repository.CountAttachmentsBySHA(sum)
+	if err == nil && count == 0 {
+		_, err = am.store.RemoveUnmodifiedSince(sum, time.Now().Add(-unreferencedGracePeriod))
+	}
+	if err != nil {
+		log.Printf("Failed to clean up attachment content %s: %v", sum, err)
+	}
+}
+
+// RemoveUnreferencedFiles removes stored content that no attachment refers to
+// anymore, such as the files of purged tasks, and returns how many files were
+// removed. Content modified after before is kept, as an upload may still be
+// about to record it.
+func (am *AttachmentManager) RemoveUnreferencedFiles(before time.Time) (int, error) {
+	blobs, err := am.store.List()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, blob := range blobs {
+		if !blob.ModTime.Before(before) {
+			continue
+		}
+
+		count, err := am.repository.CountAttachmentsBySHA(blob.SHA256)
+		if err != nil {
+			return removed, err
+		}
+		if count > 0 {
+			continue
+		}
+
+		// The content may have been stored again since it was listed
+		ok, err := am.store.RemoveUnmodifiedSince(blob.SHA256, before)
+		if err != nil {
+			return removed, err
+		}
+		if ok {
+			removed++
+		}
+	}
+
+	return removed, nil
+}
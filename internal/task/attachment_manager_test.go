@@ -0,0 +1,199 @@
+package task
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"learn-go-capstone/internal/attachments"
+	"learn-go-capstone/internal/export"
+)
+
+func TestAttachmentManager(t *testing.T) {
+	repository := setupTestRepository(t)
+	um := NewUserManager(repository)
+
+	store, err := attachments.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	am := NewAttachmentManager(repository, store, attachments.Limits{MaxSize: 64, AllowedTypes: []string{"text/*"}})
+
+	owner, err := um.RegisterUser("owner", "owner@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	other, err := um.RegisterUser("other", "other@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+
+	spec, _ := um.CreateUserTask(owner.ID, "Spec", "", Medium, nil)
+	review, _ := um.CreateUserTask(owner.ID, "Review", "", Medium, nil)
+
+	if _, err := am.AddAttachment(other.ID, spec.ID, "notes.txt", strings.NewReader("notes")); err == nil {
+		t.Error("Expected error when attaching to another user's task")
+	}
+	if _, err := am.AddAttachment(owner.ID, spec.ID, "image.png", bytes.NewReader([]byte("\x89PNG\r\n\x1a\n"))); err == nil {
+		t.Error("Expected error for a type that is not allowed")
+	}
+	if _, err := am.AddAttachment(owner.ID, spec.ID, "big.txt", strings.NewReader(strings.Repeat("a", 65))); err == nil {
+		t.Error("Expected error for content over the size limit")
+	}
+
+	first, err := am.AddAttachment(owner.ID, spec.ID, `C:\drafts\notes.txt`, strings.NewReader("shared notes"))
+	if err != nil {
+		t.Fatalf("Failed to add attachment: %v", err)
+	}
+	if first.FileName != "notes.txt" || first.Uploader != "owner" || first.Size != 12 {
+		t.Errorf("Unexpected attachment %+v", first)
+	}
+	second, err := am.AddAttachment(owner.ID, review.ID, "copy.txt", strings.NewReader("shared notes"))
+	if err != nil {
+		t.Fatalf("Failed to add attachment: %v", err)
+	}
+	if second.SHA256 != first.SHA256 {
+		t.Error("Expected identical content to share a digest")
+	}
+	if blobs, _ := store.List(); len(blobs) != 1 {
+		t.Errorf("Expected identical content to be stored once, got %+v", blobs)
+	}
+
+	if _, _, err := am.OpenAttachment(owner.ID, review.ID, first.ID); err == nil {
+		t.Error("Expected error when opening an attachment through another task")
+	}
+	attachment, content, err := am.OpenAttachment(owner.ID, spec.ID, first.ID)
+	if err != nil {
+		t.Fatalf("Failed to open attachment: %v", err)
+	}
+	data, _ := io.ReadAll(content)
+	content.Close()
+	if attachment.ID != first.ID || string(data) != "shared notes" {
+		t.Errorf("Expected the attached content, got %+v %q", attachment, data)
+	}
+
+	// Participants read and add attachments, but only remove their own
+	if _, err := um.SetUserTaskAssignees(owner.ID, review.ID, []Assignee{{Username: "other", Role: RoleWatcher}}); err != nil {
+		t.Fatalf("Failed to set assignees: %v", err)
+	}
+	if list, err := am.GetAttachments(other.ID, review.ID); err != nil || len(list) != 1 {
+		t.Errorf("Expected the watcher to see the attachment, got %+v (%v)", list, err)
+	}
+	if err := am.DeleteAttachment(other.ID, review.ID, second.ID); err == nil || err.Error() != "access denied: attachment was uploaded by another user" {
+		t.Errorf("Expected the watcher not to remove the owner's attachment, got %v", err)
+	}
+	own, err := am.AddAttachment(other.ID, review.ID, "feedback.txt", strings.NewReader("shared notes"))
+	if err != nil {
+		t.Fatalf("Failed to add attachment as watcher: %v", err)
+	}
+	if err := am.DeleteAttachment(other.ID, review.ID, own.ID); err != nil {
+		t.Errorf("Expected the watcher to remove their own attachment, got %v", err)
+	}
+
+	// The content stays while the other attachment still refers to it
+	if err := am.DeleteAttachment(owner.ID, spec.ID, first.ID); err != nil {
+		t.Fatalf("Failed to delete attachment: %v", err)
+	}
+	if blobs, _ := store.List(); len(blobs) != 1 {
+		t.Errorf("Expected shared content to be kept, got %+v", blobs)
+	}
+	if list, err := am.GetAttachments(owner.ID, spec.ID); err != nil || len(list) != 0 {
+		t.Errorf("Expected no attachments left on the spec, got %+v (%v)", list, err)
+	}
+
+	// Content stored moments ago may belong to an upload that is still being
+	// recorded, so it is left for the sweep
+	if err := am.DeleteAttachment(owner.ID, review.ID, second.ID); err != nil {
+		t.Fatalf("Failed to delete attachment: %v", err)
+	}
+	if blobs, _ := store.List(); len(blobs) != 1 {
+		t.Errorf("Expected recently stored content to be kept, got %+v", blobs)
+	}
+	if removed, err := am.RemoveUnreferencedFiles(time.Now().Add(time.Second)); err != nil || removed != 1 {
+		t.Errorf("Expected the sweep to remove the content, got %d (%v)", removed, err)
+	}
+	if blobs, _ := store.List(); len(blobs) != 0 {
+		t.Errorf("Expected unreferenced content to be removed, got %+v", blobs)
+	}
+}
+
+func TestPurgeRemovesAttachmentFiles(t *testing.T) {
+	repository := setupTestRepository(t)
+	um := NewUserManager(repository)
+
+	store, err := attachments.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	am := NewAttachmentManager(repository, store, attachments.Limits{})
+
+	owner, err := um.RegisterUser("owner", "owner@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	created, _ := um.CreateUserTask(owner.ID, "Old", "", Low, nil)
+	if _, err := am.AddAttachment(owner.ID, created.ID, "old.txt", strings.NewReader("old")); err != nil {
+		t.Fatalf("Failed to add attachment: %v", err)
+	}
+	if err := um.DeleteUserTask(owner.ID, created.ID); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+
+	purger := NewTrashPurger(repository, TrashPolicy{Retention: 24 * time.Hour, Interval: time.Hour})
+	purger.SetAttachmentManager(am)
+	if purged, err := purger.PurgeExpiredTasks(time.Now().Add(48 * time.Hour)); err != nil || purged != 1 {
+		t.Fatalf("Expected one purged task, got %d (%v)", purged, err)
+	}
+	if blobs, _ := store.List(); len(blobs) != 0 {
+		t.Errorf("Expected the files of purged tasks to be removed, got %+v", blobs)
+	}
+}
+
+func TestExportBackupIncludesAttachments(t *testing.T) {
+	repository := setupTestRepository(t)
+	um := NewUserManager(repository)
+
+	store, err := attachments.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	am := NewAttachmentManager(repository, store, attachments.Limits{})
+	em := NewExportManager(repository)
+	em.SetAttachmentStore(store)
+
+	owner, err := um.RegisterUser("owner", "owner@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	created, _ := um.CreateUserTask(owner.ID, "Backup me", "", Low, nil)
+	attachment, err := am.AddAttachment(owner.ID, created.ID, "log.txt", strings.NewReader("line 1\nline 2\n"))
+	if err != nil {
+		t.Fatalf("Failed to add attachment: %v", err)
+	}
+
+	result, err := em.ExportBackup()
+	if err != nil {
+		t.Fatalf("Failed to export backup: %v", err)
+	}
+	defer os.Remove(result.FilePath)
+
+	raw, err := os.ReadFile(result.FilePath)
+	if err != nil {
+		t.Fatalf("Failed to read backup: %v", err)
+	}
+	var backup export.ExportData
+	if err := json.Unmarshal(raw, &backup); err != nil {
+		t.Fatalf("Failed to decode backup: %v", err)
+	}
+
+	if len(backup.Attachments) != 1 || backup.Metadata.TotalAttachments != 1 {
+		t.Fatalf("Expected one attachment in the backup, got %+v", backup.Attachments)
+	}
+	if got := backup.Attachments[0]; got.ID != attachment.ID || got.SHA256 != attachment.SHA256 || string(got.Content) != "line 1\nline 2\n" {
+		t.Errorf("Expected the attachment with its content, got %+v", got)
+	}
+}
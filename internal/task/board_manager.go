@@ -0,0 +1,635 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"learn-go-capstone/internal/database"
+)
+
+// maxBoardNameLength bounds the names of boards and their columns, in characters
+const maxBoardNameLength = 100
+
+// BoardColumn is a column of a kanban board. It shows the tasks with its tag
+// or, when it has no tag, the tasks with its status. A column with a
+// WIPLimit holds at most that many cards; status changes that would pass it
+// are refused wherever they are made, not only by card moves.
+type BoardColumn struct {
+	ID       int     `json:"id"`
+	Name     string  `json:"name"`
+	Status   *Status `json:"status,omitempty"`
+	TagID    *int    `json:"tag_id,omitempty"`
+	TagName  string  `json:"tag_name,omitempty"`
+	WIPLimit int     `json:"wip_limit"`
+	// Cards are the tasks in the column, in their manual order
+	Cards []Task `json:"cards,omitempty"`
+}
+
+// Board is a kanban board over the tasks of its owner or, when ProjectID is
+// set, over the tasks of a project. A task appears in the first column with
+// one of its tags or, failing that, in the first column with its status; tasks
+// matching no column are not on the board. Archived tasks are left out.
+type Board struct {
+	ID        int           `json:"id"`
+	Name      string        `json:"name"`
+	OwnerID   *int          `json:"owner_id,omitempty"`
+	ProjectID *int          `json:"project_id,omitempty"`
+	Columns   []BoardColumn `json:"columns"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+func convertFromDatabaseBoardColumn(dc *database.BoardColumn) BoardColumn {
+	column := BoardColumn{
+		ID:       dc.ID,
+		Name:     dc.Name,
+		TagID:    dc.TagID,
+		TagName:  dc.TagName,
+		WIPLimit: dc.WIPLimit,
+	}
+	if dc.Status != nil {
+		status := Status(*dc.Status)
+		column.Status = &status
+	}
+	return column
+}
+
+// BoardManager manages kanban boards. Everyone who can see the tasks of a
+// board can see the board and move its cards; only the board owner and the
+// owners of its project can change or delete it.
+type BoardManager struct {
+	repository database.Repository
+	hierarchy  *HierarchyManager
+	events     *EventBus
+	// moves serializes card moves, so that two moves into the same column
+	// cannot both pass its work-in-progress limit
+	moves *sync.Mutex
+}
+
+// NewBoardManager creates a new board manager
+func NewBoardManager(repository database.Repository) *BoardManager {
+	return &BoardManager{
+		repository: repository,
+		hierarchy:  NewHierarchyManager(repository, DefaultCascadeRules()),
+		moves:      &sync.Mutex{},
+	}
+}
+
+// SetHierarchyManager replaces the hierarchy manager whose workflow and
+// complete rule card moves follow
+func (bm *BoardManager) SetHierarchyManager(hierarchy *HierarchyManager) {
+	bm.hierarchy = hierarchy
+}
+
+// SetEventBus sets the bus that the status and tag changes of card moves are published to
+func (bm *BoardManager) SetEventBus(bus *EventBus) {
+	bm.events = bus
+}
+
+// WithContext returns a copy of the board manager whose queries run with ctx
+func (bm *BoardManager) WithContext(ctx context.Context) *BoardManager {
+	if bm.repository == nil {
+		return bm
+	}
+
+	bound := *bm
+	bound.repository = bm.repository.WithContext(ctx)
+	bound.hierarchy = bm.hierarchy.WithContext(ctx)
+	return &bound
+}
+
+// userBoard returns a board the user can see
+func (bm *BoardManager) userBoard(userID, boardID int) (*database.Board, error) {
+	board, err := bm.repository.GetBoard(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := board.ProjectID == nil && board.OwnerID != nil && *board.OwnerID == userID
+	if board.ProjectID != nil {
+		role, err := projectRole(bm.repository, userID, *board.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		visible = role != ""
+	}
+
+	if !visible {
+		return nil, fmt.Errorf("board with ID %d not found", boardID)
+	}
+	return board, nil
+}
+
+// checkBoardOwner returns an error unless the user owns the board or its project
+func (bm *BoardManager) checkBoardOwner(userID int, board *database.Board) error {
+	if board.OwnerID != nil && *board.OwnerID == userID {
+		return nil
+	}
+
+	if board.ProjectID != nil {
+		role, err := projectRole(bm.repository, userID, *board.ProjectID)
+		if err != nil {
+			return err
+		}
+		if role == ProjectOwner {
+			return nil
+		}
+	}
+
+	return errors.New("access denied: only the board owner or a project owner can change the board")
+}
+
+// checkColumns validates the columns of a board of the given project, or of
+// a personal board when projectID is nil
+func (bm *BoardManager) checkColumns(projectID *int, columns []BoardColumn) ([]database.BoardColumn, error) {
+	if len(columns) == 0 {
+		return nil, errors.New("invalid board: a board needs at least one column")
+	}
+
+	workflow := bm.hierarchy.GetWorkflow()
+	statusColumns := make(map[Status]string)
+	tagColumns := make(map[int]string)
+
+	dbColumns := make([]database.BoardColumn, len(columns))
+	for i, column := range columns {
+		name := strings.TrimSpace(column.Name)
+		if name == "" {
+			return nil, errors.New("invalid board column: name cannot be empty")
+		}
+		if len([]rune(name)) > maxBoardNameLength {
+			return nil, fmt.Errorf("invalid board column %q: name cannot be longer than %d characters", name, maxBoardNameLength)
+		}
+		if column.WIPLimit < 0 {
+			return nil, fmt.Errorf("invalid board column %q: work-in-progress limit cannot be negative", name)
+		}
+		if (column.Status == nil) == (column.TagID == nil) {
+			return nil, fmt.Errorf("invalid board column %q: set either a status or a tag", name)
+		}
+
+		dbColumns[i] = database.BoardColumn{ID: column.ID, Name: name, TagID: column.TagID, WIPLimit: column.WIPLimit}
+
+		if column.Status != nil {
+			status := *column.Status
+			if !workflow.IsKnownStatus(status) {
+				return nil, fmt.Errorf("invalid board column %q: unknown status %d", name, status)
+			}
+			if other, ok := statusColumns[status]; ok {
+				return nil, fmt.Errorf("invalid board column %q: status %s is already shown in column %q", name, status, other)
+			}
+			statusColumns[status] = name
+			dbStatus := int(status)
+			dbColumns[i].Status = &dbStatus
+			continue
+		}
+
+		tag, err := bm.repository.GetTag(*column.TagID)
+		if err != nil || !sameProject(tag.ProjectID, projectID) {
+			return nil, fmt.Errorf("invalid board column %q: tag with ID %d not found", name, *column.TagID)
+		}
+		if other, ok := tagColumns[tag.ID]; ok {
+			return nil, fmt.Errorf("invalid board column %q: tag %s is already shown in column %q", name, tag.Name, other)
+		}
+		tagColumns[tag.ID] = name
+	}
+
+	return dbColumns, nil
+}
+
+// cleanBoardName trims a board name and checks its length
+func cleanBoardName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New("invalid board: name cannot be empty")
+	}
+	if len([]rune(name)) > maxBoardNameLength {
+		return "", fmt.Errorf("invalid board: name cannot be longer than %d characters", maxBoardNameLength)
+	}
+	return name, nil
+}
+
+// CreateBoard creates a board owned by the user, over their own tasks or,
+// when projectID is set, over the tasks of one of their projects
+func (bm *BoardManager) CreateBoard(userID int, name string, projectID *int, columns []BoardColumn) (*Board, error) {
+	name, err := cleanBoardName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if projectID != nil {
+		if _, err := bm.repository.GetProject(*projectID); err != nil {
+			return nil, err
+		}
+		role, err := projectRole(bm.repository, userID, *projectID)
+		if err != nil {
+			return nil, err
+		}
+		if role == "" {
+			return nil, fmt.Errorf("project with ID %d not found", *projectID)
+		}
+	}
+
+	for i := range columns {
+		columns[i].ID = 0
+	}
+	dbColumns, err := bm.checkColumns(projectID, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	dbBoard := &database.Board{Name: name, OwnerID: &userID, ProjectID: projectID}
+	if err := bm.repository.CreateBoard(dbBoard, dbColumns); err != nil {
+		return nil, err
+	}
+
+	return bm.GetBoard(userID, dbBoard.ID)
+}
+
+// GetUserBoards returns the boards the user can see, by name, without their cards
+func (bm *BoardManager) GetUserBoards(userID int) ([]Board, error) {
+	dbBoards, err := bm.repository.GetBoardsByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	boards := make([]Board, len(dbBoards))
+	for i, dbBoard := range dbBoards {
+		dbColumns, err := bm.repository.GetBoardColumns(dbBoard.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		boards[i] = Board{
+			ID:        dbBoard.ID,
+			Name:      dbBoard.Name,
+			OwnerID:   dbBoard.OwnerID,
+			ProjectID: dbBoard.ProjectID,
+			Columns:   make([]BoardColumn, len(dbColumns)),
+			CreatedAt: dbBoard.CreatedAt,
+			UpdatedAt: dbBoard.UpdatedAt,
+		}
+		for j := range dbColumns {
+			boards[i].Columns[j] = convertFromDatabaseBoardColumn(&dbColumns[j])
+		}
+	}
+	return boards, nil
+}
+
+// GetBoard returns a whole board: its columns with their cards in order
+func (bm *BoardManager) GetBoard(userID, boardID int) (*Board, error) {
+	dbBoard, err := bm.userBoard(userID, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	return bm.loadBoard(dbBoard)
+}
+
+// UpdateBoard renames a board and replaces its columns. Columns keep their
+// cards when they are passed with their ID; columns left out are removed.
+func (bm *BoardManager) UpdateBoard(userID, boardID int, name string, columns []BoardColumn) (*Board, error) {
+	dbBoard, err := bm.userBoard(userID, boardID)
+	if err != nil {
+		return nil, err
+	}
+	if err := bm.checkBoardOwner(userID, dbBoard); err != nil {
+		return nil, err
+	}
+
+	name, err = cleanBoardName(name)
+	if err != nil {
+		return nil, err
+	}
+	dbColumns, err := bm.checkColumns(dbBoard.ProjectID, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	dbBoard.Name = name
+	if err := bm.repository.UpdateBoard(dbBoard, dbColumns); err != nil {
+		return nil, err
+	}
+
+	return bm.GetBoard(userID, boardID)
+}
+
+// DeleteBoard deletes a board. Its tasks are kept.
+func (bm *BoardManager) DeleteBoard(userID, boardID int) error {
+	dbBoard, err := bm.userBoard(userID, boardID)
+	if err != nil {
+		return err
+	}
+	if err := bm.checkBoardOwner(userID, dbBoard); err != nil {
+		return err
+	}
+
+	return bm.repository.DeleteBoard(boardID)
+}
+
+// boardLayout is a board with its tasks placed in columns
+type boardLayout struct {
+	columns []database.BoardColumn
+	// cards holds the tasks of each column, in order
+	cards [][]database.DatabaseTask
+	// tags holds the tag IDs of every task on the board's scope
+	tags map[int]map[int]bool
+}
+
+// columnOf returns the index of the column a task with the given status and
+// tags appears in, or -1 when it matches no column
+func (l *boardLayout) columnOf(status int, tags map[int]bool) int {
+	for i, column := range l.columns {
+		if column.TagID != nil && tags[*column.TagID] {
+			return i
+		}
+	}
+	for i, column := range l.columns {
+		if column.TagID == nil && column.Status != nil && *column.Status == status {
+			return i
+		}
+	}
+	return -1
+}
+
+// layout places the unarchived tasks of the board's scope in its columns
+func (bm *BoardManager) layout(dbBoard *database.Board) (*boardLayout, error) {
+	columns, err := bm.repository.GetBoardColumns(dbBoard.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []database.DatabaseTask
+	if dbBoard.ProjectID != nil {
+		tasks, err = bm.repository.GetTasksByProject(*dbBoard.ProjectID)
+	} else if dbBoard.OwnerID != nil {
+		tasks, err = bm.repository.GetTasksByUser(*dbBoard.OwnerID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cards, err := bm.repository.GetBoardCards(dbBoard.ID)
+	if err != nil {
+		return nil, err
+	}
+	positions := make(map[int]database.BoardCard, len(cards))
+	for _, card := range cards {
+		positions[card.TaskID] = card
+	}
+
+	hasTagColumns := false
+	for _, column := range columns {
+		hasTagColumns = hasTagColumns || column.TagID != nil
+	}
+
+	l := &boardLayout{
+		columns: columns,
+		cards:   make([][]database.DatabaseTask, len(columns)),
+		tags:    make(map[int]map[int]bool),
+	}
+	for _, dbTask := range tasks {
+		if dbTask.IsArchived {
+			continue
+		}
+
+		l.tags[dbTask.ID] = make(map[int]bool)
+		if hasTagColumns {
+			tags, err := bm.repository.GetTaskTags(dbTask.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, tag := range tags {
+				l.tags[dbTask.ID][tag.ID] = true
+			}
+		}
+
+		if i := l.columnOf(dbTask.Status, l.tags[dbTask.ID]); i >= 0 {
+			l.cards[i] = append(l.cards[i], dbTask)
+		}
+	}
+
+	// Cards placed in their column come first, in their manual order; cards
+	// that reached the column another way follow, oldest first
+	for i, column := range columns {
+		column := column
+		placed := func(dbTask database.DatabaseTask) (int, bool) {
+			card, ok := positions[dbTask.ID]
+			return card.Position, ok && card.ColumnID == column.ID
+		}
+		sort.SliceStable(l.cards[i], func(a, b int) bool {
+			positionA, placedA := placed(l.cards[i][a])
+			positionB, placedB := placed(l.cards[i][b])
+			if placedA != placedB {
+				return placedA
+			}
+			if placedA && positionA != positionB {
+				return positionA < positionB
+			}
+			return l.cards[i][a].ID < l.cards[i][b].ID
+		})
+	}
+
+	return l, nil
+}
+
+// loadBoard converts a board with its columns and cards
+func (bm *BoardManager) loadBoard(dbBoard *database.Board) (*Board, error) {
+	l, err := bm.layout(dbBoard)
+	if err != nil {
+		return nil, err
+	}
+
+	board := &Board{
+		ID:        dbBoard.ID,
+		Name:      dbBoard.Name,
+		OwnerID:   dbBoard.OwnerID,
+		ProjectID: dbBoard.ProjectID,
+		Columns:   make([]BoardColumn, len(l.columns)),
+		CreatedAt: dbBoard.CreatedAt,
+		UpdatedAt: dbBoard.UpdatedAt,
+	}
+	for i := range l.columns {
+		board.Columns[i] = convertFromDatabaseBoardColumn(&l.columns[i])
+		board.Columns[i].Cards = convertFromDatabaseTasks(l.cards[i])
+	}
+	return board, nil
+}
+
+// MoveCard moves a task to a position in a column of a board. The task takes
+// the status or the tag of the column, and loses the tags of the other tag
+// columns, so that it appears in that column. A move into a column at its
+// work-in-progress limit is refused, as is a status change the workflow does
+// not allow; the status, the tags and the order are changed together or not at all.
+func (bm *BoardManager) MoveCard(userID, boardID, taskID, columnID, position int) (*Board, error) {
+	if position < 0 {
+		return nil, errors.New("invalid card position: position cannot be negative")
+	}
+
+	bm.moves.Lock()
+	defer bm.moves.Unlock()
+
+	dbBoard, err := bm.userBoard(userID, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := bm.layout(dbBoard)
+	if err != nil {
+		return nil, err
+	}
+
+	target := -1
+	for i, column := range l.columns {
+		if column.ID == columnID {
+			target = i
+		}
+	}
+	if target < 0 {
+		return nil, fmt.Errorf("board column with ID %d not found", columnID)
+	}
+	tags, ok := l.tags[taskID]
+	if !ok {
+		return nil, fmt.Errorf("task with ID %d not found", taskID)
+	}
+	dbTask, err := bm.repository.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	column := l.columns[target]
+	var order []int
+	alreadyInColumn := false
+	for _, card := range l.cards[target] {
+		if card.ID == taskID {
+			alreadyInColumn = true
+			continue
+		}
+		order = append(order, card.ID)
+	}
+	if column.WIPLimit > 0 && !alreadyInColumn && len(order) >= column.WIPLimit {
+		return nil, fmt.Errorf("work-in-progress limit reached: column %q already has %d cards", column.Name, column.WIPLimit)
+	}
+	if position > len(order) {
+		position = len(order)
+	}
+	order = append(order[:position], append([]int{taskID}, order[position:]...)...)
+
+	move := &database.BoardCardMove{BoardID: boardID, ColumnID: columnID, TaskID: taskID, Order: order}
+	for _, other := range l.columns {
+		if other.TagID != nil && other.ID != columnID && tags[*other.TagID] {
+			move.RemoveTagIDs = append(move.RemoveTagIDs, *other.TagID)
+		}
+	}
+	if column.TagID != nil && !tags[*column.TagID] {
+		move.AddTagID = column.TagID
+	}
+	if column.TagID == nil && column.Status != nil && *column.Status != dbTask.Status {
+		if err := bm.hierarchy.checkStandaloneStatus(dbTask, Status(*column.Status)); err != nil {
+			return nil, err
+		}
+		move.Status = column.Status
+	}
+
+	// The move and the next occurrence of a completed recurring task are
+	// stored together; events are published once both are committed
+	var published []Event
+	buffer := NewEventBus()
+	buffer.Subscribe(func(event Event) { published = append(published, event) })
+
+	err = bm.repository.Transaction(func(tx database.Repository) error {
+		if err := tx.MoveBoardCard(move); err != nil {
+			return err
+		}
+		return publishMove(tx, buffer, dbTask, move)
+	})
+	if err != nil {
+		return nil, err
+	}
+	bm.events.Publish(published...)
+
+	return bm.loadBoard(dbBoard)
+}
+
+// checkWIPLimits refuses a status change that would bring a task into a
+// status column at its work-in-progress limit on any board showing the task,
+// so that the limits hold however the status is changed. Tasks shown in a tag
+// column stay there whatever their status.
+func checkWIPLimits(repository database.Repository, workflow *Workflow, dbTask *database.DatabaseTask, status Status) error {
+	if dbTask.IsArchived || Status(dbTask.Status) == status {
+		return nil
+	}
+
+	boards, err := repository.GetBoardsByTask(dbTask.ID)
+	if err != nil {
+		return err
+	}
+
+	bm := &BoardManager{repository: repository}
+	for i := range boards {
+		l, err := bm.layout(&boards[i])
+		if err != nil {
+			return err
+		}
+		tags, ok := l.tags[dbTask.ID]
+		if !ok {
+			continue
+		}
+
+		target := l.columnOf(int(status), tags)
+		if target < 0 || target == l.columnOf(dbTask.Status, tags) {
+			continue
+		}
+		column := l.columns[target]
+		if column.WIPLimit > 0 && len(l.cards[target]) >= column.WIPLimit {
+			return &TransitionError{
+				TaskID:   dbTask.ID,
+				From:     Status(dbTask.Status),
+				To:       status,
+				FromName: workflow.StatusName(Status(dbTask.Status)),
+				ToName:   workflow.StatusName(status),
+				Guard:    "work-in-progress limit",
+				Reason:   fmt.Sprintf("column %q of board %q already has %d cards", column.Name, boards[i].Name, column.WIPLimit),
+			}
+		}
+	}
+	return nil
+}
+
+// publishMove publishes the events of a card move and, when the move
+// completed a recurring task, creates its next occurrence
+func publishMove(repository database.Repository, events *EventBus, before *database.DatabaseTask, move *database.BoardCardMove) error {
+	if move.AddTagID != nil {
+		events.Publish(Event{Type: EventTagAttached, TaskID: move.TaskID, TagID: *move.AddTagID})
+	}
+	if move.Status == nil {
+		return nil
+	}
+
+	dbTask, err := repository.GetTask(move.TaskID)
+	if err != nil {
+		return err
+	}
+	oldStatus := Status(before.Status)
+	events.Publish(statusChangedEvent(convertFromDatabaseTask(dbTask), dbTask.UserID, oldStatus))
+
+	if Status(dbTask.Status) == Completed && oldStatus != Completed {
+		next, err := createNextDatabaseOccurrence(repository, dbTask)
+		if err != nil {
+			return err
+		}
+		if next != nil {
+			// The next occurrence takes over the rule, as it does in
+			// setDatabaseTaskStatus, so that the task cannot spawn another one
+			dbTask.RecurrenceRule = nil
+			if err := repository.UpdateTask(dbTask); err != nil {
+				return err
+			}
+			events.Publish(databaseTaskEvent(EventTaskCreated, next))
+		}
+	}
+	return nil
+}
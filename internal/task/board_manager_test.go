@@ -0,0 +1,223 @@
+package task
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// cardIDs returns the task IDs of the cards in each column of a board
+func cardIDs(board *Board) [][]int {
+	ids := make([][]int, len(board.Columns))
+	for i, column := range board.Columns {
+		ids[i] = []int{}
+		for _, card := range column.Cards {
+			ids[i] = append(ids[i], card.ID)
+		}
+	}
+	return ids
+}
+
+func TestBoards(t *testing.T) {
+	repository := setupTestRepository(t)
+	um := NewUserManager(repository)
+	cm := NewCategoryManager(repository)
+	bm := NewBoardManager(repository)
+
+	owner, err := um.RegisterUser("owner", "owner@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	outsider, err := um.RegisterUser("outsider", "outsider@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	blocked, err := cm.CreateTag("blocked", "")
+	if err != nil {
+		t.Fatalf("Failed to create tag: %v", err)
+	}
+
+	pending, inProgress, completed, cancelled := Pending, InProgress, Completed, Cancelled
+	invalid := [][]BoardColumn{
+		nil,
+		{{Name: "Both", Status: &pending, TagID: &blocked.ID}},
+		{{Name: "Neither"}},
+		{{Name: "Todo", Status: &pending}, {Name: "Backlog", Status: &pending}},
+		{{Name: "Todo", Status: &pending, WIPLimit: -1}},
+	}
+	for _, columns := range invalid {
+		if _, err := bm.CreateBoard(owner.ID, "Invalid", nil, columns); err == nil {
+			t.Errorf("Expected error for columns %+v", columns)
+		}
+	}
+
+	board, err := bm.CreateBoard(owner.ID, "Work", nil, []BoardColumn{
+		{Name: "Todo", Status: &pending},
+		{Name: "Doing", Status: &inProgress, WIPLimit: 1},
+		{Name: "Blocked", TagID: &blocked.ID},
+		{Name: "Done", Status: &completed},
+		{Name: "Dropped", Status: &cancelled},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create board: %v", err)
+	}
+	todo, doing, blockedColumn, done, dropped := board.Columns[0].ID, board.Columns[1].ID, board.Columns[2].ID, board.Columns[3].ID, board.Columns[4].ID
+
+	var ids []int
+	for _, title := range []string{"First", "Second", "Third"} {
+		created, err := um.CreateUserTask(owner.ID, title, "", Medium, nil)
+		if err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		ids = append(ids, created.ID)
+	}
+
+	board, err = bm.GetBoard(owner.ID, board.ID)
+	if err != nil {
+		t.Fatalf("Failed to get board: %v", err)
+	}
+	if got := cardIDs(board)[0]; len(got) != 3 || got[0] != ids[0] || got[2] != ids[2] {
+		t.Errorf("Expected new tasks in creation order, got %v", got)
+	}
+
+	// Cards are ordered manually within a column
+	board, err = bm.MoveCard(owner.ID, board.ID, ids[2], todo, 0)
+	if err != nil {
+		t.Fatalf("Failed to move card: %v", err)
+	}
+	if got := cardIDs(board)[0]; got[0] != ids[2] || got[1] != ids[0] || got[2] != ids[1] {
+		t.Errorf("Expected the moved card first, got %v", got)
+	}
+
+	// Moving into a status column changes the status, up to the column limit
+	if _, err := bm.MoveCard(owner.ID, board.ID, ids[0], doing, 0); err != nil {
+		t.Fatalf("Failed to move card: %v", err)
+	}
+	started, err := um.GetVisibleTask(owner.ID, ids[0])
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if started.Status != InProgress {
+		t.Errorf("Expected status %v, got %v", InProgress, started.Status)
+	}
+	if _, err := bm.MoveCard(owner.ID, board.ID, ids[1], doing, 0); err == nil {
+		t.Error("Expected error when moving past the work-in-progress limit")
+	}
+	// The limit holds for status changes made outside the board too
+	var limitErr *TransitionError
+	if err := um.UpdateUserTaskStatus(owner.ID, ids[1], InProgress); !errors.As(err, &limitErr) || limitErr.Guard != "work-in-progress limit" {
+		t.Errorf("Expected the work-in-progress limit to refuse the status change, got %v", err)
+	}
+
+	// Moving into a tag column tags the task and frees its old column
+	board, err = bm.MoveCard(owner.ID, board.ID, ids[0], blockedColumn, 0)
+	if err != nil {
+		t.Fatalf("Failed to move card: %v", err)
+	}
+	if got := cardIDs(board); len(got[1]) != 0 || len(got[2]) != 1 || got[2][0] != ids[0] {
+		t.Errorf("Expected the task in the tag column only, got %v", got)
+	}
+	if _, err := bm.MoveCard(owner.ID, board.ID, ids[1], doing, 0); err != nil {
+		t.Errorf("Expected the column to accept a card again: %v", err)
+	}
+
+	// Leaving a tag column removes its tag
+	board, err = bm.MoveCard(owner.ID, board.ID, ids[0], done, 0)
+	if err != nil {
+		t.Fatalf("Failed to move card: %v", err)
+	}
+	tags, err := cm.GetTaskTags(ids[0])
+	if err != nil {
+		t.Fatalf("Failed to get tags: %v", err)
+	}
+	if len(tags) != 0 {
+		t.Errorf("Expected the tag to be removed, got %+v", tags)
+	}
+	if got := cardIDs(board)[3]; len(got) != 1 || got[0] != ids[0] {
+		t.Errorf("Expected the task in the done column, got %v", got)
+	}
+
+	// The workflow still applies to moves
+	if _, err := bm.MoveCard(owner.ID, board.ID, ids[2], dropped, 0); err != nil {
+		t.Fatalf("Failed to move card: %v", err)
+	}
+	var transitionErr *TransitionError
+	if _, err := bm.MoveCard(owner.ID, board.ID, ids[2], done, 0); !errors.As(err, &transitionErr) {
+		t.Errorf("Expected a transition error when completing a cancelled task, got %v", err)
+	}
+	if _, err := bm.MoveCard(owner.ID, board.ID, ids[2], todo, 0); err != nil {
+		t.Fatalf("Failed to move card: %v", err)
+	}
+
+	if _, err := bm.GetBoard(outsider.ID, board.ID); err == nil {
+		t.Error("Expected error when an outsider reads the board")
+	}
+	if _, err := bm.MoveCard(outsider.ID, board.ID, ids[2], doing, 0); err == nil {
+		t.Error("Expected error when an outsider moves a card")
+	}
+
+	// Columns kept by ID keep their cards
+	board, err = bm.UpdateBoard(owner.ID, board.ID, "Work", []BoardColumn{
+		{ID: todo, Name: "Todo", Status: &pending},
+		{ID: done, Name: "Done", Status: &completed},
+	})
+	if err != nil {
+		t.Fatalf("Failed to update board: %v", err)
+	}
+	if got := cardIDs(board); len(got) != 2 || len(got[0]) != 1 || got[0][0] != ids[2] || len(got[1]) != 1 {
+		t.Errorf("Expected the kept columns with their cards, got %v", got)
+	}
+
+	if err := bm.DeleteBoard(outsider.ID, board.ID); err == nil {
+		t.Error("Expected error when an outsider deletes the board")
+	}
+	if err := bm.DeleteBoard(owner.ID, board.ID); err != nil {
+		t.Fatalf("Failed to delete board: %v", err)
+	}
+	if _, err := um.GetVisibleTask(owner.ID, ids[0]); err != nil {
+		t.Errorf("Expected tasks to outlive the board: %v", err)
+	}
+}
+
+func TestMovingRecurringCardCreatesOneOccurrence(t *testing.T) {
+	repository := setupTestRepository(t)
+	um := NewUserManager(repository)
+	bm := NewBoardManager(repository)
+
+	owner, err := um.RegisterUser("owner", "owner@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	inProgress, completed := InProgress, Completed
+	board, err := bm.CreateBoard(owner.ID, "Chores", nil, []BoardColumn{
+		{Name: "Doing", Status: &inProgress},
+		{Name: "Done", Status: &completed},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create board: %v", err)
+	}
+	doing, done := board.Columns[0].ID, board.Columns[1].ID
+
+	dueDate := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+	rule, _ := ParseRecurrenceRule("FREQ=WEEKLY")
+	chore, err := um.CreateUserTaskWithPatch(owner.ID, "Water plants", "", Low, &dueDate, TaskPatch{Recurrence: rule})
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	// Completing, reopening and completing the task again on the board
+	// creates a single next occurrence
+	for _, column := range []int{doing, done, doing, done} {
+		if _, err := bm.MoveCard(owner.ID, board.ID, chore.ID, column, 0); err != nil {
+			t.Fatalf("Failed to move card: %v", err)
+		}
+	}
+
+	tasks, err := um.GetUserTasks(owner.ID)
+	if err != nil {
+		t.Fatalf("Failed to get tasks: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Errorf("Expected one next occurrence, got %d tasks", len(tasks))
+	}
+}
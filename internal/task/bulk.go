@@ -0,0 +1,454 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"learn-go-capstone/internal/database"
+	"learn-go-capstone/internal/search"
+)
+
+// BulkAction is the change a bulk request makes to every task it targets
+type BulkAction string
+
+const (
+	// BulkSetStatus moves the tasks to a status, following the workflow
+	BulkSetStatus BulkAction = "status"
+	// BulkSetPriority changes the priority of the tasks
+	BulkSetPriority BulkAction = "priority"
+	// BulkSetCategory files the tasks under a category, or clears it
+	BulkSetCategory BulkAction = "category"
+	// BulkSetTags adds tags to and removes tags from the tasks
+	BulkSetTags BulkAction = "tags"
+	// BulkArchive archives the tasks
+	BulkArchive BulkAction = "archive"
+	// BulkDelete moves the tasks to the trash, applying the delete rule
+	BulkDelete BulkAction = "delete"
+)
+
+// MaxBulkTasks is the most tasks a single bulk request may change
+const MaxBulkTasks = 1000
+
+// ErrBulkUnavailable is returned when bulk operations are requested from
+// memory storage, which has no transactions to run them in
+var ErrBulkUnavailable = errors.New("bulk operations are only available in database storage")
+
+// errBulkDryRun rolls back the transaction of a dry run
+var errBulkDryRun = errors.New("bulk dry run")
+
+// BulkRequest describes a change to many tasks at once. The tasks are given
+// either by ID or by a search filter, which must match at most MaxBulkTasks tasks.
+type BulkRequest struct {
+	Action BulkAction
+	IDs    []int
+	Filter *search.SearchQuery
+	// Status is set for BulkSetStatus
+	Status *Status
+	// Priority is set for BulkSetPriority
+	Priority *Priority
+	// CategoryID is used by BulkSetCategory; nil clears the category
+	CategoryID *int
+	// AddTagIDs and RemoveTagIDs are used by BulkSetTags
+	AddTagIDs    []int
+	RemoveTagIDs []int
+	// DryRun reports what would happen and changes nothing
+	DryRun bool
+	// Partial commits the tasks that could be changed and reports the others,
+	// instead of changing nothing when any task fails
+	Partial bool
+}
+
+// Validate checks the request before any task is looked at
+func (r BulkRequest) Validate() error {
+	switch r.Action {
+	case BulkSetStatus:
+		if r.Status == nil {
+			return errors.New("invalid bulk request: status is required")
+		}
+	case BulkSetPriority:
+		if r.Priority == nil {
+			return errors.New("invalid bulk request: priority is required")
+		}
+		if err := (TaskPatch{Priority: r.Priority}).Validate(); err != nil {
+			return err
+		}
+	case BulkSetTags:
+		if len(r.AddTagIDs) == 0 && len(r.RemoveTagIDs) == 0 {
+			return errors.New("invalid bulk request: no tags to add or remove")
+		}
+		added := make(map[int]bool, len(r.AddTagIDs))
+		for _, tagID := range r.AddTagIDs {
+			added[tagID] = true
+		}
+		for _, tagID := range r.RemoveTagIDs {
+			if added[tagID] {
+				return fmt.Errorf("invalid bulk request: tag %d is both added and removed", tagID)
+			}
+		}
+	case BulkSetCategory, BulkArchive, BulkDelete:
+	default:
+		return fmt.Errorf("invalid bulk action %q", r.Action)
+	}
+
+	if (len(r.IDs) == 0) == (r.Filter == nil) {
+		return errors.New("invalid bulk request: give either task IDs or a filter")
+	}
+	if len(r.IDs) > MaxBulkTasks {
+		return fmt.Errorf("invalid bulk request: at most %d tasks can be changed at once", MaxBulkTasks)
+	}
+	seen := make(map[int]bool, len(r.IDs))
+	for _, id := range r.IDs {
+		if seen[id] {
+			return fmt.Errorf("invalid bulk request: task %d is listed twice", id)
+		}
+		seen[id] = true
+	}
+
+	return nil
+}
+
+// BulkItemResult is the outcome of a bulk request for a single task
+type BulkItemResult struct {
+	TaskID  int
+	Success bool
+	Error   string
+}
+
+// BulkResult summarizes a bulk request. Items is only filled in for dry runs
+// and partial requests; otherwise every task succeeded.
+type BulkResult struct {
+	Action    BulkAction
+	DryRun    bool
+	Matched   int
+	Succeeded int
+	Failed    int
+	Items     []BulkItemResult
+}
+
+// BulkItemError is returned when a task fails a bulk request that is not
+// partial, and nothing has been changed
+type BulkItemError struct {
+	TaskID int
+	Err    error
+}
+
+func (e *BulkItemError) Error() string {
+	return fmt.Sprintf("bulk request failed on task %d: %v", e.TaskID, e.Err)
+}
+
+func (e *BulkItemError) Unwrap() error {
+	return e.Err
+}
+
+// bulkApply changes a single task with repository, a repository bound to the
+// bulk transaction, publishing the changes to events
+type bulkApply func(repository database.Repository, events *EventBus, taskID int) error
+
+// runBulk applies a validated request to its tasks in one transaction. Every
+// task is changed within its own savepoint, so that a failing task can be
+// skipped in partial mode. Events are only published once the transaction
+// has been committed. scope restricts a filter to the tasks its caller may see.
+func runBulk(repository database.Repository, events *EventBus, request BulkRequest, scope func(query *search.SearchQuery) error, apply bulkApply) (*BulkResult, error) {
+	result := &BulkResult{Action: request.Action, DryRun: request.DryRun}
+
+	var published []Event
+	buffer := NewEventBus()
+	buffer.Subscribe(func(event Event) { published = append(published, event) })
+
+	err := repository.Transaction(func(tx database.Repository) error {
+		ids, err := bulkTargets(tx, request, scope)
+		if err != nil {
+			return err
+		}
+		result.Matched = len(ids)
+
+		deleted := make(map[int]bool)
+		for _, id := range ids {
+			mark := len(published)
+			err := tx.Transaction(func(item database.Repository) error {
+				// Subtasks deleted along with an earlier task need no second delete
+				if request.Action == BulkDelete && deleted[id] {
+					return nil
+				}
+				return apply(item, buffer, id)
+			})
+
+			if err != nil {
+				published = published[:mark]
+				if !request.Partial && !request.DryRun {
+					return &BulkItemError{TaskID: id, Err: err}
+				}
+				result.Failed++
+				result.Items = append(result.Items, BulkItemResult{TaskID: id, Error: err.Error()})
+				continue
+			}
+
+			for _, event := range published[mark:] {
+				if event.Type == EventTaskDeleted {
+					deleted[event.TaskID] = true
+				}
+			}
+			result.Succeeded++
+			if request.Partial || request.DryRun {
+				result.Items = append(result.Items, BulkItemResult{TaskID: id, Success: true})
+			}
+		}
+
+		if request.DryRun {
+			return errBulkDryRun
+		}
+		return nil
+	})
+
+	if request.DryRun && errors.Is(err, errBulkDryRun) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	events.Publish(published...)
+	return result, nil
+}
+
+// bulkTargets returns the IDs of the tasks a request targets, in ascending order
+func bulkTargets(repository database.Repository, request BulkRequest, scope func(query *search.SearchQuery) error) ([]int, error) {
+	if request.Filter == nil {
+		return request.IDs, nil
+	}
+
+	query := *request.Filter
+	if scope != nil {
+		if err := scope(&query); err != nil {
+			return nil, err
+		}
+	}
+	query.Limit = MaxBulkTasks + 1
+	query.Offset = 0
+
+	found, err := search.NewSearchService(repository).SearchTasks(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(found.Tasks) > MaxBulkTasks {
+		return nil, fmt.Errorf("invalid bulk request: the filter matches more than %d tasks", MaxBulkTasks)
+	}
+
+	ids := make([]int, len(found.Tasks))
+	for i, task := range found.Tasks {
+		ids[i] = task.ID
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// applyDatabaseBulk changes a single stored task as the request says, with
+// the repository and events of hierarchy
+func applyDatabaseBulk(hierarchy *HierarchyManager, taskID int, request BulkRequest) error {
+	if request.Action == BulkDelete {
+		return hierarchy.DeleteTask(taskID)
+	}
+
+	repository := hierarchy.repository
+	dbTask, err := repository.GetTask(taskID)
+	if err != nil {
+		return err
+	}
+
+	switch request.Action {
+	case BulkSetStatus:
+		return hierarchy.setStatus(dbTask, *request.Status)
+	case BulkSetPriority:
+		TaskPatch{Priority: request.Priority}.applyToDatabaseTask(dbTask)
+		if err := repository.UpdateTask(dbTask); err != nil {
+			return err
+		}
+		hierarchy.events.Publish(databaseTaskEvent(EventTaskUpdated, dbTask))
+		return nil
+	case BulkSetCategory:
+		return setDatabaseTaskCategory(repository, hierarchy.events, dbTask, request.CategoryID)
+	case BulkSetTags:
+		return setDatabaseTaskTags(repository, hierarchy.events, dbTask, request.AddTagIDs, request.RemoveTagIDs)
+	default:
+		return setDatabaseTaskArchived(repository, hierarchy.events, dbTask, true)
+	}
+}
+
+// checkBulkStatus rejects statuses the workflow does not know before any task is changed
+func checkBulkStatus(workflow *Workflow, request BulkRequest) error {
+	if request.Action == BulkSetStatus && !workflow.IsKnownStatus(*request.Status) {
+		return fmt.Errorf("invalid bulk request: unknown status %d", *request.Status)
+	}
+	return nil
+}
+
+// setDatabaseTaskCategory files a database task under a category of its own
+// project, or clears its category when categoryID is nil, and publishes the change
+func setDatabaseTaskCategory(repository database.Repository, events *EventBus, dbTask *database.DatabaseTask, categoryID *int) error {
+	if categoryID != nil {
+		category, err := repository.GetCategory(*categoryID)
+		if err != nil || !sameProject(category.ProjectID, dbTask.ProjectID) {
+			return fmt.Errorf("invalid category %d: not available to task %d", *categoryID, dbTask.ID)
+		}
+	}
+
+	if sameID(dbTask.CategoryID, categoryID) {
+		return nil
+	}
+
+	dbTask.CategoryID = categoryID
+	dbTask.UpdatedAt = time.Now()
+	if err := repository.UpdateTask(dbTask); err != nil {
+		return err
+	}
+
+	events.Publish(databaseTaskEvent(EventTaskUpdated, dbTask))
+	return nil
+}
+
+// setDatabaseTaskTags adds tags of the task's own project to a database task
+// and removes others. Tags the task already has, or does not have, are skipped.
+func setDatabaseTaskTags(repository database.Repository, events *EventBus, dbTask *database.DatabaseTask, add, remove []int) error {
+	tags, err := repository.GetTaskTags(dbTask.ID)
+	if err != nil {
+		return err
+	}
+	current := make(map[int]bool, len(tags))
+	for _, tag := range tags {
+		current[tag.ID] = true
+	}
+
+	for _, tagID := range add {
+		tag, err := repository.GetTag(tagID)
+		if err != nil || !sameProject(tag.ProjectID, dbTask.ProjectID) {
+			return fmt.Errorf("invalid tag %d: not available to task %d", tagID, dbTask.ID)
+		}
+		if current[tagID] {
+			continue
+		}
+		if err := repository.AddTagToTask(dbTask.ID, tagID); err != nil {
+			return err
+		}
+		current[tagID] = true
+		events.Publish(Event{Type: EventTagAttached, TaskID: dbTask.ID, TagID: tagID})
+	}
+
+	for _, tagID := range remove {
+		if !current[tagID] {
+			continue
+		}
+		if err := repository.RemoveTagFromTask(dbTask.ID, tagID); err != nil {
+			return err
+		}
+		delete(current, tagID)
+	}
+
+	return nil
+}
+
+// BulkUpdateTasks applies a bulk request to tasks of the user. Each task is
+// checked as if it were changed on its own: statuses can be changed on tasks
+// the user takes part in, everything else only on tasks the user owns.
+// A filter is limited to the user's own tasks unless it names a project the
+// user is a member of.
+func (um *UserManager) BulkUpdateTasks(userID int, request BulkRequest) (*BulkResult, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+	if err := checkBulkStatus(um.GetWorkflow(), request); err != nil {
+		return nil, err
+	}
+
+	scope := func(query *search.SearchQuery) error {
+		if query.ProjectID == nil {
+			query.UserID = &userID
+			return nil
+		}
+
+		role, err := projectRole(um.repository, userID, *query.ProjectID)
+		if err != nil {
+			return err
+		}
+		if role == "" {
+			return fmt.Errorf("project with ID %d not found", *query.ProjectID)
+		}
+		return nil
+	}
+
+	return runBulk(um.repository, um.events, request, scope, func(repository database.Repository, events *EventBus, taskID int) error {
+		bound := um.withRepository(repository, events)
+		var err error
+		switch request.Action {
+		case BulkSetStatus:
+			err = bound.UpdateUserTaskStatus(userID, taskID, *request.Status)
+		case BulkSetPriority:
+			_, err = bound.PatchUserTask(userID, taskID, TaskPatch{Priority: request.Priority})
+		case BulkSetCategory:
+			_, err = bound.SetUserTaskCategory(userID, taskID, request.CategoryID)
+		case BulkSetTags:
+			_, err = bound.SetUserTaskTags(userID, taskID, request.AddTagIDs, request.RemoveTagIDs)
+		case BulkArchive:
+			_, err = bound.ArchiveUserTask(userID, taskID)
+		default:
+			err = bound.DeleteUserTask(userID, taskID)
+		}
+		return err
+	})
+}
+
+// BulkContext always fails, because memory storage has no transactions
+func (tm *TaskManager) BulkContext(ctx context.Context, request BulkRequest) (*BulkResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, ErrBulkUnavailable
+}
+
+// BulkContext applies a bulk request to stored tasks in one transaction.
+// In hybrid storage the memory copies of the changed tasks are refreshed from
+// the database once the transaction has been committed.
+func (htm *HybridTaskManager) BulkContext(ctx context.Context, request BulkRequest) (*BulkResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	htm.mu.Lock()
+	defer htm.mu.Unlock()
+
+	if htm.storageType == MemoryStorage {
+		return htm.memoryManager.BulkContext(ctx, request)
+	}
+
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+	if err := checkBulkStatus(htm.hierarchy.GetWorkflow(), request); err != nil {
+		return nil, err
+	}
+
+	// Besides the targets, a request changes the subtasks it deletes and the
+	// next occurrences it creates, which are named by its events
+	var changed []int
+	repository := htm.repository.WithContext(ctx)
+	result, err := runBulk(repository, htm.events, request, nil, func(repository database.Repository, events *EventBus, taskID int) error {
+		changed = append(changed, taskID)
+		item := NewEventBus()
+		item.Subscribe(func(event Event) {
+			changed = append(changed, event.TaskID)
+			events.Publish(event)
+		})
+		return applyDatabaseBulk(htm.hierarchy.withRepository(repository, item), taskID, request)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !request.DryRun {
+		htm.refreshMemory(repository, changed)
+	}
+	return result, nil
+}
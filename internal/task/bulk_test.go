@@ -0,0 +1,233 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"learn-go-capstone/internal/search"
+)
+
+func TestBulkUpdateTasks(t *testing.T) {
+	repository := setupTestRepository(t)
+	um := NewUserManager(repository)
+	cm := NewCategoryManager(repository)
+	bus := NewEventBus()
+	um.SetEventBus(bus)
+	var events recorder
+	bus.Subscribe(events.handle)
+
+	user, err := um.RegisterUser("user", "user@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	other, err := um.RegisterUser("other", "other@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+
+	var ids []int
+	for _, title := range []string{"Write", "Review", "Ship"} {
+		created, err := um.CreateUserTask(user.ID, title, "", Low, nil)
+		if err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		ids = append(ids, created.ID)
+	}
+	foreign, err := um.CreateUserTask(other.ID, "Foreign", "", Low, nil)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	completed, urgent := Completed, Urgent
+	invalid := []BulkRequest{
+		{Action: "rename", IDs: ids},
+		{Action: BulkSetStatus, IDs: ids},
+		{Action: BulkArchive},
+		{Action: BulkArchive, IDs: ids, Filter: &search.SearchQuery{}},
+		{Action: BulkArchive, IDs: []int{ids[0], ids[0]}},
+		{Action: BulkSetTags, IDs: ids},
+		{Action: BulkSetTags, IDs: ids, AddTagIDs: []int{1}, RemoveTagIDs: []int{1}},
+		{Action: BulkSetStatus, IDs: ids, Status: statusPtr(42)},
+	}
+	for _, request := range invalid {
+		if _, err := um.BulkUpdateTasks(user.ID, request); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+			t.Errorf("Expected invalid error for %+v, got %v", request, err)
+		}
+	}
+
+	// Nothing changes when a single task fails
+	events.events = nil
+	_, err = um.BulkUpdateTasks(user.ID, BulkRequest{Action: BulkSetStatus, IDs: []int{ids[0], foreign.ID}, Status: &completed})
+	var itemErr *BulkItemError
+	if !errors.As(err, &itemErr) || itemErr.TaskID != foreign.ID {
+		t.Fatalf("Expected the foreign task to fail the request, got %v", err)
+	}
+	if loaded, _ := um.GetUserTask(user.ID, ids[0]); loaded.Status != Pending {
+		t.Errorf("Expected the request to be rolled back, got %+v", loaded)
+	}
+	assertEventTypes(t, events.types())
+
+	// Partial requests commit the tasks that could be changed
+	result, err := um.BulkUpdateTasks(user.ID, BulkRequest{Action: BulkSetStatus, IDs: []int{ids[0], foreign.ID}, Status: &completed, Partial: true})
+	if err != nil {
+		t.Fatalf("Failed to run partial request: %v", err)
+	}
+	if result.Matched != 2 || result.Succeeded != 1 || result.Failed != 1 || len(result.Items) != 2 ||
+		!result.Items[0].Success || result.Items[1].Success || result.Items[1].Error == "" {
+		t.Errorf("Expected one success and one failure, got %+v", result)
+	}
+	if loaded, _ := um.GetUserTask(user.ID, ids[0]); loaded.Status != Completed {
+		t.Errorf("Expected the task to be completed, got %+v", loaded)
+	}
+	assertEventTypes(t, events.types(), EventStatusChanged)
+
+	// Dry runs report what would happen and change nothing
+	events.events = nil
+	result, err = um.BulkUpdateTasks(user.ID, BulkRequest{Action: BulkSetPriority, Filter: &search.SearchQuery{}, Priority: &urgent, DryRun: true})
+	if err != nil {
+		t.Fatalf("Failed to run dry run: %v", err)
+	}
+	if !result.DryRun || result.Matched != 3 || result.Succeeded != 3 || len(result.Items) != 3 {
+		t.Errorf("Expected the filter to match the user's own tasks, got %+v", result)
+	}
+	for _, id := range ids {
+		if loaded, _ := um.GetUserTask(user.ID, id); loaded.Priority != Low {
+			t.Errorf("Expected the dry run to change nothing, got %+v", loaded)
+		}
+	}
+	assertEventTypes(t, events.types())
+
+	result, err = um.BulkUpdateTasks(user.ID, BulkRequest{Action: BulkSetPriority, Filter: &search.SearchQuery{Query: "Review"}, Priority: &urgent})
+	if err != nil || result.Matched != 1 || result.Items != nil {
+		t.Fatalf("Expected one task to match, got %+v (%v)", result, err)
+	}
+	if loaded, _ := um.GetUserTask(user.ID, ids[1]); loaded.Priority != Urgent {
+		t.Errorf("Expected the matching task to be urgent, got %+v", loaded)
+	}
+
+	// Categories and tags
+	category, err := cm.CreateCategory("Work", "", "")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	tag, err := cm.CreateTag("sprint", "")
+	if err != nil {
+		t.Fatalf("Failed to create tag: %v", err)
+	}
+	if _, err := um.BulkUpdateTasks(user.ID, BulkRequest{Action: BulkSetCategory, IDs: ids, CategoryID: &category.ID}); err != nil {
+		t.Fatalf("Failed to set category: %v", err)
+	}
+	if dbTask, _ := repository.GetTask(ids[2]); dbTask.CategoryID == nil || *dbTask.CategoryID != category.ID {
+		t.Errorf("Expected the task to be categorized, got %+v", dbTask)
+	}
+	missing := category.ID + 100
+	if _, err := um.BulkUpdateTasks(user.ID, BulkRequest{Action: BulkSetCategory, IDs: ids, CategoryID: &missing}); err == nil || !strings.Contains(err.Error(), "invalid category") {
+		t.Errorf("Expected unknown categories to fail, got %v", err)
+	}
+
+	events.events = nil
+	if _, err := um.BulkUpdateTasks(user.ID, BulkRequest{Action: BulkSetTags, IDs: ids[:2], AddTagIDs: []int{tag.ID}}); err != nil {
+		t.Fatalf("Failed to add tags: %v", err)
+	}
+	assertEventTypes(t, events.types(), EventTagAttached, EventTagAttached)
+	if _, err := um.BulkUpdateTasks(user.ID, BulkRequest{Action: BulkSetTags, IDs: ids, RemoveTagIDs: []int{tag.ID}}); err != nil {
+		t.Fatalf("Failed to remove tags: %v", err)
+	}
+	if tags, _ := repository.GetTaskTags(ids[0]); len(tags) != 0 {
+		t.Errorf("Expected the tag to be removed, got %+v", tags)
+	}
+
+	if _, err := um.BulkUpdateTasks(user.ID, BulkRequest{Action: BulkArchive, IDs: ids[:1]}); err != nil {
+		t.Fatalf("Failed to archive: %v", err)
+	}
+	if archived, _ := um.GetUserArchivedTasks(user.ID); len(archived) != 1 || archived[0].ID != ids[0] {
+		t.Errorf("Expected one archived task, got %+v", archived)
+	}
+
+	if _, err := um.BulkUpdateTasks(user.ID, BulkRequest{Action: BulkDelete, IDs: ids[1:]}); err != nil {
+		t.Fatalf("Failed to delete: %v", err)
+	}
+	if deleted, _ := um.GetUserDeletedTasks(user.ID); len(deleted) != 2 {
+		t.Errorf("Expected two deleted tasks, got %+v", deleted)
+	}
+}
+
+func TestHybridBulk(t *testing.T) {
+	repository := setupTestRepository(t)
+	htm := NewHybridTaskManager(repository, DatabaseStorage)
+	ctx := context.Background()
+
+	write := htm.AddTask("Write", "", Medium, nil)
+	ship := htm.AddTask("Ship", "", Medium, nil)
+	if err := NewDependencyManager(repository).AddDependency(ship.ID, write.ID); err != nil {
+		t.Fatalf("Failed to add dependency: %v", err)
+	}
+
+	// Guards see the changes made earlier in the same request
+	completed := Completed
+	if _, err := htm.BulkContext(ctx, BulkRequest{Action: BulkSetStatus, IDs: []int{write.ID, ship.ID}, Status: &completed}); err != nil {
+		t.Fatalf("Failed to complete tasks: %v", err)
+	}
+	if loaded, _ := htm.GetTask(ship.ID); loaded.Status != Completed {
+		t.Errorf("Expected the dependent task to be completed, got %+v", loaded)
+	}
+
+	// Subtasks deleted with their parent are not deleted twice
+	parent := htm.AddTask("Parent", "", Medium, nil)
+	child := htm.AddTask("Child", "", Medium, nil)
+	if err := htm.hierarchy.SetParent(child.ID, &parent.ID); err != nil {
+		t.Fatalf("Failed to set parent: %v", err)
+	}
+	result, err := htm.BulkContext(ctx, BulkRequest{Action: BulkDelete, IDs: []int{parent.ID, child.ID}})
+	if err != nil || result.Succeeded != 2 {
+		t.Fatalf("Expected both tasks to be deleted, got %+v (%v)", result, err)
+	}
+
+	if _, err := NewTaskManager().BulkContext(ctx, BulkRequest{Action: BulkArchive, IDs: []int{1}}); !errors.Is(err, ErrBulkUnavailable) {
+		t.Errorf("Expected bulk operations to be unavailable in memory, got %v", err)
+	}
+}
+
+func TestHybridBulkRefreshesMemory(t *testing.T) {
+	repository := setupTestRepository(t)
+	htm := NewHybridTaskManager(repository, HybridStorage)
+	ctx := context.Background()
+
+	parent := htm.AddTask("Parent", "", Low, nil)
+	child := htm.AddTask("Child", "", Low, nil)
+	if err := htm.hierarchy.SetParent(child.ID, &parent.ID); err != nil {
+		t.Fatalf("Failed to set parent: %v", err)
+	}
+	other := htm.AddTask("Other", "", Low, nil)
+
+	high := High
+	if _, err := htm.BulkContext(ctx, BulkRequest{Action: BulkSetPriority, IDs: []int{parent.ID, other.ID}, Priority: &high}); err != nil {
+		t.Fatalf("Failed to set priorities: %v", err)
+	}
+	for _, id := range []int{parent.ID, other.ID} {
+		if loaded, err := htm.memoryManager.GetTask(id); err != nil || loaded.Priority != High {
+			t.Errorf("Expected the memory copy of task %d to be updated, got %+v (%v)", id, loaded, err)
+		}
+	}
+
+	// The subtask deleted along with its parent is refreshed as well
+	if _, err := htm.BulkContext(ctx, BulkRequest{Action: BulkDelete, IDs: []int{parent.ID}}); err != nil {
+		t.Fatalf("Failed to delete tasks: %v", err)
+	}
+	trashed, err := htm.memoryManager.GetDeletedTasksContext(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get deleted tasks: %v", err)
+	}
+	if len(trashed) != 2 {
+		t.Errorf("Expected the memory copies of both tasks to be in the trash, got %+v", trashed)
+	}
+	if loaded, err := htm.memoryManager.GetTask(other.ID); err != nil || loaded.DeletedAt != nil {
+		t.Errorf("Expected the other task to stay, got %+v (%v)", loaded, err)
+	}
+}
+
+func statusPtr(status Status) *Status {
+	return &status
+}
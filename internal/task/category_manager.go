@@ -7,6 +7,7 @@ import (
 // CategoryManager manages categories and tags
 type CategoryManager struct {
 	repository database.Repository
+	events     *EventBus
 }
 
 // NewCategoryManager creates a new category manager
@@ -16,6 +17,11 @@ func NewCategoryManager(repository database.Repository) *CategoryManager {
 	}
 }
 
+// SetEventBus sets the bus that tag attachments are published to
+func (cm *CategoryManager) SetEventBus(bus *EventBus) {
+	cm.events = bus
+}
+
 // Category operations
 
 // CreateCategory creates a new category
@@ -34,6 +40,23 @@ func (cm *CategoryManager) CreateCategory(name, description, color string) (*dat
 	return category, nil
 }
 
+// CreateProjectCategory creates a category owned by a project. Its name only
+// has to be unique within the project.
+func (cm *CategoryManager) CreateProjectCategory(projectID int, name, description, color string) (*database.Category, error) {
+	category := &database.Category{
+		ProjectID:   &projectID,
+		Name:        name,
+		Description: description,
+		Color:       color,
+	}
+	
+	if err := cm.repository.CreateCategory(category); err != nil {
+		return nil, err
+	}
+	
+	return category, nil
+}
+
 // GetCategory retrieves a category by ID
 func (cm *CategoryManager) GetCategory(id int) (*database.Category, error) {
 	return cm.repository.GetCategory(id)
@@ -44,6 +67,12 @@ func (cm *CategoryManager) GetAllCategories() ([]database.Category, error) {
 	return cm.repository.GetAllCategories()
 }
 
+// GetCategoriesByProject returns the categories of a project, or the global
+// categories when projectID is nil
+func (cm *CategoryManager) GetCategoriesByProject(projectID *int) ([]database.Category, error) {
+	return cm.repository.GetCategoriesByProject(projectID)
+}
+
 // UpdateCategory updates a category
 func (cm *CategoryManager) UpdateCategory(category *database.Category) error {
 	return cm.repository.UpdateCategory(category)
@@ -71,6 +100,22 @@ func (cm *CategoryManager) CreateTag(name, color string) (*database.Tag, error)
 	return tag, nil
 }
 
+// CreateProjectTag creates a tag owned by a project. Its name only has to be
+// unique within the project.
+func (cm *CategoryManager) CreateProjectTag(projectID int, name, color string) (*database.Tag, error) {
+	tag := &database.Tag{
+		ProjectID: &projectID,
+		Name:      name,
+		Color:     color,
+	}
+	
+	if err := cm.repository.CreateTag(tag); err != nil {
+		return nil, err
+	}
+	
+	return tag, nil
+}
+
 // GetTag retrieves a tag by ID
 func (cm *CategoryManager) GetTag(id int) (*database.Tag, error) {
 	return cm.repository.GetTag(id)
@@ -81,6 +126,12 @@ func (cm *CategoryManager) GetAllTags() ([]database.Tag, error) {
 	return cm.repository.GetAllTags()
 }
 
+// GetTagsByProject returns the tags of a project, or the global tags when
+// projectID is nil
+func (cm *CategoryManager) GetTagsByProject(projectID *int) ([]database.Tag, error) {
+	return cm.repository.GetTagsByProject(projectID)
+}
+
 // UpdateTag updates a tag
 func (cm *CategoryManager) UpdateTag(tag *database.Tag) error {
 	return cm.repository.UpdateTag(tag)
@@ -95,7 +146,12 @@ func (cm *CategoryManager) DeleteTag(id int) error {
 
 // AddTagToTask adds a tag to a task
 func (cm *CategoryManager) AddTagToTask(taskID, tagID int) error {
-	return cm.repository.AddTagToTask(taskID, tagID)
+	if err := cm.repository.AddTagToTask(taskID, tagID); err != nil {
+		return err
+	}
+
+	cm.events.Publish(Event{Type: EventTagAttached, TaskID: taskID, TagID: tagID})
+	return nil
 }
 
 // RemoveTagFromTask removes a tag from a task
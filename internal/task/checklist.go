@@ -0,0 +1,173 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"learn-go-capstone/internal/database"
+)
+
+// maxChecklistItemLength bounds the text of a checklist item, in characters
+const maxChecklistItemLength = 500
+
+// maxChecklistItems bounds the number of items on one checklist
+const maxChecklistItems = 100
+
+// ChecklistItem is an item of the checklist of a task, with its own
+// completion state. Items are ordered by Position, starting at 0.
+type ChecklistItem struct {
+	ID          int        `json:"id"`
+	Text        string     `json:"text"`
+	Done        bool       `json:"done"`
+	Position    int        `json:"position"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// ChecklistItemPatch changes some attributes of a checklist item; nil fields
+// are left as they are
+type ChecklistItemPatch struct {
+	Text     *string
+	Done     *bool
+	Position *int
+}
+
+func convertFromDatabaseChecklistItem(di *database.ChecklistItem) ChecklistItem {
+	return ChecklistItem{
+		ID:          di.ID,
+		Text:        di.Text,
+		Done:        di.Done,
+		Position:    di.Position,
+		CompletedAt: di.CompletedAt,
+		CreatedAt:   di.CreatedAt,
+		UpdatedAt:   di.UpdatedAt,
+	}
+}
+
+// convertFromDatabaseChecklist converts checklist items, returning nil when
+// there are none
+func convertFromDatabaseChecklist(dbItems []database.ChecklistItem) []ChecklistItem {
+	if len(dbItems) == 0 {
+		return nil
+	}
+
+	items := make([]ChecklistItem, len(dbItems))
+	for i := range dbItems {
+		items[i] = convertFromDatabaseChecklistItem(&dbItems[i])
+	}
+	return items
+}
+
+// checkChecklistText trims the text of a checklist item and validates it
+func checkChecklistText(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", errors.New("invalid checklist item: text cannot be empty")
+	}
+	if len([]rune(text)) > maxChecklistItemLength {
+		return "", fmt.Errorf("invalid checklist item: text cannot be longer than %d characters", maxChecklistItemLength)
+	}
+	return text, nil
+}
+
+// checklistItem returns an item of the checklist of a task the user can see
+func (um *UserManager) checklistItem(userID, taskID, itemID int) (*database.ChecklistItem, error) {
+	if _, err := um.GetVisibleTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	dbItem, err := um.repository.GetChecklistItem(itemID)
+	if err != nil {
+		return nil, err
+	}
+	if dbItem.TaskID != taskID {
+		return nil, fmt.Errorf("checklist item with ID %d not found", itemID)
+	}
+	return dbItem, nil
+}
+
+// GetChecklist returns the checklist of a task the user can see, by position
+func (um *UserManager) GetChecklist(userID, taskID int) ([]ChecklistItem, error) {
+	if _, err := um.GetVisibleTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	dbItems, err := um.repository.GetChecklistItems(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := convertFromDatabaseChecklist(dbItems)
+	if items == nil {
+		items = []ChecklistItem{}
+	}
+	return items, nil
+}
+
+// AddChecklistItem appends an item to the checklist of a task the user can
+// see. Everyone who can see a task can work through its checklist.
+func (um *UserManager) AddChecklistItem(userID, taskID int, text string) (*ChecklistItem, error) {
+	text, err := checkChecklistText(text)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := um.GetChecklist(userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) >= maxChecklistItems {
+		return nil, fmt.Errorf("invalid checklist item: a checklist cannot have more than %d items", maxChecklistItems)
+	}
+
+	dbItem := &database.ChecklistItem{TaskID: taskID, Text: text}
+	if err := um.repository.CreateChecklistItem(dbItem); err != nil {
+		return nil, err
+	}
+
+	item := convertFromDatabaseChecklistItem(dbItem)
+	return &item, nil
+}
+
+// UpdateChecklistItem changes the text, completion or position of an item of
+// the checklist of a task the user can see
+func (um *UserManager) UpdateChecklistItem(userID, taskID, itemID int, patch ChecklistItemPatch) (*ChecklistItem, error) {
+	dbItem, err := um.checklistItem(userID, taskID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.Text != nil {
+		if dbItem.Text, err = checkChecklistText(*patch.Text); err != nil {
+			return nil, err
+		}
+	}
+	if patch.Done != nil {
+		dbItem.Done = *patch.Done
+	}
+	if patch.Position != nil {
+		if *patch.Position < 0 {
+			return nil, errors.New("invalid checklist item: position cannot be negative")
+		}
+		dbItem.Position = *patch.Position
+	}
+
+	if err := um.repository.UpdateChecklistItem(dbItem); err != nil {
+		return nil, err
+	}
+
+	item := convertFromDatabaseChecklistItem(dbItem)
+	return &item, nil
+}
+
+// DeleteChecklistItem removes an item from the checklist of a task the user can see
+func (um *UserManager) DeleteChecklistItem(userID, taskID, itemID int) error {
+	if _, err := um.checklistItem(userID, taskID, itemID); err != nil {
+		return err
+	}
+
+	return um.repository.DeleteChecklistItem(itemID)
+}
@@ -0,0 +1,94 @@
+package task
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChecklist(t *testing.T) {
+	repository := setupTestRepository(t)
+	um := NewUserManager(repository)
+
+	user, err := um.RegisterUser("user", "user@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	other, err := um.RegisterUser("other", "other@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+
+	created, err := um.CreateUserTask(user.ID, "Release", "", High, nil)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	if _, err := um.AddChecklistItem(user.ID, created.ID, "  "); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+		t.Errorf("Expected invalid error for an empty item, got %v", err)
+	}
+	if _, err := um.AddChecklistItem(other.ID, created.ID, "Tag"); err == nil {
+		t.Error("Expected other users not to change the checklist")
+	}
+
+	var ids []int
+	for _, text := range []string{"Tag", " Build ", "Publish"} {
+		item, err := um.AddChecklistItem(user.ID, created.ID, text)
+		if err != nil {
+			t.Fatalf("Failed to add checklist item: %v", err)
+		}
+		ids = append(ids, item.ID)
+	}
+
+	items, err := um.GetChecklist(user.ID, created.ID)
+	if err != nil {
+		t.Fatalf("Failed to get checklist: %v", err)
+	}
+	if len(items) != 3 || items[1].Text != "Build" || items[2].Position != 2 {
+		t.Errorf("Expected three items in order, got %+v", items)
+	}
+
+	done := true
+	item, err := um.UpdateChecklistItem(user.ID, created.ID, ids[0], ChecklistItemPatch{Done: &done})
+	if err != nil {
+		t.Fatalf("Failed to tick off item: %v", err)
+	}
+	if !item.Done || item.CompletedAt == nil {
+		t.Errorf("Expected a completed item, got %+v", item)
+	}
+
+	// Moving an item shifts the others
+	first := 0
+	if _, err := um.UpdateChecklistItem(user.ID, created.ID, ids[2], ChecklistItemPatch{Position: &first}); err != nil {
+		t.Fatalf("Failed to move item: %v", err)
+	}
+	items, _ = um.GetChecklist(user.ID, created.ID)
+	if len(items) != 3 || items[0].ID != ids[2] || items[1].ID != ids[0] || items[2].ID != ids[1] {
+		t.Errorf("Expected the last item first, got %+v", items)
+	}
+
+	undone := false
+	item, err = um.UpdateChecklistItem(user.ID, created.ID, ids[0], ChecklistItemPatch{Done: &undone})
+	if err != nil || item.Done || item.CompletedAt != nil {
+		t.Errorf("Expected the item to be open again, got %+v (%v)", item, err)
+	}
+
+	// Items belong to their task
+	otherTask, err := um.CreateUserTask(user.ID, "Other", "", Low, nil)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if err := um.DeleteChecklistItem(user.ID, otherTask.ID, ids[1]); err == nil || !strings.HasSuffix(err.Error(), "not found") {
+		t.Errorf("Expected items of other tasks not to be found, got %v", err)
+	}
+
+	if err := um.DeleteChecklistItem(user.ID, created.ID, ids[2]); err != nil {
+		t.Fatalf("Failed to delete item: %v", err)
+	}
+	loaded, err := um.GetVisibleTask(user.ID, created.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if len(loaded.Checklist) != 2 || loaded.Checklist[0].ID != ids[0] || loaded.Checklist[0].Position != 0 {
+		t.Errorf("Expected the task to carry its remaining checklist, got %+v", loaded.Checklist)
+	}
+}
@@ -0,0 +1,314 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"learn-go-capstone/internal/database"
+)
+
+// maxCommentLength bounds the markdown body of a comment, in bytes
+const maxCommentLength = 10000
+
+// Comment is a markdown comment on a task together with its replies
+type Comment struct {
+	ID       int    `json:"id"`
+	TaskID   int    `json:"task_id"`
+	ParentID *int   `json:"parent_id,omitempty"`
+	AuthorID int    `json:"author_id"`
+	Author   string `json:"author"`
+	// Body is markdown; it is empty once the comment is deleted
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Deleted comments are only kept while they have replies
+	Deleted bool      `json:"deleted"`
+	Replies []Comment `json:"replies,omitempty"`
+}
+
+func convertFromDatabaseComment(dc *database.Comment) Comment {
+	return Comment{
+		ID:        dc.ID,
+		TaskID:    dc.TaskID,
+		ParentID:  dc.ParentID,
+		AuthorID:  dc.UserID,
+		Author:    dc.Username,
+		Body:      dc.Body,
+		CreatedAt: dc.CreatedAt,
+		UpdatedAt: dc.UpdatedAt,
+		Deleted:   dc.DeletedAt != nil,
+	}
+}
+
+// buildCommentThreads nests replies below their parents. Deleted comments
+// without replies are left out.
+func buildCommentThreads(comments []database.Comment) []Comment {
+	children := make(map[int][]database.Comment)
+	var roots []database.Comment
+	for _, comment := range comments {
+		if comment.ParentID == nil {
+			roots = append(roots, comment)
+		} else {
+			children[*comment.ParentID] = append(children[*comment.ParentID], comment)
+		}
+	}
+
+	var build func(level []database.Comment) []Comment
+	build = func(level []database.Comment) []Comment {
+		var threads []Comment
+		for i := range level {
+			comment := convertFromDatabaseComment(&level[i])
+			comment.Replies = build(children[comment.ID])
+			if comment.Deleted && len(comment.Replies) == 0 {
+				continue
+			}
+			threads = append(threads, comment)
+		}
+		return threads
+	}
+
+	return build(roots)
+}
+
+// mentionPattern matches @username. The @ must not follow a word character,
+// so e-mail addresses are not mentions.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([\w][\w.-]*)`)
+
+// codePattern matches fenced code blocks and inline code spans, whose
+// contents never mention anyone
+var codePattern = regexp.MustCompile("(?s)```.*?```|`[^`\n]*`")
+
+// ParseMentions returns the distinct usernames mentioned in a markdown body,
+// in the order they first appear
+func ParseMentions(body string) []string {
+	body = codePattern.ReplaceAllString(body, " ")
+
+	var usernames []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		// Punctuation ending a sentence is not part of the name
+		username := strings.TrimRight(match[1], ".-")
+		if !seen[username] {
+			seen[username] = true
+			usernames = append(usernames, username)
+		}
+	}
+
+	return usernames
+}
+
+// validateCommentBody trims a comment body and checks its length
+func validateCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", errors.New("comment body cannot be empty")
+	}
+	if len(body) > maxCommentLength {
+		return "", fmt.Errorf("comment body cannot be longer than %d characters", maxCommentLength)
+	}
+	return body, nil
+}
+
+// CommentManager manages threaded comments on tasks. Everyone who can see a
+// task, as its owner, a member of its project or an assignee, reviewer or
+// watcher, can read and write its comments; only the author can change one.
+type CommentManager struct {
+	repository database.Repository
+	events     *EventBus
+}
+
+// NewCommentManager creates a new comment manager
+func NewCommentManager(repository database.Repository) *CommentManager {
+	return &CommentManager{
+		repository: repository,
+	}
+}
+
+// SetEventBus sets the bus that new and edited comments are published to
+func (cm *CommentManager) SetEventBus(bus *EventBus) {
+	cm.events = bus
+}
+
+// WithContext returns a copy of the comment manager whose queries run with ctx
+func (cm *CommentManager) WithContext(ctx context.Context) *CommentManager {
+	if cm.repository == nil {
+		return cm
+	}
+
+	bound := *cm
+	bound.repository = cm.repository.WithContext(ctx)
+	return &bound
+}
+
+// visibleTask returns a task the user can see
+func (cm *CommentManager) visibleTask(userID, taskID int) (*database.DatabaseTask, error) {
+	dbTask, err := cm.repository.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if visible, err := canSeeTask(cm.repository, userID, dbTask); err != nil {
+		return nil, err
+	} else if !visible {
+		return nil, errors.New("access denied: task does not belong to user or user ID is missing")
+	}
+
+	return dbTask, nil
+}
+
+// authoredComment returns a comment of the task that the user wrote
+func (cm *CommentManager) authoredComment(userID, taskID, commentID int) (*database.Comment, error) {
+	comment, err := cm.repository.GetComment(commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.TaskID != taskID || comment.DeletedAt != nil {
+		return nil, fmt.Errorf("comment with ID %d not found", commentID)
+	}
+
+	if comment.UserID != userID {
+		return nil, errors.New("access denied: comment was written by another user")
+	}
+
+	return comment, nil
+}
+
+// resolveMentions looks up the mentioned users. Unknown and inactive users,
+// the author and users who cannot see the task are skipped, so a mention
+// never tells anyone about a task they have no access to.
+func (cm *CommentManager) resolveMentions(usernames []string, authorID int, dbTask *database.DatabaseTask) []int {
+	var userIDs []int
+	for _, username := range usernames {
+		user, err := cm.repository.GetUserByUsername(username)
+		if err != nil || !user.IsActive || user.ID == authorID {
+			continue
+		}
+		if visible, err := canSeeTask(cm.repository, user.ID, dbTask); err != nil || !visible {
+			continue
+		}
+		userIDs = append(userIDs, user.ID)
+	}
+	return userIDs
+}
+
+// publish sends a comment event for the task
+func (cm *CommentManager) publish(eventType EventType, dbTask *database.DatabaseTask, comment Comment, mentions []int) {
+	event := databaseTaskEvent(eventType, dbTask)
+	event.Comment = &comment
+	event.Mentions = mentions
+	cm.events.Publish(event)
+}
+
+// AddComment posts a comment on a task, or a reply when parentID is set.
+// Every user mentioned in the body is published with the comment.
+func (cm *CommentManager) AddComment(userID, taskID int, parentID *int, body string) (*Comment, error) {
+	body, err := validateCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	dbTask, err := cm.visibleTask(userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if parentID != nil {
+		parent, err := cm.repository.GetComment(*parentID)
+		if err != nil || parent.TaskID != taskID {
+			return nil, fmt.Errorf("parent comment with ID %d not found", *parentID)
+		}
+		if parent.DeletedAt != nil {
+			return nil, errors.New("cannot reply to a deleted comment")
+		}
+	}
+
+	dbComment := &database.Comment{
+		TaskID:   taskID,
+		ParentID: parentID,
+		UserID:   userID,
+		Body:     body,
+	}
+	if err := cm.repository.CreateComment(dbComment); err != nil {
+		return nil, err
+	}
+
+	created, err := cm.repository.GetComment(dbComment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := convertFromDatabaseComment(created)
+	cm.publish(EventCommentAdded, dbTask, comment, cm.resolveMentions(ParseMentions(body), userID, dbTask))
+	return &comment, nil
+}
+
+// GetComments returns the comment threads of a task, oldest first
+func (cm *CommentManager) GetComments(userID, taskID int) ([]Comment, error) {
+	if _, err := cm.visibleTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	comments, err := cm.repository.GetTaskComments(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCommentThreads(comments), nil
+}
+
+// EditComment replaces the body of a comment written by the user. Only users
+// who were not mentioned before are published as mentions.
+func (cm *CommentManager) EditComment(userID, taskID, commentID int, body string) (*Comment, error) {
+	body, err := validateCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	dbTask, err := cm.visibleTask(userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	dbComment, err := cm.authoredComment(userID, taskID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	mentionedBefore := make(map[string]bool)
+	for _, username := range ParseMentions(dbComment.Body) {
+		mentionedBefore[username] = true
+	}
+	var newMentions []string
+	for _, username := range ParseMentions(body) {
+		if !mentionedBefore[username] {
+			newMentions = append(newMentions, username)
+		}
+	}
+
+	dbComment.Body = body
+	if err := cm.repository.UpdateComment(dbComment); err != nil {
+		return nil, err
+	}
+
+	comment := convertFromDatabaseComment(dbComment)
+	cm.publish(EventCommentEdited, dbTask, comment, cm.resolveMentions(newMentions, userID, dbTask))
+	return &comment, nil
+}
+
+// DeleteComment deletes a comment written by the user. Its replies are kept.
+func (cm *CommentManager) DeleteComment(userID, taskID, commentID int) error {
+	if _, err := cm.visibleTask(userID, taskID); err != nil {
+		return err
+	}
+
+	if _, err := cm.authoredComment(userID, taskID, commentID); err != nil {
+		return err
+	}
+
+	return cm.repository.DeleteComment(commentID)
+}
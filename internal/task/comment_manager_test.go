@@ -0,0 +1,144 @@
+package task
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		body string
+		want []string
+	}{
+		{"Thanks @alice!", []string{"alice"}},
+		{"@bob and @carol.smith, then @bob again", []string{"bob", "carol.smith"}},
+		{"Ask @dave.", []string{"dave"}},
+		{"Mail alice@example.com", nil},
+		{"Run `@ignored` or\n```\n@also_ignored\n```\nthen ping @erin", []string{"erin"}},
+		{"No mentions here", nil},
+	}
+
+	for _, tt := range tests {
+		if got := ParseMentions(tt.body); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseMentions(%q) = %v, want %v", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestCommentManager(t *testing.T) {
+	repository := setupTestRepository(t)
+	um := NewUserManager(repository)
+	cm := NewCommentManager(repository)
+
+	bus := NewEventBus()
+	cm.SetEventBus(bus)
+	var events recorder
+	bus.Subscribe(events.handle, EventCommentAdded, EventCommentEdited)
+
+	owner, err := um.RegisterUser("owner", "owner@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	reviewer, err := um.RegisterUser("reviewer", "reviewer@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	tester, err := um.RegisterUser("tester", "tester@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+
+	created, err := um.CreateUserTask(owner.ID, "Release notes", "", Medium, nil)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	if _, err := cm.AddComment(owner.ID, created.ID, nil, "   "); err == nil {
+		t.Error("Expected error for an empty comment")
+	}
+	if _, err := cm.AddComment(reviewer.ID, created.ID, nil, "Hello"); err == nil {
+		t.Error("Expected error when commenting on a task the user cannot see")
+	}
+	if _, err := um.SetUserTaskAssignees(owner.ID, created.ID, []Assignee{{Username: "reviewer", Role: RoleReviewer}}); err != nil {
+		t.Fatalf("Failed to set assignees: %v", err)
+	}
+
+	root, err := cm.AddComment(owner.ID, created.ID, nil, "Draft is ready, @reviewer please look. cc @nobody @owner")
+	if err != nil {
+		t.Fatalf("Failed to add comment: %v", err)
+	}
+	if root.Author != "owner" {
+		t.Errorf("Expected the author's username, got %q", root.Author)
+	}
+
+	reply, err := cm.AddComment(owner.ID, created.ID, &root.ID, "Fixed the typo")
+	if err != nil {
+		t.Fatalf("Failed to add reply: %v", err)
+	}
+	missing := 999
+	if _, err := cm.AddComment(owner.ID, created.ID, &missing, "Orphan"); err == nil {
+		t.Error("Expected error when replying to a missing comment")
+	}
+
+	if _, err := cm.EditComment(owner.ID, created.ID, root.ID, "Draft is ready, @reviewer and @tester please look."); err != nil {
+		t.Fatalf("Failed to edit comment: %v", err)
+	}
+
+	assertEventTypes(t, events.types(), EventCommentAdded, EventCommentAdded, EventCommentEdited)
+	if mentions := events.events[0].Mentions; !reflect.DeepEqual(mentions, []int{reviewer.ID}) {
+		t.Errorf("Expected only the reviewer to be mentioned, got %v", mentions)
+	}
+	if mentions := events.events[1].Mentions; len(mentions) != 0 {
+		t.Errorf("Expected no mentions in the reply, got %v", mentions)
+	}
+	if mentions := events.events[2].Mentions; len(mentions) != 0 {
+		t.Errorf("Expected the tester, who cannot see the task, not to be mentioned, got %v", mentions)
+	}
+
+	if dbTask, err := repository.GetTask(created.ID); err != nil || dbTask.CommentCount != 2 {
+		t.Errorf("Expected two comments to be counted, got %+v (%v)", dbTask, err)
+	}
+
+	// Deleting the root keeps it as a placeholder above its reply
+	if err := cm.DeleteComment(owner.ID, created.ID, root.ID); err != nil {
+		t.Fatalf("Failed to delete comment: %v", err)
+	}
+	if _, err := cm.EditComment(owner.ID, created.ID, root.ID, "Back"); err == nil {
+		t.Error("Expected error when editing a deleted comment")
+	}
+	threads, err := cm.GetComments(owner.ID, created.ID)
+	if err != nil {
+		t.Fatalf("Failed to get comments: %v", err)
+	}
+	if len(threads) != 1 || !threads[0].Deleted || threads[0].Body != "" || len(threads[0].Replies) != 1 || threads[0].Replies[0].ID != reply.ID {
+		t.Fatalf("Expected a deleted placeholder with its reply, got %+v", threads)
+	}
+
+	// Once the reply is gone the whole thread disappears
+	if err := cm.DeleteComment(owner.ID, created.ID, reply.ID); err != nil {
+		t.Fatalf("Failed to delete reply: %v", err)
+	}
+	if threads, err := cm.GetComments(owner.ID, created.ID); err != nil || len(threads) != 0 {
+		t.Errorf("Expected no threads left, got %+v (%v)", threads, err)
+	}
+	if dbTask, err := repository.GetTask(created.ID); err != nil || dbTask.CommentCount != 0 {
+		t.Errorf("Expected deleted comments not to be counted, got %+v (%v)", dbTask, err)
+	}
+
+	// Reviewers and watchers take part in the discussion too
+	if _, err := um.SetUserTaskAssignees(owner.ID, created.ID, []Assignee{
+		{Username: "reviewer", Role: RoleReviewer},
+		{Username: "tester", Role: RoleWatcher},
+	}); err != nil {
+		t.Fatalf("Failed to set assignees: %v", err)
+	}
+	if _, err := cm.AddComment(reviewer.ID, created.ID, nil, "Looks good, @tester can you verify?"); err != nil {
+		t.Fatalf("Failed to add comment as reviewer: %v", err)
+	}
+	if mentions := events.events[len(events.events)-1].Mentions; !reflect.DeepEqual(mentions, []int{tester.ID}) {
+		t.Errorf("Expected the watching tester to be mentioned, got %v", mentions)
+	}
+	if threads, err := cm.GetComments(tester.ID, created.ID); err != nil || len(threads) != 1 {
+		t.Errorf("Expected the watcher to read the comment, got %+v (%v)", threads, err)
+	}
+}
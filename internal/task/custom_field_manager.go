@@ -0,0 +1,419 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"learn-go-capstone/internal/database"
+)
+
+// maxFieldOptions bounds the number of options of an enum field
+const maxFieldOptions = 100
+
+// CustomFieldType is the type of the values of a custom field
+type CustomFieldType string
+
+const (
+	FieldText   CustomFieldType = database.CustomFieldText
+	FieldNumber CustomFieldType = database.CustomFieldNumber
+	FieldDate   CustomFieldType = database.CustomFieldDate
+	FieldEnum   CustomFieldType = database.CustomFieldEnum
+	FieldUser   CustomFieldType = database.CustomFieldUser
+	FieldURL    CustomFieldType = database.CustomFieldURL
+)
+
+// ParseCustomFieldType parses a custom field type
+func ParseCustomFieldType(value string) (CustomFieldType, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for _, fieldType := range database.CustomFieldTypes {
+		if value == fieldType {
+			return CustomFieldType(value), nil
+		}
+	}
+	return "", fmt.Errorf("invalid custom field type %q: use %s", value, strings.Join(database.CustomFieldTypes, ", "))
+}
+
+// CustomField is a typed field of the tasks of a category or, when ProjectID
+// is set, of a project. Options lists the allowed values of enum fields.
+type CustomField struct {
+	ID         int             `json:"id"`
+	Name       string          `json:"name"`
+	Type       CustomFieldType `json:"type"`
+	Options    []string        `json:"options,omitempty"`
+	CategoryID *int            `json:"category_id,omitempty"`
+	ProjectID  *int            `json:"project_id,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+// CustomFieldValue is the value of a custom field on a task. Values are
+// normalized: numbers without trailing zeros, dates as YYYY-MM-DD, user IDs
+// as decimals and enum values spelled as their option.
+type CustomFieldValue struct {
+	FieldID int             `json:"field_id"`
+	Name    string          `json:"name,omitempty"`
+	Type    CustomFieldType `json:"type,omitempty"`
+	Value   string          `json:"value"`
+}
+
+func convertFromDatabaseCustomField(df *database.CustomField) CustomField {
+	return CustomField{
+		ID:         df.ID,
+		Name:       df.Name,
+		Type:       CustomFieldType(df.Type),
+		Options:    df.Options,
+		CategoryID: df.CategoryID,
+		ProjectID:  df.ProjectID,
+		CreatedAt:  df.CreatedAt,
+		UpdatedAt:  df.UpdatedAt,
+	}
+}
+
+// convertFromDatabaseCustomValues converts the values of a task, returning
+// nil when there are none
+func convertFromDatabaseCustomValues(dbValues []database.CustomFieldValue) []CustomFieldValue {
+	if len(dbValues) == 0 {
+		return nil
+	}
+
+	values := make([]CustomFieldValue, len(dbValues))
+	for i, value := range dbValues {
+		values[i] = CustomFieldValue{
+			FieldID: value.FieldID,
+			Name:    value.Name,
+			Type:    CustomFieldType(value.Type),
+			Value:   value.Value,
+		}
+	}
+	return values
+}
+
+// checkFieldDefinition trims the name and options of a field and validates
+// them against its type and the other fields of its category or project
+func checkFieldDefinition(dbField *database.CustomField, others []database.CustomField) error {
+	dbField.Name = strings.TrimSpace(dbField.Name)
+	if dbField.Name == "" {
+		return errors.New("invalid custom field: name cannot be empty")
+	}
+	if len([]rune(dbField.Name)) > maxBoardNameLength {
+		return fmt.Errorf("invalid custom field: name cannot be longer than %d characters", maxBoardNameLength)
+	}
+	for _, other := range others {
+		if other.ID != dbField.ID && strings.EqualFold(other.Name, dbField.Name) {
+			return fmt.Errorf("invalid custom field: a field named %q already exists", other.Name)
+		}
+	}
+
+	if CustomFieldType(dbField.Type) != FieldEnum {
+		if len(dbField.Options) > 0 {
+			return errors.New("invalid custom field: only enum fields have options")
+		}
+		return nil
+	}
+
+	if len(dbField.Options) == 0 {
+		return errors.New("invalid custom field: an enum field needs at least one option")
+	}
+	if len(dbField.Options) > maxFieldOptions {
+		return fmt.Errorf("invalid custom field: an enum field has at most %d options", maxFieldOptions)
+	}
+	options := make([]string, len(dbField.Options))
+	for i, option := range dbField.Options {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			return errors.New("invalid custom field: options cannot be empty")
+		}
+		for _, previous := range options[:i] {
+			if strings.EqualFold(previous, option) {
+				return fmt.Errorf("invalid custom field: option %q is listed more than once", option)
+			}
+		}
+		options[i] = option
+	}
+	dbField.Options = options
+	return nil
+}
+
+// CustomFieldManager manages custom field definitions and their values on
+// tasks. Fields of a project, or of a category of a project, are seen by its
+// members and defined by its owners; fields of global categories are shared
+// like the categories themselves.
+type CustomFieldManager struct {
+	repository database.Repository
+}
+
+// NewCustomFieldManager creates a new custom field manager
+func NewCustomFieldManager(repository database.Repository) *CustomFieldManager {
+	return &CustomFieldManager{repository: repository}
+}
+
+// WithContext returns a copy of the custom field manager whose queries run with ctx
+func (cfm *CustomFieldManager) WithContext(ctx context.Context) *CustomFieldManager {
+	if cfm.repository == nil {
+		return cfm
+	}
+	return &CustomFieldManager{repository: cfm.repository.WithContext(ctx)}
+}
+
+// fieldProject returns the project that fields of a category or a project
+// belong to: the project itself, the project of the category, or nil for a
+// global category
+func (cfm *CustomFieldManager) fieldProject(categoryID, projectID *int) (*int, error) {
+	if projectID != nil {
+		if _, err := cfm.repository.GetProject(*projectID); err != nil {
+			return nil, err
+		}
+		return projectID, nil
+	}
+
+	category, err := cfm.repository.GetCategory(*categoryID)
+	if err != nil {
+		return nil, err
+	}
+	return category.ProjectID, nil
+}
+
+// checkScope checks that fields are listed or defined for exactly one
+// category or project that the user can see, returning its project
+func (cfm *CustomFieldManager) checkScope(userID int, categoryID, projectID *int) (*int, error) {
+	if (categoryID == nil) == (projectID == nil) {
+		return nil, errors.New("invalid custom field: give either a category or a project")
+	}
+
+	project, err := cfm.fieldProject(categoryID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if project != nil {
+		role, err := projectRole(cfm.repository, userID, *project)
+		if err != nil {
+			return nil, err
+		}
+		if role == "" {
+			if projectID != nil {
+				return nil, fmt.Errorf("project with ID %d not found", *projectID)
+			}
+			return nil, fmt.Errorf("category with ID %d not found", *categoryID)
+		}
+	}
+	return project, nil
+}
+
+// checkProjectOwner returns an error unless the fields of the project, if
+// any, can be defined by the user
+func (cfm *CustomFieldManager) checkProjectOwner(userID int, project *int) error {
+	if project == nil {
+		return nil
+	}
+
+	role, err := projectRole(cfm.repository, userID, *project)
+	if err != nil {
+		return err
+	}
+	if role != ProjectOwner {
+		return errors.New("access denied: only a project owner can change the custom fields of a project")
+	}
+	return nil
+}
+
+// userField returns a custom field the user can see, with its project
+func (cfm *CustomFieldManager) userField(userID, fieldID int) (*database.CustomField, *int, error) {
+	dbField, err := cfm.repository.GetCustomField(fieldID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	project, err := cfm.checkScope(userID, dbField.CategoryID, dbField.ProjectID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("custom field with ID %d not found", fieldID)
+	}
+	return dbField, project, nil
+}
+
+// CreateField defines a custom field for the tasks of a category or of a project
+func (cfm *CustomFieldManager) CreateField(userID int, name string, fieldType CustomFieldType, options []string, categoryID, projectID *int) (*CustomField, error) {
+	fieldType, err := ParseCustomFieldType(string(fieldType))
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := cfm.checkScope(userID, categoryID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfm.checkProjectOwner(userID, project); err != nil {
+		return nil, err
+	}
+
+	others, err := cfm.repository.GetCustomFields(categoryID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	dbField := &database.CustomField{
+		Name:       name,
+		Type:       string(fieldType),
+		Options:    options,
+		CategoryID: categoryID,
+		ProjectID:  projectID,
+	}
+	if err := checkFieldDefinition(dbField, others); err != nil {
+		return nil, err
+	}
+
+	if err := cfm.repository.CreateCustomField(dbField); err != nil {
+		return nil, err
+	}
+
+	field := convertFromDatabaseCustomField(dbField)
+	return &field, nil
+}
+
+// GetFields returns the custom fields of a category or of a project, by name
+func (cfm *CustomFieldManager) GetFields(userID int, categoryID, projectID *int) ([]CustomField, error) {
+	if _, err := cfm.checkScope(userID, categoryID, projectID); err != nil {
+		return nil, err
+	}
+
+	dbFields, err := cfm.repository.GetCustomFields(categoryID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]CustomField, len(dbFields))
+	for i := range dbFields {
+		fields[i] = convertFromDatabaseCustomField(&dbFields[i])
+	}
+	return fields, nil
+}
+
+// GetField returns a custom field
+func (cfm *CustomFieldManager) GetField(userID, fieldID int) (*CustomField, error) {
+	dbField, _, err := cfm.userField(userID, fieldID)
+	if err != nil {
+		return nil, err
+	}
+
+	field := convertFromDatabaseCustomField(dbField)
+	return &field, nil
+}
+
+// UpdateField renames a custom field and replaces the options of an enum
+// field. The type of a field cannot change, and tasks lose the enum values
+// that are no longer among the options.
+func (cfm *CustomFieldManager) UpdateField(userID, fieldID int, name string, options []string) (*CustomField, error) {
+	dbField, project, err := cfm.userField(userID, fieldID)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfm.checkProjectOwner(userID, project); err != nil {
+		return nil, err
+	}
+
+	others, err := cfm.repository.GetCustomFields(dbField.CategoryID, dbField.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	dbField.Name = name
+	dbField.Options = options
+	if err := checkFieldDefinition(dbField, others); err != nil {
+		return nil, err
+	}
+
+	if err := cfm.repository.UpdateCustomField(dbField); err != nil {
+		return nil, err
+	}
+
+	field := convertFromDatabaseCustomField(dbField)
+	return &field, nil
+}
+
+// DeleteField deletes a custom field with its values on every task
+func (cfm *CustomFieldManager) DeleteField(userID, fieldID int) error {
+	_, project, err := cfm.userField(userID, fieldID)
+	if err != nil {
+		return err
+	}
+	if err := cfm.checkProjectOwner(userID, project); err != nil {
+		return err
+	}
+
+	return cfm.repository.DeleteCustomField(fieldID)
+}
+
+// SetTaskValues sets custom field values on a task the user owns or that
+// belongs to one of their projects, and returns all values of the task. Each
+// field must belong to the category or the project of the task; an empty
+// value removes the value of its field. Nothing is changed when a value is
+// invalid.
+func (cfm *CustomFieldManager) SetTaskValues(userID, taskID int, values []CustomFieldValue) ([]CustomFieldValue, error) {
+	dbTask, err := cfm.repository.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	editable := dbTask.UserID != nil && *dbTask.UserID == userID
+	if !editable && dbTask.ProjectID != nil {
+		role, err := projectRole(cfm.repository, userID, *dbTask.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		editable = role != ""
+	}
+	if !editable {
+		return nil, errors.New("access denied: task does not belong to user or user ID is missing")
+	}
+
+	normalized := make([]CustomFieldValue, len(values))
+	for i, value := range values {
+		dbField, err := cfm.repository.GetCustomField(value.FieldID)
+		if err != nil || !dbField.AppliesTo(dbTask) {
+			return nil, fmt.Errorf("invalid custom field value: field %d does not apply to task %d", value.FieldID, taskID)
+		}
+
+		normalized[i] = CustomFieldValue{FieldID: dbField.ID}
+		if strings.TrimSpace(value.Value) == "" {
+			continue
+		}
+		if normalized[i].Value, err = dbField.NormalizeValue(value.Value); err != nil {
+			return nil, err
+		}
+		if CustomFieldType(dbField.Type) == FieldUser {
+			if err := cfm.checkUserValue(dbField, normalized[i].Value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, value := range normalized {
+		if err := cfm.repository.SetTaskCustomValue(taskID, value.FieldID, value.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	dbValues, err := cfm.repository.GetTaskCustomValues(taskID)
+	if err != nil {
+		return nil, err
+	}
+	result := convertFromDatabaseCustomValues(dbValues)
+	if result == nil {
+		result = []CustomFieldValue{}
+	}
+	return result, nil
+}
+
+// checkUserValue returns an error unless a user value names an active user
+func (cfm *CustomFieldManager) checkUserValue(dbField *database.CustomField, value string) error {
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("invalid value %q for custom field %q: not a user ID", value, dbField.Name)
+	}
+	user, err := cfm.repository.GetUser(id)
+	if err != nil || !user.IsActive {
+		return fmt.Errorf("invalid value %q for custom field %q: no such user", value, dbField.Name)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package task
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCustomFields(t *testing.T) {
+	repository := setupTestRepository(t)
+	um := NewUserManager(repository)
+	cm := NewCategoryManager(repository)
+	pm := NewProjectManager(repository)
+	cfm := NewCustomFieldManager(repository)
+
+	owner, err := um.RegisterUser("owner", "owner@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	member, err := um.RegisterUser("member", "member@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	outsider, err := um.RegisterUser("outsider", "outsider@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+
+	project, err := pm.CreateProject(owner.ID, "Launch", "", ProjectSettings{})
+	if err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	if _, err := pm.SetMember(owner.ID, project.ID, member.Username, ProjectMember); err != nil {
+		t.Fatalf("Failed to add member: %v", err)
+	}
+	bugs, err := cm.CreateCategory("Bugs", "", "")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+
+	invalid := []struct {
+		name      string
+		fieldType CustomFieldType
+		options   []string
+		category  *int
+		project   *int
+	}{
+		{"Severity", FieldEnum, []string{"low"}, nil, nil},
+		{"Severity", FieldEnum, []string{"low"}, &bugs.ID, &project.ID},
+		{"Severity", FieldEnum, nil, nil, &project.ID},
+		{"Severity", FieldEnum, []string{"low", "LOW"}, nil, &project.ID},
+		{"Points", FieldNumber, []string{"1"}, nil, &project.ID},
+		{"", FieldText, nil, nil, &project.ID},
+		{"Colour", "colour", nil, nil, &project.ID},
+	}
+	for _, tt := range invalid {
+		if _, err := cfm.CreateField(owner.ID, tt.name, tt.fieldType, tt.options, tt.category, tt.project); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+			t.Errorf("Expected invalid error for %+v, got %v", tt, err)
+		}
+	}
+
+	if _, err := cfm.CreateField(member.ID, "Severity", FieldEnum, []string{"low"}, nil, &project.ID); err == nil || !strings.HasPrefix(err.Error(), "access denied: only") {
+		t.Errorf("Expected members not to define project fields, got %v", err)
+	}
+	if _, err := cfm.CreateField(outsider.ID, "Severity", FieldEnum, []string{"low"}, nil, &project.ID); err == nil || !strings.HasSuffix(err.Error(), "not found") {
+		t.Errorf("Expected outsiders not to see the project, got %v", err)
+	}
+
+	severity, err := cfm.CreateField(owner.ID, "Severity", FieldEnum, []string{"low", "medium", "high"}, nil, &project.ID)
+	if err != nil {
+		t.Fatalf("Failed to create field: %v", err)
+	}
+	points, err := cfm.CreateField(owner.ID, "Points", FieldNumber, nil, nil, &project.ID)
+	if err != nil {
+		t.Fatalf("Failed to create field: %v", err)
+	}
+	reviewer, err := cfm.CreateField(owner.ID, "Reviewer", FieldUser, nil, nil, &project.ID)
+	if err != nil {
+		t.Fatalf("Failed to create field: %v", err)
+	}
+	if _, err := cfm.CreateField(owner.ID, "severity", FieldText, nil, nil, &project.ID); err == nil {
+		t.Error("Expected duplicate field name to be rejected")
+	}
+	found, err := cfm.CreateField(outsider.ID, "Found in", FieldURL, nil, &bugs.ID, nil)
+	if err != nil {
+		t.Fatalf("Expected anyone to define fields of a global category: %v", err)
+	}
+
+	fields, err := cfm.GetFields(member.ID, nil, &project.ID)
+	if err != nil {
+		t.Fatalf("Failed to get fields: %v", err)
+	}
+	if len(fields) != 3 || fields[0].Name != "Points" || fields[1].Name != "Reviewer" || fields[2].Name != "Severity" {
+		t.Errorf("Expected the project fields by name, got %+v", fields)
+	}
+	if _, err := cfm.GetField(outsider.ID, severity.ID); err == nil || !strings.HasSuffix(err.Error(), "not found") {
+		t.Errorf("Expected outsiders not to see project fields, got %v", err)
+	}
+
+	created, err := um.CreateUserTask(member.ID, "Crash on start", "", High, nil)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	dbTask, err := repository.GetTask(created.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	dbTask.ProjectID = &project.ID
+	if err := repository.UpdateTask(dbTask); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+
+	badValues := [][]CustomFieldValue{
+		{{FieldID: severity.ID, Value: "critical"}},
+		{{FieldID: points.ID, Value: "lots"}},
+		{{FieldID: reviewer.ID, Value: "9999"}},
+		{{FieldID: found.ID, Value: "https://example.com/crash"}},
+		{{FieldID: points.ID, Value: "3"}, {FieldID: severity.ID, Value: "urgent"}},
+	}
+	for _, values := range badValues {
+		if _, err := cfm.SetTaskValues(owner.ID, created.ID, values); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+			t.Errorf("Expected invalid error for %+v, got %v", values, err)
+		}
+	}
+	if values, err := repository.GetTaskCustomValues(created.ID); err != nil || len(values) != 0 {
+		t.Errorf("Expected rejected values not to be stored, got %+v (%v)", values, err)
+	}
+	if _, err := cfm.SetTaskValues(outsider.ID, created.ID, []CustomFieldValue{{FieldID: points.ID, Value: "1"}}); err == nil {
+		t.Error("Expected outsiders not to set values")
+	}
+
+	values, err := cfm.SetTaskValues(owner.ID, created.ID, []CustomFieldValue{
+		{FieldID: severity.ID, Value: "HIGH"},
+		{FieldID: points.ID, Value: "5.50"},
+		{FieldID: reviewer.ID, Value: strconv.Itoa(member.ID)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to set values: %v", err)
+	}
+	want := map[string]string{"Severity": "high", "Points": "5.5", "Reviewer": strconv.Itoa(member.ID)}
+	if len(values) != len(want) {
+		t.Fatalf("Expected %d values, got %+v", len(want), values)
+	}
+	for _, value := range values {
+		if want[value.Name] != value.Value {
+			t.Errorf("Expected %s to be %q, got %q", value.Name, want[value.Name], value.Value)
+		}
+	}
+
+	loaded, err := um.GetVisibleTask(member.ID, created.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if len(loaded.CustomFields) != 3 {
+		t.Errorf("Expected the task to carry its custom fields, got %+v", loaded.CustomFields)
+	}
+
+	// Clearing a value and dropping an enum option both remove values
+	if values, err = cfm.SetTaskValues(member.ID, created.ID, []CustomFieldValue{{FieldID: points.ID, Value: ""}}); err != nil || len(values) != 2 {
+		t.Errorf("Expected cleared value to be removed, got %+v (%v)", values, err)
+	}
+	if _, err := cfm.UpdateField(member.ID, severity.ID, "Severity", []string{"low"}); err == nil || !strings.HasPrefix(err.Error(), "access denied: only") {
+		t.Errorf("Expected members not to change project fields, got %v", err)
+	}
+	updated, err := cfm.UpdateField(owner.ID, severity.ID, "Impact", []string{"low", "medium"})
+	if err != nil {
+		t.Fatalf("Failed to update field: %v", err)
+	}
+	if updated.Name != "Impact" || updated.Type != FieldEnum {
+		t.Errorf("Expected renamed enum field, got %+v", updated)
+	}
+	if values, _ := repository.GetTaskCustomValues(created.ID); len(values) != 1 || values[0].FieldID != reviewer.ID {
+		t.Errorf("Expected only the reviewer value to remain, got %+v", values)
+	}
+
+	// Deleting a field or moving the task out of its project removes values
+	if _, err := cfm.SetTaskValues(member.ID, created.ID, []CustomFieldValue{{FieldID: points.ID, Value: "2"}}); err != nil {
+		t.Fatalf("Failed to set value: %v", err)
+	}
+	if err := cfm.DeleteField(owner.ID, points.ID); err != nil {
+		t.Fatalf("Failed to delete field: %v", err)
+	}
+	if values, _ := repository.GetTaskCustomValues(created.ID); len(values) != 1 {
+		t.Errorf("Expected deleted field to lose its values, got %+v", values)
+	}
+	dbTask, _ = repository.GetTask(created.ID)
+	dbTask.ProjectID = nil
+	if err := repository.UpdateTask(dbTask); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+	if values, _ := repository.GetTaskCustomValues(created.ID); len(values) != 0 {
+		t.Errorf("Expected values of fields that no longer apply to be removed, got %+v", values)
+	}
+}
@@ -1,12 +1,16 @@
 package task
 
 import (
+	"context"
+	"time"
+
 	"learn-go-capstone/internal/database"
 )
 
 // DependencyManager manages task dependencies
 type DependencyManager struct {
 	repository database.Repository
+	events     *EventBus
 }
 
 // NewDependencyManager creates a new dependency manager
@@ -16,10 +20,26 @@ func NewDependencyManager(repository database.Repository) *DependencyManager {
 	}
 }
 
+// SetEventBus sets the bus that new dependencies are published to
+func (dm *DependencyManager) SetEventBus(bus *EventBus) {
+	dm.events = bus
+}
+
+// WithContext returns a copy of the dependency manager whose queries run with ctx
+func (dm *DependencyManager) WithContext(ctx context.Context) *DependencyManager {
+	if dm.repository == nil {
+		return dm
+	}
+
+	bound := *dm
+	bound.repository = dm.repository.WithContext(ctx)
+	return &bound
+}
+
 // AddDependency adds a dependency between two tasks
 func (dm *DependencyManager) AddDependency(taskID, dependsOnTaskID int) error {
 	// Validate that both tasks exist
-	_, err := dm.repository.GetTask(taskID)
+	dbTask, err := dm.repository.GetTask(taskID)
 	if err != nil {
 		return err
 	}
@@ -30,7 +50,14 @@ func (dm *DependencyManager) AddDependency(taskID, dependsOnTaskID int) error {
 	}
 	
 	// Add the dependency (this will check for circular dependencies)
-	return dm.repository.AddTaskDependency(taskID, dependsOnTaskID)
+	if err := dm.repository.AddTaskDependency(taskID, dependsOnTaskID); err != nil {
+		return err
+	}
+	
+	event := databaseTaskEvent(EventDependencyAdded, dbTask)
+	event.DependsOnTaskID = dependsOnTaskID
+	dm.events.Publish(event)
+	return nil
 }
 
 // RemoveDependency removes a dependency between two tasks
@@ -151,7 +178,8 @@ func (dm *DependencyManager) GetBlockedTasks() ([]Task, error) {
 	return blockedTasks, nil
 }
 
-// GetReadyTasks returns all tasks that are ready to be started (no incomplete dependencies)
+// GetReadyTasks returns all tasks that are ready to be started (no incomplete
+// dependencies). Snoozed tasks are not ready until they wake up.
 func (dm *DependencyManager) GetReadyTasks() ([]Task, error) {
 	allTasks, err := dm.repository.GetAllTasks()
 	if err != nil {
@@ -159,7 +187,7 @@ func (dm *DependencyManager) GetReadyTasks() ([]Task, error) {
 	}
 	
 	var readyTasks []Task
-	for _, dbTask := range allTasks {
+	for _, dbTask := range awakeDatabaseTasks(allTasks, time.Now()) {
 		task := convertFromDatabaseTask(&dbTask)
 		if task.Status == Pending {
 			canComplete, err := dm.CanCompleteTask(task.ID)
@@ -0,0 +1,303 @@
+package task
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"learn-go-capstone/internal/database"
+)
+
+// EventType identifies what happened to a task
+type EventType string
+
+const (
+	// EventTaskCreated is published when a task is stored for the first time
+	EventTaskCreated EventType = "task.created"
+	// EventTaskUpdated is published when fields other than the status change
+	EventTaskUpdated EventType = "task.updated"
+	// EventStatusChanged is published when the status of a task changes
+	EventStatusChanged EventType = "task.status_changed"
+	// EventTaskDeleted is published when a task is moved to the trash
+	EventTaskDeleted EventType = "task.deleted"
+	// EventTaskRestored is published when a task is taken out of the trash
+	EventTaskRestored EventType = "task.restored"
+	// EventDependencyAdded is published when a task starts depending on another task
+	EventDependencyAdded EventType = "task.dependency_added"
+	// EventTagAttached is published when a tag is added to a task
+	EventTagAttached EventType = "task.tag_attached"
+	// EventCommentAdded is published when a comment or reply is posted on a task
+	EventCommentAdded EventType = "task.comment_added"
+	// EventCommentEdited is published when the author changes a comment
+	EventCommentEdited EventType = "task.comment_edited"
+	// EventTaskWoken is published when a snoozed task comes back into the listings
+	EventTaskWoken EventType = "task.woken"
+)
+
+// Event describes a single task mutation
+type Event struct {
+	Type     EventType
+	Sequence uint64 // assigned by the bus, increasing in publish order
+	Time     time.Time
+	TaskID   int
+	// UserID is the owner of the task, when known
+	UserID *int
+	// Task is the state after the change, or the removed task for EventTaskDeleted
+	Task *Task
+	// OldStatus and NewStatus are set for EventStatusChanged
+	OldStatus Status
+	NewStatus Status
+	// DependsOnTaskID is set for EventDependencyAdded
+	DependsOnTaskID int
+	// TagID is set for EventTagAttached
+	TagID int
+	// Comment is set for EventCommentAdded and EventCommentEdited; Mentions
+	// holds the users it mentions for the first time
+	Comment  *Comment
+	Mentions []int
+}
+
+// EventHandler receives published events
+type EventHandler func(Event)
+
+// EventBus delivers task events to in-process subscribers.
+//
+// Synchronous subscribers run on the publishing goroutine before the mutating
+// call returns, so they must be quick and must not call back into the manager
+// that published the event. Asynchronous subscribers each get their own queue
+// and goroutine. Managers publish while the mutation is still serialized, so
+// every subscriber sees the events of one task in the order they happened.
+// Publishing to a nil *EventBus is a no-op, so managers work without one.
+type EventBus struct {
+	mu          sync.Mutex
+	sequence    uint64
+	nextID      int
+	subscribers []*subscription
+	closed      bool
+	wg          sync.WaitGroup
+}
+
+// subscription is a single registered handler
+type subscription struct {
+	id      int
+	handler EventHandler
+	types   map[EventType]bool
+	queue   *eventQueue // nil for synchronous subscribers
+}
+
+// NewEventBus creates an empty event bus
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+// Subscribe registers a synchronous handler for the given event types, or for
+// every event when no types are given. The returned function unsubscribes.
+func (b *EventBus) Subscribe(handler EventHandler, types ...EventType) func() {
+	return b.subscribe(handler, types, nil)
+}
+
+// SubscribeAsync registers a handler that runs on its own goroutine. Events are
+// queued without blocking the publisher and delivered one at a time in order.
+func (b *EventBus) SubscribeAsync(handler EventHandler, types ...EventType) func() {
+	return b.subscribe(handler, types, newEventQueue())
+}
+
+func (b *EventBus) subscribe(handler EventHandler, types []EventType, queue *eventQueue) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := &subscription{
+		id:      b.nextID,
+		handler: handler,
+		queue:   queue,
+	}
+	b.nextID++
+
+	if len(types) > 0 {
+		sub.types = make(map[EventType]bool, len(types))
+		for _, eventType := range types {
+			sub.types[eventType] = true
+		}
+	}
+
+	if queue != nil {
+		if b.closed {
+			queue.close()
+		}
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			queue.run(handler)
+		}()
+	}
+
+	b.subscribers = append(b.subscribers, sub)
+	return func() { b.unsubscribe(sub.id) }
+}
+
+func (b *EventBus) unsubscribe(id int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, sub := range b.subscribers {
+		if sub.id == id {
+			if sub.queue != nil {
+				sub.queue.close()
+			}
+			b.subscribers = append(b.subscribers[:i], b.subscribers[i+1:]...)
+			return
+		}
+	}
+}
+
+// Publish delivers events to every interested subscriber
+func (b *EventBus) Publish(events ...Event) {
+	if b == nil {
+		return
+	}
+
+	for _, event := range events {
+		b.mu.Lock()
+		if b.closed {
+			b.mu.Unlock()
+			return
+		}
+
+		b.sequence++
+		event.Sequence = b.sequence
+		if event.Time.IsZero() {
+			event.Time = time.Now()
+		}
+
+		var syncHandlers []EventHandler
+		for _, sub := range b.subscribers {
+			if sub.types != nil && !sub.types[event.Type] {
+				continue
+			}
+			if sub.queue != nil {
+				sub.queue.push(event)
+			} else {
+				syncHandlers = append(syncHandlers, sub.handler)
+			}
+		}
+		b.mu.Unlock()
+
+		for _, handler := range syncHandlers {
+			deliver(handler, event)
+		}
+	}
+}
+
+// Close stops accepting events and waits until every asynchronous subscriber
+// has handled the events already queued for it
+func (b *EventBus) Close() {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		for _, sub := range b.subscribers {
+			if sub.queue != nil {
+				sub.queue.close()
+			}
+		}
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+}
+
+// deliver calls a handler, making sure a panicking subscriber cannot break the publisher
+func deliver(handler EventHandler, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("event subscriber panicked on %s for task %d: %v", event.Type, event.TaskID, r)
+		}
+	}()
+
+	handler(event)
+}
+
+// eventQueue is an unbounded FIFO feeding one asynchronous subscriber
+type eventQueue struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	events []Event
+	closed bool
+}
+
+func newEventQueue() *eventQueue {
+	q := &eventQueue{}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+func (q *eventQueue) push(event Event) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.closed {
+		q.events = append(q.events, event)
+		q.cond.Signal()
+	}
+}
+
+func (q *eventQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.closed = true
+	q.cond.Signal()
+}
+
+// run delivers queued events until the queue is closed and drained
+func (q *eventQueue) run(handler EventHandler) {
+	for {
+		q.mu.Lock()
+		for len(q.events) == 0 && !q.closed {
+			q.cond.Wait()
+		}
+		if len(q.events) == 0 {
+			q.mu.Unlock()
+			return
+		}
+		event := q.events[0]
+		q.events = q.events[1:]
+		q.mu.Unlock()
+
+		deliver(handler, event)
+	}
+}
+
+// LogEvents returns a handler that writes every event to logger, for audit logging
+func LogEvents(logger *log.Logger) EventHandler {
+	return func(event Event) {
+		switch event.Type {
+		case EventStatusChanged:
+			logger.Printf("[%d] %s task=%d %s -> %s", event.Sequence, event.Type, event.TaskID, event.OldStatus, event.NewStatus)
+		case EventDependencyAdded:
+			logger.Printf("[%d] %s task=%d depends_on=%d", event.Sequence, event.Type, event.TaskID, event.DependsOnTaskID)
+		case EventTagAttached:
+			logger.Printf("[%d] %s task=%d tag=%d", event.Sequence, event.Type, event.TaskID, event.TagID)
+		case EventCommentAdded, EventCommentEdited:
+			logger.Printf("[%d] %s task=%d comment=%d mentions=%v", event.Sequence, event.Type, event.TaskID, event.Comment.ID, event.Mentions)
+		default:
+			logger.Printf("[%d] %s task=%d", event.Sequence, event.Type, event.TaskID)
+		}
+	}
+}
+
+// Event constructors used by the managers
+
+func taskEvent(eventType EventType, t Task, userID *int) Event {
+	return Event{Type: eventType, TaskID: t.ID, UserID: userID, Task: &t}
+}
+
+func statusChangedEvent(t Task, userID *int, oldStatus Status) Event {
+	event := taskEvent(EventStatusChanged, t, userID)
+	event.OldStatus = oldStatus
+	event.NewStatus = t.Status
+	return event
+}
+
+func databaseTaskEvent(eventType EventType, dbTask *database.DatabaseTask) Event {
+	return taskEvent(eventType, convertFromDatabaseTask(dbTask), dbTask.UserID)
+}
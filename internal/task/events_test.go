@@ -0,0 +1,159 @@
+package task
+
+import (
+	"sync"
+	"testing"
+)
+
+// recorder collects the events delivered to a subscriber
+type recorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (r *recorder) handle(event Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) types() []EventType {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	types := make([]EventType, len(r.events))
+	for i, event := range r.events {
+		types[i] = event.Type
+	}
+	return types
+}
+
+func assertEventTypes(t *testing.T, got []EventType, want ...EventType) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("Expected events %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected events %v, got %v", want, got)
+		}
+	}
+}
+
+func TestEventBusDelivery(t *testing.T) {
+	bus := NewEventBus()
+
+	var all, statuses, async recorder
+	bus.Subscribe(all.handle)
+	unsubscribe := bus.Subscribe(statuses.handle, EventStatusChanged)
+	bus.SubscribeAsync(async.handle)
+	bus.Subscribe(func(Event) { panic("broken subscriber") })
+
+	for i := 0; i < 100; i++ {
+		bus.Publish(Event{Type: EventTaskUpdated, TaskID: 1})
+	}
+	bus.Publish(Event{Type: EventStatusChanged, TaskID: 1})
+	unsubscribe()
+	bus.Publish(Event{Type: EventStatusChanged, TaskID: 1})
+
+	bus.Close()
+	bus.Publish(Event{Type: EventTaskDeleted, TaskID: 1})
+
+	if len(all.events) != 102 {
+		t.Errorf("Expected 102 events, got %d", len(all.events))
+	}
+	assertEventTypes(t, statuses.types(), EventStatusChanged)
+
+	if len(async.events) != 102 {
+		t.Fatalf("Expected Close to drain 102 async events, got %d", len(async.events))
+	}
+	for i, event := range async.events {
+		if event.Sequence != uint64(i+1) {
+			t.Fatalf("Expected async events in publish order, got sequence %d at %d", event.Sequence, i)
+		}
+	}
+}
+
+func TestTaskManagerPublishesEvents(t *testing.T) {
+	tm := NewTaskManager()
+	bus := NewEventBus()
+	tm.SetEventBus(bus)
+
+	var events recorder
+	bus.Subscribe(events.handle)
+
+	created := tm.AddTask("Water plants", "", Low, nil)
+	if _, err := tm.UpdateTask(created.ID, TaskPatch{Title: stringPtr("Water all plants")}); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+	if err := tm.UpdateTaskStatus(created.ID, InProgress); err != nil {
+		t.Fatalf("Failed to update status: %v", err)
+	}
+	if err := tm.UpdateTaskStatus(created.ID, InProgress); err != nil {
+		t.Fatalf("Failed to update status: %v", err)
+	}
+	if err := tm.DeleteTask(created.ID); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+
+	assertEventTypes(t, events.types(), EventTaskCreated, EventTaskUpdated, EventStatusChanged, EventTaskDeleted)
+
+	statusChanged := events.events[2]
+	if statusChanged.OldStatus != Pending || statusChanged.NewStatus != InProgress {
+		t.Errorf("Unexpected status change %s -> %s", statusChanged.OldStatus, statusChanged.NewStatus)
+	}
+	if deleted := events.events[3]; deleted.Task == nil || deleted.Task.Title != "Water all plants" {
+		t.Errorf("Expected the deleted task in the event, got %+v", deleted.Task)
+	}
+}
+
+func TestDatabaseManagersPublishEvents(t *testing.T) {
+	repository := setupTestRepository(t)
+	bus := NewEventBus()
+
+	htm := NewHybridTaskManager(repository, DatabaseStorage)
+	htm.SetEventBus(bus)
+	cm := NewCategoryManager(repository)
+	cm.SetEventBus(bus)
+	dm := NewDependencyManager(repository)
+	dm.SetEventBus(bus)
+
+	var events recorder
+	bus.Subscribe(events.handle)
+
+	parent := htm.AddTask("Release", "", High, nil)
+	child := htm.AddTask("Changelog", "", Medium, nil)
+	if err := htm.hierarchy.SetParent(child.ID, &parent.ID); err != nil {
+		t.Fatalf("Failed to set parent: %v", err)
+	}
+
+	tag, err := cm.CreateTag("release", "#00ff00")
+	if err != nil {
+		t.Fatalf("Failed to create tag: %v", err)
+	}
+	if err := cm.AddTagToTask(parent.ID, tag.ID); err != nil {
+		t.Fatalf("Failed to tag task: %v", err)
+	}
+	if err := dm.AddDependency(parent.ID, child.ID); err != nil {
+		t.Fatalf("Failed to add dependency: %v", err)
+	}
+	if err := htm.UpdateTaskStatus(child.ID, Completed); err != nil {
+		t.Fatalf("Failed to complete task: %v", err)
+	}
+	if err := htm.DeleteTask(parent.ID); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+
+	assertEventTypes(t, events.types(),
+		EventTaskCreated, EventTaskCreated, EventTaskUpdated,
+		EventTagAttached, EventDependencyAdded, EventStatusChanged,
+		EventTaskDeleted, EventTaskDeleted)
+
+	if dependency := events.events[4]; dependency.TaskID != parent.ID || dependency.DependsOnTaskID != child.ID {
+		t.Errorf("Unexpected dependency event %+v", dependency)
+	}
+	// The cascade deletes the subtask before its parent
+	if events.events[6].TaskID != child.ID || events.events[7].TaskID != parent.ID {
+		t.Errorf("Expected the subtask to be deleted first, got %d then %d", events.events[6].TaskID, events.events[7].TaskID)
+	}
+}
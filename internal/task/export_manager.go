@@ -3,6 +3,7 @@ package task
 import (
 	"time"
 
+	"learn-go-capstone/internal/attachments"
 	"learn-go-capstone/internal/database"
 	"learn-go-capstone/internal/export"
 )
@@ -23,6 +24,11 @@ func NewExportManager(repository database.Repository) *ExportManager {
 	}
 }
 
+// SetAttachmentStore sets the store that backups read attachment contents from
+func (em *ExportManager) SetAttachmentStore(store *attachments.Store) {
+	em.exportService.SetAttachmentStore(store)
+}
+
 // ExportTasks exports tasks to a file
 func (em *ExportManager) ExportTasks(options export.ExportOptions) (*export.ExportResult, error) {
 	return em.exportService.ExportTasks(options)
@@ -166,13 +172,15 @@ func (em *ExportManager) GetImportPreview(filePath string, format export.ExportF
 	return em.ImportTasks(filePath, options)
 }
 
-// ExportBackup creates a complete backup of all data
+// ExportBackup creates a complete backup of all data, including the contents
+// of the task attachments
 func (em *ExportManager) ExportBackup() (*export.ExportResult, error) {
 	options := export.ExportOptions{
 		Format:           export.FormatJSON,
 		IncludeTags:      true,
 		IncludeCategories: true,
 		IncludeUsers:     true,
+		IncludeAttachments: true,
 	}
 	return em.ExportTasks(options)
 }
@@ -40,3 +40,9 @@ var _ TaskManagerInterface = (*TaskManager)(nil)
 
 // Ensure HybridTaskManager implements TaskManagerInterface
 var _ TaskManagerInterface = (*HybridTaskManager)(nil)
+
+// Ensure TaskManager implements TaskManagerV2
+var _ TaskManagerV2 = (*TaskManager)(nil)
+
+// Ensure HybridTaskManager implements TaskManagerV2
+var _ TaskManagerV2 = (*HybridTaskManager)(nil)
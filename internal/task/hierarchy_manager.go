@@ -0,0 +1,455 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"learn-go-capstone/internal/database"
+)
+
+// DeleteRule decides what happens to the subtasks of a deleted task
+type DeleteRule string
+
+const (
+	// DeleteCascade deletes the whole subtree
+	DeleteCascade DeleteRule = "cascade"
+	// DeleteOrphan turns the direct subtasks into top-level tasks
+	DeleteOrphan DeleteRule = "orphan"
+	// DeleteReparent moves the direct subtasks up to the deleted task's parent
+	DeleteReparent DeleteRule = "reparent"
+	// DeleteRestrict refuses to delete a task that still has subtasks
+	DeleteRestrict DeleteRule = "restrict"
+)
+
+// CompleteRule decides what happens to the subtasks of a completed task
+type CompleteRule string
+
+const (
+	// CompleteIgnore leaves the subtasks untouched
+	CompleteIgnore CompleteRule = "ignore"
+	// CompleteCascade completes every unfinished subtask as well
+	CompleteCascade CompleteRule = "cascade"
+	// CompleteRequire refuses to complete a task with unfinished subtasks
+	CompleteRequire CompleteRule = "require"
+)
+
+// CascadeRules configures how deleting or completing a parent task affects its subtasks
+type CascadeRules struct {
+	OnDelete   DeleteRule
+	OnComplete CompleteRule
+}
+
+// DefaultCascadeRules returns the default cascade rules
+func DefaultCascadeRules() CascadeRules {
+	return CascadeRules{
+		OnDelete:   DeleteCascade,
+		OnComplete: CompleteIgnore,
+	}
+}
+
+// Validate checks that both rules are known
+func (r CascadeRules) Validate() error {
+	switch r.OnDelete {
+	case DeleteCascade, DeleteOrphan, DeleteReparent, DeleteRestrict:
+	default:
+		return fmt.Errorf("invalid delete rule %q", r.OnDelete)
+	}
+
+	switch r.OnComplete {
+	case CompleteIgnore, CompleteCascade, CompleteRequire:
+	default:
+		return fmt.Errorf("invalid complete rule %q", r.OnComplete)
+	}
+
+	return nil
+}
+
+// HierarchyManager manages parent/child relationships between tasks
+type HierarchyManager struct {
+	repository database.Repository
+	rules      CascadeRules
+	workflow   *Workflow
+	events     *EventBus
+}
+
+// NewHierarchyManager creates a new hierarchy manager.
+// Invalid rules fall back to DefaultCascadeRules.
+func NewHierarchyManager(repository database.Repository, rules CascadeRules) *HierarchyManager {
+	if rules.Validate() != nil {
+		rules = DefaultCascadeRules()
+	}
+
+	return &HierarchyManager{
+		repository: repository,
+		rules:      rules,
+		workflow:   defaultDatabaseWorkflow(NewDependencyManager(repository)),
+	}
+}
+
+// WithContext returns a copy of the hierarchy manager whose queries run with ctx
+func (hm *HierarchyManager) WithContext(ctx context.Context) *HierarchyManager {
+	if hm.repository == nil {
+		return hm
+	}
+
+	bound := *hm
+	bound.repository = hm.repository.WithContext(ctx)
+	return &bound
+}
+
+// withRepository returns a copy of the hierarchy manager that makes its
+// changes with repository and publishes them to events
+func (hm *HierarchyManager) withRepository(repository database.Repository, events *EventBus) *HierarchyManager {
+	bound := *hm
+	bound.repository = repository
+	bound.workflow = hm.workflow.withRepository(repository)
+	bound.events = events
+	return &bound
+}
+
+// GetCascadeRules returns the cascade rules in use
+func (hm *HierarchyManager) GetCascadeRules() CascadeRules {
+	return hm.rules
+}
+
+// SetWorkflow replaces the workflow that status changes must follow
+func (hm *HierarchyManager) SetWorkflow(workflow *Workflow) {
+	hm.workflow = workflow
+}
+
+// GetWorkflow returns the workflow that status changes must follow
+func (hm *HierarchyManager) GetWorkflow() *Workflow {
+	return hm.workflow
+}
+
+// SetEventBus sets the bus that status changes and deletions are published to
+func (hm *HierarchyManager) SetEventBus(bus *EventBus) {
+	hm.events = bus
+}
+
+// SetParent moves a task under a new parent, or makes it a top-level task when parentID is nil
+func (hm *HierarchyManager) SetParent(taskID int, parentID *int) error {
+	dbTask, err := hm.repository.GetTask(taskID)
+	if err != nil {
+		return err
+	}
+
+	if parentID != nil {
+		if *parentID == taskID {
+			return errors.New("a task cannot be its own parent")
+		}
+
+		if _, err := hm.repository.GetTask(*parentID); err != nil {
+			return err
+		}
+
+		// The new parent must not be inside the task's own subtree
+		ancestors, err := hm.repository.GetAncestorTasks(*parentID)
+		if err != nil {
+			return err
+		}
+		for _, ancestor := range ancestors {
+			if ancestor.ID == taskID {
+				return fmt.Errorf("moving task %d under task %d would create a cycle", taskID, *parentID)
+			}
+		}
+	}
+
+	dbTask.ParentID = parentID
+	if err := hm.repository.UpdateTask(dbTask); err != nil {
+		return err
+	}
+
+	hm.events.Publish(databaseTaskEvent(EventTaskUpdated, dbTask))
+	return nil
+}
+
+// GetChildren returns the direct subtasks of a task
+func (hm *HierarchyManager) GetChildren(taskID int) ([]Task, error) {
+	dbTasks, err := hm.repository.GetChildTasks(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertFromDatabaseTasks(dbTasks), nil
+}
+
+// GetAncestors returns the ancestors of a task, nearest parent first
+func (hm *HierarchyManager) GetAncestors(taskID int) ([]Task, error) {
+	dbTasks, err := hm.repository.GetAncestorTasks(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertFromDatabaseTasks(dbTasks), nil
+}
+
+// GetSubtree returns a task with its unarchived subtasks loaded recursively.
+// Progress is filled in for every task that has subtasks.
+func (hm *HierarchyManager) GetSubtree(taskID int) (*Task, error) {
+	dbTask, err := hm.repository.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	descendants, err := hm.repository.GetDescendantTasks(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	childrenByParent := make(map[int][]database.DatabaseTask)
+	for _, descendant := range descendants {
+		if descendant.ParentID == nil || descendant.IsArchived {
+			continue
+		}
+		childrenByParent[*descendant.ParentID] = append(childrenByParent[*descendant.ParentID], descendant)
+	}
+
+	root := convertFromDatabaseTask(dbTask)
+	buildSubtree(&root, childrenByParent, map[int]bool{})
+	return &root, nil
+}
+
+// GetProgress returns the completion percentage (0-100) of a task computed from its subtasks
+func (hm *HierarchyManager) GetProgress(taskID int) (float64, error) {
+	root, err := hm.GetSubtree(taskID)
+	if err != nil {
+		return 0, err
+	}
+
+	return computeProgress(root), nil
+}
+
+// buildSubtree attaches the subtasks of t and computes progress bottom-up
+func buildSubtree(t *Task, childrenByParent map[int][]database.DatabaseTask, visited map[int]bool) {
+	if visited[t.ID] {
+		return // Guard against cycles in stored data
+	}
+	visited[t.ID] = true
+
+	for _, child := range childrenByParent[t.ID] {
+		subtask := convertFromDatabaseTask(&child)
+		buildSubtree(&subtask, childrenByParent, visited)
+		t.Subtasks = append(t.Subtasks, subtask)
+	}
+
+	if len(t.Subtasks) > 0 {
+		progress := computeProgress(t)
+		t.Progress = &progress
+	}
+}
+
+// computeProgress returns 100 for a completed leaf, 0 for an open leaf and the
+// average of the subtasks otherwise. Cancelled subtasks do not count.
+func computeProgress(t *Task) float64 {
+	var total float64
+	counted := 0
+	for i := range t.Subtasks {
+		subtask := &t.Subtasks[i]
+		if subtask.Status == Cancelled {
+			continue
+		}
+		if subtask.Progress != nil {
+			total += *subtask.Progress
+		} else {
+			total += computeProgress(subtask)
+		}
+		counted++
+	}
+
+	if counted == 0 {
+		if t.Status == Completed {
+			return 100
+		}
+		return 0
+	}
+
+	return total / float64(counted)
+}
+
+// SetTaskStatus updates the status of a task, applying the workflow and the complete rule
+func (hm *HierarchyManager) SetTaskStatus(taskID int, status Status) error {
+	dbTask, err := hm.repository.GetTask(taskID)
+	if err != nil {
+		return err
+	}
+
+	return hm.setStatus(dbTask, status)
+}
+
+// checkTransition checks a status change against the workflow and, for tasks
+// of a project, against the transitions of the project
+func (hm *HierarchyManager) checkTransition(dbTask *database.DatabaseTask, status Status) error {
+	task := convertFromDatabaseTask(dbTask)
+	if err := hm.workflow.CheckTransition(task, status); err != nil {
+		return err
+	}
+
+	if dbTask.ProjectID != nil {
+		dbProject, err := hm.repository.GetProject(*dbTask.ProjectID)
+		if err != nil {
+			return err
+		}
+		project := convertFromDatabaseProject(dbProject)
+		if err := project.Settings.CheckTransition(task, status); err != nil {
+			return err
+		}
+	}
+
+	return checkWIPLimits(hm.repository, hm.workflow, dbTask, status)
+}
+
+// checkStandaloneStatus checks a status change that is saved on its own,
+// without applying the complete rule to subtasks. Completing a task with
+// unfinished subtasks is refused unless the rule ignores subtasks.
+func (hm *HierarchyManager) checkStandaloneStatus(dbTask *database.DatabaseTask, status Status) error {
+	if err := hm.checkTransition(dbTask, status); err != nil {
+		return err
+	}
+
+	if status != Completed || Status(dbTask.Status) == Completed || hm.rules.OnComplete == CompleteIgnore {
+		return nil
+	}
+
+	descendants, err := hm.repository.GetDescendantTasks(dbTask.ID)
+	if err != nil {
+		return err
+	}
+
+	unfinished := 0
+	for _, descendant := range descendants {
+		descendantStatus := Status(descendant.Status)
+		if !descendant.IsArchived && descendantStatus != Completed && descendantStatus != Cancelled {
+			unfinished++
+		}
+	}
+
+	if unfinished > 0 {
+		return &TransitionError{
+			TaskID: dbTask.ID,
+			From:   Status(dbTask.Status),
+			To:     status,
+			Guard:  "subtasks completed",
+			Reason: fmt.Sprintf("task %d has %d unfinished subtasks", dbTask.ID, unfinished),
+		}
+	}
+	return nil
+}
+
+// setStatus saves a status change of an already loaded task, applying the
+// workflow and the complete rule. Every status change of a stored task ends up here.
+func (hm *HierarchyManager) setStatus(dbTask *database.DatabaseTask, status Status) error {
+	if err := hm.checkTransition(dbTask, status); err != nil {
+		return err
+	}
+
+	if status == Completed && Status(dbTask.Status) != Completed && hm.rules.OnComplete != CompleteIgnore {
+		descendants, err := hm.repository.GetDescendantTasks(dbTask.ID)
+		if err != nil {
+			return err
+		}
+
+		var unfinished []database.DatabaseTask
+		for _, descendant := range descendants {
+			descendantStatus := Status(descendant.Status)
+			if !descendant.IsArchived && descendantStatus != Completed && descendantStatus != Cancelled {
+				unfinished = append(unfinished, descendant)
+			}
+		}
+
+		if len(unfinished) > 0 && hm.rules.OnComplete == CompleteRequire {
+			return &TransitionError{
+				TaskID: dbTask.ID,
+				From:   Status(dbTask.Status),
+				To:     status,
+				Guard:  "subtasks completed",
+				Reason: fmt.Sprintf("task %d has %d unfinished subtasks", dbTask.ID, len(unfinished)),
+			}
+		}
+
+		// Check every subtask first so that a rejected one leaves the whole tree unchanged
+		for i := range unfinished {
+			if err := hm.checkTransition(&unfinished[i], Completed); err != nil {
+				return err
+			}
+		}
+		for i := range unfinished {
+			if err := hm.saveStatus(&unfinished[i], Completed); err != nil {
+				return err
+			}
+		}
+	}
+
+	return hm.saveStatus(dbTask, status)
+}
+
+// saveStatus stores a checked status change and publishes the resulting events
+func (hm *HierarchyManager) saveStatus(dbTask *database.DatabaseTask, status Status) error {
+	oldStatus := Status(dbTask.Status)
+	next, err := setDatabaseTaskStatus(hm.repository, dbTask, status)
+	if err != nil {
+		return err
+	}
+
+	if status != oldStatus {
+		hm.events.Publish(statusChangedEvent(convertFromDatabaseTask(dbTask), dbTask.UserID, oldStatus))
+	}
+	if next != nil {
+		hm.events.Publish(databaseTaskEvent(EventTaskCreated, next))
+	}
+	return nil
+}
+
+// DeleteTask deletes a task, applying the delete rule to its subtasks
+func (hm *HierarchyManager) DeleteTask(taskID int) error {
+	dbTask, err := hm.repository.GetTask(taskID)
+	if err != nil {
+		return err
+	}
+
+	descendants, err := hm.repository.GetDescendantTasks(taskID)
+	if err != nil {
+		return err
+	}
+
+	if len(descendants) > 0 {
+		switch hm.rules.OnDelete {
+		case DeleteRestrict:
+			return fmt.Errorf("task %d has subtasks and cannot be deleted", taskID)
+
+		case DeleteCascade:
+			for i := range descendants {
+				if err := hm.repository.DeleteTask(descendants[i].ID); err != nil {
+					return err
+				}
+				hm.events.Publish(databaseTaskEvent(EventTaskDeleted, &descendants[i]))
+			}
+
+		case DeleteOrphan, DeleteReparent:
+			var newParentID *int
+			if hm.rules.OnDelete == DeleteReparent {
+				newParentID = dbTask.ParentID
+			}
+
+			for i := range descendants {
+				child := &descendants[i]
+				if child.ParentID == nil || *child.ParentID != taskID {
+					continue
+				}
+				child.ParentID = newParentID
+				if err := hm.repository.UpdateTask(child); err != nil {
+					return err
+				}
+				hm.events.Publish(databaseTaskEvent(EventTaskUpdated, child))
+			}
+		}
+	}
+
+	if err := hm.repository.DeleteTask(taskID); err != nil {
+		return err
+	}
+
+	hm.events.Publish(databaseTaskEvent(EventTaskDeleted, dbTask))
+	return nil
+}
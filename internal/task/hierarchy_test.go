@@ -0,0 +1,252 @@
+package task
+
+import (
+	"testing"
+
+	"learn-go-capstone/internal/database"
+)
+
+// createHierarchyTask creates a database task under the given parent
+func createHierarchyTask(t *testing.T, repository database.Repository, title string, parentID *int) *database.DatabaseTask {
+	dbTask := &database.DatabaseTask{
+		Title:    title,
+		Priority: int(Medium),
+		Status:   int(Pending),
+		ParentID: parentID,
+	}
+	if err := repository.CreateTask(dbTask); err != nil {
+		t.Fatalf("Failed to create task %q: %v", title, err)
+	}
+	return dbTask
+}
+
+func TestHierarchyManagerParentsAndAncestors(t *testing.T) {
+	repository := setupTestRepository(t)
+	hm := NewHierarchyManager(repository, DefaultCascadeRules())
+
+	root := createHierarchyTask(t, repository, "Release", nil)
+	child := createHierarchyTask(t, repository, "Write changelog", &root.ID)
+	grandchild := createHierarchyTask(t, repository, "Collect merged PRs", &child.ID)
+	other := createHierarchyTask(t, repository, "Unrelated", nil)
+
+	children, err := hm.GetChildren(root.ID)
+	if err != nil {
+		t.Fatalf("Failed to get children: %v", err)
+	}
+	if len(children) != 1 || children[0].ID != child.ID {
+		t.Errorf("Expected only task %d as child, got %v", child.ID, children)
+	}
+	if children[0].ParentID == nil || *children[0].ParentID != root.ID {
+		t.Errorf("Expected child to point at parent %d, got %v", root.ID, children[0].ParentID)
+	}
+
+	ancestors, err := hm.GetAncestors(grandchild.ID)
+	if err != nil {
+		t.Fatalf("Failed to get ancestors: %v", err)
+	}
+	if len(ancestors) != 2 || ancestors[0].ID != child.ID || ancestors[1].ID != root.ID {
+		t.Errorf("Expected ancestors [%d %d], got %v", child.ID, root.ID, ancestors)
+	}
+
+	// Moving a task below its own descendant must be rejected
+	if err := hm.SetParent(root.ID, &grandchild.ID); err == nil {
+		t.Error("Expected error when creating a cycle")
+	}
+	if err := hm.SetParent(root.ID, &root.ID); err == nil {
+		t.Error("Expected error when making a task its own parent")
+	}
+
+	if err := hm.SetParent(grandchild.ID, &other.ID); err != nil {
+		t.Fatalf("Failed to move task: %v", err)
+	}
+	ancestors, _ = hm.GetAncestors(grandchild.ID)
+	if len(ancestors) != 1 || ancestors[0].ID != other.ID {
+		t.Errorf("Expected task %d as the only ancestor, got %v", other.ID, ancestors)
+	}
+
+	if err := hm.SetParent(grandchild.ID, nil); err != nil {
+		t.Fatalf("Failed to detach task: %v", err)
+	}
+	ancestors, _ = hm.GetAncestors(grandchild.ID)
+	if len(ancestors) != 0 {
+		t.Errorf("Expected a top-level task, got ancestors %v", ancestors)
+	}
+}
+
+func TestHierarchyManagerSubtreeProgress(t *testing.T) {
+	repository := setupTestRepository(t)
+	hm := NewHierarchyManager(repository, DefaultCascadeRules())
+
+	root := createHierarchyTask(t, repository, "Move house", nil)
+	packing := createHierarchyTask(t, repository, "Pack", &root.ID)
+	createHierarchyTask(t, repository, "Hire van", &root.ID)
+	kitchen := createHierarchyTask(t, repository, "Kitchen", &packing.ID)
+	createHierarchyTask(t, repository, "Books", &packing.ID)
+	cancelled := createHierarchyTask(t, repository, "Garage sale", &root.ID)
+
+	if err := hm.SetTaskStatus(kitchen.ID, Completed); err != nil {
+		t.Fatalf("Failed to complete task: %v", err)
+	}
+	if err := hm.SetTaskStatus(cancelled.ID, Cancelled); err != nil {
+		t.Fatalf("Failed to cancel task: %v", err)
+	}
+
+	subtree, err := hm.GetSubtree(root.ID)
+	if err != nil {
+		t.Fatalf("Failed to get subtree: %v", err)
+	}
+	if len(subtree.Subtasks) != 3 {
+		t.Fatalf("Expected 3 direct subtasks, got %d", len(subtree.Subtasks))
+	}
+	if len(subtree.Subtasks[0].Subtasks) != 2 {
+		t.Errorf("Expected 2 nested subtasks, got %d", len(subtree.Subtasks[0].Subtasks))
+	}
+
+	// Pack is half done, Hire van is open and Garage sale is cancelled
+	if subtree.Subtasks[0].Progress == nil || *subtree.Subtasks[0].Progress != 50 {
+		t.Errorf("Expected nested progress 50, got %v", subtree.Subtasks[0].Progress)
+	}
+	if subtree.Progress == nil || *subtree.Progress != 25 {
+		t.Errorf("Expected root progress 25, got %v", subtree.Progress)
+	}
+
+	progress, err := hm.GetProgress(kitchen.ID)
+	if err != nil {
+		t.Fatalf("Failed to get progress: %v", err)
+	}
+	if progress != 100 {
+		t.Errorf("Expected a completed leaf to report 100, got %v", progress)
+	}
+}
+
+func TestHierarchyManagerCompleteRules(t *testing.T) {
+	repository := setupTestRepository(t)
+
+	root := createHierarchyTask(t, repository, "Parent", nil)
+	child := createHierarchyTask(t, repository, "Child", &root.ID)
+	grandchild := createHierarchyTask(t, repository, "Grandchild", &child.ID)
+
+	require := NewHierarchyManager(repository, CascadeRules{OnDelete: DeleteCascade, OnComplete: CompleteRequire})
+	if err := require.SetTaskStatus(root.ID, Completed); err == nil {
+		t.Error("Expected error completing a task with unfinished subtasks")
+	}
+
+	cascade := NewHierarchyManager(repository, CascadeRules{OnDelete: DeleteCascade, OnComplete: CompleteCascade})
+	if err := cascade.SetTaskStatus(root.ID, Completed); err != nil {
+		t.Fatalf("Failed to complete task: %v", err)
+	}
+
+	for _, id := range []int{child.ID, grandchild.ID} {
+		stored, err := repository.GetTask(id)
+		if err != nil {
+			t.Fatalf("Failed to get task: %v", err)
+		}
+		if Status(stored.Status) != Completed {
+			t.Errorf("Expected subtask %d to be completed, got %s", id, Status(stored.Status))
+		}
+	}
+
+	// Once everything below is finished the require rule allows completion
+	if err := require.SetTaskStatus(root.ID, InProgress); err != nil {
+		t.Fatalf("Failed to reopen task: %v", err)
+	}
+	if err := require.SetTaskStatus(root.ID, Completed); err != nil {
+		t.Errorf("Expected completion to succeed, got %v", err)
+	}
+}
+
+func TestHierarchyManagerDeleteRules(t *testing.T) {
+	tests := []struct {
+		rule            DeleteRule
+		wantErr         bool
+		wantChildParent func(root, middle *database.DatabaseTask) *int
+		wantDeleted     bool
+	}{
+		{DeleteCascade, false, nil, true},
+		{DeleteOrphan, false, func(root, middle *database.DatabaseTask) *int { return nil }, false},
+		{DeleteReparent, false, func(root, middle *database.DatabaseTask) *int { return &root.ID }, false},
+		{DeleteRestrict, true, func(root, middle *database.DatabaseTask) *int { return &middle.ID }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.rule), func(t *testing.T) {
+			repository := setupTestRepository(t)
+			hm := NewHierarchyManager(repository, CascadeRules{OnDelete: tt.rule, OnComplete: CompleteIgnore})
+
+			root := createHierarchyTask(t, repository, "Root", nil)
+			middle := createHierarchyTask(t, repository, "Middle", &root.ID)
+			leaf := createHierarchyTask(t, repository, "Leaf", &middle.ID)
+			nested := createHierarchyTask(t, repository, "Nested", &leaf.ID)
+
+			err := hm.DeleteTask(middle.ID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+
+			stored, err := repository.GetTask(leaf.ID)
+			if tt.wantDeleted {
+				if err == nil {
+					t.Error("Expected subtask to be deleted")
+				}
+				if _, err := repository.GetTask(nested.ID); err == nil {
+					t.Error("Expected nested subtask to be deleted")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected subtask to survive: %v", err)
+			}
+
+			want := tt.wantChildParent(root, middle)
+			if (want == nil) != (stored.ParentID == nil) || (want != nil && *want != *stored.ParentID) {
+				t.Errorf("Expected parent %v, got %v", want, stored.ParentID)
+			}
+
+			// Deeper levels keep their own parent
+			nestedTask, err := repository.GetTask(nested.ID)
+			if err != nil || nestedTask.ParentID == nil || *nestedTask.ParentID != leaf.ID {
+				t.Errorf("Expected nested subtask to stay under %d, got %v (%v)", leaf.ID, nestedTask, err)
+			}
+		})
+	}
+}
+
+func TestUserManagerSetUserTaskParent(t *testing.T) {
+	repository := setupTestRepository(t)
+	userManager := NewUserManager(repository)
+
+	owner, _ := userManager.RegisterUser("owner", "owner@example.com", "password123")
+	other, _ := userManager.RegisterUser("other", "other@example.com", "password123")
+
+	parent, _ := userManager.CreateUserTask(owner.ID, "Parent", "", Low, nil)
+	child, _ := userManager.CreateUserTask(owner.ID, "Child", "", Low, nil)
+	foreign, _ := userManager.CreateUserTask(other.ID, "Foreign", "", Low, nil)
+
+	updated, err := userManager.SetUserTaskParent(owner.ID, child.ID, &parent.ID)
+	if err != nil {
+		t.Fatalf("Failed to set parent: %v", err)
+	}
+	if updated.ParentID == nil || *updated.ParentID != parent.ID {
+		t.Errorf("Expected parent %d, got %v", parent.ID, updated.ParentID)
+	}
+
+	if _, err := userManager.SetUserTaskParent(owner.ID, child.ID, &foreign.ID); err == nil {
+		t.Error("Expected error when using another user's task as parent")
+	}
+
+	subtree, err := userManager.GetUserTaskSubtree(owner.ID, parent.ID)
+	if err != nil {
+		t.Fatalf("Failed to get subtree: %v", err)
+	}
+	if len(subtree.Subtasks) != 1 || subtree.Subtasks[0].ID != child.ID {
+		t.Errorf("Expected subtree with child %d, got %v", child.ID, subtree.Subtasks)
+	}
+
+	// With the default rules deleting the parent removes the subtask as well
+	if err := userManager.DeleteUserTask(owner.ID, parent.ID); err != nil {
+		t.Fatalf("Failed to delete parent: %v", err)
+	}
+	if _, err := repository.GetTask(child.ID); err == nil {
+		t.Error("Expected subtask to be deleted with its parent")
+	}
+}
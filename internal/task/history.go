@@ -0,0 +1,78 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"learn-go-capstone/internal/database"
+)
+
+// ErrHistoryUnavailable is returned when task history is requested from
+// memory storage, which does not record it
+var ErrHistoryUnavailable = errors.New("task history is only recorded in database storage")
+
+// HistoryEntry is a recorded change to one field of a task
+type HistoryEntry struct {
+	ID     int    `json:"id"`
+	TaskID int    `json:"task_id"`
+	UserID *int   `json:"user_id,omitempty"` // nil for changes made by the system
+	Field  string `json:"field"`
+	// OldValue and NewValue are nil when the field was unset
+	OldValue  *string   `json:"old_value,omitempty"`
+	NewValue  *string   `json:"new_value,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+func convertFromHistoryEntries(entries []database.TaskHistoryEntry) []HistoryEntry {
+	history := make([]HistoryEntry, len(entries))
+	for i, entry := range entries {
+		history[i] = HistoryEntry{
+			ID:        entry.ID,
+			TaskID:    entry.TaskID,
+			UserID:    entry.UserID,
+			Field:     entry.Field,
+			OldValue:  entry.OldValue,
+			NewValue:  entry.NewValue,
+			ChangedAt: entry.ChangedAt,
+		}
+	}
+	return history
+}
+
+// GetTaskHistoryContext always fails, because memory storage keeps no history
+func (tm *TaskManager) GetTaskHistoryContext(ctx context.Context, id int) ([]HistoryEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, ErrHistoryUnavailable
+}
+
+// GetTaskHistory returns the recorded changes of a task, oldest first
+func (htm *HybridTaskManager) GetTaskHistory(id int) ([]HistoryEntry, error) {
+	return htm.GetTaskHistoryContext(context.Background(), id)
+}
+
+// GetTaskHistoryContext returns the recorded changes of a task, oldest first.
+// History is recorded by the database, so it is available in database and
+// hybrid storage.
+func (htm *HybridTaskManager) GetTaskHistoryContext(ctx context.Context, id int) ([]HistoryEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	htm.mu.RLock()
+	defer htm.mu.RUnlock()
+
+	if htm.storageType == MemoryStorage {
+		return htm.memoryManager.GetTaskHistoryContext(ctx, id)
+	}
+
+	entries, err := htm.repository.WithContext(ctx).GetTaskHistory(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertFromHistoryEntries(entries), nil
+}
@@ -0,0 +1,81 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"learn-go-capstone/internal/database"
+)
+
+func TestTaskHistoryRecordsChanges(t *testing.T) {
+	storageTypes := map[string]StorageType{"database": DatabaseStorage, "hybrid": HybridStorage}
+	for name, storageType := range storageTypes {
+		t.Run(name, func(t *testing.T) {
+			repository := setupTestRepository(t)
+			user := &database.User{Username: "editor", Email: "editor@example.com", Password: "secret", IsActive: true}
+			if err := repository.CreateUser(user); err != nil {
+				t.Fatalf("Failed to create user: %v", err)
+			}
+
+			htm := NewHybridTaskManager(repository, storageType)
+			ctx := database.WithActor(context.Background(), user.ID)
+
+			created, err := htm.AddTaskContext(context.Background(), "Report", "", Low, nil)
+			if err != nil {
+				t.Fatalf("Failed to add task: %v", err)
+			}
+			dueDate := time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)
+			high := High
+			if _, err := htm.UpdateTaskContext(ctx, created.ID, TaskPatch{Priority: &high, DueDate: &dueDate}); err != nil {
+				t.Fatalf("Failed to update task: %v", err)
+			}
+			if err := htm.DeleteTaskContext(ctx, created.ID); err != nil {
+				t.Fatalf("Failed to delete task: %v", err)
+			}
+
+			history, err := htm.GetTaskHistoryContext(context.Background(), created.ID)
+			if err != nil {
+				t.Fatalf("Failed to get history: %v", err)
+			}
+
+			fields := make([]string, len(history))
+			for i, entry := range history {
+				fields[i] = entry.Field
+			}
+			want := []string{"created", "priority", "due_date", "deleted_at"}
+			if len(fields) != len(want) {
+				t.Fatalf("Expected changes to %v, got %v", want, fields)
+			}
+			for i := range want {
+				if fields[i] != want[i] {
+					t.Fatalf("Expected changes to %v, got %v", want, fields)
+				}
+			}
+
+			if history[0].UserID != nil {
+				t.Errorf("Expected the creation without an actor to be a system change, got user %d", *history[0].UserID)
+			}
+			priority := history[1]
+			if priority.UserID == nil || *priority.UserID != user.ID {
+				t.Errorf("Expected the update to be attributed to user %d, got %v", user.ID, priority.UserID)
+			}
+			if priority.OldValue == nil || *priority.OldValue != "1" || priority.NewValue == nil || *priority.NewValue != "3" {
+				t.Errorf("Unexpected priority change %v -> %v", priority.OldValue, priority.NewValue)
+			}
+			if due := history[2]; due.OldValue != nil || due.NewValue == nil || *due.NewValue != "2030-01-02T00:00:00Z" {
+				t.Errorf("Unexpected due date change %v -> %v", due.OldValue, due.NewValue)
+			}
+		})
+	}
+}
+
+func TestTaskHistoryUnavailableInMemory(t *testing.T) {
+	htm := NewHybridTaskManager(nil, MemoryStorage)
+	created := htm.AddTask("Note", "", Low, nil)
+
+	if _, err := htm.GetTaskHistory(created.ID); !errors.Is(err, ErrHistoryUnavailable) {
+		t.Errorf("Expected ErrHistoryUnavailable, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package task
 
 import (
+	"context"
 	"sync"
 	"time"
 	
@@ -27,29 +28,113 @@ type HybridTaskManager struct {
 	// Storage configuration
 	storageType StorageType
 	
+	// Cascade rules for subtasks of deleted or completed tasks
+	hierarchy *HierarchyManager
+	
+	// Task lifecycle events
+	events *EventBus
+	
+	// Undo journal of memory storage; database storage keeps its journal in the database
+	memoryJournal Journal
+	
 	// Synchronization
 	mu sync.RWMutex
 }
 
 // NewHybridTaskManager creates a new hybrid task manager
 func NewHybridTaskManager(repository database.Repository, storageType StorageType) *HybridTaskManager {
-	return &HybridTaskManager{
+	htm := &HybridTaskManager{
 		memoryManager: NewTaskManager(),
 		repository:    repository,
 		storageType:   storageType,
+		hierarchy:     NewHierarchyManager(repository, DefaultCascadeRules()),
+		memoryJournal: NewMemoryJournal(DefaultJournalLimit),
+	}
+	htm.attachMemoryEvents()
+	return htm
+}
+
+// SetWorkflow replaces the workflow that status changes must follow in every storage type
+func (htm *HybridTaskManager) SetWorkflow(workflow *Workflow) {
+	htm.mu.Lock()
+	defer htm.mu.Unlock()
+	htm.memoryManager.SetWorkflow(workflow)
+	htm.hierarchy.SetWorkflow(workflow)
+}
+
+// GetWorkflow returns the workflow that status changes must follow
+func (htm *HybridTaskManager) GetWorkflow() *Workflow {
+	htm.mu.RLock()
+	defer htm.mu.RUnlock()
+	if htm.storageType == MemoryStorage {
+		return htm.memoryManager.GetWorkflow()
+	}
+	return htm.hierarchy.GetWorkflow()
+}
+
+// SetHierarchyManager replaces the hierarchy manager used to apply cascade rules
+func (htm *HybridTaskManager) SetHierarchyManager(hierarchy *HierarchyManager) {
+	htm.mu.Lock()
+	defer htm.mu.Unlock()
+	htm.hierarchy = hierarchy
+}
+
+// SetEventBus sets the bus that task mutations are published to.
+// Call it after SetHierarchyManager, whose status changes and deletions it also covers.
+func (htm *HybridTaskManager) SetEventBus(bus *EventBus) {
+	htm.mu.Lock()
+	defer htm.mu.Unlock()
+	htm.events = bus
+	htm.hierarchy.SetEventBus(bus)
+	htm.attachMemoryEvents()
+}
+
+// attachMemoryEvents lets the memory manager publish its own events and
+// record its own commands only when it is the sole storage, so that hybrid
+// storage does not publish or record twice
+func (htm *HybridTaskManager) attachMemoryEvents() {
+	if htm.storageType == MemoryStorage {
+		htm.memoryManager.SetEventBus(htm.events)
+		htm.memoryManager.SetJournal(htm.memoryJournal)
+	} else {
+		htm.memoryManager.SetEventBus(nil)
+		htm.memoryManager.SetJournal(nil)
 	}
 }
 
-// AddTask adds a new task using the configured storage
+// AddTask adds a new task using the configured storage.
+// Database failures fall back to memory storage; use AddTaskContext to see them.
 func (htm *HybridTaskManager) AddTask(title, description string, priority Priority, dueDate *time.Time) *Task {
+	task, err := htm.addTask(context.Background(), title, description, priority, dueDate)
+	if err != nil {
+		task = htm.memoryManager.AddTask(title, description, priority, dueDate)
+		if htm.GetStorageType() != MemoryStorage {
+			htm.events.Publish(taskEvent(EventTaskCreated, *task, nil))
+		}
+	}
+	return task
+}
+
+// AddTaskContext validates and adds a new task using the configured storage
+func (htm *HybridTaskManager) AddTaskContext(ctx context.Context, title, description string, priority Priority, dueDate *time.Time) (*Task, error) {
+	if err := validateNewTask(title, priority); err != nil {
+		return nil, err
+	}
+
+	return htm.addTask(ctx, title, description, priority, dueDate)
+}
+
+// addTask stores a new task without validating it
+func (htm *HybridTaskManager) addTask(ctx context.Context, title, description string, priority Priority, dueDate *time.Time) (*Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	htm.mu.Lock()
 	defer htm.mu.Unlock()
 	
 	switch htm.storageType {
-	case MemoryStorage:
-		return htm.memoryManager.AddTask(title, description, priority, dueDate)
-		
-	case DatabaseStorage:
+	case DatabaseStorage, HybridStorage:
 		dbTask := &database.DatabaseTask{
 			Title:       title,
 			Description: description,
@@ -58,67 +143,54 @@ func (htm *HybridTaskManager) AddTask(title, description string, priority Priori
 			DueDate:     dueDate,
 		}
 		
-		if err := htm.repository.CreateTask(dbTask); err != nil {
-			// Fallback to memory storage if database fails
-			return htm.memoryManager.AddTask(title, description, priority, dueDate)
+		err := htm.journal(ctx, CommandCreate, 0, func(repository database.Repository, events *EventBus) error {
+			if err := repository.CreateTask(dbTask); err != nil {
+				return err
+			}
+			events.Publish(databaseTaskEvent(EventTaskCreated, dbTask))
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
 		
 		// Convert back to original Task struct
 		task := convertFromDatabaseTask(dbTask)
-		return &task
-		
-	case HybridStorage:
-		// Try database first, fallback to memory
-		dbTask := &database.DatabaseTask{
-			Title:       title,
-			Description: description,
-			Priority:    int(priority),
-			Status:      int(Pending),
-			DueDate:     dueDate,
-		}
-		
-		if err := htm.repository.CreateTask(dbTask); err != nil {
-			// Fallback to memory storage
-			return htm.memoryManager.AddTask(title, description, priority, dueDate)
+		if htm.storageType == HybridStorage {
+			// Also add to memory for fast access, under the stored ID
+			htm.memoryManager.putTask(task)
 		}
-		
-		// Also add to memory for fast access
-		htm.memoryManager.AddTask(title, description, priority, dueDate)
-		
-		// Convert back to original Task struct
-		task := convertFromDatabaseTask(dbTask)
-		return &task
+		return &task, nil
 		
 	default:
-		return htm.memoryManager.AddTask(title, description, priority, dueDate)
+		return htm.memoryManager.AddTask(title, description, priority, dueDate), nil
 	}
 }
 
 // GetTask retrieves a task by ID
 func (htm *HybridTaskManager) GetTask(id int) (*Task, error) {
+	return htm.GetTaskContext(context.Background(), id)
+}
+
+// GetTaskContext retrieves a task by ID
+func (htm *HybridTaskManager) GetTaskContext(ctx context.Context, id int) (*Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	htm.mu.RLock()
 	defer htm.mu.RUnlock()
 	
 	switch htm.storageType {
-	case MemoryStorage:
-		return htm.memoryManager.GetTask(id)
-		
-	case DatabaseStorage:
-		dbTask, err := htm.repository.GetTask(id)
-		if err != nil {
-			return nil, err
-		}
-		task := convertFromDatabaseTask(dbTask)
-		return &task, nil
-		
-	case HybridStorage:
-		// Try memory first for speed
-		if task, err := htm.memoryManager.GetTask(id); err == nil {
-			return task, nil
+	case DatabaseStorage, HybridStorage:
+		if htm.storageType == HybridStorage {
+			// Try memory first for speed
+			if task, err := htm.memoryManager.GetTask(id); err == nil {
+				return task, nil
+			}
 		}
 		
-		// Fallback to database
-		dbTask, err := htm.repository.GetTask(id)
+		dbTask, err := htm.repository.WithContext(ctx).GetTask(id)
 		if err != nil {
 			return nil, err
 		}
@@ -132,205 +204,250 @@ func (htm *HybridTaskManager) GetTask(id int) (*Task, error) {
 
 // UpdateTaskStatus updates the status of a task
 func (htm *HybridTaskManager) UpdateTaskStatus(id int, status Status) error {
+	return htm.UpdateTaskStatusContext(context.Background(), id, status)
+}
+
+// UpdateTaskStatusContext updates the status of a task following the workflow
+func (htm *HybridTaskManager) UpdateTaskStatusContext(ctx context.Context, id int, status Status) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	htm.mu.Lock()
 	defer htm.mu.Unlock()
 	
 	switch htm.storageType {
-	case MemoryStorage:
-		return htm.memoryManager.UpdateTaskStatus(id, status)
+	case DatabaseStorage, HybridStorage:
+		// The database decides whether the workflow allows the change; the
+		// memory copy of hybrid storage follows once it has been stored
+		return htm.setDatabaseStatus(ctx, id, status)
 		
-	case DatabaseStorage:
-		dbTask, err := htm.repository.GetTask(id)
+	default:
+		return htm.memoryManager.UpdateTaskStatus(id, status)
+	}
+}
+
+// setDatabaseStatus loads a stored task and changes its status through the hierarchy manager
+func (htm *HybridTaskManager) setDatabaseStatus(ctx context.Context, id int, status Status) error {
+	return htm.journal(ctx, CommandStatus, id, func(repository database.Repository, events *EventBus) error {
+		dbTask, err := repository.GetTask(id)
 		if err != nil {
 			return err
 		}
-		dbTask.Status = int(status)
-		return htm.repository.UpdateTask(dbTask)
-		
-	case HybridStorage:
-		// Update both memory and database
-		if err := htm.memoryManager.UpdateTaskStatus(id, status); err != nil {
-			// If memory update fails, try database
-			dbTask, err := htm.repository.GetTask(id)
-			if err != nil {
-				return err
-			}
-			dbTask.Status = int(status)
-			return htm.repository.UpdateTask(dbTask)
+		return htm.hierarchy.withRepository(repository, events).setStatus(dbTask, status)
+	})
+}
+
+// UpdateTask applies a partial update to a task and returns the updated task
+func (htm *HybridTaskManager) UpdateTask(id int, patch TaskPatch) (*Task, error) {
+	return htm.UpdateTaskContext(context.Background(), id, patch)
+}
+
+// UpdateTaskContext applies a partial update to a task and returns the updated task
+func (htm *HybridTaskManager) UpdateTaskContext(ctx context.Context, id int, patch TaskPatch) (*Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := patch.Validate(); err != nil {
+		return nil, err
+	}
+
+	htm.mu.Lock()
+	defer htm.mu.Unlock()
+
+	switch htm.storageType {
+	case DatabaseStorage, HybridStorage:
+		// The memory copy of hybrid storage follows once the change is stored
+		return htm.updateDatabaseTask(ctx, id, patch)
+
+	default:
+		return htm.memoryManager.UpdateTask(id, patch)
+	}
+}
+
+// updateDatabaseTask loads a task from the repository, patches and saves it.
+// An empty patch saves nothing.
+func (htm *HybridTaskManager) updateDatabaseTask(ctx context.Context, id int, patch TaskPatch) (*Task, error) {
+	if patch.IsEmpty() {
+		dbTask, err := htm.repository.WithContext(ctx).GetTask(id)
+		if err != nil {
+			return nil, err
 		}
-		
-		// Also update database
-		dbTask, err := htm.repository.GetTask(id)
-		if err == nil {
-			dbTask.Status = int(status)
-			htm.repository.UpdateTask(dbTask)
+		task := convertFromDatabaseTask(dbTask)
+		return &task, nil
+	}
+
+	var dbTask *database.DatabaseTask
+	err := htm.journal(ctx, CommandUpdate, id, func(repository database.Repository, events *EventBus) error {
+		var err error
+		dbTask, err = repository.GetTask(id)
+		if err != nil {
+			return err
 		}
-		
+
+		patch.applyToDatabaseTask(dbTask)
+		if err := repository.UpdateTask(dbTask); err != nil {
+			return err
+		}
+
+		events.Publish(databaseTaskEvent(EventTaskUpdated, dbTask))
 		return nil
-		
-	default:
-		return htm.memoryManager.UpdateTaskStatus(id, status)
+	})
+	if err != nil {
+		return nil, err
 	}
+
+	task := convertFromDatabaseTask(dbTask)
+	return &task, nil
 }
 
-// DeleteTask removes a task by ID
+// DeleteTask removes a task by ID.
+// Hybrid storage falls back to the memory copy when the database fails; use DeleteTaskContext to see the failure.
 func (htm *HybridTaskManager) DeleteTask(id int) error {
+	err := htm.DeleteTaskContext(context.Background(), id)
+	if err != nil && htm.GetStorageType() == HybridStorage {
+		if memoryErr := htm.memoryManager.DeleteTask(id); memoryErr == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// DeleteTaskContext removes a task by ID, applying the subtask cascade rules
+func (htm *HybridTaskManager) DeleteTaskContext(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	htm.mu.Lock()
 	defer htm.mu.Unlock()
 	
 	switch htm.storageType {
-	case MemoryStorage:
-		return htm.memoryManager.DeleteTask(id)
-		
-	case DatabaseStorage:
-		return htm.repository.DeleteTask(id)
-		
-	case HybridStorage:
-		// Delete from both memory and database
-		memoryErr := htm.memoryManager.DeleteTask(id)
-		dbErr := htm.repository.DeleteTask(id)
-		
-		// Return error if both fail
-		if memoryErr != nil && dbErr != nil {
-			return dbErr
-		}
-		
-		return nil
+	case DatabaseStorage, HybridStorage:
+		// The memory copy of hybrid storage follows once the change is stored
+		return htm.deleteDatabaseTask(ctx, id)
 		
 	default:
 		return htm.memoryManager.DeleteTask(id)
 	}
 }
 
-// GetAllTasks returns all tasks
-func (htm *HybridTaskManager) GetAllTasks() []Task {
+// deleteDatabaseTask moves a stored task to the trash through the hierarchy manager
+func (htm *HybridTaskManager) deleteDatabaseTask(ctx context.Context, id int) error {
+	return htm.journal(ctx, CommandDelete, id, func(repository database.Repository, events *EventBus) error {
+		return htm.hierarchy.withRepository(repository, events).DeleteTask(id)
+	})
+}
+
+// listTasks runs a database query for the configured storage type, or the
+// memory query for memory storage
+func (htm *HybridTaskManager) listTasks(ctx context.Context, fromDatabase func(database.Repository) ([]database.DatabaseTask, error), fromMemory func() []Task) ([]Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	htm.mu.RLock()
 	defer htm.mu.RUnlock()
 	
 	switch htm.storageType {
-	case MemoryStorage:
-		return htm.memoryManager.GetAllTasks()
-		
-	case DatabaseStorage:
-		dbTasks, err := htm.repository.GetAllTasks()
+	case DatabaseStorage, HybridStorage:
+		dbTasks, err := fromDatabase(htm.repository.WithContext(ctx))
 		if err != nil {
-			// Fallback to memory
-			return htm.memoryManager.GetAllTasks()
-		}
-		return convertFromDatabaseTasks(dbTasks)
-		
-	case HybridStorage:
-		// Try database first
-		dbTasks, err := htm.repository.GetAllTasks()
-		if err != nil {
-			// Fallback to memory
-			return htm.memoryManager.GetAllTasks()
+			return nil, err
 		}
-		return convertFromDatabaseTasks(dbTasks)
+		return convertFromDatabaseTasks(dbTasks), nil
 		
 	default:
+		return fromMemory(), nil
+	}
+}
+
+// GetAllTasks returns all tasks, falling back to memory if the database fails
+func (htm *HybridTaskManager) GetAllTasks() []Task {
+	tasks, err := htm.GetAllTasksContext(context.Background())
+	if err != nil {
 		return htm.memoryManager.GetAllTasks()
 	}
+	return tasks
 }
 
-// GetTasksByStatus returns tasks filtered by status
+// GetAllTasksContext returns all tasks that are not snoozed
+func (htm *HybridTaskManager) GetAllTasksContext(ctx context.Context) ([]Task, error) {
+	return htm.listTasks(ctx, awake(func(repository database.Repository) ([]database.DatabaseTask, error) {
+		return repository.GetAllTasks()
+	}), htm.memoryManager.GetAllTasks)
+}
+
+// GetTasksByStatus returns tasks filtered by status, falling back to memory if the database fails
 func (htm *HybridTaskManager) GetTasksByStatus(status Status) []Task {
-	htm.mu.RLock()
-	defer htm.mu.RUnlock()
-	
-	switch htm.storageType {
-	case MemoryStorage:
-		return htm.memoryManager.GetTasksByStatus(status)
-		
-	case DatabaseStorage:
-		dbTasks, err := htm.repository.GetTasksByStatus(int(status))
-		if err != nil {
-			// Fallback to memory
-			return htm.memoryManager.GetTasksByStatus(status)
-		}
-		return convertFromDatabaseTasks(dbTasks)
-		
-	case HybridStorage:
-		// Try database first
-		dbTasks, err := htm.repository.GetTasksByStatus(int(status))
-		if err != nil {
-			// Fallback to memory
-			return htm.memoryManager.GetTasksByStatus(status)
-		}
-		return convertFromDatabaseTasks(dbTasks)
-		
-	default:
+	tasks, err := htm.GetTasksByStatusContext(context.Background(), status)
+	if err != nil {
 		return htm.memoryManager.GetTasksByStatus(status)
 	}
+	return tasks
 }
 
-// GetTasksByPriority returns tasks filtered by priority
+// GetTasksByStatusContext returns tasks filtered by status
+func (htm *HybridTaskManager) GetTasksByStatusContext(ctx context.Context, status Status) ([]Task, error) {
+	return htm.listTasks(ctx, awake(func(repository database.Repository) ([]database.DatabaseTask, error) {
+		return repository.GetTasksByStatus(int(status))
+	}), func() []Task {
+		return htm.memoryManager.GetTasksByStatus(status)
+	})
+}
+
+// GetTasksByPriority returns tasks filtered by priority, falling back to memory if the database fails
 func (htm *HybridTaskManager) GetTasksByPriority(priority Priority) []Task {
-	htm.mu.RLock()
-	defer htm.mu.RUnlock()
-	
-	switch htm.storageType {
-	case MemoryStorage:
-		return htm.memoryManager.GetTasksByPriority(priority)
-		
-	case DatabaseStorage:
-		dbTasks, err := htm.repository.GetTasksByPriority(int(priority))
-		if err != nil {
-			// Fallback to memory
-			return htm.memoryManager.GetTasksByPriority(priority)
-		}
-		return convertFromDatabaseTasks(dbTasks)
-		
-	case HybridStorage:
-		// Try database first
-		dbTasks, err := htm.repository.GetTasksByPriority(int(priority))
-		if err != nil {
-			// Fallback to memory
-			return htm.memoryManager.GetTasksByPriority(priority)
-		}
-		return convertFromDatabaseTasks(dbTasks)
-		
-	default:
+	tasks, err := htm.GetTasksByPriorityContext(context.Background(), priority)
+	if err != nil {
 		return htm.memoryManager.GetTasksByPriority(priority)
 	}
+	return tasks
+}
+
+// GetTasksByPriorityContext returns tasks filtered by priority
+func (htm *HybridTaskManager) GetTasksByPriorityContext(ctx context.Context, priority Priority) ([]Task, error) {
+	return htm.listTasks(ctx, awake(func(repository database.Repository) ([]database.DatabaseTask, error) {
+		return repository.GetTasksByPriority(int(priority))
+	}), func() []Task {
+		return htm.memoryManager.GetTasksByPriority(priority)
+	})
 }
 
 // SortTasksByPriority sorts tasks by priority (highest first)
 func (htm *HybridTaskManager) SortTasksByPriority() []Task {
-	// This method doesn't need database-specific implementation
-	// as it's just sorting the results from GetAllTasks
-	return htm.memoryManager.SortTasksByPriority()
+	tasks, err := htm.SortTasksByPriorityContext(context.Background())
+	if err != nil {
+		return htm.memoryManager.SortTasksByPriority()
+	}
+	return tasks
 }
 
-// GetOverdueTasks returns tasks that are past their due date
+// SortTasksByPriorityContext returns all tasks sorted by priority (highest first)
+func (htm *HybridTaskManager) SortTasksByPriorityContext(ctx context.Context) ([]Task, error) {
+	tasks, err := htm.GetAllTasksContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sortByPriority(tasks)
+	return tasks, nil
+}
+
+// GetOverdueTasks returns tasks that are past their due date, falling back to memory if the database fails
 func (htm *HybridTaskManager) GetOverdueTasks() []Task {
-	htm.mu.RLock()
-	defer htm.mu.RUnlock()
-	
-	switch htm.storageType {
-	case MemoryStorage:
-		return htm.memoryManager.GetOverdueTasks()
-		
-	case DatabaseStorage:
-		dbTasks, err := htm.repository.GetOverdueTasks()
-		if err != nil {
-			// Fallback to memory
-			return htm.memoryManager.GetOverdueTasks()
-		}
-		return convertFromDatabaseTasks(dbTasks)
-		
-	case HybridStorage:
-		// Try database first
-		dbTasks, err := htm.repository.GetOverdueTasks()
-		if err != nil {
-			// Fallback to memory
-			return htm.memoryManager.GetOverdueTasks()
-		}
-		return convertFromDatabaseTasks(dbTasks)
-		
-	default:
+	tasks, err := htm.GetOverdueTasksContext(context.Background())
+	if err != nil {
 		return htm.memoryManager.GetOverdueTasks()
 	}
+	return tasks
+}
+
+// GetOverdueTasksContext returns tasks that are past their due date
+func (htm *HybridTaskManager) GetOverdueTasksContext(ctx context.Context) ([]Task, error) {
+	return htm.listTasks(ctx, func(repository database.Repository) ([]database.DatabaseTask, error) {
+		return repository.GetOverdueTasks()
+	}, htm.memoryManager.GetOverdueTasks)
 }
 
 // SetStorageType changes the storage type at runtime
@@ -338,6 +455,7 @@ func (htm *HybridTaskManager) SetStorageType(storageType StorageType) {
 	htm.mu.Lock()
 	defer htm.mu.Unlock()
 	htm.storageType = storageType
+	htm.attachMemoryEvents()
 }
 
 // GetStorageType returns the current storage type
@@ -368,6 +486,25 @@ func (htm *HybridTaskManager) SyncToDatabase() error {
 	return nil
 }
 
+// refreshMemory reloads the memory copies of stored tasks in hybrid storage,
+// so that they follow changes made in the database
+func (htm *HybridTaskManager) refreshMemory(repository database.Repository, ids []int) {
+	if htm.storageType != HybridStorage {
+		return
+	}
+
+	refreshed := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if id == 0 || refreshed[id] {
+			continue
+		}
+		refreshed[id] = true
+		if dbTask, err := storedTask(repository, id); err == nil {
+			htm.memoryManager.putTask(convertFromDatabaseTask(dbTask))
+		}
+	}
+}
+
 // LoadFromDatabase loads all tasks from database into memory
 func (htm *HybridTaskManager) LoadFromDatabase() error {
 	htm.mu.Lock()
@@ -382,11 +519,12 @@ func (htm *HybridTaskManager) LoadFromDatabase() error {
 		return err
 	}
 	
-	// Clear memory and load from database
+	// Clear memory and load from database, keeping the stored IDs so that
+	// changes and undo reach the same task in both
 	htm.memoryManager = NewTaskManager()
+	htm.attachMemoryEvents()
 	for _, dbTask := range dbTasks {
-		task := convertFromDatabaseTask(&dbTask)
-		htm.memoryManager.AddTask(task.Title, task.Description, task.Priority, task.DueDate)
+		htm.memoryManager.putTask(convertFromDatabaseTask(&dbTask))
 	}
 	
 	return nil
@@ -401,6 +539,12 @@ func convertToDatabaseTask(t Task) *database.DatabaseTask {
 		categoryID = &t.Category.ID
 	}
 	
+	var recurrenceRule *string
+	if t.Recurrence != nil {
+		rule := t.Recurrence.String()
+		recurrenceRule = &rule
+	}
+	
 	return &database.DatabaseTask{
 		ID:          t.ID,
 		Title:       t.Title,
@@ -412,7 +556,14 @@ func convertToDatabaseTask(t Task) *database.DatabaseTask {
 		DueDate:     t.DueDate,
 		CategoryID:  categoryID,
 		UserID:      nil, // Will be set when users are implemented
-		IsArchived:  false,
+		IsArchived:  t.IsArchived,
+		RecurrenceRule: recurrenceRule,
+		ParentID:    t.ParentID,
+		DeletedAt:   t.DeletedAt,
+		EstimateMinutes: t.EstimateMinutes,
+		ProjectID:   t.ProjectID,
+		SprintID:    t.SprintID,
+		HiddenUntil: t.HiddenUntil,
 	}
 }
 
@@ -429,6 +580,14 @@ func convertFromDatabaseTask(dt *database.DatabaseTask) Task {
 		DueDate:     dt.DueDate,
 		Category:    nil,
 		Tags:        []Tag{},
+		ParentID:    dt.ParentID,
+		IsArchived:  dt.IsArchived,
+		DeletedAt:   dt.DeletedAt,
+		EstimateMinutes: dt.EstimateMinutes,
+		CommentCount: dt.CommentCount,
+		ProjectID:   dt.ProjectID,
+		SprintID:    dt.SprintID,
+		HiddenUntil: dt.HiddenUntil,
 	}
 	
 	// Load category if categoryID is set
@@ -437,6 +596,13 @@ func convertFromDatabaseTask(dt *database.DatabaseTask) Task {
 		// For now, we'll leave it as nil and load it separately when needed
 	}
 	
+	// Invalid stored rules are ignored rather than failing the whole read
+	if dt.RecurrenceRule != nil {
+		if rule, err := ParseRecurrenceRule(*dt.RecurrenceRule); err == nil {
+			task.Recurrence = rule
+		}
+	}
+	
 	// Tags will be loaded separately when needed
 	// This avoids circular dependencies and keeps the converter simple
 	
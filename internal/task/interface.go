@@ -1,12 +1,19 @@
 package task
 
-import "time"
+import (
+	"context"
+	"time"
 
-// TaskManagerInterface defines the interface for task management operations
+	"learn-go-capstone/internal/quickadd"
+)
+
+// TaskManagerInterface defines the interface for task management operations.
+// New code should use TaskManagerV2, which reports every failure.
 type TaskManagerInterface interface {
 	AddTask(title, description string, priority Priority, dueDate *time.Time) *Task
 	GetTask(id int) (*Task, error)
 	UpdateTaskStatus(id int, status Status) error
+	UpdateTask(id int, patch TaskPatch) (*Task, error)
 	DeleteTask(id int) error
 	GetAllTasks() []Task
 	GetTasksByStatus(status Status) []Task
@@ -14,3 +21,38 @@ type TaskManagerInterface interface {
 	SortTasksByPriority() []Task
 	GetOverdueTasks() []Task
 }
+
+// TaskManagerV2 is the context-aware task management interface.
+// Every method takes a context, which is passed down to the database, and
+// returns an error instead of silently falling back or dropping failures.
+type TaskManagerV2 interface {
+	AddTaskContext(ctx context.Context, title, description string, priority Priority, dueDate *time.Time) (*Task, error)
+	GetTaskContext(ctx context.Context, id int) (*Task, error)
+	UpdateTaskStatusContext(ctx context.Context, id int, status Status) error
+	UpdateTaskContext(ctx context.Context, id int, patch TaskPatch) (*Task, error)
+	DeleteTaskContext(ctx context.Context, id int) error
+	GetAllTasksContext(ctx context.Context) ([]Task, error)
+	GetTasksByStatusContext(ctx context.Context, status Status) ([]Task, error)
+	GetTasksByPriorityContext(ctx context.Context, priority Priority) ([]Task, error)
+	SortTasksByPriorityContext(ctx context.Context) ([]Task, error)
+	GetOverdueTasksContext(ctx context.Context) ([]Task, error)
+	ArchiveTaskContext(ctx context.Context, id int) error
+	UnarchiveTaskContext(ctx context.Context, id int) error
+	GetArchivedTasksContext(ctx context.Context) ([]Task, error)
+	SnoozeTaskContext(ctx context.Context, id int, until time.Time) error
+	UnsnoozeTaskContext(ctx context.Context, id int) error
+	GetSnoozedTasksContext(ctx context.Context) ([]Task, error)
+	RestoreTaskContext(ctx context.Context, id int) error
+	GetDeletedTasksContext(ctx context.Context) ([]Task, error)
+	GetTaskHistoryContext(ctx context.Context, id int) ([]HistoryEntry, error)
+	StartTimerContext(ctx context.Context, taskID int, note string) (*TimeEntry, error)
+	StopTimerContext(ctx context.Context) (*TimeEntry, error)
+	LogTimeContext(ctx context.Context, taskID int, startedAt time.Time, duration time.Duration, note string) (*TimeEntry, error)
+	GetTaskTimeContext(ctx context.Context, taskID int) (*TaskTime, error)
+	GetSprintReportContext(ctx context.Context, sprintID int, unit BurndownUnit) (*SprintReport, error)
+	BulkContext(ctx context.Context, request BulkRequest) (*BulkResult, error)
+	QuickAddContext(ctx context.Context, entry *quickadd.Entry, description string) (*Task, error)
+	NextTasksContext(ctx context.Context, limit int) ([]ScoredTask, error)
+	UndoContext(ctx context.Context) (*Command, error)
+	RedoContext(ctx context.Context) (*Command, error)
+}
@@ -0,0 +1,319 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"learn-go-capstone/internal/database"
+)
+
+// LinkType is the kind of a link from one task to another
+type LinkType string
+
+const (
+	// LinkBlocks keeps the linked task from being ready until the task is
+	// completed. Blocking links are the task dependencies, so they take part
+	// in the circular dependency check.
+	LinkBlocks LinkType = database.BlocksLinkType
+	// LinkRelatesTo points to a related task and reads the same from both ends
+	LinkRelatesTo LinkType = "relates_to"
+	// LinkDuplicates marks the task as a duplicate of the linked task; the
+	// duplicate can be closed when the link is made
+	LinkDuplicates LinkType = "duplicates"
+	// LinkClones marks the task as a copy of the linked task
+	LinkClones LinkType = "clones"
+)
+
+// linkTypes lists the link types in the order they are described in
+var linkTypes = []LinkType{LinkBlocks, LinkRelatesTo, LinkDuplicates, LinkClones}
+
+// Inverse returns the name of the link as seen from the linked task, such as
+// blocked_by for blocks
+func (t LinkType) Inverse() string {
+	switch t {
+	case LinkBlocks:
+		return "blocked_by"
+	case LinkDuplicates:
+		return "duplicated_by"
+	case LinkClones:
+		return "cloned_by"
+	default:
+		return string(t)
+	}
+}
+
+// ParseLinkName parses the name of a link as seen from either of its tasks.
+// inverse reports whether the name is seen from the linked task, as
+// blocked_by is for blocks.
+func ParseLinkName(name string) (linkType LinkType, inverse bool, err error) {
+	for _, candidate := range linkTypes {
+		if name == string(candidate) {
+			return candidate, false, nil
+		}
+		if name == candidate.Inverse() {
+			return candidate, true, nil
+		}
+	}
+	return "", false, fmt.Errorf("invalid link type %q: must be blocks, blocked_by, relates_to, duplicates, duplicated_by, clones or cloned_by", name)
+}
+
+// TaskLink is a link between two tasks as seen from one of them
+type TaskLink struct {
+	Type LinkType `json:"type"`
+	// Name is the link as seen from TaskID: blocked_by on a task that another
+	// task blocks, for example
+	Name         string    `json:"name"`
+	TaskID       int       `json:"task_id"`
+	LinkedTaskID int       `json:"linked_task_id"`
+	LinkedTask   *Task     `json:"linked_task,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// viewTaskLink returns a stored link as seen from one of its tasks
+func viewTaskLink(link database.TaskLink, taskID int) TaskLink {
+	linkType := LinkType(link.Type)
+	if link.TaskID == taskID {
+		return TaskLink{Type: linkType, Name: string(linkType), TaskID: taskID, LinkedTaskID: link.LinkedTaskID, CreatedAt: link.CreatedAt}
+	}
+	return TaskLink{Type: linkType, Name: linkType.Inverse(), TaskID: taskID, LinkedTaskID: link.TaskID, CreatedAt: link.CreatedAt}
+}
+
+// linkEnds returns the tasks a link named as seen from taskID starts and ends at
+func linkEnds(taskID int, name string, linkedTaskID int) (LinkType, int, int, error) {
+	linkType, inverse, err := ParseLinkName(name)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	if taskID == linkedTaskID {
+		return "", 0, 0, errors.New("invalid link: a task cannot be linked to itself")
+	}
+
+	if inverse {
+		return linkType, linkedTaskID, taskID, nil
+	}
+	return linkType, taskID, linkedTaskID, nil
+}
+
+// addDatabaseTaskLink links two stored tasks. name is the link as seen from
+// taskID, so that "blocked_by" makes the linked task block taskID.
+func addDatabaseTaskLink(repository database.Repository, events *EventBus, taskID int, name string, linkedTaskID int) (*TaskLink, error) {
+	linkType, from, to, err := linkEnds(taskID, name, linkedTaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	dbTask, err := repository.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	linked, err := repository.GetTask(linkedTaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := repository.GetTaskLinks(from)
+	if err != nil {
+		return nil, err
+	}
+	for _, link := range links {
+		if LinkType(link.Type) != linkType {
+			continue
+		}
+		forward := link.TaskID == from && link.LinkedTaskID == to
+		backward := link.TaskID == to && link.LinkedTaskID == from
+		if forward || (backward && linkType == LinkRelatesTo) {
+			return nil, fmt.Errorf("invalid link: tasks %d and %d are already linked as %s", from, to, linkType)
+		}
+	}
+
+	stored := database.TaskLink{TaskID: from, LinkedTaskID: to, Type: string(linkType)}
+	if linkType == LinkBlocks {
+		// The blocked task depends on the blocking one
+		circular, err := repository.CheckCircularDependency(to, from)
+		if err != nil {
+			return nil, err
+		}
+		if circular {
+			return nil, fmt.Errorf("invalid link: task %d blocking task %d would create a circular dependency", from, to)
+		}
+		if err := repository.AddTaskDependency(to, from); err != nil {
+			return nil, err
+		}
+
+		blocked := linked
+		if to == taskID {
+			blocked = dbTask
+		}
+		event := databaseTaskEvent(EventDependencyAdded, blocked)
+		event.DependsOnTaskID = from
+		events.Publish(event)
+		stored.CreatedAt = time.Now()
+	} else if err := repository.AddTaskLink(&stored); err != nil {
+		return nil, err
+	}
+
+	link := viewTaskLink(stored, taskID)
+	linkedTask := convertFromDatabaseTask(linked)
+	link.LinkedTask = &linkedTask
+	return &link, nil
+}
+
+// removeDatabaseTaskLink removes a link between two stored tasks, named as
+// seen from taskID
+func removeDatabaseTaskLink(repository database.Repository, taskID int, name string, linkedTaskID int) error {
+	linkType, from, to, err := linkEnds(taskID, name, linkedTaskID)
+	if err != nil {
+		return err
+	}
+
+	switch linkType {
+	case LinkBlocks:
+		return repository.RemoveTaskDependency(to, from)
+	case LinkRelatesTo:
+		// Related tasks are stored in the direction they were linked in
+		if err := repository.RemoveTaskLink(from, to, string(linkType)); err == nil {
+			return nil
+		}
+		return repository.RemoveTaskLink(to, from, string(linkType))
+	default:
+		return repository.RemoveTaskLink(from, to, string(linkType))
+	}
+}
+
+// getDatabaseTaskLinks returns the links of a stored task, oldest first, with
+// the tasks at their other ends. Links whose other task keep rejects are left
+// out; a nil keep keeps every link.
+func getDatabaseTaskLinks(repository database.Repository, taskID int, keep func(dbTask *database.DatabaseTask) (bool, error)) ([]TaskLink, error) {
+	links, err := repository.GetTaskLinks(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	viewed := make([]TaskLink, 0, len(links))
+	for _, link := range links {
+		view := viewTaskLink(link, taskID)
+		dbTask, err := repository.GetTask(view.LinkedTaskID)
+		if err != nil {
+			return nil, err
+		}
+		if keep != nil {
+			if ok, err := keep(dbTask); err != nil {
+				return nil, err
+			} else if !ok {
+				continue
+			}
+		}
+		linkedTask := convertFromDatabaseTask(dbTask)
+		view.LinkedTask = &linkedTask
+		viewed = append(viewed, view)
+	}
+
+	return viewed, nil
+}
+
+// AddLink links two tasks. name is the link as seen from taskID, such as
+// blocks or blocked_by.
+func (dm *DependencyManager) AddLink(taskID int, name string, linkedTaskID int) (*TaskLink, error) {
+	return addDatabaseTaskLink(dm.repository, dm.events, taskID, name, linkedTaskID)
+}
+
+// RemoveLink removes a link between two tasks, named as seen from taskID
+func (dm *DependencyManager) RemoveLink(taskID int, name string, linkedTaskID int) error {
+	return removeDatabaseTaskLink(dm.repository, taskID, name, linkedTaskID)
+}
+
+// GetLinks returns the links of a task in both directions, oldest first
+func (dm *DependencyManager) GetLinks(taskID int) ([]TaskLink, error) {
+	return getDatabaseTaskLinks(dm.repository, taskID, nil)
+}
+
+// GetUserTaskLinks returns the links of a task the user can see, oldest
+// first. Links to tasks the user cannot see are left out.
+func (um *UserManager) GetUserTaskLinks(userID, taskID int) ([]TaskLink, error) {
+	if _, err := um.GetVisibleTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	return getDatabaseTaskLinks(um.repository, taskID, func(dbTask *database.DatabaseTask) (bool, error) {
+		return canSeeTask(um.repository, userID, dbTask)
+	})
+}
+
+// AddUserTaskLink links a task the user owns to a task the user can see.
+// name is the link as seen from taskID. When closeDuplicate is set on a
+// duplicates link, the duplicate is cancelled unless it is already closed.
+func (um *UserManager) AddUserTaskLink(userID, taskID int, name string, linkedTaskID int, closeDuplicate bool) (*TaskLink, error) {
+	dbTask, err := um.repository.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if dbTask.UserID == nil || *dbTask.UserID != userID {
+		return nil, errors.New("access denied: task does not belong to user or user ID is missing")
+	}
+	if _, err := um.GetVisibleTask(userID, linkedTaskID); err != nil {
+		return nil, err
+	}
+
+	var link *TaskLink
+	var published []Event
+	buffer := NewEventBus()
+	buffer.Subscribe(func(event Event) { published = append(published, event) })
+
+	err = um.repository.Transaction(func(tx database.Repository) error {
+		bound := um.withRepository(tx, buffer)
+
+		var err error
+		link, err = addDatabaseTaskLink(tx, buffer, taskID, name, linkedTaskID)
+		if err != nil {
+			return err
+		}
+		if !closeDuplicate || link.Type != LinkDuplicates {
+			return nil
+		}
+
+		duplicate, err := tx.GetTask(taskID)
+		if link.Name != string(LinkDuplicates) {
+			duplicate, err = tx.GetTask(linkedTaskID)
+		}
+		if err != nil {
+			return err
+		}
+		if status := Status(duplicate.Status); status == Completed || status == Cancelled {
+			return nil
+		}
+		if err := bound.UpdateUserTaskStatus(userID, duplicate.ID, Cancelled); err != nil {
+			return err
+		}
+
+		if duplicate.ID == linkedTaskID {
+			closed, err := tx.GetTask(linkedTaskID)
+			if err != nil {
+				return err
+			}
+			linkedTask := convertFromDatabaseTask(closed)
+			link.LinkedTask = &linkedTask
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	um.events.Publish(published...)
+	return link, nil
+}
+
+// RemoveUserTaskLink removes a link from a task the user owns, named as seen
+// from taskID
+func (um *UserManager) RemoveUserTaskLink(userID, taskID int, name string, linkedTaskID int) error {
+	dbTask, err := um.repository.GetTask(taskID)
+	if err != nil {
+		return err
+	}
+	if dbTask.UserID == nil || *dbTask.UserID != userID {
+		return errors.New("access denied: task does not belong to user or user ID is missing")
+	}
+
+	return removeDatabaseTaskLink(um.repository, taskID, name, linkedTaskID)
+}
@@ -0,0 +1,168 @@
+package task
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLinkName(t *testing.T) {
+	tests := []struct {
+		name     string
+		linkType LinkType
+		inverse  bool
+	}{
+		{"blocks", LinkBlocks, false},
+		{"blocked_by", LinkBlocks, true},
+		{"relates_to", LinkRelatesTo, false},
+		{"duplicated_by", LinkDuplicates, true},
+		{"cloned_by", LinkClones, true},
+	}
+	for _, tt := range tests {
+		linkType, inverse, err := ParseLinkName(tt.name)
+		if err != nil {
+			t.Errorf("Failed to parse %q: %v", tt.name, err)
+			continue
+		}
+		if linkType != tt.linkType || inverse != tt.inverse {
+			t.Errorf("Expected %q to be %s (inverse %v), got %s (inverse %v)", tt.name, tt.linkType, tt.inverse, linkType, inverse)
+		}
+	}
+
+	if _, _, err := ParseLinkName("depends"); err == nil || !strings.HasPrefix(err.Error(), "invalid link type") {
+		t.Errorf("Expected an invalid link type error, got %v", err)
+	}
+}
+
+func TestDependencyManagerLinks(t *testing.T) {
+	repository := setupTestRepository(t)
+	um := NewUserManager(repository)
+	dm := NewDependencyManager(repository)
+
+	user, err := um.RegisterUser("user", "user@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	var ids []int
+	for _, title := range []string{"Design", "Build", "Ship"} {
+		created, err := um.CreateUserTask(user.ID, title, "", Medium, nil)
+		if err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		ids = append(ids, created.ID)
+	}
+
+	// Build is blocked by Design, named from the blocked side
+	link, err := dm.AddLink(ids[1], "blocked_by", ids[0])
+	if err != nil {
+		t.Fatalf("Failed to add link: %v", err)
+	}
+	if link.Type != LinkBlocks || link.Name != "blocked_by" || link.LinkedTask == nil || link.LinkedTask.ID != ids[0] {
+		t.Errorf("Expected a blocked_by link to Design, got %+v", link)
+	}
+	if ready, _ := dm.CanCompleteTask(ids[1]); ready {
+		t.Error("Expected a blocked task not to be ready")
+	}
+	if _, err := dm.AddLink(ids[1], "blocks", ids[0]); err == nil || !strings.Contains(err.Error(), "circular dependency") {
+		t.Errorf("Expected a circular dependency error, got %v", err)
+	}
+
+	if _, err := dm.AddLink(ids[2], "relates_to", ids[0]); err != nil {
+		t.Fatalf("Failed to add link: %v", err)
+	}
+	if _, err := dm.AddLink(ids[0], "relates_to", ids[2]); err == nil || !strings.HasPrefix(err.Error(), "invalid link") {
+		t.Errorf("Expected related tasks to be linked only once, got %v", err)
+	}
+	if _, err := dm.AddLink(ids[0], "clones", ids[0]); err == nil {
+		t.Error("Expected a task not to be linked to itself")
+	}
+
+	// Each end sees the link under its own name
+	links, err := dm.GetLinks(ids[0])
+	if err != nil {
+		t.Fatalf("Failed to get links: %v", err)
+	}
+	if len(links) != 2 || links[0].Name != "blocks" || links[0].LinkedTaskID != ids[1] || links[1].Name != "relates_to" || links[1].LinkedTaskID != ids[2] {
+		t.Errorf("Expected Design to block Build and relate to Ship, got %+v", links)
+	}
+
+	if err := dm.RemoveLink(ids[0], "blocks", ids[1]); err != nil {
+		t.Fatalf("Failed to remove link: %v", err)
+	}
+	if ready, _ := dm.CanCompleteTask(ids[1]); !ready {
+		t.Error("Expected the task to be ready once nothing blocks it")
+	}
+	if err := dm.RemoveLink(ids[0], "relates_to", ids[2]); err != nil {
+		t.Fatalf("Failed to remove link from the other end: %v", err)
+	}
+	if links, _ := dm.GetLinks(ids[2]); len(links) != 0 {
+		t.Errorf("Expected no links left, got %+v", links)
+	}
+}
+
+func TestUserManagerTaskLinks(t *testing.T) {
+	repository := setupTestRepository(t)
+	um := NewUserManager(repository)
+
+	user, err := um.RegisterUser("user", "user@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	other, err := um.RegisterUser("other", "other@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	original, err := um.CreateUserTask(user.ID, "Fix login", "", High, nil)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	duplicate, err := um.CreateUserTask(user.ID, "Login is broken", "", Medium, nil)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	private, err := um.CreateUserTask(other.ID, "Private", "", Low, nil)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	if _, err := um.AddUserTaskLink(user.ID, original.ID, "relates_to", private.ID, false); err == nil {
+		t.Error("Expected linking to a task the user cannot see to fail")
+	}
+	if _, err := um.AddUserTaskLink(other.ID, original.ID, "relates_to", private.ID, false); err == nil {
+		t.Error("Expected linking from another user's task to fail")
+	}
+
+	// Linking the original as duplicated by the duplicate closes the duplicate
+	link, err := um.AddUserTaskLink(user.ID, original.ID, "duplicated_by", duplicate.ID, true)
+	if err != nil {
+		t.Fatalf("Failed to add link: %v", err)
+	}
+	if link.LinkedTask == nil || link.LinkedTask.Status != Cancelled {
+		t.Errorf("Expected the duplicate to be cancelled, got %+v", link.LinkedTask)
+	}
+	if original, _ := um.GetUserTask(user.ID, original.ID); original.Status != Pending {
+		t.Errorf("Expected the original to stay open, got %s", original.Status)
+	}
+
+	links, err := um.GetUserTaskLinks(user.ID, duplicate.ID)
+	if err != nil {
+		t.Fatalf("Failed to get links: %v", err)
+	}
+	if len(links) != 1 || links[0].Name != "duplicates" || links[0].LinkedTaskID != original.ID {
+		t.Errorf("Expected the duplicate to link to the original, got %+v", links)
+	}
+
+	// Links to tasks the user cannot see are left out
+	if _, err := NewDependencyManager(repository).AddLink(private.ID, "relates_to", original.ID); err != nil {
+		t.Fatalf("Failed to add link: %v", err)
+	}
+	if links, _ := um.GetUserTaskLinks(user.ID, original.ID); len(links) != 1 {
+		t.Errorf("Expected only the visible link, got %+v", links)
+	}
+
+	if err := um.RemoveUserTaskLink(user.ID, duplicate.ID, "duplicates", original.ID); err != nil {
+		t.Fatalf("Failed to remove link: %v", err)
+	}
+	if err := um.RemoveUserTaskLink(user.ID, duplicate.ID, "duplicates", original.ID); err == nil || !strings.HasSuffix(err.Error(), "not found") {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
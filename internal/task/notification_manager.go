@@ -2,6 +2,7 @@ package task
 
 import (
 	"fmt"
+	"log"
 	"time"
 
 	"learn-go-capstone/internal/database"
@@ -143,6 +144,100 @@ func (nm *NotificationManager) CreateTaskDeletedNotification(userID, taskID int,
 	return nm.notificationService.SendNotification(notification)
 }
 
+// CreateTaskWokenNotification creates a notification when a snoozed task
+// comes back into the listings
+func (nm *NotificationManager) CreateTaskWokenNotification(userID, taskID int) error {
+	// Get task details
+	task, err := nm.repository.GetTask(taskID)
+	if err != nil {
+		return err
+	}
+	
+	notification := &notifications.Notification{
+		UserID:      userID,
+		TaskID:      taskID,
+		Type:        notifications.TypeInApp,
+		Priority:    notifications.PriorityNormal,
+		Trigger:     notifications.TriggerSnoozeEnded,
+		Title:       "Snoozed Task Is Back",
+		Message:     fmt.Sprintf("Task '%s' is no longer snoozed", task.Title),
+		Recipient:   "",
+		MaxRetries:  3,
+	}
+	
+	return nm.notificationService.SendNotification(notification)
+}
+
+// CreateMentionNotification creates an in-app notification for a user who was
+// mentioned in a comment on a task
+func (nm *NotificationManager) CreateMentionNotification(userID, taskID int, comment *Comment) error {
+	// Get task details
+	task, err := nm.repository.GetTask(taskID)
+	if err != nil {
+		return err
+	}
+	
+	notification := &notifications.Notification{
+		UserID:      userID,
+		TaskID:      taskID,
+		Type:        notifications.TypeInApp,
+		Priority:    notifications.PriorityNormal,
+		Trigger:     notifications.TriggerMention,
+		Title:       "You Were Mentioned",
+		Message:     fmt.Sprintf("%s mentioned you in a comment on task '%s'", comment.Author, task.Title),
+		Recipient:   "",
+		MaxRetries:  3,
+		Metadata:    map[string]interface{}{"comment_id": comment.ID},
+	}
+	
+	return nm.notificationService.SendNotification(notification)
+}
+
+// SubscribeToEvents sends notifications to task owners when their tasks are
+// created, deleted or woken up from a snooze, to every watcher of a task when
+// its status changes, and to the users mentioned in comments. Notifications
+// are sent from an asynchronous subscriber. The returned function unsubscribes.
+func (nm *NotificationManager) SubscribeToEvents(bus *EventBus) func() {
+	return bus.SubscribeAsync(nm.handleEvent, EventTaskCreated, EventStatusChanged, EventTaskDeleted, EventCommentAdded, EventCommentEdited, EventTaskWoken)
+}
+
+// handleEvent turns a task event into a notification for the task owner, for
+// the watchers of a status change or for the mentioned users of a comment event
+func (nm *NotificationManager) handleEvent(event Event) {
+	switch event.Type {
+	case EventCommentAdded, EventCommentEdited:
+		for _, userID := range event.Mentions {
+			if err := nm.CreateMentionNotification(userID, event.TaskID, event.Comment); err != nil {
+				log.Printf("Failed to send mention notification to user %d for task %d: %v", userID, event.TaskID, err)
+			}
+		}
+		return
+	case EventStatusChanged:
+		nm.notifyWatchers(event.TaskID, func(userID int) error {
+			return nm.CreateStatusChangeNotification(userID, event.TaskID, event.OldStatus, event.NewStatus)
+		})
+		return
+	}
+	
+	if event.UserID == nil {
+		return
+	}
+	
+	var err error
+	switch event.Type {
+	case EventTaskCreated:
+		err = nm.CreateTaskCreatedNotification(*event.UserID, event.TaskID)
+	case EventTaskDeleted:
+		err = nm.CreateTaskDeletedNotification(*event.UserID, event.TaskID, event.Task.Title)
+	case EventTaskWoken:
+		err = nm.CreateTaskWokenNotification(*event.UserID, event.TaskID)
+	}
+	
+	if err != nil {
+		log.Printf("Failed to send notification for %s of task %d: %v", event.Type, event.TaskID, err)
+	}
+}
+
 // CheckOverdueTasks checks for overdue tasks and creates reminders
 func (nm *NotificationManager) CheckOverdueTasks() error {
 	// Get all overdue tasks
@@ -151,26 +246,41 @@ func (nm *NotificationManager) CheckOverdueTasks() error {
 		return err
 	}
 	
-	// Create overdue reminders for each task
+	// Create overdue reminders for every watcher of each task
 	for _, task := range overdueTasks {
-		if task.UserID == nil {
-			continue // Skip tasks without user ID
-		}
-		
 		// Calculate how many hours overdue
 		overdueHours := int(time.Since(*task.DueDate).Hours())
 		
-		// Create overdue reminder
-		err = nm.CreateOverdueReminder(*task.UserID, task.ID, overdueHours)
-		if err != nil {
-			// Log error but continue with other tasks
-			continue
-		}
+		nm.notifyWatchers(task.ID, func(userID int) error {
+			return nm.CreateOverdueReminder(userID, task.ID, overdueHours)
+		})
 	}
 	
 	return nil
 }
 
+// notifyWatchers calls send for the owner of a task and every user taking
+// part in it. Failures are logged and do not stop the other notifications.
+func (nm *NotificationManager) notifyWatchers(taskID int, send func(userID int) error) {
+	task, err := nm.repository.GetTask(taskID)
+	if err != nil {
+		log.Printf("Failed to get watchers of task %d: %v", taskID, err)
+		return
+	}
+	
+	watchers, err := taskWatchers(nm.repository, task)
+	if err != nil {
+		log.Printf("Failed to get watchers of task %d: %v", taskID, err)
+		return
+	}
+	
+	for _, userID := range watchers {
+		if err := send(userID); err != nil {
+			log.Printf("Failed to notify user %d about task %d: %v", userID, taskID, err)
+		}
+	}
+}
+
 // CheckDueSoonTasks checks for tasks due soon and creates reminders
 func (nm *NotificationManager) CheckDueSoonTasks(reminderMinutes int) error {
 	// Get all tasks with due dates
@@ -182,9 +292,9 @@ func (nm *NotificationManager) CheckDueSoonTasks(reminderMinutes int) error {
 	now := time.Now()
 	reminderTime := now.Add(time.Duration(reminderMinutes) * time.Minute)
 	
-	// Find tasks due within the reminder time
+	// Find tasks due within the reminder time; snoozed tasks stay quiet
 	for _, task := range allTasks {
-		if task.DueDate == nil || task.UserID == nil {
+		if task.DueDate == nil || task.UserID == nil || snoozedAt(task.HiddenUntil, now) {
 			continue
 		}
 		
@@ -107,7 +107,13 @@ func TestNotificationManager(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to create task deleted notification: %v", err)
 	}
-	
+
+	// Test creating task woken notification
+	err = notificationManager.CreateTaskWokenNotification(user.ID, task.ID)
+	if err != nil {
+		t.Fatalf("Failed to create task woken notification: %v", err)
+	}
+
 	// Test checking overdue tasks
 	err = notificationManager.CheckOverdueTasks()
 	if err != nil {
@@ -0,0 +1,134 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"learn-go-capstone/internal/database"
+)
+
+// TaskPatch describes a partial update of a task.
+// Nil fields are left untouched, which gives callers JSON Merge Patch
+// semantics: only the fields present in the patch are changed.
+type TaskPatch struct {
+	Title       *string
+	Description *string
+	Priority    *Priority
+	DueDate     *time.Time
+	// ClearDueDate removes the due date (a JSON null in a merge patch)
+	ClearDueDate bool
+	Recurrence   *RecurrenceRule
+	// ClearRecurrence stops a task from recurring
+	ClearRecurrence bool
+	EstimateMinutes *int
+	// ClearEstimate removes the time estimate
+	ClearEstimate bool
+}
+
+// IsEmpty reports whether the patch changes nothing
+func (p TaskPatch) IsEmpty() bool {
+	return p.Title == nil && p.Description == nil && p.Priority == nil &&
+		p.DueDate == nil && !p.ClearDueDate && p.Recurrence == nil && !p.ClearRecurrence &&
+		p.EstimateMinutes == nil && !p.ClearEstimate
+}
+
+// Validate checks the patched fields using the same rules for every storage type
+func (p TaskPatch) Validate() error {
+	if p.Title != nil && strings.TrimSpace(*p.Title) == "" {
+		return errors.New("title cannot be empty")
+	}
+
+	if p.Priority != nil && (*p.Priority < Low || *p.Priority > Urgent) {
+		return fmt.Errorf("invalid priority %d: must be between %d and %d", *p.Priority, Low, Urgent)
+	}
+
+	if p.DueDate != nil && p.ClearDueDate {
+		return errors.New("due date cannot be both set and cleared")
+	}
+
+	if p.Recurrence != nil {
+		if p.ClearRecurrence {
+			return errors.New("recurrence cannot be both set and cleared")
+		}
+		if err := p.Recurrence.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if p.EstimateMinutes != nil {
+		if p.ClearEstimate {
+			return errors.New("estimate cannot be both set and cleared")
+		}
+		if *p.EstimateMinutes < 0 {
+			return fmt.Errorf("invalid estimate %d: cannot be negative", *p.EstimateMinutes)
+		}
+	}
+
+	return nil
+}
+
+// apply applies the patch to an in-memory task and bumps UpdatedAt
+func (p TaskPatch) apply(t *Task) {
+	if p.Title != nil {
+		t.Title = strings.TrimSpace(*p.Title)
+	}
+	if p.Description != nil {
+		t.Description = *p.Description
+	}
+	if p.Priority != nil {
+		t.Priority = *p.Priority
+	}
+	if p.ClearDueDate {
+		t.DueDate = nil
+	} else if p.DueDate != nil {
+		dueDate := *p.DueDate
+		t.DueDate = &dueDate
+	}
+	if p.ClearRecurrence {
+		t.Recurrence = nil
+	} else if p.Recurrence != nil {
+		rule := *p.Recurrence
+		t.Recurrence = &rule
+	}
+	if p.ClearEstimate {
+		t.EstimateMinutes = nil
+	} else if p.EstimateMinutes != nil {
+		estimate := *p.EstimateMinutes
+		t.EstimateMinutes = &estimate
+	}
+	t.UpdatedAt = time.Now()
+}
+
+// applyToDatabaseTask applies the patch to a database task and bumps UpdatedAt
+func (p TaskPatch) applyToDatabaseTask(dt *database.DatabaseTask) {
+	if p.Title != nil {
+		dt.Title = strings.TrimSpace(*p.Title)
+	}
+	if p.Description != nil {
+		dt.Description = *p.Description
+	}
+	if p.Priority != nil {
+		dt.Priority = int(*p.Priority)
+	}
+	if p.ClearDueDate {
+		dt.DueDate = nil
+	} else if p.DueDate != nil {
+		dueDate := *p.DueDate
+		dt.DueDate = &dueDate
+	}
+	if p.ClearRecurrence {
+		dt.RecurrenceRule = nil
+	} else if p.Recurrence != nil {
+		rule := p.Recurrence.String()
+		dt.RecurrenceRule = &rule
+	}
+	if p.ClearEstimate {
+		dt.EstimateMinutes = nil
+	} else if p.EstimateMinutes != nil {
+		estimate := *p.EstimateMinutes
+		dt.EstimateMinutes = &estimate
+	}
+	dt.UpdatedAt = time.Now()
+}
@@ -0,0 +1,192 @@
+package task
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"learn-go-capstone/internal/database"
+)
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func priorityPtr(p Priority) *Priority {
+	return &p
+}
+
+func setupTestRepository(t *testing.T) database.Repository {
+	config := &database.Config{
+		Driver: "sqlite3",
+		DSN:    fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()),
+	}
+
+	db, err := database.Connect(config)
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	t.Cleanup(func() { database.Close(db) })
+
+	migrationManager := database.NewMigrationManager(db)
+	if err := migrationManager.Migrate(); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	return database.NewSQLiteRepository(db)
+}
+
+func TestTaskPatchValidate(t *testing.T) {
+	dueDate := time.Now()
+
+	tests := []struct {
+		name    string
+		patch   TaskPatch
+		wantErr bool
+	}{
+		{"empty patch", TaskPatch{}, false},
+		{"valid fields", TaskPatch{Title: stringPtr("New"), Priority: priorityPtr(High)}, false},
+		{"blank title", TaskPatch{Title: stringPtr("   ")}, true},
+		{"priority too low", TaskPatch{Priority: priorityPtr(0)}, true},
+		{"priority too high", TaskPatch{Priority: priorityPtr(Urgent + 1)}, true},
+		{"set and clear due date", TaskPatch{DueDate: &dueDate, ClearDueDate: true}, true},
+	}
+
+	for _, tt := range tests {
+		err := tt.patch.Validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestTaskManagerUpdateTask(t *testing.T) {
+	tm := NewTaskManager()
+	dueDate := time.Now().Add(24 * time.Hour)
+	created := tm.AddTask("Original", "Original description", Low, &dueDate)
+
+	// Make sure the update timestamp can move forward
+	time.Sleep(time.Millisecond)
+
+	updated, err := tm.UpdateTask(created.ID, TaskPatch{
+		Title:    stringPtr("  Renamed  "),
+		Priority: priorityPtr(Urgent),
+	})
+	if err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+
+	if updated.ID != created.ID {
+		t.Errorf("Expected ID %d to be kept, got %d", created.ID, updated.ID)
+	}
+	if updated.Title != "Renamed" {
+		t.Errorf("Expected title 'Renamed', got '%s'", updated.Title)
+	}
+	if updated.Description != "Original description" {
+		t.Errorf("Expected description to be unchanged, got '%s'", updated.Description)
+	}
+	if updated.Priority != Urgent {
+		t.Errorf("Expected priority Urgent, got %s", updated.Priority)
+	}
+	if updated.DueDate == nil {
+		t.Error("Expected due date to be unchanged")
+	}
+	if !updated.UpdatedAt.After(created.UpdatedAt) {
+		t.Error("Expected UpdatedAt to be bumped")
+	}
+
+	// Clearing the due date
+	updated, err = tm.UpdateTask(created.ID, TaskPatch{ClearDueDate: true})
+	if err != nil {
+		t.Fatalf("Failed to clear due date: %v", err)
+	}
+	if updated.DueDate != nil {
+		t.Error("Expected due date to be cleared")
+	}
+
+	// Invalid patches leave the task untouched
+	if _, err := tm.UpdateTask(created.ID, TaskPatch{Title: stringPtr("")}); err == nil {
+		t.Error("Expected error for empty title")
+	}
+	stored, _ := tm.GetTask(created.ID)
+	if stored.Title != "Renamed" {
+		t.Errorf("Expected title to stay 'Renamed', got '%s'", stored.Title)
+	}
+
+	if _, err := tm.UpdateTask(999, TaskPatch{Title: stringPtr("Missing")}); err == nil {
+		t.Error("Expected error for non-existent task")
+	}
+}
+
+func TestHybridTaskManagerUpdateTask(t *testing.T) {
+	repository := setupTestRepository(t)
+	htm := NewHybridTaskManager(repository, DatabaseStorage)
+
+	created := htm.AddTask("Database task", "Stored in SQLite", Medium, nil)
+	dueDate := time.Date(2030, 1, 15, 9, 0, 0, 0, time.UTC)
+
+	updated, err := htm.UpdateTask(created.ID, TaskPatch{
+		Description: stringPtr("Edited"),
+		DueDate:     &dueDate,
+	})
+	if err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+	if updated.Title != "Database task" {
+		t.Errorf("Expected title to be unchanged, got '%s'", updated.Title)
+	}
+
+	stored, err := htm.GetTask(created.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if stored.Description != "Edited" {
+		t.Errorf("Expected description 'Edited', got '%s'", stored.Description)
+	}
+	if stored.DueDate == nil || !stored.DueDate.Equal(dueDate) {
+		t.Errorf("Expected due date %v, got %v", dueDate, stored.DueDate)
+	}
+
+	if _, err := htm.UpdateTask(created.ID, TaskPatch{Priority: priorityPtr(9)}); err == nil {
+		t.Error("Expected error for invalid priority")
+	}
+}
+
+func TestUserManagerPatchUserTask(t *testing.T) {
+	repository := setupTestRepository(t)
+	userManager := NewUserManager(repository)
+
+	owner, err := userManager.RegisterUser("owner", "owner@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register owner: %v", err)
+	}
+	other, err := userManager.RegisterUser("other", "other@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register other user: %v", err)
+	}
+
+	created, err := userManager.CreateUserTask(owner.ID, "Owned task", "", Low, nil)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	updated, err := userManager.PatchUserTask(owner.ID, created.ID, TaskPatch{Title: stringPtr("Owned and edited")})
+	if err != nil {
+		t.Fatalf("Failed to patch task: %v", err)
+	}
+	if updated.Title != "Owned and edited" {
+		t.Errorf("Expected title 'Owned and edited', got '%s'", updated.Title)
+	}
+
+	if _, err := userManager.PatchUserTask(other.ID, created.ID, TaskPatch{Title: stringPtr("Hijacked")}); err == nil {
+		t.Error("Expected access denied when patching another user's task")
+	}
+
+	stored, err := userManager.GetUserTask(owner.ID, created.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if stored.Title != "Owned and edited" {
+		t.Errorf("Expected title to stay 'Owned and edited', got '%s'", stored.Title)
+	}
+}
@@ -0,0 +1,122 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+
+	"learn-go-capstone/internal/database"
+	"learn-go-capstone/internal/rank"
+)
+
+// TaskPosition is where a task sits in a manually ordered list: the user's
+// own list, or the shared list of a project when ProjectID is set
+type TaskPosition struct {
+	TaskID    int
+	ProjectID *int
+	Key       string
+}
+
+// MoveUserTask places a task right after another one in a manually ordered
+// list, or at the top of the list when afterTaskID is nil. Without a project
+// the list is the user's own order of their tasks; with one it is the order
+// of the project's tasks that all its members share. Only the moved task gets
+// a new key, unless that key grows too long and the list is rebalanced.
+func (um *UserManager) MoveUserTask(userID, taskID int, projectID, afterTaskID *int) (*TaskPosition, error) {
+	if afterTaskID != nil && *afterTaskID == taskID {
+		return nil, errors.New("invalid position: a task cannot be placed after itself")
+	}
+
+	list := database.PersonalTaskList(userID)
+	if projectID != nil {
+		role, err := projectRole(um.repository, userID, *projectID)
+		if err != nil {
+			return nil, err
+		}
+		if role == "" {
+			return nil, fmt.Errorf("project with ID %d not found", *projectID)
+		}
+		list = database.ProjectTaskList(*projectID)
+	}
+
+	dbTask, err := um.repository.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if projectID == nil && (dbTask.UserID == nil || *dbTask.UserID != userID) {
+		return nil, errors.New("access denied: task does not belong to user or user ID is missing")
+	}
+	if projectID != nil && (dbTask.ProjectID == nil || *dbTask.ProjectID != *projectID) {
+		return nil, fmt.Errorf("invalid position: task %d is not in project %d", taskID, *projectID)
+	}
+
+	position := &TaskPosition{TaskID: taskID, ProjectID: projectID}
+	err = um.repository.Transaction(func(tx database.Repository) error {
+		ranks, err := tx.GetTaskRanks(list)
+		if err != nil {
+			return err
+		}
+
+		// The moved task goes at index in the list without it
+		others := make([]database.TaskRank, 0, len(ranks))
+		for _, taskRank := range ranks {
+			if taskRank.TaskID != taskID {
+				others = append(others, taskRank)
+			}
+		}
+		index := 0
+		if afterTaskID != nil {
+			index = -1
+			for i, taskRank := range others {
+				if taskRank.TaskID == *afterTaskID {
+					index = i + 1
+					break
+				}
+			}
+			if index < 0 {
+				return fmt.Errorf("invalid position: task %d has no position in this list", *afterTaskID)
+			}
+		}
+
+		var prev, next string
+		if index > 0 {
+			prev = others[index-1].Key
+		}
+		if index < len(others) {
+			next = others[index].Key
+		}
+		key, err := rank.Between(prev, next)
+		if err != nil {
+			return err
+		}
+
+		if len(key) <= rank.MaxLength {
+			position.Key = key
+			return tx.SetTaskRank(&database.TaskRank{List: list, TaskID: taskID, Key: key})
+		}
+
+		ordered := make([]database.TaskRank, 0, len(others)+1)
+		ordered = append(ordered, others[:index]...)
+		ordered = append(ordered, database.TaskRank{List: list, TaskID: taskID})
+		ordered = append(ordered, others[index:]...)
+		rebalanced := rebalanceTaskRanks(ordered)
+		position.Key = rebalanced[index].Key
+		return tx.ReplaceTaskRanks(list, rebalanced)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return position, nil
+}
+
+// rebalanceTaskRanks gives ordered tasks evenly spaced, short keys in the
+// same order, which makes room again after many moves into one spot
+func rebalanceTaskRanks(ordered []database.TaskRank) []database.TaskRank {
+	keys := rank.Spread(len(ordered))
+	rebalanced := make([]database.TaskRank, len(ordered))
+	for i, taskRank := range ordered {
+		taskRank.Key = keys[i]
+		rebalanced[i] = taskRank
+	}
+	return rebalanced
+}
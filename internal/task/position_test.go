@@ -0,0 +1,176 @@
+package task
+
+import (
+	"testing"
+
+	"learn-go-capstone/internal/database"
+	"learn-go-capstone/internal/rank"
+)
+
+func TestMoveUserTask(t *testing.T) {
+	repository := setupTestRepository(t)
+	um := NewUserManager(repository)
+	pm := NewProjectManager(repository)
+
+	owner, err := um.RegisterUser("owner", "owner@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	other, err := um.RegisterUser("other", "other@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+
+	var ids []int
+	for _, title := range []string{"First", "Second", "Third"} {
+		created, err := um.CreateUserTask(owner.ID, title, "", Medium, nil)
+		if err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		ids = append(ids, created.ID)
+	}
+
+	// order returns the tasks of a list in order
+	order := func(list string) []int {
+		ranks, err := repository.GetTaskRanks(list)
+		if err != nil {
+			t.Fatalf("Failed to get task ranks: %v", err)
+		}
+		var order []int
+		for _, taskRank := range ranks {
+			order = append(order, taskRank.TaskID)
+		}
+		return order
+	}
+	equal := func(a, b []int) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	}
+	personal := database.PersonalTaskList(owner.ID)
+
+	if _, err := um.MoveUserTask(owner.ID, ids[2], nil, nil); err != nil {
+		t.Fatalf("Failed to move task: %v", err)
+	}
+	if _, err := um.MoveUserTask(owner.ID, ids[0], nil, &ids[2]); err != nil {
+		t.Fatalf("Failed to move task: %v", err)
+	}
+	if _, err := um.MoveUserTask(owner.ID, ids[1], nil, nil); err != nil {
+		t.Fatalf("Failed to move task: %v", err)
+	}
+	if got := order(personal); !equal(got, []int{ids[1], ids[2], ids[0]}) {
+		t.Fatalf("Expected order %v, got %v", []int{ids[1], ids[2], ids[0]}, got)
+	}
+
+	// Moving a task again only changes its own key
+	before, _ := repository.GetTaskRanks(personal)
+	if _, err := um.MoveUserTask(owner.ID, ids[1], nil, &ids[0]); err != nil {
+		t.Fatalf("Failed to move task: %v", err)
+	}
+	after, _ := repository.GetTaskRanks(personal)
+	if after[0] != before[1] || after[1] != before[2] {
+		t.Errorf("Expected the other keys to stay, got %v and %v", before, after)
+	}
+
+	if _, err := um.MoveUserTask(owner.ID, ids[0], nil, &ids[0]); err == nil {
+		t.Error("Expected placing a task after itself to fail")
+	}
+	unranked, err := um.CreateUserTask(owner.ID, "Unranked", "", Low, nil)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if _, err := um.MoveUserTask(owner.ID, ids[0], nil, &unranked.ID); err == nil {
+		t.Error("Expected placing a task after an unranked task to fail")
+	}
+	if _, err := um.MoveUserTask(other.ID, ids[0], nil, nil); err == nil {
+		t.Error("Expected moving another user's task to fail")
+	}
+
+	// Projects share one order between their members
+	project, err := pm.CreateProject(owner.ID, "Shared", "", ProjectSettings{})
+	if err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	if _, err := um.MoveUserTask(owner.ID, ids[0], &project.ID, nil); err == nil {
+		t.Error("Expected moving a task outside the project to fail")
+	}
+	if _, err := um.SetUserTaskProject(owner.ID, ids[0], &project.ID); err != nil {
+		t.Fatalf("Failed to move task to project: %v", err)
+	}
+	if _, err := um.MoveUserTask(other.ID, ids[0], &project.ID, nil); err == nil {
+		t.Error("Expected a non-member to be refused")
+	}
+	position, err := um.MoveUserTask(owner.ID, ids[0], &project.ID, nil)
+	if err != nil {
+		t.Fatalf("Failed to move task in project: %v", err)
+	}
+	if position.ProjectID == nil || *position.ProjectID != project.ID || position.Key == "" {
+		t.Errorf("Expected a position in the project, got %+v", position)
+	}
+	if got := order(database.ProjectTaskList(project.ID)); !equal(got, []int{ids[0]}) {
+		t.Errorf("Expected the project order to hold the task, got %v", got)
+	}
+}
+
+func TestMoveUserTaskRebalances(t *testing.T) {
+	repository := setupTestRepository(t)
+	um := NewUserManager(repository)
+
+	user, err := um.RegisterUser("user", "user@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+
+	var ids []int
+	for _, title := range []string{"Anchor", "Left", "Right"} {
+		created, err := um.CreateUserTask(user.ID, title, "", Medium, nil)
+		if err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		ids = append(ids, created.ID)
+	}
+	for i := len(ids) - 1; i >= 0; i-- {
+		if _, err := um.MoveUserTask(user.ID, ids[i], nil, nil); err != nil {
+			t.Fatalf("Failed to move task: %v", err)
+		}
+	}
+
+	// Keep moving the last task right after the anchor, which halves the
+	// room there every time, until the list has to be rebalanced
+	list := database.PersonalTaskList(user.ID)
+	initial, _ := repository.GetTaskRanks(list)
+	rebalanced := false
+	for i := 0; i < 200 && !rebalanced; i++ {
+		ranks, err := repository.GetTaskRanks(list)
+		if err != nil {
+			t.Fatalf("Failed to get task ranks: %v", err)
+		}
+		last := ranks[len(ranks)-1].TaskID
+		if _, err := um.MoveUserTask(user.ID, last, nil, &ids[0]); err != nil {
+			t.Fatalf("Failed to move task: %v", err)
+		}
+
+		moved, err := repository.GetTaskRanks(list)
+		if err != nil {
+			t.Fatalf("Failed to get task ranks: %v", err)
+		}
+		if moved[0].TaskID != ids[0] || moved[1].TaskID != last {
+			t.Fatalf("Expected the moved task right after the anchor, got %v", moved)
+		}
+		for _, taskRank := range moved {
+			if len(taskRank.Key) > rank.MaxLength {
+				t.Fatalf("Expected keys of at most %d digits, got %q", rank.MaxLength, taskRank.Key)
+			}
+		}
+		rebalanced = moved[0].Key != initial[0].Key
+	}
+	if !rebalanced {
+		t.Error("Expected the list to be rebalanced")
+	}
+}
@@ -0,0 +1,467 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"learn-go-capstone/internal/database"
+)
+
+// maxProjectNameLength bounds the name of a project, in characters
+const maxProjectNameLength = 100
+
+// ProjectRole is the part a member takes in a project
+type ProjectRole string
+
+const (
+	// ProjectOwner can change the settings and the members of the project
+	ProjectOwner ProjectRole = "owner"
+	// ProjectMember can read the tasks of the project and change their status
+	ProjectMember ProjectRole = "member"
+)
+
+// ParseProjectRole parses the name of a project role
+func ParseProjectRole(value string) (ProjectRole, error) {
+	switch role := ProjectRole(value); role {
+	case ProjectOwner, ProjectMember:
+		return role, nil
+	default:
+		return "", fmt.Errorf("invalid project role %q: must be owner or member", value)
+	}
+}
+
+// StatusTransition allows tasks to move from one status to another
+type StatusTransition struct {
+	From Status `json:"from"`
+	To   Status `json:"to"`
+}
+
+// ProjectSettings are the defaults and rules of a project
+type ProjectSettings struct {
+	// DefaultPriority is given to tasks created in the project without a priority
+	DefaultPriority Priority `json:"default_priority"`
+	// Transitions narrows the workflow for the tasks of the project: when set,
+	// a status change must be allowed both by the workflow and by this list.
+	// Without transitions the workflow applies unchanged.
+	Transitions []StatusTransition `json:"transitions,omitempty"`
+}
+
+// validate checks the settings, defaulting the priority to Medium
+func (s *ProjectSettings) validate() error {
+	if s.DefaultPriority == 0 {
+		s.DefaultPriority = Medium
+	}
+	if s.DefaultPriority < Low || s.DefaultPriority > Urgent {
+		return fmt.Errorf("invalid project settings: default priority %d must be between %d and %d", s.DefaultPriority, Low, Urgent)
+	}
+
+	for _, transition := range s.Transitions {
+		if transition.From < 0 || transition.To < 0 {
+			return fmt.Errorf("invalid project settings: transition from %d to %d uses an unknown status", transition.From, transition.To)
+		}
+		if transition.From == transition.To {
+			return fmt.Errorf("invalid project settings: transition from %s to itself", transition.From)
+		}
+	}
+
+	return nil
+}
+
+// CheckTransition returns a *TransitionError if the project workflow does not
+// allow the task to move to the given status. Setting a task to the status it
+// already has is always allowed.
+func (s ProjectSettings) CheckTransition(t Task, to Status) error {
+	if len(s.Transitions) == 0 || t.Status == to {
+		return nil
+	}
+
+	for _, transition := range s.Transitions {
+		if transition.From == t.Status && transition.To == to {
+			return nil
+		}
+	}
+
+	return &TransitionError{TaskID: t.ID, From: t.Status, To: to, Reason: "transition not allowed by the project workflow"}
+}
+
+// ProjectMemberInfo is a user with access to a project
+type ProjectMemberInfo struct {
+	UserID   int         `json:"user_id"`
+	Username string      `json:"username"`
+	Role     ProjectRole `json:"role"`
+	JoinedAt time.Time   `json:"joined_at"`
+}
+
+// Project owns tasks, categories and tags of a team
+type Project struct {
+	ID          int             `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	OwnerID     *int            `json:"owner_id,omitempty"`
+	Settings    ProjectSettings `json:"settings"`
+	// Members are only loaded when a single project is read
+	Members   []ProjectMemberInfo `json:"members,omitempty"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// convertFromDatabaseProject converts a stored project. An invalid stored
+// workflow is ignored rather than failing the whole read, like recurrence rules.
+func convertFromDatabaseProject(dp *database.Project) Project {
+	project := Project{
+		ID:          dp.ID,
+		Name:        dp.Name,
+		Description: dp.Description,
+		OwnerID:     dp.OwnerID,
+		Settings:    ProjectSettings{DefaultPriority: Priority(dp.DefaultPriority)},
+		CreatedAt:   dp.CreatedAt,
+		UpdatedAt:   dp.UpdatedAt,
+	}
+
+	if dp.Workflow != "" {
+		var transitions []StatusTransition
+		if err := json.Unmarshal([]byte(dp.Workflow), &transitions); err == nil {
+			project.Settings.Transitions = transitions
+		}
+	}
+
+	return project
+}
+
+// encodeWorkflow stores the transitions of the settings as JSON, or as an
+// empty string when the project uses the workflow unchanged
+func encodeWorkflow(settings ProjectSettings) (string, error) {
+	if len(settings.Transitions) == 0 {
+		return "", nil
+	}
+
+	workflow, err := json.Marshal(settings.Transitions)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode project workflow: %w", err)
+	}
+	return string(workflow), nil
+}
+
+// cleanProjectName trims a project name and checks its length
+func cleanProjectName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New("invalid project: name cannot be empty")
+	}
+	if len([]rune(name)) > maxProjectNameLength {
+		return "", fmt.Errorf("invalid project: name cannot be longer than %d characters", maxProjectNameLength)
+	}
+	return name, nil
+}
+
+// projectRole returns the role of the user in a project, or an empty role when
+// the user is not a member
+func projectRole(repository database.Repository, userID, projectID int) (ProjectRole, error) {
+	members, err := repository.GetProjectMembers(projectID)
+	if err != nil {
+		return "", err
+	}
+
+	for _, member := range members {
+		if member.UserID == userID {
+			return ProjectRole(member.Role), nil
+		}
+	}
+
+	return "", nil
+}
+
+// ProjectManager manages projects and their members. Projects are only
+// visible to their members, and only owners can change them.
+type ProjectManager struct {
+	repository database.Repository
+}
+
+// NewProjectManager creates a new project manager
+func NewProjectManager(repository database.Repository) *ProjectManager {
+	return &ProjectManager{
+		repository: repository,
+	}
+}
+
+// WithContext returns a copy of the project manager whose queries run with ctx
+func (pm *ProjectManager) WithContext(ctx context.Context) *ProjectManager {
+	if pm.repository == nil {
+		return pm
+	}
+
+	bound := *pm
+	bound.repository = pm.repository.WithContext(ctx)
+	return &bound
+}
+
+// MemberRole returns the role of the user in a project. Projects the user is
+// not a member of are reported as not found.
+func (pm *ProjectManager) MemberRole(userID, projectID int) (ProjectRole, error) {
+	if _, err := pm.repository.GetProject(projectID); err != nil {
+		return "", err
+	}
+
+	role, err := projectRole(pm.repository, userID, projectID)
+	if err != nil {
+		return "", err
+	}
+	if role == "" {
+		return "", fmt.Errorf("project with ID %d not found", projectID)
+	}
+
+	return role, nil
+}
+
+// CheckMember returns an error unless the user is a member of the project
+func (pm *ProjectManager) CheckMember(userID, projectID int) error {
+	_, err := pm.MemberRole(userID, projectID)
+	return err
+}
+
+// checkOwner returns an error unless the user is an owner of the project
+func (pm *ProjectManager) checkOwner(userID, projectID int, action string) error {
+	role, err := pm.MemberRole(userID, projectID)
+	if err != nil {
+		return err
+	}
+	if role != ProjectOwner {
+		return fmt.Errorf("access denied: only a project owner can %s", action)
+	}
+	return nil
+}
+
+// CreateProject creates a project owned by the user
+func (pm *ProjectManager) CreateProject(userID int, name, description string, settings ProjectSettings) (*Project, error) {
+	name, err := cleanProjectName(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := settings.validate(); err != nil {
+		return nil, err
+	}
+
+	workflow, err := encodeWorkflow(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	dbProject := &database.Project{
+		Name:            name,
+		Description:     description,
+		OwnerID:         &userID,
+		DefaultPriority: int(settings.DefaultPriority),
+		Workflow:        workflow,
+	}
+	if err := pm.repository.CreateProject(dbProject); err != nil {
+		return nil, err
+	}
+
+	return pm.GetProject(userID, dbProject.ID)
+}
+
+// GetProject retrieves a project of the user with its members
+func (pm *ProjectManager) GetProject(userID, projectID int) (*Project, error) {
+	if err := pm.CheckMember(userID, projectID); err != nil {
+		return nil, err
+	}
+
+	dbProject, err := pm.repository.GetProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := pm.repository.GetProjectMembers(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	project := convertFromDatabaseProject(dbProject)
+	project.Members = convertFromDatabaseProjectMembers(members)
+	return &project, nil
+}
+
+// GetUserProjects returns the projects the user is a member of, by name
+func (pm *ProjectManager) GetUserProjects(userID int) ([]Project, error) {
+	dbProjects, err := pm.repository.GetProjectsByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make([]Project, len(dbProjects))
+	for i := range dbProjects {
+		projects[i] = convertFromDatabaseProject(&dbProjects[i])
+	}
+	return projects, nil
+}
+
+// UpdateProject replaces the name, description and settings of a project
+func (pm *ProjectManager) UpdateProject(userID, projectID int, name, description string, settings ProjectSettings) (*Project, error) {
+	if err := pm.checkOwner(userID, projectID, "change the project"); err != nil {
+		return nil, err
+	}
+
+	name, err := cleanProjectName(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := settings.validate(); err != nil {
+		return nil, err
+	}
+
+	workflow, err := encodeWorkflow(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	dbProject := &database.Project{
+		ID:              projectID,
+		Name:            name,
+		Description:     description,
+		DefaultPriority: int(settings.DefaultPriority),
+		Workflow:        workflow,
+	}
+	if err := pm.repository.UpdateProject(dbProject); err != nil {
+		return nil, err
+	}
+
+	return pm.GetProject(userID, projectID)
+}
+
+// DeleteProject deletes a project with its categories and tags. Projects with
+// tasks outside the trash cannot be deleted.
+func (pm *ProjectManager) DeleteProject(userID, projectID int) error {
+	if err := pm.checkOwner(userID, projectID, "delete the project"); err != nil {
+		return err
+	}
+
+	return pm.repository.DeleteProject(projectID)
+}
+
+// GetMembers returns the members of a project of the user
+func (pm *ProjectManager) GetMembers(userID, projectID int) ([]ProjectMemberInfo, error) {
+	if err := pm.CheckMember(userID, projectID); err != nil {
+		return nil, err
+	}
+
+	members, err := pm.repository.GetProjectMembers(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertFromDatabaseProjectMembers(members), nil
+}
+
+// SetMember adds an active user to a project, or changes the role of a
+// member, and returns the members
+func (pm *ProjectManager) SetMember(userID, projectID int, username string, role ProjectRole) ([]ProjectMemberInfo, error) {
+	if err := pm.checkOwner(userID, projectID, "manage members"); err != nil {
+		return nil, err
+	}
+
+	role, err := ParseProjectRole(string(role))
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := pm.repository.GetUserByUsername(username)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project member %s: no such user", username)
+	}
+	if !user.IsActive {
+		return nil, fmt.Errorf("invalid project member %s: user is inactive", username)
+	}
+
+	if role != ProjectOwner {
+		if err := pm.keepAnOwner(projectID, user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	member := &database.ProjectMember{ProjectID: projectID, UserID: user.ID, Role: string(role)}
+	if err := pm.repository.SetProjectMember(member); err != nil {
+		return nil, err
+	}
+
+	return pm.GetMembers(userID, projectID)
+}
+
+// RemoveMember removes a user from a project. Owners can remove anyone and
+// every member can leave.
+func (pm *ProjectManager) RemoveMember(userID, projectID, memberID int) error {
+	if memberID != userID {
+		if err := pm.checkOwner(userID, projectID, "manage members"); err != nil {
+			return err
+		}
+	} else if err := pm.CheckMember(userID, projectID); err != nil {
+		return err
+	}
+
+	if err := pm.keepAnOwner(projectID, memberID); err != nil {
+		return err
+	}
+
+	return pm.repository.RemoveProjectMember(projectID, memberID)
+}
+
+// keepAnOwner returns an error when the user is the last owner of a project,
+// so that every project keeps someone who can manage it
+func (pm *ProjectManager) keepAnOwner(projectID, userID int) error {
+	members, err := pm.repository.GetProjectMembers(projectID)
+	if err != nil {
+		return err
+	}
+
+	owners := 0
+	isOwner := false
+	for _, member := range members {
+		if ProjectRole(member.Role) == ProjectOwner {
+			owners++
+			isOwner = isOwner || member.UserID == userID
+		}
+	}
+
+	if isOwner && owners == 1 {
+		return errors.New("invalid project member: a project needs at least one owner")
+	}
+	return nil
+}
+
+// GetProjectTasks returns the tasks of a project of the user, newest first.
+// Archived tasks are left out unless includeArchived is set.
+func (pm *ProjectManager) GetProjectTasks(userID, projectID int, includeArchived bool) ([]Task, error) {
+	if err := pm.CheckMember(userID, projectID); err != nil {
+		return nil, err
+	}
+
+	dbTasks, err := pm.repository.GetTasksByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []Task
+	for i := range dbTasks {
+		if dbTasks[i].IsArchived && !includeArchived {
+			continue
+		}
+		tasks = append(tasks, convertFromDatabaseTask(&dbTasks[i]))
+	}
+	return tasks, nil
+}
+
+func convertFromDatabaseProjectMembers(dbMembers []database.ProjectMember) []ProjectMemberInfo {
+	members := make([]ProjectMemberInfo, len(dbMembers))
+	for i, dbMember := range dbMembers {
+		members[i] = ProjectMemberInfo{
+			UserID:   dbMember.UserID,
+			Username: dbMember.Username,
+			Role:     ProjectRole(dbMember.Role),
+			JoinedAt: dbMember.CreatedAt,
+		}
+	}
+	return members
+}
@@ -0,0 +1,171 @@
+package task
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestProjects(t *testing.T) {
+	repository := setupTestRepository(t)
+	um := NewUserManager(repository)
+	pm := NewProjectManager(repository)
+	cm := NewCategoryManager(repository)
+
+	owner, err := um.RegisterUser("owner", "owner@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	dev, err := um.RegisterUser("dev", "dev@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	outsider, err := um.RegisterUser("outsider", "outsider@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+
+	invalid := []ProjectSettings{
+		{DefaultPriority: 9},
+		{Transitions: []StatusTransition{{From: Pending, To: Pending}}},
+	}
+	for _, settings := range invalid {
+		if _, err := pm.CreateProject(owner.ID, "Invalid", "", settings); err == nil {
+			t.Errorf("Expected error for settings %+v", settings)
+		}
+	}
+	if _, err := pm.CreateProject(owner.ID, "  ", "", ProjectSettings{}); err == nil {
+		t.Error("Expected error for an empty project name")
+	}
+
+	// Tasks of the project may only start and finish, never be cancelled
+	web, err := pm.CreateProject(owner.ID, "Web", "", ProjectSettings{
+		DefaultPriority: High,
+		Transitions: []StatusTransition{
+			{From: Pending, To: InProgress},
+			{From: InProgress, To: Completed},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	if len(web.Members) != 1 || web.Members[0].UserID != owner.ID || web.Members[0].Role != ProjectOwner {
+		t.Errorf("Expected the creator to be the only owner, got %+v", web.Members)
+	}
+	mobile, err := pm.CreateProject(owner.ID, "Mobile", "", ProjectSettings{})
+	if err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	if mobile.Settings.DefaultPriority != Medium {
+		t.Errorf("Expected default priority %v, got %v", Medium, mobile.Settings.DefaultPriority)
+	}
+
+	// Category and tag names only have to be unique within a project
+	if _, err := cm.CreateCategory("Backend", "", ""); err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	if _, err := cm.CreateProjectCategory(web.ID, "Backend", "", ""); err != nil {
+		t.Fatalf("Failed to create project category: %v", err)
+	}
+	if _, err := cm.CreateProjectCategory(mobile.ID, "Backend", "", ""); err != nil {
+		t.Fatalf("Failed to create project category: %v", err)
+	}
+	if _, err := cm.CreateProjectCategory(web.ID, "Backend", "", ""); err == nil {
+		t.Error("Expected error for a duplicate category name within a project")
+	}
+	if _, err := cm.CreateProjectTag(web.ID, "urgent", ""); err != nil {
+		t.Fatalf("Failed to create project tag: %v", err)
+	}
+	if _, err := cm.CreateTag("urgent", ""); err != nil {
+		t.Fatalf("Failed to create tag: %v", err)
+	}
+	webCategories, err := cm.GetCategoriesByProject(&web.ID)
+	if err != nil {
+		t.Fatalf("Failed to get project categories: %v", err)
+	}
+	if len(webCategories) != 1 || webCategories[0].ProjectID == nil || *webCategories[0].ProjectID != web.ID {
+		t.Errorf("Expected one category in the project, got %+v", webCategories)
+	}
+
+	// Only owners manage members, and only members see the project
+	if _, err := pm.SetMember(dev.ID, web.ID, "dev", ProjectMember); err == nil {
+		t.Error("Expected error when a non-member adds a member")
+	}
+	if _, err := pm.SetMember(owner.ID, web.ID, "nobody", ProjectMember); err == nil {
+		t.Error("Expected error when adding an unknown user")
+	}
+	if _, err := pm.SetMember(owner.ID, web.ID, "dev", ProjectMember); err != nil {
+		t.Fatalf("Failed to add member: %v", err)
+	}
+	if _, err := pm.UpdateProject(dev.ID, web.ID, "Website", "", web.Settings); err == nil {
+		t.Error("Expected error when a member updates the project")
+	}
+	if _, err := pm.GetProject(outsider.ID, web.ID); err == nil {
+		t.Error("Expected error when an outsider reads the project")
+	}
+	projects, err := pm.GetUserProjects(dev.ID)
+	if err != nil {
+		t.Fatalf("Failed to get projects: %v", err)
+	}
+	if len(projects) != 1 || projects[0].ID != web.ID {
+		t.Errorf("Expected the member to see one project, got %+v", projects)
+	}
+	if err := pm.RemoveMember(owner.ID, web.ID, owner.ID); err == nil {
+		t.Error("Expected error when the last owner leaves")
+	}
+
+	created, err := um.CreateUserTask(owner.ID, "Landing page", "", web.Settings.DefaultPriority, nil)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if _, err := um.SetUserTaskProject(outsider.ID, created.ID, &web.ID); err == nil {
+		t.Error("Expected error when a non-owner moves the task")
+	}
+	if _, err := um.SetUserTaskProject(owner.ID, created.ID, &web.ID); err != nil {
+		t.Fatalf("Failed to move task into project: %v", err)
+	}
+
+	// Members see and work on the tasks of the project
+	if _, err := um.GetVisibleTask(dev.ID, created.ID); err != nil {
+		t.Errorf("Expected the member to see the task: %v", err)
+	}
+	if _, err := um.GetVisibleTask(outsider.ID, created.ID); err == nil {
+		t.Error("Expected error when an outsider reads the task")
+	}
+	tasks, err := pm.GetProjectTasks(dev.ID, web.ID, false)
+	if err != nil {
+		t.Fatalf("Failed to get project tasks: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != created.ID {
+		t.Errorf("Expected the project task, got %+v", tasks)
+	}
+
+	// The global workflow allows cancelling, the project does not
+	var transitionErr *TransitionError
+	if err := um.UpdateUserTaskStatus(dev.ID, created.ID, Cancelled); !errors.As(err, &transitionErr) {
+		t.Errorf("Expected a transition error, got %v", err)
+	} else if transitionErr.Reason != "transition not allowed by the project workflow" {
+		t.Errorf("Expected the project workflow to reject the transition, got %v", err)
+	}
+	if err := um.UpdateUserTaskStatus(dev.ID, created.ID, InProgress); err != nil {
+		t.Errorf("Failed to start task: %v", err)
+	}
+
+	if err := pm.DeleteProject(owner.ID, web.ID); err == nil {
+		t.Error("Expected error when deleting a project with tasks")
+	}
+	if _, err := um.SetUserTaskProject(owner.ID, created.ID, nil); err != nil {
+		t.Fatalf("Failed to move task out of project: %v", err)
+	}
+	if err := pm.DeleteProject(dev.ID, web.ID); err == nil {
+		t.Error("Expected error when a member deletes the project")
+	}
+	if err := pm.DeleteProject(owner.ID, web.ID); err != nil {
+		t.Fatalf("Failed to delete project: %v", err)
+	}
+	if _, err := pm.GetProject(owner.ID, web.ID); err == nil {
+		t.Error("Expected error when reading a deleted project")
+	}
+	if _, err := cm.CreateProjectCategory(mobile.ID, "Frontend", "", ""); err != nil {
+		t.Errorf("Expected other projects to keep working: %v", err)
+	}
+}
@@ -0,0 +1,258 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"learn-go-capstone/internal/database"
+	"learn-go-capstone/internal/quickadd"
+)
+
+// quickAddTask builds the task an entry describes. Entries without a
+// priority get defaultPriority.
+func quickAddTask(entry *quickadd.Entry, description string, defaultPriority Priority) (Task, error) {
+	priority := Priority(entry.Priority)
+	if priority == 0 {
+		priority = defaultPriority
+	}
+	if err := validateNewTask(entry.Title, priority); err != nil {
+		return Task{}, err
+	}
+
+	task := Task{
+		Title:       entry.Title,
+		Description: description,
+		Priority:    priority,
+		Status:      Pending,
+		DueDate:     entry.DueDate,
+		Tags:        []Tag{},
+	}
+	if entry.Recurrence != "" {
+		rule, err := ParseRecurrenceRule(entry.Recurrence)
+		if err != nil {
+			return Task{}, err
+		}
+		task.Recurrence = rule
+	}
+	return task, nil
+}
+
+// createQuickAddTask stores a task built by quickAddTask in one transaction,
+// filed under the category the entry names and tagged with its tags. The
+// category must exist in the task's project, or be global for personal tasks;
+// missing tags are created. Events are only published once the task is stored.
+func createQuickAddTask(repository database.Repository, events *EventBus, task *Task, entry *quickadd.Entry, userID, projectID *int) error {
+	dbTask := &database.DatabaseTask{
+		Title:       task.Title,
+		Description: task.Description,
+		Priority:    int(task.Priority),
+		Status:      int(Pending),
+		DueDate:     task.DueDate,
+		UserID:      userID,
+		ProjectID:   projectID,
+	}
+	if task.Recurrence != nil {
+		rule := task.Recurrence.String()
+		dbTask.RecurrenceRule = &rule
+	}
+
+	var published []Event
+	buffer := NewEventBus()
+	buffer.Subscribe(func(event Event) { published = append(published, event) })
+
+	err := repository.Transaction(func(tx database.Repository) error {
+		if err := tx.CreateTask(dbTask); err != nil {
+			return err
+		}
+		buffer.Publish(databaseTaskEvent(EventTaskCreated, dbTask))
+
+		if entry.Category != "" {
+			category, err := findQuickAddCategory(tx, entry.Category, projectID)
+			if err != nil {
+				return err
+			}
+			if err := setDatabaseTaskCategory(tx, buffer, dbTask, &category.ID); err != nil {
+				return err
+			}
+			task.Category = convertFromDatabaseCategory(category)
+		}
+
+		if len(entry.Tags) > 0 {
+			tags, err := findQuickAddTags(tx, entry.Tags, projectID)
+			if err != nil {
+				return err
+			}
+			ids := make([]int, len(tags))
+			for i := range tags {
+				ids[i] = tags[i].ID
+				task.Tags = append(task.Tags, Tag{ID: tags[i].ID, Name: tags[i].Name, Color: tags[i].Color, CreatedAt: tags[i].CreatedAt})
+			}
+			if err := setDatabaseTaskTags(tx, buffer, dbTask, ids, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	task.ID = dbTask.ID
+	task.CreatedAt = dbTask.CreatedAt
+	task.UpdatedAt = dbTask.UpdatedAt
+	task.ProjectID = projectID
+	events.Publish(published...)
+	return nil
+}
+
+// findQuickAddCategory looks up a category of a project, or a global category
+// when projectID is nil, by name ignoring case
+func findQuickAddCategory(repository database.Repository, name string, projectID *int) (*database.Category, error) {
+	categories, err := repository.GetCategoriesByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range categories {
+		if strings.EqualFold(categories[i].Name, name) {
+			return &categories[i], nil
+		}
+	}
+	return nil, fmt.Errorf("invalid category %q: no such category is available to the task", name)
+}
+
+// findQuickAddTags looks up tags of a project, or global tags when projectID
+// is nil, by name ignoring case and creates the ones that do not exist yet
+func findQuickAddTags(repository database.Repository, names []string, projectID *int) ([]database.Tag, error) {
+	existing, err := repository.GetTagsByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]database.Tag, 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, tag := range existing {
+			if strings.EqualFold(tag.Name, name) {
+				tags = append(tags, tag)
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+
+		tag := database.Tag{Name: name, ProjectID: projectID}
+		if err := repository.CreateTag(&tag); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// convertFromDatabaseCategory converts a stored category
+func convertFromDatabaseCategory(dc *database.Category) *Category {
+	return &Category{
+		ID:          dc.ID,
+		Name:        dc.Name,
+		Description: dc.Description,
+		Color:       dc.Color,
+		CreatedAt:   dc.CreatedAt,
+		UpdatedAt:   dc.UpdatedAt,
+	}
+}
+
+// QuickAddUserTask creates a task for a user from a parsed quick-add entry,
+// in a project the user is a member of when projectID is set. Entries without
+// a priority take the project's default priority, or medium for personal tasks.
+func (um *UserManager) QuickAddUserTask(userID int, entry *quickadd.Entry, description string, projectID *int) (*Task, error) {
+	defaultPriority := Medium
+	if projectID != nil {
+		dbProject, err := um.repository.GetProject(*projectID)
+		if err != nil {
+			return nil, err
+		}
+		role, err := projectRole(um.repository, userID, *projectID)
+		if err != nil {
+			return nil, err
+		}
+		if role == "" {
+			return nil, fmt.Errorf("project with ID %d not found", *projectID)
+		}
+		defaultPriority = convertFromDatabaseProject(dbProject).Settings.DefaultPriority
+	}
+
+	task, err := quickAddTask(entry, description, defaultPriority)
+	if err != nil {
+		return nil, err
+	}
+	if err := createQuickAddTask(um.repository, um.events, &task, entry, &userID, projectID); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// QuickAddContext creates a task from a parsed quick-add entry. The category
+// and tags are kept by name on the task.
+func (tm *TaskManager) QuickAddContext(ctx context.Context, entry *quickadd.Entry, description string) (*Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	task, err := quickAddTask(entry, description, Medium)
+	if err != nil {
+		return nil, err
+	}
+	setQuickAddNames(&task, entry)
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	return tm.addTaskLocked(task), nil
+}
+
+// setQuickAddNames files a memory task under the category and tags an entry names
+func setQuickAddNames(task *Task, entry *quickadd.Entry) {
+	if entry.Category != "" {
+		task.Category = &Category{Name: entry.Category}
+	}
+	for _, name := range entry.Tags {
+		task.Tags = append(task.Tags, Tag{Name: name})
+	}
+}
+
+// QuickAddContext creates a task from a parsed quick-add entry using the
+// configured storage. Stored tasks are filed under an existing global category
+// and tagged with global tags, which are created when missing.
+func (htm *HybridTaskManager) QuickAddContext(ctx context.Context, entry *quickadd.Entry, description string) (*Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	htm.mu.Lock()
+	defer htm.mu.Unlock()
+
+	if htm.storageType == MemoryStorage {
+		return htm.memoryManager.QuickAddContext(ctx, entry, description)
+	}
+
+	task, err := quickAddTask(entry, description, Medium)
+	if err != nil {
+		return nil, err
+	}
+	memoryTask := task
+	if err := createQuickAddTask(htm.repository.WithContext(ctx), htm.events, &task, entry, nil, nil); err != nil {
+		return nil, err
+	}
+
+	if htm.storageType == HybridStorage {
+		// Also add to memory for fast access
+		setQuickAddNames(&memoryTask, entry)
+		htm.memoryManager.mu.Lock()
+		htm.memoryManager.addTaskLocked(memoryTask)
+		htm.memoryManager.mu.Unlock()
+	}
+
+	return &task, nil
+}
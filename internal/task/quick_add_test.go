@@ -0,0 +1,124 @@
+package task
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"learn-go-capstone/internal/quickadd"
+)
+
+func TestQuickAddUserTask(t *testing.T) {
+	repository := setupTestRepository(t)
+	um := NewUserManager(repository)
+	cm := NewCategoryManager(repository)
+	bus := NewEventBus()
+	um.SetEventBus(bus)
+	var events recorder
+	bus.Subscribe(events.handle)
+
+	user, err := um.RegisterUser("user", "user@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	category, err := cm.CreateCategory("Home", "", "")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	finance, err := cm.CreateTag("finance", "")
+	if err != nil {
+		t.Fatalf("Failed to create tag: %v", err)
+	}
+
+	now := time.Date(2024, time.March, 13, 14, 30, 0, 0, time.UTC)
+	entry, err := quickadd.Parse("Pay rent tomorrow 5pm !urgent #Finance #bills @home every month", now)
+	if err != nil {
+		t.Fatalf("Failed to parse entry: %v", err)
+	}
+
+	created, err := um.QuickAddUserTask(user.ID, entry, "Transfer to landlord", nil)
+	if err != nil {
+		t.Fatalf("Failed to quick-add task: %v", err)
+	}
+	if created.Title != "Pay rent" || created.Priority != Urgent || created.Recurrence == nil ||
+		created.Category == nil || created.Category.ID != category.ID || len(created.Tags) != 2 || created.Tags[0].ID != finance.ID {
+		t.Errorf("Expected the entry to be applied, got %+v", created)
+	}
+	assertEventTypes(t, events.types(), EventTaskCreated, EventTaskUpdated, EventTagAttached, EventTagAttached)
+
+	dbTask, err := repository.GetTask(created.ID)
+	if err != nil {
+		t.Fatalf("Failed to load task: %v", err)
+	}
+	if dbTask.UserID == nil || *dbTask.UserID != user.ID || dbTask.CategoryID == nil || dbTask.RecurrenceRule == nil ||
+		*dbTask.RecurrenceRule != "FREQ=MONTHLY" || !dbTask.DueDate.Equal(*entry.DueDate) {
+		t.Errorf("Expected the task to be stored, got %+v", dbTask)
+	}
+	if tags, _ := repository.GetTaskTags(created.ID); len(tags) != 2 {
+		t.Errorf("Expected the missing tag to be created, got %+v", tags)
+	}
+
+	// Nothing is stored when the category does not exist
+	events.events = nil
+	entry, _ = quickadd.Parse("Call mom @family", now)
+	if _, err := um.QuickAddUserTask(user.ID, entry, "", nil); err == nil || !strings.HasPrefix(err.Error(), "invalid category") {
+		t.Errorf("Expected an unknown category to fail, got %v", err)
+	}
+	if tasks, _ := um.GetUserTasks(user.ID); len(tasks) != 1 {
+		t.Errorf("Expected the failed entry to be rolled back, got %+v", tasks)
+	}
+	assertEventTypes(t, events.types())
+
+	// Projects give their default priority and must be joined
+	pm := NewProjectManager(repository)
+	project, err := pm.CreateProject(user.ID, "Launch", "", ProjectSettings{DefaultPriority: High})
+	if err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	entry, _ = quickadd.Parse("Write announcement", now)
+	created, err = um.QuickAddUserTask(user.ID, entry, "", &project.ID)
+	if err != nil || created.Priority != High || created.ProjectID == nil || *created.ProjectID != project.ID {
+		t.Errorf("Expected the project's default priority, got %+v (%v)", created, err)
+	}
+
+	other, err := um.RegisterUser("other", "other@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	if _, err := um.QuickAddUserTask(other.ID, entry, "", &project.ID); err == nil || !strings.HasSuffix(err.Error(), "not found") {
+		t.Errorf("Expected non-members to be rejected, got %v", err)
+	}
+}
+
+func TestQuickAddContext(t *testing.T) {
+	ctx := context.Background()
+	entry, err := quickadd.Parse("Water plants every other day #garden @home", time.Now())
+	if err != nil {
+		t.Fatalf("Failed to parse entry: %v", err)
+	}
+
+	created, err := NewTaskManager().QuickAddContext(ctx, entry, "")
+	if err != nil {
+		t.Fatalf("Failed to quick-add task: %v", err)
+	}
+	if created.Priority != Medium || created.Category == nil || created.Category.Name != "home" ||
+		len(created.Tags) != 1 || created.Recurrence == nil || created.Recurrence.Interval != 2 {
+		t.Errorf("Expected the entry to be kept in memory, got %+v", created)
+	}
+
+	htm := NewHybridTaskManager(setupTestRepository(t), HybridStorage)
+	created, err = htm.QuickAddContext(ctx, entry, "")
+	if err == nil || !strings.HasPrefix(err.Error(), "invalid category") {
+		t.Errorf("Expected stored tasks to need an existing category, got %+v (%v)", created, err)
+	}
+
+	entry.Category = ""
+	created, err = htm.QuickAddContext(ctx, entry, "")
+	if err != nil || len(created.Tags) != 1 || created.Tags[0].ID == 0 {
+		t.Fatalf("Expected the task to be stored with a new tag, got %+v (%v)", created, err)
+	}
+	if loaded, err := htm.GetTaskContext(ctx, created.ID); err != nil || loaded.Recurrence == nil {
+		t.Errorf("Expected the stored task to recur, got %+v (%v)", loaded, err)
+	}
+}
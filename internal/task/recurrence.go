@@ -0,0 +1,421 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"learn-go-capstone/internal/database"
+)
+
+// Frequency represents how often a recurring task repeats
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// RecurrenceRule describes an RRULE-style (RFC 5545) repetition schedule.
+// Only the subset needed for chores is supported: FREQ, INTERVAL, BYDAY,
+// BYMONTHDAY, UNTIL and COUNT.
+type RecurrenceRule struct {
+	Frequency Frequency      `json:"frequency"`
+	Interval  int            `json:"interval,omitempty"`
+	ByWeekday []time.Weekday `json:"by_weekday,omitempty"`
+	// ByMonthDay is the day of the month that monthly and yearly rules fall
+	// on. Months without that day use their last day instead.
+	ByMonthDay int        `json:"by_month_day,omitempty"`
+	Until      *time.Time `json:"until,omitempty"`
+	// Count is the number of occurrences left, including the current one.
+	// Zero means the rule repeats until Until or forever.
+	Count int `json:"count,omitempty"`
+}
+
+// untilLayout is the RFC 5545 UTC date-time format used for UNTIL
+const untilLayout = "20060102T150405Z"
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// ParseRecurrenceRule parses an RRULE string such as
+// "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=10". A leading "RRULE:" is allowed.
+func ParseRecurrenceRule(value string) (*RecurrenceRule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return nil, errors.New("recurrence rule cannot be empty")
+	}
+
+	rule := &RecurrenceRule{Interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid recurrence rule part %q", part)
+		}
+		key, val := strings.ToUpper(strings.TrimSpace(kv[0])), strings.TrimSpace(kv[1])
+
+		switch key {
+		case "FREQ":
+			rule.Frequency = Frequency(strings.ToUpper(val))
+
+		case "INTERVAL":
+			interval, err := strconv.Atoi(val)
+			if err != nil {
+				return nil, fmt.Errorf("invalid INTERVAL %q", val)
+			}
+			rule.Interval = interval
+
+		case "BYDAY":
+			for _, code := range strings.Split(val, ",") {
+				weekday, ok := weekdayCodes[strings.ToUpper(strings.TrimSpace(code))]
+				if !ok {
+					return nil, fmt.Errorf("invalid BYDAY value %q", code)
+				}
+				rule.ByWeekday = append(rule.ByWeekday, weekday)
+			}
+
+		case "BYMONTHDAY":
+			day, err := strconv.Atoi(val)
+			if err != nil {
+				return nil, fmt.Errorf("invalid BYMONTHDAY %q", val)
+			}
+			rule.ByMonthDay = day
+
+		case "UNTIL":
+			until, err := parseUntil(val)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = &until
+
+		case "COUNT":
+			count, err := strconv.Atoi(val)
+			if err != nil {
+				return nil, fmt.Errorf("invalid COUNT %q", val)
+			}
+			rule.Count = count
+
+		default:
+			return nil, fmt.Errorf("unsupported recurrence rule part %q", key)
+		}
+	}
+
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// parseUntil accepts the RFC 5545 date-time and date forms, and RFC 3339
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{untilLayout, "20060102", time.RFC3339} {
+		if until, err := time.Parse(layout, value); err == nil {
+			return until, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid UNTIL %q", value)
+}
+
+// Validate checks that the rule is complete and supported
+func (r RecurrenceRule) Validate() error {
+	switch r.Frequency {
+	case Daily, Weekly, Monthly, Yearly:
+	case "":
+		return errors.New("recurrence rule requires a frequency")
+	default:
+		return fmt.Errorf("invalid frequency %q", r.Frequency)
+	}
+
+	if r.Interval < 0 {
+		return errors.New("recurrence interval cannot be negative")
+	}
+
+	if r.Count < 0 {
+		return errors.New("recurrence count cannot be negative")
+	}
+
+	if len(r.ByWeekday) > 0 && r.Frequency != Daily && r.Frequency != Weekly {
+		return errors.New("BYDAY is only supported for DAILY and WEEKLY rules")
+	}
+
+	for _, weekday := range r.ByWeekday {
+		if weekday < time.Sunday || weekday > time.Saturday {
+			return fmt.Errorf("invalid weekday %d", weekday)
+		}
+	}
+
+	if r.ByMonthDay != 0 && r.Frequency != Monthly && r.Frequency != Yearly {
+		return errors.New("BYMONTHDAY is only supported for MONTHLY and YEARLY rules")
+	}
+
+	if r.ByMonthDay < 0 || r.ByMonthDay > 31 {
+		return fmt.Errorf("invalid month day %d", r.ByMonthDay)
+	}
+
+	return nil
+}
+
+// String formats the rule as an RRULE string
+func (r RecurrenceRule) String() string {
+	parts := []string{"FREQ=" + string(r.Frequency)}
+
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+
+	if len(r.ByWeekday) > 0 {
+		codes := make([]string, len(r.ByWeekday))
+		for i, weekday := range r.ByWeekday {
+			codes[i] = weekdayNames[weekday]
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+
+	if r.ByMonthDay > 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.ByMonthDay))
+	}
+
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilLayout))
+	}
+
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// Next returns the first occurrence after from. It returns false when the
+// rule is exhausted, either because Count is used up or Until has passed.
+func (r RecurrenceRule) Next(from time.Time) (time.Time, bool) {
+	if r.Count == 1 {
+		return time.Time{}, false
+	}
+
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	var next time.Time
+	switch r.Frequency {
+	case Daily:
+		next = from.AddDate(0, 0, interval)
+		for i := 0; len(r.ByWeekday) > 0 && !r.hasWeekday(next.Weekday()); i++ {
+			// Every weekday reachable with this interval has been tried
+			if i >= 7 {
+				return time.Time{}, false
+			}
+			next = next.AddDate(0, 0, interval)
+		}
+
+	case Weekly:
+		if len(r.ByWeekday) == 0 {
+			next = from.AddDate(0, 0, 7*interval)
+			break
+		}
+		// Walk forward day by day, only accepting days in weeks that are a
+		// multiple of interval away from the current week (weeks start Monday)
+		for days := 1; ; days++ {
+			candidate := from.AddDate(0, 0, days)
+			weeks := weeksBetween(from, candidate)
+			if weeks%interval == 0 && r.hasWeekday(candidate.Weekday()) {
+				next = candidate
+				break
+			}
+		}
+
+	case Monthly:
+		next = addMonthsClamped(from, interval, r.monthDay(from))
+
+	case Yearly:
+		next = addMonthsClamped(from, 12*interval, r.monthDay(from))
+
+	default:
+		return time.Time{}, false
+	}
+
+	if r.Until != nil && next.After(*r.Until) {
+		return time.Time{}, false
+	}
+
+	return next, true
+}
+
+// advance returns the rule carried by the next occurrence
+func (r RecurrenceRule) advance() *RecurrenceRule {
+	next := r
+	if next.Count > 0 {
+		next.Count--
+	}
+	if len(r.ByWeekday) > 0 {
+		next.ByWeekday = append([]time.Weekday(nil), r.ByWeekday...)
+	}
+	return &next
+}
+
+// monthDay returns the day of the month that the rule falls on, which is the
+// day of from unless the rule names one
+func (r RecurrenceRule) monthDay(from time.Time) int {
+	if r.ByMonthDay > 0 {
+		return r.ByMonthDay
+	}
+	return from.Day()
+}
+
+func (r RecurrenceRule) hasWeekday(weekday time.Weekday) bool {
+	for _, w := range r.ByWeekday {
+		if w == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// weeksBetween returns the number of Monday-based calendar weeks from a to b
+func weeksBetween(a, b time.Time) int {
+	startOfWeek := func(t time.Time) time.Time {
+		offset := (int(t.Weekday()) + 6) % 7
+		y, m, d := t.AddDate(0, 0, -offset).Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	return int(startOfWeek(b).Sub(startOfWeek(a)).Hours() / (24 * 7))
+}
+
+// addMonthsClamped adds months to t and moves it to the given day, clamping
+// the day to the end of the target month instead of overflowing
+// (Jan 31 + 1 month = Feb 28/29)
+func addMonthsClamped(t time.Time, months, d int) time.Time {
+	y, m, _ := t.Date()
+	firstOfTarget := time.Date(y, m+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := firstOfTarget.AddDate(0, 1, -1).Day()
+	if d > lastDay {
+		d = lastDay
+	}
+	return firstOfTarget.AddDate(0, 0, d-1)
+}
+
+// nextOccurrence builds the task that follows a completed recurring task.
+// It returns nil when the task is not recurring or the rule is exhausted.
+func nextOccurrence(t Task, completedAt time.Time) *Task {
+	if t.Recurrence == nil {
+		return nil
+	}
+
+	from := completedAt
+	if t.DueDate != nil {
+		from = *t.DueDate
+	}
+
+	dueDate, ok := t.Recurrence.Next(from)
+	if !ok {
+		return nil
+	}
+
+	// Later occurrences keep the day the series started on, even after a
+	// shorter month moved one of them to its last day
+	rule := t.Recurrence.advance()
+	if rule.Frequency == Monthly || rule.Frequency == Yearly {
+		rule.ByMonthDay = t.Recurrence.monthDay(from)
+	}
+
+	next := Task{
+		Title:       t.Title,
+		Description: t.Description,
+		Priority:    t.Priority,
+		Status:      Pending,
+		DueDate:     &dueDate,
+		Category:    t.Category,
+		Tags:        append([]Tag(nil), t.Tags...),
+		Recurrence:  rule,
+	}
+	return &next
+}
+
+// createNextDatabaseOccurrence creates the follow-up of a completed recurring
+// database task, copying its owner, category and tags in one transaction
+func createNextDatabaseOccurrence(repository database.Repository, completed *database.DatabaseTask) (*database.DatabaseTask, error) {
+	next := nextOccurrence(convertFromDatabaseTask(completed), time.Now())
+	if next == nil {
+		return nil, nil
+	}
+
+	nextTask := convertToDatabaseTask(*next)
+	nextTask.UserID = completed.UserID
+	nextTask.CategoryID = completed.CategoryID
+
+	err := repository.Transaction(func(tx database.Repository) error {
+		if err := tx.CreateTask(nextTask); err != nil {
+			return fmt.Errorf("failed to create next occurrence: %w", err)
+		}
+
+		tags, err := tx.GetTaskTags(completed.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get tags for next occurrence: %w", err)
+		}
+		for _, tag := range tags {
+			if err := tx.AddTagToTask(nextTask.ID, tag.ID); err != nil {
+				return fmt.Errorf("failed to copy tag to next occurrence: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nextTask, nil
+}
+
+// setDatabaseTaskStatus saves a status change of a database task and, when a
+// recurring task becomes Completed, creates and returns its next occurrence.
+// The rule moves on to the next occurrence, so completing the task again
+// after reopening it does not create a second one. The status change and the
+// next occurrence are stored in one transaction, so a failure leaves neither
+// behind.
+func setDatabaseTaskStatus(repository database.Repository, dbTask *database.DatabaseTask, status Status) (*database.DatabaseTask, error) {
+	oldStatus := dbTask.Status
+	oldRule := dbTask.RecurrenceRule
+	wasCompleted := Status(oldStatus) == Completed
+
+	var next *database.DatabaseTask
+	dbTask.Status = int(status)
+	err := repository.Transaction(func(tx database.Repository) error {
+		if status == Completed && !wasCompleted {
+			var err error
+			if next, err = createNextDatabaseOccurrence(tx, dbTask); err != nil {
+				return err
+			}
+			if next != nil {
+				dbTask.RecurrenceRule = nil
+			}
+		}
+		return tx.UpdateTask(dbTask)
+	})
+	if err != nil {
+		dbTask.Status = oldStatus
+		dbTask.RecurrenceRule = oldRule
+		return nil, err
+	}
+
+	return next, nil
+}
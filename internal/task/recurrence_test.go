@@ -0,0 +1,318 @@
+package task
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"learn-go-capstone/internal/database"
+)
+
+func TestParseRecurrenceRule(t *testing.T) {
+	rule, err := ParseRecurrenceRule("RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;UNTIL=20301231T000000Z;COUNT=4")
+	if err != nil {
+		t.Fatalf("Failed to parse rule: %v", err)
+	}
+
+	if rule.Frequency != Weekly {
+		t.Errorf("Expected WEEKLY, got %s", rule.Frequency)
+	}
+	if rule.Interval != 2 {
+		t.Errorf("Expected interval 2, got %d", rule.Interval)
+	}
+	if len(rule.ByWeekday) != 2 || rule.ByWeekday[0] != time.Monday || rule.ByWeekday[1] != time.Thursday {
+		t.Errorf("Expected BYDAY MO,TH, got %v", rule.ByWeekday)
+	}
+	if rule.Until == nil || rule.Until.Year() != 2030 {
+		t.Errorf("Expected UNTIL in 2030, got %v", rule.Until)
+	}
+	if rule.Count != 4 {
+		t.Errorf("Expected count 4, got %d", rule.Count)
+	}
+
+	expected := "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;UNTIL=20301231T000000Z;COUNT=4"
+	if rule.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, rule.String())
+	}
+
+	invalid := []string{"", "INTERVAL=2", "FREQ=HOURLY", "FREQ=DAILY;BYDAY=XX", "FREQ=MONTHLY;BYDAY=MO", "FREQ=DAILY;COUNT=-1", "FREQ=WEEKLY;BYMONTHDAY=3", "FREQ=MONTHLY;BYMONTHDAY=32"}
+	for _, value := range invalid {
+		if _, err := ParseRecurrenceRule(value); err == nil {
+			t.Errorf("Expected error parsing %q", value)
+		}
+	}
+}
+
+func TestRecurrenceRuleNext(t *testing.T) {
+	// 2024-01-31 is a Wednesday
+	from := time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		rule     string
+		expected time.Time
+	}{
+		{"FREQ=DAILY", time.Date(2024, 2, 1, 9, 0, 0, 0, time.UTC)},
+		{"FREQ=DAILY;INTERVAL=3", time.Date(2024, 2, 3, 9, 0, 0, 0, time.UTC)},
+		{"FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR", time.Date(2024, 2, 1, 9, 0, 0, 0, time.UTC)},
+		{"FREQ=WEEKLY", time.Date(2024, 2, 7, 9, 0, 0, 0, time.UTC)},
+		{"FREQ=WEEKLY;BYDAY=MO,FR", time.Date(2024, 2, 2, 9, 0, 0, 0, time.UTC)},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO", time.Date(2024, 2, 12, 9, 0, 0, 0, time.UTC)},
+		{"FREQ=MONTHLY", time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC)},
+		{"FREQ=YEARLY", time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC)},
+		{"FREQ=MONTHLY;BYMONTHDAY=15", time.Date(2024, 2, 15, 9, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		rule, err := ParseRecurrenceRule(tt.rule)
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %v", tt.rule, err)
+		}
+
+		next, ok := rule.Next(from)
+		if !ok {
+			t.Errorf("%s: expected a next occurrence", tt.rule)
+			continue
+		}
+		if !next.Equal(tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.rule, tt.expected, next)
+		}
+	}
+
+	// A series that started on the 31st returns to it after a shorter month
+	rule, _ := ParseRecurrenceRule("FREQ=MONTHLY;BYMONTHDAY=31")
+	if next, ok := rule.Next(time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC)); !ok || !next.Equal(time.Date(2024, 3, 31, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the 31st of March, got %v", next)
+	}
+
+	exhausted := []string{"FREQ=DAILY;COUNT=1", "FREQ=WEEKLY;UNTIL=20240201T000000Z"}
+	for _, value := range exhausted {
+		rule, _ := ParseRecurrenceRule(value)
+		if _, ok := rule.Next(from); ok {
+			t.Errorf("%s: expected the rule to be exhausted", value)
+		}
+	}
+}
+
+func TestCompletingRecurringTaskInMemory(t *testing.T) {
+	tm := NewTaskManager()
+	dueDate := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+	created := tm.AddTask("Take out the trash", "Bins go out on Monday", Medium, &dueDate)
+
+	rule, _ := ParseRecurrenceRule("FREQ=WEEKLY;COUNT=2")
+	if _, err := tm.UpdateTask(created.ID, TaskPatch{Recurrence: rule}); err != nil {
+		t.Fatalf("Failed to set recurrence: %v", err)
+	}
+
+	if err := tm.UpdateTaskStatus(created.ID, Completed); err != nil {
+		t.Fatalf("Failed to complete task: %v", err)
+	}
+
+	tasks := tm.GetAllTasks()
+	if len(tasks) != 2 {
+		t.Fatalf("Expected the next occurrence to be created, got %d tasks", len(tasks))
+	}
+
+	next := tasks[1]
+	if next.Status != Pending {
+		t.Errorf("Expected next occurrence to be pending, got %s", next.Status)
+	}
+	if next.Title != created.Title || next.Priority != created.Priority {
+		t.Errorf("Expected next occurrence to copy title and priority, got %+v", next)
+	}
+	expectedDue := dueDate.AddDate(0, 0, 7)
+	if next.DueDate == nil || !next.DueDate.Equal(expectedDue) {
+		t.Errorf("Expected next due date %v, got %v", expectedDue, next.DueDate)
+	}
+	if next.Recurrence == nil || next.Recurrence.Count != 1 {
+		t.Errorf("Expected the remaining count to drop to 1, got %+v", next.Recurrence)
+	}
+
+	// Completing a task twice must not spawn another occurrence, even after reopening it
+	if err := tm.UpdateTaskStatus(created.ID, Completed); err != nil {
+		t.Fatalf("Failed to re-complete task: %v", err)
+	}
+	if err := tm.UpdateTaskStatus(created.ID, InProgress); err != nil {
+		t.Fatalf("Failed to reopen task: %v", err)
+	}
+	if err := tm.UpdateTaskStatus(created.ID, Completed); err != nil {
+		t.Fatalf("Failed to re-complete task: %v", err)
+	}
+	// The last occurrence ends the series
+	if err := tm.UpdateTaskStatus(next.ID, Completed); err != nil {
+		t.Fatalf("Failed to complete last occurrence: %v", err)
+	}
+	if len(tm.GetAllTasks()) != 2 {
+		t.Errorf("Expected no further occurrences, got %d tasks", len(tm.GetAllTasks()))
+	}
+}
+
+func TestMonthlyRecurrenceKeepsDayOfMonth(t *testing.T) {
+	tm := NewTaskManager()
+	dueDate := time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC)
+	created := tm.AddTask("Close the books", "", High, &dueDate)
+
+	rule, _ := ParseRecurrenceRule("FREQ=MONTHLY")
+	if _, err := tm.UpdateTask(created.ID, TaskPatch{Recurrence: rule}); err != nil {
+		t.Fatalf("Failed to set recurrence: %v", err)
+	}
+
+	expected := []time.Time{
+		time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 4, 30, 9, 0, 0, 0, time.UTC),
+	}
+	current := created.ID
+	for _, due := range expected {
+		if err := tm.UpdateTaskStatus(current, Completed); err != nil {
+			t.Fatalf("Failed to complete task: %v", err)
+		}
+		pending := tm.GetTasksByStatus(Pending)
+		if len(pending) != 1 {
+			t.Fatalf("Expected one pending occurrence, got %d", len(pending))
+		}
+		if pending[0].DueDate == nil || !pending[0].DueDate.Equal(due) {
+			t.Errorf("Expected the next occurrence on %v, got %v", due, pending[0].DueDate)
+		}
+		current = pending[0].ID
+	}
+}
+
+func TestCompletingRecurringTaskInDatabase(t *testing.T) {
+	repository := setupTestRepository(t)
+	htm := NewHybridTaskManager(repository, DatabaseStorage)
+
+	category := &database.Category{Name: "Chores", Color: "#00ff00"}
+	if err := repository.CreateCategory(category); err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	tag := &database.Tag{Name: "home", Color: "#0000ff"}
+	if err := repository.CreateTag(tag); err != nil {
+		t.Fatalf("Failed to create tag: %v", err)
+	}
+
+	dueDate := time.Date(2024, 1, 31, 18, 0, 0, 0, time.UTC)
+	rule := "FREQ=MONTHLY"
+	dbTask := &database.DatabaseTask{
+		Title:          "Pay rent",
+		Priority:       int(High),
+		DueDate:        &dueDate,
+		CategoryID:     &category.ID,
+		RecurrenceRule: &rule,
+	}
+	if err := repository.CreateTask(dbTask); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if err := repository.AddTagToTask(dbTask.ID, tag.ID); err != nil {
+		t.Fatalf("Failed to tag task: %v", err)
+	}
+
+	if err := htm.UpdateTaskStatus(dbTask.ID, Completed); err != nil {
+		t.Fatalf("Failed to complete task: %v", err)
+	}
+
+	pending, err := repository.GetTasksByStatus(int(Pending))
+	if err != nil {
+		t.Fatalf("Failed to get pending tasks: %v", err)
+	}
+	if len(pending) != 1 {
+		t.Fatalf("Expected one pending next occurrence, got %d", len(pending))
+	}
+
+	next := pending[0]
+	expectedDue := time.Date(2024, 2, 29, 18, 0, 0, 0, time.UTC)
+	if next.DueDate == nil || !next.DueDate.Equal(expectedDue) {
+		t.Errorf("Expected next due date %v, got %v", expectedDue, next.DueDate)
+	}
+	if next.CategoryID == nil || *next.CategoryID != category.ID {
+		t.Errorf("Expected category %d to be copied, got %v", category.ID, next.CategoryID)
+	}
+	if next.RecurrenceRule == nil || *next.RecurrenceRule != "FREQ=MONTHLY;BYMONTHDAY=31" {
+		t.Errorf("Expected the recurrence rule to keep the day of the month, got %v", next.RecurrenceRule)
+	}
+
+	tags, err := repository.GetTaskTags(next.ID)
+	if err != nil {
+		t.Fatalf("Failed to get tags: %v", err)
+	}
+	if len(tags) != 1 || tags[0].ID != tag.ID {
+		t.Errorf("Expected tag %d to be copied, got %v", tag.ID, tags)
+	}
+
+	// The next occurrence took over the rule, so reopening and completing
+	// the task again does not create a second one
+	if err := htm.UpdateTaskStatus(dbTask.ID, InProgress); err != nil {
+		t.Fatalf("Failed to reopen task: %v", err)
+	}
+	if err := htm.UpdateTaskStatus(dbTask.ID, Completed); err != nil {
+		t.Fatalf("Failed to re-complete task: %v", err)
+	}
+	all, err := repository.GetAllTasks()
+	if err != nil {
+		t.Fatalf("Failed to get tasks: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("Expected no further occurrences, got %d tasks", len(all))
+	}
+}
+
+// failingTagRepository fails to tag tasks, inside transactions as well
+type failingTagRepository struct {
+	database.Repository
+}
+
+func (r failingTagRepository) AddTagToTask(taskID, tagID int) error {
+	return errors.New("disk I/O error")
+}
+
+func (r failingTagRepository) Transaction(fn func(repository database.Repository) error) error {
+	return r.Repository.Transaction(func(tx database.Repository) error {
+		return fn(failingTagRepository{tx})
+	})
+}
+
+func TestCompletingRecurringTaskInDatabaseRollsBack(t *testing.T) {
+	repository := setupTestRepository(t)
+
+	tag := &database.Tag{Name: "home", Color: "#0000ff"}
+	if err := repository.CreateTag(tag); err != nil {
+		t.Fatalf("Failed to create tag: %v", err)
+	}
+	dueDate := time.Date(2024, 1, 31, 18, 0, 0, 0, time.UTC)
+	rule := "FREQ=WEEKLY"
+	dbTask := &database.DatabaseTask{
+		Title:          "Water plants",
+		Priority:       int(Low),
+		DueDate:        &dueDate,
+		RecurrenceRule: &rule,
+	}
+	if err := repository.CreateTask(dbTask); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if err := repository.AddTagToTask(dbTask.ID, tag.ID); err != nil {
+		t.Fatalf("Failed to tag task: %v", err)
+	}
+
+	// Copying the tags fails, so neither the completion nor the next
+	// occurrence may be stored
+	if _, err := setDatabaseTaskStatus(failingTagRepository{repository}, dbTask, Completed); err == nil {
+		t.Fatal("Expected completing the task to fail")
+	}
+	if Status(dbTask.Status) != Pending {
+		t.Errorf("Expected the task to keep its status, got %s", Status(dbTask.Status))
+	}
+
+	stored, err := repository.GetTask(dbTask.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if Status(stored.Status) != Pending {
+		t.Errorf("Expected the completion to be rolled back, got %s", Status(stored.Status))
+	}
+	pending, err := repository.GetTasksByStatus(int(Pending))
+	if err != nil {
+		t.Fatalf("Failed to get pending tasks: %v", err)
+	}
+	if len(pending) != 1 {
+		t.Errorf("Expected no next occurrence, got %d pending tasks", len(pending))
+	}
+}
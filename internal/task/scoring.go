@@ -0,0 +1,362 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"learn-go-capstone/internal/database"
+)
+
+// Names of the factors of the default next-up ranking
+const (
+	FactorPriority  = "priority"
+	FactorDueDate   = "due_date"
+	FactorBlocking  = "blocking"
+	FactorEstimate  = "estimate"
+	FactorStaleness = "staleness"
+)
+
+// dueDateHorizon is how far ahead a due date starts to make a task urgent
+const dueDateHorizon = 14 * 24 * time.Hour
+
+// staleAfter is how long a task must go untouched to count as fully stale
+const staleAfter = 30 * 24 * time.Hour
+
+// ScoreInput is what a factor knows about a task beyond the task itself
+type ScoreInput struct {
+	Now time.Time
+	// Blocking is the number of unfinished tasks that depend on the task
+	Blocking int
+}
+
+// ScoreFactor is one aspect of a task that the next-up ranking weighs.
+// Score returns a value between 0 and 1; the higher, the sooner the task
+// should be done.
+type ScoreFactor interface {
+	Name() string
+	Score(t Task, input ScoreInput) float64
+}
+
+type scoreFactor struct {
+	name  string
+	score func(Task, ScoreInput) float64
+}
+
+func (f scoreFactor) Name() string { return f.name }
+
+func (f scoreFactor) Score(t Task, input ScoreInput) float64 { return f.score(t, input) }
+
+// NewScoreFactor creates a factor from a scoring function
+func NewScoreFactor(name string, score func(t Task, input ScoreInput) float64) ScoreFactor {
+	return scoreFactor{name: name, score: score}
+}
+
+// DefaultScoreFactors returns the factors of the default ranking: priority,
+// due-date urgency, the number of tasks blocked downstream, the estimate
+// (quick tasks first) and staleness
+func DefaultScoreFactors() []ScoreFactor {
+	return []ScoreFactor{
+		NewScoreFactor(FactorPriority, func(t Task, _ ScoreInput) float64 {
+			return clampScore(float64(t.Priority-Low) / float64(Urgent-Low))
+		}),
+		NewScoreFactor(FactorDueDate, func(t Task, input ScoreInput) float64 {
+			if t.DueDate == nil {
+				return 0
+			}
+			// Overdue tasks score 1, tasks due beyond the horizon 0
+			return clampScore(1 - float64(t.DueDate.Sub(input.Now))/float64(dueDateHorizon))
+		}),
+		NewScoreFactor(FactorBlocking, func(_ Task, input ScoreInput) float64 {
+			return 1 - 1/float64(1+input.Blocking)
+		}),
+		NewScoreFactor(FactorEstimate, func(t Task, _ ScoreInput) float64 {
+			// Tasks without an estimate count as taking an hour
+			minutes := 60.0
+			if t.EstimateMinutes != nil && *t.EstimateMinutes > 0 {
+				minutes = float64(*t.EstimateMinutes)
+			}
+			return 1 / (1 + minutes/60)
+		}),
+		NewScoreFactor(FactorStaleness, func(t Task, input ScoreInput) float64 {
+			return clampScore(float64(input.Now.Sub(t.UpdatedAt)) / float64(staleAfter))
+		}),
+	}
+}
+
+func clampScore(score float64) float64 {
+	return math.Max(0, math.Min(1, score))
+}
+
+// ScoringWeights are the weights of the factors of a ranking, by factor name.
+// Factors without a weight do not count.
+type ScoringWeights map[string]float64
+
+// DefaultScoringWeights returns the weights used until a user sets their own
+func DefaultScoringWeights() ScoringWeights {
+	return ScoringWeights{
+		FactorPriority:  3,
+		FactorDueDate:   3,
+		FactorBlocking:  2,
+		FactorEstimate:  1,
+		FactorStaleness: 1,
+	}
+}
+
+// ScoredTask is a task with its next-up score between 0 and 1. Factors holds
+// the weighted share of each factor in Score.
+type ScoredTask struct {
+	Task    Task               `json:"task"`
+	Score   float64            `json:"score"`
+	Factors map[string]float64 `json:"factors"`
+}
+
+// Scorer ranks tasks by the weighted average of its factors
+type Scorer struct {
+	factors []ScoreFactor
+}
+
+// NewScorer creates a scorer with the given factors, or with the default
+// factors when none are given
+func NewScorer(factors ...ScoreFactor) *Scorer {
+	if len(factors) == 0 {
+		factors = DefaultScoreFactors()
+	}
+	return &Scorer{factors: factors}
+}
+
+// Factors returns the names of the factors of the scorer
+func (s *Scorer) Factors() []string {
+	names := make([]string, len(s.factors))
+	for i, factor := range s.factors {
+		names[i] = factor.Name()
+	}
+	return names
+}
+
+// Validate checks that weights only name factors of the scorer, that no
+// weight is negative and that at least one weight is positive
+func (s *Scorer) Validate(weights ScoringWeights) error {
+	known := make(map[string]bool, len(s.factors))
+	for _, factor := range s.factors {
+		known[factor.Name()] = true
+	}
+
+	total := 0.0
+	for name, weight := range weights {
+		if !known[name] {
+			return fmt.Errorf("invalid scoring weights: unknown factor %q", name)
+		}
+		if weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+			return fmt.Errorf("invalid scoring weights: weight of %s must be a non-negative number", name)
+		}
+		total += weight
+	}
+	if total == 0 {
+		return fmt.Errorf("invalid scoring weights: at least one weight must be positive")
+	}
+
+	return nil
+}
+
+// Rank scores the tasks and returns them best first. Ties go to the task due
+// first, then to the older task. blocking counts the unfinished dependents of
+// each task by ID.
+func (s *Scorer) Rank(tasks []Task, weights ScoringWeights, now time.Time, blocking map[int]int) []ScoredTask {
+	total := 0.0
+	for _, factor := range s.factors {
+		total += weights[factor.Name()]
+	}
+
+	ranked := make([]ScoredTask, 0, len(tasks))
+	for _, t := range tasks {
+		input := ScoreInput{Now: now, Blocking: blocking[t.ID]}
+		scored := ScoredTask{Task: t, Factors: make(map[string]float64, len(s.factors))}
+		for _, factor := range s.factors {
+			weight := weights[factor.Name()]
+			if weight == 0 || total == 0 {
+				continue
+			}
+			share := weight * clampScore(factor.Score(t, input)) / total
+			scored.Factors[factor.Name()] = share
+			scored.Score += share
+		}
+		ranked = append(ranked, scored)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if (a.Task.DueDate == nil) != (b.Task.DueDate == nil) {
+			return a.Task.DueDate != nil
+		}
+		if a.Task.DueDate != nil && !a.Task.DueDate.Equal(*b.Task.DueDate) {
+			return a.Task.DueDate.Before(*b.Task.DueDate)
+		}
+		return a.Task.ID < b.Task.ID
+	})
+
+	return ranked
+}
+
+// defaultScorer ranks tasks where no scorer has been set
+var defaultScorer = NewScorer()
+
+// isOpen reports whether a task still needs doing
+func isOpen(t Task) bool {
+	return t.Status != Completed && t.Status != Cancelled
+}
+
+// firstScored returns the first limit tasks, or all of them when limit is not positive
+func firstScored(ranked []ScoredTask, limit int) []ScoredTask {
+	if limit > 0 && len(ranked) > limit {
+		return ranked[:limit]
+	}
+	return ranked
+}
+
+// rankStoredTasks ranks the open tasks that are not blocked by unfinished
+// dependencies, counting the unfinished dependents of each
+func rankStoredTasks(repository database.Repository, scorer *Scorer, tasks []Task, weights ScoringWeights, limit int) ([]ScoredTask, error) {
+	dm := NewDependencyManager(repository)
+
+	var candidates []Task
+	blocking := make(map[int]int)
+	for _, t := range tasks {
+		if !isOpen(t) {
+			continue
+		}
+		ready, err := dm.CanCompleteTask(t.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !ready {
+			continue
+		}
+
+		dependents, err := dm.GetTasksThatDependOn(t.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, dependent := range dependents {
+			if isOpen(dependent) {
+				blocking[t.ID]++
+			}
+		}
+		candidates = append(candidates, t)
+	}
+
+	return firstScored(scorer.Rank(candidates, weights, time.Now(), blocking), limit), nil
+}
+
+// NextTasks ranks the open tasks with the default weights and returns the
+// first limit of them, or all when limit is not positive. Memory storage
+// keeps no dependencies, so no task counts as blocking another.
+func (tm *TaskManager) NextTasks(limit int) []ScoredTask {
+	var open []Task
+	for _, t := range tm.GetAllTasks() {
+		if isOpen(t) {
+			open = append(open, t)
+		}
+	}
+
+	return firstScored(defaultScorer.Rank(open, DefaultScoringWeights(), time.Now(), nil), limit)
+}
+
+// NextTasksContext ranks the open tasks with the default weights
+func (tm *TaskManager) NextTasksContext(ctx context.Context, limit int) ([]ScoredTask, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return tm.NextTasks(limit), nil
+}
+
+// NextTasksContext ranks the open, unblocked tasks with the default weights
+func (htm *HybridTaskManager) NextTasksContext(ctx context.Context, limit int) ([]ScoredTask, error) {
+	if htm.GetStorageType() == MemoryStorage {
+		return htm.memoryManager.NextTasksContext(ctx, limit)
+	}
+
+	tasks, err := htm.GetAllTasksContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return rankStoredTasks(htm.repository.WithContext(ctx), defaultScorer, tasks, DefaultScoringWeights(), limit)
+}
+
+// SetScorer replaces the scorer that ranks the next tasks of users
+func (um *UserManager) SetScorer(scorer *Scorer) {
+	um.scorer = scorer
+}
+
+func (um *UserManager) scoring() *Scorer {
+	if um.scorer == nil {
+		return defaultScorer
+	}
+	return um.scorer
+}
+
+// GetUserScoringWeights returns the weights a user ranks tasks with: the
+// defaults, overridden by the weights the user has set
+func (um *UserManager) GetUserScoringWeights(userID int) (ScoringWeights, error) {
+	stored, err := um.repository.GetScoringWeights(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	weights := DefaultScoringWeights()
+	if stored != nil {
+		for name, weight := range stored.Weights {
+			weights[name] = weight
+		}
+	}
+
+	return weights, nil
+}
+
+// SetUserScoringWeights sets the weights a user ranks tasks with. Factors
+// left out keep their default weight.
+func (um *UserManager) SetUserScoringWeights(userID int, weights ScoringWeights) (ScoringWeights, error) {
+	effective := DefaultScoringWeights()
+	for name, weight := range weights {
+		effective[name] = weight
+	}
+	if err := um.scoring().Validate(effective); err != nil {
+		return nil, err
+	}
+
+	if err := um.repository.SetScoringWeights(&database.ScoringWeights{UserID: userID, Weights: weights}); err != nil {
+		return nil, err
+	}
+
+	return effective, nil
+}
+
+// ResetUserScoringWeights brings a user back to the default weights
+func (um *UserManager) ResetUserScoringWeights(userID int) error {
+	return um.repository.DeleteScoringWeights(userID)
+}
+
+// GetUserNextTasks ranks the open tasks of a user that are not blocked by
+// unfinished dependencies, with the user's weights, and returns the first
+// limit of them, or all when limit is not positive. Archived and snoozed
+// tasks are left out.
+func (um *UserManager) GetUserNextTasks(userID, limit int) ([]ScoredTask, error) {
+	weights, err := um.GetUserScoringWeights(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := um.GetUserTasks(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return rankStoredTasks(um.repository, um.scoring(), tasks, weights, limit)
+}
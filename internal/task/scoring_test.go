@@ -0,0 +1,158 @@
+package task
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScorerRank(t *testing.T) {
+	now := time.Date(2024, time.March, 13, 12, 0, 0, 0, time.UTC)
+	tomorrow := now.Add(24 * time.Hour)
+	nextMonth := now.Add(30 * 24 * time.Hour)
+	quick := 15
+
+	tasks := []Task{
+		{ID: 1, Title: "Someday", Priority: Low, UpdatedAt: now},
+		{ID: 2, Title: "Due tomorrow", Priority: Medium, DueDate: &tomorrow, UpdatedAt: now},
+		{ID: 3, Title: "Urgent", Priority: Urgent, DueDate: &nextMonth, UpdatedAt: now},
+		{ID: 4, Title: "Quick", Priority: Low, EstimateMinutes: &quick, UpdatedAt: now},
+	}
+
+	ranked := NewScorer().Rank(tasks, DefaultScoringWeights(), now, map[int]int{1: 3})
+	order := make([]int, len(ranked))
+	for i, scored := range ranked {
+		order[i] = scored.Task.ID
+	}
+	if want := []int{2, 3, 1, 4}; !reflect.DeepEqual(order, want) {
+		t.Errorf("Expected order %v, got %v", want, order)
+	}
+	for _, scored := range ranked {
+		sum := 0.0
+		for _, share := range scored.Factors {
+			sum += share
+		}
+		if scored.Score < 0 || scored.Score > 1 || sum-scored.Score > 1e-9 || scored.Score-sum > 1e-9 {
+			t.Errorf("Expected the factors of task %d to add up to its score, got %v and %v", scored.Task.ID, scored.Factors, scored.Score)
+		}
+	}
+
+	// Only the estimate counts
+	ranked = NewScorer().Rank(tasks, ScoringWeights{FactorEstimate: 1}, now, nil)
+	if ranked[0].Task.ID != 4 {
+		t.Errorf("Expected the quick task first, got %d", ranked[0].Task.ID)
+	}
+
+	// A plugged-in factor
+	titled := NewScoreFactor("title", func(task Task, _ ScoreInput) float64 {
+		if task.Title == "Someday" {
+			return 1
+		}
+		return 0
+	})
+	ranked = NewScorer(titled).Rank(tasks, ScoringWeights{"title": 1}, now, nil)
+	if ranked[0].Task.ID != 1 || ranked[0].Score != 1 {
+		t.Errorf("Expected the custom factor to rank Someday first, got %+v", ranked[0])
+	}
+
+	invalid := []ScoringWeights{
+		{FactorPriority: -1},
+		{"luck": 1},
+		{FactorPriority: 0},
+	}
+	for _, weights := range invalid {
+		if err := NewScorer().Validate(weights); err == nil {
+			t.Errorf("Expected weights %v to be invalid", weights)
+		}
+	}
+}
+
+func TestGetUserNextTasks(t *testing.T) {
+	repository := setupTestRepository(t)
+	um := NewUserManager(repository)
+	dm := NewDependencyManager(repository)
+
+	user, err := um.RegisterUser("user", "user@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+
+	tomorrow := time.Now().Add(24 * time.Hour)
+	design, err := um.CreateUserTask(user.ID, "Design", "", Low, nil)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	build, err := um.CreateUserTask(user.ID, "Build", "", Urgent, &tomorrow)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	release, err := um.CreateUserTask(user.ID, "Release", "", Urgent, &tomorrow)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	done, err := um.CreateUserTask(user.ID, "Done", "", Urgent, nil)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if err := um.UpdateUserTaskStatus(user.ID, done.ID, Completed); err != nil {
+		t.Fatalf("Failed to complete task: %v", err)
+	}
+	// Build waits for the design, and the release for the build
+	if err := dm.AddDependency(build.ID, design.ID); err != nil {
+		t.Fatalf("Failed to add dependency: %v", err)
+	}
+	if err := dm.AddDependency(release.ID, build.ID); err != nil {
+		t.Fatalf("Failed to add dependency: %v", err)
+	}
+
+	next, err := um.GetUserNextTasks(user.ID, 0)
+	if err != nil {
+		t.Fatalf("Failed to get next tasks: %v", err)
+	}
+	if len(next) != 1 || next[0].Task.ID != design.ID || next[0].Factors[FactorBlocking] == 0 {
+		t.Fatalf("Expected only the unblocked design task, blocking others, got %+v", next)
+	}
+
+	weights, err := um.GetUserScoringWeights(user.ID)
+	if err != nil || weights[FactorPriority] != DefaultScoringWeights()[FactorPriority] {
+		t.Fatalf("Expected the default weights, got %v (%v)", weights, err)
+	}
+	if _, err := um.SetUserScoringWeights(user.ID, ScoringWeights{FactorPriority: -2}); err == nil {
+		t.Error("Expected a negative weight to be rejected")
+	}
+	weights, err = um.SetUserScoringWeights(user.ID, ScoringWeights{FactorPriority: 10, FactorStaleness: 0})
+	if err != nil {
+		t.Fatalf("Failed to set weights: %v", err)
+	}
+	if weights[FactorPriority] != 10 || weights[FactorStaleness] != 0 || weights[FactorDueDate] != DefaultScoringWeights()[FactorDueDate] {
+		t.Errorf("Expected the set weights over the defaults, got %v", weights)
+	}
+	if stored, err := um.GetUserScoringWeights(user.ID); err != nil || stored[FactorPriority] != 10 {
+		t.Errorf("Expected the weights to be stored, got %v (%v)", stored, err)
+	}
+
+	if err := um.ResetUserScoringWeights(user.ID); err != nil {
+		t.Fatalf("Failed to reset weights: %v", err)
+	}
+	if weights, err := um.GetUserScoringWeights(user.ID); err != nil || weights[FactorPriority] != DefaultScoringWeights()[FactorPriority] {
+		t.Errorf("Expected the default weights after a reset, got %v (%v)", weights, err)
+	}
+}
+
+func TestTaskManagerNextTasks(t *testing.T) {
+	tm := NewTaskManager()
+	tm.AddTask("Low", "", Low, nil)
+	urgent := tm.AddTask("Urgent", "", Urgent, nil)
+	done := tm.AddTask("Done", "", Urgent, nil)
+	if err := tm.UpdateTaskStatus(done.ID, Completed); err != nil {
+		t.Fatalf("Failed to complete task: %v", err)
+	}
+
+	next := tm.NextTasks(1)
+	if len(next) != 1 || next[0].Task.ID != urgent.ID {
+		t.Errorf("Expected the urgent task first, got %+v", next)
+	}
+	if len(tm.NextTasks(0)) != 2 {
+		t.Error("Expected completed tasks to be left out")
+	}
+}
@@ -0,0 +1,332 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"learn-go-capstone/internal/database"
+)
+
+// IsSnoozed reports whether a task is hidden from the default listings at now
+func (t Task) IsSnoozed(now time.Time) bool {
+	return snoozedAt(t.HiddenUntil, now)
+}
+
+func snoozedAt(hiddenUntil *time.Time, now time.Time) bool {
+	return hiddenUntil != nil && hiddenUntil.After(now)
+}
+
+// validateSnooze rejects snoozing a task into the past
+func validateSnooze(until time.Time) error {
+	if !until.After(time.Now()) {
+		return fmt.Errorf("invalid snooze: %s is not in the future", until.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// awakeDatabaseTasks leaves out the tasks that are snoozed at now
+func awakeDatabaseTasks(dbTasks []database.DatabaseTask, now time.Time) []database.DatabaseTask {
+	awake := make([]database.DatabaseTask, 0, len(dbTasks))
+	for _, dbTask := range dbTasks {
+		if !snoozedAt(dbTask.HiddenUntil, now) {
+			awake = append(awake, dbTask)
+		}
+	}
+	return awake
+}
+
+// snoozedDatabaseTasks keeps only the tasks that are snoozed at now
+func snoozedDatabaseTasks(dbTasks []database.DatabaseTask, now time.Time) []database.DatabaseTask {
+	var snoozed []database.DatabaseTask
+	for _, dbTask := range dbTasks {
+		if snoozedAt(dbTask.HiddenUntil, now) {
+			snoozed = append(snoozed, dbTask)
+		}
+	}
+	return snoozed
+}
+
+// setHiddenUntil snoozes an in-memory task until the given time, or wakes it
+// up when until is nil
+func (tm *TaskManager) setHiddenUntil(id int, until *time.Time) error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	for i := range tm.tasks {
+		if tm.tasks[i].ID == id && tm.tasks[i].DeletedAt == nil {
+			tm.tasks[i].HiddenUntil = until
+			tm.tasks[i].UpdatedAt = time.Now()
+			tm.events.Publish(taskEvent(EventTaskUpdated, tm.tasks[i], nil))
+			return nil
+		}
+	}
+
+	return fmt.Errorf("task with ID %d not found", id)
+}
+
+// SnoozeTask hides a task from the default listings until the given time
+func (tm *TaskManager) SnoozeTask(id int, until time.Time) error {
+	if err := validateSnooze(until); err != nil {
+		return err
+	}
+	until = until.UTC()
+	return tm.setHiddenUntil(id, &until)
+}
+
+// UnsnoozeTask brings a snoozed task back into the listings right away
+func (tm *TaskManager) UnsnoozeTask(id int) error {
+	return tm.setHiddenUntil(id, nil)
+}
+
+// GetSnoozedTasks returns the tasks that are currently snoozed
+func (tm *TaskManager) GetSnoozedTasks() []Task {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	now := time.Now()
+	var snoozed []Task
+	for _, task := range tm.tasks {
+		if task.IsSnoozed(now) && !task.IsArchived && task.DeletedAt == nil {
+			snoozed = append(snoozed, task)
+		}
+	}
+
+	return snoozed
+}
+
+// SnoozeTaskContext hides a task from the default listings until the given time
+func (tm *TaskManager) SnoozeTaskContext(ctx context.Context, id int, until time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return tm.SnoozeTask(id, until)
+}
+
+// UnsnoozeTaskContext brings a snoozed task back into the listings right away
+func (tm *TaskManager) UnsnoozeTaskContext(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return tm.UnsnoozeTask(id)
+}
+
+// GetSnoozedTasksContext returns the tasks that are currently snoozed
+func (tm *TaskManager) GetSnoozedTasksContext(ctx context.Context) ([]Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return tm.GetSnoozedTasks(), nil
+}
+
+// setDatabaseTaskHiddenUntil stores when a database task wakes up and
+// publishes the change. Times are stored in UTC.
+func setDatabaseTaskHiddenUntil(repository database.Repository, events *EventBus, dbTask *database.DatabaseTask, until *time.Time) error {
+	if until != nil {
+		utc := until.UTC()
+		until = &utc
+	}
+	if dbTask.HiddenUntil == nil && until == nil {
+		return nil
+	}
+
+	dbTask.HiddenUntil = until
+	if err := repository.UpdateTask(dbTask); err != nil {
+		return err
+	}
+
+	events.Publish(databaseTaskEvent(EventTaskUpdated, dbTask))
+	return nil
+}
+
+// SnoozeTaskContext hides a task from the default listings until the given time
+func (htm *HybridTaskManager) SnoozeTaskContext(ctx context.Context, id int, until time.Time) error {
+	if err := validateSnooze(until); err != nil {
+		return err
+	}
+	return htm.setHiddenUntil(ctx, id, &until)
+}
+
+// UnsnoozeTaskContext brings a snoozed task back into the listings right away
+func (htm *HybridTaskManager) UnsnoozeTaskContext(ctx context.Context, id int) error {
+	return htm.setHiddenUntil(ctx, id, nil)
+}
+
+// GetSnoozedTasksContext returns the tasks that are currently snoozed
+func (htm *HybridTaskManager) GetSnoozedTasksContext(ctx context.Context) ([]Task, error) {
+	return htm.listTasks(ctx, func(repository database.Repository) ([]database.DatabaseTask, error) {
+		dbTasks, err := repository.GetAllTasks()
+		if err != nil {
+			return nil, err
+		}
+		return snoozedDatabaseTasks(dbTasks, time.Now()), nil
+	}, htm.memoryManager.GetSnoozedTasks)
+}
+
+func (htm *HybridTaskManager) setHiddenUntil(ctx context.Context, id int, until *time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	htm.mu.Lock()
+	defer htm.mu.Unlock()
+
+	switch htm.storageType {
+	case DatabaseStorage, HybridStorage:
+		if err := htm.setDatabaseHiddenUntil(ctx, id, until); err != nil {
+			return err
+		}
+		htm.refreshMemory(htm.repository.WithContext(ctx), []int{id})
+		return nil
+
+	default:
+		return htm.memoryManager.setHiddenUntil(id, utcTime(until))
+	}
+}
+
+func (htm *HybridTaskManager) setDatabaseHiddenUntil(ctx context.Context, id int, until *time.Time) error {
+	repository := htm.repository.WithContext(ctx)
+	dbTask, err := repository.GetTask(id)
+	if err != nil {
+		return err
+	}
+
+	return setDatabaseTaskHiddenUntil(repository, htm.events, dbTask, until)
+}
+
+// utcTime returns a copy of t in UTC, or nil
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+// awake wraps a database listing so that it leaves out snoozed tasks
+func awake(list func(database.Repository) ([]database.DatabaseTask, error)) func(database.Repository) ([]database.DatabaseTask, error) {
+	return func(repository database.Repository) ([]database.DatabaseTask, error) {
+		dbTasks, err := list(repository)
+		if err != nil {
+			return nil, err
+		}
+		return awakeDatabaseTasks(dbTasks, time.Now()), nil
+	}
+}
+
+// SnoozeUserTask hides a user's task from the default listings until the
+// given time. The owner is notified when it wakes up.
+func (um *UserManager) SnoozeUserTask(userID, taskID int, until time.Time) (*Task, error) {
+	if err := validateSnooze(until); err != nil {
+		return nil, err
+	}
+	return um.setUserTaskHiddenUntil(userID, taskID, &until)
+}
+
+// UnsnoozeUserTask brings a user's snoozed task back into the listings right
+// away, without a wake-up notification
+func (um *UserManager) UnsnoozeUserTask(userID, taskID int) (*Task, error) {
+	return um.setUserTaskHiddenUntil(userID, taskID, nil)
+}
+
+func (um *UserManager) setUserTaskHiddenUntil(userID, taskID int, until *time.Time) (*Task, error) {
+	dbTask, err := um.repository.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if dbTask.UserID == nil || *dbTask.UserID != userID {
+		return nil, errors.New("access denied: task does not belong to user or user ID is missing")
+	}
+
+	if err := setDatabaseTaskHiddenUntil(um.repository, um.events, dbTask, until); err != nil {
+		return nil, err
+	}
+
+	return um.withAssignees(dbTask)
+}
+
+// GetUserSnoozedTasks gets the tasks of a user that are currently snoozed
+func (um *UserManager) GetUserSnoozedTasks(userID int) ([]Task, error) {
+	dbTasks, err := um.repository.GetTasksByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertFromDatabaseTasks(snoozedDatabaseTasks(dbTasks, time.Now())), nil
+}
+
+// SnoozeWaker periodically wakes up snoozed tasks whose time has come and
+// publishes EventTaskWoken for each, so that their owners can be notified
+type SnoozeWaker struct {
+	repository database.Repository
+	interval   time.Duration
+	events     *EventBus
+	ctx        context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// NewSnoozeWaker creates a waker that checks for woken tasks on every interval
+func NewSnoozeWaker(repository database.Repository, interval time.Duration) *SnoozeWaker {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &SnoozeWaker{
+		repository: repository,
+		interval:   interval,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// SetEventBus sets the bus that woken tasks are published to
+func (w *SnoozeWaker) SetEventBus(bus *EventBus) {
+	w.events = bus
+}
+
+// Start wakes tasks once and then on every interval until Stop is called
+func (w *SnoozeWaker) Start() {
+	runPeriodically(w.ctx, &w.wg, w.interval, func() {
+		if woken, err := w.WakeTasks(time.Now()); err != nil {
+			log.Printf("Waking snoozed tasks failed: %v", err)
+		} else if woken > 0 {
+			log.Printf("Woke up %d snoozed tasks", woken)
+		}
+	})
+}
+
+// Stop stops the waker and waits for a running pass to finish
+func (w *SnoozeWaker) Stop() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+// WakeTasks clears the snooze of every task snoozed until now or earlier and
+// returns how many were woken up
+func (w *SnoozeWaker) WakeTasks(now time.Time) (int, error) {
+	repository := w.repository.WithContext(w.ctx)
+
+	dbTasks, err := repository.GetWokenTasks(now)
+	if err != nil {
+		return 0, err
+	}
+
+	woken := 0
+	for i := range dbTasks {
+		dbTask := &dbTasks[i]
+		dbTask.HiddenUntil = nil
+		if err := repository.UpdateTask(dbTask); err != nil {
+			return woken, err
+		}
+		w.events.Publish(databaseTaskEvent(EventTaskWoken, dbTask))
+		woken++
+	}
+
+	return woken, nil
+}
@@ -0,0 +1,122 @@
+package task
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTaskManagerSnooze(t *testing.T) {
+	tm := NewTaskManager()
+	kept := tm.AddTask("Keep", "", High, nil)
+	yesterday := time.Now().Add(-24 * time.Hour)
+	later := tm.AddTask("Later", "", Low, &yesterday)
+
+	if err := tm.SnoozeTask(later.ID, time.Now().Add(-time.Minute)); err == nil {
+		t.Error("Expected snoozing into the past to fail")
+	}
+	if err := tm.SnoozeTask(42, time.Now().Add(time.Hour)); err == nil {
+		t.Error("Expected error when snoozing a missing task")
+	}
+	if err := tm.SnoozeTask(later.ID, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Failed to snooze task: %v", err)
+	}
+
+	all := tm.GetAllTasks()
+	if len(all) != 1 || all[0].ID != kept.ID {
+		t.Errorf("Expected only the awake task, got %+v", all)
+	}
+	if len(tm.GetTasksByPriority(Low)) != 0 || len(tm.GetOverdueTasks()) != 0 {
+		t.Error("Expected snoozed tasks to be left out of filtered listings and overdue tasks")
+	}
+
+	snoozed := tm.GetSnoozedTasks()
+	if len(snoozed) != 1 || snoozed[0].ID != later.ID || snoozed[0].HiddenUntil == nil {
+		t.Errorf("Expected the snoozed task, got %+v", snoozed)
+	}
+
+	if err := tm.UnsnoozeTask(later.ID); err != nil {
+		t.Fatalf("Failed to unsnooze task: %v", err)
+	}
+	if len(tm.GetAllTasks()) != 2 || len(tm.GetOverdueTasks()) != 1 {
+		t.Error("Expected the unsnoozed task to be listed again")
+	}
+}
+
+func TestSnoozeUserTaskHidesUntilWoken(t *testing.T) {
+	repository := setupTestRepository(t)
+	um := NewUserManager(repository)
+	dm := NewDependencyManager(repository)
+
+	user, err := um.RegisterUser("user", "user@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	other, err := um.RegisterUser("other", "other@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+
+	yesterday := time.Now().Add(-24 * time.Hour)
+	snoozed, err := um.CreateUserTask(user.ID, "Renew passport", "", Medium, &yesterday)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	awake, err := um.CreateUserTask(user.ID, "Book flights", "", Medium, nil)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	bus := NewEventBus()
+	um.SetEventBus(bus)
+	var events recorder
+	bus.Subscribe(events.handle)
+
+	until := time.Now().Add(3 * 24 * time.Hour)
+	if _, err := um.SnoozeUserTask(other.ID, snoozed.ID, until); err == nil {
+		t.Error("Expected snoozing another user's task to fail")
+	}
+	updated, err := um.SnoozeUserTask(user.ID, snoozed.ID, until)
+	if err != nil {
+		t.Fatalf("Failed to snooze task: %v", err)
+	}
+	if updated.HiddenUntil == nil || !updated.HiddenUntil.Equal(until) || updated.HiddenUntil.Location() != time.UTC {
+		t.Errorf("Expected the task to be hidden until %v in UTC, got %v", until, updated.HiddenUntil)
+	}
+	assertEventTypes(t, events.types(), EventTaskUpdated)
+
+	tasks, err := um.GetUserTasks(user.ID)
+	if err != nil || len(tasks) != 1 || tasks[0].ID != awake.ID {
+		t.Errorf("Expected only the awake task to be listed, got %+v (%v)", tasks, err)
+	}
+	ready, err := dm.GetReadyTasks()
+	if err != nil || len(ready) != 1 || ready[0].ID != awake.ID {
+		t.Errorf("Expected only the awake task to be ready, got %+v (%v)", ready, err)
+	}
+	overdue, err := repository.GetOverdueTasks()
+	if err != nil || len(overdue) != 0 {
+		t.Errorf("Expected the snoozed task to be left out of overdue tasks, got %d (%v)", len(overdue), err)
+	}
+	hidden, err := um.GetUserSnoozedTasks(user.ID)
+	if err != nil || len(hidden) != 1 || hidden[0].ID != snoozed.ID {
+		t.Errorf("Expected the snoozed task, got %+v (%v)", hidden, err)
+	}
+
+	waker := NewSnoozeWaker(repository, time.Minute)
+	waker.SetEventBus(bus)
+	if woken, err := waker.WakeTasks(time.Now()); err != nil || woken != 0 {
+		t.Fatalf("Expected nothing to wake up yet, got %d (%v)", woken, err)
+	}
+	if woken, err := waker.WakeTasks(until.Add(time.Minute)); err != nil || woken != 1 {
+		t.Fatalf("Expected one task to wake up, got %d (%v)", woken, err)
+	}
+	assertEventTypes(t, events.types(), EventTaskUpdated, EventTaskWoken)
+
+	tasks, err = um.GetUserTasks(user.ID)
+	if err != nil || len(tasks) != 2 {
+		t.Errorf("Expected the woken task to be listed again, got %d (%v)", len(tasks), err)
+	}
+	overdue, err = repository.GetOverdueTasks()
+	if err != nil || len(overdue) != 1 || overdue[0].HiddenUntil != nil {
+		t.Errorf("Expected the woken task to be overdue again, got %+v (%v)", overdue, err)
+	}
+}
@@ -0,0 +1,396 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"learn-go-capstone/internal/database"
+)
+
+// maxSprintDays bounds the length of a sprint, so that reports stay small
+const maxSprintDays = 366
+
+// Sprint is an iteration from StartDate to EndDate, both dates included, over
+// the tasks of its owner or, when ProjectID is set, of a project. Tasks are
+// planned into at most one sprint at a time. Closed sprints no longer change.
+type Sprint struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	Goal      string     `json:"goal,omitempty"`
+	OwnerID   *int       `json:"owner_id,omitempty"`
+	ProjectID *int       `json:"project_id,omitempty"`
+	StartDate time.Time  `json:"start_date"`
+	EndDate   time.Time  `json:"end_date"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	// Tasks are only loaded when a single sprint is read
+	Tasks []Task `json:"tasks,omitempty"`
+}
+
+func convertFromDatabaseSprint(ds *database.Sprint) Sprint {
+	return Sprint{
+		ID:        ds.ID,
+		Name:      ds.Name,
+		Goal:      ds.Goal,
+		OwnerID:   ds.OwnerID,
+		ProjectID: ds.ProjectID,
+		StartDate: ds.StartDate,
+		EndDate:   ds.EndDate,
+		ClosedAt:  ds.ClosedAt,
+		CreatedAt: ds.CreatedAt,
+		UpdatedAt: ds.UpdatedAt,
+	}
+}
+
+// sprintDate returns the calendar date of t as midnight UTC
+func sprintDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// checkSprintFields trims the name and goal of a sprint, reduces its dates to
+// calendar dates and validates them
+func checkSprintFields(dbSprint *database.Sprint) error {
+	dbSprint.Name = strings.TrimSpace(dbSprint.Name)
+	dbSprint.Goal = strings.TrimSpace(dbSprint.Goal)
+	dbSprint.StartDate = sprintDate(dbSprint.StartDate)
+	dbSprint.EndDate = sprintDate(dbSprint.EndDate)
+
+	if dbSprint.Name == "" {
+		return errors.New("invalid sprint: name cannot be empty")
+	}
+	if len([]rune(dbSprint.Name)) > maxBoardNameLength {
+		return fmt.Errorf("invalid sprint: name cannot be longer than %d characters", maxBoardNameLength)
+	}
+	if dbSprint.EndDate.Before(dbSprint.StartDate) {
+		return errors.New("invalid sprint: end date cannot be before start date")
+	}
+	if dbSprint.EndDate.Sub(dbSprint.StartDate) >= maxSprintDays*24*time.Hour {
+		return fmt.Errorf("invalid sprint: a sprint cannot be longer than %d days", maxSprintDays)
+	}
+	return nil
+}
+
+// checkSprintOpen returns an error when the sprint is closed
+func checkSprintOpen(dbSprint *database.Sprint) error {
+	if dbSprint.ClosedAt != nil {
+		return fmt.Errorf("sprint %q is already closed", dbSprint.Name)
+	}
+	return nil
+}
+
+// SprintManager manages sprints. Everyone who can see the tasks of a sprint
+// can see it, plan tasks into it and read its reports; only the sprint owner
+// and the owners of its project can change, close or delete it.
+type SprintManager struct {
+	repository database.Repository
+}
+
+// NewSprintManager creates a new sprint manager
+func NewSprintManager(repository database.Repository) *SprintManager {
+	return &SprintManager{repository: repository}
+}
+
+// WithContext returns a copy of the sprint manager whose queries run with ctx
+func (sm *SprintManager) WithContext(ctx context.Context) *SprintManager {
+	if sm.repository == nil {
+		return sm
+	}
+	return &SprintManager{repository: sm.repository.WithContext(ctx)}
+}
+
+// checkProject returns an error unless the user is a member of the project
+func (sm *SprintManager) checkProject(userID int, projectID *int) error {
+	if projectID == nil {
+		return nil
+	}
+
+	if _, err := sm.repository.GetProject(*projectID); err != nil {
+		return err
+	}
+	role, err := projectRole(sm.repository, userID, *projectID)
+	if err != nil {
+		return err
+	}
+	if role == "" {
+		return fmt.Errorf("project with ID %d not found", *projectID)
+	}
+	return nil
+}
+
+// userSprint returns a sprint the user can see
+func (sm *SprintManager) userSprint(userID, sprintID int) (*database.Sprint, error) {
+	dbSprint, err := sm.repository.GetSprint(sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := dbSprint.ProjectID == nil && dbSprint.OwnerID != nil && *dbSprint.OwnerID == userID
+	if dbSprint.ProjectID != nil {
+		role, err := projectRole(sm.repository, userID, *dbSprint.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		visible = role != ""
+	}
+
+	if !visible {
+		return nil, fmt.Errorf("sprint with ID %d not found", sprintID)
+	}
+	return dbSprint, nil
+}
+
+// checkSprintOwner returns an error unless the user owns the sprint or its project
+func (sm *SprintManager) checkSprintOwner(userID int, dbSprint *database.Sprint) error {
+	if dbSprint.OwnerID != nil && *dbSprint.OwnerID == userID {
+		return nil
+	}
+
+	if dbSprint.ProjectID != nil {
+		role, err := projectRole(sm.repository, userID, *dbSprint.ProjectID)
+		if err != nil {
+			return err
+		}
+		if role == ProjectOwner {
+			return nil
+		}
+	}
+
+	return errors.New("access denied: only the sprint owner or a project owner can change the sprint")
+}
+
+// CreateSprint creates a sprint owned by the user, over their own tasks or,
+// when projectID is set, over the tasks of one of their projects
+func (sm *SprintManager) CreateSprint(userID int, name, goal string, startDate, endDate time.Time, projectID *int) (*Sprint, error) {
+	dbSprint := &database.Sprint{
+		Name:      name,
+		Goal:      goal,
+		OwnerID:   &userID,
+		ProjectID: projectID,
+		StartDate: startDate,
+		EndDate:   endDate,
+	}
+	if err := checkSprintFields(dbSprint); err != nil {
+		return nil, err
+	}
+	if err := sm.checkProject(userID, projectID); err != nil {
+		return nil, err
+	}
+
+	if err := sm.repository.CreateSprint(dbSprint); err != nil {
+		return nil, err
+	}
+
+	sprint := convertFromDatabaseSprint(dbSprint)
+	return &sprint, nil
+}
+
+// GetSprints returns the sprints of a project of the user or, when projectID
+// is nil, their personal sprints, by start date and without their tasks
+func (sm *SprintManager) GetSprints(userID int, projectID *int) ([]Sprint, error) {
+	if err := sm.checkProject(userID, projectID); err != nil {
+		return nil, err
+	}
+
+	dbSprints, err := sm.repository.GetSprints(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	sprints := make([]Sprint, len(dbSprints))
+	for i := range dbSprints {
+		sprints[i] = convertFromDatabaseSprint(&dbSprints[i])
+	}
+	return sprints, nil
+}
+
+// GetSprint returns a sprint with the tasks currently planned into it
+func (sm *SprintManager) GetSprint(userID, sprintID int) (*Sprint, error) {
+	dbSprint, err := sm.userSprint(userID, sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := sm.repository.GetTasksBySprint(sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	sprint := convertFromDatabaseSprint(dbSprint)
+	sprint.Tasks = convertFromDatabaseTasks(tasks)
+	return &sprint, nil
+}
+
+// UpdateSprint changes the name, goal and dates of an open sprint
+func (sm *SprintManager) UpdateSprint(userID, sprintID int, name, goal string, startDate, endDate time.Time) (*Sprint, error) {
+	dbSprint, err := sm.userSprint(userID, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	if err := sm.checkSprintOwner(userID, dbSprint); err != nil {
+		return nil, err
+	}
+	if err := checkSprintOpen(dbSprint); err != nil {
+		return nil, err
+	}
+
+	dbSprint.Name = name
+	dbSprint.Goal = goal
+	dbSprint.StartDate = startDate
+	dbSprint.EndDate = endDate
+	if err := checkSprintFields(dbSprint); err != nil {
+		return nil, err
+	}
+
+	if err := sm.repository.UpdateSprint(dbSprint); err != nil {
+		return nil, err
+	}
+
+	return sm.GetSprint(userID, sprintID)
+}
+
+// DeleteSprint deletes a sprint. Its tasks go back to the backlog.
+func (sm *SprintManager) DeleteSprint(userID, sprintID int) error {
+	dbSprint, err := sm.userSprint(userID, sprintID)
+	if err != nil {
+		return err
+	}
+	if err := sm.checkSprintOwner(userID, dbSprint); err != nil {
+		return err
+	}
+
+	return sm.repository.DeleteSprint(sprintID)
+}
+
+// AddTask plans a task into an open sprint, taking it out of any other
+// sprint. The task must belong to the sprint's project or, for a personal
+// sprint, be a task of the user outside any project.
+func (sm *SprintManager) AddTask(userID, sprintID, taskID int) (*Sprint, error) {
+	dbSprint, err := sm.userSprint(userID, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSprintOpen(dbSprint); err != nil {
+		return nil, err
+	}
+
+	dbTask, err := sm.repository.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := dbTask.UserID != nil && *dbTask.UserID == userID
+	if !visible && dbTask.ProjectID != nil {
+		role, err := projectRole(sm.repository, userID, *dbTask.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		visible = role != ""
+	}
+	if !visible {
+		return nil, fmt.Errorf("task with ID %d not found", taskID)
+	}
+	if !sameProject(dbTask.ProjectID, dbSprint.ProjectID) {
+		return nil, fmt.Errorf("invalid sprint task: task %d is not in the project of sprint %q", taskID, dbSprint.Name)
+	}
+
+	if dbTask.SprintID == nil || *dbTask.SprintID != sprintID {
+		dbTask.SprintID = &sprintID
+		dbTask.UpdatedAt = time.Now()
+		if err := sm.repository.UpdateTask(dbTask); err != nil {
+			return nil, err
+		}
+	}
+
+	return sm.GetSprint(userID, sprintID)
+}
+
+// RemoveTask takes a task out of an open sprint, back to the backlog
+func (sm *SprintManager) RemoveTask(userID, sprintID, taskID int) error {
+	dbSprint, err := sm.userSprint(userID, sprintID)
+	if err != nil {
+		return err
+	}
+	if err := checkSprintOpen(dbSprint); err != nil {
+		return err
+	}
+
+	dbTask, err := sm.repository.GetTask(taskID)
+	if err != nil {
+		return err
+	}
+	if dbTask.SprintID == nil || *dbTask.SprintID != sprintID {
+		return fmt.Errorf("task with ID %d not found", taskID)
+	}
+
+	dbTask.SprintID = nil
+	dbTask.UpdatedAt = time.Now()
+	return sm.repository.UpdateTask(dbTask)
+}
+
+// CloseSprint closes a sprint. Its tasks that are neither completed nor
+// cancelled carry over into the open sprint carryOverTo of the same project
+// or owner, or go back to the backlog when carryOverTo is nil.
+func (sm *SprintManager) CloseSprint(userID, sprintID int, carryOverTo *int) (*Sprint, error) {
+	dbSprint, err := sm.userSprint(userID, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	if err := sm.checkSprintOwner(userID, dbSprint); err != nil {
+		return nil, err
+	}
+	if err := checkSprintOpen(dbSprint); err != nil {
+		return nil, err
+	}
+
+	if carryOverTo != nil {
+		next, err := sm.userSprint(userID, *carryOverTo)
+		if err != nil {
+			return nil, err
+		}
+		if next.ID == sprintID {
+			return nil, errors.New("invalid carry-over: a sprint cannot carry over into itself")
+		}
+		if !sameProject(next.ProjectID, dbSprint.ProjectID) {
+			return nil, fmt.Errorf("invalid carry-over: sprint %q is not in the same project", next.Name)
+		}
+		if err := checkSprintOpen(next); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := sm.repository.CloseSprint(sprintID, carryOverTo); err != nil {
+		return nil, err
+	}
+
+	return sm.GetSprint(userID, sprintID)
+}
+
+// GetReport returns the burndown of a sprint with the velocity of the
+// sprints of its project or owner that closed by its end
+func (sm *SprintManager) GetReport(userID, sprintID int, unit BurndownUnit) (*SprintReport, error) {
+	dbSprint, err := sm.userSprint(userID, sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildSprintReport(sm.repository, dbSprint, unit, time.Now())
+}
+
+// GetVelocity returns the velocity of the closed sprints of a project of the
+// user or, when projectID is nil, of their personal sprints
+func (sm *SprintManager) GetVelocity(userID int, projectID *int, unit BurndownUnit) (*VelocityReport, error) {
+	if err := sm.checkProject(userID, projectID); err != nil {
+		return nil, err
+	}
+
+	dbSprints, err := sm.repository.GetSprints(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildVelocityReport(sm.repository, dbSprints, unit)
+}
@@ -0,0 +1,195 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"learn-go-capstone/internal/database"
+)
+
+func TestSprintSnapshot(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	text := func(s string) *string { return &s }
+	sprintID, estimate := 7, 60
+
+	// The first task joins the sprint on day 2 and is completed on day 4; the
+	// second is in the sprint from its creation and cancelled on day 3
+	timelines := []taskTimeline{
+		{
+			task: &database.DatabaseTask{ID: 1, Status: int(Completed), CreatedAt: day(1), SprintID: &sprintID, EstimateMinutes: &estimate},
+			history: []database.TaskHistoryEntry{
+				{Field: "sprint_id", NewValue: text("7"), ChangedAt: day(2).Add(time.Hour)},
+				{Field: "status", OldValue: text("0"), NewValue: text("2"), ChangedAt: day(4).Add(time.Hour)},
+			},
+		},
+		{
+			task: &database.DatabaseTask{ID: 2, Status: int(Cancelled), CreatedAt: day(1), SprintID: &sprintID},
+			history: []database.TaskHistoryEntry{
+				{Field: "status", OldValue: text("1"), NewValue: text("3"), ChangedAt: day(3).Add(time.Hour)},
+			},
+		},
+	}
+
+	tests := []struct {
+		at               time.Time
+		unit             BurndownUnit
+		scope, completed int
+	}{
+		{day(2), BurndownTasks, 1, 0},
+		{day(3), BurndownTasks, 2, 0},
+		{day(4), BurndownTasks, 1, 0},
+		{day(5), BurndownTasks, 1, 1},
+		{day(5), BurndownEstimate, 60, 60},
+	}
+	for _, tt := range tests {
+		scope, completed := sprintSnapshot(timelines, sprintID, tt.at, tt.unit)
+		if scope != tt.scope || completed != tt.completed {
+			t.Errorf("At %s in %s: expected %d/%d, got %d/%d", tt.at.Format("2006-01-02"), tt.unit, tt.completed, tt.scope, completed, scope)
+		}
+	}
+}
+
+func TestSprints(t *testing.T) {
+	repository := setupTestRepository(t)
+	um := NewUserManager(repository)
+	sm := NewSprintManager(repository)
+
+	owner, err := um.RegisterUser("owner", "owner@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	outsider, err := um.RegisterUser("outsider", "outsider@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+
+	today := sprintDate(time.Now())
+	if _, err := sm.CreateSprint(owner.ID, "Backwards", "", today, today.AddDate(0, 0, -1), nil); err == nil {
+		t.Error("Expected error for an end date before the start date")
+	}
+	if _, err := sm.CreateSprint(owner.ID, " ", "", today, today, nil); err == nil {
+		t.Error("Expected error for an empty sprint name")
+	}
+
+	first, err := sm.CreateSprint(owner.ID, "Sprint 1", "Get started", today.AddDate(0, 0, -2), today.AddDate(0, 0, 2), nil)
+	if err != nil {
+		t.Fatalf("Failed to create sprint: %v", err)
+	}
+	second, err := sm.CreateSprint(owner.ID, "Sprint 2", "", today.AddDate(0, 0, 3), today.AddDate(0, 0, 16), nil)
+	if err != nil {
+		t.Fatalf("Failed to create sprint: %v", err)
+	}
+
+	var ids []int
+	for _, title := range []string{"Design", "Build", "Test"} {
+		created, err := um.CreateUserTask(owner.ID, title, "", Medium, nil)
+		if err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		if _, err := sm.AddTask(owner.ID, first.ID, created.ID); err != nil {
+			t.Fatalf("Failed to add task to sprint: %v", err)
+		}
+		ids = append(ids, created.ID)
+	}
+	if _, err := sm.AddTask(outsider.ID, first.ID, ids[0]); err == nil {
+		t.Error("Expected error when an outsider plans into the sprint")
+	}
+	if err := sm.RemoveTask(owner.ID, first.ID, ids[2]); err != nil {
+		t.Fatalf("Failed to remove task from sprint: %v", err)
+	}
+	if err := um.UpdateUserTaskStatus(owner.ID, ids[0], Completed); err != nil {
+		t.Fatalf("Failed to complete task: %v", err)
+	}
+
+	sprint, err := sm.GetSprint(owner.ID, first.ID)
+	if err != nil {
+		t.Fatalf("Failed to get sprint: %v", err)
+	}
+	if len(sprint.Tasks) != 2 || sprint.Tasks[0].SprintID == nil || *sprint.Tasks[0].SprintID != first.ID {
+		t.Errorf("Expected two tasks in the sprint, got %+v", sprint.Tasks)
+	}
+
+	// Days before the tasks were planned are empty, and future days are left out
+	report, err := sm.GetReport(owner.ID, first.ID, BurndownTasks)
+	if err != nil {
+		t.Fatalf("Failed to get report: %v", err)
+	}
+	if len(report.Burndown) != 3 {
+		t.Fatalf("Expected three days of burndown, got %+v", report.Burndown)
+	}
+	if point := report.Burndown[0]; point.Scope != 0 {
+		t.Errorf("Expected an empty first day, got %+v", point)
+	}
+	if point := report.Burndown[2]; point.Scope != 2 || point.Completed != 1 || point.Remaining != 1 {
+		t.Errorf("Expected one of two tasks done today, got %+v", point)
+	}
+
+	if _, err := sm.CloseSprint(outsider.ID, first.ID, nil); err == nil {
+		t.Error("Expected error when an outsider closes the sprint")
+	}
+	if _, err := sm.CloseSprint(owner.ID, first.ID, &first.ID); err == nil {
+		t.Error("Expected error when carrying over into the same sprint")
+	}
+	closed, err := sm.CloseSprint(owner.ID, first.ID, &second.ID)
+	if err != nil {
+		t.Fatalf("Failed to close sprint: %v", err)
+	}
+	if closed.ClosedAt == nil || len(closed.Tasks) != 1 || closed.Tasks[0].ID != ids[0] {
+		t.Errorf("Expected only the completed task to stay in the closed sprint, got %+v", closed)
+	}
+	if _, err := sm.AddTask(owner.ID, first.ID, ids[2]); err == nil {
+		t.Error("Expected error when planning into a closed sprint")
+	}
+
+	// The unfinished task carried over
+	next, err := sm.GetSprint(owner.ID, second.ID)
+	if err != nil {
+		t.Fatalf("Failed to get sprint: %v", err)
+	}
+	if len(next.Tasks) != 1 || next.Tasks[0].ID != ids[1] {
+		t.Errorf("Expected the unfinished task in the next sprint, got %+v", next.Tasks)
+	}
+
+	// Reports of a closed sprint still see the carried-over task
+	report, err = sm.GetReport(owner.ID, first.ID, BurndownTasks)
+	if err != nil {
+		t.Fatalf("Failed to get report: %v", err)
+	}
+	if report.Remaining != 1 || report.Completed != 1 {
+		t.Errorf("Expected one task done and one left at closing, got %+v", report)
+	}
+
+	velocity, err := sm.GetVelocity(owner.ID, nil, BurndownTasks)
+	if err != nil {
+		t.Fatalf("Failed to get velocity: %v", err)
+	}
+	if len(velocity.Sprints) != 1 || velocity.Sprints[0].SprintID != first.ID || velocity.Sprints[0].Completed != 1 || velocity.Average != 1 {
+		t.Errorf("Expected the velocity of the closed sprint, got %+v", velocity)
+	}
+
+	// The CLI reads the same report without a user
+	htm := NewHybridTaskManager(repository, DatabaseStorage)
+	cliReport, err := htm.GetSprintReportContext(context.Background(), first.ID, BurndownTasks)
+	if err != nil {
+		t.Fatalf("Failed to get report: %v", err)
+	}
+	if cliReport.Completed != report.Completed || len(cliReport.Burndown) != len(report.Burndown) {
+		t.Errorf("Expected the same report, got %+v", cliReport)
+	}
+	if _, err := NewTaskManager().GetSprintReportContext(context.Background(), first.ID, BurndownTasks); !errors.Is(err, ErrSprintsUnavailable) {
+		t.Errorf("Expected ErrSprintsUnavailable, got %v", err)
+	}
+
+	if err := sm.DeleteSprint(owner.ID, second.ID); err != nil {
+		t.Fatalf("Failed to delete sprint: %v", err)
+	}
+	backlog, err := um.GetVisibleTask(owner.ID, ids[1])
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if backlog.SprintID != nil {
+		t.Errorf("Expected the task back in the backlog, got sprint %d", *backlog.SprintID)
+	}
+}
@@ -0,0 +1,348 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"learn-go-capstone/internal/database"
+)
+
+// ErrSprintsUnavailable is returned when sprints are requested from memory
+// storage, which keeps neither sprints nor task history
+var ErrSprintsUnavailable = errors.New("sprints are only available in database storage")
+
+// BurndownUnit is what sprint reports count
+type BurndownUnit string
+
+const (
+	// BurndownTasks counts tasks
+	BurndownTasks BurndownUnit = "tasks"
+	// BurndownEstimate sums estimated minutes; tasks without an estimate count as zero
+	BurndownEstimate BurndownUnit = "estimate"
+)
+
+// ParseBurndownUnit parses a burndown unit, defaulting to tasks
+func ParseBurndownUnit(value string) (BurndownUnit, error) {
+	switch unit := BurndownUnit(strings.ToLower(strings.TrimSpace(value))); unit {
+	case "":
+		return BurndownTasks, nil
+	case BurndownTasks, BurndownEstimate:
+		return unit, nil
+	default:
+		return "", fmt.Errorf("invalid burndown unit %q: use tasks or estimate", value)
+	}
+}
+
+// BurndownPoint is the state of a sprint at the end of a day. Cancelled tasks
+// are left out of the scope.
+type BurndownPoint struct {
+	Date      time.Time `json:"date"`
+	Scope     int       `json:"scope"`
+	Completed int       `json:"completed"`
+	Remaining int       `json:"remaining"`
+	// Ideal falls in a straight line from the committed scope to zero on the last day
+	Ideal float64 `json:"ideal"`
+}
+
+// SprintVelocity is what a closed sprint committed to and completed
+type SprintVelocity struct {
+	SprintID  int       `json:"sprint_id"`
+	Name      string    `json:"name"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+	// Committed is the scope at the end of the first day
+	Committed int `json:"committed"`
+	// Completed is what was completed when the sprint closed
+	Completed int `json:"completed"`
+}
+
+// VelocityReport lists the velocity of closed sprints, oldest first
+type VelocityReport struct {
+	Unit    BurndownUnit     `json:"unit"`
+	Sprints []SprintVelocity `json:"sprints"`
+	Average float64          `json:"average"`
+}
+
+// SprintReport is the daily burndown of a sprint, up to today or to its
+// closing, with the velocity of the sprints of its project or owner that
+// closed by its end
+type SprintReport struct {
+	Sprint    Sprint          `json:"sprint"`
+	Unit      BurndownUnit    `json:"unit"`
+	Committed int             `json:"committed"`
+	Completed int             `json:"completed"`
+	Remaining int             `json:"remaining"`
+	Burndown  []BurndownPoint `json:"burndown"`
+	Velocity  VelocityReport  `json:"velocity"`
+}
+
+// taskTimeline is a task with its recorded changes, from which its state at
+// any earlier time is rebuilt
+type taskTimeline struct {
+	task    *database.DatabaseTask
+	history []database.TaskHistoryEntry
+}
+
+// valueAt returns the value a field had just before at, given its current value
+func (tl *taskTimeline) valueAt(field string, current *string, at time.Time) *string {
+	var value *string
+	changed := false
+	for _, entry := range tl.history {
+		if entry.Field != field {
+			continue
+		}
+		if !entry.ChangedAt.Before(at) {
+			if changed {
+				return value
+			}
+			return entry.OldValue
+		}
+		value, changed = entry.NewValue, true
+	}
+
+	if changed {
+		return value
+	}
+	return current
+}
+
+// intAt returns the value an integer field had just before at, or nil
+func (tl *taskTimeline) intAt(field string, current *int, at time.Time) *int {
+	var currentValue *string
+	if current != nil {
+		value := strconv.Itoa(*current)
+		currentValue = &value
+	}
+
+	value := tl.valueAt(field, currentValue, at)
+	if value == nil {
+		return nil
+	}
+	i, err := strconv.Atoi(*value)
+	if err != nil {
+		return nil
+	}
+	return &i
+}
+
+// sprintTimelines loads every task that is or was in a sprint with its history
+func sprintTimelines(repository database.Repository, sprintID int) ([]taskTimeline, error) {
+	tasks, err := repository.GetSprintHistoryTasks(sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	timelines := make([]taskTimeline, len(tasks))
+	for i := range tasks {
+		history, err := repository.GetTaskHistory(tasks[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		timelines[i] = taskTimeline{task: &tasks[i], history: history}
+	}
+	return timelines, nil
+}
+
+// sprintSnapshot measures the scope of a sprint and its completed part just before at
+func sprintSnapshot(timelines []taskTimeline, sprintID int, at time.Time, unit BurndownUnit) (scope, completed int) {
+	for i := range timelines {
+		tl := &timelines[i]
+		if !tl.task.CreatedAt.Before(at) {
+			continue
+		}
+
+		sprint := tl.intAt("sprint_id", tl.task.SprintID, at)
+		if sprint == nil || *sprint != sprintID {
+			continue
+		}
+
+		var deletedAt *string
+		if tl.task.DeletedAt != nil {
+			deleted := tl.task.DeletedAt.UTC().Format(time.RFC3339)
+			deletedAt = &deleted
+		}
+		if tl.valueAt("deleted_at", deletedAt, at) != nil {
+			continue
+		}
+
+		status := Status(tl.task.Status)
+		if value := tl.intAt("status", &tl.task.Status, at); value != nil {
+			status = Status(*value)
+		}
+		if status == Cancelled {
+			continue
+		}
+
+		weight := 1
+		if unit == BurndownEstimate {
+			weight = 0
+			if estimate := tl.intAt("estimate_minutes", tl.task.EstimateMinutes, at); estimate != nil {
+				weight = *estimate
+			}
+		}
+
+		scope += weight
+		if status == Completed {
+			completed += weight
+		}
+	}
+	return scope, completed
+}
+
+// sprintEnd is the time reports of a sprint stop at: its closing or now
+func sprintEnd(dbSprint *database.Sprint, now time.Time) time.Time {
+	if dbSprint.ClosedAt != nil && dbSprint.ClosedAt.Before(now) {
+		return *dbSprint.ClosedAt
+	}
+	return now
+}
+
+// buildSprintReport builds the burndown of a sprint up to now and the
+// velocity of the sprints of its project or owner that closed by its end
+func buildSprintReport(repository database.Repository, dbSprint *database.Sprint, unit BurndownUnit, now time.Time) (*SprintReport, error) {
+	timelines, err := sprintTimelines(repository, dbSprint.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SprintReport{
+		Sprint:   convertFromDatabaseSprint(dbSprint),
+		Unit:     unit,
+		Burndown: []BurndownPoint{},
+	}
+
+	end := sprintEnd(dbSprint, now)
+	days := int(dbSprint.EndDate.Sub(dbSprint.StartDate)/(24*time.Hour)) + 1
+	for day := dbSprint.StartDate; !day.After(dbSprint.EndDate) && day.Before(end); day = day.AddDate(0, 0, 1) {
+		at := day.AddDate(0, 0, 1)
+		if at.After(end) {
+			at = end
+		}
+
+		scope, completed := sprintSnapshot(timelines, dbSprint.ID, at, unit)
+		report.Burndown = append(report.Burndown, BurndownPoint{
+			Date:      day,
+			Scope:     scope,
+			Completed: completed,
+			Remaining: scope - completed,
+		})
+	}
+
+	if len(report.Burndown) > 0 {
+		report.Committed = report.Burndown[0].Scope
+		last := report.Burndown[len(report.Burndown)-1]
+		report.Completed = last.Completed
+		report.Remaining = last.Remaining
+	}
+	for i := range report.Burndown {
+		if days > 1 {
+			report.Burndown[i].Ideal = float64(report.Committed) * float64(days-1-i) / float64(days-1)
+		}
+	}
+
+	// Velocity compares the sprints of the same project, or of the same owner
+	var dbSprints []database.Sprint
+	switch {
+	case dbSprint.ProjectID != nil:
+		dbSprints, err = repository.GetSprints(0, dbSprint.ProjectID)
+	case dbSprint.OwnerID != nil:
+		dbSprints, err = repository.GetSprints(*dbSprint.OwnerID, nil)
+	default:
+		dbSprints = []database.Sprint{*dbSprint}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var past []database.Sprint
+	for _, other := range dbSprints {
+		if !other.EndDate.After(dbSprint.EndDate) {
+			past = append(past, other)
+		}
+	}
+
+	velocity, err := buildVelocityReport(repository, past, unit)
+	if err != nil {
+		return nil, err
+	}
+	report.Velocity = *velocity
+
+	return report, nil
+}
+
+// buildVelocityReport measures the closed sprints among dbSprints
+func buildVelocityReport(repository database.Repository, dbSprints []database.Sprint, unit BurndownUnit) (*VelocityReport, error) {
+	report := &VelocityReport{Unit: unit, Sprints: []SprintVelocity{}}
+
+	total := 0
+	for i := range dbSprints {
+		dbSprint := &dbSprints[i]
+		if dbSprint.ClosedAt == nil {
+			continue
+		}
+
+		timelines, err := sprintTimelines(repository, dbSprint.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		firstDay := dbSprint.StartDate.AddDate(0, 0, 1)
+		if firstDay.After(*dbSprint.ClosedAt) {
+			firstDay = *dbSprint.ClosedAt
+		}
+		committed, _ := sprintSnapshot(timelines, dbSprint.ID, firstDay, unit)
+		_, completed := sprintSnapshot(timelines, dbSprint.ID, *dbSprint.ClosedAt, unit)
+
+		report.Sprints = append(report.Sprints, SprintVelocity{
+			SprintID:  dbSprint.ID,
+			Name:      dbSprint.Name,
+			StartDate: dbSprint.StartDate,
+			EndDate:   dbSprint.EndDate,
+			Committed: committed,
+			Completed: completed,
+		})
+		total += completed
+	}
+
+	if len(report.Sprints) > 0 {
+		report.Average = float64(total) / float64(len(report.Sprints))
+	}
+	return report, nil
+}
+
+// GetSprintReportContext always fails, because memory storage keeps no sprints
+func (tm *TaskManager) GetSprintReportContext(ctx context.Context, sprintID int, unit BurndownUnit) (*SprintReport, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, ErrSprintsUnavailable
+}
+
+// GetSprintReportContext returns the burndown and velocity report of a sprint.
+// Sprints are kept by the database, so they are available in database and
+// hybrid storage.
+func (htm *HybridTaskManager) GetSprintReportContext(ctx context.Context, sprintID int, unit BurndownUnit) (*SprintReport, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	htm.mu.RLock()
+	defer htm.mu.RUnlock()
+
+	if htm.storageType == MemoryStorage {
+		return htm.memoryManager.GetSprintReportContext(ctx, sprintID, unit)
+	}
+
+	repository := htm.repository.WithContext(ctx)
+	dbSprint, err := repository.GetSprint(sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildSprintReport(repository, dbSprint, unit, time.Now())
+}
@@ -40,6 +40,30 @@ type Task struct {
 	// Phase 2: Enhanced data model
 	Category    *Category `json:"category,omitempty"`
 	Tags        []Tag     `json:"tags,omitempty"`
+	Recurrence  *RecurrenceRule `json:"recurrence,omitempty"`
+	// Archived tasks are left out of every listing unless asked for
+	IsArchived  bool      `json:"is_archived"`
+	// DeletedAt is set while the task is in the trash; trashed tasks are hidden everywhere
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	// EstimateMinutes is the expected effort, compared with the tracked time in reports
+	EstimateMinutes *int  `json:"estimate_minutes,omitempty"`
+	// ProjectID is set for tasks that belong to a project
+	ProjectID   *int      `json:"project_id,omitempty"`
+	// SprintID is set while the task is planned into a sprint
+	SprintID    *int      `json:"sprint_id,omitempty"`
+	// HiddenUntil is set while the task is snoozed; snoozed tasks are left out
+	// of the default listings until then
+	HiddenUntil *time.Time `json:"hidden_until,omitempty"`
+	// CommentCount is the number of comments that have not been deleted
+	CommentCount int      `json:"comment_count,omitempty"`
+	// Assignees, CustomFields and Checklist are only loaded when a single task is read through UserManager
+	Assignees   []Assignee `json:"assignees,omitempty"`
+	CustomFields []CustomFieldValue `json:"custom_fields,omitempty"`
+	Checklist   []ChecklistItem `json:"checklist,omitempty"`
+	// Task hierarchy: Subtasks and Progress are only loaded by HierarchyManager.GetSubtree
+	ParentID    *int      `json:"parent_id,omitempty"`
+	Subtasks    []Task    `json:"subtasks,omitempty"`
+	Progress    *float64  `json:"progress,omitempty"`
 }
 
 // Category represents a task category
@@ -64,6 +88,9 @@ type Tag struct {
 type TaskManager struct {
 	tasks []Task
 	nextID int
+	workflow *Workflow
+	events *EventBus
+	journal Journal
 	mu     sync.RWMutex
 }
 
@@ -72,9 +99,32 @@ func NewTaskManager() *TaskManager {
 	return &TaskManager{
 		tasks:  make([]Task, 0),
 		nextID: 1,
+		workflow: DefaultWorkflow(),
+		journal: NewMemoryJournal(DefaultJournalLimit),
 	}
 }
 
+// SetWorkflow replaces the workflow that status changes must follow
+func (tm *TaskManager) SetWorkflow(workflow *Workflow) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.workflow = workflow
+}
+
+// GetWorkflow returns the workflow that status changes must follow
+func (tm *TaskManager) GetWorkflow() *Workflow {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	return tm.workflow
+}
+
+// SetEventBus sets the bus that task mutations are published to
+func (tm *TaskManager) SetEventBus(bus *EventBus) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.events = bus
+}
+
 // AddTask adds a new task to the manager
 func (tm *TaskManager) AddTask(title, description string, priority Priority, dueDate *time.Time) *Task {
 	tm.mu.Lock()
@@ -95,6 +145,22 @@ func (tm *TaskManager) AddTask(title, description string, priority Priority, due
 	tm.tasks = append(tm.tasks, task)
 	tm.nextID++
 	
+	tm.events.Publish(taskEvent(EventTaskCreated, task, nil))
+	tm.recordLocked(CommandCreate, task.ID, TaskChange{TaskID: task.ID, After: memorySnapshot(task)})
+	return &task
+}
+
+// addTaskLocked stores a fully built task under a new ID; tm.mu must be held
+func (tm *TaskManager) addTaskLocked(task Task) *Task {
+	now := time.Now()
+	task.ID = tm.nextID
+	task.CreatedAt = now
+	task.UpdatedAt = now
+	
+	tm.tasks = append(tm.tasks, task)
+	tm.nextID++
+	
+	tm.events.Publish(taskEvent(EventTaskCreated, task, nil))
 	return &task
 }
 
@@ -104,7 +170,7 @@ func (tm *TaskManager) GetTask(id int) (*Task, error) {
 	defer tm.mu.RUnlock()
 	
 	for i := range tm.tasks {
-		if tm.tasks[i].ID == id {
+		if tm.tasks[i].ID == id && tm.tasks[i].DeletedAt == nil {
 			return &tm.tasks[i], nil
 		}
 	}
@@ -118,9 +184,37 @@ func (tm *TaskManager) UpdateTaskStatus(id int, status Status) error {
 	defer tm.mu.Unlock()
 	
 	for i := range tm.tasks {
-		if tm.tasks[i].ID == id {
+		if tm.tasks[i].ID == id && tm.tasks[i].DeletedAt == nil {
+			if err := tm.workflow.CheckTransition(tm.tasks[i], status); err != nil {
+				return err
+			}
+			
+			before := tm.tasks[i]
+			oldStatus := tm.tasks[i].Status
+			wasCompleted := oldStatus == Completed
 			tm.tasks[i].Status = status
 			tm.tasks[i].UpdatedAt = time.Now()
+			if status == oldStatus {
+				return nil
+			}
+			
+			// Completing a recurring task schedules its next occurrence, which
+			// takes over the rule so that completing this task again after
+			// reopening it does not schedule another one
+			var next *Task
+			if status == Completed && !wasCompleted {
+				if next = nextOccurrence(tm.tasks[i], tm.tasks[i].UpdatedAt); next != nil {
+					tm.tasks[i].Recurrence = nil
+				}
+			}
+			
+			tm.events.Publish(statusChangedEvent(tm.tasks[i], nil, oldStatus))
+			changes := []TaskChange{{TaskID: id, Before: memorySnapshot(before), After: memorySnapshot(tm.tasks[i])}}
+			if next != nil {
+				added := tm.addTaskLocked(*next)
+				changes = append(changes, TaskChange{TaskID: added.ID, After: memorySnapshot(*added)})
+			}
+			tm.recordLocked(CommandStatus, id, changes...)
 			return nil
 		}
 	}
@@ -128,14 +222,46 @@ func (tm *TaskManager) UpdateTaskStatus(id int, status Status) error {
 	return fmt.Errorf("task with ID %d not found", id)
 }
 
-// DeleteTask removes a task by ID
+// UpdateTask applies a partial update to a task and returns the updated task.
+// An empty patch leaves the task as it is.
+func (tm *TaskManager) UpdateTask(id int, patch TaskPatch) (*Task, error) {
+	if err := patch.Validate(); err != nil {
+		return nil, err
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	for i := range tm.tasks {
+		if tm.tasks[i].ID == id && tm.tasks[i].DeletedAt == nil {
+			if patch.IsEmpty() {
+				unchanged := tm.tasks[i]
+				return &unchanged, nil
+			}
+			before := tm.tasks[i]
+			patch.apply(&tm.tasks[i])
+			updated := tm.tasks[i]
+			tm.events.Publish(taskEvent(EventTaskUpdated, updated, nil))
+			tm.recordLocked(CommandUpdate, id, TaskChange{TaskID: id, Before: memorySnapshot(before), After: memorySnapshot(updated)})
+			return &updated, nil
+		}
+	}
+
+	return nil, fmt.Errorf("task with ID %d not found", id)
+}
+
+// DeleteTask moves a task to the trash
 func (tm *TaskManager) DeleteTask(id int) error {
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
 	
 	for i := range tm.tasks {
-		if tm.tasks[i].ID == id {
-			tm.tasks = append(tm.tasks[:i], tm.tasks[i+1:]...)
+		if tm.tasks[i].ID == id && tm.tasks[i].DeletedAt == nil {
+			before := tm.tasks[i]
+			now := time.Now()
+			tm.tasks[i].DeletedAt = &now
+			tm.events.Publish(taskEvent(EventTaskDeleted, tm.tasks[i], nil))
+			tm.recordLocked(CommandDelete, id, TaskChange{TaskID: id, Before: memorySnapshot(before), After: memorySnapshot(tm.tasks[i])})
 			return nil
 		}
 	}
@@ -149,11 +275,26 @@ func (tm *TaskManager) GetAllTasks() []Task {
 	defer tm.mu.RUnlock()
 	
 	// Return a copy to prevent external modifications
-	tasks := make([]Task, len(tm.tasks))
-	copy(tasks, tm.tasks)
+	return tm.activeTasksLocked()
+}
+
+// activeTasksLocked returns a copy of the tasks that are neither archived nor
+// trashed; tm.mu must be held
+func (tm *TaskManager) activeTasksLocked() []Task {
+	tasks := make([]Task, 0, len(tm.tasks))
+	for _, task := range tm.tasks {
+		if task.isActive() {
+			tasks = append(tasks, task)
+		}
+	}
 	return tasks
 }
 
+// isActive reports whether a task shows up in the regular listings
+func (t Task) isActive() bool {
+	return !t.IsArchived && t.DeletedAt == nil && !t.IsSnoozed(time.Now())
+}
+
 // GetTasksByStatus returns tasks filtered by status
 func (tm *TaskManager) GetTasksByStatus(status Status) []Task {
 	tm.mu.RLock()
@@ -161,7 +302,7 @@ func (tm *TaskManager) GetTasksByStatus(status Status) []Task {
 	
 	var filtered []Task
 	for _, task := range tm.tasks {
-		if task.Status == status {
+		if task.Status == status && task.isActive() {
 			filtered = append(filtered, task)
 		}
 	}
@@ -176,7 +317,7 @@ func (tm *TaskManager) GetTasksByPriority(priority Priority) []Task {
 	
 	var filtered []Task
 	for _, task := range tm.tasks {
-		if task.Priority == priority {
+		if task.Priority == priority && task.isActive() {
 			filtered = append(filtered, task)
 		}
 	}
@@ -189,8 +330,7 @@ func (tm *TaskManager) SortTasksByPriority() []Task {
 	tm.mu.RLock()
 	defer tm.mu.RUnlock()
 	
-	tasks := make([]Task, len(tm.tasks))
-	copy(tasks, tm.tasks)
+	tasks := tm.activeTasksLocked()
 	
 	sort.Slice(tasks, func(i, j int) bool {
 		return tasks[i].Priority > tasks[j].Priority
@@ -208,7 +348,7 @@ func (tm *TaskManager) GetOverdueTasks() []Task {
 	var overdue []Task
 	
 	for _, task := range tm.tasks {
-		if task.DueDate != nil && task.DueDate.Before(now) && task.Status != Completed {
+		if task.DueDate != nil && task.DueDate.Before(now) && task.Status != Completed && task.isActive() {
 			overdue = append(overdue, task)
 		}
 	}
@@ -0,0 +1,160 @@
+package task
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// Context-aware (TaskManagerV2) methods of the in-memory TaskManager.
+// Memory operations cannot block, so the context is only checked up front.
+
+// validateNewTask applies the TaskPatch rules to the fields of a new task
+func validateNewTask(title string, priority Priority) error {
+	return TaskPatch{Title: &title, Priority: &priority}.Validate()
+}
+
+// AddTaskContext validates and adds a new task
+func (tm *TaskManager) AddTaskContext(ctx context.Context, title, description string, priority Priority, dueDate *time.Time) (*Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := validateNewTask(title, priority); err != nil {
+		return nil, err
+	}
+
+	return tm.AddTask(title, description, priority, dueDate), nil
+}
+
+// GetTaskContext retrieves a task by ID
+func (tm *TaskManager) GetTaskContext(ctx context.Context, id int) (*Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return tm.GetTask(id)
+}
+
+// UpdateTaskStatusContext updates the status of a task following the workflow
+func (tm *TaskManager) UpdateTaskStatusContext(ctx context.Context, id int, status Status) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return tm.UpdateTaskStatus(id, status)
+}
+
+// UpdateTaskContext applies a partial update to a task
+func (tm *TaskManager) UpdateTaskContext(ctx context.Context, id int, patch TaskPatch) (*Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return tm.UpdateTask(id, patch)
+}
+
+// DeleteTaskContext removes a task by ID
+func (tm *TaskManager) DeleteTaskContext(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return tm.DeleteTask(id)
+}
+
+// GetAllTasksContext returns all tasks
+func (tm *TaskManager) GetAllTasksContext(ctx context.Context) ([]Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return tm.GetAllTasks(), nil
+}
+
+// GetTasksByStatusContext returns tasks filtered by status
+func (tm *TaskManager) GetTasksByStatusContext(ctx context.Context, status Status) ([]Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return tm.GetTasksByStatus(status), nil
+}
+
+// GetTasksByPriorityContext returns tasks filtered by priority
+func (tm *TaskManager) GetTasksByPriorityContext(ctx context.Context, priority Priority) ([]Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return tm.GetTasksByPriority(priority), nil
+}
+
+// SortTasksByPriorityContext returns all tasks sorted by priority (highest first)
+func (tm *TaskManager) SortTasksByPriorityContext(ctx context.Context) ([]Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return tm.SortTasksByPriority(), nil
+}
+
+// GetOverdueTasksContext returns tasks that are past their due date
+func (tm *TaskManager) GetOverdueTasksContext(ctx context.Context) ([]Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return tm.GetOverdueTasks(), nil
+}
+
+// ArchiveTaskContext hides a task from every listing without deleting it
+func (tm *TaskManager) ArchiveTaskContext(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return tm.ArchiveTask(id)
+}
+
+// UnarchiveTaskContext brings an archived task back into the listings
+func (tm *TaskManager) UnarchiveTaskContext(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return tm.UnarchiveTask(id)
+}
+
+// GetArchivedTasksContext returns the archived tasks
+func (tm *TaskManager) GetArchivedTasksContext(ctx context.Context) ([]Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return tm.GetArchivedTasks(), nil
+}
+
+// RestoreTaskContext takes a task out of the trash
+func (tm *TaskManager) RestoreTaskContext(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return tm.RestoreTask(id)
+}
+
+// GetDeletedTasksContext returns the tasks in the trash
+func (tm *TaskManager) GetDeletedTasksContext(ctx context.Context) ([]Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return tm.GetDeletedTasks(), nil
+}
+
+// sortByPriority sorts tasks in place, highest priority first
+func sortByPriority(tasks []Task) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].Priority > tasks[j].Priority
+	})
+}
@@ -0,0 +1,153 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"learn-go-capstone/internal/database"
+)
+
+func TestTaskManagerV2ReportsErrors(t *testing.T) {
+	managers := map[string]TaskManagerV2{
+		"memory":   NewTaskManager(),
+		"database": NewHybridTaskManager(setupTestRepository(t), DatabaseStorage),
+	}
+
+	for name, tm := range managers {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			if _, err := tm.AddTaskContext(ctx, "  ", "", Medium, nil); err == nil {
+				t.Error("Expected error for an empty title")
+			}
+			if _, err := tm.AddTaskContext(ctx, "Bad priority", "", Priority(9), nil); err == nil {
+				t.Error("Expected error for an invalid priority")
+			}
+
+			low, err := tm.AddTaskContext(ctx, "Low", "", Low, nil)
+			if err != nil {
+				t.Fatalf("Failed to add task: %v", err)
+			}
+			if _, err := tm.AddTaskContext(ctx, "Urgent", "", Urgent, nil); err != nil {
+				t.Fatalf("Failed to add task: %v", err)
+			}
+
+			sorted, err := tm.SortTasksByPriorityContext(ctx)
+			if err != nil {
+				t.Fatalf("Failed to sort tasks: %v", err)
+			}
+			if len(sorted) != 2 || sorted[0].Priority != Urgent {
+				t.Errorf("Expected the urgent task first, got %+v", sorted)
+			}
+
+			if err := tm.DeleteTaskContext(ctx, low.ID); err != nil {
+				t.Fatalf("Failed to delete task: %v", err)
+			}
+			if _, err := tm.GetTaskContext(ctx, low.ID); err == nil {
+				t.Error("Expected error for a deleted task")
+			}
+		})
+	}
+}
+
+func TestTaskManagerV2CancelledContext(t *testing.T) {
+	managers := map[string]TaskManagerV2{
+		"memory":   NewTaskManager(),
+		"database": NewHybridTaskManager(setupTestRepository(t), DatabaseStorage),
+		"hybrid":   NewHybridTaskManager(setupTestRepository(t), HybridStorage),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for name, tm := range managers {
+		t.Run(name, func(t *testing.T) {
+			if _, err := tm.AddTaskContext(ctx, "Too late", "", Medium, nil); !errors.Is(err, context.Canceled) {
+				t.Errorf("Expected context.Canceled from AddTaskContext, got %v", err)
+			}
+			if _, err := tm.GetAllTasksContext(ctx); !errors.Is(err, context.Canceled) {
+				t.Errorf("Expected context.Canceled from GetAllTasksContext, got %v", err)
+			}
+			if err := tm.UpdateTaskStatusContext(ctx, 1, Completed); !errors.Is(err, context.Canceled) {
+				t.Errorf("Expected context.Canceled from UpdateTaskStatusContext, got %v", err)
+			}
+
+			tasks, err := tm.GetAllTasksContext(context.Background())
+			if err != nil {
+				t.Fatalf("Failed to list tasks: %v", err)
+			}
+			if len(tasks) != 0 {
+				t.Errorf("Expected no tasks to be stored, got %d", len(tasks))
+			}
+		})
+	}
+}
+
+func TestHybridContextMethodsReportDatabaseErrors(t *testing.T) {
+	db, err := database.Connect(&database.Config{
+		Driver: "sqlite3",
+		DSN:    fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()),
+	})
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	if err := database.NewMigrationManager(db).Migrate(); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	htm := NewHybridTaskManager(database.NewSQLiteRepository(db), HybridStorage)
+	ctx := context.Background()
+	stored, err := htm.AddTaskContext(ctx, "Stored", "", Medium, nil)
+	if err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+
+	database.Close(db)
+
+	if _, err := htm.AddTaskContext(ctx, "Lost", "", Medium, nil); err == nil {
+		t.Error("Expected AddTaskContext to report the database error")
+	}
+	title := "Renamed"
+	if _, err := htm.UpdateTaskContext(ctx, stored.ID, TaskPatch{Title: &title}); err == nil {
+		t.Error("Expected UpdateTaskContext to report the database error")
+	}
+	if err := htm.ArchiveTaskContext(ctx, stored.ID); err == nil {
+		t.Error("Expected ArchiveTaskContext to report the database error")
+	}
+	if err := htm.DeleteTaskContext(ctx, stored.ID); err == nil {
+		t.Error("Expected DeleteTaskContext to report the database error")
+	}
+	if _, err := htm.GetAllTasksContext(ctx); err == nil {
+		t.Error("Expected GetAllTasksContext to report the database error")
+	}
+	if loaded, err := htm.GetTaskContext(ctx, stored.ID); err != nil || loaded.Title != "Stored" {
+		t.Errorf("Expected the failed changes to leave the memory copy alone, got %+v (%v)", loaded, err)
+	}
+
+	// The methods without a context keep falling back to memory
+	if kept := htm.AddTask("Kept", "", Medium, nil); kept == nil || kept.ID == stored.ID {
+		t.Errorf("Expected the task to be kept in memory, got %+v", kept)
+	}
+	if tasks := htm.GetAllTasks(); len(tasks) != 2 {
+		t.Errorf("Expected 2 tasks in memory, got %d", len(tasks))
+	}
+	if err := htm.DeleteTask(stored.ID); err != nil {
+		t.Errorf("Expected DeleteTask to fall back to memory, got %v", err)
+	}
+}
+
+func TestRepositoryHonoursContext(t *testing.T) {
+	repository := setupTestRepository(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := repository.WithContext(ctx).GetAllTasks(); err == nil {
+		t.Error("Expected a query with a cancelled context to fail")
+	}
+	if _, err := repository.GetAllTasks(); err != nil {
+		t.Errorf("Expected the unbound repository to keep working, got %v", err)
+	}
+}
@@ -0,0 +1,419 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"learn-go-capstone/internal/database"
+)
+
+// maxTemplateTasks bounds the number of tasks a template creates
+const maxTemplateTasks = 50
+
+// maxDueOffsetDays bounds the due offset of a template task in either direction
+const maxDueOffsetDays = 3650
+
+// Template describes tasks that are created together, such as the steps of
+// an onboarding or a release. It belongs to its owner or, when ProjectID is
+// set, to a project, whose tasks it creates.
+type Template struct {
+	ID          int            `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	OwnerID     *int           `json:"owner_id,omitempty"`
+	ProjectID   *int           `json:"project_id,omitempty"`
+	Tasks       []TemplateTask `json:"tasks"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// TemplateTask is a task of a template. DueOffsetDays places the due date
+// that many days after the day the template is instantiated. Parent makes
+// the task a subtask and DependsOn makes it depend on other tasks; both refer
+// to earlier tasks of the template by index.
+type TemplateTask struct {
+	Title         string   `json:"title"`
+	Description   string   `json:"description,omitempty"`
+	Priority      Priority `json:"priority"`
+	CategoryID    *int     `json:"category_id,omitempty"`
+	TagIDs        []int    `json:"tag_ids,omitempty"`
+	DueOffsetDays *int     `json:"due_offset_days,omitempty"`
+	Checklist     []string `json:"checklist,omitempty"`
+	Parent        *int     `json:"parent,omitempty"`
+	DependsOn     []int    `json:"depends_on,omitempty"`
+}
+
+func convertFromDatabaseTemplate(dt *database.TaskTemplate) Template {
+	template := Template{
+		ID:          dt.ID,
+		Name:        dt.Name,
+		Description: dt.Description,
+		OwnerID:     dt.OwnerID,
+		ProjectID:   dt.ProjectID,
+		Tasks:       make([]TemplateTask, len(dt.Tasks)),
+		CreatedAt:   dt.CreatedAt,
+		UpdatedAt:   dt.UpdatedAt,
+	}
+	for i, task := range dt.Tasks {
+		template.Tasks[i] = TemplateTask{
+			Title:         task.Title,
+			Description:   task.Description,
+			Priority:      Priority(task.Priority),
+			CategoryID:    task.CategoryID,
+			TagIDs:        task.TagIDs,
+			DueOffsetDays: task.DueOffsetDays,
+			Checklist:     task.Checklist,
+			Parent:        task.Parent,
+			DependsOn:     task.DependsOn,
+		}
+	}
+	return template
+}
+
+func convertToDatabaseTemplateTasks(tasks []TemplateTask) []database.TemplateTask {
+	dbTasks := make([]database.TemplateTask, len(tasks))
+	for i, task := range tasks {
+		dbTasks[i] = database.TemplateTask{
+			Title:         task.Title,
+			Description:   task.Description,
+			Priority:      int(task.Priority),
+			CategoryID:    task.CategoryID,
+			TagIDs:        task.TagIDs,
+			DueOffsetDays: task.DueOffsetDays,
+			Checklist:     task.Checklist,
+			Parent:        task.Parent,
+			DependsOn:     task.DependsOn,
+		}
+	}
+	return dbTasks
+}
+
+// TemplateManager manages task templates. Everyone who can see a template
+// can instantiate it; only the template owner and the owners of its project
+// can change or delete it.
+type TemplateManager struct {
+	repository database.Repository
+	events     *EventBus
+}
+
+// NewTemplateManager creates a new template manager
+func NewTemplateManager(repository database.Repository) *TemplateManager {
+	return &TemplateManager{repository: repository}
+}
+
+// SetEventBus sets the bus that the creation of instantiated tasks is published to
+func (tm *TemplateManager) SetEventBus(bus *EventBus) {
+	tm.events = bus
+}
+
+// WithContext returns a copy of the template manager whose queries run with ctx
+func (tm *TemplateManager) WithContext(ctx context.Context) *TemplateManager {
+	if tm.repository == nil {
+		return tm
+	}
+	bound := *tm
+	bound.repository = tm.repository.WithContext(ctx)
+	return &bound
+}
+
+// checkProject returns an error unless the user is a member of the project
+func (tm *TemplateManager) checkProject(userID int, projectID *int) error {
+	if projectID == nil {
+		return nil
+	}
+
+	if _, err := tm.repository.GetProject(*projectID); err != nil {
+		return err
+	}
+	role, err := projectRole(tm.repository, userID, *projectID)
+	if err != nil {
+		return err
+	}
+	if role == "" {
+		return fmt.Errorf("project with ID %d not found", *projectID)
+	}
+	return nil
+}
+
+// userTemplate returns a template the user can see
+func (tm *TemplateManager) userTemplate(userID, templateID int) (*database.TaskTemplate, error) {
+	dbTemplate, err := tm.repository.GetTemplate(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := dbTemplate.ProjectID == nil && dbTemplate.OwnerID != nil && *dbTemplate.OwnerID == userID
+	if dbTemplate.ProjectID != nil {
+		role, err := projectRole(tm.repository, userID, *dbTemplate.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		visible = role != ""
+	}
+
+	if !visible {
+		return nil, fmt.Errorf("template with ID %d not found", templateID)
+	}
+	return dbTemplate, nil
+}
+
+// checkTemplateOwner returns an error unless the user owns the template or its project
+func (tm *TemplateManager) checkTemplateOwner(userID int, dbTemplate *database.TaskTemplate) error {
+	if dbTemplate.OwnerID != nil && *dbTemplate.OwnerID == userID {
+		return nil
+	}
+
+	if dbTemplate.ProjectID != nil {
+		role, err := projectRole(tm.repository, userID, *dbTemplate.ProjectID)
+		if err != nil {
+			return err
+		}
+		if role == ProjectOwner {
+			return nil
+		}
+	}
+
+	return errors.New("access denied: only the template owner or a project owner can change the template")
+}
+
+// checkTemplate trims the name, description and task texts of a template and
+// validates them. Categories and tags must still exist and belong to the
+// project of the template, or be global for a personal template.
+func (tm *TemplateManager) checkTemplate(dbTemplate *database.TaskTemplate) error {
+	dbTemplate.Name = strings.TrimSpace(dbTemplate.Name)
+	dbTemplate.Description = strings.TrimSpace(dbTemplate.Description)
+	if dbTemplate.Name == "" {
+		return errors.New("invalid template: name cannot be empty")
+	}
+	if len([]rune(dbTemplate.Name)) > maxBoardNameLength {
+		return fmt.Errorf("invalid template: name cannot be longer than %d characters", maxBoardNameLength)
+	}
+	if len(dbTemplate.Tasks) == 0 {
+		return errors.New("invalid template: a template needs at least one task")
+	}
+	if len(dbTemplate.Tasks) > maxTemplateTasks {
+		return fmt.Errorf("invalid template: a template cannot have more than %d tasks", maxTemplateTasks)
+	}
+
+	for i := range dbTemplate.Tasks {
+		if err := tm.checkTemplateTask(dbTemplate, i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkTemplateTask validates the task of a template at index i
+func (tm *TemplateManager) checkTemplateTask(dbTemplate *database.TaskTemplate, i int) error {
+	task := &dbTemplate.Tasks[i]
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("invalid template task %d: %s", i, fmt.Sprintf(format, args...))
+	}
+
+	task.Title = strings.TrimSpace(task.Title)
+	if task.Title == "" {
+		return invalid("title cannot be empty")
+	}
+	if task.Priority < int(Low) || task.Priority > int(Urgent) {
+		return invalid("priority must be between %d and %d", Low, Urgent)
+	}
+	if task.DueOffsetDays != nil && (*task.DueOffsetDays < -maxDueOffsetDays || *task.DueOffsetDays > maxDueOffsetDays) {
+		return invalid("due offset cannot be more than %d days", maxDueOffsetDays)
+	}
+
+	if len(task.Checklist) > maxChecklistItems {
+		return invalid("a checklist cannot have more than %d items", maxChecklistItems)
+	}
+	for j, text := range task.Checklist {
+		text, err := checkChecklistText(text)
+		if err != nil {
+			return invalid("%v", err)
+		}
+		task.Checklist[j] = text
+	}
+
+	if task.Parent != nil && (*task.Parent < 0 || *task.Parent >= i) {
+		return invalid("parent must be an earlier task")
+	}
+	seen := make(map[int]bool, len(task.DependsOn))
+	for _, index := range task.DependsOn {
+		if index < 0 || index >= i {
+			return invalid("dependencies must be earlier tasks")
+		}
+		if seen[index] {
+			return invalid("depends on task %d twice", index)
+		}
+		seen[index] = true
+	}
+
+	if task.CategoryID != nil {
+		category, err := tm.repository.GetCategory(*task.CategoryID)
+		if err != nil || !sameProject(category.ProjectID, dbTemplate.ProjectID) {
+			return invalid("category %d is not available", *task.CategoryID)
+		}
+	}
+	seen = make(map[int]bool, len(task.TagIDs))
+	for _, tagID := range task.TagIDs {
+		tag, err := tm.repository.GetTag(tagID)
+		if err != nil || !sameProject(tag.ProjectID, dbTemplate.ProjectID) {
+			return invalid("tag %d is not available", tagID)
+		}
+		if seen[tagID] {
+			return invalid("has tag %d twice", tagID)
+		}
+		seen[tagID] = true
+	}
+	return nil
+}
+
+// CreateTemplate creates a template owned by the user, for their own tasks
+// or, when projectID is set, for the tasks of one of their projects
+func (tm *TemplateManager) CreateTemplate(userID int, name, description string, tasks []TemplateTask, projectID *int) (*Template, error) {
+	if err := tm.checkProject(userID, projectID); err != nil {
+		return nil, err
+	}
+
+	dbTemplate := &database.TaskTemplate{
+		Name:        name,
+		Description: description,
+		OwnerID:     &userID,
+		ProjectID:   projectID,
+		Tasks:       convertToDatabaseTemplateTasks(tasks),
+	}
+	if err := tm.checkTemplate(dbTemplate); err != nil {
+		return nil, err
+	}
+
+	if err := tm.repository.CreateTemplate(dbTemplate); err != nil {
+		return nil, err
+	}
+
+	template := convertFromDatabaseTemplate(dbTemplate)
+	return &template, nil
+}
+
+// GetTemplates returns the templates of a project of the user or, when
+// projectID is nil, their personal templates, by name
+func (tm *TemplateManager) GetTemplates(userID int, projectID *int) ([]Template, error) {
+	if err := tm.checkProject(userID, projectID); err != nil {
+		return nil, err
+	}
+
+	dbTemplates, err := tm.repository.GetTemplates(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]Template, len(dbTemplates))
+	for i := range dbTemplates {
+		templates[i] = convertFromDatabaseTemplate(&dbTemplates[i])
+	}
+	return templates, nil
+}
+
+// GetTemplate returns a template the user can see
+func (tm *TemplateManager) GetTemplate(userID, templateID int) (*Template, error) {
+	dbTemplate, err := tm.userTemplate(userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	template := convertFromDatabaseTemplate(dbTemplate)
+	return &template, nil
+}
+
+// UpdateTemplate replaces the name, description and tasks of a template
+func (tm *TemplateManager) UpdateTemplate(userID, templateID int, name, description string, tasks []TemplateTask) (*Template, error) {
+	dbTemplate, err := tm.userTemplate(userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tm.checkTemplateOwner(userID, dbTemplate); err != nil {
+		return nil, err
+	}
+
+	dbTemplate.Name = name
+	dbTemplate.Description = description
+	dbTemplate.Tasks = convertToDatabaseTemplateTasks(tasks)
+	if err := tm.checkTemplate(dbTemplate); err != nil {
+		return nil, err
+	}
+
+	if err := tm.repository.UpdateTemplate(dbTemplate); err != nil {
+		return nil, err
+	}
+
+	template := convertFromDatabaseTemplate(dbTemplate)
+	return &template, nil
+}
+
+// DeleteTemplate deletes a template. Tasks created from it are kept.
+func (tm *TemplateManager) DeleteTemplate(userID, templateID int) error {
+	dbTemplate, err := tm.userTemplate(userID, templateID)
+	if err != nil {
+		return err
+	}
+	if err := tm.checkTemplateOwner(userID, dbTemplate); err != nil {
+		return err
+	}
+
+	return tm.repository.DeleteTemplate(templateID)
+}
+
+// Instantiate creates the tasks of a template for the user in one
+// transaction, in the order of the template, and returns them. Due dates are
+// placed relative to the calendar date of start, and tasks of a project
+// template join its project.
+func (tm *TemplateManager) Instantiate(userID, templateID int, start time.Time) ([]Task, error) {
+	dbTemplate, err := tm.userTemplate(userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	// Categories and tags may have gone since the template was saved
+	if err := tm.checkTemplate(dbTemplate); err != nil {
+		return nil, err
+	}
+
+	start = sprintDate(start)
+	now := time.Now()
+	newTasks := make([]database.NewTask, len(dbTemplate.Tasks))
+	for i, templateTask := range dbTemplate.Tasks {
+		dbTask := &database.DatabaseTask{
+			Title:       templateTask.Title,
+			Description: templateTask.Description,
+			Priority:    templateTask.Priority,
+			Status:      int(Pending),
+			CreatedAt:   now,
+			UpdatedAt:   now,
+			UserID:      &userID,
+			CategoryID:  templateTask.CategoryID,
+			ProjectID:   dbTemplate.ProjectID,
+		}
+		if templateTask.DueOffsetDays != nil {
+			dueDate := start.AddDate(0, 0, *templateTask.DueOffsetDays)
+			dbTask.DueDate = &dueDate
+		}
+
+		newTasks[i] = database.NewTask{
+			Task:      dbTask,
+			TagIDs:    templateTask.TagIDs,
+			Checklist: templateTask.Checklist,
+			Parent:    templateTask.Parent,
+			DependsOn: templateTask.DependsOn,
+		}
+	}
+
+	if err := tm.repository.CreateTasks(newTasks); err != nil {
+		return nil, err
+	}
+
+	tasks := make([]Task, len(newTasks))
+	for i, newTask := range newTasks {
+		tm.events.Publish(databaseTaskEvent(EventTaskCreated, newTask.Task))
+		tasks[i] = convertFromDatabaseTask(newTask.Task)
+	}
+	return tasks, nil
+}
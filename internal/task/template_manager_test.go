@@ -0,0 +1,175 @@
+package task
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTemplates(t *testing.T) {
+	repository := setupTestRepository(t)
+	um := NewUserManager(repository)
+	cm := NewCategoryManager(repository)
+	pm := NewProjectManager(repository)
+	tm := NewTemplateManager(repository)
+
+	owner, err := um.RegisterUser("owner", "owner@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	member, err := um.RegisterUser("member", "member@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	outsider, err := um.RegisterUser("outsider", "outsider@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+
+	project, err := pm.CreateProject(owner.ID, "Launch", "", ProjectSettings{})
+	if err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	if _, err := pm.SetMember(owner.ID, project.ID, member.Username, ProjectMember); err != nil {
+		t.Fatalf("Failed to add member: %v", err)
+	}
+	docs, err := cm.CreateProjectCategory(project.ID, "Docs", "", "")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	release, err := cm.CreateProjectTag(project.ID, "release", "")
+	if err != nil {
+		t.Fatalf("Failed to create tag: %v", err)
+	}
+	global, err := cm.CreateTag("global", "")
+	if err != nil {
+		t.Fatalf("Failed to create tag: %v", err)
+	}
+
+	zero, three := 0, 3
+	tasks := []TemplateTask{
+		{Title: "Release", Priority: High, DueOffsetDays: &three, TagIDs: []int{release.ID}},
+		{Title: " Write notes ", Priority: Medium, CategoryID: &docs.ID, Parent: &zero, Checklist: []string{"Draft", " Review "}},
+		{Title: "Publish", Priority: Urgent, DueOffsetDays: &three, Parent: &zero, DependsOn: []int{1}},
+	}
+
+	invalid := [][]TemplateTask{
+		nil,
+		{{Title: " ", Priority: Low}},
+		{{Title: "Release", Priority: 9}},
+		{{Title: "Release", Priority: Low, Parent: &zero}},
+		{{Title: "Release", Priority: Low}, {Title: "Notes", Priority: Low, DependsOn: []int{1}}},
+		{{Title: "Release", Priority: Low}, {Title: "Notes", Priority: Low, DependsOn: []int{0, 0}}},
+		{{Title: "Release", Priority: Low, Checklist: []string{""}}},
+		{{Title: "Release", Priority: Low, TagIDs: []int{global.ID}}},
+	}
+	for _, tt := range invalid {
+		if _, err := tm.CreateTemplate(owner.ID, "Release", "", tt, &project.ID); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+			t.Errorf("Expected invalid error for %+v, got %v", tt, err)
+		}
+	}
+	if _, err := tm.CreateTemplate(owner.ID, "Release", "", tasks, nil); err == nil {
+		t.Error("Expected project categories and tags to be unavailable to personal templates")
+	}
+	if _, err := tm.CreateTemplate(outsider.ID, "Release", "", tasks, &project.ID); err == nil || !strings.HasSuffix(err.Error(), "not found") {
+		t.Errorf("Expected outsiders not to see the project, got %v", err)
+	}
+
+	template, err := tm.CreateTemplate(member.ID, " Release ", "Ship it", tasks, &project.ID)
+	if err != nil {
+		t.Fatalf("Failed to create template: %v", err)
+	}
+	if template.Name != "Release" || len(template.Tasks) != 3 || template.Tasks[1].Title != "Write notes" || template.Tasks[1].Checklist[1] != "Review" {
+		t.Errorf("Expected a trimmed template, got %+v", template)
+	}
+
+	templates, err := tm.GetTemplates(owner.ID, &project.ID)
+	if err != nil || len(templates) != 1 {
+		t.Errorf("Expected the project template, got %+v (%v)", templates, err)
+	}
+	if templates, _ := tm.GetTemplates(owner.ID, nil); len(templates) != 0 {
+		t.Errorf("Expected no personal templates, got %+v", templates)
+	}
+	if _, err := tm.GetTemplate(outsider.ID, template.ID); err == nil || !strings.HasSuffix(err.Error(), "not found") {
+		t.Errorf("Expected outsiders not to see the template, got %v", err)
+	}
+
+	// Only the template owner and project owners can change a template
+	if _, err := pm.SetMember(owner.ID, project.ID, outsider.Username, ProjectMember); err != nil {
+		t.Fatalf("Failed to add member: %v", err)
+	}
+	if _, err := tm.UpdateTemplate(outsider.ID, template.ID, "Mine", "", tasks); err == nil || !strings.HasPrefix(err.Error(), "access denied: only") {
+		t.Errorf("Expected other members not to change the template, got %v", err)
+	}
+	if _, err := tm.UpdateTemplate(owner.ID, template.ID, "Release", "Ship it", tasks[:2]); err != nil {
+		t.Errorf("Expected project owners to change the template: %v", err)
+	}
+	if _, err := tm.UpdateTemplate(member.ID, template.ID, "Release", "Ship it", tasks); err != nil {
+		t.Fatalf("Failed to update template: %v", err)
+	}
+
+	start := time.Date(2024, time.March, 1, 15, 30, 0, 0, time.UTC)
+	created, err := tm.Instantiate(outsider.ID, template.ID, start)
+	if err != nil {
+		t.Fatalf("Failed to instantiate template: %v", err)
+	}
+	if len(created) != 3 {
+		t.Fatalf("Expected 3 tasks, got %+v", created)
+	}
+
+	wantDue := time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC)
+	root, err := um.GetVisibleTask(outsider.ID, created[0].ID)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if root.ProjectID == nil || *root.ProjectID != project.ID || root.Priority != High || root.DueDate == nil || !root.DueDate.Equal(wantDue) {
+		t.Errorf("Expected a project task due three days after the start, got %+v", root)
+	}
+	if tags, err := repository.GetTaskTags(root.ID); err != nil || len(tags) != 1 || tags[0].ID != release.ID {
+		t.Errorf("Expected the task to be tagged, got %+v (%v)", tags, err)
+	}
+
+	notes, err := um.GetVisibleTask(outsider.ID, created[1].ID)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if notes.ParentID == nil || *notes.ParentID != root.ID || notes.DueDate != nil {
+		t.Errorf("Expected a subtask without due date, got %+v", notes)
+	}
+	if dbNotes, err := repository.GetTask(notes.ID); err != nil || dbNotes.CategoryID == nil || *dbNotes.CategoryID != docs.ID {
+		t.Errorf("Expected the subtask to be categorized, got %+v (%v)", dbNotes, err)
+	}
+	if len(notes.Checklist) != 2 || notes.Checklist[0].Text != "Draft" || notes.Checklist[1].Position != 1 || notes.Checklist[0].Done {
+		t.Errorf("Expected the checklist of the template, got %+v", notes.Checklist)
+	}
+
+	dependencies, err := repository.GetTaskDependencies(created[2].ID)
+	if err != nil || len(dependencies) != 1 || dependencies[0].DependsOnTaskID != notes.ID {
+		t.Errorf("Expected the last task to depend on the notes, got %+v (%v)", dependencies, err)
+	}
+
+	// Nothing is created when a category of the template has gone
+	if err := cm.DeleteCategory(docs.ID); err != nil {
+		t.Fatalf("Failed to delete category: %v", err)
+	}
+	before, _ := um.GetUserTasks(outsider.ID)
+	if _, err := tm.Instantiate(outsider.ID, template.ID, start); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+		t.Errorf("Expected invalid error for a deleted category, got %v", err)
+	}
+	if after, _ := um.GetUserTasks(outsider.ID); len(after) != len(before) {
+		t.Errorf("Expected no tasks to be created, got %d instead of %d", len(after), len(before))
+	}
+
+	if err := tm.DeleteTemplate(outsider.ID, template.ID); err == nil || !strings.HasPrefix(err.Error(), "access denied: only") {
+		t.Errorf("Expected other members not to delete the template, got %v", err)
+	}
+	if err := tm.DeleteTemplate(member.ID, template.ID); err != nil {
+		t.Fatalf("Failed to delete template: %v", err)
+	}
+	if _, err := tm.GetTemplate(member.ID, template.ID); err == nil {
+		t.Error("Expected the template to be deleted")
+	}
+	if _, err := um.GetVisibleTask(outsider.ID, created[0].ID); err != nil {
+		t.Errorf("Expected tasks created from the template to be kept: %v", err)
+	}
+}
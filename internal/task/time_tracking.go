@@ -0,0 +1,364 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"learn-go-capstone/internal/database"
+)
+
+// ErrTimeTrackingUnavailable is returned when time is tracked with memory
+// storage, which does not keep time entries
+var ErrTimeTrackingUnavailable = errors.New("time tracking is only available in database storage")
+
+// TimeEntry is time spent on a task, either measured by a timer or logged by hand
+type TimeEntry struct {
+	ID     int  `json:"id"`
+	TaskID int  `json:"task_id"`
+	UserID *int `json:"user_id,omitempty"` // nil for time tracked from the CLI
+	// EndedAt is nil while the timer is running
+	StartedAt time.Time     `json:"started_at"`
+	EndedAt   *time.Time    `json:"ended_at,omitempty"`
+	Duration  time.Duration `json:"duration"`
+	Note      string        `json:"note,omitempty"`
+}
+
+// IsRunning reports whether the entry is a timer that has not been stopped yet
+func (e TimeEntry) IsRunning() bool {
+	return e.EndedAt == nil
+}
+
+func convertFromTimeEntry(entry *database.TimeEntry) TimeEntry {
+	return TimeEntry{
+		ID:        entry.ID,
+		TaskID:    entry.TaskID,
+		UserID:    entry.UserID,
+		StartedAt: entry.StartedAt,
+		EndedAt:   entry.EndedAt,
+		Duration:  time.Duration(entry.DurationSeconds) * time.Second,
+		Note:      entry.Note,
+	}
+}
+
+// TaskTime compares the time tracked on a task with its estimate
+type TaskTime struct {
+	TaskID          int  `json:"task_id"`
+	EstimateMinutes *int `json:"estimate_minutes,omitempty"`
+	// Tracked is the total of the completed entries; a running timer is not counted
+	Tracked time.Duration `json:"tracked"`
+	Entries []TimeEntry   `json:"entries"`
+}
+
+func newTaskTime(dbTask *database.DatabaseTask, entries []database.TimeEntry) *TaskTime {
+	taskTime := &TaskTime{
+		TaskID:          dbTask.ID,
+		EstimateMinutes: dbTask.EstimateMinutes,
+		Entries:         make([]TimeEntry, len(entries)),
+	}
+	for i := range entries {
+		taskTime.Entries[i] = convertFromTimeEntry(&entries[i])
+		taskTime.Tracked += taskTime.Entries[i].Duration
+	}
+	return taskTime
+}
+
+// validateTimeLog checks a manually logged entry
+func validateTimeLog(startedAt time.Time, duration time.Duration) error {
+	if duration < time.Minute {
+		return fmt.Errorf("invalid duration %s: must be at least a minute", duration)
+	}
+	if startedAt.After(time.Now()) {
+		return errors.New("logged time cannot start in the future")
+	}
+	return nil
+}
+
+// ReportGrouping selects how a time report aggregates entries
+type ReportGrouping string
+
+const (
+	GroupByTask     ReportGrouping = "task"
+	GroupByCategory ReportGrouping = "category"
+	GroupByUser     ReportGrouping = "user"
+)
+
+// ParseReportGrouping parses "task", "category" or "user"
+func ParseReportGrouping(s string) (ReportGrouping, error) {
+	switch grouping := ReportGrouping(s); grouping {
+	case GroupByTask, GroupByCategory, GroupByUser:
+		return grouping, nil
+	default:
+		return "", fmt.Errorf("invalid report grouping %q: must be task, category or user", s)
+	}
+}
+
+// TimeReportRow is the time tracked for one task, category or user
+type TimeReportRow struct {
+	// GroupID is nil for uncategorized tasks and for time tracked from the CLI
+	GroupID *int          `json:"group_id,omitempty"`
+	Name    string        `json:"name"`
+	Tracked time.Duration `json:"tracked"`
+	// EstimateMinutes is the sum of the estimates of the tasks worked on
+	EstimateMinutes int `json:"estimate_minutes"`
+	Tasks           int `json:"tasks"`
+	Entries         int `json:"entries"`
+}
+
+// buildTimeReport aggregates the completed entries that started in [from, to)
+// on the tasks accepted by include
+func buildTimeReport(repository database.Repository, grouping ReportGrouping, from, to time.Time, include func(*database.DatabaseTask) bool) ([]TimeReportRow, error) {
+	if !to.After(from) {
+		return nil, errors.New("report range must end after it starts")
+	}
+
+	entries, err := repository.GetTimeEntriesBetween(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make(map[int]*database.DatabaseTask)
+	rows := make(map[int]*TimeReportRow)
+	rowTasks := make(map[int]map[int]bool)
+	var order []int
+
+	for _, entry := range entries {
+		dbTask, loaded := tasks[entry.TaskID]
+		if !loaded {
+			dbTask, err = repository.GetTask(entry.TaskID)
+			if err != nil {
+				return nil, err
+			}
+			tasks[entry.TaskID] = dbTask
+		}
+		if !include(dbTask) {
+			continue
+		}
+
+		var groupID *int
+		switch grouping {
+		case GroupByTask:
+			groupID = &dbTask.ID
+		case GroupByCategory:
+			groupID = dbTask.CategoryID
+		case GroupByUser:
+			groupID = entry.UserID
+		}
+
+		// 0 is never a valid ID, so it keys the entries without a group
+		key := 0
+		if groupID != nil {
+			key = *groupID
+		}
+
+		row, exists := rows[key]
+		if !exists {
+			name, err := reportGroupName(repository, grouping, dbTask, groupID)
+			if err != nil {
+				return nil, err
+			}
+			row = &TimeReportRow{GroupID: groupID, Name: name}
+			rows[key] = row
+			rowTasks[key] = make(map[int]bool)
+			order = append(order, key)
+		}
+
+		row.Tracked += time.Duration(entry.DurationSeconds) * time.Second
+		row.Entries++
+		if !rowTasks[key][dbTask.ID] {
+			rowTasks[key][dbTask.ID] = true
+			row.Tasks++
+			if dbTask.EstimateMinutes != nil {
+				row.EstimateMinutes += *dbTask.EstimateMinutes
+			}
+		}
+	}
+
+	report := make([]TimeReportRow, len(order))
+	for i, key := range order {
+		report[i] = *rows[key]
+	}
+	sort.SliceStable(report, func(i, j int) bool {
+		return report[i].Tracked > report[j].Tracked
+	})
+
+	return report, nil
+}
+
+// reportGroupName names a row of a time report
+func reportGroupName(repository database.Repository, grouping ReportGrouping, dbTask *database.DatabaseTask, groupID *int) (string, error) {
+	switch {
+	case grouping == GroupByTask:
+		return dbTask.Title, nil
+	case grouping == GroupByCategory && groupID == nil:
+		return "Uncategorized", nil
+	case grouping == GroupByCategory:
+		category, err := repository.GetCategory(*groupID)
+		if err != nil {
+			return "", err
+		}
+		return category.Name, nil
+	case groupID == nil:
+		return "Unattributed", nil
+	default:
+		user, err := repository.GetUser(*groupID)
+		if err != nil {
+			return "", err
+		}
+		return user.Username, nil
+	}
+}
+
+// StartTimerContext always fails, because memory storage keeps no time entries
+func (tm *TaskManager) StartTimerContext(ctx context.Context, taskID int, note string) (*TimeEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, ErrTimeTrackingUnavailable
+}
+
+// StopTimerContext always fails, because memory storage keeps no time entries
+func (tm *TaskManager) StopTimerContext(ctx context.Context) (*TimeEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, ErrTimeTrackingUnavailable
+}
+
+// LogTimeContext always fails, because memory storage keeps no time entries
+func (tm *TaskManager) LogTimeContext(ctx context.Context, taskID int, startedAt time.Time, duration time.Duration, note string) (*TimeEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, ErrTimeTrackingUnavailable
+}
+
+// GetTaskTimeContext always fails, because memory storage keeps no time entries
+func (tm *TaskManager) GetTaskTimeContext(ctx context.Context, taskID int) (*TaskTime, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, ErrTimeTrackingUnavailable
+}
+
+// Time tracked through the HybridTaskManager is not attributed to a user, so
+// there is a single running timer for the whole task manager. Time entries are
+// kept by the database, so they are available in database and hybrid storage.
+
+// StartTimerContext starts a timer on a task. Only one timer can run at a time.
+func (htm *HybridTaskManager) StartTimerContext(ctx context.Context, taskID int, note string) (*TimeEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	htm.mu.Lock()
+	defer htm.mu.Unlock()
+
+	if htm.storageType == MemoryStorage {
+		return htm.memoryManager.StartTimerContext(ctx, taskID, note)
+	}
+
+	repository := htm.repository.WithContext(ctx)
+	if _, err := repository.GetTask(taskID); err != nil {
+		return nil, err
+	}
+
+	entry := &database.TimeEntry{TaskID: taskID, Note: note}
+	if err := repository.StartTimer(entry); err != nil {
+		return nil, err
+	}
+
+	timeEntry := convertFromTimeEntry(entry)
+	return &timeEntry, nil
+}
+
+// StopTimerContext stops the running timer and returns the completed entry
+func (htm *HybridTaskManager) StopTimerContext(ctx context.Context) (*TimeEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	htm.mu.Lock()
+	defer htm.mu.Unlock()
+
+	if htm.storageType == MemoryStorage {
+		return htm.memoryManager.StopTimerContext(ctx)
+	}
+
+	entry, err := htm.repository.WithContext(ctx).StopTimer(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	timeEntry := convertFromTimeEntry(entry)
+	return &timeEntry, nil
+}
+
+// LogTimeContext records time spent on a task without running a timer
+func (htm *HybridTaskManager) LogTimeContext(ctx context.Context, taskID int, startedAt time.Time, duration time.Duration, note string) (*TimeEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	htm.mu.Lock()
+	defer htm.mu.Unlock()
+
+	if htm.storageType == MemoryStorage {
+		return htm.memoryManager.LogTimeContext(ctx, taskID, startedAt, duration, note)
+	}
+
+	if err := validateTimeLog(startedAt, duration); err != nil {
+		return nil, err
+	}
+
+	repository := htm.repository.WithContext(ctx)
+	if _, err := repository.GetTask(taskID); err != nil {
+		return nil, err
+	}
+
+	entry := &database.TimeEntry{
+		TaskID:          taskID,
+		StartedAt:       startedAt,
+		DurationSeconds: int(duration / time.Second),
+		Note:            note,
+	}
+	if err := repository.CreateTimeEntry(entry); err != nil {
+		return nil, err
+	}
+
+	timeEntry := convertFromTimeEntry(entry)
+	return &timeEntry, nil
+}
+
+// GetTaskTimeContext returns the time entries of a task together with its estimate
+func (htm *HybridTaskManager) GetTaskTimeContext(ctx context.Context, taskID int) (*TaskTime, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	htm.mu.RLock()
+	defer htm.mu.RUnlock()
+
+	if htm.storageType == MemoryStorage {
+		return htm.memoryManager.GetTaskTimeContext(ctx, taskID)
+	}
+
+	repository := htm.repository.WithContext(ctx)
+	dbTask, err := repository.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := repository.GetTimeEntries(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTaskTime(dbTask, entries), nil
+}
@@ -0,0 +1,194 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHybridTimeTracking(t *testing.T) {
+	repository := setupTestRepository(t)
+	htm := NewHybridTaskManager(repository, DatabaseStorage)
+	ctx := context.Background()
+
+	created, err := htm.AddTaskContext(ctx, "Invoice", "", Medium, nil)
+	if err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+	estimate := 90
+	if _, err := htm.UpdateTaskContext(ctx, created.ID, TaskPatch{EstimateMinutes: &estimate}); err != nil {
+		t.Fatalf("Failed to set estimate: %v", err)
+	}
+	other, err := htm.AddTaskContext(ctx, "Timesheet", "", Low, nil)
+	if err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+
+	if _, err := htm.StopTimerContext(ctx); err == nil {
+		t.Error("Expected error when stopping without a running timer")
+	}
+	if _, err := htm.StartTimerContext(ctx, 999, ""); err == nil {
+		t.Error("Expected error when starting a timer on a missing task")
+	}
+
+	running, err := htm.StartTimerContext(ctx, created.ID, "drafting")
+	if err != nil {
+		t.Fatalf("Failed to start timer: %v", err)
+	}
+	if !running.IsRunning() {
+		t.Error("Expected the started entry to be running")
+	}
+	if _, err := htm.StartTimerContext(ctx, other.ID, ""); err == nil {
+		t.Error("Expected error when starting a second timer")
+	}
+
+	stopped, err := htm.StopTimerContext(ctx)
+	if err != nil {
+		t.Fatalf("Failed to stop timer: %v", err)
+	}
+	if stopped.ID != running.ID || stopped.IsRunning() {
+		t.Errorf("Expected the running entry to be stopped, got %+v", stopped)
+	}
+
+	if _, err := htm.LogTimeContext(ctx, created.ID, time.Now(), 30*time.Second, ""); err == nil {
+		t.Error("Expected error when logging less than a minute")
+	}
+	if _, err := htm.LogTimeContext(ctx, created.ID, time.Now().Add(time.Hour), time.Hour, ""); err == nil {
+		t.Error("Expected error when logging time in the future")
+	}
+	if _, err := htm.LogTimeContext(ctx, created.ID, time.Now().Add(-time.Hour), 45*time.Minute, "review"); err != nil {
+		t.Fatalf("Failed to log time: %v", err)
+	}
+
+	taskTime, err := htm.GetTaskTimeContext(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task time: %v", err)
+	}
+	if len(taskTime.Entries) != 2 {
+		t.Fatalf("Expected two entries, got %+v", taskTime.Entries)
+	}
+	if taskTime.Tracked < 45*time.Minute || taskTime.Tracked > 46*time.Minute {
+		t.Errorf("Expected about 45 minutes tracked, got %s", taskTime.Tracked)
+	}
+	if taskTime.EstimateMinutes == nil || *taskTime.EstimateMinutes != estimate {
+		t.Errorf("Expected the estimate of %d minutes, got %v", estimate, taskTime.EstimateMinutes)
+	}
+
+	// A timer can be started again once the previous one is stopped
+	if _, err := htm.StartTimerContext(ctx, other.ID, ""); err != nil {
+		t.Errorf("Failed to start a new timer: %v", err)
+	}
+}
+
+func TestTimeTrackingUnavailableInMemory(t *testing.T) {
+	htm := NewHybridTaskManager(nil, MemoryStorage)
+	created := htm.AddTask("Note", "", Low, nil)
+
+	if _, err := htm.StartTimerContext(context.Background(), created.ID, ""); !errors.Is(err, ErrTimeTrackingUnavailable) {
+		t.Errorf("Expected ErrTimeTrackingUnavailable, got %v", err)
+	}
+}
+
+func TestUserTimeReport(t *testing.T) {
+	repository := setupTestRepository(t)
+	um := NewUserManager(repository)
+	cm := NewCategoryManager(repository)
+
+	alice, err := um.RegisterUser("alice", "alice@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	bob, err := um.RegisterUser("bob", "bob@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+
+	work, err := cm.CreateCategory("Work", "", "#ff0000")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+
+	design, _ := um.CreateUserTask(alice.ID, "Design", "", High, nil)
+	build, _ := um.CreateUserTask(alice.ID, "Build", "", High, nil)
+	chores, _ := um.CreateUserTask(alice.ID, "Chores", "", Low, nil)
+	private, _ := um.CreateUserTask(bob.ID, "Private", "", Low, nil)
+
+	designEstimate, buildEstimate := 60, 120
+	for id, estimate := range map[int]*int{design.ID: &designEstimate, build.ID: &buildEstimate} {
+		dbTask, err := repository.GetTask(id)
+		if err != nil {
+			t.Fatalf("Failed to get task: %v", err)
+		}
+		dbTask.CategoryID = &work.ID
+		dbTask.EstimateMinutes = estimate
+		if err := repository.UpdateTask(dbTask); err != nil {
+			t.Fatalf("Failed to update task: %v", err)
+		}
+	}
+
+	day := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	logs := []struct {
+		userID, taskID int
+		startedAt      time.Time
+		minutes        int
+	}{
+		{alice.ID, design.ID, day, 30},
+		{alice.ID, design.ID, day.Add(2 * time.Hour), 20},
+		{alice.ID, build.ID, day.Add(4 * time.Hour), 90},
+		{alice.ID, chores.ID, day.Add(6 * time.Hour), 15},
+		{alice.ID, build.ID, day.AddDate(0, 0, -10), 60}, // outside the range
+		{bob.ID, private.ID, day, 45},                    // not alice's task
+	}
+	for _, log := range logs {
+		if _, err := um.LogUserTime(log.userID, log.taskID, log.startedAt, time.Duration(log.minutes)*time.Minute, ""); err != nil {
+			t.Fatalf("Failed to log time: %v", err)
+		}
+	}
+
+	if _, err := um.LogUserTime(bob.ID, design.ID, day, time.Hour, ""); err == nil {
+		t.Error("Expected error when logging time on another user's task")
+	}
+	if _, err := um.StartUserTimer(bob.ID, design.ID, ""); err == nil {
+		t.Error("Expected error when starting a timer on another user's task")
+	}
+
+	from, to := day.Truncate(24*time.Hour), day.Truncate(24*time.Hour).AddDate(0, 0, 1)
+
+	byCategory, err := um.GetUserTimeReport(alice.ID, GroupByCategory, from, to)
+	if err != nil {
+		t.Fatalf("Failed to get report: %v", err)
+	}
+	if len(byCategory) != 2 {
+		t.Fatalf("Expected a row for Work and one for uncategorized tasks, got %+v", byCategory)
+	}
+	if row := byCategory[0]; row.Name != "Work" || row.Tracked != 140*time.Minute || row.EstimateMinutes != 180 || row.Tasks != 2 || row.Entries != 3 {
+		t.Errorf("Unexpected Work row %+v", row)
+	}
+	if row := byCategory[1]; row.GroupID != nil || row.Name != "Uncategorized" || row.Tracked != 15*time.Minute {
+		t.Errorf("Unexpected uncategorized row %+v", row)
+	}
+
+	byTask, err := um.GetUserTimeReport(alice.ID, GroupByTask, from, to)
+	if err != nil {
+		t.Fatalf("Failed to get report: %v", err)
+	}
+	if len(byTask) != 3 || byTask[0].Name != "Build" || byTask[0].Tracked != 90*time.Minute || byTask[0].EstimateMinutes != 120 {
+		t.Errorf("Expected tasks ordered by tracked time, got %+v", byTask)
+	}
+
+	byUser, err := um.GetUserTimeReport(alice.ID, GroupByUser, from, to)
+	if err != nil {
+		t.Fatalf("Failed to get report: %v", err)
+	}
+	if len(byUser) != 1 || byUser[0].Name != "alice" || byUser[0].Tracked != 155*time.Minute {
+		t.Errorf("Expected only alice's time on her tasks, got %+v", byUser)
+	}
+
+	if _, err := um.GetUserTimeReport(alice.ID, GroupByTask, to, from); err == nil {
+		t.Error("Expected error for an inverted range")
+	}
+	if _, err := ParseReportGrouping("project"); err == nil {
+		t.Error("Expected error for an unknown grouping")
+	}
+}
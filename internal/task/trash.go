@@ -0,0 +1,223 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"learn-go-capstone/internal/database"
+)
+
+// RestoreTask takes a task out of the trash
+func (tm *TaskManager) RestoreTask(id int) error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	for i := range tm.tasks {
+		if tm.tasks[i].ID == id && tm.tasks[i].DeletedAt != nil {
+			tm.tasks[i].DeletedAt = nil
+			tm.tasks[i].UpdatedAt = time.Now()
+			tm.events.Publish(taskEvent(EventTaskRestored, tm.tasks[i], nil))
+			return nil
+		}
+	}
+
+	return fmt.Errorf("task with ID %d not found in trash", id)
+}
+
+// GetDeletedTasks returns the tasks in the trash
+func (tm *TaskManager) GetDeletedTasks() []Task {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	var deleted []Task
+	for _, task := range tm.tasks {
+		if task.DeletedAt != nil {
+			deleted = append(deleted, task)
+		}
+	}
+
+	return deleted
+}
+
+// PurgeDeletedTasks permanently removes the tasks that were moved to the
+// trash before cutoff and returns how many were removed
+func (tm *TaskManager) PurgeDeletedTasks(cutoff time.Time) int {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	kept := tm.tasks[:0]
+	for _, task := range tm.tasks {
+		if task.DeletedAt == nil || !task.DeletedAt.Before(cutoff) {
+			kept = append(kept, task)
+		}
+	}
+
+	purged := len(tm.tasks) - len(kept)
+	tm.tasks = kept
+	return purged
+}
+
+// restoreDatabaseTask takes a task out of the trash and publishes the change.
+// Trashed ancestors are restored first, so a restored subtask never hangs
+// below a parent that is still in the trash.
+func restoreDatabaseTask(repository database.Repository, events *EventBus, id int) (*database.DatabaseTask, error) {
+	dbTask, err := repository.GetDeletedTask(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if dbTask.ParentID != nil {
+		if _, err := repository.GetDeletedTask(*dbTask.ParentID); err == nil {
+			if _, err := restoreDatabaseTask(repository, events, *dbTask.ParentID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := repository.RestoreTask(id); err != nil {
+		return nil, err
+	}
+
+	dbTask.DeletedAt = nil
+	dbTask.UpdatedAt = time.Now()
+	events.Publish(databaseTaskEvent(EventTaskRestored, dbTask))
+	return dbTask, nil
+}
+
+// RestoreTask takes a task out of the trash
+func (htm *HybridTaskManager) RestoreTask(id int) error {
+	return htm.RestoreTaskContext(context.Background(), id)
+}
+
+// GetDeletedTasks returns the tasks in the trash, falling back to memory if the database fails
+func (htm *HybridTaskManager) GetDeletedTasks() []Task {
+	tasks, err := htm.GetDeletedTasksContext(context.Background())
+	if err != nil {
+		return htm.memoryManager.GetDeletedTasks()
+	}
+	return tasks
+}
+
+// RestoreTaskContext takes a task out of the trash
+func (htm *HybridTaskManager) RestoreTaskContext(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	htm.mu.Lock()
+	defer htm.mu.Unlock()
+
+	switch htm.storageType {
+	case DatabaseStorage, HybridStorage:
+		// Restoring a task also restores its trashed parents, which the
+		// memory copy of hybrid storage follows through their events
+		var restored []int
+		events := NewEventBus()
+		events.Subscribe(func(event Event) {
+			restored = append(restored, event.TaskID)
+			htm.events.Publish(event)
+		})
+		repository := htm.repository.WithContext(ctx)
+		if _, err := restoreDatabaseTask(repository, events, id); err != nil {
+			return err
+		}
+		htm.refreshMemory(repository, restored)
+		return nil
+
+	default:
+		return htm.memoryManager.RestoreTask(id)
+	}
+}
+
+// GetDeletedTasksContext returns the tasks in the trash
+func (htm *HybridTaskManager) GetDeletedTasksContext(ctx context.Context) ([]Task, error) {
+	return htm.listTasks(ctx, func(repository database.Repository) ([]database.DatabaseTask, error) {
+		return repository.GetDeletedTasks()
+	}, htm.memoryManager.GetDeletedTasks)
+}
+
+// TrashPolicy configures how long deleted tasks are kept before they are purged
+type TrashPolicy struct {
+	// Retention is how long a task stays in the trash
+	Retention time.Duration
+	// Interval is how often expired tasks are purged
+	Interval time.Duration
+}
+
+// Validate checks that the policy can be run
+func (p TrashPolicy) Validate() error {
+	if p.Retention <= 0 {
+		return errors.New("trash retention must be positive")
+	}
+	if p.Interval <= 0 {
+		return errors.New("trash purge interval must be positive")
+	}
+	return nil
+}
+
+// TrashPurger periodically and permanently removes tasks whose retention in
+// the trash has expired
+type TrashPurger struct {
+	repository  database.Repository
+	policy      TrashPolicy
+	attachments *AttachmentManager
+	ctx        context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// NewTrashPurger creates a purger for the given policy
+func NewTrashPurger(repository database.Repository, policy TrashPolicy) *TrashPurger {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &TrashPurger{
+		repository: repository,
+		policy:     policy,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// SetAttachmentManager sets the manager whose stored files are cleaned up
+// once the attachments of purged tasks no longer refer to them
+func (p *TrashPurger) SetAttachmentManager(am *AttachmentManager) {
+	p.attachments = am
+}
+
+// Start purges expired tasks once and then on every interval until Stop is called
+func (p *TrashPurger) Start() {
+	runPeriodically(p.ctx, &p.wg, p.policy.Interval, func() {
+		if purged, err := p.PurgeExpiredTasks(time.Now()); err != nil {
+			log.Printf("Trash purge failed: %v", err)
+		} else if purged > 0 {
+			log.Printf("Purged %d tasks from the trash", purged)
+		}
+	})
+}
+
+// Stop stops the purger and waits for a running pass to finish
+func (p *TrashPurger) Stop() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+// PurgeExpiredTasks permanently removes every task that was moved to the
+// trash more than policy.Retention before now and returns how many were removed
+func (p *TrashPurger) PurgeExpiredTasks(now time.Time) (int, error) {
+	purged, err := p.repository.WithContext(p.ctx).PurgeDeletedTasksBefore(now.Add(-p.policy.Retention))
+	if err != nil || purged == 0 || p.attachments == nil {
+		return purged, err
+	}
+
+	// Files uploaded in the last interval are spared, as their attachment
+	// may not be recorded yet
+	if _, err := p.attachments.WithContext(p.ctx).RemoveUnreferencedFiles(now.Add(-p.policy.Interval)); err != nil {
+		log.Printf("Failed to remove files of purged attachments: %v", err)
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,148 @@
+package task
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTaskManagerTrash(t *testing.T) {
+	tm := NewTaskManager()
+	kept := tm.AddTask("Keep", "", High, nil)
+	removed := tm.AddTask("Remove", "", Low, nil)
+
+	if err := tm.DeleteTask(removed.ID); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+	if err := tm.DeleteTask(removed.ID); err == nil {
+		t.Error("Expected error when deleting a task that is already in the trash")
+	}
+
+	all := tm.GetAllTasks()
+	if len(all) != 1 || all[0].ID != kept.ID {
+		t.Errorf("Expected only the remaining task, got %+v", all)
+	}
+	if _, err := tm.GetTask(removed.ID); err == nil {
+		t.Error("Expected a trashed task to be hidden from GetTask")
+	}
+
+	deleted := tm.GetDeletedTasks()
+	if len(deleted) != 1 || deleted[0].ID != removed.ID || deleted[0].DeletedAt == nil {
+		t.Fatalf("Expected the trashed task, got %+v", deleted)
+	}
+
+	if err := tm.RestoreTask(removed.ID); err != nil {
+		t.Fatalf("Failed to restore task: %v", err)
+	}
+	if err := tm.RestoreTask(kept.ID); err == nil {
+		t.Error("Expected error when restoring a task that is not in the trash")
+	}
+	if len(tm.GetAllTasks()) != 2 {
+		t.Error("Expected the restored task to be listed again")
+	}
+
+	if err := tm.DeleteTask(removed.ID); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+	if purged := tm.PurgeDeletedTasks(time.Now().Add(-time.Hour)); purged != 0 {
+		t.Errorf("Expected nothing to be purged before the cutoff, got %d", purged)
+	}
+	if purged := tm.PurgeDeletedTasks(time.Now().Add(time.Hour)); purged != 1 {
+		t.Errorf("Expected one task to be purged, got %d", purged)
+	}
+	if len(tm.GetDeletedTasks()) != 0 || len(tm.GetAllTasks()) != 1 {
+		t.Error("Expected the purged task to be gone for good")
+	}
+}
+
+func TestDatabaseTrashRestoresTagsAndDependencies(t *testing.T) {
+	repository := setupTestRepository(t)
+	htm := NewHybridTaskManager(repository, DatabaseStorage)
+	cm := NewCategoryManager(repository)
+	dm := NewDependencyManager(repository)
+
+	bus := NewEventBus()
+	htm.SetEventBus(bus)
+	var events recorder
+	bus.Subscribe(events.handle)
+
+	parent := htm.AddTask("Release", "", High, nil)
+	child := htm.AddTask("Changelog", "", Medium, nil)
+	other := htm.AddTask("Announce", "", Low, nil)
+	if err := htm.hierarchy.SetParent(child.ID, &parent.ID); err != nil {
+		t.Fatalf("Failed to set parent: %v", err)
+	}
+	tag, err := cm.CreateTag("release", "#00ff00")
+	if err != nil {
+		t.Fatalf("Failed to create tag: %v", err)
+	}
+	if err := cm.AddTagToTask(child.ID, tag.ID); err != nil {
+		t.Fatalf("Failed to tag task: %v", err)
+	}
+	if err := dm.AddDependency(other.ID, child.ID); err != nil {
+		t.Fatalf("Failed to add dependency: %v", err)
+	}
+
+	// Deleting the parent cascades into the trash
+	if err := htm.DeleteTask(parent.ID); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+	if all := htm.GetAllTasks(); len(all) != 1 || all[0].ID != other.ID {
+		t.Fatalf("Expected only the unrelated task to be listed, got %+v", all)
+	}
+	if deps, err := repository.GetTaskDependencies(other.ID); err != nil || len(deps) != 0 {
+		t.Errorf("Expected dependencies on trashed tasks to be hidden, got %+v (%v)", deps, err)
+	}
+	if deleted := htm.GetDeletedTasks(); len(deleted) != 2 {
+		t.Fatalf("Expected both tasks in the trash, got %+v", deleted)
+	}
+
+	// Restoring the subtask brings its parent back as well
+	if err := htm.RestoreTask(child.ID); err != nil {
+		t.Fatalf("Failed to restore task: %v", err)
+	}
+	if len(htm.GetAllTasks()) != 3 || len(htm.GetDeletedTasks()) != 0 {
+		t.Fatalf("Expected every task to be restored, got %+v", htm.GetAllTasks())
+	}
+	restored, err := repository.GetTask(child.ID)
+	if err != nil || restored.ParentID == nil || *restored.ParentID != parent.ID {
+		t.Errorf("Expected the subtask to keep its parent, got %+v (%v)", restored, err)
+	}
+	if tags, err := repository.GetTaskTags(child.ID); err != nil || len(tags) != 1 {
+		t.Errorf("Expected the tag to survive the trash, got %+v (%v)", tags, err)
+	}
+	if deps, err := repository.GetTaskDependencies(other.ID); err != nil || len(deps) != 1 {
+		t.Errorf("Expected the dependency to survive the trash, got %+v (%v)", deps, err)
+	}
+
+	assertEventTypes(t, events.types(),
+		EventTaskCreated, EventTaskCreated, EventTaskCreated, EventTaskUpdated,
+		EventTaskDeleted, EventTaskDeleted, EventTaskRestored, EventTaskRestored)
+	if events.events[6].TaskID != parent.ID || events.events[7].TaskID != child.ID {
+		t.Errorf("Expected the parent to be restored first, got %d then %d", events.events[6].TaskID, events.events[7].TaskID)
+	}
+
+	// Purging removes the task together with its tags and dependencies
+	if err := htm.DeleteTask(child.ID); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+	purger := NewTrashPurger(repository, TrashPolicy{Retention: 24 * time.Hour, Interval: time.Hour})
+	if purged, err := purger.PurgeExpiredTasks(time.Now()); err != nil || purged != 0 {
+		t.Fatalf("Expected nothing to be purged yet, got %d (%v)", purged, err)
+	}
+	if purged, err := purger.PurgeExpiredTasks(time.Now().Add(48 * time.Hour)); err != nil || purged != 1 {
+		t.Fatalf("Expected one task to be purged, got %d (%v)", purged, err)
+	}
+	if _, err := repository.GetDeletedTask(child.ID); err == nil {
+		t.Error("Expected the purged task to be gone")
+	}
+	if tags, err := repository.GetTaskTags(child.ID); err != nil || len(tags) != 0 {
+		t.Errorf("Expected the tags of the purged task to be removed, got %+v (%v)", tags, err)
+	}
+	if err := htm.RestoreTask(child.ID); err == nil {
+		t.Error("Expected error when restoring a purged task")
+	}
+
+	if err := (TrashPolicy{Retention: time.Hour}).Validate(); err == nil {
+		t.Error("Expected a policy without interval to be invalid")
+	}
+}
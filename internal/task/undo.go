@@ -0,0 +1,983 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"learn-go-capstone/internal/database"
+)
+
+// DefaultJournalLimit is the number of commands a journal keeps per user
+const DefaultJournalLimit = 100
+
+var (
+	// ErrNothingToUndo is returned by undo when the journal has no command left to undo
+	ErrNothingToUndo = errors.New("nothing to undo")
+	// ErrNothingToRedo is returned by redo when no command has been undone since the last change
+	ErrNothingToRedo = errors.New("nothing to redo")
+	// ErrReplayConflict is returned by undo and redo when a field the command
+	// changed has been changed again since
+	ErrReplayConflict = errors.New("conflicting change")
+)
+
+// CommandKind is the task mutation a command records
+type CommandKind string
+
+const (
+	CommandCreate CommandKind = "create"
+	CommandStatus CommandKind = "status"
+	CommandUpdate CommandKind = "update"
+	CommandDelete CommandKind = "delete"
+)
+
+// TaskSnapshot is the state of a task as a command found or left it. The
+// links are only recorded before a delete, so that undoing it can bring back
+// the tags, category and dependencies the task had.
+type TaskSnapshot struct {
+	Task       Task  `json:"task"`
+	CategoryID *int  `json:"category_id,omitempty"`
+	TagIDs     []int `json:"tag_ids,omitempty"`
+	DependsOn  []int `json:"depends_on,omitempty"`
+	Dependents []int `json:"dependents,omitempty"`
+}
+
+// TaskChange is what a command did to one task. A nil Before stands for a
+// task the command created, and a nil After for one it no longer finds.
+type TaskChange struct {
+	TaskID int           `json:"task_id"`
+	Before *TaskSnapshot `json:"before,omitempty"`
+	After  *TaskSnapshot `json:"after,omitempty"`
+}
+
+// Command is a recorded task mutation that can be undone and redone. Besides
+// the task it targets, it covers the subtasks a delete or status change
+// cascaded to and the next occurrence completing a recurring task created.
+type Command struct {
+	ID        int          `json:"id"`
+	UserID    *int         `json:"user_id,omitempty"`
+	Kind      CommandKind  `json:"kind"`
+	TaskID    int          `json:"task_id"`
+	Changes   []TaskChange `json:"changes"`
+	Undone    bool         `json:"undone"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// String describes the command for people, such as `delete task 12 "Report"`
+func (c Command) String() string {
+	for _, change := range c.Changes {
+		if change.TaskID != c.TaskID {
+			continue
+		}
+		snapshot := change.After
+		if snapshot == nil {
+			snapshot = change.Before
+		}
+		if snapshot != nil {
+			return fmt.Sprintf("%s task %d %q", c.verb(), c.TaskID, snapshot.Task.Title)
+		}
+	}
+	return fmt.Sprintf("%s task %d", c.verb(), c.TaskID)
+}
+
+func (c Command) verb() string {
+	switch c.Kind {
+	case CommandCreate:
+		return "create"
+	case CommandStatus:
+		return "change the status of"
+	case CommandDelete:
+		return "delete"
+	default:
+		return "update"
+	}
+}
+
+// Journal keeps the commands of each user so that they can be undone and
+// redone. A nil user ID stands for the local command line user.
+type Journal interface {
+	// Record appends a command to the journal of its user; the commands the
+	// user had undone can no longer be redone
+	Record(command *Command) error
+	// Undoable returns the latest command that has not been undone, or nil
+	Undoable(userID *int) (*Command, error)
+	// Redoable returns the earliest command that has been undone, or nil
+	Redoable(userID *int) (*Command, error)
+	// SetUndone marks a command as undone or as done again
+	SetUndone(commandID int, undone bool) error
+}
+
+// memoryJournal keeps the journals of memory storage, which end with the process
+type memoryJournal struct {
+	mu       sync.Mutex
+	limit    int
+	nextID   int
+	commands map[int][]Command
+}
+
+// NewMemoryJournal creates a journal that keeps the latest limit commands of
+// each user in memory
+func NewMemoryJournal(limit int) Journal {
+	return &memoryJournal{
+		limit:    limit,
+		nextID:   1,
+		commands: make(map[int][]Command),
+	}
+}
+
+// journalOwner maps a user to the key of their journal; user IDs start at one
+func journalOwner(userID *int) int {
+	if userID == nil {
+		return 0
+	}
+	return *userID
+}
+
+func (j *memoryJournal) Record(command *Command) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	owner := journalOwner(command.UserID)
+	kept := make([]Command, 0, len(j.commands[owner])+1)
+	for _, recorded := range j.commands[owner] {
+		if !recorded.Undone {
+			kept = append(kept, recorded)
+		}
+	}
+
+	command.ID = j.nextID
+	command.Undone = false
+	command.CreatedAt = time.Now()
+	j.nextID++
+
+	kept = append(kept, *command)
+	if len(kept) > j.limit {
+		kept = kept[len(kept)-j.limit:]
+	}
+	j.commands[owner] = kept
+	return nil
+}
+
+func (j *memoryJournal) Undoable(userID *int) (*Command, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	commands := j.commands[journalOwner(userID)]
+	for i := len(commands) - 1; i >= 0; i-- {
+		if !commands[i].Undone {
+			command := commands[i]
+			return &command, nil
+		}
+	}
+	return nil, nil
+}
+
+func (j *memoryJournal) Redoable(userID *int) (*Command, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, command := range j.commands[journalOwner(userID)] {
+		if command.Undone {
+			return &command, nil
+		}
+	}
+	return nil, nil
+}
+
+func (j *memoryJournal) SetUndone(commandID int, undone bool) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, commands := range j.commands {
+		for i := range commands {
+			if commands[i].ID == commandID {
+				commands[i].Undone = undone
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("journal command with ID %d not found", commandID)
+}
+
+// databaseJournal keeps the journals in the database, so that a command run
+// from the command line can be undone by the next one
+type databaseJournal struct {
+	repository database.Repository
+	limit      int
+}
+
+// NewDatabaseJournal creates a journal that keeps the latest limit commands
+// of each user in the database
+func NewDatabaseJournal(repository database.Repository, limit int) Journal {
+	return &databaseJournal{repository: repository, limit: limit}
+}
+
+func (j *databaseJournal) Record(command *Command) error {
+	changes, err := json.Marshal(command.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to encode command: %w", err)
+	}
+
+	stored := &database.JournalCommand{
+		UserID:  command.UserID,
+		Kind:    string(command.Kind),
+		TaskID:  command.TaskID,
+		Changes: changes,
+	}
+	if err := j.repository.CreateJournalCommand(stored, j.limit); err != nil {
+		return err
+	}
+
+	command.ID = stored.ID
+	command.Undone = false
+	command.CreatedAt = stored.CreatedAt
+	return nil
+}
+
+func (j *databaseJournal) Undoable(userID *int) (*Command, error) {
+	return convertFromJournalCommand(j.repository.GetUndoableCommand(userID))
+}
+
+func (j *databaseJournal) Redoable(userID *int) (*Command, error) {
+	return convertFromJournalCommand(j.repository.GetRedoableCommand(userID))
+}
+
+func (j *databaseJournal) SetUndone(commandID int, undone bool) error {
+	return j.repository.SetJournalCommandUndone(commandID, undone)
+}
+
+// convertFromJournalCommand decodes a stored command
+func convertFromJournalCommand(stored *database.JournalCommand, err error) (*Command, error) {
+	if err != nil || stored == nil {
+		return nil, err
+	}
+
+	command := &Command{
+		ID:        stored.ID,
+		UserID:    stored.UserID,
+		Kind:      CommandKind(stored.Kind),
+		TaskID:    stored.TaskID,
+		Undone:    stored.Undone,
+		CreatedAt: stored.CreatedAt,
+	}
+	if err := json.Unmarshal(stored.Changes, &command.Changes); err != nil {
+		return nil, fmt.Errorf("invalid journal command %d: %w", stored.ID, err)
+	}
+	return command, nil
+}
+
+// nextCommand returns the command that undo or redo works on next. A nil
+// journal has nothing to undo or redo.
+func nextCommand(journal Journal, userID *int, undo bool) (*Command, error) {
+	var command *Command
+	var err error
+	switch {
+	case journal == nil:
+	case undo:
+		command, err = journal.Undoable(userID)
+	default:
+		command, err = journal.Redoable(userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if command == nil {
+		if undo {
+			return nil, ErrNothingToUndo
+		}
+		return nil, ErrNothingToRedo
+	}
+	return command, nil
+}
+
+// replay puts every task of a command back to its state before the command
+// when undoing, latest change first, or after it when redoing. put gets the
+// state the command left the task in and the state to bring it to.
+func (c *Command) replay(undo bool, put func(taskID int, from, to *TaskSnapshot) error) error {
+	if undo {
+		for i := len(c.Changes) - 1; i >= 0; i-- {
+			change := c.Changes[i]
+			if err := put(change.TaskID, change.After, change.Before); err != nil {
+				return err
+			}
+		}
+	} else {
+		for _, change := range c.Changes {
+			if err := put(change.TaskID, change.Before, change.After); err != nil {
+				return err
+			}
+		}
+	}
+
+	c.Undone = undo
+	return nil
+}
+
+// snapshotField is a field of a task that a command can change. Undo and
+// redo only put back the fields the command changed, so that later changes
+// to the other fields, such as archiving or snoozing the task, are kept.
+type snapshotField struct {
+	name string
+	// value returns the field in a form that can be compared with ==
+	value func(s *TaskSnapshot) interface{}
+	set   func(dst, src *TaskSnapshot)
+}
+
+var snapshotFields = []snapshotField{
+	{"title", func(s *TaskSnapshot) interface{} { return s.Task.Title }, func(dst, src *TaskSnapshot) { dst.Task.Title = src.Task.Title }},
+	{"description", func(s *TaskSnapshot) interface{} { return s.Task.Description }, func(dst, src *TaskSnapshot) { dst.Task.Description = src.Task.Description }},
+	{"priority", func(s *TaskSnapshot) interface{} { return s.Task.Priority }, func(dst, src *TaskSnapshot) { dst.Task.Priority = src.Task.Priority }},
+	{"status", func(s *TaskSnapshot) interface{} { return s.Task.Status }, func(dst, src *TaskSnapshot) { dst.Task.Status = src.Task.Status }},
+	{"due date", func(s *TaskSnapshot) interface{} { return optionalTime(s.Task.DueDate) }, func(dst, src *TaskSnapshot) { dst.Task.DueDate = src.Task.DueDate }},
+	{"category", func(s *TaskSnapshot) interface{} { return snapshotCategory(s) }, func(dst, src *TaskSnapshot) {
+		dst.CategoryID = src.CategoryID
+		dst.Task.Category = src.Task.Category
+	}},
+	{"recurrence", func(s *TaskSnapshot) interface{} {
+		if s.Task.Recurrence == nil {
+			return ""
+		}
+		return s.Task.Recurrence.String()
+	}, func(dst, src *TaskSnapshot) { dst.Task.Recurrence = src.Task.Recurrence }},
+	{"estimate", func(s *TaskSnapshot) interface{} { return optionalInt(s.Task.EstimateMinutes) }, func(dst, src *TaskSnapshot) { dst.Task.EstimateMinutes = src.Task.EstimateMinutes }},
+	{"parent", func(s *TaskSnapshot) interface{} { return optionalInt(s.Task.ParentID) }, func(dst, src *TaskSnapshot) { dst.Task.ParentID = src.Task.ParentID }},
+	{"project", func(s *TaskSnapshot) interface{} { return optionalInt(s.Task.ProjectID) }, func(dst, src *TaskSnapshot) { dst.Task.ProjectID = src.Task.ProjectID }},
+	{"sprint", func(s *TaskSnapshot) interface{} { return optionalInt(s.Task.SprintID) }, func(dst, src *TaskSnapshot) { dst.Task.SprintID = src.Task.SprintID }},
+	{"archived state", func(s *TaskSnapshot) interface{} { return s.Task.IsArchived }, func(dst, src *TaskSnapshot) { dst.Task.IsArchived = src.Task.IsArchived }},
+	{"snooze", func(s *TaskSnapshot) interface{} { return optionalTime(s.Task.HiddenUntil) }, func(dst, src *TaskSnapshot) { dst.Task.HiddenUntil = src.Task.HiddenUntil }},
+	// Only whether the task is in the trash matters, not since when
+	{"trash state", func(s *TaskSnapshot) interface{} { return s.Task.DeletedAt != nil }, func(dst, src *TaskSnapshot) { dst.Task.DeletedAt = src.Task.DeletedAt }},
+}
+
+func optionalInt(value *int) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+func optionalTime(value *time.Time) interface{} {
+	if value == nil {
+		return nil
+	}
+	return value.UTC().Format(time.RFC3339Nano)
+}
+
+// snapshotCategory returns the category ID of a snapshot, which memory
+// snapshots keep on the task and database snapshots next to it
+func snapshotCategory(s *TaskSnapshot) interface{} {
+	if s.CategoryID != nil {
+		return *s.CategoryID
+	}
+	if s.Task.Category != nil {
+		return s.Task.Category.ID
+	}
+	return nil
+}
+
+// mergeSnapshot returns the current state of a task with the fields the
+// command changed between from and to set to their values in to, and the
+// names of those fields. It fails with ErrReplayConflict when one of them has
+// been changed again since. A nil from stands for a task the command created
+// or no longer found, of which only the trash state is put back.
+func mergeSnapshot(taskID int, current, from, to *TaskSnapshot) (*TaskSnapshot, map[string]bool, error) {
+	merged := *current
+	changed := make(map[string]bool)
+	for _, field := range snapshotFields {
+		if from == nil {
+			if field.name != "trash state" {
+				continue
+			}
+		} else if field.value(from) == field.value(to) {
+			continue
+		} else if field.value(current) != field.value(from) {
+			return nil, nil, fmt.Errorf("%w: the %s of task %d has been changed since", ErrReplayConflict, field.name, taskID)
+		}
+
+		field.set(&merged, to)
+		changed[field.name] = true
+	}
+	return &merged, changed, nil
+}
+
+// Memory storage
+
+// SetJournal replaces the journal that task mutations are recorded in; a nil
+// journal stops recording
+func (tm *TaskManager) SetJournal(journal Journal) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.journal = journal
+}
+
+// recordLocked records a command of the local user; tm.mu must be held.
+// Memory storage cannot fail to record, so errors are left out.
+func (tm *TaskManager) recordLocked(kind CommandKind, taskID int, changes ...TaskChange) {
+	if tm.journal == nil {
+		return
+	}
+	tm.journal.Record(&Command{Kind: kind, TaskID: taskID, Changes: changes})
+}
+
+// memorySnapshot returns a snapshot of a task held in memory, whose tags and
+// category are part of the task itself
+func memorySnapshot(task Task) *TaskSnapshot {
+	return &TaskSnapshot{Task: task}
+}
+
+// Undo reverses the latest change to the tasks that has not been undone
+func (tm *TaskManager) Undo() (*Command, error) {
+	return tm.replayCommand(true)
+}
+
+// Redo applies the earliest undone change again
+func (tm *TaskManager) Redo() (*Command, error) {
+	return tm.replayCommand(false)
+}
+
+// UndoContext reverses the latest change to the tasks that has not been undone
+func (tm *TaskManager) UndoContext(ctx context.Context) (*Command, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return tm.Undo()
+}
+
+// RedoContext applies the earliest undone change again
+func (tm *TaskManager) RedoContext(ctx context.Context) (*Command, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return tm.Redo()
+}
+
+func (tm *TaskManager) replayCommand(undo bool) (*Command, error) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	command, err := nextCommand(tm.journal, nil, undo)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tm.replayLocked(command, undo); err != nil {
+		return nil, err
+	}
+	if err := tm.journal.SetUndone(command.ID, undo); err != nil {
+		return nil, err
+	}
+	return command, nil
+}
+
+// replayLocked undoes or redoes a command in memory; tm.mu must be held.
+// Memory cannot roll back, so every change is checked before any is made.
+func (tm *TaskManager) replayLocked(command *Command, undo bool) error {
+	check := *command
+	err := check.replay(undo, func(taskID int, from, to *TaskSnapshot) error {
+		_, _, err := tm.mergeSnapshotLocked(taskID, from, to)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return command.replay(undo, func(taskID int, from, to *TaskSnapshot) error {
+		tm.putSnapshotLocked(taskID, from, to)
+		return nil
+	})
+}
+
+// mergeSnapshotLocked returns the index of the task with the given ID, or -1
+// when it is no longer held, and the task as it is to be after replaying a
+// change from one snapshot to another. A status change is checked against
+// the workflow. tm.mu must be held.
+func (tm *TaskManager) mergeSnapshotLocked(id int, from, to *TaskSnapshot) (*TaskSnapshot, int, error) {
+	for i := range tm.tasks {
+		if tm.tasks[i].ID != id {
+			continue
+		}
+		if to == nil {
+			return nil, i, nil
+		}
+
+		current := memorySnapshot(tm.tasks[i])
+		merged, changed, err := mergeSnapshot(id, current, from, to)
+		if err != nil {
+			return nil, -1, err
+		}
+		if changed["status"] {
+			if err := tm.workflow.CheckTransition(current.Task, merged.Task.Status); err != nil {
+				return nil, -1, err
+			}
+		}
+		return merged, i, nil
+	}
+	return to, -1, nil
+}
+
+// putSnapshotLocked replays a change to a task from one snapshot to another,
+// moving it in or out of the trash as needed; a nil snapshot to moves the
+// task to the trash. The change must have been checked with
+// mergeSnapshotLocked. tm.mu must be held.
+func (tm *TaskManager) putSnapshotLocked(id int, from, to *TaskSnapshot) {
+	merged, index, _ := tm.mergeSnapshotLocked(id, from, to)
+
+	now := time.Now()
+	if to == nil {
+		if index >= 0 && tm.tasks[index].DeletedAt == nil {
+			tm.tasks[index].DeletedAt = &now
+			tm.events.Publish(taskEvent(EventTaskDeleted, tm.tasks[index], nil))
+		}
+		return
+	}
+
+	task := merged.Task
+	task.UpdatedAt = now
+	if index < 0 {
+		// Purged from the trash since: put it back under its own ID
+		tm.putTaskLocked(task)
+		tm.events.Publish(taskEvent(EventTaskRestored, task, nil))
+		return
+	}
+
+	current := tm.tasks[index]
+	tm.tasks[index] = task
+	switch {
+	case current.DeletedAt != nil && task.DeletedAt == nil:
+		tm.events.Publish(taskEvent(EventTaskRestored, task, nil))
+	case current.DeletedAt == nil && task.DeletedAt != nil:
+		tm.events.Publish(taskEvent(EventTaskDeleted, task, nil))
+	case current.Status != task.Status:
+		tm.events.Publish(statusChangedEvent(task, nil, current.Status))
+	default:
+		tm.events.Publish(taskEvent(EventTaskUpdated, task, nil))
+	}
+}
+
+// putTask stores a copy of a task under its own ID, replacing the task held
+// under that ID, if any
+func (tm *TaskManager) putTask(task Task) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.putTaskLocked(task)
+}
+
+// putTaskLocked stores a copy of a task under its own ID; tm.mu must be held
+func (tm *TaskManager) putTaskLocked(task Task) {
+	if task.ID >= tm.nextID {
+		tm.nextID = task.ID + 1
+	}
+	for i := range tm.tasks {
+		if tm.tasks[i].ID == task.ID {
+			tm.tasks[i] = task
+			return
+		}
+	}
+	tm.tasks = append(tm.tasks, task)
+}
+
+// Database storage
+
+// databaseSnapshot returns a snapshot of a stored task, with its links when
+// withLinks is set
+func databaseSnapshot(repository database.Repository, dbTask *database.DatabaseTask, withLinks bool) (*TaskSnapshot, error) {
+	snapshot := &TaskSnapshot{
+		Task:       convertFromDatabaseTask(dbTask),
+		CategoryID: dbTask.CategoryID,
+	}
+	if !withLinks {
+		return snapshot, nil
+	}
+
+	tags, err := repository.GetTaskTags(dbTask.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		snapshot.TagIDs = append(snapshot.TagIDs, tag.ID)
+	}
+
+	dependencies, err := repository.GetTaskDependencies(dbTask.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, dependency := range dependencies {
+		snapshot.DependsOn = append(snapshot.DependsOn, dependency.DependsOnTaskID)
+	}
+
+	dependents, err := repository.GetTasksThatDependOn(dbTask.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, dependent := range dependents {
+		snapshot.Dependents = append(snapshot.Dependents, dependent.ID)
+	}
+
+	return snapshot, nil
+}
+
+// storedTask loads a task whether or not it is in the trash
+func storedTask(repository database.Repository, id int) (*database.DatabaseTask, error) {
+	dbTask, err := repository.GetTask(id)
+	if err == nil {
+		return dbTask, nil
+	}
+
+	trashed, trashErr := repository.GetDeletedTask(id)
+	if trashErr != nil {
+		return nil, err
+	}
+	return trashed, nil
+}
+
+// journalDatabaseCommand runs op in a transaction with its events held back,
+// records the changes to every task those events name as one command of the
+// user, and publishes the events once the transaction has been committed.
+// taskID is the task op changes, or zero when op creates it; the task and its
+// subtasks are snapshotted before op runs, as deletes and status changes can
+// cascade to subtasks.
+func journalDatabaseCommand(repository database.Repository, events *EventBus, userID *int, kind CommandKind, taskID int, op func(repository database.Repository, events *EventBus) error) error {
+	var published []Event
+	buffer := NewEventBus()
+	buffer.Subscribe(func(event Event) { published = append(published, event) })
+
+	err := repository.Transaction(func(tx database.Repository) error {
+		// A task that cannot be loaded is left for op to fail on
+		before := make(map[int]*TaskSnapshot)
+		if dbTask, err := tx.GetTask(taskID); err == nil {
+			descendants, err := tx.GetDescendantTasks(taskID)
+			if err != nil {
+				return err
+			}
+			for _, affected := range append([]database.DatabaseTask{*dbTask}, descendants...) {
+				affected := affected
+				snapshot, err := databaseSnapshot(tx, &affected, kind == CommandDelete)
+				if err != nil {
+					return err
+				}
+				before[affected.ID] = snapshot
+			}
+		}
+
+		if err := op(tx, buffer); err != nil {
+			return err
+		}
+
+		command := &Command{UserID: userID, Kind: kind, TaskID: taskID}
+		seen := make(map[int]bool)
+		for _, event := range published {
+			if event.TaskID == 0 || seen[event.TaskID] {
+				continue
+			}
+			seen[event.TaskID] = true
+
+			change := TaskChange{TaskID: event.TaskID, Before: before[event.TaskID]}
+			if dbTask, err := storedTask(tx, event.TaskID); err == nil {
+				if change.After, err = databaseSnapshot(tx, dbTask, false); err != nil {
+					return err
+				}
+			}
+			command.Changes = append(command.Changes, change)
+		}
+		if len(command.Changes) == 0 {
+			return nil
+		}
+		if command.TaskID == 0 {
+			command.TaskID = command.Changes[0].TaskID
+		}
+
+		return NewDatabaseJournal(tx, DefaultJournalLimit).Record(command)
+	})
+	if err != nil {
+		return err
+	}
+
+	events.Publish(published...)
+	return nil
+}
+
+// replayDatabaseCommand undoes or redoes the next command of a user in one
+// transaction and publishes the changes once it has been committed. Status
+// changes follow the workflow of hierarchy.
+func replayDatabaseCommand(repository database.Repository, events *EventBus, hierarchy *HierarchyManager, userID *int, undo bool) (*Command, error) {
+	var published []Event
+	buffer := NewEventBus()
+	buffer.Subscribe(func(event Event) { published = append(published, event) })
+
+	var command *Command
+	err := repository.Transaction(func(tx database.Repository) error {
+		journal := NewDatabaseJournal(tx, DefaultJournalLimit)
+		workflow := hierarchy.withRepository(tx, buffer).GetWorkflow()
+
+		var err error
+		command, err = nextCommand(journal, userID, undo)
+		if err != nil {
+			return err
+		}
+
+		err = command.replay(undo, func(taskID int, from, to *TaskSnapshot) error {
+			return putDatabaseSnapshot(tx, buffer, workflow, taskID, from, to)
+		})
+		if err != nil {
+			return err
+		}
+
+		return journal.SetUndone(command.ID, undo)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events.Publish(published...)
+	return command, nil
+}
+
+// putDatabaseSnapshot replays a change to a stored task from one snapshot to
+// another, moving it in or out of the trash as needed; a nil snapshot to
+// moves the task to the trash. Only the fields the change made are put back,
+// and a task taken out of the trash gets back the links it had when the
+// snapshot was taken.
+func putDatabaseSnapshot(repository database.Repository, events *EventBus, workflow *Workflow, id int, from, to *TaskSnapshot) error {
+	current, err := storedTask(repository, id)
+	if err != nil {
+		if to == nil {
+			return nil
+		}
+		return fmt.Errorf("task with ID %d not found: it may have been purged from the trash", id)
+	}
+	trashed := current.DeletedAt != nil
+
+	if to == nil {
+		if trashed {
+			return nil
+		}
+		if err := repository.DeleteTask(id); err != nil {
+			return err
+		}
+		events.Publish(databaseTaskEvent(EventTaskDeleted, current))
+		return nil
+	}
+
+	currentSnapshot, err := databaseSnapshot(repository, current, false)
+	if err != nil {
+		return err
+	}
+	merged, changed, err := mergeSnapshot(id, currentSnapshot, from, to)
+	if err != nil {
+		return err
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	if trashed && merged.Task.DeletedAt != nil {
+		return fmt.Errorf("%w: task %d has been moved to the trash since", ErrReplayConflict, id)
+	}
+	if changed["status"] {
+		if err := workflow.CheckTransition(currentSnapshot.Task, merged.Task.Status); err != nil {
+			return err
+		}
+	}
+
+	restored := trashed && merged.Task.DeletedAt == nil
+	if restored {
+		if err := repository.RestoreTask(id); err != nil {
+			return err
+		}
+		trashed = false
+	}
+
+	dbTask := convertToDatabaseTask(merged.Task)
+	dbTask.UserID = current.UserID
+	dbTask.CategoryID = merged.CategoryID
+	if changed["category"] && dbTask.CategoryID != nil {
+		if _, err := repository.GetCategory(*dbTask.CategoryID); err != nil {
+			dbTask.CategoryID = nil
+		}
+	}
+	if !changed["recurrence"] {
+		// Keep the stored rule as it is, even one that cannot be parsed
+		dbTask.RecurrenceRule = current.RecurrenceRule
+	}
+	if err := repository.UpdateTask(dbTask); err != nil {
+		return err
+	}
+
+	switch {
+	case restored:
+		if err := restoreDatabaseLinks(repository, id, to); err != nil {
+			return err
+		}
+		events.Publish(databaseTaskEvent(EventTaskRestored, dbTask))
+	case !trashed && merged.Task.DeletedAt != nil:
+		if err := repository.DeleteTask(id); err != nil {
+			return err
+		}
+		events.Publish(databaseTaskEvent(EventTaskDeleted, dbTask))
+	case current.Status != dbTask.Status:
+		events.Publish(statusChangedEvent(convertFromDatabaseTask(dbTask), dbTask.UserID, Status(current.Status)))
+	default:
+		events.Publish(databaseTaskEvent(EventTaskUpdated, dbTask))
+	}
+	return nil
+}
+
+// restoreDatabaseLinks adds back the tags and dependencies of a snapshot that
+// a task has lost since. Tags and tasks that no longer exist are skipped.
+func restoreDatabaseLinks(repository database.Repository, id int, snapshot *TaskSnapshot) error {
+	tags, err := repository.GetTaskTags(id)
+	if err != nil {
+		return err
+	}
+	tagged := make(map[int]bool)
+	for _, tag := range tags {
+		tagged[tag.ID] = true
+	}
+	for _, tagID := range snapshot.TagIDs {
+		if tagged[tagID] {
+			continue
+		}
+		if _, err := repository.GetTag(tagID); err != nil {
+			continue
+		}
+		if err := repository.AddTagToTask(id, tagID); err != nil {
+			return err
+		}
+	}
+
+	dependencies, err := repository.GetTaskDependencies(id)
+	if err != nil {
+		return err
+	}
+	dependsOn := make(map[int]bool)
+	for _, dependency := range dependencies {
+		dependsOn[dependency.DependsOnTaskID] = true
+	}
+	for _, dependencyID := range snapshot.DependsOn {
+		if dependsOn[dependencyID] {
+			continue
+		}
+		if _, err := repository.GetTask(dependencyID); err != nil {
+			continue
+		}
+		if err := repository.AddTaskDependency(id, dependencyID); err != nil {
+			return err
+		}
+	}
+
+	dependents, err := repository.GetTasksThatDependOn(id)
+	if err != nil {
+		return err
+	}
+	dependedOn := make(map[int]bool)
+	for _, dependent := range dependents {
+		dependedOn[dependent.ID] = true
+	}
+	for _, dependentID := range snapshot.Dependents {
+		if dependedOn[dependentID] {
+			continue
+		}
+		if _, err := repository.GetTask(dependentID); err != nil {
+			continue
+		}
+		if err := repository.AddTaskDependency(dependentID, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Undo reverses the latest change to the stored tasks that has not been undone
+func (htm *HybridTaskManager) Undo() (*Command, error) {
+	return htm.UndoContext(context.Background())
+}
+
+// Redo applies the earliest undone change again
+func (htm *HybridTaskManager) Redo() (*Command, error) {
+	return htm.RedoContext(context.Background())
+}
+
+// UndoContext reverses the latest change to the tasks that has not been undone
+func (htm *HybridTaskManager) UndoContext(ctx context.Context) (*Command, error) {
+	return htm.replayCommand(ctx, true)
+}
+
+// RedoContext applies the earliest undone change again
+func (htm *HybridTaskManager) RedoContext(ctx context.Context) (*Command, error) {
+	return htm.replayCommand(ctx, false)
+}
+
+func (htm *HybridTaskManager) replayCommand(ctx context.Context, undo bool) (*Command, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	htm.mu.Lock()
+	defer htm.mu.Unlock()
+
+	switch htm.storageType {
+	case DatabaseStorage, HybridStorage:
+		repository := htm.repository.WithContext(ctx)
+		command, err := replayDatabaseCommand(repository, htm.events, htm.hierarchy, nil, undo)
+		if err != nil {
+			return nil, err
+		}
+
+		ids := make([]int, len(command.Changes))
+		for i, change := range command.Changes {
+			ids[i] = change.TaskID
+		}
+		htm.refreshMemory(repository, ids)
+		return command, nil
+
+	default:
+		return htm.memoryManager.replayCommand(undo)
+	}
+}
+
+// journal records a database change of the hybrid manager as a command of
+// the local user. In hybrid storage the memory copies of the tasks the change
+// names are refreshed once it has been stored.
+func (htm *HybridTaskManager) journal(ctx context.Context, kind CommandKind, taskID int, op func(repository database.Repository, events *EventBus) error) error {
+	repository := htm.repository.WithContext(ctx)
+	changed := []int{taskID}
+	err := journalDatabaseCommand(repository, htm.events, nil, kind, taskID, func(repository database.Repository, events *EventBus) error {
+		named := NewEventBus()
+		named.Subscribe(func(event Event) {
+			changed = append(changed, event.TaskID)
+			events.Publish(event)
+		})
+		return op(repository, named)
+	})
+	if err != nil {
+		return err
+	}
+
+	htm.refreshMemory(repository, changed)
+	return nil
+}
+
+// UndoUserCommand reverses the latest change a user made to their tasks that
+// has not been undone
+func (um *UserManager) UndoUserCommand(userID int) (*Command, error) {
+	return replayDatabaseCommand(um.repository, um.events, um.hierarchy, &userID, true)
+}
+
+// RedoUserCommand applies the earliest change the user undid again
+func (um *UserManager) RedoUserCommand(userID int) (*Command, error) {
+	return replayDatabaseCommand(um.repository, um.events, um.hierarchy, &userID, false)
+}
+
+// journal records a change made by op as a command of the user. op gets a
+// copy of the user manager bound to the transaction of the change.
+func (um *UserManager) journal(userID int, kind CommandKind, taskID int, op func(bound *UserManager) error) error {
+	return journalDatabaseCommand(um.repository, um.events, &userID, kind, taskID, func(repository database.Repository, events *EventBus) error {
+		return op(um.withRepository(repository, events))
+	})
+}
@@ -0,0 +1,407 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTaskManagerUndoRedo(t *testing.T) {
+	tm := NewTaskManager()
+
+	if _, err := tm.Undo(); !errors.Is(err, ErrNothingToUndo) {
+		t.Fatalf("Expected nothing to undo, got %v", err)
+	}
+
+	task := tm.AddTask("Write report", "", Medium, nil)
+	if err := tm.UpdateTaskStatus(task.ID, InProgress); err != nil {
+		t.Fatalf("Failed to update status: %v", err)
+	}
+	title := "Write the report"
+	if _, err := tm.UpdateTask(task.ID, TaskPatch{Title: &title}); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+	if err := tm.DeleteTask(task.ID); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+
+	command, err := tm.Undo()
+	if err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	if command.Kind != CommandDelete || command.TaskID != task.ID || !command.Undone {
+		t.Errorf("Expected the delete to be undone, got %+v", command)
+	}
+	restored, err := tm.GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("Expected the task to be back: %v", err)
+	}
+	if restored.Title != title || restored.Status != InProgress {
+		t.Errorf("Expected the task as it was before the delete, got %+v", restored)
+	}
+
+	if _, err := tm.Undo(); err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	if _, err := tm.Undo(); err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	if restored, _ := tm.GetTask(task.ID); restored.Title != "Write report" || restored.Status != Pending {
+		t.Errorf("Expected the original task, got %+v", restored)
+	}
+	if _, err := tm.Undo(); err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	if _, err := tm.GetTask(task.ID); err == nil {
+		t.Error("Expected undoing the create to remove the task")
+	}
+	if _, err := tm.Undo(); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("Expected nothing left to undo, got %v", err)
+	}
+
+	// Redo walks forward again, in order
+	command, err = tm.Redo()
+	if err != nil {
+		t.Fatalf("Failed to redo: %v", err)
+	}
+	if command.Kind != CommandCreate || command.Undone {
+		t.Errorf("Expected the create to be redone, got %+v", command)
+	}
+	if _, err := tm.Redo(); err != nil {
+		t.Fatalf("Failed to redo: %v", err)
+	}
+	if redone, err := tm.GetTask(task.ID); err != nil || redone.Status != InProgress {
+		t.Errorf("Expected the status change to be redone, got %+v (%v)", redone, err)
+	}
+
+	// A new change drops what was left to redo
+	if err := tm.UpdateTaskStatus(task.ID, Completed); err != nil {
+		t.Fatalf("Failed to update status: %v", err)
+	}
+	if _, err := tm.Redo(); !errors.Is(err, ErrNothingToRedo) {
+		t.Errorf("Expected nothing to redo, got %v", err)
+	}
+}
+
+func TestTaskManagerUndoRecurrence(t *testing.T) {
+	tm := NewTaskManager()
+	task := tm.AddTask("Water plants", "", Low, nil)
+	tm.tasks[0].Recurrence = &RecurrenceRule{Frequency: Daily, Interval: 1}
+
+	if err := tm.UpdateTaskStatus(task.ID, Completed); err != nil {
+		t.Fatalf("Failed to complete task: %v", err)
+	}
+	if len(tm.GetAllTasks()) != 2 {
+		t.Fatalf("Expected the next occurrence to be created, got %+v", tm.GetAllTasks())
+	}
+
+	if _, err := tm.Undo(); err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	tasks := tm.GetAllTasks()
+	if len(tasks) != 1 || tasks[0].ID != task.ID || tasks[0].Status != Pending {
+		t.Errorf("Expected only the pending task to be left, got %+v", tasks)
+	}
+}
+
+func TestTaskManagerUndoKeepsLaterChanges(t *testing.T) {
+	tm := NewTaskManager()
+	task := tm.AddTask("Draft", "", Medium, nil)
+	title := "Final"
+	if _, err := tm.UpdateTask(task.ID, TaskPatch{Title: &title}); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+	if err := tm.ArchiveTask(task.ID); err != nil {
+		t.Fatalf("Failed to archive task: %v", err)
+	}
+
+	if _, err := tm.Undo(); err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	undone, _ := tm.GetTask(task.ID)
+	if undone.Title != "Draft" || !undone.IsArchived {
+		t.Errorf("Expected the title to be undone and the task to stay archived, got %+v", undone)
+	}
+
+	// A field changed again since cannot be put back
+	if _, err := tm.Redo(); err != nil {
+		t.Fatalf("Failed to redo: %v", err)
+	}
+	tm.tasks[0].Title = "Edited elsewhere"
+	if _, err := tm.Undo(); !errors.Is(err, ErrReplayConflict) {
+		t.Errorf("Expected a conflict, got %v", err)
+	}
+	if current, _ := tm.GetTask(task.ID); current.Title != "Edited elsewhere" {
+		t.Errorf("Expected the conflicting undo to change nothing, got %+v", current)
+	}
+}
+
+func TestTaskManagerUndoFollowsWorkflow(t *testing.T) {
+	tm := NewTaskManager()
+	workflow := DefaultWorkflow()
+	workflow.RemoveTransition(Completed, Pending)
+	tm.SetWorkflow(workflow)
+
+	task := tm.AddTask("Ship", "", High, nil)
+	if err := tm.UpdateTaskStatus(task.ID, Completed); err != nil {
+		t.Fatalf("Failed to complete task: %v", err)
+	}
+
+	var transitionErr *TransitionError
+	if _, err := tm.Undo(); !errors.As(err, &transitionErr) {
+		t.Fatalf("Expected the workflow to reject the undo, got %v", err)
+	}
+	if current, _ := tm.GetTask(task.ID); current.Status != Completed {
+		t.Errorf("Expected the task to stay completed, got %s", current.Status)
+	}
+}
+
+func TestUserManagerUndoKeepsLaterChanges(t *testing.T) {
+	repository := setupTestRepository(t)
+	um := NewUserManager(repository)
+
+	user, err := um.RegisterUser("user", "user@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	created, err := um.CreateUserTask(user.ID, "A", "", Medium, nil)
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	title := "B"
+	if _, err := um.PatchUserTask(user.ID, created.ID, TaskPatch{Title: &title}); err != nil {
+		t.Fatalf("Failed to patch task: %v", err)
+	}
+	if _, err := um.SnoozeUserTask(user.ID, created.ID, time.Now().Add(24*time.Hour)); err != nil {
+		t.Fatalf("Failed to snooze task: %v", err)
+	}
+	if _, err := um.ArchiveUserTask(user.ID, created.ID); err != nil {
+		t.Fatalf("Failed to archive task: %v", err)
+	}
+
+	if _, err := um.UndoUserCommand(user.ID); err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	undone, err := repository.GetTask(created.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if undone.Title != "A" || !undone.IsArchived || undone.HiddenUntil == nil {
+		t.Errorf("Expected only the title to be undone, got %+v", undone)
+	}
+
+	// A title changed since the command cannot be put back
+	if _, err := um.RedoUserCommand(user.ID); err != nil {
+		t.Fatalf("Failed to redo: %v", err)
+	}
+	redone, _ := repository.GetTask(created.ID)
+	redone.Title = "C"
+	if err := repository.UpdateTask(redone); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+	if _, err := um.UndoUserCommand(user.ID); !errors.Is(err, ErrReplayConflict) {
+		t.Errorf("Expected a conflict, got %v", err)
+	}
+	if current, _ := repository.GetTask(created.ID); current.Title != "C" {
+		t.Errorf("Expected the conflicting undo to change nothing, got %q", current.Title)
+	}
+}
+
+func TestUserManagerUndoDeleteRestoresLinks(t *testing.T) {
+	repository := setupTestRepository(t)
+	um := NewUserManager(repository)
+	cm := NewCategoryManager(repository)
+	dm := NewDependencyManager(repository)
+
+	user, err := um.RegisterUser("user", "user@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	other, err := um.RegisterUser("other", "other@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+
+	var ids []int
+	for _, title := range []string{"Design", "Build", "Ship"} {
+		created, err := um.CreateUserTask(user.ID, title, "", Medium, nil)
+		if err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		ids = append(ids, created.ID)
+	}
+	category, err := cm.CreateCategory("Work", "", "")
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	tag, err := cm.CreateTag("release", "")
+	if err != nil {
+		t.Fatalf("Failed to create tag: %v", err)
+	}
+	if _, err := um.SetUserTaskCategory(user.ID, ids[1], &category.ID); err != nil {
+		t.Fatalf("Failed to set category: %v", err)
+	}
+	if err := cm.AddTagToTask(ids[1], tag.ID); err != nil {
+		t.Fatalf("Failed to add tag: %v", err)
+	}
+	if err := dm.AddDependency(ids[1], ids[0]); err != nil {
+		t.Fatalf("Failed to add dependency: %v", err)
+	}
+	if err := dm.AddDependency(ids[2], ids[1]); err != nil {
+		t.Fatalf("Failed to add dependency: %v", err)
+	}
+
+	if err := um.DeleteUserTask(user.ID, ids[1]); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+	// Links lost while the task is in the trash come back as well
+	if err := repository.RemoveTagFromTask(ids[1], tag.ID); err != nil {
+		t.Fatalf("Failed to remove tag: %v", err)
+	}
+	if err := repository.RemoveTaskDependency(ids[2], ids[1]); err != nil {
+		t.Fatalf("Failed to remove dependency: %v", err)
+	}
+
+	if _, err := um.UndoUserCommand(other.ID); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("Expected another user to have nothing to undo, got %v", err)
+	}
+	command, err := um.UndoUserCommand(user.ID)
+	if err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	if command.Kind != CommandDelete || command.TaskID != ids[1] {
+		t.Errorf("Expected the delete to be undone, got %+v", command)
+	}
+
+	restored, err := repository.GetTask(ids[1])
+	if err != nil {
+		t.Fatalf("Expected the task to be restored: %v", err)
+	}
+	if restored.CategoryID == nil || *restored.CategoryID != category.ID {
+		t.Errorf("Expected the category to be kept, got %+v", restored.CategoryID)
+	}
+	if tags, _ := repository.GetTaskTags(ids[1]); len(tags) != 1 || tags[0].ID != tag.ID {
+		t.Errorf("Expected the tag to be restored, got %+v", tags)
+	}
+	if dependencies, _ := repository.GetTaskDependencies(ids[1]); len(dependencies) != 1 || dependencies[0].DependsOnTaskID != ids[0] {
+		t.Errorf("Expected the dependency to be restored, got %+v", dependencies)
+	}
+	if dependents, _ := repository.GetTasksThatDependOn(ids[1]); len(dependents) != 1 || dependents[0].ID != ids[2] {
+		t.Errorf("Expected the dependent to be restored, got %+v", dependents)
+	}
+
+	if _, err := um.RedoUserCommand(user.ID); err != nil {
+		t.Fatalf("Failed to redo: %v", err)
+	}
+	if _, err := repository.GetTask(ids[1]); err == nil {
+		t.Error("Expected redo to delete the task again")
+	}
+	if _, err := um.RedoUserCommand(user.ID); !errors.Is(err, ErrNothingToRedo) {
+		t.Errorf("Expected nothing left to redo, got %v", err)
+	}
+}
+
+func TestHybridTaskManagerUndo(t *testing.T) {
+	repository := setupTestRepository(t)
+	htm := NewHybridTaskManager(repository, DatabaseStorage)
+	ctx := context.Background()
+
+	parent, err := htm.AddTaskContext(ctx, "Release", "", High, nil)
+	if err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+	child, err := htm.AddTaskContext(ctx, "Changelog", "", Medium, nil)
+	if err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+	if err := NewHierarchyManager(repository, DefaultCascadeRules()).SetParent(child.ID, &parent.ID); err != nil {
+		t.Fatalf("Failed to set parent: %v", err)
+	}
+	if err := htm.DeleteTask(parent.ID); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+	if _, err := repository.GetTask(child.ID); err == nil {
+		t.Fatal("Expected the subtask to be deleted with its parent")
+	}
+
+	// The journal lives in the database, so a new manager, as in the next
+	// command line run, can undo the delete together with its cascade
+	command, err := NewHybridTaskManager(repository, DatabaseStorage).Undo()
+	if err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	if command.Kind != CommandDelete || len(command.Changes) != 2 {
+		t.Errorf("Expected the delete and its cascade to be undone, got %+v", command)
+	}
+	for _, id := range []int{parent.ID, child.ID} {
+		if _, err := repository.GetTask(id); err != nil {
+			t.Errorf("Expected task %d to be restored: %v", id, err)
+		}
+	}
+
+	if _, err := htm.Undo(); err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	if _, err := repository.GetTask(child.ID); err == nil {
+		t.Error("Expected undoing the create to trash the task")
+	}
+	if _, err := htm.Redo(); err != nil {
+		t.Fatalf("Failed to redo: %v", err)
+	}
+	if _, err := repository.GetTask(child.ID); err != nil {
+		t.Errorf("Expected redoing the create to bring the task back: %v", err)
+	}
+
+	// Memory storage keeps its own journal
+	htm.SetStorageType(MemoryStorage)
+	if _, err := htm.Undo(); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("Expected memory storage to have nothing to undo, got %v", err)
+	}
+}
+
+func TestHybridTaskManagerUndoKeepsStoredIDs(t *testing.T) {
+	repository := setupTestRepository(t)
+	htm := NewHybridTaskManager(repository, HybridStorage)
+	ctx := context.Background()
+
+	first, err := htm.AddTaskContext(ctx, "Old", "", Low, nil)
+	if err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+	second, err := htm.AddTaskContext(ctx, "Report", "", Medium, nil)
+	if err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+	third, err := htm.AddTaskContext(ctx, "Notes", "", Medium, nil)
+	if err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+	if err := htm.DeleteTask(first.ID); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+
+	// The trashed task is not loaded, so memory IDs handed out afresh would
+	// put the notes where the report is stored
+	if err := htm.LoadFromDatabase(); err != nil {
+		t.Fatalf("Failed to load tasks: %v", err)
+	}
+	title := "Quarterly report"
+	if _, err := htm.UpdateTask(second.ID, TaskPatch{Title: &title}); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+	if _, err := htm.Undo(); err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+
+	task, err := htm.GetTask(second.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if task.Title != "Report" {
+		t.Errorf("Expected the memory copy to be undone too, got %q", task.Title)
+	}
+	if notes, err := htm.memoryManager.GetTask(third.ID); err != nil || notes.Title != "Notes" {
+		t.Errorf("Expected the memory copy of the notes to be left alone, got %+v (%v)", notes, err)
+	}
+}
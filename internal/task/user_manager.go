@@ -1,7 +1,9 @@
 package task
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"learn-go-capstone/internal/auth"
@@ -12,6 +14,9 @@ import (
 type UserManager struct {
 	repository   database.Repository
 	authService  *auth.AuthService
+	hierarchy    *HierarchyManager
+	events       *EventBus
+	scorer       *Scorer
 }
 
 // NewUserManager creates a new user manager
@@ -19,9 +24,49 @@ func NewUserManager(repository database.Repository) *UserManager {
 	return &UserManager{
 		repository:  repository,
 		authService: auth.NewAuthService(repository),
+		hierarchy:   NewHierarchyManager(repository, DefaultCascadeRules()),
 	}
 }
 
+// SetHierarchyManager replaces the hierarchy manager used to apply cascade rules
+func (um *UserManager) SetHierarchyManager(hierarchy *HierarchyManager) {
+	um.hierarchy = hierarchy
+}
+
+// SetEventBus sets the bus that task mutations are published to.
+// Call it after SetHierarchyManager, whose status changes and deletions it also covers.
+func (um *UserManager) SetEventBus(bus *EventBus) {
+	um.events = bus
+	um.hierarchy.SetEventBus(bus)
+}
+
+// GetWorkflow returns the workflow that status changes must follow
+func (um *UserManager) GetWorkflow() *Workflow {
+	return um.hierarchy.GetWorkflow()
+}
+
+// WithContext returns a copy of the user manager whose queries run with ctx
+func (um *UserManager) WithContext(ctx context.Context) *UserManager {
+	if um.repository == nil {
+		return um
+	}
+
+	bound := *um
+	bound.repository = um.repository.WithContext(ctx)
+	bound.hierarchy = um.hierarchy.WithContext(ctx)
+	return &bound
+}
+
+// withRepository returns a copy of the user manager that makes its changes
+// with repository and publishes them to events
+func (um *UserManager) withRepository(repository database.Repository, events *EventBus) *UserManager {
+	bound := *um
+	bound.repository = repository
+	bound.hierarchy = um.hierarchy.withRepository(repository, events)
+	bound.events = events
+	return &bound
+}
+
 // RegisterUser registers a new user
 func (um *UserManager) RegisterUser(username, email, password string) (*database.User, error) {
 	return um.authService.RegisterUser(username, email, password)
@@ -99,11 +144,38 @@ func (um *UserManager) GetUserTasks(userID int) ([]Task, error) {
 		return nil, err
 	}
 	
-	return convertFromDatabaseTasks(dbTasks), nil
+	// Snoozed tasks are listed by GetUserSnoozedTasks until they wake up
+	return convertFromDatabaseTasks(awakeDatabaseTasks(dbTasks, time.Now())), nil
 }
 
 // CreateUserTask creates a task for a specific user
 func (um *UserManager) CreateUserTask(userID int, title, description string, priority Priority, dueDate *time.Time) (*Task, error) {
+	return um.CreateUserTaskWithPatch(userID, title, description, priority, dueDate, TaskPatch{})
+}
+
+// CreateUserTaskWithPatch creates a task for a specific user with a patch
+// applied before it is stored, so fields such as the recurrence rule and the
+// estimate are written by the same insert
+func (um *UserManager) CreateUserTaskWithPatch(userID int, title, description string, priority Priority, dueDate *time.Time, patch TaskPatch) (*Task, error) {
+	return um.CreateUserTaskWithRelations(userID, title, description, priority, dueDate, patch, TaskRelations{})
+}
+
+// TaskRelations are the project, parent task and assignees a new task is
+// created with. Nil fields leave the task without them.
+type TaskRelations struct {
+	ProjectID *int
+	ParentID  *int
+	Assignees []Assignee
+}
+
+// CreateUserTaskWithRelations creates a task for a specific user like
+// CreateUserTaskWithPatch and links it to its relations in the same
+// transaction, so a relation that cannot be set leaves no task behind
+func (um *UserManager) CreateUserTaskWithRelations(userID int, title, description string, priority Priority, dueDate *time.Time, patch TaskPatch, relations TaskRelations) (*Task, error) {
+	if err := patch.Validate(); err != nil {
+		return nil, err
+	}
+	
 	now := time.Now()
 	task := &database.DatabaseTask{
 		Title:       title,
@@ -116,15 +188,42 @@ func (um *UserManager) CreateUserTask(userID int, title, description string, pri
 		UserID:      &userID,
 		IsArchived:  false,
 	}
+	patch.applyToDatabaseTask(task)
 	
-	err := um.repository.CreateTask(task)
+	var created *Task
+	err := um.journal(userID, CommandCreate, 0, func(bound *UserManager) error {
+		if err := bound.repository.CreateTask(task); err != nil {
+			return err
+		}
+		bound.events.Publish(databaseTaskEvent(EventTaskCreated, task))
+		
+		// Convert to task.Task
+		convertedTask := convertFromDatabaseTask(task)
+		created = &convertedTask
+		
+		var err error
+		if relations.ProjectID != nil {
+			if created, err = bound.SetUserTaskProject(userID, task.ID, relations.ProjectID); err != nil {
+				return err
+			}
+		}
+		if relations.ParentID != nil {
+			if created, err = bound.SetUserTaskParent(userID, task.ID, relations.ParentID); err != nil {
+				return fmt.Errorf("invalid parent task: %w", err)
+			}
+		}
+		if relations.Assignees != nil {
+			if created, err = bound.SetUserTaskAssignees(userID, task.ID, relations.Assignees); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 	
-	// Convert to task.Task
-	convertedTask := convertFromDatabaseTask(task)
-	return &convertedTask, nil
+	return created, nil
 }
 
 // UpdateUserTask updates a task for a specific user
@@ -140,15 +239,62 @@ func (um *UserManager) UpdateUserTask(userID, taskID int, title, description str
 		return errors.New("task not found or access denied")
 	}
 	
+	// Status changes follow the workflow like any other status update
+	if err := um.GetWorkflow().CheckTransition(convertFromDatabaseTask(task), status); err != nil {
+		return err
+	}
+	
 	// Update the task
 	task.Title = title
 	task.Description = description
 	task.Priority = int(priority)
-	task.Status = int(status)
 	task.DueDate = dueDate
 	task.UpdatedAt = time.Now()
 	
-	return um.repository.UpdateTask(task)
+	return um.journal(userID, CommandUpdate, taskID, func(bound *UserManager) error {
+		if err := bound.hierarchy.setStatus(task, status); err != nil {
+			return err
+		}
+		
+		bound.events.Publish(databaseTaskEvent(EventTaskUpdated, task))
+		return nil
+	})
+}
+
+// PatchUserTask applies a partial update to a task for a specific user
+func (um *UserManager) PatchUserTask(userID, taskID int, patch TaskPatch) (*Task, error) {
+	if err := patch.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Get the task first to verify ownership
+	dbTask, err := um.repository.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if dbTask.UserID == nil || *dbTask.UserID != userID {
+		return nil, errors.New("access denied: task does not belong to user or user ID is missing")
+	}
+
+	// An empty patch changes nothing, so it is neither saved nor recorded
+	if patch.IsEmpty() {
+		return um.withAssignees(dbTask)
+	}
+
+	patch.applyToDatabaseTask(dbTask)
+	err = um.journal(userID, CommandUpdate, taskID, func(bound *UserManager) error {
+		if err := bound.repository.UpdateTask(dbTask); err != nil {
+			return err
+		}
+		bound.events.Publish(databaseTaskEvent(EventTaskUpdated, dbTask))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return um.withAssignees(dbTask)
 }
 
 // DeleteUserTask deletes a task for a specific user
@@ -164,7 +310,181 @@ func (um *UserManager) DeleteUserTask(userID, taskID int) error {
 		return errors.New("task not found or access denied")
 	}
 	
-	return um.repository.DeleteTask(taskID)
+	return um.journal(userID, CommandDelete, taskID, func(bound *UserManager) error {
+		return bound.hierarchy.DeleteTask(taskID)
+	})
+}
+
+// ArchiveUserTask archives a task for a specific user
+func (um *UserManager) ArchiveUserTask(userID, taskID int) (*Task, error) {
+	return um.setUserTaskArchived(userID, taskID, true)
+}
+
+// UnarchiveUserTask unarchives a task for a specific user
+func (um *UserManager) UnarchiveUserTask(userID, taskID int) (*Task, error) {
+	return um.setUserTaskArchived(userID, taskID, false)
+}
+
+func (um *UserManager) setUserTaskArchived(userID, taskID int, archived bool) (*Task, error) {
+	dbTask, err := um.repository.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if dbTask.UserID == nil || *dbTask.UserID != userID {
+		return nil, errors.New("access denied: task does not belong to user or user ID is missing")
+	}
+
+	if err := setDatabaseTaskArchived(um.repository, um.events, dbTask, archived); err != nil {
+		return nil, err
+	}
+
+	task := convertFromDatabaseTask(dbTask)
+	return &task, nil
+}
+
+// GetUserArchivedTasks gets the archived tasks of a user
+func (um *UserManager) GetUserArchivedTasks(userID int) ([]Task, error) {
+	dbTasks, err := um.repository.GetArchivedTasksByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertFromDatabaseTasks(dbTasks), nil
+}
+
+// RestoreUserTask takes a task of a specific user out of the trash
+func (um *UserManager) RestoreUserTask(userID, taskID int) (*Task, error) {
+	dbTask, err := um.repository.GetDeletedTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if dbTask.UserID == nil || *dbTask.UserID != userID {
+		return nil, errors.New("access denied: task does not belong to user or user ID is missing")
+	}
+
+	dbTask, err = restoreDatabaseTask(um.repository, um.events, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	task := convertFromDatabaseTask(dbTask)
+	return &task, nil
+}
+
+// GetUserDeletedTasks gets the trashed tasks of a user
+func (um *UserManager) GetUserDeletedTasks(userID int) ([]Task, error) {
+	dbTasks, err := um.repository.GetDeletedTasksByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertFromDatabaseTasks(dbTasks), nil
+}
+
+// GetUserTaskHistory gets the recorded changes of a task of a specific user
+func (um *UserManager) GetUserTaskHistory(userID, taskID int) ([]HistoryEntry, error) {
+	if _, err := um.GetUserTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	entries, err := um.repository.GetTaskHistory(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertFromHistoryEntries(entries), nil
+}
+
+// StartUserTimer starts a timer for a user on one of their tasks. A user can
+// only run one timer at a time.
+func (um *UserManager) StartUserTimer(userID, taskID int, note string) (*TimeEntry, error) {
+	if _, err := um.GetUserTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	entry := &database.TimeEntry{TaskID: taskID, UserID: &userID, Note: note}
+	if err := um.repository.StartTimer(entry); err != nil {
+		return nil, err
+	}
+
+	timeEntry := convertFromTimeEntry(entry)
+	return &timeEntry, nil
+}
+
+// StopUserTimer stops the running timer of a user on a task
+func (um *UserManager) StopUserTimer(userID, taskID int) (*TimeEntry, error) {
+	if _, err := um.GetUserTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	running, err := um.repository.GetRunningTimer(&userID)
+	if err != nil {
+		return nil, err
+	}
+	if running.TaskID != taskID {
+		return nil, fmt.Errorf("the running timer is on task %d, not on task %d", running.TaskID, taskID)
+	}
+
+	entry, err := um.repository.StopTimer(&userID)
+	if err != nil {
+		return nil, err
+	}
+
+	timeEntry := convertFromTimeEntry(entry)
+	return &timeEntry, nil
+}
+
+// LogUserTime records time a user spent on one of their tasks without a timer
+func (um *UserManager) LogUserTime(userID, taskID int, startedAt time.Time, duration time.Duration, note string) (*TimeEntry, error) {
+	if err := validateTimeLog(startedAt, duration); err != nil {
+		return nil, err
+	}
+
+	if _, err := um.GetUserTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	entry := &database.TimeEntry{
+		TaskID:          taskID,
+		UserID:          &userID,
+		StartedAt:       startedAt,
+		DurationSeconds: int(duration / time.Second),
+		Note:            note,
+	}
+	if err := um.repository.CreateTimeEntry(entry); err != nil {
+		return nil, err
+	}
+
+	timeEntry := convertFromTimeEntry(entry)
+	return &timeEntry, nil
+}
+
+// GetUserTaskTime returns the time entries of a user's task together with its estimate
+func (um *UserManager) GetUserTaskTime(userID, taskID int) (*TaskTime, error) {
+	if _, err := um.GetUserTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	dbTask, err := um.repository.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := um.repository.GetTimeEntries(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTaskTime(dbTask, entries), nil
+}
+
+// GetUserTimeReport aggregates the time tracked on a user's tasks between from and to
+func (um *UserManager) GetUserTimeReport(userID int, grouping ReportGrouping, from, to time.Time) ([]TimeReportRow, error) {
+	return buildTimeReport(um.repository, grouping, from, to, func(dbTask *database.DatabaseTask) bool {
+		return dbTask.UserID != nil && *dbTask.UserID == userID
+	})
 }
 
 // GetUserTasksByStatus gets tasks for a user by status
@@ -174,9 +494,9 @@ func (um *UserManager) GetUserTasksByStatus(userID int, status Status) ([]Task,
 		return nil, err
 	}
 	
-	// Filter by user ID
+	// Filter by user ID, leaving out snoozed tasks
 	var userTasks []Task
-	for _, dbTask := range dbTasks {
+	for _, dbTask := range awakeDatabaseTasks(dbTasks, time.Now()) {
 		if dbTask.UserID != nil && *dbTask.UserID == userID {
 			userTasks = append(userTasks, convertFromDatabaseTask(&dbTask))
 		}
@@ -192,9 +512,9 @@ func (um *UserManager) GetUserTasksByPriority(userID int, priority Priority) ([]
 		return nil, err
 	}
 	
-	// Filter by user ID
+	// Filter by user ID, leaving out snoozed tasks
 	var userTasks []Task
-	for _, dbTask := range dbTasks {
+	for _, dbTask := range awakeDatabaseTasks(dbTasks, time.Now()) {
 		if dbTask.UserID != nil && *dbTask.UserID == userID {
 			userTasks = append(userTasks, convertFromDatabaseTask(&dbTask))
 		}
@@ -236,7 +556,8 @@ func (um *UserManager) GetUserTask(userID, taskID int) (*Task, error) {
 	return &task, nil
 }
 
-// UpdateUserTaskStatus updates the status of a user's task, ensuring ownership
+// UpdateUserTaskStatus updates the status of a task owned by the user, or of
+// a task the user is an assignee or reviewer of
 func (um *UserManager) UpdateUserTaskStatus(userID, taskID int, status Status) error {
 	dbTask, err := um.repository.GetTask(taskID)
 	if err != nil {
@@ -244,10 +565,352 @@ func (um *UserManager) UpdateUserTaskStatus(userID, taskID int, status Status) e
 	}
 
 	if dbTask.UserID == nil || *dbTask.UserID != userID {
-		return errors.New("access denied: task does not belong to user or user ID is missing")
+		member, err := um.isProjectMember(userID, dbTask)
+		if err != nil {
+			return err
+		}
+
+		if !member {
+			role, err := um.assigneeRole(userID, taskID)
+			if err != nil {
+				return err
+			}
+			if role == "" {
+				return errors.New("access denied: task does not belong to user or user ID is missing")
+			}
+			if !role.CanUpdateStatus() {
+				return fmt.Errorf("access denied: a %s cannot change the status of a task", role)
+			}
+		}
 	}
 
-	dbTask.Status = int(status)
+	return um.journal(userID, CommandStatus, taskID, func(bound *UserManager) error {
+		return bound.hierarchy.setStatus(dbTask, status)
+	})
+}
+
+// GetVisibleTask retrieves a task that the user owns or takes part in, with
+// its assignees
+func (um *UserManager) GetVisibleTask(userID, taskID int) (*Task, error) {
+	dbTask, err := um.repository.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if visible, err := canSeeTask(um.repository, userID, dbTask); err != nil {
+		return nil, err
+	} else if !visible {
+		return nil, errors.New("access denied: task does not belong to user or user ID is missing")
+	}
+
+	return um.withAssignees(dbTask)
+}
+
+// canSeeTask reports whether the user owns the task, is a member of its
+// project or takes part in it as an assignee, reviewer or watcher
+func canSeeTask(repository database.Repository, userID int, dbTask *database.DatabaseTask) (bool, error) {
+	if dbTask.UserID != nil && *dbTask.UserID == userID {
+		return true, nil
+	}
+
+	if dbTask.ProjectID != nil {
+		role, err := projectRole(repository, userID, *dbTask.ProjectID)
+		if err != nil || role != "" {
+			return role != "", err
+		}
+	}
+
+	assignees, err := repository.GetTaskAssignees(dbTask.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, assignee := range assignees {
+		if assignee.UserID == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isProjectMember reports whether the task belongs to a project the user is a member of
+func (um *UserManager) isProjectMember(userID int, dbTask *database.DatabaseTask) (bool, error) {
+	if dbTask.ProjectID == nil {
+		return false, nil
+	}
+
+	role, err := projectRole(um.repository, userID, *dbTask.ProjectID)
+	if err != nil {
+		return false, err
+	}
+	return role != "", nil
+}
+
+// SetUserTaskProject moves a user's task into a project the user is a member
+// of, or out of its project when projectID is nil. The category of the task is
+// cleared when it belongs to another project, as are its tags, and the task
+// goes back to the backlog.
+func (um *UserManager) SetUserTaskProject(userID, taskID int, projectID *int) (*Task, error) {
+	dbTask, err := um.repository.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if dbTask.UserID == nil || *dbTask.UserID != userID {
+		return nil, errors.New("access denied: task does not belong to user or user ID is missing")
+	}
+
+	if projectID != nil {
+		if _, err := um.repository.GetProject(*projectID); err != nil {
+			return nil, err
+		}
+		role, err := projectRole(um.repository, userID, *projectID)
+		if err != nil {
+			return nil, err
+		}
+		if role == "" {
+			return nil, fmt.Errorf("project with ID %d not found", *projectID)
+		}
+	}
+
+	if sameProject(dbTask.ProjectID, projectID) {
+		return um.withAssignees(dbTask)
+	}
+
+	if dbTask.CategoryID != nil {
+		category, err := um.repository.GetCategory(*dbTask.CategoryID)
+		if err != nil || !sameProject(category.ProjectID, projectID) {
+			dbTask.CategoryID = nil
+		}
+	}
+
+	dbTask.ProjectID = projectID
+	dbTask.SprintID = nil
 	dbTask.UpdatedAt = time.Now()
-	return um.repository.UpdateTask(dbTask)
+	if err := um.repository.UpdateTask(dbTask); err != nil {
+		return nil, err
+	}
+
+	tags, err := um.repository.GetTaskTags(taskID)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		if !sameProject(tag.ProjectID, projectID) {
+			if err := um.repository.RemoveTagFromTask(taskID, tag.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return um.GetVisibleTask(userID, taskID)
+}
+
+// SetUserTaskCategory files a user's task under a category of the task's
+// project, or a global category for personal tasks, or clears its category
+// when categoryID is nil
+func (um *UserManager) SetUserTaskCategory(userID, taskID int, categoryID *int) (*Task, error) {
+	dbTask, err := um.repository.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if dbTask.UserID == nil || *dbTask.UserID != userID {
+		return nil, errors.New("access denied: task does not belong to user or user ID is missing")
+	}
+
+	if err := setDatabaseTaskCategory(um.repository, um.events, dbTask, categoryID); err != nil {
+		return nil, err
+	}
+
+	return um.withAssignees(dbTask)
+}
+
+// SetUserTaskTags adds tags to and removes tags from a user's task. Added tags
+// must belong to the task's project, or be global for personal tasks.
+func (um *UserManager) SetUserTaskTags(userID, taskID int, add, remove []int) (*Task, error) {
+	dbTask, err := um.repository.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if dbTask.UserID == nil || *dbTask.UserID != userID {
+		return nil, errors.New("access denied: task does not belong to user or user ID is missing")
+	}
+
+	if err := setDatabaseTaskTags(um.repository, um.events, dbTask, add, remove); err != nil {
+		return nil, err
+	}
+
+	return um.withAssignees(dbTask)
+}
+
+// sameProject reports whether two optional project IDs are equal
+func sameProject(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// sameID reports whether two optional IDs, such as category IDs, are equal
+func sameID(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// GetAssignedTasks gets the tasks the user takes part in with the given role,
+// or with any role when role is empty
+func (um *UserManager) GetAssignedTasks(userID int, role AssigneeRole) ([]Task, error) {
+	if role != "" {
+		if _, err := ParseAssigneeRole(string(role)); err != nil {
+			return nil, err
+		}
+	}
+
+	dbTasks, err := um.repository.GetTasksByAssignee(userID, string(role))
+	if err != nil {
+		return nil, err
+	}
+
+	return convertFromDatabaseTasks(dbTasks), nil
+}
+
+// SetUserTaskAssignees replaces the assignees of a user's task. Assignees are
+// identified by UserID or, when it is zero, by Username, and must be active
+// users listed once each.
+func (um *UserManager) SetUserTaskAssignees(userID, taskID int, assignees []Assignee) (*Task, error) {
+	dbTask, err := um.repository.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if dbTask.UserID == nil || *dbTask.UserID != userID {
+		return nil, errors.New("access denied: task does not belong to user or user ID is missing")
+	}
+
+	dbAssignees := make([]database.TaskAssignee, 0, len(assignees))
+	listed := make(map[int]bool)
+	for _, assignee := range assignees {
+		role, err := ParseAssigneeRole(string(assignee.Role))
+		if err != nil {
+			return nil, err
+		}
+
+		var user *database.User
+		if assignee.UserID != 0 {
+			user, err = um.repository.GetUser(assignee.UserID)
+		} else {
+			user, err = um.repository.GetUserByUsername(assignee.Username)
+		}
+		name := assignee.Username
+		if name == "" {
+			name = fmt.Sprintf("#%d", assignee.UserID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid assignee %s: no such user", name)
+		}
+		if !user.IsActive {
+			return nil, fmt.Errorf("invalid assignee %s: user is inactive", name)
+		}
+		if listed[user.ID] {
+			return nil, fmt.Errorf("invalid assignee %s: listed more than once", name)
+		}
+		listed[user.ID] = true
+
+		dbAssignees = append(dbAssignees, database.TaskAssignee{TaskID: taskID, UserID: user.ID, Role: string(role)})
+	}
+
+	if err := um.repository.SetTaskAssignees(taskID, dbAssignees); err != nil {
+		return nil, err
+	}
+
+	return um.withAssignees(dbTask)
+}
+
+// assigneeRole returns the role of the user on a task, or an empty role when
+// the user does not take part in it
+func (um *UserManager) assigneeRole(userID, taskID int) (AssigneeRole, error) {
+	assignees, err := um.repository.GetTaskAssignees(taskID)
+	if err != nil {
+		return "", err
+	}
+
+	for _, assignee := range assignees {
+		if assignee.UserID == userID {
+			return AssigneeRole(assignee.Role), nil
+		}
+	}
+
+	return "", nil
+}
+
+// withAssignees converts a database task and loads its assignees, custom
+// field values and checklist
+func (um *UserManager) withAssignees(dbTask *database.DatabaseTask) (*Task, error) {
+	dbAssignees, err := um.repository.GetTaskAssignees(dbTask.ID)
+	if err != nil {
+		return nil, err
+	}
+	dbValues, err := um.repository.GetTaskCustomValues(dbTask.ID)
+	if err != nil {
+		return nil, err
+	}
+	dbItems, err := um.repository.GetChecklistItems(dbTask.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	task := convertFromDatabaseTask(dbTask)
+	task.Assignees = convertFromDatabaseAssignees(dbAssignees)
+	task.CustomFields = convertFromDatabaseCustomValues(dbValues)
+	task.Checklist = convertFromDatabaseChecklist(dbItems)
+	return &task, nil
+}
+
+// SetUserTaskParent moves a user's task under another of their tasks, or to
+// the top level when parentID is nil
+func (um *UserManager) SetUserTaskParent(userID, taskID int, parentID *int) (*Task, error) {
+	if _, err := um.GetUserTask(userID, taskID); err != nil {
+		return nil, err
+	}
+	if parentID != nil {
+		if _, err := um.GetUserTask(userID, *parentID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := um.hierarchy.SetParent(taskID, parentID); err != nil {
+		return nil, err
+	}
+
+	return um.GetUserTask(userID, taskID)
+}
+
+// GetUserTaskSubtree returns a user's task with its subtasks loaded recursively
+func (um *UserManager) GetUserTaskSubtree(userID, taskID int) (*Task, error) {
+	if _, err := um.GetUserTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	return um.hierarchy.GetSubtree(taskID)
+}
+
+// GetUserSubtasks returns the direct subtasks of a user's task
+func (um *UserManager) GetUserSubtasks(userID, taskID int) ([]Task, error) {
+	if _, err := um.GetUserTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	return um.hierarchy.GetChildren(taskID)
+}
+
+// GetUserTaskAncestors returns the ancestors of a user's task, nearest parent first
+func (um *UserManager) GetUserTaskAncestors(userID, taskID int) ([]Task, error) {
+	if _, err := um.GetUserTask(userID, taskID); err != nil {
+		return nil, err
+	}
+
+	return um.hierarchy.GetAncestors(taskID)
 }
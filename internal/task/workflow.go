@@ -0,0 +1,295 @@
+package task
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"learn-go-capstone/internal/database"
+)
+
+// Guard is a condition that must hold before a task may enter a status.
+// It returns a non-nil error describing why the transition is blocked.
+type Guard struct {
+	Name  string
+	Check func(t Task) error
+	// bind recreates a guard that reads stored tasks for another repository
+	bind func(repository database.Repository) Guard
+}
+
+// TransitionError is returned when a workflow rejects a status change
+type TransitionError struct {
+	TaskID int
+	From   Status
+	To     Status
+	// FromName and ToName are the names the workflow gives the statuses;
+	// when empty, the built-in names are used
+	FromName string
+	ToName   string
+	// Guard is the name of the failed guard, empty if the transition itself is not allowed
+	Guard  string
+	Reason string
+}
+
+func (e *TransitionError) Error() string {
+	from, to := e.FromName, e.ToName
+	if from == "" {
+		from = e.From.String()
+	}
+	if to == "" {
+		to = e.To.String()
+	}
+	if e.Guard != "" {
+		return fmt.Sprintf("cannot move task %d from %s to %s: %s: %s", e.TaskID, from, to, e.Guard, e.Reason)
+	}
+	return fmt.Sprintf("cannot move task %d from %s to %s: %s", e.TaskID, from, to, e.Reason)
+}
+
+// Workflow defines the statuses a task can be in, the allowed transitions
+// between them and the guards that must pass before entering a status
+type Workflow struct {
+	mu          sync.RWMutex
+	statuses    map[Status]string
+	transitions map[Status]map[Status]bool
+	guards      map[Status][]Guard
+}
+
+// NewWorkflow creates an empty workflow
+func NewWorkflow() *Workflow {
+	return &Workflow{
+		statuses:    make(map[Status]string),
+		transitions: make(map[Status]map[Status]bool),
+		guards:      make(map[Status][]Guard),
+	}
+}
+
+// DefaultWorkflow returns the built-in workflow: tasks move freely between
+// Pending and In Progress, can be completed or cancelled from either, and
+// finished tasks can be reopened. A cancelled task cannot be completed directly.
+func DefaultWorkflow() *Workflow {
+	w := NewWorkflow()
+	for _, status := range []Status{Pending, InProgress, Completed, Cancelled} {
+		w.statuses[status] = status.String()
+	}
+
+	w.AddTransition(Pending, InProgress)
+	w.AddTransition(Pending, Completed)
+	w.AddTransition(Pending, Cancelled)
+	w.AddTransition(InProgress, Pending)
+	w.AddTransition(InProgress, Completed)
+	w.AddTransition(InProgress, Cancelled)
+	w.AddTransition(Completed, Pending)
+	w.AddTransition(Completed, InProgress)
+	w.AddTransition(Cancelled, Pending)
+
+	return w
+}
+
+// AddStatus adds a custom status such as "In Review" or "Blocked".
+// Custom statuses must use values above the built-in ones.
+func (w *Workflow) AddStatus(status Status, name string) error {
+	name = strings.TrimSpace(name)
+	if status <= Cancelled {
+		return fmt.Errorf("custom status %d must be greater than %d", status, Cancelled)
+	}
+	if name == "" {
+		return fmt.Errorf("custom status %d needs a name", status)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for existing, existingName := range w.statuses {
+		if existing != status && strings.EqualFold(existingName, name) {
+			return fmt.Errorf("status name %q is already used by status %d", name, existing)
+		}
+	}
+
+	w.statuses[status] = name
+	return nil
+}
+
+// AddTransition allows tasks to move from one status to another
+func (w *Workflow) AddTransition(from, to Status) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.statuses[from]; !ok {
+		return fmt.Errorf("unknown status %d", from)
+	}
+	if _, ok := w.statuses[to]; !ok {
+		return fmt.Errorf("unknown status %d", to)
+	}
+
+	if w.transitions[from] == nil {
+		w.transitions[from] = make(map[Status]bool)
+	}
+	w.transitions[from][to] = true
+	return nil
+}
+
+// RemoveTransition forbids a previously allowed transition
+func (w *Workflow) RemoveTransition(from, to Status) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.transitions[from], to)
+}
+
+// AddGuard adds a guard that is checked whenever a task enters the given status
+func (w *Workflow) AddGuard(to Status, guard Guard) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.guards[to] = append(w.guards[to], guard)
+}
+
+// withRepository returns a copy of the workflow whose guards read stored
+// tasks from repository, so that checks see the changes of a transaction
+func (w *Workflow) withRepository(repository database.Repository) *Workflow {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	bound := NewWorkflow()
+	for status, name := range w.statuses {
+		bound.statuses[status] = name
+	}
+	for from, targets := range w.transitions {
+		bound.transitions[from] = make(map[Status]bool, len(targets))
+		for to := range targets {
+			bound.transitions[from][to] = true
+		}
+	}
+	for to, guards := range w.guards {
+		for _, guard := range guards {
+			if guard.bind != nil {
+				guard = guard.bind(repository)
+			}
+			bound.guards[to] = append(bound.guards[to], guard)
+		}
+	}
+	return bound
+}
+
+// IsKnownStatus reports whether the workflow defines the status
+func (w *Workflow) IsKnownStatus(status Status) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	_, ok := w.statuses[status]
+	return ok
+}
+
+// StatusName returns the name the workflow gives a status, such as the name
+// of a custom status, or the built-in name of a status it does not define
+func (w *Workflow) StatusName(status Status) string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if name, ok := w.statuses[status]; ok {
+		return name
+	}
+	return status.String()
+}
+
+// Statuses returns all statuses of the workflow in ascending order
+func (w *Workflow) Statuses() []Status {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	statuses := make([]Status, 0, len(w.statuses))
+	for status := range w.statuses {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i] < statuses[j] })
+	return statuses
+}
+
+// AllowedTransitions returns the statuses a task in the given status may move to
+func (w *Workflow) AllowedTransitions(from Status) []Status {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	var allowed []Status
+	for to := range w.transitions[from] {
+		allowed = append(allowed, to)
+	}
+	sort.Slice(allowed, func(i, j int) bool { return allowed[i] < allowed[j] })
+	return allowed
+}
+
+// ParseStatus looks up a status by name, ignoring case, spaces, hyphens and underscores
+func (w *Workflow) ParseStatus(name string) (Status, error) {
+	normalize := func(s string) string {
+		return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(s))
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	for status, statusName := range w.statuses {
+		if normalize(statusName) == normalize(name) {
+			return status, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown status %q", name)
+}
+
+// CheckTransition returns a *TransitionError if the task may not move to the
+// given status. Setting a task to the status it already has is always allowed.
+func (w *Workflow) CheckTransition(t Task, to Status) error {
+	if t.Status == to {
+		return nil
+	}
+
+	w.mu.RLock()
+	fromName := w.statuses[t.Status]
+	toName, known := w.statuses[to]
+	allowed := w.transitions[t.Status][to]
+	guards := append([]Guard(nil), w.guards[to]...)
+	w.mu.RUnlock()
+
+	if !known {
+		return &TransitionError{TaskID: t.ID, From: t.Status, To: to, FromName: fromName, Reason: "unknown status"}
+	}
+	if !allowed {
+		return &TransitionError{TaskID: t.ID, From: t.Status, To: to, FromName: fromName, ToName: toName, Reason: "transition not allowed"}
+	}
+
+	for _, guard := range guards {
+		if err := guard.Check(t); err != nil {
+			return &TransitionError{TaskID: t.ID, From: t.Status, To: to, FromName: fromName, ToName: toName, Guard: guard.Name, Reason: err.Error()}
+		}
+	}
+
+	return nil
+}
+
+// DependenciesCompletedGuard blocks a transition while any task the task depends on is unfinished
+func DependenciesCompletedGuard(dm *DependencyManager) Guard {
+	return Guard{
+		Name: "dependencies completed",
+		Check: func(t Task) error {
+			canComplete, err := dm.CanCompleteTask(t.ID)
+			if err != nil {
+				return err
+			}
+			if !canComplete {
+				return fmt.Errorf("task %d depends on unfinished tasks", t.ID)
+			}
+			return nil
+		},
+		bind: func(repository database.Repository) Guard {
+			return DependenciesCompletedGuard(NewDependencyManager(repository))
+		},
+	}
+}
+
+// defaultDatabaseWorkflow returns the default workflow with the guards that
+// need a repository, so dependencies are enforced for stored tasks
+func defaultDatabaseWorkflow(dm *DependencyManager) *Workflow {
+	w := DefaultWorkflow()
+	w.AddGuard(Completed, DependenciesCompletedGuard(dm))
+	return w
+}
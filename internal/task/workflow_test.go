@@ -0,0 +1,176 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const (
+	testInReview Status = 10
+	testBlocked  Status = 11
+)
+
+func TestDefaultWorkflowTransitions(t *testing.T) {
+	w := DefaultWorkflow()
+
+	tests := []struct {
+		from    Status
+		to      Status
+		allowed bool
+	}{
+		{Pending, InProgress, true},
+		{InProgress, Completed, true},
+		{Completed, InProgress, true},
+		{Cancelled, Pending, true},
+		{Cancelled, Completed, false},
+		{Cancelled, InProgress, false},
+		{Completed, Completed, true},
+	}
+
+	for _, tt := range tests {
+		err := w.CheckTransition(Task{ID: 1, Status: tt.from}, tt.to)
+		if (err == nil) != tt.allowed {
+			t.Errorf("%s -> %s: expected allowed %v, got %v", tt.from, tt.to, tt.allowed, err)
+		}
+
+		var transitionErr *TransitionError
+		if err != nil && !errors.As(err, &transitionErr) {
+			t.Errorf("%s -> %s: expected a *TransitionError, got %T", tt.from, tt.to, err)
+		}
+	}
+
+	if err := w.CheckTransition(Task{ID: 1, Status: Pending}, Status(42)); err == nil {
+		t.Error("Expected error for an unknown status")
+	}
+}
+
+func TestWorkflowCustomStatusesAndGuards(t *testing.T) {
+	w := DefaultWorkflow()
+
+	if err := w.AddStatus(InProgress, "Doing"); err == nil {
+		t.Error("Expected error when redefining a built-in status")
+	}
+	if err := w.AddStatus(testInReview, "In Review"); err != nil {
+		t.Fatalf("Failed to add status: %v", err)
+	}
+	if err := w.AddStatus(testBlocked, "Blocked"); err != nil {
+		t.Fatalf("Failed to add status: %v", err)
+	}
+	if err := w.AddStatus(Status(12), "blocked"); err == nil {
+		t.Error("Expected error for a duplicate status name")
+	}
+
+	// Reviews sit between In Progress and Completed
+	w.RemoveTransition(InProgress, Completed)
+	w.AddTransition(InProgress, testInReview)
+	w.AddTransition(testInReview, Completed)
+	w.AddTransition(testInReview, InProgress)
+
+	w.AddGuard(testInReview, Guard{
+		Name: "has description",
+		Check: func(t Task) error {
+			if t.Description == "" {
+				return fmt.Errorf("task needs a description before review")
+			}
+			return nil
+		},
+	})
+
+	if name := w.StatusName(testInReview); name != "In Review" {
+		t.Errorf("Expected custom status name, got %q", name)
+	}
+	// Custom names belong to the workflow that defines them
+	if name := DefaultWorkflow().StatusName(testInReview); name != "Unknown" {
+		t.Errorf("Expected another workflow not to know the custom status, got %q", name)
+	}
+	status, err := w.ParseStatus("in-review")
+	if err != nil || status != testInReview {
+		t.Errorf("Expected to parse In Review, got %v (%v)", status, err)
+	}
+
+	inProgress := Task{ID: 7, Status: InProgress}
+	if err := w.CheckTransition(inProgress, Completed); err == nil {
+		t.Error("Expected In Progress -> Completed to be rejected")
+	}
+
+	err = w.CheckTransition(inProgress, testInReview)
+	var transitionErr *TransitionError
+	if !errors.As(err, &transitionErr) || transitionErr.Guard != "has description" {
+		t.Fatalf("Expected the guard to reject the transition, got %v", err)
+	}
+	if transitionErr.TaskID != 7 || transitionErr.From != InProgress || transitionErr.To != testInReview {
+		t.Errorf("Unexpected error details: %+v", transitionErr)
+	}
+	if !strings.Contains(err.Error(), "from In Progress to In Review") {
+		t.Errorf("Expected the error to name the custom status, got %q", err.Error())
+	}
+
+	inProgress.Description = "Ready"
+	if err := w.CheckTransition(inProgress, testInReview); err != nil {
+		t.Errorf("Expected the transition to pass, got %v", err)
+	}
+
+	allowed := w.AllowedTransitions(testInReview)
+	if len(allowed) != 2 || allowed[0] != InProgress || allowed[1] != Completed {
+		t.Errorf("Expected [In Progress Completed], got %v", allowed)
+	}
+}
+
+func TestTaskManagerEnforcesWorkflow(t *testing.T) {
+	tm := NewTaskManager()
+	created := tm.AddTask("Write docs", "", Medium, nil)
+
+	if err := tm.UpdateTaskStatus(created.ID, Cancelled); err != nil {
+		t.Fatalf("Failed to cancel task: %v", err)
+	}
+
+	err := tm.UpdateTaskStatus(created.ID, Completed)
+	var transitionErr *TransitionError
+	if !errors.As(err, &transitionErr) {
+		t.Fatalf("Expected a *TransitionError, got %v", err)
+	}
+
+	stored, _ := tm.GetTask(created.ID)
+	if stored.Status != Cancelled {
+		t.Errorf("Expected a rejected transition to keep the status, got %s", stored.Status)
+	}
+}
+
+func TestDatabaseWorkflowRequiresDependencies(t *testing.T) {
+	storageTypes := map[string]StorageType{"database": DatabaseStorage, "hybrid": HybridStorage}
+	for name, storageType := range storageTypes {
+		t.Run(name, func(t *testing.T) {
+			repository := setupTestRepository(t)
+			htm := NewHybridTaskManager(repository, storageType)
+			dm := NewDependencyManager(repository)
+
+			blocker := htm.AddTask("Design schema", "", High, nil)
+			blocked := htm.AddTask("Write migrations", "", High, nil)
+			if err := dm.AddDependency(blocked.ID, blocker.ID); err != nil {
+				t.Fatalf("Failed to add dependency: %v", err)
+			}
+
+			err := htm.UpdateTaskStatus(blocked.ID, Completed)
+			var transitionErr *TransitionError
+			if !errors.As(err, &transitionErr) || transitionErr.Guard != "dependencies completed" {
+				t.Fatalf("Expected the dependency guard to reject completion, got %v", err)
+			}
+			// Hybrid storage reads memory first, which must not have changed
+			if loaded, err := htm.GetTask(blocked.ID); err != nil || loaded.Status != Pending {
+				t.Errorf("Expected the rejected task to stay pending, got %+v (%v)", loaded, err)
+			}
+
+			if err := htm.UpdateTaskStatus(blocker.ID, Completed); err != nil {
+				t.Fatalf("Failed to complete blocker: %v", err)
+			}
+			if err := htm.UpdateTaskStatus(blocked.ID, Completed); err != nil {
+				t.Errorf("Expected completion once dependencies are done, got %v", err)
+			}
+			if loaded, err := htm.GetTask(blocked.ID); err != nil || loaded.Status != Completed {
+				t.Errorf("Expected the task to be completed, got %+v (%v)", loaded, err)
+			}
+		})
+	}
+}
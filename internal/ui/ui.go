@@ -2,12 +2,15 @@ package ui
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
+	"learn-go-capstone/internal/quickadd"
 	"learn-go-capstone/internal/task"
 	"github.com/fatih/color"
 	"github.com/olekukonko/tablewriter"
@@ -25,8 +28,8 @@ func DisplayWelcome() {
 	fmt.Println()
 }
 
-// RunInteractiveMode starts the interactive CLI
-func RunInteractiveMode(tm task.TaskManagerInterface) error {
+// RunInteractiveMode starts the interactive CLI. Storage calls are bound to ctx.
+func RunInteractiveMode(ctx context.Context, tm task.TaskManagerV2) error {
 	scanner := bufio.NewScanner(os.Stdin)
 	
 	for {
@@ -41,23 +44,29 @@ func RunInteractiveMode(tm task.TaskManagerInterface) error {
 		
 		switch choice {
 		case "1":
-			addTaskInteractive(tm, scanner)
+			addTaskInteractive(ctx, tm, scanner)
 		case "2":
-			listTasks(tm)
+			listTasks(ctx, tm)
 		case "3":
-			updateTaskStatus(tm, scanner)
+			updateTaskStatus(ctx, tm, scanner)
 		case "4":
-			deleteTask(tm, scanner)
+			deleteTask(ctx, tm, scanner)
 		case "5":
-			listTasksByStatus(tm, scanner)
+			listTasksByStatus(ctx, tm, scanner)
 		case "6":
-			listTasksByPriority(tm, scanner)
+			listTasksByPriority(ctx, tm, scanner)
 		case "7":
-			listOverdueTasks(tm)
+			listOverdueTasks(ctx, tm)
 		case "8":
-			showStatistics(tm)
+			showStatistics(ctx, tm)
 		case "9":
-			runConcurrencyDemo(tm)
+			runConcurrencyDemo(ctx, tm)
+		case "10":
+			showNextTasks(ctx, tm)
+		case "11":
+			replayChange(ctx, tm, true)
+		case "12":
+			replayChange(ctx, tm, false)
 		case "0":
 			color.Green("👋 Thanks for using Go Task Manager!")
 			return nil
@@ -82,39 +91,57 @@ func displayMenu() {
 	fmt.Println("7. Show Overdue Tasks")
 	fmt.Println("8. Show Statistics")
 	fmt.Println("9. Concurrency Demo")
+	fmt.Println("10. What Should I Do Next")
+	fmt.Println("11. Undo Last Change")
+	fmt.Println("12. Redo Undone Change")
 	fmt.Println("0. Exit")
 }
 
-func addTaskInteractive(tm task.TaskManagerInterface, scanner *bufio.Scanner) {
-	fmt.Print("Enter task title: ")
+func addTaskInteractive(ctx context.Context, tm task.TaskManagerV2, scanner *bufio.Scanner) {
+	color.White("Quick-add: !low..!urgent priority, #tag, @category, dates like tomorrow 5pm or every month")
+	fmt.Print("Enter task: ")
 	scanner.Scan()
-	title := strings.TrimSpace(scanner.Text())
+	text := strings.TrimSpace(scanner.Text())
 	
-	if title == "" {
+	if text == "" {
 		color.Red("❌ Title cannot be empty")
 		return
 	}
 	
+	entry, err := quickadd.Parse(text, time.Now())
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	
 	fmt.Print("Enter task description: ")
 	scanner.Scan()
 	description := strings.TrimSpace(scanner.Text())
 	
-	priority := getPriorityFromUser(scanner)
-	
-	var dueDate *time.Time
-	fmt.Print("Enter due date (YYYY-MM-DD) or press Enter to skip: ")
-	scanner.Scan()
-	dueDateStr := strings.TrimSpace(scanner.Text())
+	// Only ask for what the task line left out
+	if entry.Priority == 0 {
+		entry.Priority = int(getPriorityFromUser(scanner))
+	}
 	
-	if dueDateStr != "" {
-		if parsed, err := time.Parse("2006-01-02", dueDateStr); err == nil {
-			dueDate = &parsed
-		} else {
-			color.Red("❌ Invalid date format. Skipping due date.")
+	if entry.DueDate == nil {
+		fmt.Print("Enter due date (e.g. tomorrow, fri 5pm, YYYY-MM-DD) or press Enter to skip: ")
+		scanner.Scan()
+		dueDateStr := strings.TrimSpace(scanner.Text())
+		
+		if dueDateStr != "" {
+			if parsed, err := quickadd.ParseDate(dueDateStr, time.Now()); err == nil {
+				entry.DueDate = &parsed
+			} else {
+				color.Red("❌ Invalid date format. Skipping due date.")
+			}
 		}
 	}
 	
-	newTask := tm.AddTask(title, description, priority, dueDate)
+	newTask, err := tm.QuickAddContext(ctx, entry, description)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
 	color.Green("✅ Task added successfully!")
 	color.White("Task ID: %d", newTask.ID)
 }
@@ -140,8 +167,12 @@ func getPriorityFromUser(scanner *bufio.Scanner) task.Priority {
 	}
 }
 
-func listTasks(tm task.TaskManagerInterface) {
-	tasks := tm.GetAllTasks()
+func listTasks(ctx context.Context, tm task.TaskManagerV2) {
+	tasks, err := tm.GetAllTasksContext(ctx)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
 	if len(tasks) == 0 {
 		color.Yellow("📝 No tasks found.")
 		return
@@ -212,7 +243,7 @@ func getStatusColor(s task.Status) func(string) string {
 	}
 }
 
-func updateTaskStatus(tm task.TaskManagerInterface, scanner *bufio.Scanner) {
+func updateTaskStatus(ctx context.Context, tm task.TaskManagerV2, scanner *bufio.Scanner) {
 	fmt.Print("Enter task ID: ")
 	scanner.Scan()
 	idStr := strings.TrimSpace(scanner.Text())
@@ -223,7 +254,7 @@ func updateTaskStatus(tm task.TaskManagerInterface, scanner *bufio.Scanner) {
 		return
 	}
 	
-	_, err = tm.GetTask(id)
+	_, err = tm.GetTaskContext(ctx, id)
 	if err != nil {
 		color.Red("❌ %v", err)
 		return
@@ -248,7 +279,7 @@ func updateTaskStatus(tm task.TaskManagerInterface, scanner *bufio.Scanner) {
 		return
 	}
 	
-	err = tm.UpdateTaskStatus(id, status)
+	err = tm.UpdateTaskStatusContext(ctx, id, status)
 	if err != nil {
 		color.Red("❌ %v", err)
 		return
@@ -257,7 +288,7 @@ func updateTaskStatus(tm task.TaskManagerInterface, scanner *bufio.Scanner) {
 	color.Green("✅ Task status updated successfully!")
 }
 
-func deleteTask(tm task.TaskManagerInterface, scanner *bufio.Scanner) {
+func deleteTask(ctx context.Context, tm task.TaskManagerV2, scanner *bufio.Scanner) {
 	fmt.Print("Enter task ID to delete: ")
 	scanner.Scan()
 	idStr := strings.TrimSpace(scanner.Text())
@@ -268,7 +299,7 @@ func deleteTask(tm task.TaskManagerInterface, scanner *bufio.Scanner) {
 		return
 	}
 	
-	err = tm.DeleteTask(id)
+	err = tm.DeleteTaskContext(ctx, id)
 	if err != nil {
 		color.Red("❌ %v", err)
 		return
@@ -277,7 +308,32 @@ func deleteTask(tm task.TaskManagerInterface, scanner *bufio.Scanner) {
 	color.Green("✅ Task deleted successfully!")
 }
 
-func listTasksByStatus(tm task.TaskManagerInterface, scanner *bufio.Scanner) {
+// replayChange undoes the latest change to the tasks, or redoes the last one undone
+func replayChange(ctx context.Context, tm task.TaskManagerV2, undo bool) {
+	var command *task.Command
+	var err error
+	if undo {
+		command, err = tm.UndoContext(ctx)
+	} else {
+		command, err = tm.RedoContext(ctx)
+	}
+	switch {
+	case errors.Is(err, task.ErrNothingToUndo), errors.Is(err, task.ErrNothingToRedo):
+		color.Yellow("ℹ️  %v", err)
+		return
+	case err != nil:
+		color.Red("❌ %v", err)
+		return
+	}
+	
+	if undo {
+		color.Green("✅ Undone: %s", command)
+	} else {
+		color.Green("✅ Redone: %s", command)
+	}
+}
+
+func listTasksByStatus(ctx context.Context, tm task.TaskManagerV2, scanner *bufio.Scanner) {
 	fmt.Print("Enter status (1=Pending, 2=In Progress, 3=Completed, 4=Cancelled): ")
 	scanner.Scan()
 	statusStr := strings.TrimSpace(scanner.Text())
@@ -297,11 +353,15 @@ func listTasksByStatus(tm task.TaskManagerInterface, scanner *bufio.Scanner) {
 		return
 	}
 	
-	tasks := tm.GetTasksByStatus(status)
+	tasks, err := tm.GetTasksByStatusContext(ctx, status)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
 	displayTasksTable(tasks, fmt.Sprintf("Tasks with Status: %s", status.String()))
 }
 
-func listTasksByPriority(tm task.TaskManagerInterface, scanner *bufio.Scanner) {
+func listTasksByPriority(ctx context.Context, tm task.TaskManagerV2, scanner *bufio.Scanner) {
 	fmt.Print("Enter priority (1=Low, 2=Medium, 3=High, 4=Urgent): ")
 	scanner.Scan()
 	priorityStr := strings.TrimSpace(scanner.Text())
@@ -321,17 +381,73 @@ func listTasksByPriority(tm task.TaskManagerInterface, scanner *bufio.Scanner) {
 		return
 	}
 	
-	tasks := tm.GetTasksByPriority(priority)
+	tasks, err := tm.GetTasksByPriorityContext(ctx, priority)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
 	displayTasksTable(tasks, fmt.Sprintf("Tasks with Priority: %s", priority.String()))
 }
 
-func listOverdueTasks(tm task.TaskManagerInterface) {
-	tasks := tm.GetOverdueTasks()
+func listOverdueTasks(ctx context.Context, tm task.TaskManagerV2) {
+	tasks, err := tm.GetOverdueTasksContext(ctx)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
 	displayTasksTable(tasks, "Overdue Tasks")
 }
 
-func showStatistics(tm task.TaskManagerInterface) {
-	allTasks := tm.GetAllTasks()
+// nextTasksShown is how many tasks the next-up ranking shows
+const nextTasksShown = 10
+
+func showNextTasks(ctx context.Context, tm task.TaskManagerV2) {
+	ranked, err := tm.NextTasksContext(ctx, nextTasksShown)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	if len(ranked) == 0 {
+		color.Yellow("🎉 Nothing left to do.")
+		return
+	}
+	
+	color.Cyan("\n🎯 What Should I Do Next")
+	
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"#", "ID", "Title", "Priority", "Status", "Due Date", "Score"})
+	table.SetBorder(true)
+	table.SetCenterSeparator("|")
+	table.SetColumnSeparator("|")
+	table.SetRowSeparator("-")
+	
+	for i, scored := range ranked {
+		t := scored.Task
+		dueDate := "N/A"
+		if t.DueDate != nil {
+			dueDate = t.DueDate.Format("2006-01-02")
+		}
+		
+		table.Append([]string{
+			fmt.Sprintf("%d", i+1),
+			fmt.Sprintf("%d", t.ID),
+			t.Title,
+			getPriorityColor(t.Priority)(t.Priority.String()),
+			getStatusColor(t.Status)(t.Status.String()),
+			dueDate,
+			fmt.Sprintf("%.0f", scored.Score*100),
+		})
+	}
+	
+	table.Render()
+}
+
+func showStatistics(ctx context.Context, tm task.TaskManagerV2) {
+	allTasks, err := tm.GetAllTasksContext(ctx)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
 	
 	if len(allTasks) == 0 {
 		color.Yellow("📊 No tasks to show statistics for.")
@@ -345,10 +461,16 @@ func showStatistics(tm task.TaskManagerInterface) {
 	color.White("Total Tasks: %d", len(allTasks))
 	
 	// Status breakdown
-	pending := len(tm.GetTasksByStatus(task.Pending))
-	inProgress := len(tm.GetTasksByStatus(task.InProgress))
-	completed := len(tm.GetTasksByStatus(task.Completed))
-	cancelled := len(tm.GetTasksByStatus(task.Cancelled))
+	statusCounts := make(map[task.Status]int)
+	priorityCounts := make(map[task.Priority]int)
+	for _, t := range allTasks {
+		statusCounts[t.Status]++
+		priorityCounts[t.Priority]++
+	}
+	pending := statusCounts[task.Pending]
+	inProgress := statusCounts[task.InProgress]
+	completed := statusCounts[task.Completed]
+	cancelled := statusCounts[task.Cancelled]
 	
 	fmt.Printf("Pending: %d\n", pending)
 	fmt.Printf("In Progress: %d\n", inProgress)
@@ -356,10 +478,10 @@ func showStatistics(tm task.TaskManagerInterface) {
 	fmt.Printf("Cancelled: %d\n", cancelled)
 	
 	// Priority breakdown
-	low := len(tm.GetTasksByPriority(task.Low))
-	medium := len(tm.GetTasksByPriority(task.Medium))
-	high := len(tm.GetTasksByPriority(task.High))
-	urgent := len(tm.GetTasksByPriority(task.Urgent))
+	low := priorityCounts[task.Low]
+	medium := priorityCounts[task.Medium]
+	high := priorityCounts[task.High]
+	urgent := priorityCounts[task.Urgent]
 	
 	fmt.Printf("\nPriority Breakdown:\n")
 	fmt.Printf("Low: %d\n", low)
@@ -368,12 +490,17 @@ func showStatistics(tm task.TaskManagerInterface) {
 	fmt.Printf("Urgent: %d\n", urgent)
 	
 	// Overdue tasks
-	overdue := len(tm.GetOverdueTasks())
+	overdueTasks, err := tm.GetOverdueTasksContext(ctx)
+	if err != nil {
+		color.Red("❌ %v", err)
+		return
+	}
+	overdue := len(overdueTasks)
 	fmt.Printf("\nOverdue Tasks: %d\n", overdue)
 }
 
 // runConcurrencyDemo demonstrates Go's concurrency features
-func runConcurrencyDemo(tm task.TaskManagerInterface) {
+func runConcurrencyDemo(ctx context.Context, tm task.TaskManagerV2) {
 	color.Cyan("\n🔄 Go Concurrency Demo")
 	fmt.Println("This demo shows goroutines and channels in action...")
 	
@@ -390,22 +517,30 @@ func runConcurrencyDemo(tm task.TaskManagerInterface) {
 	}
 	
 	// Use channels to add tasks concurrently
-	taskChan := make(chan *task.Task, len(demoTasks))
+	type result struct {
+		task *task.Task
+		err  error
+	}
+	taskChan := make(chan result, len(demoTasks))
 	
 	// Start goroutines to add tasks
 	for _, demoTask := range demoTasks {
 		go func(title, desc string, priority task.Priority) {
-			newTask := tm.AddTask(title, desc, priority, nil)
-			taskChan <- newTask
+			newTask, err := tm.AddTaskContext(ctx, title, desc, priority, nil)
+			taskChan <- result{task: newTask, err: err}
 		}(demoTask.title, demoTask.description, demoTask.priority)
 	}
 	
 	// Collect results
 	var addedTasks []*task.Task
 	for i := 0; i < len(demoTasks); i++ {
-		addedTask := <-taskChan
-		addedTasks = append(addedTasks, addedTask)
-		color.Green("✅ Added task: %s (ID: %d)", addedTask.Title, addedTask.ID)
+		added := <-taskChan
+		if added.err != nil {
+			color.Red("❌ %v", added.err)
+			continue
+		}
+		addedTasks = append(addedTasks, added.task)
+		color.Green("✅ Added task: %s (ID: %d)", added.task.Title, added.task.ID)
 		time.Sleep(200 * time.Millisecond) // Simulate processing time
 	}
 	
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"os/signal"
 
 	"learn-go-capstone/cmd"
 	"learn-go-capstone/internal/config"
@@ -16,7 +18,7 @@ func main() {
 	cfg := config.LoadConfig()
 	
 	// Initialize task manager based on configuration
-	var taskManager task.TaskManagerInterface
+	var taskManager task.TaskManagerV2
 	
 	if cfg.IsDatabaseEnabled() {
 		// Connect to database
@@ -67,17 +69,21 @@ func main() {
 		log.Println("Using memory storage")
 	}
 	
+	// Cancel in-flight storage calls on Ctrl+C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	
 	// Display welcome message
 	ui.DisplayWelcome()
 	
 	// Check if we should run in interactive mode or show help
 	if len(os.Args) > 1 {
-		cmd.HandleCommand(os.Args[1:], taskManager)
+		cmd.HandleCommand(ctx, os.Args[1:], taskManager)
 		return
 	}
 	
 	// Run interactive mode
-	if err := ui.RunInteractiveMode(taskManager); err != nil {
+	if err := ui.RunInteractiveMode(ctx, taskManager); err != nil {
 		log.Fatalf("Error running interactive mode: %v", err)
 	}
 }
\ No newline at end of file